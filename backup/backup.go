@@ -0,0 +1,288 @@
+package backup
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/config"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/multierr"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// configName is the archive entry name of the configuration file.
+	configName = "config.toml"
+	// manifestName is the archive entry name of the backup manifest, always the first entry.
+	manifestName = "manifest.json"
+	// manifestVersion is the current backup manifest version.
+	manifestVersion = 1
+)
+
+// Manifest is a description of a backup archive's contents.
+type Manifest struct {
+	// Version is the manifest format version.
+	Version int `json:"version"`
+	// Created is the time of creating the backup.
+	Created time.Time `json:"created"`
+	// Files are the backed up files.
+	Files []File `json:"files"`
+}
+
+// File is a backed up state file.
+type File struct {
+	// Name is the archive entry name of the file.
+	Name string `json:"name"`
+	// Path is the absolute path of the file at the time of backing up, it's restored there.
+	Path string `json:"path"`
+}
+
+// Source returns the path of a state file in a server configuration, an empty string if it's not configured.
+type Source func(cfg *config.Config) string
+
+// RepoSource returns the path of a repository's state file in its configuration, an empty string if it's not configured.
+type RepoSource func(repoConfig *config.Repo) string
+
+var (
+	sources     = make(map[string]Source)     // archive entry name -> source
+	repoSources = make(map[string]RepoSource) // archive entry name in a repository's directory -> source
+)
+
+// Register registers a state file persisted by a feature, to be backed up under an archive entry name.
+// It's meant to be called from the init function of the package owning the file, it panics if the name is taken.
+func Register(name string, src Source) {
+	if _, ok := sources[name]; ok || name == configName {
+		panic("backup: duplicate state file " + name)
+	}
+
+	sources[name] = src
+}
+
+// RegisterRepo registers a state file persisted by a feature per repository, to be backed up under an archive entry name
+// in the directory of the repository. It's meant to be called from the init function of the package owning the file,
+// it panics if the name is taken.
+func RegisterRepo(name string, src RepoSource) {
+	if _, ok := repoSources[name]; ok {
+		panic("backup: duplicate repository state file " + name)
+	}
+
+	repoSources[name] = src
+}
+
+// Files collects the state files of a server configuration, the configuration file included.
+// Media and the remux/transcode cache are not included, they're either not state or can be made again.
+// Only files registered by their owners (Register, RegisterRepo) are collected, files that don't exist (yet) are skipped.
+func Files(configPath string, cfg *config.Config) ([]File, error) {
+	var files []File
+	add := func(name, p string) error {
+		if p == "" { // not configured
+			return nil
+		}
+
+		absPath, err := filepath.Abs(p)
+		if err != nil {
+			return errors.Wrap(err, "failed to make path absolute")
+		}
+		if _, err := os.Stat(absPath); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return errors.Wrap(err, "failed to stat file")
+		}
+
+		files = append(files, File{Name: name, Path: absPath})
+		return nil
+	}
+
+	if err := add(configName, configPath); err != nil {
+		return nil, err
+	}
+	for _, name := range sortedKeys(sources) {
+		if err := add(name, sources[name](cfg)); err != nil {
+			return nil, errors.Wrapf(err, "failed to add %s", name)
+		}
+	}
+	for _, repoId := range sortedKeys(cfg.Repos) {
+		for _, name := range sortedKeys(repoSources) {
+			if err := add(path.Join("repos", repoId, name), repoSources[name](cfg.Repos[repoId])); err != nil {
+				return nil, errors.Wrapf(err, "failed to add repository %s %s", repoId, name)
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// sortedKeys returns the keys of a map in ascending order, for a deterministic archive layout.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+
+	return keys
+}
+
+// Create writes a zstd-compressed tar archive of the files to w.
+func Create(w io.Writer, files []File) (err error) {
+	zw, err := zstd.NewWriter(w)
+	if err != nil {
+		return errors.Wrap(err, "failed to open compressor")
+	}
+
+	tw := tar.NewWriter(zw)
+	defer func() {
+		if err0 := tw.Close(); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to close archive"))
+		}
+		if err0 := zw.Close(); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to close compressor"))
+		}
+	}()
+
+	manifest, err := json.Marshal(&Manifest{
+		Version: manifestVersion,
+		Created: time.Now(),
+		Files:   files,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal manifest")
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    manifestName,
+		Mode:    0644,
+		Size:    int64(len(manifest)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return errors.Wrap(err, "failed to write manifest header")
+	}
+	if _, err := tw.Write(manifest); err != nil {
+		return errors.Wrap(err, "failed to write manifest")
+	}
+
+	for _, file := range files {
+		if err := writeFile(tw, file); err != nil {
+			return errors.Wrapf(err, "failed to write file %s", file.Path)
+		}
+	}
+
+	return nil
+}
+
+func writeFile(tw *tar.Writer, file File) (err error) {
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open file")
+	}
+	defer func() {
+		if err0 := f.Close(); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to close file"))
+		}
+	}()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return errors.Wrap(err, "failed to stat file")
+	}
+
+	hdr, err := tar.FileInfoHeader(fi, "")
+	if err != nil {
+		return errors.Wrap(err, "failed to make header")
+	}
+
+	hdr.Name = file.Name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return errors.Wrap(err, "failed to write header")
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return errors.Wrap(err, "failed to copy file")
+	}
+
+	return nil
+}
+
+// Restore extracts a backup archive made by Create, the files are restored to their original paths.
+// Existing files are only replaced if overwrite is true, ErrFileExists is returned otherwise.
+func Restore(r io.Reader, overwrite bool) (_ *Manifest, err error) {
+	zr, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open decompressor")
+	}
+	defer zr.Close()
+
+	tr := tar.NewReader(zr)
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read manifest header")
+	}
+	if hdr.Name != manifestName {
+		return nil, fmt.Errorf("expected manifest, got %s", hdr.Name)
+	}
+
+	var manifest Manifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal manifest")
+	}
+	if manifest.Version > manifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d", manifest.Version)
+	}
+
+	paths := make(map[string]string, len(manifest.Files))
+	for _, file := range manifest.Files {
+		paths[file.Name] = file.Path
+		if _, err := os.Stat(file.Path); err == nil && !overwrite {
+			return nil, &ErrFileExists{Path: file.Path}
+		}
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			return nil, errors.Wrap(err, "failed to read header")
+		}
+
+		p, ok := paths[hdr.Name]
+		if !ok {
+			return nil, fmt.Errorf("unexpected archive entry %s", hdr.Name)
+		}
+		if err := restoreFile(tr, p, hdr.FileInfo().Mode().Perm()); err != nil {
+			return nil, errors.Wrapf(err, "failed to restore file %s", p)
+		}
+	}
+
+	return &manifest, nil
+}
+
+func restoreFile(r io.Reader, p string, perm fs.FileMode) (err error) {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return errors.Wrap(err, "failed to open file")
+	}
+	defer func() {
+		if err0 := f.Close(); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to close file"))
+		}
+	}()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return errors.Wrap(err, "failed to copy file")
+	}
+
+	return nil
+}
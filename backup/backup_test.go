@@ -0,0 +1,48 @@
+package backup
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var (
+		dir   = t.TempDir()
+		path  = filepath.Join(dir, "state", "index.json")
+		files = []File{{Name: "repos/test/index.json", Path: path}}
+	)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Create(&buf, files); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := buf.Bytes()
+	if _, err := Restore(bytes.NewReader(archive), false); err == nil {
+		t.Error("expected existing file error")
+	}
+
+	if err := os.RemoveAll(filepath.Dir(path)); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := Restore(bytes.NewReader(archive), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Files) != 1 || manifest.Files[0] != files[0] {
+		t.Errorf("unexpected manifest files %v", manifest.Files)
+	}
+
+	if b, err := os.ReadFile(path); err != nil || string(b) != "{}" {
+		t.Errorf("unexpected restored file contents %q (%v)", b, err)
+	}
+}
@@ -0,0 +1,14 @@
+package backup
+
+import "fmt"
+
+// ErrFileExists is an error about a restored file already existing.
+type ErrFileExists struct {
+	// Path is the path of the existing file.
+	Path string
+}
+
+// Error returns the string representation of the error.
+func (efe *ErrFileExists) Error() string {
+	return fmt.Sprintf("file %s already exists", efe.Path)
+}
@@ -0,0 +1,109 @@
+package backup_test
+
+import (
+	"github.com/katana-project/katana/backup"
+	"github.com/katana-project/katana/config"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	// owners of state files, registering them
+	_ "github.com/katana-project/katana/repo/archive"
+	_ "github.com/katana-project/katana/repo/checksum"
+	_ "github.com/katana-project/katana/repo/identify"
+	_ "github.com/katana-project/katana/repo/index"
+	_ "github.com/katana-project/katana/repo/refresh"
+	_ "github.com/katana-project/katana/repo/replica"
+	_ "github.com/katana-project/katana/server/collection"
+	_ "github.com/katana-project/katana/server/playback"
+	_ "github.com/katana-project/katana/server/stats"
+)
+
+// nonStatePaths are the path options of the configuration not pointing at state files, by their field path.
+var nonStatePaths = map[string]struct{}{
+	"Identify.FpcalcPath": {},
+	"Mux.FFmpegPath":      {},
+	"Mux.FFprobePath":     {},
+	"Repo.Archive.Path":   {}, // cold storage directory
+	"Repo.CachePath":      {}, // made again
+}
+
+func TestFiles(t *testing.T) {
+	var (
+		dir      = t.TempDir()
+		expected = make(map[string]struct{})
+	)
+	makeFile := func(name string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		expected[path] = struct{}{}
+		return path
+	}
+
+	// sets every path option of a section to a new file, so that a state file missing from the backup is caught
+	var setPaths func(prefix string, v reflect.Value)
+	setPaths = func(prefix string, v reflect.Value) {
+		for i := 0; i < v.NumField(); i++ {
+			var (
+				field = v.Type().Field(i)
+				name  = prefix + field.Name
+			)
+			switch {
+			case field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct:
+				if !v.Field(i).IsNil() {
+					setPaths(name+".", v.Field(i).Elem())
+				}
+			case field.Type.Kind() == reflect.String && strings.HasSuffix(field.Name, "Path"):
+				if _, ok := nonStatePaths[name]; !ok {
+					v.Field(i).SetString(makeFile(strings.ReplaceAll(name, ".", "-")))
+				}
+			}
+		}
+	}
+
+	cfg := &config.Config{HTTP: &config.HTTP{}, Repos: map[string]*config.Repo{"test": {Path: config.Paths{dir}}}}
+	cfg.Defaults()
+	setPaths("", reflect.ValueOf(cfg).Elem())
+
+	repoConfig := cfg.Repos["test"]
+	setPaths("Repo.", reflect.ValueOf(repoConfig).Elem())
+
+	// state files with paths derived from other options, such as from the repository's directory
+	rv := reflect.ValueOf(repoConfig)
+	for i := 0; i < rv.NumMethod(); i++ {
+		method := rv.Type().Method(i)
+		if !strings.HasSuffix(method.Name, "Path") || method.Type.NumIn() != 1 || method.Type.NumOut() != 1 {
+			continue
+		}
+
+		derived := rv.Method(i).Call(nil)[0].String()
+		if err := os.WriteFile(derived, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		expected[derived] = struct{}{}
+	}
+
+	files, err := backup.Files(makeFile("config.toml"), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make(map[string]struct{}, len(files))
+	for _, file := range files {
+		if _, ok := names[file.Name]; ok {
+			t.Errorf("duplicate archive entry name %s", file.Name)
+		}
+
+		names[file.Name] = struct{}{}
+		delete(expected, file.Path)
+	}
+	for path := range expected {
+		t.Errorf("state file %s not backed up", filepath.Base(path))
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"github.com/katana-project/katana/backup"
+	"github.com/katana-project/katana/config"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// handleBackup handles the backup sub-command.
+func (ac *appContext) handleBackup(cCtx *cli.Context) (err error) {
+	configPath := cCtx.String("config")
+	cfg, err := config.ParseWithDefaults(configPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to load config")
+	}
+
+	files, err := backup.Files(configPath, cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to collect files")
+	}
+
+	out := cCtx.String("out")
+	if out == "" {
+		out = "katana-backup-" + time.Now().Format("20060102-150405") + ".tar.zst"
+	}
+	out = filepath.Clean(out)
+
+	f, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create backup file")
+	}
+	defer func() {
+		if err0 := f.Close(); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to close backup file"))
+		}
+	}()
+
+	if err := backup.Create(f, files); err != nil {
+		return errors.Wrap(err, "failed to create backup")
+	}
+
+	ac.logger.Info("backup created successfully", zap.String("path", out), zap.Int("files", len(files)))
+	return nil
+}
+
+// handleRestore handles the restore sub-command.
+func (ac *appContext) handleRestore(cCtx *cli.Context) (err error) {
+	in := filepath.Clean(cCtx.String("in"))
+
+	f, err := os.Open(in)
+	if err != nil {
+		return errors.Wrap(err, "failed to open backup file")
+	}
+	defer func() {
+		if err0 := f.Close(); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to close backup file"))
+		}
+	}()
+
+	manifest, err := backup.Restore(f, cCtx.Bool("force"))
+	if err != nil {
+		return errors.Wrap(err, "failed to restore backup")
+	}
+
+	for _, file := range manifest.Files {
+		ac.logger.Info("restored file", zap.String("path", file.Path))
+	}
+	ac.logger.Info("backup restored successfully", zap.String("path", in), zap.Time("created", manifest.Created))
+	return nil
+}
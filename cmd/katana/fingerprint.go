@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/config"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/index"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// handleFingerprint handles the fingerprint sub-command.
+// The fingerprint is made from the repository's index, it's equal to the one of the running server if it's in sync with its files.
+func (ac *appContext) handleFingerprint(cCtx *cli.Context) error {
+	cfg, err := config.ParseWithDefaults(cCtx.String("config"))
+	if err != nil {
+		return errors.Wrap(err, "failed to load config")
+	}
+
+	repoId := cCtx.String("repo")
+	repoConfig, ok := cfg.Repos[repoId]
+	if !ok {
+		return fmt.Errorf("repository %s not configured", repoId)
+	}
+	if repoConfig.IndexPath == "" {
+		return fmt.Errorf("repository %s has no index, its media are only known while the server is running", repoId)
+	}
+
+	fp, err := index.Fingerprint(repoConfig.IndexPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to make fingerprint")
+	}
+
+	if cCtx.Bool("items") {
+		ids := maps.Keys(fp.Items)
+		slices.Sort(ids)
+
+		for _, id := range ids {
+			if _, err := fmt.Fprintf(cCtx.App.Writer, "%s %s\n", fp.Items[id], id); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = fmt.Fprintf(cCtx.App.Writer, "%s (%d media)\n", fp.Hash, fp.Count)
+	return err
+}
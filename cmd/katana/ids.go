@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/config"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/checksum"
+	"github.com/katana-project/katana/repo/index"
+	"github.com/katana-project/katana/server/playback"
+	"github.com/katana-project/katana/server/stats"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// handleMigrateIDs handles the migrate-ids sub-command.
+// The server must be stopped, the index and the state keyed by media IDs are rewritten in place.
+func (ac *appContext) handleMigrateIDs(cCtx *cli.Context) (err error) {
+	cfg, err := config.ParseWithDefaults(cCtx.String("config"))
+	if err != nil {
+		return errors.Wrap(err, "failed to load config")
+	}
+
+	repoId := cCtx.String("repo")
+	repoConfig, ok := cfg.Repos[repoId]
+	if !ok {
+		return fmt.Errorf("repository %s not configured", repoId)
+	}
+	if repoConfig.IndexPath == "" {
+		return fmt.Errorf("repository %s has no index, its media IDs are made anew on startup", repoId)
+	}
+
+	idStrategy := repo.IDStrategy(repoConfig.IDStrategy)
+	if idStrategy == nil {
+		return fmt.Errorf("unknown media ID strategy %s", repoConfig.IDStrategy)
+	}
+
+	ids, err := index.MigrateIDs(repoConfig.IndexPath, repoConfig.Path, idStrategy)
+	if err != nil {
+		return errors.Wrap(err, "failed to migrate index")
+	}
+	if len(ids) == 0 {
+		ac.logger.Info("media IDs are up to date", zap.String("repo", repoId))
+		return nil
+	}
+
+	pb, err := playback.NewStore(map[string]string{repoId: repoConfig.PlaybackPath()}, ac.logger)
+	if err != nil {
+		return errors.Wrap(err, "failed to open playback store")
+	}
+	pb.RenameMedia(repoId, ids)
+	if err0 := pb.Close(); err0 != nil {
+		err = multierr.Append(err, errors.Wrap(err0, "failed to save playback states"))
+	}
+
+	if cfg.Stats.Path != "" {
+		store, err0 := stats.NewStore(cfg.Stats.Path, cfg.Stats.Retention, ac.logger)
+		if err0 != nil {
+			return multierr.Append(err, errors.Wrap(err0, "failed to open statistics store"))
+		}
+		store.RenameMedia(repoId, ids)
+		if err0 := store.Close(); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to save statistics"))
+		}
+	}
+
+	if cfg.Checksums.Path != "" {
+		catalog, err0 := checksum.NewCatalog(cfg.Checksums.Path, ac.logger)
+		if err0 != nil {
+			return multierr.Append(err, errors.Wrap(err0, "failed to open checksum catalog"))
+		}
+		if err0 := catalog.RenameMedia(repoId, ids); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to save checksum catalog"))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	for oldId, newId := range ids {
+		ac.logger.Info("migrated media ID", zap.String("repo", repoId), zap.String("old_id", oldId), zap.String("new_id", newId))
+	}
+	ac.logger.Info("media IDs migrated successfully", zap.String("repo", repoId), zap.Int("changed", len(ids)))
+	return nil
+}
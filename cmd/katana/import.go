@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/config"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/index"
+	"github.com/katana-project/katana/server/playback"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+	"os"
+)
+
+// handleImportState handles the import-state sub-command.
+// The server must be stopped, the playback states of the repository are rewritten in place.
+func (ac *appContext) handleImportState(cCtx *cli.Context) error {
+	format := playback.ImportFormat(cCtx.String("format"))
+	if !slices.Contains(playback.ImportFormats, format) {
+		return fmt.Errorf("unknown import format %s, expected one of %v", format, playback.ImportFormats)
+	}
+
+	cfg, err := config.ParseWithDefaults(cCtx.String("config"))
+	if err != nil {
+		return errors.Wrap(err, "failed to load config")
+	}
+
+	repoId := cCtx.String("repo")
+	repoConfig, ok := cfg.Repos[repoId]
+	if !ok {
+		return fmt.Errorf("repository %s not configured", repoId)
+	}
+	if repoConfig.IndexPath == "" {
+		return fmt.Errorf("repository %s has no index, its media are only known while the server is running", repoId)
+	}
+
+	f, err := os.Open(cCtx.String("in"))
+	if err != nil {
+		return errors.Wrap(err, "failed to open export")
+	}
+	defer f.Close()
+
+	entries, err := playback.ParseImport(f, format)
+	if err != nil {
+		return err
+	}
+
+	items, err := index.Items(repoConfig.IndexPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read index")
+	}
+
+	matched, unmatched := playback.MatchImport(entries, repoId, items)
+	for _, entry := range unmatched {
+		ac.logger.Info("no media matched", zap.String("repo", repoId), zap.Stringer("entry", entry))
+	}
+	if cCtx.Bool("dry-run") {
+		for mediaId, entry := range matched {
+			ac.logger.Info("matched media", zap.String("repo", repoId), zap.String("media", mediaId), zap.Stringer("entry", entry))
+		}
+		ac.logger.Info("dry run finished", zap.String("repo", repoId), zap.Int("matched", len(matched)), zap.Int("unmatched", len(unmatched)))
+		return nil
+	}
+
+	pb, err := playback.NewStore(map[string]string{repoId: repoConfig.PlaybackPath()}, ac.logger)
+	if err != nil {
+		return errors.Wrap(err, "failed to open playback store")
+	}
+
+	var (
+		user     = cCtx.String("user")
+		imported int
+	)
+	for mediaId, entry := range matched {
+		if pb.Import(repoId, user, mediaId, entry.Position, entry.Watched, entry.Updated) {
+			imported++
+		}
+	}
+	if err := pb.Close(); err != nil {
+		return errors.Wrap(err, "failed to save playback states")
+	}
+
+	ac.logger.Info(
+		"watch state imported successfully",
+		zap.String("repo", repoId),
+		zap.String("user", user),
+		zap.Int("imported", imported),
+		zap.Int("skipped", len(matched)-imported),
+		zap.Int("unmatched", len(unmatched)),
+	)
+	return nil
+}
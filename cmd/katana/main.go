@@ -28,6 +28,11 @@ func main() {
 						Usage:   "the configuration path, defaults to config.toml",
 						Value:   "config.toml",
 					},
+					&cli.StringFlag{
+						Name:  "setup-host",
+						Usage: "the address of the setup wizard, started if the configuration doesn't exist, defaults to :8000",
+						Value: ":8000",
+					},
 				},
 				Action: appCtx.handleServer,
 			},
@@ -44,6 +49,164 @@ func main() {
 				},
 				Action: appCtx.handleConfig,
 			},
+			{
+				Name:  "backup",
+				Usage: "backs up the server state (configuration, indexes), excluding media",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "the configuration path, defaults to config.toml",
+						Value:   "config.toml",
+					},
+					&cli.StringFlag{
+						Name:    "out",
+						Aliases: []string{"o"},
+						Usage:   "the backup archive path, defaults to katana-backup-<date>.tar.zst",
+					},
+				},
+				Action: appCtx.handleBackup,
+			},
+			{
+				Name:  "restore",
+				Usage: "restores the server state from a backup to its original paths",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "in",
+						Aliases:  []string{"i"},
+						Usage:    "the backup archive path",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:    "force",
+						Aliases: []string{"f"},
+						Usage:   "overwrite existing files",
+					},
+				},
+				Action: appCtx.handleRestore,
+			},
+			{
+				Name:  "migrate-ids",
+				Usage: "remakes the media IDs of a repository's index with its configured ID strategy, the server must be stopped",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "the configuration path, defaults to config.toml",
+						Value:   "config.toml",
+					},
+					&cli.StringFlag{
+						Name:     "repo",
+						Aliases:  []string{"r"},
+						Usage:    "the repository ID",
+						Required: true,
+					},
+				},
+				Action: appCtx.handleMigrateIDs,
+			},
+			{
+				Name:  "fingerprint",
+				Usage: "prints the content fingerprint of a repository's index, for comparing it with other servers",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "the configuration path, defaults to config.toml",
+						Value:   "config.toml",
+					},
+					&cli.StringFlag{
+						Name:     "repo",
+						Aliases:  []string{"r"},
+						Usage:    "the repository ID",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:  "items",
+						Usage: "print the digests of the single media too, for finding the media that differ",
+					},
+				},
+				Action: appCtx.handleFingerprint,
+			},
+			{
+				Name:  "import-state",
+				Usage: "imports the watch state of a user from an export of another server (katana, plex, jellyfin, trakt), the server must be stopped",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "config",
+						Aliases: []string{"c"},
+						Usage:   "the configuration path, defaults to config.toml",
+						Value:   "config.toml",
+					},
+					&cli.StringFlag{
+						Name:     "repo",
+						Aliases:  []string{"r"},
+						Usage:    "the repository ID",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "in",
+						Aliases:  []string{"i"},
+						Usage:    "the export path",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "format",
+						Aliases:  []string{"f"},
+						Usage:    "the export format, one of katana, plex, jellyfin and trakt",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "user",
+						Aliases: []string{"u"},
+						Usage:   "the user to import the watch state for, empty if authentication is disabled",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "only log the matches, without importing them",
+					},
+				},
+				Action: appCtx.handleImportState,
+			},
+			{
+				Name:  "token",
+				Usage: "generates a static API token along with its hash for the configuration",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "feed",
+						Usage: "generate a feed token, for subscribing to feeds such as the calendar",
+					},
+				},
+				Action: appCtx.handleToken,
+			},
+			{
+				Name:   "password",
+				Usage:  "hashes a password read from standard input for the configuration",
+				Action: appCtx.handlePassword,
+			},
+			{
+				Name:  "update",
+				Usage: "replaces the executable with the latest release, if it's newer",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "check",
+						Usage: "only check whether an update is available",
+					},
+					&cli.StringFlag{
+						Name:  "feed",
+						Usage: "the URL of the latest release in the GitHub API, defaults to the project's releases",
+					},
+					&cli.BoolFlag{
+						Name:  "skip-signature",
+						Usage: "accept checksums without verifying their signature, for builds without a public key",
+					},
+				},
+				Action: appCtx.handleUpdate,
+			},
+			{
+				Name:   "version",
+				Usage:  "prints the version of the executable",
+				Action: appCtx.handleVersion,
+			},
 		},
 	}
 
@@ -1,55 +1,168 @@
 package main
 
 import (
+	"context"
 	"github.com/katana-project/katana/config"
 	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/internal/trace"
 	"github.com/katana-project/katana/server"
 	"github.com/urfave/cli/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+	"io/fs"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
 )
 
 // handleServer handles the server sub-command.
 func (ac *appContext) handleServer(cCtx *cli.Context) (err error) {
-	cfg, err := config.ParseWithDefaults(cCtx.String("config"))
+	path := cCtx.String("config")
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		done, err := ac.runSetup(cCtx.Context, path, cCtx.String("setup-host"))
+		if err != nil || !done {
+			return err
+		}
+	}
+
+	cfg, err := config.ParseWithDefaults(path)
 	if err != nil {
 		return errors.Wrap(err, "failed to load config")
 	}
 
+	if cfg.Tracing.Endpoint != "" { // zero value
+		tp, err := trace.NewProvider(cCtx.Context, cfg.Tracing.Endpoint, cfg.Tracing.ServiceName, cfg.Tracing.SampleRatio)
+		if err != nil {
+			return errors.Wrap(err, "failed to create tracer provider")
+		}
+
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+			ac.logger.Warn("failed to export spans", zap.Error(err))
+		}))
+		defer func() { // after shutting down, spans of it are exported too
+			if err0 := tp.Shutdown(context.Background()); err0 != nil {
+				err = multierr.Append(err, errors.Wrap(err0, "failed to shut down tracer provider"))
+			}
+		}()
+	}
+
+	// opened before scanning repositories, so that taken ports fail fast
+	listeners := make([]net.Listener, 0, len(cfg.HTTP.Listeners))
+	defer func() { // closed by the http server already, unless returning early
+		for _, l := range listeners {
+			_ = l.Close()
+		}
+	}()
+	for _, lc := range cfg.HTTP.Listeners {
+		l, err := server.Listen(lc)
+		if err != nil {
+			return err
+		}
+
+		listeners = append(listeners, l)
+	}
+
 	handler, err := server.NewConfiguredRouter(cfg, ac.logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to configure router")
 	}
-	defer func() {
-		if err0 := handler.Close(); err0 != nil {
-			err = multierr.Append(err, errors.Wrap(err0, "failed to close handler"))
-		}
-	}()
 
 	var (
-		httpServer = &http.Server{Addr: cfg.HTTP.Host, Handler: handler}
-		errorChan  = make(chan error)
+		httpServer = &http.Server{Handler: handler}
+		errorChan  = make(chan error, len(listeners)+1)
+		shutdown   = server.NewShutdown(ac.logger)
 	)
-	go func() {
-		ac.logger.Info("listening for http requests", zap.String("addr", httpServer.Addr))
-		errorChan <- httpServer.ListenAndServe()
-	}()
+	httpServer.RegisterOnShutdown(handler.Drain) // event streams never go idle, end them
+	// stop taking requests first, then jobs, statistics and repositories are closed by the handler (v1.Server.Shutdown)
+	shutdown.Add("http server", func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, cfg.HTTP.ShutdownTimeout)
+		defer cancel()
 
-	ctx, stop := signal.NotifyContext(cCtx.Context, os.Interrupt)
+		if err := httpServer.Shutdown(ctx); err != nil {
+			return multierr.Append(err, httpServer.Close()) // interrupt requests past the timeout
+		}
+
+		return nil
+	})
+	shutdown.Add("handler", func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Jobs.DrainTimeout)
+		defer cancel()
+
+		return handler.Shutdown(ctx)
+	})
+
+	if cfg.HTTP.TLS() {
+		var redirectHandler http.Handler
+		if cfg.HTTP.ACME() {
+			manager := &autocert.Manager{ // renews certificates in the background as they're requested
+				Prompt:     autocert.AcceptTOS,
+				Cache:      autocert.DirCache(cfg.HTTP.ACMECacheDir),
+				HostPolicy: autocert.HostWhitelist(cfg.HTTP.ACMEDomains...),
+				Email:      cfg.HTTP.ACMEEmail,
+				Client:     &acme.Client{DirectoryURL: cfg.HTTP.ACMEDirectory},
+			}
+			httpServer.TLSConfig = manager.TLSConfig()
+
+			redirectHandler = manager.HTTPHandler(server.NewRedirectHandler(cfg.HTTP.TLSHost()))
+		} else if cfg.HTTP.RedirectHost != "" {
+			redirectHandler = server.NewRedirectHandler(cfg.HTTP.TLSHost())
+		}
+
+		if redirectHandler != nil {
+			redirectServer := &http.Server{Addr: cfg.HTTP.RedirectHost, Handler: redirectHandler}
+			shutdown.Add("redirect server", func(ctx context.Context) error {
+				ctx, cancel := context.WithTimeout(ctx, cfg.HTTP.ShutdownTimeout)
+				defer cancel()
+
+				return redirectServer.Shutdown(ctx)
+			})
+
+			go func() {
+				ac.logger.Info("listening for http requests to redirect", zap.String("addr", redirectServer.Addr))
+				if err := redirectServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+					errorChan <- errors.Wrap(err, "redirect server errored")
+				}
+			}()
+		}
+	}
+
+	for i, l := range listeners {
+		useTLS := cfg.HTTP.TLS() && cfg.HTTP.Listeners[i].Socket == "" // sockets are for local reverse proxies
+
+		go func(l net.Listener) {
+			if useTLS {
+				ac.logger.Info("listening for https requests", zap.Stringer("addr", l.Addr()))
+				errorChan <- httpServer.ServeTLS(l, cfg.HTTP.TLSCert, cfg.HTTP.TLSKey) // ignored with ACME, GetCertificate is used
+				return
+			}
+
+			ac.logger.Info("listening for http requests", zap.Stringer("addr", l.Addr()))
+			errorChan <- httpServer.Serve(l)
+		}(l)
+	}
+
+	ctx, stop := signal.NotifyContext(cCtx.Context, os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
 	select {
 	case <-ctx.Done():
-		ac.logger.Info("shutting down gracefully")
-		if err = httpServer.Shutdown(ctx); err != nil {
-			err = errors.Wrap(err, "failed to shutdown http server")
-		}
+		ac.logger.Info(
+			"shutting down gracefully",
+			zap.Duration("timeout", cfg.HTTP.ShutdownTimeout),
+			zap.Duration("drain_timeout", cfg.Jobs.DrainTimeout),
+		)
 	case err = <-errorChan:
 		err = errors.Wrap(err, "http server errored")
 	}
+	stop() // a repeated signal kills the process
 
-	return err
+	return multierr.Append(err, shutdown.Run(context.Background())) // each step is bounded by its own timeout
 }
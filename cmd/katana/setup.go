@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/server/setup"
+	"go.uber.org/zap"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// setupShutdownTimeout is the timeout of shutting down the setup wizard server.
+const setupShutdownTimeout = 5 * time.Second
+
+// runSetup serves the setup wizard on host until it writes the configuration to path.
+// Returns whether it was written, false if the server was interrupted first.
+func (ac *appContext) runSetup(ctx context.Context, path, host string) (bool, error) {
+	l, err := net.Listen("tcp", host)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to listen on %s", host)
+	}
+
+	var (
+		wizard     = setup.NewWizard(path, host, ac.logger)
+		httpServer = &http.Server{Handler: wizard}
+		errorChan  = make(chan error, 1)
+	)
+	go func() {
+		errorChan <- httpServer.Serve(l)
+	}()
+
+	ac.logger.Warn(
+		"configuration not found, serving the setup wizard, it's unauthenticated until finished",
+		zap.String("path", path),
+		zap.Stringer("addr", l.Addr()),
+	)
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var done bool
+	select {
+	case <-ctx.Done():
+	case <-wizard.Done():
+		done = true
+	case err = <-errorChan:
+		return false, errors.Wrap(err, "setup server errored")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), setupShutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil { // the finishing request is responded to first
+		_ = httpServer.Close()
+	}
+
+	return done, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/server/auth"
+	"github.com/urfave/cli/v2"
+	"strings"
+)
+
+// handleToken handles the token sub-command.
+func (ac *appContext) handleToken(cCtx *cli.Context) error {
+	token, err := auth.NewToken()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate token")
+	}
+
+	section := "auth.tokens"
+	if cCtx.Bool("feed") {
+		section = "auth.feed_tokens"
+	}
+
+	hash := auth.HashToken(token)
+	_, err = fmt.Fprintf(
+		cCtx.App.Writer,
+		"token: %s\nadd the token hash to the %s configuration: \"%s\"\n",
+		token,
+		section,
+		hash,
+	)
+	if err == nil && !cCtx.Bool("feed") {
+		_, err = fmt.Fprintf(cCtx.App.Writer, "the token's client ID for transfer caps is \"%s\"\n", auth.TokenClient(hash))
+	}
+	return err
+}
+
+// handlePassword handles the password sub-command.
+func (ac *appContext) handlePassword(cCtx *cli.Context) error {
+	line, err := bufio.NewReader(cCtx.App.Reader).ReadString('\n')
+	if err != nil && line == "" {
+		return errors.Wrap(err, "failed to read password")
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return errors.New("password is empty")
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return errors.Wrap(err, "failed to hash password")
+	}
+
+	_, err = fmt.Fprintf(cCtx.App.Writer, "add the password hash to the auth.users configuration: \"%s\"\n", hash)
+	return err
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/katana-project/katana"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/internal/update"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// startTimeout is the timeout of the new executable printing its version after an update.
+const startTimeout = 30 * time.Second
+
+// handleVersion handles the version sub-command.
+func (ac *appContext) handleVersion(cCtx *cli.Context) error {
+	_, err := fmt.Fprintln(cCtx.App.Writer, katana.Version)
+	return err
+}
+
+// handleUpdate handles the update sub-command.
+func (ac *appContext) handleUpdate(cCtx *cli.Context) error {
+	key, err := update.ParsePublicKey()
+	if err != nil {
+		return err
+	}
+	if key == nil && !cCtx.Bool("check") && !cCtx.Bool("skip-signature") {
+		return errors.New("the build has no public key to verify releases with, pass --skip-signature to only verify checksums")
+	}
+
+	client := update.NewClient(cCtx.String("feed"), katana.Version, nil)
+	release, err := client.Latest(cCtx.Context)
+	if err != nil {
+		return err
+	}
+	if !update.Newer(release.Version, katana.Version) {
+		ac.logger.Info("no update available", zap.String("version", katana.Version), zap.String("latest", release.Version))
+		return nil
+	}
+
+	ac.logger.Info("update available", zap.String("version", katana.Version), zap.String("latest", release.Version))
+	if cCtx.Bool("check") {
+		return nil
+	}
+
+	name := update.AssetName(runtime.GOOS, runtime.GOARCH)
+	binary, err := client.Download(cCtx.Context, release, name)
+	if err != nil {
+		return err
+	}
+	checksums, err := client.Download(cCtx.Context, release, update.ChecksumsAsset)
+	if err != nil {
+		return err
+	}
+
+	var signature []byte
+	if key != nil {
+		if signature, err = client.Download(cCtx.Context, release, update.SignatureAsset); err != nil {
+			return err
+		}
+	}
+	if err := update.Verify(binary, name, checksums, signature, key); err != nil {
+		return errors.Wrap(err, "failed to verify release")
+	}
+
+	path, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, "failed to find executable")
+	}
+	if path, err = filepath.EvalSymlinks(path); err != nil {
+		return errors.Wrap(err, "failed to resolve executable")
+	}
+
+	if err := update.Install(path, binary, func(path string) error {
+		return checkStart(cCtx.Context, path, release.Version)
+	}); err != nil {
+		return err
+	}
+
+	ac.logger.Info(
+		"updated, restart the server to run the new version",
+		zap.String("version", release.Version),
+		zap.String("path", path),
+		zap.String("previous", path+update.OldSuffix),
+	)
+	return nil
+}
+
+// checkStart checks that an executable starts, by running its version sub-command, which must print the expected version.
+func checkStart(ctx context.Context, path, version string) error {
+	ctx, cancel := context.WithTimeout(ctx, startTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "version")
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.Wrapf(err, "version sub-command failed (%s)", msg)
+		}
+
+		return errors.Wrap(err, "version sub-command failed")
+	}
+
+	if got := strings.TrimSpace(stdout.String()); got != version {
+		return fmt.Errorf("expected version %s, got %s", version, got)
+	}
+
+	return nil
+}
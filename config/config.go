@@ -1,9 +1,13 @@
 package config
 
 import (
+	"fmt"
 	"github.com/BurntSushi/toml"
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 // MetadataSource is a metadata source ID.
@@ -16,6 +20,10 @@ const (
 	MetadataSourceAnalysis MetadataSource = "analysis"
 	// MetadataSourceTMDB is the TMDB (The Movie Database) metadata source ID (tmdb.NewSource).
 	MetadataSourceTMDB MetadataSource = "tmdb"
+	// MetadataSourceAniList is the AniList anime metadata source ID (anilist.NewSource).
+	MetadataSourceAniList MetadataSource = "anilist"
+	// MetadataSourceTags is the embedded container tag metadata source ID (mux.NewTagSource).
+	MetadataSourceTags MetadataSource = "tags"
 )
 
 // Capability is a capability ID.
@@ -30,6 +38,42 @@ const (
 	CapabilityTranscode Capability = "transcode"
 )
 
+// IDStrategy is a media ID strategy ID.
+type IDStrategy string
+
+const (
+	// IDStrategySlug is the sanitized file name media ID strategy ID (media.IDStrategySlug).
+	IDStrategySlug IDStrategy = "slug"
+	// IDStrategyHash is the path hash media ID strategy ID (media.IDStrategyHash).
+	IDStrategyHash IDStrategy = "hash"
+	// IDStrategyUUID is the path UUID media ID strategy ID (media.IDStrategyUUID).
+	IDStrategyUUID IDStrategy = "uuid"
+	// IDStrategyContent is the file content hash media ID strategy ID (media.IDStrategyContent).
+	IDStrategyContent IDStrategy = "content"
+	// IDStrategyMeta is the metadata-based media ID strategy ID (media.IDStrategyMeta).
+	IDStrategyMeta IDStrategy = "meta"
+)
+
+// CacheLayout is an operation cache file naming layout ID.
+type CacheLayout string
+
+const (
+	// CacheLayoutFlat is the flat cache layout ID (mux.LayoutFlat).
+	CacheLayoutFlat CacheLayout = "flat"
+	// CacheLayoutFormat is the per-format directory cache layout ID (mux.LayoutFormat).
+	CacheLayoutFormat CacheLayout = "format"
+)
+
+// ReplicaConflict is a replication conflict rule ID.
+type ReplicaConflict string
+
+const (
+	// ReplicaConflictOrigin is the ID of the rule replacing local changes with the origin's (replica.ConflictOrigin).
+	ReplicaConflictOrigin ReplicaConflict = "origin"
+	// ReplicaConflictLocal is the ID of the rule keeping local changes (replica.ConflictLocal).
+	ReplicaConflictLocal ReplicaConflict = "local"
+)
+
 // Section is a section of the configuration file.
 // T is always going to be the type of this section.
 type Section[T any] interface {
@@ -41,6 +85,28 @@ type Section[T any] interface {
 type Config struct {
 	// HTTP is the "http" configuration section.
 	HTTP *HTTP `toml:"http"`
+	// Jobs is the "jobs" configuration section.
+	Jobs *Jobs `toml:"jobs"`
+	// Stats is the "stats" configuration section.
+	Stats *Stats `toml:"stats"`
+	// Auth is the "auth" configuration section.
+	Auth *Auth `toml:"auth"`
+	// Tracing is the "tracing" configuration section.
+	Tracing *Tracing `toml:"tracing"`
+	// Mux is the "mux" configuration section.
+	Mux *Mux `toml:"mux"`
+	// Checksums is the "checksums" configuration section.
+	Checksums *Checksums `toml:"checksums"`
+	// Refresh is the "refresh" configuration section.
+	Refresh *Refresh `toml:"refresh"`
+	// Webhooks is the "webhooks" configuration section.
+	Webhooks *Webhooks `toml:"webhooks"`
+	// Collections is the "collections" configuration section.
+	Collections *Collections `toml:"collections"`
+	// Update is the "update" configuration section.
+	Update *Update `toml:"update"`
+	// Identify is the "identify" configuration section.
+	Identify *Identify `toml:"identify"`
 	// Repos is the collection of repository configuration, keyed by their ID.
 	Repos map[string]*Repo `toml:"repos"`
 }
@@ -48,63 +114,855 @@ type Config struct {
 // Defaults completes the configuration with default values.
 func (c *Config) Defaults() *Config {
 	c.HTTP = c.HTTP.Defaults()
+	c.Jobs = c.Jobs.Defaults()
+	c.Stats = c.Stats.Defaults()
+	c.Auth = c.Auth.Defaults()
+	c.Tracing = c.Tracing.Defaults()
+	c.Mux = c.Mux.Defaults()
+	c.Checksums = c.Checksums.Defaults()
+	c.Refresh = c.Refresh.Defaults()
+	c.Webhooks = c.Webhooks.Defaults()
+	c.Update = c.Update.Defaults()
+	c.Identify = c.Identify.Defaults()
 	for k, v := range c.Repos {
 		def := v.Defaults()
 		if def.Name == "" {
 			def.Name = k
 		}
+		if def.Replica.Repo == "" {
+			def.Replica.Repo = k
+		}
 
 		c.Repos[k] = def
 	}
+	c.Collections = c.Collections.Defaults()
+	if c.Collections.Path == "" { // next to the index of the first indexed repository
+		repoIds := maps.Keys(c.Repos)
+		slices.Sort(repoIds)
+		for _, repoId := range repoIds {
+			if indexPath := c.Repos[repoId].IndexPath; indexPath != "" {
+				c.Collections.Path = filepath.Join(filepath.Dir(indexPath), "collections.json")
+				break
+			}
+		}
+	}
 
 	return c
 }
 
 // HTTP is an HTTP configuration section of the configuration file.
 type HTTP struct {
-	// Host is the host string, used for http.ListenAndServe, defaults to ":8000".
+	// Host is the host string of the TCP listener, used if no Listeners are configured, defaults to ":8000".
 	Host string `toml:"host"`
+	// Listeners are the TCP and Unix domain socket listeners, all serving the same requests, defaults to one listening on Host.
+	Listeners []*Listener `toml:"listeners"`
+	// ShutdownTimeout is the maximum duration of waiting for in-flight requests when shutting down, such as "10s", defaults to 30 seconds.
+	// Requests still running afterward, such as long media streams, are interrupted.
+	ShutdownTimeout time.Duration `toml:"shutdown_timeout"`
+	// TLSCert is the path of a PEM-encoded TLS certificate (chain), HTTPS is served if it and TLSKey are set.
+	TLSCert string `toml:"tls_cert"`
+	// TLSKey is the path of the PEM-encoded private key of TLSCert.
+	TLSKey string `toml:"tls_key"`
+	// ACMEDomains are the domains of a certificate obtained and renewed from an ACME server (Let's Encrypt by default),
+	// HTTPS is served with it if set, instead of TLSCert and TLSKey.
+	// The domains are validated over HTTP, RedirectHost must be reachable on port 80 of each.
+	ACMEDomains []string `toml:"acme_domains"`
+	// ACMECacheDir is the relative or absolute path of the directory storing the ACME account key and certificate,
+	// defaults to "./.katana/acme".
+	ACMECacheDir string `toml:"acme_cache_dir"`
+	// ACMEEmail is the contact e-mail address of the ACME account, notified of expiring certificates, can be empty.
+	ACMEEmail string `toml:"acme_email"`
+	// ACMEDirectory is the directory URL of the ACME server, defaults to Let's Encrypt.
+	ACMEDirectory string `toml:"acme_directory"`
+	// RedirectHost is the host string of a plain HTTP listener redirecting to HTTPS, used only if HTTPS is served,
+	// defaults to ":80" with ACMEDomains, disabled if empty otherwise.
+	RedirectHost string `toml:"redirect_host"`
+	// CORS is the "http.cors" configuration section.
+	CORS *CORS `toml:"cors"`
+	// AccessLog is the "http.access_log" configuration section.
+	AccessLog *AccessLog `toml:"access_log"`
+}
+
+// ACME checks whether the certificate is obtained from an ACME server.
+func (h *HTTP) ACME() bool {
+	return len(h.ACMEDomains) > 0
+}
+
+// TLS checks whether HTTPS is served.
+func (h *HTTP) TLS() bool {
+	return h.ACME() || (h.TLSCert != "" && h.TLSKey != "")
 }
 
 // Defaults completes the section with default values.
 func (h *HTTP) Defaults() *HTTP {
+	if h == nil { // section not present
+		h = &HTTP{}
+	}
 	if h.Host == "" {
 		h.Host = ":8000"
 	}
+	if len(h.Listeners) == 0 {
+		h.Listeners = []*Listener{{Host: h.Host}}
+	}
+	for i, l := range h.Listeners {
+		h.Listeners[i] = l.Defaults()
+	}
+	h.CORS = h.CORS.Defaults()
+	h.AccessLog = h.AccessLog.Defaults()
+	if h.ShutdownTimeout <= 0 {
+		h.ShutdownTimeout = 30 * time.Second
+	}
+	if h.ACME() {
+		if h.ACMECacheDir == "" {
+			h.ACMECacheDir = "./.katana/acme"
+		}
+		if h.ACMEDirectory == "" {
+			h.ACMEDirectory = "https://acme-v02.api.letsencrypt.org/directory"
+		}
+		if h.RedirectHost == "" { // needed for validation
+			h.RedirectHost = ":80"
+		}
+	}
 
 	return h
 }
 
+// TLSHost returns the host string of the first TCP listener, the one HTTP requests are redirected to if HTTPS is served.
+func (h *HTTP) TLSHost() string {
+	for _, l := range h.Listeners {
+		if l.Socket == "" {
+			return l.Host
+		}
+	}
+
+	return ""
+}
+
+// Listener is an HTTP listener configuration of the configuration file, on a TCP host or a Unix domain socket.
+type Listener struct {
+	// Host is the host string of a TCP listener, such as ":8000" or "127.0.0.1:8000".
+	Host string `toml:"host"`
+	// Socket is the relative or absolute path of a Unix domain socket, used instead of Host if set.
+	// Requests over it are always plain HTTP, it's meant for a local reverse proxy.
+	Socket string `toml:"socket"`
+	// SocketMode is the file mode of Socket, such as 0o600, defaults to 0o660 (owner and group).
+	SocketMode uint32 `toml:"socket_mode"`
+}
+
+// Defaults completes the section with default values.
+func (l *Listener) Defaults() *Listener {
+	if l == nil {
+		l = &Listener{}
+	}
+	if l.SocketMode == 0 {
+		l.SocketMode = 0660
+	}
+
+	return l
+}
+
+// CORS is a cross-origin resource sharing configuration section of the API, part of the HTTP section.
+type CORS struct {
+	// AllowedOrigins are the origins allowed to make cross-origin requests, with at most one "*" wildcard each,
+	// such as "https://*.example.com", defaults to all ("https://*" and "http://*").
+	AllowedOrigins []string `toml:"allowed_origins"`
+	// AllowedMethods are the HTTP methods of allowed cross-origin requests, defaults to GET, POST, PUT, PATCH and DELETE.
+	AllowedMethods []string `toml:"allowed_methods"`
+	// AllowedHeaders are the request headers allowed in cross-origin requests, defaults to the ones used by the API.
+	AllowedHeaders []string `toml:"allowed_headers"`
+	// AllowCredentials allows cross-origin requests with credentials (cookies), AllowedOrigins should be listed explicitly then.
+	AllowCredentials bool `toml:"allow_credentials"`
+	// MaxAge is the duration for which preflight request results can be cached, such as "10m", defaults to 5 minutes.
+	MaxAge time.Duration `toml:"max_age"`
+}
+
+// Defaults completes the section with default values.
+func (c *CORS) Defaults() *CORS {
+	if c == nil { // section not present
+		c = &CORS{}
+	}
+	if len(c.AllowedOrigins) == 0 {
+		c.AllowedOrigins = []string{"https://*", "http://*"}
+	}
+	if len(c.AllowedMethods) == 0 {
+		c.AllowedMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	}
+	if len(c.AllowedHeaders) == 0 {
+		c.AllowedHeaders = []string{"Accept", "Authorization", "Content-Type", "If-None-Match", "If-Modified-Since"}
+	}
+	if c.MaxAge <= 0 {
+		c.MaxAge = 5 * time.Minute
+	}
+
+	return c
+}
+
+// AccessLog is a request logging configuration section, part of the HTTP section.
+type AccessLog struct {
+	// Disabled disables logging of requests.
+	Disabled bool `toml:"disabled"`
+	// ErrorsOnly limits logging to requests with an error response (status 400 and above), for high-traffic deployments.
+	ErrorsOnly bool `toml:"errors_only"`
+	// SkipPaths are URL path prefixes of requests that aren't logged, such as "/api/v1/events".
+	SkipPaths []string `toml:"skip_paths"`
+}
+
+// Defaults completes the section with default values.
+func (a *AccessLog) Defaults() *AccessLog {
+	if a == nil { // section not present
+		a = &AccessLog{}
+	}
+
+	return a
+}
+
+// Jobs is a background job configuration section of the configuration file.
+type Jobs struct {
+	// Workers is the number of concurrently processed jobs, defaults to 1.
+	Workers int `toml:"workers"`
+	// Retention is the duration for which finished jobs are kept, such as "30m", defaults to 1 hour.
+	Retention time.Duration `toml:"retention"`
+	// DrainTimeout is the maximum duration of waiting for running jobs when shutting down, such as "5m", defaults to 30 seconds.
+	// Jobs still running afterward are canceled, their partial cache files are removed.
+	DrainTimeout time.Duration `toml:"drain_timeout"`
+}
+
+// Defaults completes the section with default values.
+func (j *Jobs) Defaults() *Jobs {
+	if j == nil { // section not present
+		j = &Jobs{}
+	}
+	if j.Workers <= 0 {
+		j.Workers = 1
+	}
+	if j.Retention <= 0 {
+		j.Retention = time.Hour
+	}
+	if j.DrainTimeout <= 0 {
+		j.DrainTimeout = 30 * time.Second
+	}
+
+	return j
+}
+
+// Stats is a streaming statistics configuration section of the configuration file.
+type Stats struct {
+	// Path is the relative or absolute path of the statistics file, statistics are only kept in memory if empty.
+	Path string `toml:"path"`
+	// Retention is the duration for which daily statistics are kept, such as "720h", defaults to 90 days.
+	Retention time.Duration `toml:"retention"`
+	// MonthlyCap is the number of bytes served to a client per calendar month, streaming is refused past it, zero is unlimited.
+	MonthlyCap int64 `toml:"monthly_cap"`
+	// Caps are monthly caps of specific clients by their ID, overriding MonthlyCap, zero is unlimited.
+	// The client ID is the username of users, or "token:" followed by the start of an API token's hash (see the "token" sub-command).
+	Caps map[string]int64 `toml:"caps"`
+}
+
+// Defaults completes the section with default values.
+func (s *Stats) Defaults() *Stats {
+	if s == nil { // section not present
+		s = &Stats{}
+	}
+	if s.Retention <= 0 {
+		s.Retention = 90 * 24 * time.Hour
+	}
+
+	return s
+}
+
+// Auth is an authentication configuration section of the configuration file.
+// Authentication is enforced if at least one token or user is configured.
+type Auth struct {
+	// Tokens are the hex-encoded SHA-256 hashes of static API tokens, generated by the "token" sub-command.
+	Tokens []string `toml:"tokens"`
+	// FeedTokens are the hex-encoded SHA-256 hashes of feed tokens, generated by the "token" sub-command.
+	// Feed tokens are passed in the URL of feeds (such as the calendar feed) and authenticate nothing else.
+	FeedTokens []string `toml:"feed_tokens"`
+	// Users is a mapping of usernames to their password hashes, generated by the "password" sub-command.
+	Users map[string]string `toml:"users"`
+	// SessionTTL is the duration for which user sessions are valid after logging in, such as "24h", defaults to 7 days.
+	SessionTTL time.Duration `toml:"session_ttl"`
+}
+
+// Defaults completes the section with default values.
+func (a *Auth) Defaults() *Auth {
+	if a == nil { // section not present
+		a = &Auth{}
+	}
+	if a.SessionTTL <= 0 {
+		a.SessionTTL = 7 * 24 * time.Hour
+	}
+
+	return a
+}
+
+// Tracing is an OpenTelemetry tracing configuration section of the configuration file.
+type Tracing struct {
+	// Endpoint is the base URL of the OTLP/HTTP endpoint receiving spans, such as "http://localhost:4318", tracing is disabled if empty.
+	Endpoint string `toml:"endpoint"`
+	// ServiceName is the service name of the recorded spans, defaults to "katana".
+	ServiceName string `toml:"service_name"`
+	// SampleRatio is the ratio of recorded traces between 0 and 1, such as 0.1, defaults to 1 (all traces).
+	SampleRatio float64 `toml:"sample_ratio"`
+}
+
+// Defaults completes the section with default values.
+func (t *Tracing) Defaults() *Tracing {
+	if t == nil { // section not present
+		t = &Tracing{}
+	}
+	if t.ServiceName == "" {
+		t.ServiceName = "katana"
+	}
+	if t.SampleRatio <= 0 || t.SampleRatio > 1 {
+		t.SampleRatio = 1
+	}
+
+	return t
+}
+
+// Mux is a media conversion configuration section of the configuration file.
+type Mux struct {
+	// Backend is the conversion backend ID, "libav" (FFmpeg libraries) or "exec" (ffmpeg binaries),
+	// defaults to "libav" if the build has CGO, "exec" otherwise.
+	// The libav backend only transcodes video with the encoder defaults in the source resolution, without seeking,
+	// watermarks or DASH streaming (see media.TranscodeOption), the exec backend supports all of them.
+	Backend string `toml:"backend"`
+	// FFmpegPath is the path or name (looked up in PATH) of the ffmpeg binary used by the exec backend, defaults to "ffmpeg".
+	FFmpegPath string `toml:"ffmpeg_path"`
+	// FFprobePath is the path or name (looked up in PATH) of the ffprobe binary used by the exec backend, defaults to "ffprobe".
+	FFprobePath string `toml:"ffprobe_path"`
+	// ProbeWorkers is the number of workers probing added media ahead of requests, defaults to 2,
+	// negative disables probing ahead (media are then probed on the first request or by a probe job).
+	ProbeWorkers int `toml:"probe_workers"`
+	// Watermark is the configuration of overlaying a watermark on transcoded video,
+	// conversions re-encoding video are refused by conversion backends not supporting it (the "exec" backend does).
+	Watermark *Watermark `toml:"watermark"`
+	// DASH is the configuration of the representations of adaptive streaming.
+	DASH *DASH `toml:"dash"`
+}
+
+// Defaults completes the section with default values.
+func (m *Mux) Defaults() *Mux {
+	if m == nil { // section not present
+		m = &Mux{}
+	}
+	if m.FFmpegPath == "" {
+		m.FFmpegPath = "ffmpeg"
+	}
+	if m.FFprobePath == "" {
+		m.FFprobePath = "ffprobe"
+	}
+	if m.ProbeWorkers == 0 {
+		m.ProbeWorkers = 2
+	}
+
+	m.Watermark = m.Watermark.Defaults()
+	m.DASH = m.DASH.Defaults()
+
+	return m
+}
+
+// Watermark is a transcoded video watermark configuration section, for shared or demo deployments.
+type Watermark struct {
+	// Text is the overlaid text, "{user}" is replaced with the name of the requesting user (empty without authentication)
+	// and "{server}" with the host name of the server, such as "{user}@{server}"; no text is drawn if empty.
+	Text string `toml:"text"`
+	// Image is the relative or absolute path of an overlaid image, such as a PNG logo, no image is drawn if empty.
+	Image string `toml:"image"`
+	// Position is the placement of the watermark, "top-left", "top-right", "bottom-left", "bottom-right" or "center",
+	// defaults to "bottom-right".
+	Position string `toml:"position"`
+	// Opacity is the opacity of the watermark between 0 and 1, defaults to 0.5.
+	Opacity float64 `toml:"opacity"`
+}
+
+// Defaults completes the section with default values.
+func (w *Watermark) Defaults() *Watermark {
+	if w == nil { // section not present
+		w = &Watermark{}
+	}
+	if w.Position == "" {
+		w.Position = "bottom-right"
+	}
+	if w.Opacity <= 0 {
+		w.Opacity = 0.5
+	}
+
+	return w
+}
+
+// DASH is an MPEG-DASH adaptive streaming configuration section, each representation is a transcoded file.
+type DASH struct {
+	// VideoCodec is the video encoder name of video representations, defaults to "libx264".
+	VideoCodec string `toml:"video_codec"`
+	// AudioCodec is the audio encoder name of the audio representation, defaults to "aac".
+	AudioCodec string `toml:"audio_codec"`
+	// AudioBitRate is the bit rate of the audio representation in bits per second, defaults to 128 kb/s.
+	AudioBitRate int64 `toml:"audio_bit_rate"`
+	// Renditions are the video representations, defaults to 1080p, 720p and 480p.
+	Renditions []Rendition `toml:"renditions"`
+}
+
+// Rendition is a video representation of adaptive streaming.
+type Rendition struct {
+	// Height is the video height in pixels, the aspect ratio is kept.
+	Height int `toml:"height"`
+	// VideoBitRate is the video bit rate in bits per second.
+	VideoBitRate int64 `toml:"video_bit_rate"`
+}
+
+// Defaults completes the section with default values.
+func (d *DASH) Defaults() *DASH {
+	if d == nil { // section not present
+		d = &DASH{}
+	}
+	if d.VideoCodec == "" {
+		d.VideoCodec = "libx264"
+	}
+	if d.AudioCodec == "" {
+		d.AudioCodec = "aac"
+	}
+	if d.AudioBitRate <= 0 {
+		d.AudioBitRate = 128_000
+	}
+	if len(d.Renditions) == 0 {
+		d.Renditions = []Rendition{
+			{Height: 1080, VideoBitRate: 5_000_000},
+			{Height: 720, VideoBitRate: 3_000_000},
+			{Height: 480, VideoBitRate: 1_200_000},
+		}
+	}
+
+	return d
+}
+
+// Checksums is a media checksum catalog configuration section of the configuration file.
+type Checksums struct {
+	// Path is the relative or absolute path of the checksum catalog file, checksums aren't computed if empty.
+	Path string `toml:"path"`
+	// Interval is the period between computing checksums of new and modified media, such as "6h", defaults to 24 hours.
+	// They're computed once no jobs are running, so that playback and conversions aren't slowed down.
+	Interval time.Duration `toml:"interval"`
+}
+
+// Defaults completes the section with default values.
+func (c *Checksums) Defaults() *Checksums {
+	if c == nil { // section not present
+		c = &Checksums{}
+	}
+	if c.Interval <= 0 {
+		c.Interval = 24 * time.Hour
+	}
+
+	return c
+}
+
+// Identify is a configuration section of suggesting matches of media whose file names resolve to no or low-confidence metadata,
+// by Chromaprint/AcoustID fingerprints of audio and perceptual hashes of video frames.
+type Identify struct {
+	// Enabled is whether matches are suggested.
+	Enabled bool `toml:"enabled"`
+	// Path is the relative or absolute path of the file keeping the frame hashes of media, they're only kept in memory if empty.
+	Path string `toml:"path"`
+	// FpcalcPath is the path or name (looked up in PATH) of the Chromaprint fpcalc binary, defaults to "fpcalc".
+	FpcalcPath string `toml:"fpcalc_path"`
+	// AcoustIDKey is the AcoustID application API key, audio isn't fingerprinted if empty.
+	AcoustIDKey string `toml:"acoustid_key"`
+}
+
+// Defaults completes the section with default values.
+func (i *Identify) Defaults() *Identify {
+	if i == nil { // section not present
+		i = &Identify{}
+	}
+	if i.FpcalcPath == "" {
+		i.FpcalcPath = "fpcalc"
+	}
+
+	return i
+}
+
+// Refresh is a metadata refresh configuration section of the configuration file.
+type Refresh struct {
+	// Path is the relative or absolute path of the file keeping the refresh times, metadata isn't refreshed periodically if empty.
+	Path string `toml:"path"`
+	// MaxAge is the age of metadata after which it's refreshed, such as "720h", defaults to 30 days.
+	MaxAge time.Duration `toml:"max_age"`
+	// Jitter is the maximum random delay added to the age of each media, so that media added together aren't all refreshed at once,
+	// defaults to 3 days.
+	Jitter time.Duration `toml:"jitter"`
+	// Interval is the period between checks for media due to be refreshed, such as "1h", defaults to 1 hour.
+	// They're refreshed once no jobs are running, so that playback and conversions aren't slowed down.
+	Interval time.Duration `toml:"interval"`
+	// RateLimit is the maximum number of refreshes per second of each repository, so that its metadata sources aren't flooded,
+	// defaults to 1, negative disables limiting.
+	RateLimit float64 `toml:"rate_limit"`
+}
+
+// Defaults completes the section with default values.
+func (r *Refresh) Defaults() *Refresh {
+	if r == nil { // section not present
+		r = &Refresh{}
+	}
+	if r.MaxAge <= 0 {
+		r.MaxAge = 30 * 24 * time.Hour
+	}
+	if r.Jitter <= 0 {
+		r.Jitter = 3 * 24 * time.Hour
+	}
+	if r.Interval <= 0 {
+		r.Interval = time.Hour
+	}
+	if r.RateLimit == 0 {
+		r.RateLimit = 1
+	}
+
+	return r
+}
+
+// PushService is a push notification service ID.
+type PushService string
+
+const (
+	// PushServiceGotify is the Gotify push service ID (notify.ServiceGotify).
+	PushServiceGotify PushService = "gotify"
+	// PushServiceNtfy is the ntfy push service ID (notify.ServiceNtfy).
+	PushServiceNtfy PushService = "ntfy"
+)
+
+// Update is a release update check configuration section of the configuration file.
+type Update struct {
+	// Feed is the URL of the latest release in the GitHub API, defaults to the project's releases (update.DefaultFeed).
+	Feed string `toml:"feed"`
+	// Interval is the period between checks for a newer release, reported by the system endpoint, such as "24h",
+	// defaults to 1 day, negative disables checking. Development builds are never checked.
+	Interval time.Duration `toml:"interval"`
+}
+
+// Defaults completes the section with default values.
+func (u *Update) Defaults() *Update {
+	if u == nil { // section not present
+		u = &Update{}
+	}
+	if u.Interval == 0 {
+		u.Interval = 24 * time.Hour
+	}
+
+	return u
+}
+
+// Webhooks is an outgoing notification configuration section of the configuration file.
+type Webhooks struct {
+	// Push are the Gotify and ntfy endpoints receiving push notifications about selected events.
+	Push []*Push `toml:"push"`
+	// MinFreeSpace is the free space in bytes of a repository's filesystem below which a "low_disk" notification is pushed,
+	// zero disables the check.
+	MinFreeSpace int64 `toml:"min_free_space"`
+	// DiskInterval is the period between checks of repositories' free space, such as "5m", defaults to 15 minutes.
+	DiskInterval time.Duration `toml:"disk_interval"`
+}
+
+// Defaults completes the section with default values.
+func (w *Webhooks) Defaults() *Webhooks {
+	if w == nil { // section not present
+		w = &Webhooks{}
+	}
+	if w.DiskInterval <= 0 {
+		w.DiskInterval = 15 * time.Minute
+	}
+
+	return w
+}
+
+// Push is a push notification endpoint configuration, part of the webhooks section.
+type Push struct {
+	// Service is the push service ID, "gotify" or "ntfy".
+	Service PushService `toml:"service"`
+	// URL is the server URL for Gotify, such as "https://gotify.example.com", or the topic URL for ntfy, such as "https://ntfy.sh/katana".
+	URL string `toml:"url"`
+	// Token is the Gotify application token or the ntfy access token, can be empty for ntfy.
+	Token string `toml:"token"`
+	// Priority is the message priority, defaults to the service default.
+	Priority int `toml:"priority"`
+	// Events are the pushed notification kinds, "media_added", "scan_failed" and "low_disk", defaults to all.
+	Events []string `toml:"events"`
+}
+
+// Collections is a collections configuration section of the configuration file.
+type Collections struct {
+	// Path is the relative or absolute path of the collections file, defaults to collections.json next to the index file
+	// of the first repository (by ID) with one, collections are only kept in memory if there's none.
+	Path string `toml:"path"`
+}
+
+// Defaults completes the section with default values.
+func (c *Collections) Defaults() *Collections {
+	if c == nil { // section not present
+		c = &Collections{}
+	}
+
+	return c
+}
+
 // Repo is a base repository configuration.
 type Repo struct {
 	// Name is the name of the repository, defaults to the repository ID.
 	Name string `toml:"name"`
-	// Path is the relative or absolute path of the repository's directory.
-	Path string `toml:"path"`
+	// Aliases are alternative IDs of the repository usable in API URLs, such as "tv" for "tv-shows-nas1".
+	Aliases []string `toml:"aliases"`
+	// Path is the relative or absolute path of the repository's directory, or a list of them for media spread over multiple directories,
+	// such as on multiple drives. The first directory is the primary one, uploads are saved to it.
+	Path Paths `toml:"path"`
 	// Path is the relative or absolute path of the repository's index file, can be empty.
 	IndexPath string `toml:"index_path"`
-	// CachePath is the relative or absolute path of the repository's operation cache, defaults to <path>/.katana/cache.
+	// CachePath is the relative or absolute path of the repository's operation cache, defaults to <path>/.katana/cache of the first directory.
 	CachePath string `toml:"cache_path"`
+	// CacheMaxBytes is the size limit of the repository's operation cache in bytes, the least recently used files are removed
+	// when it's exceeded, zero means no limit.
+	CacheMaxBytes int64 `toml:"cache_max_bytes"`
+	// CacheMaxAge is the longest time a cache file is kept since its last use, such as "720h", regardless of its format,
+	// zero means no limit (see Cache for per-format retention).
+	CacheMaxAge time.Duration `toml:"cache_max_age"`
+	// Cache is the naming and retention configuration of the repository's operation cache.
+	Cache *Cache `toml:"cache"`
+	// Precache is the configuration of converting newly added media ahead of stream requests.
+	Precache *Precache `toml:"precache"`
+	// Trash is the configuration of the repository's trash of deleted media files.
+	Trash *Trash `toml:"trash"`
+	// Replica is the configuration of mirroring a repository of another server into the repository.
+	Replica *Replica `toml:"replica"`
+	// Archive is the configuration of moving media files of the repository to cold storage.
+	Archive *Archive `toml:"archive"`
 	// Capabilities are the capability IDs of the repository.
 	Capabilities []Capability `toml:"capabilities"`
+	// Quota is the soft storage quota of the repository's media in bytes, new media is refused when exceeded, zero means no quota.
+	Quota int64 `toml:"quota"`
+	// IDStrategy is the strategy ID of making media IDs ("slug", "hash", "uuid", "content" or "meta"), defaults to "slug".
+	// Already indexed media keeps its ID when this is changed, until migrated with the migrate-ids sub-command.
+	IDStrategy IDStrategy `toml:"id_strategy"`
+	// Ignore are glob patterns of files and directories excluded from scanning and watching, besides dot-prefixed ones,
+	// matched against paths relative to the repository directory, such as "**/sample/*" or "*.nfo".
+	// Patterns without a slash match names at any depth, "**" matches any number of directories.
+	Ignore []string `toml:"ignore"`
+	// Include are glob patterns of the only files scanned and watched, like Ignore, all files are if empty.
+	Include []string `toml:"include"`
 	// Sources is a mapping of used metadata sources and their configuration, keyed by their name.
 	Sources map[MetadataSource]map[string]interface{} `toml:"sources"`
 }
 
+// Paths is a list of directory paths, configured as a single path or an array of them.
+type Paths []string
+
+// UnmarshalTOML decodes a single path or an array of them.
+func (p *Paths) UnmarshalTOML(data interface{}) error {
+	switch v := data.(type) {
+	case string:
+		*p = Paths{v}
+	case []interface{}:
+		paths := make(Paths, len(v))
+		for i, path := range v {
+			s, ok := path.(string)
+			if !ok {
+				return fmt.Errorf("expected a path string, got %v", path)
+			}
+
+			paths[i] = s
+		}
+		*p = paths
+	default:
+		return fmt.Errorf("expected a path string or an array of them, got %v", data)
+	}
+
+	return nil
+}
+
+// String returns the paths separated by commas.
+func (p Paths) String() string {
+	return strings.Join(p, ", ")
+}
+
 // Capable checks whether a Capability is contained in the configuration.
 func (r *Repo) Capable(c Capability) bool {
 	return slices.Contains(r.Capabilities, c)
 }
 
+// PlaybackPath returns the path of the repository's playback state file, next to the index file.
+// It's empty if the repository isn't indexed, playback states are only kept in memory then.
+func (r *Repo) PlaybackPath() string {
+	if r.IndexPath == "" {
+		return ""
+	}
+
+	return strings.TrimSuffix(r.IndexPath, filepath.Ext(r.IndexPath)) + ".playback.json"
+}
+
+// ReplicaPath returns the path of the repository's replication state file, next to the index file.
+// It's empty if the repository isn't indexed, the replication state is only kept in memory then.
+func (r *Repo) ReplicaPath() string {
+	if r.IndexPath == "" {
+		return ""
+	}
+
+	return strings.TrimSuffix(r.IndexPath, filepath.Ext(r.IndexPath)) + ".replica.json"
+}
+
+// ArchivePath returns the path of the repository's cold storage state file, next to the index file.
+// It's empty if the repository isn't indexed, the archive state is only kept in memory then.
+func (r *Repo) ArchivePath() string {
+	if r.IndexPath == "" {
+		return ""
+	}
+
+	return strings.TrimSuffix(r.IndexPath, filepath.Ext(r.IndexPath)) + ".archive.json"
+}
+
 // Defaults completes the section with default values.
 func (r *Repo) Defaults() *Repo {
-	if r.CachePath == "" {
-		r.CachePath = filepath.Join(r.Path, ".katana", "cache")
+	if r.CachePath == "" && len(r.Path) > 0 {
+		r.CachePath = filepath.Join(r.Path[0], ".katana", "cache")
+	}
+	if r.IDStrategy == "" {
+		r.IDStrategy = IDStrategySlug
 	}
+	r.Cache = r.Cache.Defaults()
+	r.Precache = r.Precache.Defaults()
+	r.Trash = r.Trash.Defaults()
+	r.Replica = r.Replica.Defaults()
+	r.Archive = r.Archive.Defaults()
 
 	return r
 }
 
+// Precache is a configuration section of a repository for converting newly added media in the background, warming its cache.
+// Media is remuxed to the format, or transcoded if a codec is set; it requires the "remux" or "transcode" capability respectively.
+type Precache struct {
+	// Format is the target format name, such as "mp4", nothing is converted ahead if empty.
+	Format string `toml:"format"`
+	// VideoCodec is the target video encoder name, such as "libx264", empty keeps the source codec.
+	VideoCodec string `toml:"video_codec"`
+	// AudioCodec is the target audio encoder name, such as "aac", empty keeps the source codec;
+	// audio isn't re-encoded by every conversion backend (the "exec" backend does).
+	AudioCodec string `toml:"audio_codec"`
+}
+
+// Defaults completes the section with default values.
+func (p *Precache) Defaults() *Precache {
+	if p == nil { // section not present
+		p = &Precache{}
+	}
+
+	return p
+}
+
+// Trash is a trash configuration section of a repository.
+type Trash struct {
+	// Enabled is whether files of deleted media are moved to a .katana/trash directory of their repository directory
+	// instead of being deleted, restorable until they expire.
+	Enabled bool `toml:"enabled"`
+	// Retention is the duration for which deleted files are kept, such as "168h", defaults to 30 days.
+	Retention time.Duration `toml:"retention"`
+}
+
+// Defaults completes the section with default values.
+func (t *Trash) Defaults() *Trash {
+	if t == nil { // section not present
+		t = &Trash{}
+	}
+	if t.Retention <= 0 {
+		t.Retention = 30 * 24 * time.Hour
+	}
+
+	return t
+}
+
+// Replica is a replication configuration section of a repository, mirroring a repository of another server into it,
+// such as for off-site backups.
+type Replica struct {
+	// URL is the base URL of the origin server, such as "https://katana.example.com", replication is disabled if empty.
+	URL string `toml:"url"`
+	// Token is a static API token of the origin server, can be empty if it doesn't require authentication.
+	Token string `toml:"token"`
+	// Repo is the ID of the origin repository, defaults to the ID of the repository.
+	Repo string `toml:"repo"`
+	// Files is whether media files are downloaded too, only the metadata and tags of media present locally are replicated otherwise.
+	Files bool `toml:"files"`
+	// Conflict is the rule ID for media changed both locally and at the origin since the last replication ("origin" or "local"),
+	// defaults to "origin".
+	Conflict ReplicaConflict `toml:"conflict"`
+	// Prune is whether media removed from the origin are removed from the repository too, along with their files if Files is set.
+	Prune bool `toml:"prune"`
+	// Interval is the period between replications, such as "6h", zero replicates only when requested through the API.
+	Interval time.Duration `toml:"interval"`
+}
+
+// Defaults completes the section with default values.
+func (r *Replica) Defaults() *Replica {
+	if r == nil { // section not present
+		r = &Replica{}
+	}
+	if r.Conflict == "" {
+		r.Conflict = ReplicaConflictOrigin
+	}
+
+	return r
+}
+
+// Archive is a cold storage configuration section of a repository, archived media files are moved to a directory
+// or by commands, their media are kept browsable and restored on playback.
+type Archive struct {
+	// Path is the directory archived media files are moved to, such as on a slower drive, used if Command is empty.
+	Path string `toml:"path"`
+	// Command is the command and arguments moving a file to cold storage, such as ["rclone", "moveto", "{path}", "remote:katana/{key}"],
+	// "{path}" is replaced with the absolute path of the file and "{key}" with its path relative to the repository directory.
+	Command []string `toml:"command"`
+	// RestoreCommand is the command and arguments moving a file back from cold storage, with the same placeholders as Command,
+	// such as ["rclone", "moveto", "remote:katana/{key}", "{path}"], required if Command is set.
+	RestoreCommand []string `toml:"restore_command"`
+}
+
+// Enabled checks whether archiving is configured.
+func (a *Archive) Enabled() bool {
+	return a.Path != "" || len(a.Command) > 0
+}
+
+// Defaults completes the section with default values.
+func (a *Archive) Defaults() *Archive {
+	if a == nil { // section not present
+		a = &Archive{}
+	}
+
+	return a
+}
+
+// Cache is an operation cache configuration section of a repository.
+type Cache struct {
+	// Layout is the layout ID of cache file names, defaults to "flat".
+	Layout CacheLayout `toml:"layout"`
+	// JanitorInterval is the period between removals of expired cache files, such as "30m", defaults to 1 hour.
+	JanitorInterval time.Duration `toml:"janitor_interval"`
+	// Remux is the retention of remuxed files since their last use keyed by the format extension or "*" for any format, such as { mp4 = "720h" }.
+	// Files of formats without a retention are kept for as long as their source media exists.
+	Remux map[string]time.Duration `toml:"remux"`
+	// Transcode is the retention of transcoded files since their last use, keyed like Remux.
+	Transcode map[string]time.Duration `toml:"transcode"`
+}
+
+// Defaults completes the section with default values.
+func (c *Cache) Defaults() *Cache {
+	if c == nil { // section not present
+		c = &Cache{}
+	}
+	if c.Layout == "" {
+		c.Layout = CacheLayoutFlat
+	}
+	if c.JanitorInterval <= 0 {
+		c.JanitorInterval = time.Hour
+	}
+
+	return c
+}
+
 // Parse parses the configuration from a file.
 func Parse(path string) (*Config, error) {
 	var cfg Config
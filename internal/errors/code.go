@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// Code is a stable, machine-readable type of error, shared by the repository, metadata and API layers.
+// The values are the API error types (v1.ErrorType).
+type Code string
+
+const (
+	// CodeInternal is the code of errors not caused by the caller, such as I/O failures. Errors without a code have it.
+	CodeInternal Code = "internal_error"
+	// CodeBadRequest is the code of errors about invalid input, such as a malformed ID or a duplicate path.
+	CodeBadRequest Code = "bad_request"
+	// CodeNotFound is the code of errors about missing media, repositories or files.
+	CodeNotFound Code = "not_found"
+	// CodeUnknownFormat is the code of errors about media formats or codecs that can't be handled.
+	CodeUnknownFormat Code = "unknown_format"
+	// CodeMissingCapability is the code of errors about operations a repository isn't capable of.
+	CodeMissingCapability Code = "missing_capability"
+	// CodeQuotaExceeded is the code of errors about media not fitting into a repository's storage quota.
+	CodeQuotaExceeded Code = "quota_exceeded"
+	// CodeNotReady is the code of errors about results that are still being made.
+	CodeNotReady Code = "not_ready"
+	// CodeGone is the code of errors about media whose file has disappeared.
+	CodeGone Code = "gone"
+	// CodeUnavailable is the code of errors about repositories whose files are unavailable, such as an unmounted network share.
+	CodeUnavailable Code = "unavailable"
+	// CodeUnauthorized is the code of errors about missing or invalid credentials.
+	CodeUnauthorized Code = "unauthorized"
+)
+
+// Coded is an error classified with a Code.
+type Coded interface {
+	error
+
+	// Code returns the code of the error.
+	Code() Code
+}
+
+// codedError is an error classified with a code by WithCode.
+type codedError struct {
+	err  error
+	code Code
+}
+
+// Error returns the string representation of the error.
+func (ce *codedError) Error() string {
+	return ce.err.Error()
+}
+
+// Code returns the code of the error.
+func (ce *codedError) Code() Code {
+	return ce.code
+}
+
+// Unwrap returns the classified error.
+func (ce *codedError) Unwrap() error {
+	return ce.err
+}
+
+// WithCode classifies an error with a code, the error stays matchable with Is and As.
+func WithCode(err error, code Code) error {
+	return &codedError{err: err, code: code}
+}
+
+// Classify returns the code of the first error in the chain classified with one (Coded) along with that error,
+// CodeInternal and the error itself if there's none. Errors wrapping fs.ErrNotExist are CodeNotFound.
+func Classify(err error) (Code, error) {
+	var coded Coded
+	if errors.As(err, &coded) {
+		return coded.Code(), coded
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return CodeNotFound, fs.ErrNotExist
+	}
+
+	return CodeInternal, err
+}
@@ -0,0 +1,30 @@
+package errors
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	sentinel := WithCode(New("queue is full"), CodeBadRequest)
+
+	tests := []struct {
+		err  error
+		code Code
+		msg  string
+	}{
+		{New("test"), CodeInternal, "test"},
+		{Wrap(sentinel, "failed to queue"), CodeBadRequest, "queue is full"},
+		{Wrap(&fs.PathError{Op: "stat", Path: "a", Err: fs.ErrNotExist}, "failed to stat"), CodeNotFound, fs.ErrNotExist.Error()},
+	}
+	for _, test := range tests {
+		code, err := Classify(test.err)
+		if code != test.code || err.Error() != test.msg {
+			t.Errorf("expected %s %q for %q, got %s %q", test.code, test.msg, test.err, code, err)
+		}
+	}
+
+	if !Is(Wrap(sentinel, "failed to queue"), sentinel) {
+		t.Error("expected coded sentinel to match itself")
+	}
+}
@@ -14,3 +14,8 @@ func New(text string) error {
 func Is(err, target error) bool {
 	return errors.Is(err, target)
 }
+
+// As redirects to the errors.As method.
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
@@ -60,3 +60,19 @@ func (km *KMutex) Do(key string, action func() (interface{}, error)) (interface{
 
 	return res, err
 }
+
+// TryDo acquires a lock if it isn't held already, runs the action and releases it.
+// The action is not run and false is returned if the lock is held.
+func (km *KMutex) TryDo(key string, action func() (interface{}, error)) (interface{}, bool, error) {
+	mu := km.Make(key).(*refCtMutex)
+	defer km.Release(key)
+
+	if !mu.TryLock() {
+		return nil, false, nil
+	}
+
+	res, err := action()
+
+	mu.Unlock()
+	return res, true, err
+}
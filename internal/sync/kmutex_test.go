@@ -87,3 +87,25 @@ func TestKMutexReferenceCounting(t *testing.T) {
 		t.Error("expected mutex to be deleted after releasing for the second time")
 	}
 }
+
+func TestKMutexTryDo(t *testing.T) {
+	var km KMutex
+
+	mu := km.Make(key)
+	mu.Lock()
+
+	if _, ok, _ := km.TryDo(key, func() (interface{}, error) { return nil, nil }); ok {
+		t.Error("expected the action not to run while the lock is held")
+	}
+
+	mu.Unlock()
+	km.Release(key)
+
+	res, ok, err := km.TryDo(key, func() (interface{}, error) { return key, nil })
+	if !ok || err != nil || res != key {
+		t.Errorf("expected the action to run, got %v, %t, %v", res, ok, err)
+	}
+	if _, ok := km.mx[key]; ok {
+		t.Error("key still found")
+	}
+}
@@ -0,0 +1,38 @@
+package sync
+
+import "sync"
+
+// Tracker counts running operations, like a sync.WaitGroup that refuses new operations once it's being waited for.
+type Tracker struct {
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// Enter marks the start of an operation, returns false if the tracker has been closed, in which case it mustn't start.
+// A started operation must be marked finished with Leave.
+func (t *Tracker) Enter() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return false
+	}
+
+	t.wg.Add(1)
+	return true
+}
+
+// Leave marks the end of an operation.
+func (t *Tracker) Leave() {
+	t.wg.Done()
+}
+
+// Close refuses new operations and waits for the running ones to finish.
+func (t *Tracker) Close() {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+
+	t.wg.Wait()
+}
@@ -0,0 +1,31 @@
+package sync
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	var tr Tracker
+	if !tr.Enter() {
+		t.Fatal("expected operation to start")
+	}
+
+	left := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(left)
+		tr.Leave()
+	}()
+
+	tr.Close()
+	select {
+	case <-left:
+	default:
+		t.Error("closed before the running operation finished")
+	}
+
+	if tr.Enter() {
+		t.Error("expected operation to be refused after closing")
+	}
+}
@@ -0,0 +1,68 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child", attribute.String("repo.id", "test"))
+	Fail(child, errors.New("test error"))
+	child.End()
+	Fail(parent, nil)
+	parent.End()
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name() != "child" || spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Errorf("expected child span of the parent, got %s", spans[0].Name())
+	}
+	if s := spans[0].Status(); s.Code != codes.Error || s.Description != "test error" {
+		t.Errorf("expected failed child span, got status %+v", s)
+	}
+	if s := spans[1].Status(); s.Code != codes.Unset {
+		t.Errorf("expected parent span not to fail, got status %+v", s)
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	received := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.URL.Path
+	}))
+	defer srv.Close()
+
+	tp, err := NewProvider(context.Background(), srv.URL, "katana-test", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, span := tp.Tracer(tracerName).Start(context.Background(), "test")
+	span.End()
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if path := <-received; path != "/v1/traces" {
+		t.Errorf("unexpected export path %s", path)
+	}
+
+	if _, err := NewProvider(context.Background(), "grpc://localhost:4317", "katana-test", 1); err == nil {
+		t.Error("expected unsupported endpoint scheme error")
+	}
+}
@@ -0,0 +1,62 @@
+package update
+
+import (
+	"github.com/katana-project/katana/internal/errors"
+	"go.uber.org/multierr"
+	"os"
+)
+
+// OldSuffix is the file name suffix of the previous executable, kept after an update.
+const OldSuffix = ".old"
+
+// Install replaces the executable at path with a new binary, keeping the previous one next to it (OldSuffix).
+// The new executable is checked by the check function, such as by starting it, the previous one is put back if it fails.
+func Install(path string, binary []byte, check func(path string) error) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to stat executable")
+	}
+
+	var (
+		tmp = path + ".new"
+		old = path + OldSuffix
+	)
+	if err := os.WriteFile(tmp, binary, fi.Mode().Perm()); err != nil {
+		_ = os.Remove(tmp)
+		return errors.Wrap(err, "failed to write new executable")
+	}
+	if err := os.Chmod(tmp, fi.Mode().Perm()); err != nil { // not masked by the umask
+		_ = os.Remove(tmp)
+		return errors.Wrap(err, "failed to change new executable mode")
+	}
+
+	if err := os.Remove(old); err != nil && !errors.Is(err, os.ErrNotExist) {
+		_ = os.Remove(tmp)
+		return errors.Wrap(err, "failed to remove previous executable")
+	}
+	if err := os.Rename(path, old); err != nil {
+		_ = os.Remove(tmp)
+		return errors.Wrap(err, "failed to move current executable")
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return multierr.Append(errors.Wrap(err, "failed to move new executable"), rollback(path, old))
+	}
+
+	if check != nil {
+		if err := check(path); err != nil {
+			return multierr.Append(errors.Wrap(err, "new executable failed to start, rolled back"), rollback(path, old))
+		}
+	}
+
+	return nil
+}
+
+// rollback puts the previous executable back in place.
+func rollback(path, old string) error {
+	if err := os.Rename(old, path); err != nil {
+		return errors.Wrapf(err, "failed to restore previous executable, it's kept at %s", old)
+	}
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package update
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultFeed is the URL of the project's latest release in the GitHub API.
+	DefaultFeed = "https://api.github.com/repos/katana-project/katana/releases/latest"
+	// ChecksumsAsset is the name of the release asset listing the SHA-256 checksums of the other assets, in the sha256sum format.
+	ChecksumsAsset = "checksums.txt"
+	// SignatureAsset is the name of the release asset with the Ed25519 signature of the checksums, raw or base64-encoded.
+	SignatureAsset = ChecksumsAsset + ".sig"
+
+	// maxAssetSize is the maximum size of a downloaded asset.
+	maxAssetSize = 512 << 20
+)
+
+// PublicKey is the base64-encoded Ed25519 public key verifying the signatures of release checksums, set at link time
+// (-ldflags "-X github.com/katana-project/katana/internal/update.PublicKey=..."), empty if the build can't verify them.
+var PublicKey = ""
+
+// ParsePublicKey decodes the public key of the build (PublicKey), returns nil if there's none.
+func ParsePublicKey() (ed25519.PublicKey, error) {
+	if PublicKey == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode public key")
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key has %d bytes, expected %d", len(key), ed25519.PublicKeySize)
+	}
+
+	return key, nil
+}
+
+// Release is a published release of the project.
+type Release struct {
+	// Version is the version tag of the release, such as "v1.2.0".
+	Version string
+	// Assets are the download URLs of the release's files, keyed by their name.
+	Assets map[string]string
+}
+
+// githubRelease is a release in the GitHub API.
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// Client is a client of a release feed.
+type Client struct {
+	feed, userAgent string
+	client          *http.Client
+}
+
+// NewClient creates a client of a release feed, a GitHub API URL of the latest release (DefaultFeed if empty).
+// Requests are identified by the current version, the HTTP client can be nil.
+func NewClient(feed, version string, client *http.Client) *Client {
+	if feed == "" {
+		feed = DefaultFeed
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Client{feed: feed, userAgent: "katana/" + version, client: client}
+}
+
+// Latest fetches the latest release.
+func (c *Client) Latest(ctx context.Context) (*Release, error) {
+	b, err := c.get(ctx, c.feed, 1<<20)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch release feed")
+	}
+
+	var gr githubRelease
+	if err := json.Unmarshal(b, &gr); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal release feed")
+	}
+	if gr.TagName == "" {
+		return nil, errors.New("release has no version tag")
+	}
+
+	r := &Release{Version: gr.TagName, Assets: make(map[string]string, len(gr.Assets))}
+	for _, a := range gr.Assets {
+		r.Assets[a.Name] = a.URL
+	}
+
+	return r, nil
+}
+
+// Download downloads an asset of a release.
+func (c *Client) Download(ctx context.Context, r *Release, name string) ([]byte, error) {
+	url, ok := r.Assets[name]
+	if !ok {
+		return nil, fmt.Errorf("release %s has no asset %s", r.Version, name)
+	}
+
+	b, err := c.get(ctx, url, maxAssetSize)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download %s", name)
+	}
+
+	return b, nil
+}
+
+func (c *Client) get(ctx context.Context, url string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json, application/octet-stream")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(res.Body, limit+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response")
+	}
+	if int64(len(b)) > limit {
+		return nil, fmt.Errorf("response is larger than %d bytes", limit)
+	}
+
+	return b, nil
+}
+
+// AssetName returns the name of the binary asset of a platform, such as "katana_linux_amd64" or "katana_windows_amd64.exe".
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("katana_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+
+	return name
+}
+
+// Verify checks that the SHA-256 checksum of an asset is listed in the checksums of its release.
+// The checksums are verified against their signature first, unless the public key is nil.
+func Verify(asset []byte, name string, checksums, signature []byte, key ed25519.PublicKey) error {
+	if key != nil {
+		sig := bytes.TrimSpace(signature)
+		if len(sig) != ed25519.SignatureSize { // base64-encoded
+			decoded, err := base64.StdEncoding.DecodeString(string(sig))
+			if err != nil {
+				return errors.Wrap(err, "failed to decode signature")
+			}
+
+			sig = decoded
+		}
+		if !ed25519.Verify(key, checksums, sig) {
+			return errors.New("checksum signature is invalid")
+		}
+	}
+
+	sum := sha256.Sum256(asset)
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name { // "*" marks binary mode
+			continue
+		}
+
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("checksum of %s doesn't match", name)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("checksum of %s is not listed", name)
+}
+
+// Newer checks whether a version is newer than the current one, both in the "vMAJOR.MINOR.PATCH" form,
+// optionally with a pre-release suffix ("-rc.1"), which is older than the release itself.
+// Versions that can't be parsed, such as of development builds, are never newer or older.
+func Newer(version, current string) bool {
+	v, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+	cur, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+
+	for i := 0; i < 3; i++ {
+		if v.parts[i] != cur.parts[i] {
+			return v.parts[i] > cur.parts[i]
+		}
+	}
+	if v.pre == "" || cur.pre == "" {
+		return v.pre == "" && cur.pre != ""
+	}
+
+	return v.pre > cur.pre
+}
+
+type semver struct {
+	parts [3]int
+	pre   string
+}
+
+func parseVersion(s string) (semver, bool) {
+	var v semver
+
+	s, ok := strings.CutPrefix(s, "v")
+	if !ok {
+		return v, false
+	}
+	s, _, _ = strings.Cut(s, "+") // build metadata
+	s, v.pre, _ = strings.Cut(s, "-")
+
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return v, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, false
+		}
+
+		v.parts[i] = n
+	}
+
+	return v, true
+}
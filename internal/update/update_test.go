@@ -0,0 +1,146 @@
+package update
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		version, current string
+		newer            bool
+	}{
+		{"v1.2.0", "v1.1.9", true},
+		{"v1.10.0", "v1.9.0", true},
+		{"v1.2.0", "v1.2.0", false},
+		{"v1.1.0", "v1.2.0", false},
+		{"v1.2.0", "v1.2.0-rc.1", true},
+		{"v1.2.0-rc.2", "v1.2.0-rc.1", true},
+		{"v1.2.0-rc.1", "v1.2.0", false},
+		{"v1.2.0", "dev", false},
+		{"latest", "v1.0.0", false},
+	}
+
+	for _, tt := range tests {
+		if newer := Newer(tt.version, tt.current); newer != tt.newer {
+			t.Errorf("Newer(%q, %q) = %t, expected %t", tt.version, tt.current, newer, tt.newer)
+		}
+	}
+}
+
+func checksums(assets map[string][]byte) []byte {
+	var b []byte
+	for name, data := range assets {
+		sum := sha256.Sum256(data)
+		b = append(b, fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), name)...)
+	}
+
+	return b
+}
+
+func TestVerify(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		binary = []byte("binary")
+		sums   = checksums(map[string][]byte{"katana_linux_amd64": binary})
+		sig    = ed25519.Sign(priv, sums)
+	)
+	if err := Verify(binary, "katana_linux_amd64", sums, sig, pub); err != nil {
+		t.Errorf("expected valid asset, got %v", err)
+	}
+	if err := Verify(binary, "katana_linux_amd64", sums, []byte(base64.StdEncoding.EncodeToString(sig)+"\n"), pub); err != nil {
+		t.Errorf("expected valid asset with a base64 signature, got %v", err)
+	}
+	if err := Verify([]byte("tampered"), "katana_linux_amd64", sums, sig, pub); err == nil {
+		t.Error("expected checksum mismatch")
+	}
+	if err := Verify(binary, "katana_darwin_arm64", sums, sig, pub); err == nil {
+		t.Error("expected unlisted asset to be refused")
+	}
+
+	forged := checksums(map[string][]byte{"katana_linux_amd64": []byte("tampered")})
+	if err := Verify([]byte("tampered"), "katana_linux_amd64", forged, sig, pub); err == nil {
+		t.Error("expected invalid signature")
+	}
+	if err := Verify([]byte("tampered"), "katana_linux_amd64", forged, nil, nil); err != nil {
+		t.Errorf("expected unsigned checksums to be accepted without a key, got %v", err)
+	}
+}
+
+func TestClient_Latest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "katana/v1.0.0" {
+			t.Errorf("unexpected user agent %q", r.Header.Get("User-Agent"))
+		}
+
+		switch r.URL.Path {
+		case "/latest":
+			_, _ = fmt.Fprintf(w, `{"tag_name": "v1.1.0", "assets": [{"name": "katana_linux_amd64", "browser_download_url": "http://%s/bin"}]}`, r.Host)
+		case "/bin":
+			_, _ = w.Write([]byte("binary"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL+"/latest", "v1.0.0", srv.Client())
+	r, err := c.Latest(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Version != "v1.1.0" {
+		t.Errorf("expected version v1.1.0, got %s", r.Version)
+	}
+
+	b, err := c.Download(context.Background(), r, "katana_linux_amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "binary" {
+		t.Errorf("unexpected asset content %q", b)
+	}
+	if _, err := c.Download(context.Background(), r, ChecksumsAsset); err == nil {
+		t.Error("expected missing asset error")
+	}
+}
+
+func TestInstall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "katana")
+	if err := os.WriteFile(path, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Install(path, []byte("broken"), func(string) error { return fmt.Errorf("exit status 1") }); err == nil {
+		t.Error("expected failed check error")
+	}
+	if b, _ := os.ReadFile(path); string(b) != "old" {
+		t.Errorf("expected rollback to the old executable, got %q", b)
+	}
+
+	if err := Install(path, []byte("new"), func(string) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "new" {
+		t.Errorf("expected new executable, got %q", b)
+	}
+	if b, _ := os.ReadFile(path + OldSuffix); string(b) != "old" {
+		t.Errorf("expected old executable to be kept, got %q", b)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0755 {
+		t.Errorf("expected executable mode to be kept, got %v", fi.Mode())
+	}
+}
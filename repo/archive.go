@@ -0,0 +1,15 @@
+package repo
+
+// ArchiveStatus is the cold storage status of media, archived media are kept in their repository without their file.
+type ArchiveStatus string
+
+const (
+	// ArchiveStatusNone is the status of media whose file is in its repository.
+	ArchiveStatusNone ArchiveStatus = ""
+	// ArchiveStatusArchiving is the status of media whose file is being moved to cold storage.
+	ArchiveStatusArchiving ArchiveStatus = "archiving"
+	// ArchiveStatusArchived is the status of media whose file is in cold storage.
+	ArchiveStatusArchived ArchiveStatus = "archived"
+	// ArchiveStatusRestoring is the status of media whose file is being moved back from cold storage.
+	ArchiveStatusRestoring ArchiveStatus = "restoring"
+)
@@ -0,0 +1,318 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"go.uber.org/zap"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// entry is media whose file is in cold storage or being moved there or back.
+type entry struct {
+	// Status is the archive status of the media, never repo.ArchiveStatusNone.
+	Status repo.ArchiveStatus `json:"status"`
+	// Key is the key of the file in cold storage (see Mover.Archive).
+	Key string `json:"key"`
+	// Archived is the time of the file being moved to cold storage.
+	Archived time.Time `json:"archived"`
+}
+
+// archiveRepo is a wrapping repo.MutableRepository with a repo.CapabilityArchive capability.
+// It must wrap the repository below the index, so that the media of archived files are kept in the index.
+type archiveRepo struct {
+	repo.MutableRepository
+
+	mover  Mover
+	path   string // state file, empty if it's kept in memory
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	entries map[string]*entry // media ID -> entry
+}
+
+// NewRepository creates a repository moving media files to cold storage with a Mover,
+// keeping the archive state in a file at path, or only in memory if it's empty.
+// Moves interrupted by a shutdown are resolved by whether the file is in the repository.
+func NewRepository(r repo.MutableRepository, mover Mover, path string, logger *zap.Logger) (repo.MutableRepository, error) {
+	ar := &archiveRepo{
+		MutableRepository: r,
+		mover:             mover,
+		path:              path,
+		logger:            logger,
+		entries:           make(map[string]*entry),
+	}
+	if err := ar.load(); err != nil {
+		return nil, err
+	}
+
+	return ar, nil
+}
+
+func (ar *archiveRepo) load() error {
+	if ar.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(ar.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return errors.Wrap(err, "failed to read archive state")
+	}
+	if err := json.Unmarshal(data, &ar.entries); err != nil {
+		return errors.Wrap(err, "failed to unmarshal archive state")
+	}
+
+	var changed bool
+	for id, e := range ar.entries {
+		if e.Status == repo.ArchiveStatusArchived {
+			continue
+		}
+
+		// interrupted, the file is either in the repository or in cold storage
+		path := ar.filePath(e)
+		_ = os.Remove(restorePath(path))
+		if _, err := os.Stat(path); err == nil {
+			delete(ar.entries, id)
+		} else {
+			e.Status = repo.ArchiveStatusArchived
+		}
+		changed = true
+	}
+	if changed {
+		return ar.save()
+	}
+
+	return nil
+}
+
+// save saves the archive state, mu must be held or the state not shared yet.
+func (ar *archiveRepo) save() error {
+	if ar.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(ar.entries)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal archive state")
+	}
+	if err := os.MkdirAll(filepath.Dir(ar.path), 0755); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	tmp := ar.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return errors.Wrap(err, "failed to write archive state")
+	}
+	if err := os.Rename(tmp, ar.path); err != nil {
+		return errors.Wrap(err, "failed to replace archive state")
+	}
+
+	return nil
+}
+
+// filePath returns the absolute path of an entry's file in the repository.
+func (ar *archiveRepo) filePath(e *entry) string {
+	return repo.AbsPath(ar.MutableRepository.Roots(), filepath.FromSlash(e.Key))
+}
+
+// restorePath returns the path a file is restored to before being moved into place, dot-prefixed to be ignored by watchers.
+func restorePath(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".restore")
+}
+
+// begin starts moving the file of media by storing its entry, if the media has the expected archive status,
+// ErrBusy is returned otherwise. The status of a restore is set on the entry, an archive uses a new one.
+func (ar *archiveRepo) begin(id string, e *entry, expected repo.ArchiveStatus) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	status := repo.ArchiveStatusNone
+	if cur, ok := ar.entries[id]; ok {
+		status = cur.Status
+	}
+	if status != expected {
+		return &ErrBusy{ID: id, Status: status}
+	}
+
+	if expected == repo.ArchiveStatusArchived {
+		e.Status = repo.ArchiveStatusRestoring
+	}
+	ar.entries[id] = e
+	return ar.save()
+}
+
+// setStatus sets the archive status of media and saves the state, ArchiveStatusNone removes its entry.
+func (ar *archiveRepo) setStatus(id string, e *entry, status repo.ArchiveStatus) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if status == repo.ArchiveStatusNone {
+		delete(ar.entries, id)
+	} else {
+		if status == repo.ArchiveStatusArchived && e.Archived.IsZero() {
+			e.Archived = time.Now()
+		}
+		e.Status = status
+		ar.entries[id] = e
+	}
+
+	return ar.save()
+}
+
+// archived returns the ID of archived media with a path, empty if it's not archived.
+func (ar *archiveRepo) archived(path string) string {
+	m := ar.MutableRepository.Find(path)
+	if m == nil {
+		return ""
+	}
+
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if _, ok := ar.entries[m.ID()]; !ok {
+		return ""
+	}
+
+	return m.ID()
+}
+
+func (ar *archiveRepo) Capabilities() repo.Capability {
+	return ar.MutableRepository.Capabilities() | repo.CapabilityArchive
+}
+
+func (ar *archiveRepo) ArchiveStatus(id string) repo.ArchiveStatus {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if e, ok := ar.entries[id]; ok {
+		return e.Status
+	}
+
+	return repo.ArchiveStatusNone
+}
+
+func (ar *archiveRepo) Archive(ctx context.Context, id string) error {
+	m := ar.MutableRepository.Get(id)
+	if m == nil {
+		return &repo.ErrMediaNotFound{ID: id, Repo: ar.MutableRepository.ID()}
+	}
+
+	relPath, err := repo.RelPath(ar.MutableRepository.Roots(), m.Path())
+	if err != nil {
+		return err
+	}
+
+	e := &entry{Status: repo.ArchiveStatusArchiving, Key: filepath.ToSlash(relPath)}
+	if err := ar.begin(id, e, repo.ArchiveStatusNone); err != nil {
+		var busy *ErrBusy
+		if errors.As(err, &busy) && busy.Status == repo.ArchiveStatusArchived {
+			return nil
+		}
+
+		return err
+	}
+	if err := ar.mover.Archive(ctx, m.Path(), e.Key); err != nil {
+		if err0 := ar.setStatus(id, e, repo.ArchiveStatusNone); err0 != nil && ar.logger != nil {
+			ar.logger.Error("failed to save archive state", zap.String("repo", ar.MutableRepository.ID()), zap.Error(err0))
+		}
+
+		return errors.Wrap(err, "failed to move media file to cold storage")
+	}
+
+	if ar.logger != nil {
+		ar.logger.Info("moved media to cold storage", zap.String("repo", ar.MutableRepository.ID()), zap.String("id", id), zap.String("key", e.Key))
+	}
+
+	return ar.setStatus(id, e, repo.ArchiveStatusArchived)
+}
+
+func (ar *archiveRepo) Restore(ctx context.Context, id string) error {
+	ar.mu.Lock()
+	e, ok := ar.entries[id]
+	ar.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	if err := ar.begin(id, e, repo.ArchiveStatusArchived); err != nil {
+		var busy *ErrBusy
+		if errors.As(err, &busy) && busy.Status == repo.ArchiveStatusNone {
+			return nil // restored in the meantime
+		}
+
+		return err
+	}
+
+	var (
+		path = ar.filePath(e)
+		tmp  = restorePath(path)
+	)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		err = errors.Wrap(err, "failed to make directories")
+	} else if err = ar.mover.Restore(ctx, e.Key, tmp); err != nil {
+		err = errors.Wrap(err, "failed to move media file from cold storage")
+	} else if err = os.Rename(tmp, path); err != nil {
+		err = errors.Wrap(err, "failed to move restored media file")
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		if err0 := ar.setStatus(id, e, repo.ArchiveStatusArchived); err0 != nil && ar.logger != nil {
+			ar.logger.Error("failed to save archive state", zap.String("repo", ar.MutableRepository.ID()), zap.Error(err0))
+		}
+
+		return err
+	}
+
+	if ar.logger != nil {
+		ar.logger.Info("restored media from cold storage", zap.String("repo", ar.MutableRepository.ID()), zap.String("id", id), zap.String("key", e.Key))
+	}
+
+	return ar.setStatus(id, e, repo.ArchiveStatusNone)
+}
+
+func (ar *archiveRepo) AddPath(path string) error {
+	if ar.archived(path) != "" {
+		return nil // picked up by a watcher during a restore
+	}
+
+	return ar.MutableRepository.AddPath(path)
+}
+
+func (ar *archiveRepo) RemovePath(path string) error {
+	if ar.archived(path) != "" {
+		return nil // the file was moved to cold storage, the media is kept
+	}
+
+	return ar.MutableRepository.RemovePath(path)
+}
+
+func (ar *archiveRepo) Remove(m media.Media) error {
+	if err := ar.MutableRepository.Remove(m); err != nil {
+		return err
+	}
+
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	e, ok := ar.entries[m.ID()]
+	if !ok {
+		return nil
+	}
+
+	delete(ar.entries, m.ID())
+	if ar.logger != nil { // the file is left in cold storage
+		ar.logger.Warn("removed archived media", zap.String("repo", ar.MutableRepository.ID()), zap.String("id", m.ID()), zap.String("key", e.Key))
+	}
+
+	return ar.save()
+}
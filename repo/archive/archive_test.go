@@ -0,0 +1,114 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestRepo(t *testing.T, statePath string) (repo.MutableRepository, string) {
+	root := t.TempDir()
+	r, err := repo.NewRepository("test", "Test", root, meta.NewLiteralSource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(root, "Movies", "movie.mkv")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, bytes.Repeat([]byte("movie"), 1024), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(media.NewMedia("movie", path, meta.NewMetadata(meta.TypeMovie, "Movie", "", "", time.Time{}, 0, nil), media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+
+	mover, err := NewDirMover(filepath.Join(t.TempDir(), "cold"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ar, err := NewRepository(r, mover, statePath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ar, path
+}
+
+func TestArchiveRepo_ArchiveRestore(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "archive.json")
+	ar, path := newTestRepo(t, statePath)
+	if !ar.Capabilities().Has(repo.CapabilityArchive) {
+		t.Error("expected archive capability")
+	}
+
+	if err := ar.Archive(context.Background(), "movie"); err != nil {
+		t.Fatal(err)
+	}
+	if status := ar.ArchiveStatus("movie"); status != repo.ArchiveStatusArchived {
+		t.Errorf("expected archived status, got %q", status)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected file to be moved to cold storage, got %v", err)
+	}
+
+	// a watcher noticing the file gone
+	if err := ar.RemovePath(path); err != nil {
+		t.Fatal(err)
+	}
+	if ar.Get("movie") == nil {
+		t.Fatal("expected archived media to be kept")
+	}
+
+	var entries map[string]*entry
+	if data, err := os.ReadFile(statePath); err != nil {
+		t.Fatal(err)
+	} else if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := entries["movie"]; !ok || e.Key != "Movies/movie.mkv" || e.Status != repo.ArchiveStatusArchived {
+		t.Errorf("expected archive state to be saved, got %v", entries)
+	}
+
+	if err := ar.Restore(context.Background(), "movie"); err != nil {
+		t.Fatal(err)
+	}
+	if status := ar.ArchiveStatus("movie"); status != repo.ArchiveStatusNone {
+		t.Errorf("expected no archive status, got %q", status)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, bytes.Repeat([]byte("movie"), 1024)) {
+		t.Error("expected restored file to be intact")
+	}
+}
+
+func TestArchiveRepo_Interrupted(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "archive.json")
+	data, err := json.Marshal(map[string]*entry{
+		"movie": {Status: repo.ArchiveStatusArchiving, Key: "Movies/movie.mkv"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(statePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ar, _ := newTestRepo(t, statePath)
+	if status := ar.ArchiveStatus("movie"); status != repo.ArchiveStatusNone {
+		t.Errorf("expected interrupted archive of a present file to be dropped, got %q", status)
+	}
+}
@@ -0,0 +1,10 @@
+package archive
+
+import (
+	"github.com/katana-project/katana/backup"
+	"github.com/katana-project/katana/config"
+)
+
+func init() {
+	backup.RegisterRepo("archive.json", (*config.Repo).ArchivePath)
+}
@@ -0,0 +1,25 @@
+package archive
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+)
+
+// ErrBusy is an error about media whose file is being moved to cold storage or back already.
+type ErrBusy struct {
+	// ID is the media ID.
+	ID string
+	// Status is the archive status of the media.
+	Status repo.ArchiveStatus
+}
+
+// Error returns the string representation of the error.
+func (eb *ErrBusy) Error() string {
+	return fmt.Sprintf("media %s is %s already", eb.ID, eb.Status)
+}
+
+// Code returns the error code (errors.CodeNotReady).
+func (eb *ErrBusy) Code() errors.Code {
+	return errors.CodeNotReady
+}
@@ -0,0 +1,170 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// Mover moves media files to cold storage and back.
+type Mover interface {
+	// Archive moves the file at an absolute path to cold storage under a key,
+	// a slash-separated path relative to the repository's roots (repo.RelPath).
+	Archive(ctx context.Context, path, key string) error
+	// Restore moves the file under a key from cold storage to an absolute path, its directory exists.
+	Restore(ctx context.Context, key, path string) error
+}
+
+// dirMover is a Mover keeping files in a directory, such as on a slower drive or a network share.
+type dirMover struct {
+	root string
+}
+
+// NewDirMover creates a Mover keeping files in a directory, mirroring their paths relative to the repository's roots.
+func NewDirMover(root string) (Mover, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make path absolute")
+	}
+
+	return &dirMover{root: root}, nil
+}
+
+func (dm *dirMover) Archive(ctx context.Context, path, key string) error {
+	dst := filepath.Join(dm.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	return moveFile(ctx, path, dst)
+}
+
+func (dm *dirMover) Restore(ctx context.Context, key, path string) error {
+	return moveFile(ctx, filepath.Join(dm.root, filepath.FromSlash(key)), path)
+}
+
+// moveFile moves a file, copying it if the destination is on another filesystem.
+func moveFile(ctx context.Context, src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	// the partial copy is dot-prefixed, ignored by scans and watchers
+	tmp := filepath.Join(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp")
+	if err := copyFile(ctx, src, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return errors.Wrap(err, "failed to replace file")
+	}
+	if err := os.Remove(src); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return errors.Wrap(err, "failed to remove copied file")
+	}
+
+	return nil
+}
+
+func copyFile(ctx context.Context, src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.Wrap(err, "failed to open file")
+	}
+	defer in.Close()
+
+	fi, err := in.Stat()
+	if err != nil {
+		return errors.Wrap(err, "failed to stat file")
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return errors.Wrap(err, "failed to create file")
+	}
+	if _, err := io.Copy(out, &ctxReader{ctx: ctx, r: in}); err != nil {
+		_ = out.Close()
+		return errors.Wrap(err, "failed to copy file")
+	}
+	if err := out.Close(); err != nil {
+		return errors.Wrap(err, "failed to close file")
+	}
+
+	return os.Chtimes(dst, fi.ModTime(), fi.ModTime())
+}
+
+// ctxReader is an io.Reader failing once its context is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *ctxReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return cr.r.Read(p)
+}
+
+// commandMover is a Mover running commands, such as of rclone.
+type commandMover struct {
+	archive, restore []string
+}
+
+// NewCommandMover creates a Mover running commands with arguments, archive moves a file to cold storage and restore moves it back.
+// The "{path}" and "{key}" placeholders in arguments are replaced with the absolute path of the local file
+// and its key (see Mover.Archive), such as in ["rclone", "moveto", "{path}", "remote:katana/{key}"].
+func NewCommandMover(archive, restore []string) (Mover, error) {
+	if len(archive) == 0 || len(restore) == 0 {
+		return nil, errors.New("both archive and restore commands are required")
+	}
+
+	return &commandMover{archive: archive, restore: restore}, nil
+}
+
+func (cm *commandMover) Archive(ctx context.Context, path, key string) error {
+	return runCommand(ctx, cm.archive, path, key)
+}
+
+func (cm *commandMover) Restore(ctx context.Context, key, path string) error {
+	return runCommand(ctx, cm.restore, path, key)
+}
+
+func runCommand(ctx context.Context, command []string, path, key string) error {
+	r := strings.NewReplacer("{path}", path, "{key}", key)
+
+	args := make([]string, len(command)-1)
+	for i, arg := range command[1:] {
+		args[i] = r.Replace(arg)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil { // killed
+			return ctxErr
+		}
+
+		msg := strings.TrimSpace(stderr.String())
+		if i := strings.LastIndexByte(msg, '\n'); i != -1 { // the last line is usually the cause
+			msg = msg[i+1:]
+		}
+		if msg == "" {
+			return errors.Wrapf(err, "%s failed", command[0])
+		}
+
+		return errors.Wrapf(err, "%s failed (%s)", command[0], msg)
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package checksum
+
+import (
+	"github.com/katana-project/katana/backup"
+	"github.com/katana-project/katana/config"
+)
+
+func init() {
+	backup.Register("checksums.json", func(cfg *config.Config) string {
+		if cfg.Checksums == nil {
+			return ""
+		}
+
+		return cfg.Checksums.Path
+	})
+}
@@ -0,0 +1,481 @@
+package checksum
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/zeebo/xxh3"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Algorithm is the checksum algorithm ID, stored checksums are prefixed with it ("xxh3:<hex>").
+// Checksums of another algorithm are recomputed rather than compared.
+const Algorithm = "xxh3"
+
+// saveInterval is the minimum period between saves of the catalog during a long update or verification.
+const saveInterval = time.Minute
+
+// entry is a JSON-serializable checksum of a media file.
+type entry struct {
+	Path     string    `json:"path"`
+	Checksum string    `json:"checksum"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mod_time"`
+	Verified time.Time `json:"verified"` // the last time the checksum was computed or found matching
+}
+
+// unchanged checks whether a file looks the same as when its checksum was computed, i.e. it wasn't modified.
+func (e *entry) unchanged(path string, fi fs.FileInfo) bool {
+	return e.Path == path && e.Size == fi.Size() && e.ModTime.Equal(fi.ModTime())
+}
+
+// Mismatch is a media file whose contents changed without its size and modification time changing, i.e. silent corruption.
+type Mismatch struct {
+	// MediaID is the ID of the corrupted media.
+	MediaID string `json:"media_id"`
+	// Path is the path of the media file.
+	Path string `json:"path"`
+	// Expected is the checksum stored in the catalog.
+	Expected string `json:"expected"`
+	// Actual is the checksum of the current file contents.
+	Actual string `json:"actual"`
+}
+
+// Report is the result of verifying the checksums of a repository.
+type Report struct {
+	// Time is the time of the verification finishing.
+	Time time.Time `json:"time"`
+	// Checked is the number of verified media files.
+	Checked int `json:"checked"`
+	// Corrupted are the corrupted media files.
+	Corrupted []*Mismatch `json:"corrupted"`
+}
+
+// catalog is the JSON-serializable catalog file.
+type catalog struct {
+	Repos   map[string]map[string]*entry `json:"repos"`   // repository ID -> media ID -> entry
+	Reports map[string]*Report           `json:"reports"` // repository ID -> last verification
+}
+
+// Catalog is a catalog of full-file checksums of media, for detecting silent corruption (bitrot) of archived files.
+// Checksums are computed by Update and compared by Verify, both take a while, they're meant to run in background jobs.
+type Catalog struct {
+	path   string
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	catalog  catalog
+	dirty    bool
+	lastSave time.Time
+
+	running sync.Map // repository ID -> struct{}, runs of a repository are exclusive
+}
+
+// NewCatalog creates a checksum catalog persisted to a JSON file, after updates, verifications and on Close.
+func NewCatalog(path string, logger *zap.Logger) (*Catalog, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Catalog{
+		path:   absPath,
+		logger: logger,
+		catalog: catalog{
+			Repos:   make(map[string]map[string]*entry),
+			Reports: make(map[string]*Report),
+		},
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *Catalog) load() error {
+	b, err := os.ReadFile(c.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return errors.Wrap(err, "failed to read checksum catalog")
+	}
+
+	if err := json.Unmarshal(b, &c.catalog); err != nil {
+		return errors.Wrap(err, "failed to unmarshal checksum catalog")
+	}
+	if c.catalog.Repos == nil {
+		c.catalog.Repos = make(map[string]map[string]*entry)
+	}
+	if c.catalog.Reports == nil {
+		c.catalog.Reports = make(map[string]*Report)
+	}
+
+	return nil
+}
+
+// save saves the catalog if it changed since the last save, mu must be held.
+func (c *Catalog) save() error {
+	if !c.dirty {
+		return nil
+	}
+
+	b, err := json.Marshal(&c.catalog)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal checksum catalog")
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return errors.Wrap(err, "failed to write checksum catalog")
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return errors.Wrap(err, "failed to replace checksum catalog")
+	}
+
+	c.dirty = false
+	c.lastSave = time.Now()
+	return nil
+}
+
+// set stores an entry and saves the catalog if it hasn't been saved for a while, so that long runs don't lose their work.
+func (c *Catalog) set(repoId, mediaId string, e *entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, ok := c.catalog.Repos[repoId]
+	if !ok {
+		entries = make(map[string]*entry)
+		c.catalog.Repos[repoId] = entries
+	}
+
+	entries[mediaId] = e
+	c.dirty = true
+	if time.Since(c.lastSave) >= saveInterval {
+		if err := c.save(); err != nil && c.logger != nil {
+			c.logger.Error("failed to save checksum catalog", zap.String("path", c.path), zap.Error(err))
+		}
+	}
+}
+
+// get returns a copy of an entry, nil if there's none.
+func (c *Catalog) get(repoId, mediaId string) *entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.catalog.Repos[repoId][mediaId]
+	if !ok {
+		return nil
+	}
+
+	cp := *e
+	return &cp
+}
+
+// prune forgets the entries of media no longer in a repository.
+func (c *Catalog) prune(repoId string, items []media.Media) {
+	ids := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		ids[item.ID()] = struct{}{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id := range c.catalog.Repos[repoId] {
+		if _, ok := ids[id]; !ok {
+			delete(c.catalog.Repos[repoId], id)
+			c.dirty = true
+		}
+	}
+}
+
+// RenameMedia moves the checksums of a repository's media to new IDs and saves the catalog, such as after migrating media IDs.
+// ids is a mapping of old media IDs to new ones, checksums of media not in it are kept as-is.
+func (c *Catalog) RenameMedia(repoId string, ids map[string]string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make(map[string]*entry, len(c.catalog.Repos[repoId]))
+	for id, e := range c.catalog.Repos[repoId] {
+		if id0, ok := ids[id]; ok {
+			id = id0
+			c.dirty = true
+		}
+
+		entries[id] = e
+	}
+	c.catalog.Repos[repoId] = entries
+
+	return c.save()
+}
+
+// lock marks a run on a repository, returns false if one is running already.
+func (c *Catalog) lock(repoId string) bool {
+	_, running := c.running.LoadOrStore(repoId, struct{}{})
+	return !running
+}
+
+func (c *Catalog) unlock(repoId string) error {
+	c.running.Delete(repoId)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.save()
+}
+
+// Update computes the checksums of new and modified media of a repository and forgets those of removed media.
+// Remote media (media.Opener) are skipped, progress is reported in bytes read (repo.ReportProgress).
+// ErrRunning is returned if the repository is being updated or verified already.
+func (c *Catalog) Update(ctx context.Context, r repo.Repository) (err error) {
+	repoId := r.ID()
+	if !c.lock(repoId) {
+		return &ErrRunning{Repo: repoId}
+	}
+	defer func() {
+		err = multierr.Append(err, c.unlock(repoId))
+	}()
+
+	items := r.Items()
+	c.prune(repoId, items)
+
+	var (
+		pending  []pendingFile
+		progress repo.Progress
+	)
+	for _, item := range items {
+		f, ok := c.stat(repoId, item)
+		if !ok || (f.entry != nil && f.entry.unchanged(f.path, f.info) && validChecksum(f.entry.Checksum)) {
+			continue // remote, missing or up-to-date
+		}
+
+		pending = append(pending, f)
+		progress.Total += f.info.Size()
+	}
+
+	for _, f := range pending {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		checksum, err := compute(ctx, f.path, &progress)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return err
+			}
+			if c.logger != nil { // removed in the meantime, unreadable, ...
+				c.logger.Warn("failed to compute media checksum", zap.String("repo", repoId), zap.String("path", f.path), zap.Error(err))
+			}
+			continue
+		}
+
+		c.set(repoId, f.id, &entry{
+			Path:     f.path,
+			Checksum: checksum,
+			Size:     f.info.Size(),
+			ModTime:  f.info.ModTime(),
+			Verified: time.Now(),
+		})
+	}
+
+	return nil
+}
+
+// Verify recomputes the checksums of a repository's media, reporting files whose contents changed without
+// their size and modification time changing. Checksums of modified and new media are updated like with Update.
+// ErrRunning is returned if the repository is being updated or verified already,
+// ErrCorrupted if corrupted files were found, the report is returned along with it.
+func (c *Catalog) Verify(ctx context.Context, r repo.Repository) (_ *Report, err error) {
+	repoId := r.ID()
+	if !c.lock(repoId) {
+		return nil, &ErrRunning{Repo: repoId}
+	}
+	defer func() {
+		err = multierr.Append(err, c.unlock(repoId))
+	}()
+
+	items := r.Items()
+	c.prune(repoId, items)
+
+	var (
+		pending  []pendingFile
+		progress repo.Progress
+		report   = &Report{Corrupted: make([]*Mismatch, 0)}
+	)
+	for _, item := range items {
+		if f, ok := c.stat(repoId, item); ok {
+			pending = append(pending, f)
+			progress.Total += f.info.Size()
+		}
+	}
+
+	for _, f := range pending {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		checksum, err := compute(ctx, f.path, &progress)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil, err
+			}
+			if c.logger != nil {
+				c.logger.Warn("failed to compute media checksum", zap.String("repo", repoId), zap.String("path", f.path), zap.Error(err))
+			}
+			continue
+		}
+
+		report.Checked++
+		if f.entry != nil && f.entry.unchanged(f.path, f.info) && validChecksum(f.entry.Checksum) && f.entry.Checksum != checksum {
+			report.Corrupted = append(report.Corrupted, &Mismatch{
+				MediaID:  f.id,
+				Path:     f.path,
+				Expected: f.entry.Checksum,
+				Actual:   checksum,
+			})
+			if c.logger != nil {
+				c.logger.Error(
+					"media file corrupted, checksum changed without modification",
+					zap.String("repo", repoId),
+					zap.String("path", f.path),
+					zap.String("expected", f.entry.Checksum),
+					zap.String("actual", checksum),
+				)
+			}
+			continue // keep the original checksum, the file stays reported until it's restored or replaced
+		}
+
+		c.set(repoId, f.id, &entry{
+			Path:     f.path,
+			Checksum: checksum,
+			Size:     f.info.Size(),
+			ModTime:  f.info.ModTime(),
+			Verified: time.Now(),
+		})
+	}
+
+	slices.SortFunc(report.Corrupted, func(a, b *Mismatch) int {
+		if a.Path < b.Path {
+			return -1
+		} else if a.Path > b.Path {
+			return 1
+		}
+
+		return 0
+	})
+	report.Time = time.Now()
+
+	c.mu.Lock()
+	c.catalog.Reports[repoId] = report
+	c.dirty = true
+	c.mu.Unlock()
+
+	if len(report.Corrupted) > 0 {
+		paths := make([]string, len(report.Corrupted))
+		for i, m := range report.Corrupted {
+			paths[i] = m.Path
+		}
+
+		return report, &ErrCorrupted{Repo: repoId, Paths: paths}
+	}
+
+	return report, nil
+}
+
+// LastReport returns the report of the last verification of a repository, nil if it hasn't been verified yet.
+func (c *Catalog) LastReport(repoId string) *Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.catalog.Reports[repoId]
+}
+
+// Close saves the catalog a final time.
+func (c *Catalog) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.save()
+}
+
+// pendingFile is a media file to compute the checksum of.
+type pendingFile struct {
+	id, path string
+	info     fs.FileInfo
+	entry    *entry // the stored checksum, nil if there's none
+}
+
+// stat looks up the file of local media, returns false if it's remote or missing.
+func (c *Catalog) stat(repoId string, item media.Media) (pendingFile, bool) {
+	path := item.Path()
+	if media.URLScheme(path) != "" {
+		return pendingFile{}, false
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil || !fi.Mode().IsRegular() {
+		return pendingFile{}, false
+	}
+
+	return pendingFile{id: item.ID(), path: path, info: fi, entry: c.get(repoId, item.ID())}, true
+}
+
+// validChecksum checks whether a checksum was computed with the current algorithm.
+func validChecksum(checksum string) bool {
+	return len(checksum) > len(Algorithm) && checksum[:len(Algorithm)+1] == Algorithm+":"
+}
+
+// compute computes the checksum of a file, adding the bytes read to the progress.
+func compute(ctx context.Context, path string, progress *repo.Progress) (_ string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open file")
+	}
+	defer func() {
+		if err0 := f.Close(); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to close file"))
+		}
+	}()
+
+	var (
+		h   = xxh3.New() // 64-bit, fast enough to keep up with the disk
+		buf = make([]byte, 1024*1024)
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		n, err := f.Read(buf)
+		if n > 0 {
+			_, _ = h.Write(buf[:n])
+			progress.Processed += int64(n)
+			repo.ReportProgress(ctx, *progress)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read file")
+		}
+	}
+
+	return Algorithm + ":" + hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,89 @@
+package checksum
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCatalog_Verify(t *testing.T) {
+	root := t.TempDir()
+	r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := make(map[string]string)
+	for id, name := range map[string]string{"intact": "Intact.mkv", "rotten": "Rotten.mkv", "edited": "Edited.mkv"} {
+		path := filepath.Join(root, name)
+		if err := os.WriteFile(path, []byte("original contents of "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Add(media.NewMedia(id, path, nil, media.FormatMKV)); err != nil {
+			t.Fatal(err)
+		}
+
+		paths[id] = path
+	}
+
+	catalogPath := filepath.Join(t.TempDir(), "checksums.json")
+	c, err := NewCatalog(catalogPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Update(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// corrupt a file in place, keeping its size and modification time
+	fi, err := os.Stat(paths["rotten"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(paths["rotten"], []byte("originaL contents of Rotten.mkv"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(paths["rotten"], fi.ModTime(), fi.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+
+	// modify a file legitimately
+	if err := os.WriteFile(paths["edited"], []byte("new contents"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(paths["edited"], later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = NewCatalog(catalogPath, nil) // reloaded from the file
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	report, err := c.Verify(context.Background(), r)
+
+	var errCorrupted *ErrCorrupted
+	if !errors.As(err, &errCorrupted) {
+		t.Fatalf("expected corruption error, got %v", err)
+	}
+	if report.Checked != 3 {
+		t.Errorf("expected 3 checked files, got %d", report.Checked)
+	}
+	if len(report.Corrupted) != 1 || report.Corrupted[0].MediaID != "rotten" {
+		t.Fatalf("expected only rotten media corrupted, got %+v", report.Corrupted)
+	}
+	if c.LastReport("test") != report {
+		t.Error("expected verification report to be kept")
+	}
+}
@@ -0,0 +1,36 @@
+package checksum
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"strings"
+)
+
+// ErrRunning is an error about a repository's checksums being updated or verified already.
+type ErrRunning struct {
+	// Repo is the repository ID.
+	Repo string
+}
+
+// Error returns the string representation of the error.
+func (er *ErrRunning) Error() string {
+	return fmt.Sprintf("checksums of repository %s are being computed already", er.Repo)
+}
+
+// Code returns the error code (errors.CodeNotReady).
+func (er *ErrRunning) Code() errors.Code {
+	return errors.CodeNotReady
+}
+
+// ErrCorrupted is an error about media files found corrupted by a verification.
+type ErrCorrupted struct {
+	// Repo is the repository ID.
+	Repo string
+	// Paths are the paths of the corrupted files.
+	Paths []string
+}
+
+// Error returns the string representation of the error.
+func (ec *ErrCorrupted) Error() string {
+	return fmt.Sprintf("%d corrupted media files in repository %s: %s", len(ec.Paths), ec.Repo, strings.Join(ec.Paths, ", "))
+}
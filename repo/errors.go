@@ -1,10 +1,13 @@
 package repo
 
 import (
-	"errors"
 	"fmt"
+	"github.com/katana-project/katana/internal/errors"
 )
 
+// ErrClosed is an error about an operation started after the repository has been closed.
+var ErrClosed = errors.WithCode(errors.New("repository is closed"), errors.CodeNotReady)
+
 // ErrInvalidID is an error about an invalid ID, either of a repository or media.
 type ErrInvalidID struct {
 	// ID is the offending id.
@@ -18,6 +21,29 @@ func (ein *ErrInvalidID) Error() string {
 	return fmt.Sprintf("invalid ID %s, expected %s", ein.ID, ein.Expected)
 }
 
+// Code returns the error code (errors.CodeBadRequest).
+func (ein *ErrInvalidID) Code() errors.Code {
+	return errors.CodeBadRequest
+}
+
+// ErrInvalidTag is an error about an invalid media tag.
+type ErrInvalidTag struct {
+	// Tag is the offending tag.
+	Tag string
+	// Expected is the expected tag form, a regular expression.
+	Expected string
+}
+
+// Error returns the string representation of the error.
+func (eit *ErrInvalidTag) Error() string {
+	return fmt.Sprintf("invalid tag %s, expected %s", eit.Tag, eit.Expected)
+}
+
+// Code returns the error code (errors.CodeBadRequest).
+func (eit *ErrInvalidTag) Code() errors.Code {
+	return errors.CodeBadRequest
+}
+
 // ErrInvalidMediaPath is an error about an unexpected media path,
 // expected a path within the repository's root directory (could not relativize the media path).
 type ErrInvalidMediaPath struct {
@@ -32,6 +58,11 @@ func (eimp *ErrInvalidMediaPath) Error() string {
 	return fmt.Sprintf("invalid media path %s, outside of repository root %s", eimp.Path, eimp.Root)
 }
 
+// Code returns the error code (errors.CodeBadRequest).
+func (eimp *ErrInvalidMediaPath) Code() errors.Code {
+	return errors.CodeBadRequest
+}
+
 // ErrInvalidMediaType is an error about an unexpected media MIME type.
 type ErrInvalidMediaType struct {
 	// Path is the offending media path.
@@ -45,6 +76,11 @@ func (eimt *ErrInvalidMediaType) Error() string {
 	return fmt.Sprintf("invalid media MIME type %s, path %s", eimt.Type, eimt.Path)
 }
 
+// Code returns the error code (errors.CodeUnknownFormat).
+func (eimt *ErrInvalidMediaType) Code() errors.Code {
+	return errors.CodeUnknownFormat
+}
+
 // ErrDuplicateID is an error about a duplicate media ID in a repository.
 type ErrDuplicateID struct {
 	// ID is the offending ID.
@@ -58,6 +94,11 @@ func (edi *ErrDuplicateID) Error() string {
 	return fmt.Sprintf("duplicate media ID %s in repository %s", edi.ID, edi.Repo)
 }
 
+// Code returns the error code (errors.CodeBadRequest).
+func (edi *ErrDuplicateID) Code() errors.Code {
+	return errors.CodeBadRequest
+}
+
 // ErrDuplicatePath is an error about a duplicate media path in a repository.
 type ErrDuplicatePath struct {
 	// Path is the offending path.
@@ -71,6 +112,74 @@ func (edp *ErrDuplicatePath) Error() string {
 	return fmt.Sprintf("duplicate media path %s in repository %s", edp.Path, edp.Repo)
 }
 
+// Code returns the error code (errors.CodeBadRequest).
+func (edp *ErrDuplicatePath) Code() errors.Code {
+	return errors.CodeBadRequest
+}
+
+// ErrMediaNotFound is an error about media missing from a repository.
+type ErrMediaNotFound struct {
+	// ID is the offending ID.
+	ID string
+	// Repo is the repository name.
+	Repo string
+}
+
+// Error returns the string representation of the error.
+func (emnf *ErrMediaNotFound) Error() string {
+	return fmt.Sprintf("media ID %s not found in repository %s", emnf.ID, emnf.Repo)
+}
+
+// Code returns the error code (errors.CodeNotFound).
+func (emnf *ErrMediaNotFound) Code() errors.Code {
+	return errors.CodeNotFound
+}
+
+// ErrLocked is an error about an automatic change of media locked against them (see MutableRepository.SetLocked).
+type ErrLocked struct {
+	// ID is the offending ID.
+	ID string
+	// Repo is the repository name.
+	Repo string
+}
+
+// Error returns the string representation of the error.
+func (el *ErrLocked) Error() string {
+	return fmt.Sprintf("media ID %s is locked in repository %s", el.ID, el.Repo)
+}
+
+// Code returns the error code (errors.CodeBadRequest).
+func (el *ErrLocked) Code() errors.Code {
+	return errors.CodeBadRequest
+}
+
+// ErrQuotaExceeded is an error about new media not fitting into a repository's storage quota.
+type ErrQuotaExceeded struct {
+	// Path is the offending media path.
+	Path string
+	// Repo is the repository name.
+	Repo string
+	// Size is the size of the offending media in bytes.
+	Size int64
+	// Usage is the current storage usage of the repository in bytes.
+	Usage int64
+	// Quota is the storage quota of the repository in bytes.
+	Quota int64
+}
+
+// Error returns the string representation of the error.
+func (eqe *ErrQuotaExceeded) Error() string {
+	return fmt.Sprintf(
+		"storage quota of repository %s exceeded by media %s, size %d bytes, usage %d/%d bytes",
+		eqe.Repo, eqe.Path, eqe.Size, eqe.Usage, eqe.Quota,
+	)
+}
+
+// Code returns the error code (errors.CodeQuotaExceeded).
+func (eqe *ErrQuotaExceeded) Code() errors.Code {
+	return errors.CodeQuotaExceeded
+}
+
 // ErrUnsupportedOperation is an error about an operation unsupported in a repository.
 type ErrUnsupportedOperation struct {
 	// Operation is the unsupported operation.
@@ -84,6 +193,11 @@ func (euo *ErrUnsupportedOperation) Error() string {
 	return fmt.Sprintf("unsupported operation %s for repository %s", euo.Operation, euo.Repo)
 }
 
+// Code returns the error code (errors.CodeMissingCapability).
+func (euo *ErrUnsupportedOperation) Code() errors.Code {
+	return errors.CodeMissingCapability
+}
+
 // Unwrap returns the parent error (errors.ErrUnsupported).
 func (euo *ErrUnsupportedOperation) Unwrap() error {
 	return errors.ErrUnsupported
@@ -102,7 +216,47 @@ func (euf *ErrUnsupportedFormat) Error() string {
 	return fmt.Sprintf("unsupported format %s for %s", euf.Format, euf.Operation)
 }
 
+// Code returns the error code (errors.CodeUnknownFormat).
+func (euf *ErrUnsupportedFormat) Code() errors.Code {
+	return errors.CodeUnknownFormat
+}
+
 // Unwrap returns the parent error (errors.ErrUnsupported).
 func (euf *ErrUnsupportedFormat) Unwrap() error {
 	return errors.ErrUnsupported
 }
+
+// ErrNotReady is an error about an operation result that is still being made by another operation.
+// It's only returned to callers that don't wait for the result (WithNoWait).
+type ErrNotReady struct {
+	// Path is the path of the pending result.
+	Path string
+}
+
+// Error returns the string representation of the error.
+func (enr *ErrNotReady) Error() string {
+	return fmt.Sprintf("%s is still being made", enr.Path)
+}
+
+// Code returns the error code (errors.CodeNotReady).
+func (enr *ErrNotReady) Code() errors.Code {
+	return errors.CodeNotReady
+}
+
+// ErrUnavailable is an error about a repository's root directory being unavailable, such as an unmounted network share.
+type ErrUnavailable struct {
+	// Path is the path of the root directory.
+	Path string
+	// Reason is the reason of it being unavailable.
+	Reason string
+}
+
+// Error returns the string representation of the error.
+func (eu *ErrUnavailable) Error() string {
+	return fmt.Sprintf("repository root %s is unavailable, %s", eu.Path, eu.Reason)
+}
+
+// Code returns the error code (errors.CodeUnavailable).
+func (eu *ErrUnavailable) Code() errors.Code {
+	return errors.CodeUnavailable
+}
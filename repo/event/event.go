@@ -0,0 +1,153 @@
+package event
+
+import (
+	"sync"
+	"time"
+)
+
+// subscriptionSize is the number of events buffered for a subscriber.
+const subscriptionSize = 64
+
+// Type is a type of event.
+type Type string
+
+const (
+	// TypeMediaAdded is the type of an event about media added to a repository.
+	TypeMediaAdded Type = "media_added"
+	// TypeMediaRemoved is the type of an event about media removed from a repository.
+	TypeMediaRemoved Type = "media_removed"
+	// TypeMediaUpdated is the type of an event about media being updated in a repository, such as its metadata being re-matched.
+	TypeMediaUpdated Type = "media_updated"
+	// TypeMediaMoved is the type of an event about media being relinked to the file it was moved or renamed to, keeping its ID.
+	TypeMediaMoved Type = "media_moved"
+	// TypeScanCompleted is the type of an event about a repository scan finishing, media may have been added by it.
+	TypeScanCompleted Type = "scan_completed"
+	// TypeRepoDegraded is the type of an event about a repository's files becoming unavailable, such as an unmounted network share.
+	TypeRepoDegraded Type = "repo_degraded"
+	// TypeRepoRecovered is the type of an event about a degraded repository's files becoming available again.
+	TypeRepoRecovered Type = "repo_recovered"
+	// TypeQuotaWarning is the type of an event about a repository's storage usage nearing or exceeding its quota,
+	// or media being refused over it.
+	TypeQuotaWarning Type = "quota_warning"
+	// TypeJobUpdated is the type of an event about a job changing its state.
+	TypeJobUpdated Type = "job_updated"
+	// TypeNewEpisode is the type of an event about a new episode of a series followed by a user, addressed to the user.
+	TypeNewEpisode Type = "new_episode"
+)
+
+// Event is a notification about a change in a repository or a job.
+type Event struct {
+	// Type is the type of the event.
+	Type Type
+	// RepoID is the ID of the concerned repository.
+	RepoID string
+	// MediaID is the ID of the concerned media, empty for events about a whole repository.
+	MediaID string
+	// JobID is the ID of the concerned job, empty for events not about jobs.
+	JobID string
+	// JobType is the type of the concerned job, empty for events not about jobs.
+	JobType string
+	// JobState is the new state of the concerned job, empty for events not about jobs.
+	JobState string
+	// User is the user the event is addressed to, empty for events for all users.
+	User string
+	// Time is the time of the event.
+	Time time.Time
+}
+
+// Subscription is a subscription to events published to a Bus.
+type Subscription struct {
+	// C is the channel of published events, it's closed when the subscription ends.
+	C <-chan Event
+
+	bus *Bus
+	c   chan Event
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.bus.unsubscribe(s)
+}
+
+// Bus is a publish-subscribe hub of events, safe for concurrent use.
+// Publishing never blocks, subscribers that don't keep up are dropped and should subscribe again.
+type Bus struct {
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// NewBus creates an event bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscribe subscribes to events published from now on, the subscription is ended immediately if the bus is closed.
+func (b *Bus) Subscribe() *Subscription {
+	c := make(chan Event, subscriptionSize)
+	s := &Subscription{C: c, bus: b, c: c}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(c)
+	} else {
+		b.subs[s] = struct{}{}
+	}
+
+	return s
+}
+
+// Publish sends an event to all subscribers, the event time is set if zero.
+// It's a no-op if the bus is nil or closed.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subs {
+		select {
+		case s.c <- e:
+		default: // subscriber is lagging behind, drop it instead of losing events silently
+			delete(b.subs, s)
+			close(s.c)
+		}
+	}
+}
+
+func (b *Bus) unsubscribe(s *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[s]; ok {
+		delete(b.subs, s)
+		close(s.c)
+	}
+}
+
+// Closed checks whether the bus is closed, i.e. whether an ended subscription shouldn't be renewed.
+func (b *Bus) Closed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.closed
+}
+
+// Close ends all subscriptions, events published afterwards are discarded.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for s := range b.subs {
+		delete(b.subs, s)
+		close(s.c)
+	}
+}
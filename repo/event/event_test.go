@@ -0,0 +1,39 @@
+package event
+
+import (
+	"testing"
+)
+
+func TestBus(t *testing.T) {
+	b := NewBus()
+
+	sub := b.Subscribe()
+	b.Publish(Event{Type: TypeMediaAdded, RepoID: "repo", MediaID: "media"})
+
+	e := <-sub.C
+	if e.Type != TypeMediaAdded || e.MediaID != "media" || e.Time.IsZero() {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	for i := 0; i <= subscriptionSize; i++ { // one more than buffered
+		b.Publish(Event{Type: TypeMediaUpdated, RepoID: "repo", MediaID: "media"})
+	}
+
+	var n int
+	for range sub.C {
+		n++
+	}
+	if n != subscriptionSize {
+		t.Errorf("expected %d buffered events before the lagging subscription ended, got %d", subscriptionSize, n)
+	}
+
+	sub = b.Subscribe()
+	b.Close()
+	if _, ok := <-sub.C; ok {
+		t.Error("expected subscription to end on close")
+	}
+	if _, ok := <-b.Subscribe().C; ok {
+		t.Error("expected subscription to a closed bus to end immediately")
+	}
+	sub.Close() // no-op
+}
@@ -0,0 +1,29 @@
+package event
+
+import (
+	"github.com/katana-project/katana/repo"
+)
+
+// eventTypes are the bus event types of repository event types.
+var eventTypes = map[repo.EventType]Type{
+	repo.EventMediaAdded:    TypeMediaAdded,
+	repo.EventMediaRemoved:  TypeMediaRemoved,
+	repo.EventMediaUpdated:  TypeMediaUpdated,
+	repo.EventMediaMoved:    TypeMediaMoved,
+	repo.EventScanCompleted: TypeScanCompleted,
+	repo.EventRepoDegraded:  TypeRepoDegraded,
+	repo.EventRepoRecovered: TypeRepoRecovered,
+	repo.EventQuotaWarning:  TypeQuotaWarning,
+}
+
+// Forward publishes the changes of a repository (repo.Events) to an event bus, until the returned function is called.
+func Forward(r repo.Repository, bus *Bus) (stop func()) {
+	return r.Events().Subscribe(func(e repo.Event) {
+		e0 := Event{Type: eventTypes[e.Type], RepoID: e.RepoID}
+		if e.Media != nil {
+			e0.MediaID = e.Media.ID()
+		}
+
+		bus.Publish(e0)
+	})
+}
@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"github.com/katana-project/katana/repo/media"
+	"sync"
+)
+
+// EventType is a type of repository change.
+type EventType string
+
+const (
+	// EventMediaAdded is the type of an event about media added to a repository, including media discovered by a scan.
+	EventMediaAdded EventType = "media_added"
+	// EventMediaRemoved is the type of an event about media removed from a repository.
+	EventMediaRemoved EventType = "media_removed"
+	// EventMediaUpdated is the type of an event about media replaced in a repository, such as its metadata being re-matched
+	// or its sidecar subtitles changing.
+	EventMediaUpdated EventType = "media_updated"
+	// EventMediaMoved is the type of an event about media relinked to the file it was moved or renamed to, keeping its ID.
+	EventMediaMoved EventType = "media_moved"
+	// EventScanCompleted is the type of an event about a repository scan finishing successfully.
+	EventScanCompleted EventType = "scan_completed"
+	// EventRepoDegraded is the type of an event about a repository's files becoming unavailable (Health).
+	EventRepoDegraded EventType = "repo_degraded"
+	// EventRepoRecovered is the type of an event about a degraded repository's files becoming available again.
+	EventRepoRecovered EventType = "repo_recovered"
+	// EventQuotaWarning is the type of an event about a repository's storage usage nearing or exceeding its quota,
+	// or media being refused over it.
+	EventQuotaWarning EventType = "quota_warning"
+)
+
+// Event is a notification about a repository change.
+type Event struct {
+	// Type is the type of the change.
+	Type EventType
+	// RepoID is the ID of the changed repository.
+	RepoID string
+	// Media is the concerned media, the removed media for EventMediaRemoved, nil for events about the whole repository.
+	Media media.Media
+	// OldPath is the absolute path of the media file before it was moved, only set for EventMediaMoved.
+	OldPath string
+}
+
+// Observer is a receiver of repository events.
+type Observer func(e Event)
+
+// observer is a registered Observer, compared by identity on unsubscribing.
+type observer struct {
+	fn Observer
+}
+
+// Events is a registry of observers of a repository's changes, shared by the repository and all wrappers of it.
+// Observers are called synchronously in the order of subscription, after the change is visible and outside of repository locks,
+// they should return quickly and may call back into the repository.
+type Events struct {
+	mu        sync.RWMutex
+	observers []*observer
+}
+
+// Subscribe registers an observer, the returned function unregisters it.
+func (e *Events) Subscribe(fn Observer) (unsubscribe func()) {
+	o := &observer{fn: fn}
+
+	e.mu.Lock()
+	e.observers = append(e.observers, o)
+	e.mu.Unlock()
+
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		for i, o0 := range e.observers {
+			if o0 == o {
+				e.observers = append(e.observers[:i:i], e.observers[i+1:]...) // copy, Publish may be iterating the old slice
+				return
+			}
+		}
+	}
+}
+
+// Publish calls all observers with the events, in order.
+func (e *Events) Publish(events ...Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	e.mu.RLock()
+	observers := e.observers
+	e.mu.RUnlock()
+
+	for _, ev := range events {
+		for _, o := range observers {
+			o.fn(ev)
+		}
+	}
+}
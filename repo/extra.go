@@ -0,0 +1,175 @@
+package repo
+
+import (
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media"
+	"golang.org/x/exp/slices"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// seasonDirPattern matches the names of season directories of a series, whose extras are in the parent (series) directory.
+var seasonDirPattern = regexp.MustCompile(`(?i)^(season[\s._-]*\d+|s\d+|specials)$`)
+
+// findExtras finds the theme music and trailers of media in directories, nearest first, directory entries are read by readDir.
+// Theme music and "trailer" files belong to all media in their directory, "-trailer" suffixed files to the media
+// with the matching name stem and files in a "trailers" subdirectory to all media in its parent directory.
+// Example: "Movie-trailer.mp4" belongs to "Movie.mkv", "theme.mp3" in "Show" belongs to "Show/Season 01/Episode.mkv".
+func findExtras(mediaPath string, dirs []string, readDir func(dir string) ([]fs.DirEntry, error), foldCase bool) ([]*media.Extra, error) {
+	fold := func(s string) string {
+		if foldCase {
+			return strings.ToLower(s)
+		}
+
+		return s
+	}
+
+	var (
+		mediaStem = fold(stem(filepath.Base(mediaPath)))
+		extras    []*media.Extra
+	)
+	add := func(path string, kind media.ExtraKind, id string) {
+		if !slices.ContainsFunc(extras, func(e *media.Extra) bool { return e.ID == id }) { // nearer extras shadow farther ones
+			extras = append(extras, &media.Extra{ID: id, Kind: kind, Path: path, MIME: media.ExtraMIME(filepath.Ext(path))})
+		}
+	}
+	for i, dir := range dirs {
+		entries, err := readDir(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if strings.HasPrefix(name, ".") {
+				continue
+			}
+
+			if entry.IsDir() {
+				if !strings.EqualFold(name, "trailers") {
+					continue
+				}
+
+				trailers, err := readDir(filepath.Join(dir, name))
+				if err != nil {
+					return nil, err
+				}
+				for _, trailer := range trailers {
+					path := filepath.Join(dir, name, trailer.Name())
+					if !trailer.IsDir() && !strings.HasPrefix(trailer.Name(), ".") && media.ExtraKindOf(path) == media.ExtraKindTrailer {
+						add(path, media.ExtraKindTrailer, media.SanitizeID("trailers-"+trailer.Name()))
+					}
+				}
+				continue
+			}
+
+			var (
+				path = filepath.Join(dir, name)
+				kind = media.ExtraKindOf(path)
+			)
+			if kind == "" {
+				continue
+			}
+			if nameStem := fold(stem(name)); strings.HasSuffix(nameStem, "-trailer") && (i > 0 || nameStem != mediaStem+"-trailer") {
+				continue // another media's trailer
+			}
+
+			add(path, kind, media.SanitizeID(name))
+		}
+	}
+
+	return extras, nil
+}
+
+// extraDirs returns the directories to look for extras of media in, its own and the series directory of an episode in a season directory.
+func (mr *mutableRepo) extraDirs(mediaPath string) []string {
+	dir := filepath.Dir(mediaPath)
+	if seasonDirPattern.MatchString(filepath.Base(dir)) && !slices.Contains(mr.roots, dir) {
+		return []string{dir, filepath.Dir(dir)}
+	}
+
+	return []string{dir}
+}
+
+// discoverExtras finds the theme music and trailers of media, mu must be held.
+func (mr *mutableRepo) discoverExtras(items []media.Media) error {
+	entries := make(map[string][]fs.DirEntry) // directory -> entries
+	readDir := func(dir string) ([]fs.DirEntry, error) {
+		dirEntries, ok := entries[dir]
+		if !ok {
+			var err error
+			if dirEntries, err = os.ReadDir(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+
+			entries[dir] = dirEntries
+		}
+
+		return dirEntries, nil
+	}
+
+	for _, item := range items {
+		extras, err := findExtras(item.Path(), mr.extraDirs(item.Path()), readDir, mr.foldCase)
+		if err != nil {
+			return err
+		}
+
+		if len(extras) > 0 {
+			mr.extras[item.ID()] = extras
+		} else {
+			delete(mr.extras, item.ID())
+		}
+	}
+
+	return nil
+}
+
+func (mr *mutableRepo) Extras(id string) ([]*media.Extra, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	if _, ok := mr.itemsById[id]; !ok {
+		return nil, nil
+	}
+
+	return append([]*media.Extra{}, mr.extras[id]...), nil
+}
+
+// updateExtras rediscovers the extras of media that an added or removed extra file may belong to.
+func (mr *mutableRepo) updateExtras(path string) error {
+	if _, err := mr.rel(path); err != nil {
+		return &ErrInvalidMediaPath{
+			Path: path,
+			Root: mr.path,
+		}
+	}
+
+	dir := filepath.Dir(path)
+	if strings.EqualFold(filepath.Base(dir), "trailers") {
+		dir = filepath.Dir(dir)
+	}
+
+	var events []Event
+	defer func() { mr.events.Publish(events...) }() // after unlocking
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var items []media.Media
+	for _, item := range mr.itemsById {
+		if dirs := mr.extraDirs(item.Path()); slices.ContainsFunc(dirs, func(d string) bool { return mr.pathKey(d) == mr.pathKey(dir) }) {
+			items = append(items, item)
+		}
+	}
+	if err := mr.discoverExtras(items); err != nil {
+		return errors.Wrap(err, "failed to discover extras")
+	}
+
+	for _, item := range items {
+		events = append(events, Event{Type: EventMediaUpdated, RepoID: mr.id, Media: item})
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package repo
+
+import (
+	"github.com/katana-project/katana/repo/media"
+	"golang.org/x/exp/slices"
+)
+
+// Features are the media processing components (FFmpeg) available to repositories, detected at startup.
+type Features struct {
+	// Muxers and Demuxers are the names of the available container formats for writing and reading.
+	Muxers, Demuxers []string
+	// Encoders and Decoders are the names of the available codec implementations.
+	Encoders, Decoders []string
+	// HWAccels are the hardware acceleration methods of the available codec implementations, such as "nvenc" or "vaapi".
+	HWAccels []string
+	// Protocols are the URL schemes of media files readable without copying them locally, such as "http" or "https".
+	Protocols []string
+	// TranscodeOptions are the transcode profile settings honored in transcoding, a video codec can always be set.
+	TranscodeOptions []media.TranscodeOption
+	// Fragmenting is whether remuxed files of formats indexed after their samples (the MP4 family) can be written fragmented,
+	// so that they're readable while being written (see WithPartial).
+	Fragmenting bool
+	// Storyboards is whether thumbnails of video can be taken for storyboards (see Repository.Storyboard).
+	Storyboards bool
+	// Previews is whether preview clips of video can be made (see Repository.Preview).
+	Previews bool
+}
+
+// UnsupportedOption returns the first option set by a transcode profile which isn't honored, false if all are.
+func (f *Features) UnsupportedOption(profile *media.TranscodeProfile) (media.TranscodeOption, bool) {
+	for _, opt := range profile.Options() {
+		if !slices.Contains(f.TranscodeOptions, opt) {
+			return opt, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"github.com/katana-project/katana/internal/errors"
+	"path"
+	"strings"
+)
+
+// Filter selects the files handled in a repository by glob patterns, matched case-sensitively against
+// slash-separated paths relative to the repository root.
+// Patterns without a slash match names at any depth, others are anchored to the root (a leading slash is optional).
+// "*" matches any characters within a path element, "**" any number of path elements, the rest of the syntax is that of path.Match.
+// Example: "*.nfo", "**/sample/*", "/Downloads"
+type Filter struct {
+	ignore, include []string
+}
+
+// NewFilter creates a Filter excluding files and directories matching an ignore pattern and, if there are any include patterns,
+// files not matching one of them. Directories are always walked, unless ignored, ignore patterns take precedence.
+func NewFilter(ignore, include []string) (*Filter, error) {
+	f := &Filter{}
+	for _, patterns := range []struct {
+		src []string
+		dst *[]string
+	}{{ignore, &f.ignore}, {include, &f.include}} {
+		for _, pattern := range patterns.src {
+			if pattern == "" {
+				continue
+			}
+			if !strings.Contains(pattern, "/") {
+				pattern = "**/" + pattern
+			}
+
+			pattern = strings.TrimPrefix(pattern, "/")
+			for _, elem := range strings.Split(pattern, "/") {
+				if _, err := path.Match(elem, ""); err != nil {
+					return nil, errors.Wrapf(err, "invalid pattern %s", pattern)
+				}
+			}
+
+			*patterns.dst = append(*patterns.dst, pattern)
+		}
+	}
+
+	return f, nil
+}
+
+// Match checks whether a file or directory at a slash-separated path relative to the repository root passes the filter.
+// A nil Filter passes everything.
+func (f *Filter) Match(relPath string, dir bool) bool {
+	if f == nil {
+		return true
+	}
+
+	elems := strings.Split(relPath, "/")
+	for _, pattern := range f.ignore {
+		if matchGlob(strings.Split(pattern, "/"), elems) {
+			return false
+		}
+	}
+	if dir || len(f.include) == 0 {
+		return true
+	}
+	for _, pattern := range f.include {
+		if matchGlob(strings.Split(pattern, "/"), elems) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchGlob matches path elements against the elements of a glob pattern.
+func matchGlob(pattern, elems []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(elems); i++ {
+				if matchGlob(pattern[1:], elems[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+		if len(elems) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], elems[0]); !ok {
+			return false
+		}
+
+		pattern, elems = pattern[1:], elems[1:]
+	}
+
+	return len(elems) == 0
+}
@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Fingerprint is a deterministic digest of a repository's contents, equal for repositories with the same media,
+// such as a mirror in sync with its origin. Media paths are left out, so repositories laid out differently can be compared.
+type Fingerprint struct {
+	// Hash is the hex-encoded SHA-256 digest of the sorted media IDs and their item digests.
+	Hash string
+	// Count is the number of media.
+	Count int
+	// Items are the digests of the media by their IDs, for finding the media that differ.
+	Items map[string]string
+}
+
+// FingerprintItem makes the digest of media, a hex-encoded SHA-256 digest of its file size, format, metadata and tags.
+// The file is not read, the size and the tags are the ones recorded by the repository (see Repository.FileStat, Repository.Tags).
+func FingerprintItem(m media.Media, size int64, tags []string) (string, error) {
+	meta0, err := json.Marshal(media.NewMedia(m.ID(), "", m.Meta(), m.Format())) // metadata versions of the media
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal media")
+	}
+
+	h := sha256.New()
+	_ = binary.Write(h, binary.BigEndian, size)
+	h.Write(meta0)
+	for _, tag := range tags {
+		h.Write([]byte{0})
+		h.Write([]byte(tag))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// NewFingerprint combines the item digests of media, keyed by their IDs, into a fingerprint.
+func NewFingerprint(items map[string]string) *Fingerprint {
+	ids := maps.Keys(items)
+	slices.Sort(ids)
+
+	h := sha256.New()
+	for _, id := range ids {
+		h.Write([]byte(id))
+		h.Write([]byte{0})
+		h.Write([]byte(items[id]))
+		h.Write([]byte{'\n'})
+	}
+
+	return &Fingerprint{Hash: hex.EncodeToString(h.Sum(nil)), Count: len(ids), Items: items}
+}
+
+// MakeFingerprint makes the fingerprint of a repository's current contents, no media files are read.
+func MakeFingerprint(r Repository) (*Fingerprint, error) {
+	var (
+		items   = r.Items()
+		digests = make(map[string]string, len(items))
+	)
+	for _, item := range items {
+		digest, err := FingerprintItem(item, r.FileStat(item.ID()).Size, r.Tags(item.ID()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fingerprint media %s", item.ID())
+		}
+
+		digests[item.ID()] = digest
+	}
+
+	return NewFingerprint(digests), nil
+}
@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"github.com/katana-project/katana/internal/errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Health is the availability of a repository's files.
+type Health struct {
+	// Degraded is whether the repository root is unavailable, such as an unmounted network share.
+	// Media are kept meanwhile, but their files can't be read.
+	Degraded bool
+	// Reason is the reason of the repository being degraded, empty if it isn't.
+	Reason string
+	// Since is the time of the last availability change, zero if it never changed.
+	Since time.Time
+}
+
+// CheckRoot checks whether a repository root directory is available, returns an *ErrUnavailable if it isn't.
+// An unmounted volume leaves its mount point empty, so an empty directory is unavailable if files are expected in it.
+func CheckRoot(path string, expectFiles bool) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		reason := err.Error()
+		if errors.Is(err, fs.ErrNotExist) {
+			reason = "it doesn't exist"
+		}
+
+		return &ErrUnavailable{Path: path, Reason: reason}
+	}
+	if !fi.IsDir() {
+		return &ErrUnavailable{Path: path, Reason: "it's not a directory"}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return &ErrUnavailable{Path: path, Reason: err.Error()}
+	}
+	defer f.Close()
+
+	names, err := f.Readdirnames(1)
+	if err != nil && !errors.Is(err, io.EOF) { // stale network file handles and such
+		return &ErrUnavailable{Path: path, Reason: err.Error()}
+	}
+	if expectFiles && len(names) == 0 {
+		return &ErrUnavailable{Path: path, Reason: "it's empty, its volume is likely unmounted"}
+	}
+
+	return nil
+}
+
+// CheckRoots checks whether all root directories of a repository are available (CheckRoot), returns an *ErrUnavailable of the first one that isn't.
+// Files are expected in the roots containing media of the repository.
+func CheckRoots(r Repository) error {
+	var (
+		roots    = r.Roots()
+		hasMedia = make([]bool, len(roots))
+	)
+	for _, m := range r.Items() {
+		for i, root := range roots {
+			if relPath, err := filepath.Rel(root, m.Path()); err == nil && isLocal(relPath) {
+				hasMedia[i] = true
+				break
+			}
+		}
+	}
+
+	for i, root := range roots {
+		if err := CheckRoot(root, hasMedia[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
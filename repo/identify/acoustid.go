@@ -0,0 +1,133 @@
+package identify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// DefaultAcoustIDURL is the URL of the AcoustID lookup API.
+const DefaultAcoustIDURL = "https://api.acoustid.org/v2/lookup"
+
+// chromaprint is the output of fpcalc.
+type chromaprint struct {
+	Duration    float64 `json:"duration"`
+	Fingerprint string  `json:"fingerprint"`
+}
+
+// fingerprint makes the Chromaprint fingerprint of an audio file with the fpcalc binary.
+func (i *Identifier) fingerprint(ctx context.Context, path string) (*chromaprint, error) {
+	var stderr strings.Builder
+
+	cmd := exec.CommandContext(ctx, i.fpcalc, "-json", path)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.Wrapf(err, "failed to run fpcalc: %s", msg)
+		}
+
+		return nil, errors.Wrap(err, "failed to run fpcalc")
+	}
+
+	var cp chromaprint
+	if err := json.Unmarshal(out, &cp); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal fpcalc output")
+	}
+	if cp.Fingerprint == "" {
+		return nil, errors.New("fpcalc made no fingerprint")
+	}
+
+	return &cp, nil
+}
+
+// acoustIDResponse is a response of the AcoustID lookup API.
+type acoustIDResponse struct {
+	Status string `json:"status"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Results []struct {
+		Score      float64 `json:"score"`
+		Recordings []struct {
+			ID      string `json:"id"`
+			Title   string `json:"title"`
+			Artists []struct {
+				Name string `json:"name"`
+			} `json:"artists"`
+			ReleaseGroups []struct {
+				Title string `json:"title"`
+			} `json:"releasegroups"`
+		} `json:"recordings"`
+	} `json:"results"`
+}
+
+// lookup looks up the recordings of a fingerprint in AcoustID, scored by how well the fingerprint matched.
+func (i *Identifier) lookup(ctx context.Context, cp *chromaprint) ([]*Suggestion, error) {
+	form := url.Values{
+		"client":      {i.acoustIDKey},
+		"duration":    {strconv.Itoa(int(math.Round(cp.Duration)))},
+		"fingerprint": {cp.Fingerprint},
+		"meta":        {"recordings releasegroups"},
+		"format":      {"json"},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.acoustIDURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to look up fingerprint")
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response")
+	}
+
+	var res acoustIDResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal response (status %d)", resp.StatusCode)
+	}
+	if res.Status != "ok" {
+		msg := resp.Status
+		if res.Error != nil {
+			msg = res.Error.Message
+		}
+
+		return nil, fmt.Errorf("fingerprint lookup failed: %s", msg)
+	}
+
+	var suggestions []*Suggestion
+	for _, r := range res.Results {
+		for _, rec := range r.Recordings {
+			if rec.Title == "" { // recordings without metadata
+				continue
+			}
+
+			s := &Suggestion{Source: SourceAcoustID, Score: r.Score, Title: rec.Title, RecordingID: rec.ID}
+			for _, a := range rec.Artists {
+				s.Artists = append(s.Artists, a.Name)
+			}
+			if len(rec.ReleaseGroups) > 0 {
+				s.Album = rec.ReleaseGroups[0].Title
+			}
+
+			suggestions = append(suggestions, s)
+		}
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,16 @@
+package identify
+
+import (
+	"github.com/katana-project/katana/backup"
+	"github.com/katana-project/katana/config"
+)
+
+func init() {
+	backup.Register("identify.json", func(cfg *config.Config) string {
+		if cfg.Identify == nil {
+			return ""
+		}
+
+		return cfg.Identify.Path
+	})
+}
@@ -0,0 +1,190 @@
+package identify
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"image"
+	"io/fs"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// hashWidth and hashHeight are the frame size of difference hashes, 8 differences in 8 rows make 64 bits.
+	hashWidth, hashHeight = 9, 8
+	// hashFrames is the number of frames hashed per video.
+	hashFrames = 8
+)
+
+// FrameReader reads grayscale video frames spread evenly over a media file, such as a mux.Backend.
+type FrameReader interface {
+	Frames(ctx context.Context, path string, count, width, height int) ([]*image.Gray, error)
+}
+
+// frameHash makes the difference hash (dHash) of a 9x8 grayscale frame, a bit per pixel brighter than its right neighbor.
+// Re-encoded or rescaled copies of a frame hash the same or differ in a few bits.
+func frameHash(frame *image.Gray) uint64 {
+	var h uint64
+	for y := 0; y < hashHeight; y++ {
+		for x := 0; x < hashWidth-1; x++ {
+			h <<= 1
+			if frame.GrayAt(x, y).Y > frame.GrayAt(x+1, y).Y {
+				h |= 1
+			}
+		}
+	}
+
+	return h
+}
+
+// similarity scores how alike the frames of two videos are between 0 and 1, by the mean distance of each frame to its closest match.
+// Frames don't need to be aligned, so that videos with a different intro or cut still match.
+func similarity(a, b []uint64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	var total int
+	for _, ha := range a {
+		best := 64
+		for _, hb := range b {
+			if d := bits.OnesCount64(ha ^ hb); d < best {
+				best = d
+			}
+		}
+
+		total += best
+	}
+
+	return 1 - float64(total)/float64(len(a)*64)
+}
+
+// hashEntry is the frame hashes of a media file.
+type hashEntry struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Hashes  []uint64  `json:"hashes"`
+}
+
+// matches checks whether the entry was hashed from the file of the stat, i.e. the file wasn't modified since.
+func (he *hashEntry) matches(stat repo.FileStat) bool {
+	return he.Size == stat.Size && he.ModTime.Equal(stat.ModTime)
+}
+
+// hashStore is a persisted store of frame hashes, keyed by repository and media ID ("<repo>/<media>").
+type hashStore struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]*hashEntry
+	dirty   bool
+}
+
+func newHashStore(path string) (*hashStore, error) {
+	hs := &hashStore{path: path, entries: make(map[string]*hashEntry)}
+	if path == "" {
+		return hs, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return hs, nil
+		}
+
+		return nil, errors.Wrap(err, "failed to read frame hashes")
+	}
+	if err := json.Unmarshal(b, &hs.entries); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal frame hashes")
+	}
+
+	return hs, nil
+}
+
+func hashKey(repoId, mediaId string) string {
+	return repoId + "/" + mediaId
+}
+
+// get returns the hashes of media, if they were made from the file of the stat.
+func (hs *hashStore) get(key string, stat repo.FileStat) ([]uint64, bool) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	he, ok := hs.entries[key]
+	if !ok || !he.matches(stat) {
+		return nil, false
+	}
+
+	return he.Hashes, true
+}
+
+// all returns the hashes of all media by their key, regardless of whether their files changed since.
+func (hs *hashStore) all() map[string][]uint64 {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	res := make(map[string][]uint64, len(hs.entries))
+	for key, he := range hs.entries {
+		res[key] = he.Hashes
+	}
+
+	return res
+}
+
+func (hs *hashStore) put(key string, stat repo.FileStat, hashes []uint64) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	hs.entries[key] = &hashEntry{Size: stat.Size, ModTime: stat.ModTime, Hashes: hashes}
+	hs.dirty = true
+}
+
+// prune removes the hashes of a repository's media that aren't among the IDs anymore.
+func (hs *hashStore) prune(repoId string, ids map[string]struct{}) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	prefix := repoId + "/"
+	for key := range hs.entries {
+		if len(key) > len(prefix) && key[:len(prefix)] == prefix {
+			if _, ok := ids[key[len(prefix):]]; !ok {
+				delete(hs.entries, key)
+				hs.dirty = true
+			}
+		}
+	}
+}
+
+// flush saves the store if it changed since the last save.
+func (hs *hashStore) flush() error {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	if hs.path == "" || !hs.dirty {
+		return nil
+	}
+
+	b, err := json.Marshal(hs.entries)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal frame hashes")
+	}
+	if err := os.MkdirAll(filepath.Dir(hs.path), 0755); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	tmp := hs.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return errors.Wrap(err, "failed to write frame hashes")
+	}
+	if err := os.Rename(tmp, hs.path); err != nil {
+		return errors.Wrap(err, "failed to replace frame hashes")
+	}
+
+	hs.dirty = false
+	return nil
+}
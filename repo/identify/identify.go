@@ -0,0 +1,269 @@
+package identify
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+	"net/http"
+)
+
+const (
+	// minSimilarity is the frame similarity (see similarity) above which videos are presumed to be the same.
+	minSimilarity = 0.85
+	// maxSuggestions is the maximum number of suggestions of media.
+	maxSuggestions = 5
+)
+
+// Source is the method of making a suggestion.
+type Source string
+
+const (
+	// SourceAcoustID is a suggestion of a recording matched by its Chromaprint fingerprint in AcoustID.
+	SourceAcoustID Source = "acoustid"
+	// SourceFrames is a suggestion of identified media with perceptually similar video frames.
+	SourceFrames Source = "frames"
+)
+
+// Suggestion is a likely match of media whose file name resolved to no or low-confidence metadata.
+type Suggestion struct {
+	// Source is the method of making the suggestion.
+	Source Source
+	// Score is the likelihood of the suggestion being right, between 0 and 1.
+	Score float64
+	// Title is the title of the match.
+	Title string
+	// Artists are the artist names of a recording, AcoustID only.
+	Artists []string
+	// Album is the album title of a recording, AcoustID only.
+	Album string
+	// RecordingID is the MusicBrainz recording ID of a recording, AcoustID only.
+	RecordingID string
+	// RepoID and MediaID identify the media with similar frames, whose metadata can be copied, frames only.
+	RepoID, MediaID string
+}
+
+// Options are the options of an Identifier.
+type Options struct {
+	// FpcalcPath is the path or name (looked up in PATH) of the Chromaprint fpcalc binary, defaults to "fpcalc".
+	FpcalcPath string
+	// AcoustIDKey is the AcoustID application API key, audio files aren't fingerprinted if it's empty.
+	AcoustIDKey string
+	// AcoustIDURL is the URL of the AcoustID lookup API, defaults to DefaultAcoustIDURL.
+	AcoustIDURL string
+	// Frames reads the video frames of media files, video files aren't hashed if it's nil.
+	Frames FrameReader
+	// Path is the path of the file persisting frame hashes, they're only kept in memory if it's empty.
+	Path string
+	// Client is the HTTP client of AcoustID requests, defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Identifier suggests likely matches of media that couldn't be identified by their file names,
+// by the Chromaprint fingerprints of audio and perceptual hashes of video frames.
+type Identifier struct {
+	fpcalc, acoustIDKey, acoustIDURL string
+	frames                           FrameReader
+	client                           *http.Client
+	hashes                           *hashStore
+	logger                           *zap.Logger
+}
+
+// NewIdentifier creates an identifier, frame hashes persisted by a previous one are loaded.
+func NewIdentifier(opts *Options, logger *zap.Logger) (*Identifier, error) {
+	hashes, err := newHashStore(opts.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &Identifier{
+		fpcalc:      opts.FpcalcPath,
+		acoustIDKey: opts.AcoustIDKey,
+		acoustIDURL: opts.AcoustIDURL,
+		frames:      opts.Frames,
+		client:      opts.Client,
+		hashes:      hashes,
+		logger:      logger,
+	}
+	if i.fpcalc == "" {
+		i.fpcalc = "fpcalc"
+	}
+	if i.acoustIDURL == "" {
+		i.acoustIDURL = DefaultAcoustIDURL
+	}
+	if i.client == nil {
+		i.client = http.DefaultClient
+	}
+
+	return i, nil
+}
+
+// identified checks whether media has metadata presumed to be its own, i.e. it can be suggested for other media.
+func identified(m media.Media) bool {
+	mm := m.Meta()
+	if mm == nil {
+		return false
+	}
+
+	c := meta.Confidence(mm)
+	return c == 0 || c >= meta.LowConfidence // unscored metadata was matched by an ID
+}
+
+// Suggest suggests likely matches of media in a repository, best first.
+// Audio is looked up by its fingerprint, video is compared to the frames of identified media in the candidate repositories,
+// hashed ahead by Hash. Remote media files aren't read, nil is returned for them.
+func (i *Identifier) Suggest(ctx context.Context, r repo.Repository, m media.Media, candidates []repo.Repository) ([]*Suggestion, error) {
+	if media.URLScheme(m.Path()) != "" {
+		return nil, nil
+	}
+
+	var (
+		suggestions []*Suggestion
+		err         error
+	)
+	if m.Format().Audio() {
+		suggestions, err = i.suggestAudio(ctx, m)
+	} else {
+		suggestions, err = i.suggestVideo(ctx, r, m, candidates)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b *Suggestion) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+
+		return 0
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	return suggestions, nil
+}
+
+func (i *Identifier) suggestAudio(ctx context.Context, m media.Media) ([]*Suggestion, error) {
+	if i.acoustIDKey == "" {
+		return nil, nil
+	}
+
+	cp, err := i.fingerprint(ctx, m.Path())
+	if err != nil {
+		return nil, err
+	}
+
+	return i.lookup(ctx, cp)
+}
+
+func (i *Identifier) suggestVideo(ctx context.Context, r repo.Repository, m media.Media, candidates []repo.Repository) ([]*Suggestion, error) {
+	if i.frames == nil {
+		return nil, nil
+	}
+
+	hashes, err := i.hash(ctx, r, m)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		all         = i.hashes.all()
+		suggestions []*Suggestion
+	)
+	for _, cr := range candidates {
+		for _, item := range cr.Items() {
+			if (cr.ID() == r.ID() && item.ID() == m.ID()) || !identified(item) {
+				continue
+			}
+
+			other, ok := all[hashKey(cr.ID(), item.ID())]
+			if !ok {
+				continue
+			}
+			if score := similarity(hashes, other); score >= minSimilarity {
+				suggestions = append(suggestions, &Suggestion{
+					Source:  SourceFrames,
+					Score:   score,
+					Title:   item.Meta().Title(),
+					RepoID:  cr.ID(),
+					MediaID: item.ID(),
+				})
+			}
+		}
+	}
+
+	return suggestions, nil
+}
+
+// hash returns the frame hashes of video media, they're made if the media wasn't hashed since its file changed.
+// Frames of a single color (i.e. black screens) are left out, they'd match any video.
+func (i *Identifier) hash(ctx context.Context, r repo.Repository, m media.Media) ([]uint64, error) {
+	var (
+		key  = hashKey(r.ID(), m.ID())
+		stat = r.FileStat(m.ID())
+	)
+	if hashes, ok := i.hashes.get(key, stat); ok {
+		return hashes, nil
+	}
+
+	frames, err := i.frames.Frames(ctx, m.Path(), hashFrames, hashWidth, hashHeight)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read frames")
+	}
+
+	hashes := make([]uint64, 0, len(frames))
+	for _, frame := range frames {
+		if h := frameHash(frame); h != 0 {
+			hashes = append(hashes, h)
+		}
+	}
+
+	i.hashes.put(key, stat, hashes)
+	return hashes, nil
+}
+
+// Hash hashes the frames of a repository's identified video media that weren't hashed since their files changed,
+// so that unidentified media can be compared to them, and removes the hashes of media no longer in the repository.
+// Progress is reported to the context's repo.ProgressFunc in media.
+func (i *Identifier) Hash(ctx context.Context, r repo.Repository) error {
+	if i.frames == nil {
+		return nil
+	}
+
+	var (
+		items = r.Items()
+		ids   = make(map[string]struct{}, len(items))
+	)
+	for n, item := range items {
+		ids[item.ID()] = struct{}{}
+		if item.Format().Audio() || !identified(item) || media.URLScheme(item.Path()) != "" {
+			continue
+		}
+
+		if _, err := i.hash(ctx, r, item); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if logger := repo.Logger(ctx, i.logger); logger != nil {
+				logger.Warn("failed to hash video frames", zap.String("repo", r.ID()), zap.String("id", item.ID()), zap.Error(err))
+			}
+		}
+
+		repo.ReportProgress(ctx, repo.Progress{Processed: int64(n + 1), Total: int64(len(items))})
+	}
+
+	i.hashes.prune(r.ID(), ids)
+	return i.hashes.flush()
+}
+
+// Close saves the frame hashes.
+func (i *Identifier) Close() error {
+	return i.hashes.flush()
+}
@@ -0,0 +1,142 @@
+package identify
+
+import (
+	"context"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"image"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeFrames returns frames with a pattern picked by the file name, "a" files share theirs.
+type fakeFrames struct{}
+
+func (fakeFrames) Frames(_ context.Context, path string, count, width, height int) ([]*image.Gray, error) {
+	seed := byte(7)
+	if strings.HasPrefix(filepath.Base(path), "a") {
+		seed = 3
+	}
+
+	frames := make([]*image.Gray, count)
+	for i := range frames {
+		frame := image.NewGray(image.Rect(0, 0, width, height))
+		for j := range frame.Pix {
+			frame.Pix[j] = byte(j*int(seed)+i*31) * seed
+		}
+		frames[i] = frame
+	}
+
+	return frames, nil
+}
+
+func newTestRepo(t *testing.T, files map[string]meta.Metadata) repo.MutableRepository {
+	root := t.TempDir()
+	r, err := repo.NewRepository("test", "Test", root, meta.NewLiteralSource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, mm := range files {
+		path := filepath.Join(root, name+".mkv")
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Add(media.NewMedia(name, path, mm, media.FormatMKV)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return r
+}
+
+func TestSimilarity(t *testing.T) {
+	a := []uint64{0xF0F0F0F0F0F0F0F0, 0x0123456789ABCDEF}
+	if s := similarity(a, a); s != 1 {
+		t.Errorf("expected similarity 1 of the same frames, got %f", s)
+	}
+	if s := similarity(a, []uint64{a[1], a[0] ^ 1}); s < minSimilarity {
+		t.Errorf("expected similar reordered frames, got %f", s)
+	}
+	if s := similarity(a, []uint64{^a[0], ^a[1]}); s >= minSimilarity {
+		t.Errorf("expected inverted frames not to be similar, got %f", s)
+	}
+	if s := similarity(a, nil); s != 0 {
+		t.Errorf("expected similarity 0 without frames, got %f", s)
+	}
+}
+
+func TestIdentifier_SuggestVideo(t *testing.T) {
+	r := newTestRepo(t, map[string]meta.Metadata{
+		"a-movie":   meta.NewMetadata(meta.TypeMovie, "Movie", "", "", time.Time{}, 0, nil),
+		"b-movie":   meta.NewMetadata(meta.TypeMovie, "Other Movie", "", "", time.Time{}, 0, nil),
+		"a-unnamed": nil,
+	})
+
+	path := filepath.Join(t.TempDir(), "hashes.json")
+	i, err := NewIdentifier(&Options{Frames: fakeFrames{}, Path: path}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := i.Hash(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	suggestions, err := i.Suggest(context.Background(), r, r.Get("a-unnamed"), []repo.Repository{r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != 1 || suggestions[0].MediaID != "a-movie" || suggestions[0].Title != "Movie" || suggestions[0].Source != SourceFrames {
+		t.Fatalf("expected a suggestion of the movie with the same frames, got %+v", suggestions)
+	}
+
+	// hashes are persisted
+	i, err = NewIdentifier(&Options{Path: path}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := i.hashes.all()[hashKey("test", "b-movie")]; !ok {
+		t.Error("expected persisted frame hashes")
+	}
+}
+
+func TestIdentifier_Lookup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("client") != "key" || r.FormValue("fingerprint") != "AQAD" || r.FormValue("duration") != "181" {
+			_, _ = w.Write([]byte(`{"status": "error", "error": {"message": "invalid request"}}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"status": "ok", "results": [{"score": 0.93, "recordings": [
+			{"id": "rec-1", "title": "Song", "artists": [{"name": "Band"}], "releasegroups": [{"title": "Album"}]},
+			{"id": "rec-2"}
+		]}]}`))
+	}))
+	defer srv.Close()
+
+	i, err := NewIdentifier(&Options{AcoustIDKey: "key", AcoustIDURL: srv.URL}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	suggestions, err := i.lookup(context.Background(), &chromaprint{Duration: 180.6, Fingerprint: "AQAD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != 1 {
+		t.Fatalf("expected 1 suggestion, got %d", len(suggestions))
+	}
+	if s := suggestions[0]; s.Title != "Song" || s.Album != "Album" || s.RecordingID != "rec-1" || len(s.Artists) != 1 || s.Score != 0.93 {
+		t.Errorf("unexpected suggestion %+v", s)
+	}
+
+	if _, err := i.lookup(context.Background(), &chromaprint{Duration: 10, Fingerprint: "AQAD"}); err == nil || !strings.Contains(err.Error(), "invalid request") {
+		t.Errorf("expected lookup error, got %v", err)
+	}
+}
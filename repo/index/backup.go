@@ -0,0 +1,12 @@
+package index
+
+import (
+	"github.com/katana-project/katana/backup"
+	"github.com/katana-project/katana/config"
+)
+
+func init() {
+	backup.RegisterRepo("index.json", func(repoConfig *config.Repo) string {
+		return repoConfig.IndexPath
+	})
+}
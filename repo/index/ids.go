@@ -0,0 +1,119 @@
+package index
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"golang.org/x/exp/slices"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MigrateIDs remakes the IDs of the media in an index with another ID strategy, such as after changing it in the configuration.
+// Colliding IDs are made unique like in a repository scan, the index is replaced atomically.
+// Returns a mapping of the changed IDs (old -> new), for migrating data keyed by media IDs.
+//
+// The index must not be open by a repository, the media files are located relative to the repository's roots (repo.Repository.Roots).
+func MigrateIDs(path string, roots []string, strategy media.IDStrategy) (map[string]string, error) {
+	s, records, err := openStore(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open index")
+	}
+	defer s.Close()
+
+	slices.SortFunc(records, func(a, b *record) int { // like the repository's file walk, so that collisions resolve the same
+		return strings.Compare(filepath.ToSlash(a.Item.Path_), filepath.ToSlash(b.Item.Path_))
+	})
+
+	var (
+		ids   = make(map[string]string)
+		taken = make(map[string]struct{}, len(records))
+		live  = make(map[string][]byte, len(records))
+	)
+	for _, rec := range records {
+		root, relPath := repo.SplitRoot(roots, rec.Item.Path_)
+		id, err := strategy(root, relPath, rec.Item.Meta_)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to make ID of media %s", rec.ID)
+		}
+		for i, base := 2, id; ; i++ {
+			if _, ok := taken[id]; !ok {
+				break
+			}
+
+			id = fmt.Sprintf("%s-%d", base, i)
+		}
+		taken[id] = struct{}{}
+
+		if id != rec.ID {
+			ids[rec.ID] = id
+		}
+		rec.ID, rec.Item.ID_ = id, id
+
+		line, err := json.Marshal(rec)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal index record")
+		}
+		live[id] = line
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	s.live = live
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Items reads the media in an index, such as for matching data to media while the server is stopped.
+// The media paths are relative to the repository's roots, the index must not be open by a repository.
+func Items(path string) ([]media.Media, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, errors.Wrap(err, "failed to stat index")
+	}
+
+	s, records, err := openStore(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open index")
+	}
+	defer s.Close()
+
+	items := make([]media.Media, len(records))
+	for i, rec := range records {
+		items[i] = rec.Item
+	}
+
+	return items, nil
+}
+
+// Fingerprint makes the fingerprint of the media in an index (see repo.MakeFingerprint), such as for comparing repositories while the server is stopped.
+// It's equal to the fingerprint of the indexed repository, unless its files changed since it was last scanned; the index must not be open by a repository.
+func Fingerprint(path string) (*repo.Fingerprint, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, errors.Wrap(err, "failed to stat index")
+	}
+
+	s, records, err := openStore(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open index")
+	}
+	defer s.Close()
+
+	digests := make(map[string]string, len(records))
+	for _, rec := range records {
+		digest, err := repo.FingerprintItem(rec.Item, rec.Stat.fileStat().Size, rec.Tags)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fingerprint media %s", rec.ID)
+		}
+
+		digests[rec.ID] = digest
+	}
+
+	return repo.NewFingerprint(digests), nil
+}
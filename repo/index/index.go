@@ -1,11 +1,13 @@
 package index
 
 import (
-	"encoding/json"
+	"context"
 	"github.com/katana-project/katana/internal/errors"
 	"github.com/katana-project/katana/repo"
 	"github.com/katana-project/katana/repo/media"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -17,39 +19,61 @@ import (
 type indexedRepository struct {
 	repo.MutableRepository
 
-	path       string
-	oldPath    string
-	parentPath string
-	logger     *zap.Logger
+	path   string
+	logger *zap.Logger
 
-	mu sync.Mutex
+	mu           sync.Mutex
+	store        *store
+	fingerprints map[string]*fingerprint // media ID -> fingerprint
+
+	batches    int                 // the nesting depth of running batches, see BeginBatch
+	pending    map[string]struct{} // media IDs changed during the running batches
+	pendingAll bool                // whether all media need to be written after the running batches
+
+	stop     context.CancelFunc
+	verified chan struct{} // closed when the background verification of loaded items finishes
+	deferred bool          // whether the verification was deferred, the repository root was unavailable
 }
 
-// index is a JSON-serializable media index.
-type index struct {
-	Items []*media.BasicMedia `json:"items"`
+// fingerprint is a JSON-serializable fingerprint of a media file's contents (media.Hash), used for finding moved files.
+type fingerprint struct {
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+
+	path string // the absolute path of the fingerprinted file
 }
 
+// verifyWorkers is the number of concurrent existence checks of loaded index items.
+const verifyWorkers = 16
+
 // NewRepository creates a file-based indexing repository.
+// The index is an append-only log (see store), only changed items are written to it, a legacy JSON index is migrated.
+// Index items are loaded without checking whether their files exist, that's done in the background,
+// items of missing files are relinked to their moved files (see fingerprint) or removed.
 func NewRepository(repo repo.MutableRepository, path string, logger *zap.Logger) (repo.MutableRepository, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, err
 	}
 
-	dirPath := filepath.Dir(absPath)
-	fileName := filepath.Base(absPath)
 	ir := &indexedRepository{
 		MutableRepository: repo,
 		path:              absPath,
-		oldPath:           filepath.Join(dirPath, fileName+".old"),
-		parentPath:        dirPath,
 		logger:            logger,
+		fingerprints:      make(map[string]*fingerprint),
+		pending:           make(map[string]struct{}),
+		verified:          make(chan struct{}),
 	}
 	if err := ir.load(); err != nil {
+		close(ir.verified)
+		ir.stop = func() {}
 		return ir, err
 	}
 
+	ctx, stop := context.WithCancel(context.Background())
+	ir.stop = stop
+	go ir.verify(ctx)
+
 	return ir, nil
 }
 
@@ -71,129 +95,436 @@ func (ir *indexedRepository) load() error {
 		}()
 	}
 
-	bytes, err := os.ReadFile(ir.path)
+	s, records, err := openStore(ir.path)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil
+		return err
+	}
+	ir.store = s
+	if len(s.skipped) > 0 && ir.logger != nil {
+		ir.logger.Warn(
+			"skipped corrupt index records",
+			zap.String("repo", ir.MutableRepository.ID()),
+			zap.String("path", ir.path),
+			zap.Ints("lines", s.skipped),
+		)
+	}
+
+	roots := ir.MutableRepository.Roots()
+	for _, rec := range records {
+		if err := ir.add(rec.Item, repo.AbsPath(roots, rec.Item.Path()), rec.Fingerprint, rec.Stat.fileStat(), false); err != nil {
+			return err
+		}
+		if len(rec.Tags) > 0 {
+			if err := ir.MutableRepository.SetTags(rec.ID, rec.Tags); err != nil {
+				return errors.Wrap(err, "failed to set index item tags")
+			}
+		}
+		if rec.Locked {
+			if err := ir.MutableRepository.SetLocked(rec.ID, true); err != nil {
+				return errors.Wrap(err, "failed to lock index item")
+			}
+		}
+	}
+
+	return nil
+}
+
+// verify checks whether the files of loaded index items exist, items of missing files are relinked or removed.
+// If the repository root is unavailable, such as an unmounted volume, that's deferred to the next scan (see Scan).
+func (ir *indexedRepository) verify(ctx context.Context) {
+	defer close(ir.verified)
+
+	var (
+		repoPath   = ir.MutableRepository.Path()
+		verifyTime = time.Now()
+		items      = ir.MutableRepository.Items()
+		missing    = findMissing(ctx, items)
+	)
+	if ctx.Err() != nil { // closed
+		return
+	}
+
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	var relinked, removed int
+	if len(missing) > 0 {
+		if err := repo.CheckRoots(ir.MutableRepository); err != nil { // not removed, just unreachable
+			ir.deferred = true
+			if ir.logger != nil {
+				ir.logger.Warn(
+					"repository root unavailable, deferring index verification",
+					zap.String("repo", ir.MutableRepository.ID()),
+					zap.String("repo_path", repoPath),
+					zap.String("index_path", ir.path),
+					zap.Error(err),
+				)
+			}
+			return
 		}
 
-		return errors.Wrap(err, "failed to read index")
+		relinked, removed = ir.resolveMissing(missing)
 	}
 
-	var ix index
-	if err := json.Unmarshal(bytes, &ix); err != nil {
-		return errors.Wrap(err, "failed to unmarshal index")
+	if ir.logger != nil {
+		ir.logger.Info(
+			"finished index verification",
+			zap.String("repo", ir.MutableRepository.ID()),
+			zap.String("repo_path", repoPath),
+			zap.String("path", ir.path),
+			zap.Int("items", len(items)),
+			zap.Int("relinked", relinked),
+			zap.Int("removed", removed),
+			zap.Int64("elapsed_ms", time.Since(verifyTime).Milliseconds()),
+		)
 	}
+}
 
+// findMissing returns the items whose files don't exist, until the context is done.
+func findMissing(ctx context.Context, items []media.Media) []media.Media {
+	var (
+		queue   = make(chan media.Media)
+		missing []media.Media
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+	)
+	for i := 0; i < verifyWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range queue {
+				if _, err := os.Stat(item.Path()); errors.Is(err, fs.ErrNotExist) {
+					mu.Lock()
+					missing = append(missing, item)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, item := range items {
+		select {
+		case queue <- item:
+		case <-ctx.Done():
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	return missing
+}
+
+// resolveMissing relinks items of missing files to their moved files or removes them, writing the changes to the index.
+// Returns the number of relinked and removed items, mu must be held.
+func (ir *indexedRepository) resolveMissing(missing []media.Media) (relinked, removed int) {
 	repoPath := ir.MutableRepository.Path()
-	for _, item := range ix.Items {
-		absItemPath := filepath.Join(repoPath, item.Path())
-		if _, err := os.Stat(absItemPath); errors.Is(err, fs.ErrNotExist) {
-			ir.logger.Warn(
-				"non-existent index item, skipping",
+
+	missing = slices.DeleteFunc(missing, func(item media.Media) bool {
+		return ir.MutableRepository.ArchiveStatus(item.ID()) != repo.ArchiveStatusNone // the file is in cold storage
+	})
+
+	paths := make(map[string]struct{})
+	for _, item := range ir.MutableRepository.Items() {
+		paths[item.Path()] = struct{}{}
+	}
+
+	moved, err := ir.findMoved(missing, ir.fingerprints, paths)
+	if err != nil && ir.logger != nil { // relink what was found before the failure
+		ir.logger.Warn(
+			"failed to look up moved index items",
+			zap.String("repo", ir.MutableRepository.ID()),
+			zap.String("repo_path", repoPath),
+			zap.String("index_path", ir.path),
+			zap.Error(err),
+		)
+	}
+
+	for _, item := range missing {
+		if current := ir.MutableRepository.Get(item.ID()); current == nil || current.Path() != item.Path() {
+			continue // changed in the meantime
+		}
+
+		tags, locked := ir.MutableRepository.Tags(item.ID()), ir.MutableRepository.Locked(item.ID()) // removed along with the item
+		if err := ir.MutableRepository.Remove(item); err != nil {
+			if ir.logger != nil {
+				ir.logger.Error("failed to remove index item", zap.String("id", item.ID()), zap.Error(err))
+			}
+			continue
+		}
+
+		newPath, ok := moved[item.ID()]
+		if !ok {
+			removed++
+			if ir.logger != nil {
+				ir.logger.Warn(
+					"non-existent index item, removing",
+					zap.String("repo", ir.MutableRepository.ID()),
+					zap.String("repo_path", repoPath),
+					zap.String("index_path", ir.path),
+					zap.String("path", item.Path()),
+				)
+			}
+			continue
+		}
+
+		if err := ir.add(item, newPath, ir.fingerprints[item.ID()], repo.FileStat{}, true); err != nil {
+			if ir.logger != nil {
+				ir.logger.Error("failed to relink index item", zap.String("id", item.ID()), zap.Error(err))
+			}
+			continue
+		}
+		if len(tags) > 0 {
+			if err := ir.MutableRepository.SetTags(item.ID(), tags); err != nil && ir.logger != nil {
+				ir.logger.Error("failed to keep relinked index item tags", zap.String("id", item.ID()), zap.Error(err))
+			}
+		}
+		if locked {
+			if err := ir.MutableRepository.SetLocked(item.ID(), true); err != nil && ir.logger != nil {
+				ir.logger.Error("failed to keep relinked index item lock", zap.String("id", item.ID()), zap.Error(err))
+			}
+		}
+
+		relinked++
+		if ir.logger != nil {
+			ir.logger.Info(
+				"relinked moved index item",
 				zap.String("repo", ir.MutableRepository.ID()),
-				zap.String("repo_path", ir.MutableRepository.Path()),
+				zap.String("repo_path", repoPath),
 				zap.String("index_path", ir.path),
-				zap.String("path", absItemPath),
+				zap.String("id", item.ID()),
+				zap.String("old_path", item.Path()),
+				zap.String("path", newPath),
 			)
-			continue
 		}
-
-		// un-hack the Media contract for code reuse - you're not supposed to have relative paths in there
-		absItem := media.NewBasicMedia(media.NewMedia(item.ID(), absItemPath, item.Meta(), item.Format()))
-		if err := ir.MutableRepository.Add(absItem); err != nil {
-			return errors.Wrap(err, "failed to add index item to repository")
+	}
+	if relinked > 0 || removed > 0 {
+		if err := ir.sync(); err != nil && ir.logger != nil {
+			ir.logger.Error("failed to write index", zap.String("path", ir.path), zap.Error(err))
 		}
 	}
 
+	return relinked, removed
+}
+
+// add adds an index item to the underlying repository under an absolute path, along with its fingerprint, can be nil.
+// The file is only accessed if it's verified to exist, its stat is only used if it isn't.
+func (ir *indexedRepository) add(item media.Media, absItemPath string, fp *fingerprint, stat repo.FileStat, verified bool) error {
+	add := func(m media.Media) error {
+		return ir.MutableRepository.AddUnverified(m, stat)
+	}
+	if verified {
+		add = ir.MutableRepository.Add
+	}
+
+	// un-hack the Media contract for code reuse - you're not supposed to have relative paths in there
+	absItem := media.NewBasicMedia(media.NewMedia(item.ID(), absItemPath, item.Meta(), item.Format()))
+	if err := add(absItem); err != nil {
+		return errors.Wrap(err, "failed to add index item to repository")
+	}
+
+	if fp != nil {
+		fp.path = absItemPath
+		ir.fingerprints[item.ID()] = fp
+	}
+
 	return nil
 }
 
-func (ir *indexedRepository) save() error {
+// findMoved looks up files matching the fingerprints of missing index items in the repository's directory,
+// files of present items are skipped. Returns a mapping of media IDs to the absolute paths they were found at.
+func (ir *indexedRepository) findMoved(missing []media.Media, fps map[string]*fingerprint, paths map[string]struct{}) (map[string]string, error) {
+	bySize := make(map[int64][]string) // file size -> media IDs, so that only files of a matching size get hashed
+	for _, item := range missing {
+		if fp, ok := fps[item.ID()]; ok && fp != nil {
+			bySize[fp.Size] = append(bySize[fp.Size], item.ID())
+		}
+	}
+
+	moved := make(map[string]string)
+	if len(bySize) == 0 {
+		return moved, nil
+	}
+
+	walk := func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ir.MutableRepository.Ignored(path, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+		if d.IsDir() || media.SubtitleCodec(filepath.Ext(path)) != "" || media.ExtraKindOf(path) != "" {
+			return nil
+		}
+		if _, ok := paths[path]; ok {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		ids := bySize[fi.Size()]
+		if len(ids) == 0 {
+			return nil
+		}
+
+		hash, err := media.Hash(path)
+		if err != nil {
+			return err
+		}
+		for i, id := range ids {
+			if fps[id].Hash == hash {
+				moved[id] = path
+				bySize[fi.Size()] = append(ids[:i:i], ids[i+1:]...) // one file relinks one item
+				break
+			}
+		}
+
+		return nil
+	}
+	for _, root := range ir.MutableRepository.Roots() {
+		if err := filepath.WalkDir(root, walk); err != nil {
+			return moved, err
+		}
+	}
+
+	return moved, nil
+}
+
+// put writes the current state of media to the index, media no longer in the repository are deleted from it.
+// Writing is deferred until the running batches are committed, mu must be held.
+func (ir *indexedRepository) put(ids ...string) error {
+	if ir.batches > 0 {
+		for _, id := range ids {
+			ir.pending[id] = struct{}{}
+		}
+		return nil
+	}
+
+	records := make([]*record, 0, len(ids))
+	for _, id := range ids {
+		rec, err := ir.record(id)
+		if err != nil {
+			return err
+		}
+
+		records = append(records, rec)
+	}
+
+	return ir.store.write(records)
+}
+
+// sync writes the changes of all media to the index, deferred until the running batches are committed, mu must be held.
+func (ir *indexedRepository) sync() error {
+	if ir.batches > 0 {
+		ir.pendingAll = true
+		return nil
+	}
+
 	if ir.logger != nil {
-		saveTime := time.Now()
+		syncTime := time.Now()
 		defer func() {
 			ir.logger.Info(
-				"finished index save",
+				"finished index sync",
 				zap.String("repo", ir.MutableRepository.ID()),
 				zap.String("repo_path", ir.MutableRepository.Path()),
 				zap.String("path", ir.path),
-				zap.Int64("elapsed_ms", time.Since(saveTime).Milliseconds()),
+				zap.Int64("elapsed_ms", time.Since(syncTime).Milliseconds()),
 			)
 		}()
 	}
 
 	var (
-		path  = ir.MutableRepository.Path()
 		items = ir.MutableRepository.Items()
-		ix    = &index{Items: make([]*media.BasicMedia, len(items))}
+		ids   = make([]string, 0, len(items))
+		seen  = make(map[string]struct{}, len(items))
 	)
-	for i, item := range items {
-		relItemPath, err := filepath.Rel(path, item.Path())
-		if err != nil {
-			return err // shouldn't be possible
+	for _, item := range items {
+		ids = append(ids, item.ID())
+		seen[item.ID()] = struct{}{}
+	}
+	for _, id := range ir.store.ids() {
+		if _, ok := seen[id]; !ok {
+			ids = append(ids, id)
 		}
-
-		// hack the Media contract for code reuse - you're not supposed to have relative paths in there
-		ix.Items[i] = media.NewBasicMedia(media.NewMedia(item.ID(), relItemPath, item.Meta(), item.Format()))
 	}
 
-	bytes, err := json.Marshal(ix)
-	if err != nil {
-		return errors.Wrap(err, "failed to marshal index")
-	}
+	return ir.put(ids...)
+}
 
-	if err := os.MkdirAll(ir.parentPath, 0); err != nil {
-		return errors.Wrap(err, "failed to make directories")
+// record makes the index record of the current state of media, a deletion if it's no longer in the repository.
+func (ir *indexedRepository) record(id string) (*record, error) {
+	item := ir.MutableRepository.Get(id)
+	if item == nil {
+		delete(ir.fingerprints, id)
+		return &record{ID: id, Deleted: true}, nil
 	}
 
-	if err := ir.copy(); err != nil {
-		return errors.Wrap(err, "failed to copy old index file")
+	relItemPath, err := repo.RelPath(ir.MutableRepository.Roots(), item.Path())
+	if err != nil {
+		return nil, err // shouldn't be possible
 	}
 
-	if err := os.WriteFile(ir.path, bytes, 0); err != nil {
-		return errors.Wrap(err, "failed to write index")
+	fp := ir.fingerprint(item)
+	if fp != nil {
+		ir.fingerprints[id] = fp
 	}
 
-	return nil
+	return &record{
+		ID: id,
+		// hack the Media contract for code reuse - you're not supposed to have relative paths in there
+		Item:        media.NewBasicMedia(media.NewMedia(id, relItemPath, item.Meta(), item.Format())),
+		Fingerprint: fp,
+		Stat:        newFileStat(ir.MutableRepository.FileStat(id)),
+		Tags:        ir.MutableRepository.Tags(id),
+		Locked:      ir.MutableRepository.Locked(id),
+	}, nil
 }
 
-func (ir *indexedRepository) copy() error {
-	if ir.logger != nil {
-		copyTime := time.Now()
-		defer func() {
-			ir.logger.Info(
-				"finished index copy",
-				zap.String("repo", ir.MutableRepository.ID()),
-				zap.String("repo_path", ir.MutableRepository.Path()),
-				zap.String("path", ir.path),
-				zap.Int64("elapsed_ms", time.Since(copyTime).Milliseconds()),
-			)
-		}()
+// fingerprint returns the fingerprint of a media file, made if the file changed path since the last one.
+// Returns nil if the file can't be read, such as when it was removed in the meantime.
+func (ir *indexedRepository) fingerprint(m media.Media) *fingerprint {
+	path := m.Path()
+	if fp, ok := ir.fingerprints[m.ID()]; ok && fp.path == path {
+		return fp
 	}
 
-	bytes, err := os.ReadFile(ir.path)
+	fi, err := os.Stat(path)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil
-		}
+		return nil
+	}
 
-		return err
+	hash, err := media.Hash(path)
+	if err != nil {
+		return nil
 	}
 
-	return os.WriteFile(ir.oldPath, bytes, 0)
+	return &fingerprint{Hash: hash, Size: fi.Size(), path: path}
 }
 
-func (ir *indexedRepository) Scan() error {
+func (ir *indexedRepository) Scan(mode repo.ScanMode) error {
+	<-ir.verified // moved files must be relinked before they're picked up as new media
+
 	ir.mu.Lock()
 	defer ir.mu.Unlock()
 
-	err := ir.MutableRepository.Scan()
+	err := ir.MutableRepository.Scan(mode)
 	if err != nil {
 		return err
 	}
 
-	return ir.save()
+	if ir.deferred && repo.CheckRoots(ir.MutableRepository) == nil {
+		ir.deferred = false
+		if missing := findMissing(context.Background(), ir.MutableRepository.Items()); len(missing) > 0 {
+			ir.resolveMissing(missing)
+		}
+	}
+
+	return ir.sync()
 }
 
 func (ir *indexedRepository) Add(m media.Media) error {
@@ -205,7 +536,7 @@ func (ir *indexedRepository) Add(m media.Media) error {
 		return err
 	}
 
-	return ir.save()
+	return ir.put(m.ID())
 }
 
 func (ir *indexedRepository) AddPath(path string) error {
@@ -217,7 +548,57 @@ func (ir *indexedRepository) AddPath(path string) error {
 		return err
 	}
 
-	return ir.save()
+	if m := ir.MutableRepository.Find(path); m != nil {
+		return ir.put(m.ID())
+	}
+	return ir.sync()
+}
+
+func (ir *indexedRepository) Update(m media.Media) error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if err := ir.MutableRepository.Update(m); err != nil {
+		return err
+	}
+
+	return ir.put(m.ID())
+}
+
+func (ir *indexedRepository) Move(m media.Media, path string) error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if err := ir.MutableRepository.Move(m, path); err != nil {
+		return err
+	}
+	if fp, ok := ir.fingerprints[m.ID()]; ok { // same contents, no need to fingerprint again
+		fp.path = path
+	}
+
+	return ir.put(m.ID())
+}
+
+func (ir *indexedRepository) SetTags(id string, tags []string) error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if err := ir.MutableRepository.SetTags(id, tags); err != nil {
+		return err
+	}
+
+	return ir.put(id)
+}
+
+func (ir *indexedRepository) SetLocked(id string, locked bool) error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if err := ir.MutableRepository.SetLocked(id, locked); err != nil {
+		return err
+	}
+
+	return ir.put(id)
 }
 
 func (ir *indexedRepository) Remove(m media.Media) error {
@@ -228,16 +609,85 @@ func (ir *indexedRepository) Remove(m media.Media) error {
 		return err
 	}
 
-	return ir.save()
+	return ir.put(m.ID())
 }
 
 func (ir *indexedRepository) RemovePath(path string) error {
 	ir.mu.Lock()
 	defer ir.mu.Unlock()
 
+	m := ir.MutableRepository.Find(path)
 	if err := ir.MutableRepository.RemovePath(path); err != nil {
 		return err
 	}
 
-	return ir.save()
+	if m != nil {
+		return ir.put(m.ID())
+	}
+	return ir.sync()
+}
+
+func (ir *indexedRepository) BeginBatch() {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	ir.batches++
+}
+
+func (ir *indexedRepository) Commit() error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if ir.batches == 0 { // unmatched
+		return nil
+	}
+
+	ir.batches--
+	if ir.batches > 0 {
+		return nil
+	}
+
+	return ir.flush()
+}
+
+// flush writes the changes deferred by batches, mu must be held.
+func (ir *indexedRepository) flush() error {
+	pending, pendingAll := ir.pending, ir.pendingAll
+	ir.pending, ir.pendingAll = make(map[string]struct{}), false
+	if pendingAll {
+		return ir.sync()
+	}
+
+	ids := make([]string, 0, len(pending))
+	for id := range pending {
+		ids = append(ids, id)
+	}
+
+	return ir.put(ids...)
+}
+
+// Close stops the background verification and waits for a running write before closing the index and the underlying repository.
+// Changes of uncommitted batches are written.
+func (ir *indexedRepository) Close() error {
+	ir.stop()
+	<-ir.verified
+
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	var err error
+	if ir.store != nil {
+		if ir.batches > 0 { // left uncommitted
+			ir.batches = 0
+			err = ir.flush()
+		}
+
+		err = multierr.Append(err, ir.store.Close())
+	}
+
+	return multierr.Append(err, ir.MutableRepository.Close())
+}
+
+func (ir *indexedRepository) Mutable() repo.MutableRepository {
+	return ir
 }
@@ -0,0 +1,540 @@
+package index
+
+import (
+	"encoding/json"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIndexedRepo_Relink(t *testing.T) {
+	var (
+		root      = t.TempDir()
+		indexPath = filepath.Join(t.TempDir(), "index.json")
+		oldPath   = filepath.Join(root, "Movie.mkv")
+		newPath   = filepath.Join(root, "Movies", "Movie (2000).mkv")
+	)
+	if err := os.WriteFile(oldPath, []byte("movie"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Other.mkv"), []byte("other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	open := func() repo.MutableRepository {
+		r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ir, err := NewRepository(r, indexPath, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = ir.Close() })
+
+		<-ir.(*indexedRepository).verified
+		return ir
+	}
+
+	ir := open()
+	if err := ir.Add(media.NewMedia("movie", oldPath, nil, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatal(err)
+	}
+
+	ir = open()
+	m := ir.Get("movie")
+	if m == nil {
+		t.Fatal("expected moved media to be relinked")
+	}
+	if m.Path() != newPath {
+		t.Errorf("expected path %s, got %s", newPath, m.Path())
+	}
+	if ir.Find(filepath.Join(root, "Other.mkv")) != nil {
+		t.Error("expected unindexed file not to be added")
+	}
+
+	if err := os.Remove(newPath); err != nil {
+		t.Fatal(err)
+	}
+
+	ir = open()
+	if ir.Get("movie") != nil {
+		t.Error("expected media of a removed file to be removed")
+	}
+}
+
+func TestIndexedRepo_Unmounted(t *testing.T) {
+	var (
+		root      = t.TempDir()
+		indexPath = filepath.Join(t.TempDir(), "index.json")
+		path      = filepath.Join(root, "Movie.mkv")
+		otherPath = filepath.Join(root, "Other.txt")
+	)
+	if err := os.WriteFile(path, []byte("movie"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	open := func() repo.MutableRepository {
+		r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ir, err := NewRepository(r, indexPath, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = ir.Close() })
+
+		<-ir.(*indexedRepository).verified
+		return ir
+	}
+
+	ir := open()
+	if err := ir.Add(media.NewMedia("movie", path, nil, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Remove(path); err != nil { // unmounted, the mount point is left empty
+		t.Fatal(err)
+	}
+
+	ir = open()
+	if ir.Get("movie") == nil {
+		t.Fatal("expected media to be kept while the repository root is empty")
+	}
+	if err := ir.Scan(repo.ScanIncremental); err != nil {
+		t.Fatal(err)
+	}
+	if ir.Get("movie") == nil {
+		t.Fatal("expected media to be kept after scanning an empty repository root")
+	}
+
+	if err := os.WriteFile(otherPath, []byte("other"), 0644); err != nil { // mounted again, without the movie
+		t.Fatal(err)
+	}
+	if err := ir.Scan(repo.ScanIncremental); err != nil {
+		t.Fatal(err)
+	}
+	if ir.Get("movie") != nil {
+		t.Error("expected media of a removed file to be removed once the repository root is available")
+	}
+}
+
+func TestIndexedRepo_Migrate(t *testing.T) {
+	var (
+		root      = t.TempDir()
+		indexPath = filepath.Join(t.TempDir(), "index.json")
+		path      = filepath.Join(root, "Movie.mkv")
+	)
+	if err := os.WriteFile(path, []byte("movie"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	legacy, err := json.Marshal(&legacyIndex{
+		Items: []*media.BasicMedia{media.NewBasicMedia(media.NewMedia("movie", "Movie.mkv", nil, media.FormatMKV))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(indexPath, legacy, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	open := func() repo.MutableRepository {
+		r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ir, err := NewRepository(r, indexPath, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		<-ir.(*indexedRepository).verified
+		return ir
+	}
+
+	ir := open()
+	if m := ir.Get("movie"); m == nil || m.Path() != path {
+		t.Fatalf("expected legacy index item to be migrated, got %v", m)
+	}
+	if b, err := os.ReadFile(indexPath + ".old"); err != nil || string(b) != string(legacy) {
+		t.Errorf("expected legacy index to be kept, got %q (%v)", b, err)
+	}
+	if err := ir.Remove(ir.Get("movie")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ir.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	ir = open()
+	defer ir.Close()
+
+	if ir.Get("movie") != nil {
+		t.Error("expected removed media not to be loaded from the migrated index")
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	s, records, err := openStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Fatalf("expected new index to be empty, got %d records", len(records))
+	}
+
+	for i := 0; i < 3; i++ { // the same record, written once
+		err := s.write([]*record{
+			{ID: "a", Item: media.NewBasicMedia(media.NewMedia("a", "a.mkv", nil, media.FormatMKV))},
+			{ID: "b", Item: media.NewBasicMedia(media.NewMedia("b", "b.mkv", nil, media.FormatMKV))},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.write([]*record{{ID: "b", Deleted: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.file.WriteString(`{"id":"c","item":`); err != nil { // interrupted write
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, records, err = openStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if len(records) != 1 || records[0].ID != "a" || records[0].Item.Path() != "a.mkv" {
+		t.Fatalf("expected only record a, got %v", records)
+	}
+	if s.garbage != 2 {
+		t.Errorf("expected 2 superseded records, got %d", s.garbage)
+	}
+
+	if err := s.write([]*record{{ID: "c", Item: media.NewBasicMedia(media.NewMedia("c", "c.mkv", nil, media.FormatMKV))}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.compact(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(b), "\n"); lines != 3 { // header, a, c
+		t.Errorf("expected 3 lines in compacted index, got %d:\n%s", lines, b)
+	}
+}
+
+func TestStore_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	s, _, err := openStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := s.write([]*record{{ID: id, Item: media.NewBasicMedia(media.NewMedia(id, id+".mkv", nil, media.FormatMKV))}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	offset, err := s.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.file.WriteString(`{"id":"c","item":`); err != nil { // failed write
+		t.Fatal(err)
+	}
+	if err := s.discard(offset, os.ErrClosed); err != os.ErrClosed {
+		t.Errorf("expected the write error, got %v", err)
+	}
+	if err := s.write([]*record{{ID: "d", Item: media.NewBasicMedia(media.NewMedia("d", "d.mkv", nil, media.FormatMKV))}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), `"id":"c"`) {
+		t.Fatalf("expected the torn record to be discarded:\n%s", b)
+	}
+
+	lines := strings.SplitAfter(string(b), "\n") // header, a, b, d
+	lines[2] = lines[2][:len(lines[2])/2] + "\n"
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, records, err := openStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if len(s.skipped) != 1 || s.skipped[0] != 3 {
+		t.Errorf("expected line 3 to be skipped, got %v", s.skipped)
+	}
+	if ids := s.ids(); len(records) != 2 || len(ids) != 2 || s.live["a"] == nil || s.live["d"] == nil {
+		t.Errorf("expected records a and d, got %v", ids)
+	}
+
+	b, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(b), "\n"); lines != 3 { // header, a, d
+		t.Errorf("expected the corrupt record to be compacted away, got %d lines:\n%s", lines, b)
+	}
+}
+
+func TestIndexedRepo_Batch(t *testing.T) {
+	var (
+		root      = t.TempDir()
+		indexPath = filepath.Join(t.TempDir(), "index.json")
+		path      = filepath.Join(root, "Movie.mkv")
+	)
+	if err := os.WriteFile(path, []byte("movie"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ir, err := NewRepository(r, indexPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ir.Close()
+
+	indexed := func() bool {
+		b, err := os.ReadFile(indexPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return strings.Contains(string(b), `"id":"movie"`)
+	}
+
+	ir.BeginBatch()
+	ir.BeginBatch() // nested
+	if err := ir.Add(media.NewMedia("movie", path, nil, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ir.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if indexed() {
+		t.Error("expected media not to be written before the outermost batch is committed")
+	}
+
+	if err := ir.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if !indexed() {
+		t.Error("expected media to be written once the batch is committed")
+	}
+}
+
+func TestIndexedRepo_Tags(t *testing.T) {
+	var (
+		root      = t.TempDir()
+		indexPath = filepath.Join(t.TempDir(), "index.json")
+		path      = filepath.Join(root, "Movie.mkv")
+	)
+	if err := os.WriteFile(path, []byte("movie"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	open := func() repo.MutableRepository {
+		r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ir, err := NewRepository(r, indexPath, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return ir
+	}
+
+	ir := open()
+	if err := ir.Add(media.NewMedia("movie", path, nil, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ir.SetTags("movie", []string{"halloween"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ir.SetLocked("movie", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := ir.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	ir = open()
+	defer ir.Close()
+
+	if got := ir.Tags("movie"); len(got) != 1 || got[0] != "halloween" {
+		t.Errorf("expected tags to be loaded from the index, got %v", got)
+	}
+	if !ir.Locked("movie") {
+		t.Error("expected lock to be loaded from the index")
+	}
+}
+
+func TestMigrateIDs(t *testing.T) {
+	var (
+		root      = t.TempDir()
+		indexPath = filepath.Join(t.TempDir(), "index.json")
+	)
+	for _, dir := range []string{"A", "B"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, dir, "episode-1.mkv"), []byte(dir), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	open := func() repo.MutableRepository {
+		r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ir, err := NewRepository(r, indexPath, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		<-ir.(*indexedRepository).verified
+		return ir
+	}
+
+	ir := open()
+	for _, m := range []media.Media{
+		media.NewMedia("a", filepath.Join(root, "A", "episode-1.mkv"), nil, media.FormatMKV),
+		media.NewMedia("b", filepath.Join(root, "B", "episode-1.mkv"), nil, media.FormatMKV),
+	} {
+		if err := ir.Add(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ir.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := MigrateIDs(indexPath, []string{root}, media.IDStrategySlug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids["a"] != "episode-1-mkv" || ids["b"] != "episode-1-mkv-2" {
+		t.Errorf("unexpected migrated IDs %v", ids)
+	}
+
+	ir = open()
+	t.Cleanup(func() { _ = ir.Close() })
+
+	if m := ir.Get("episode-1-mkv-2"); m == nil || m.Path() != filepath.Join(root, "B", "episode-1.mkv") {
+		t.Errorf("expected migrated media, got %v", m)
+	}
+	if ir.Get("a") != nil {
+		t.Error("expected old ID to be gone")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	var (
+		root      = t.TempDir()
+		indexPath = filepath.Join(t.TempDir(), "index.json")
+		path      = filepath.Join(root, "Movie.mkv")
+	)
+	if err := os.WriteFile(path, []byte("movie"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	open := func() repo.MutableRepository {
+		r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ir, err := NewRepository(r, indexPath, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		<-ir.(*indexedRepository).verified
+		return ir
+	}
+
+	ir := open()
+	if err := ir.Add(media.NewMedia("movie", path, nil, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ir.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	ir = open()
+	live, err := repo.MakeFingerprint(ir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ir.SetTags("movie", []string{"kids"}); err != nil {
+		t.Fatal(err)
+	}
+	tagged, err := repo.MakeFingerprint(ir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ir.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if live.Count != 1 {
+		t.Errorf("expected 1 fingerprinted media, got %d", live.Count)
+	}
+	if tagged.Hash == live.Hash {
+		t.Error("expected fingerprint to change with tags")
+	}
+
+	fp, err := Fingerprint(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fp.Hash != tagged.Hash {
+		t.Errorf("expected index fingerprint %s to match the repository's, got %s", tagged.Hash, fp.Hash)
+	}
+}
@@ -0,0 +1,365 @@
+package index
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// storeVersion is the current version of the index log format, see migrate.
+	storeVersion = 1
+	// compactThreshold is the minimum number of superseded records in the log before it's compacted.
+	compactThreshold = 1024
+)
+
+// header is the first record of the index log.
+type header struct {
+	Version int `json:"version"`
+}
+
+// record is a JSON-serializable index log record, an item put into or deleted from the index.
+type record struct {
+	ID          string            `json:"id"`
+	Deleted     bool              `json:"deleted,omitempty"`
+	Item        *media.BasicMedia `json:"item,omitempty"` // path is relative to the repository root
+	Fingerprint *fingerprint      `json:"fingerprint,omitempty"`
+	Stat        *fileStat         `json:"stat,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`   // user-defined tags
+	Locked      bool              `json:"locked,omitempty"` // locked against automatic changes
+}
+
+// fileStat is a JSON-serializable repo.FileStat, the file's stat when its format was last detected.
+type fileStat struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// newFileStat converts a repo.FileStat, returns nil if it's zero.
+func newFileStat(stat repo.FileStat) *fileStat {
+	if stat.IsZero() {
+		return nil
+	}
+
+	return &fileStat{Size: stat.Size, ModTime: stat.ModTime}
+}
+
+// fileStat converts the stat to a repo.FileStat, nil is converted to a zero value.
+func (st *fileStat) fileStat() repo.FileStat {
+	if st == nil {
+		return repo.FileStat{}
+	}
+
+	return repo.FileStat{Size: st.Size, ModTime: st.ModTime}
+}
+
+// legacyIndex is a media index of the whole-file JSON format, before the log (version 0).
+type legacyIndex struct {
+	Items        []*media.BasicMedia     `json:"items"`
+	Fingerprints map[string]*fingerprint `json:"fingerprints,omitempty"` // media ID -> fingerprint
+}
+
+// store is an append-only log of index records, writes only append the records of changed items.
+// The log is compacted once superseded records outnumber the live ones, the store is not safe for concurrent use.
+type store struct {
+	path string
+	file *os.File
+
+	live    map[string][]byte // media ID -> encoded record
+	garbage int               // the number of superseded records in the log
+	skipped []int             // the line numbers of corrupt records skipped when loading the log
+}
+
+// openStore opens an index log, making it if it doesn't exist, a legacy JSON index is migrated (see migrate).
+// Returns the store and its live records.
+func openStore(path string) (*store, []*record, error) {
+	s := &store{path: path, live: make(map[string][]byte)}
+
+	records, err := s.load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s.file == nil || len(s.skipped) > 0 || s.garbage >= compactThreshold && s.garbage > len(s.live) {
+		if err := s.compact(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return s, records, nil
+}
+
+// load reads the log, a torn record at its end (an interrupted write) is discarded.
+// Corrupt records before it are skipped (see skipped), the log is compacted without them after loading.
+func (s *store) load() ([]*record, error) {
+	f, err := os.OpenFile(s.path, os.O_RDWR, 0)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "failed to open index")
+	}
+
+	var (
+		r      = bufio.NewReader(f)
+		offset int64
+		hdr    *header
+		byId   = make(map[string]*record)
+	)
+	for lineNum := 1; ; lineNum++ {
+		line, err := r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			_ = f.Close()
+			return nil, errors.Wrap(err, "failed to read index")
+		}
+
+		eof := err == io.EOF
+		if eof && (len(line) == 0 || hdr != nil) {
+			if len(line) > 0 { // torn record
+				if err := f.Truncate(offset); err != nil {
+					_ = f.Close()
+					return nil, errors.Wrap(err, "failed to truncate torn index record")
+				}
+			}
+			break
+		}
+
+		if hdr == nil {
+			hdr = &header{}
+			if err := json.Unmarshal(line, hdr); err != nil || hdr.Version == 0 {
+				_ = f.Close()
+				return s.migrate(0)
+			}
+			if hdr.Version > storeVersion {
+				_ = f.Close()
+				return nil, fmt.Errorf("index version %d is newer than supported version %d", hdr.Version, storeVersion)
+			}
+		} else {
+			var rec record
+			if err := json.Unmarshal(line, &rec); err != nil || rec.ID == "" {
+				s.skipped = append(s.skipped, lineNum)
+				offset += int64(len(line))
+				if eof {
+					break
+				}
+				continue
+			}
+
+			if _, ok := s.live[rec.ID]; ok {
+				s.garbage++
+			}
+			if rec.Deleted {
+				delete(s.live, rec.ID)
+				delete(byId, rec.ID)
+				s.garbage++
+			} else {
+				s.live[rec.ID] = bytes.TrimSuffix(line, []byte{'\n'})
+				byId[rec.ID] = &rec
+			}
+		}
+
+		offset += int64(len(line))
+		if eof {
+			break
+		}
+	}
+	if hdr == nil { // empty file
+		_ = f.Close()
+		return nil, nil
+	}
+	if hdr.Version < storeVersion {
+		_ = f.Close()
+		return s.migrate(hdr.Version)
+	}
+
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		_ = f.Close()
+		return nil, errors.Wrap(err, "failed to seek index")
+	}
+	s.file = f
+
+	records := make([]*record, 0, len(byId))
+	for _, rec := range byId {
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+// migrate upgrades an index of an older format version to the current one, keeping a copy of it next to the log.
+// The migrated log is written by the following compaction.
+func (s *store) migrate(version int) ([]*record, error) {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read index")
+	}
+
+	var records []*record
+	switch version {
+	case 0:
+		var ix legacyIndex
+		if err := json.Unmarshal(b, &ix); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal legacy index")
+		}
+
+		records = make([]*record, len(ix.Items))
+		for i, item := range ix.Items {
+			records[i] = &record{ID: item.ID(), Item: item, Fingerprint: ix.Fingerprints[item.ID()]}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported index version %d", version)
+	}
+
+	if err := os.WriteFile(s.path+".old", b, 0644); err != nil {
+		return nil, errors.Wrap(err, "failed to copy old index")
+	}
+
+	s.live = make(map[string][]byte, len(records))
+	for _, rec := range records {
+		line, err := json.Marshal(rec)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal index record")
+		}
+
+		s.live[rec.ID] = line
+	}
+
+	return records, nil
+}
+
+// write appends records to the log, records of items that didn't change since they were last written are skipped.
+func (s *store) write(records []*record) error {
+	var (
+		buf     bytes.Buffer
+		changed = make(map[string][]byte) // media ID -> encoded record, nil if deleted
+	)
+	for _, rec := range records {
+		prev, ok := s.live[rec.ID]
+		if rec.Deleted && !ok {
+			continue
+		}
+
+		line, err := json.Marshal(rec)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal index record")
+		}
+		if !rec.Deleted && ok && bytes.Equal(prev, line) {
+			continue
+		}
+
+		buf.Write(line)
+		buf.WriteByte('\n')
+		if rec.Deleted {
+			changed[rec.ID] = nil
+		} else {
+			changed[rec.ID] = line
+		}
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	offset, err := s.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return errors.Wrap(err, "failed to seek index")
+	}
+	if _, err := s.file.Write(buf.Bytes()); err != nil {
+		return s.discard(offset, errors.Wrap(err, "failed to write index records"))
+	}
+	if err := s.file.Sync(); err != nil {
+		return s.discard(offset, errors.Wrap(err, "failed to sync index"))
+	}
+
+	for id, line := range changed {
+		if _, ok := s.live[id]; ok {
+			s.garbage++
+		}
+		if line == nil {
+			delete(s.live, id)
+			s.garbage++ // the deletion record itself
+		} else {
+			s.live[id] = line
+		}
+	}
+	if s.garbage >= compactThreshold && s.garbage > len(s.live) {
+		return s.compact()
+	}
+
+	return nil
+}
+
+// discard truncates the log to the offset before a failed write, for the following records not to be appended after a torn one.
+// It's the best effort, records left torn by a failed truncation are skipped by the next load, the write error is returned.
+func (s *store) discard(offset int64, err error) error {
+	if s.file.Truncate(offset) == nil {
+		_, _ = s.file.Seek(offset, io.SeekStart)
+	}
+
+	return err
+}
+
+// compact rewrites the log with only the live records, replacing it atomically.
+func (s *store) compact() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to create index")
+	}
+
+	w := bufio.NewWriter(f)
+	hdr, _ := json.Marshal(&header{Version: storeVersion})
+	w.Write(hdr)
+	w.WriteByte('\n')
+	for _, line := range s.live {
+		w.Write(line)
+		w.WriteByte('\n')
+	}
+
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "failed to write index")
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "failed to sync index")
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "failed to replace index")
+	}
+
+	if s.file != nil {
+		_ = s.file.Close()
+	}
+	s.file, s.garbage = f, 0
+
+	return nil
+}
+
+// ids returns the media IDs of the live records.
+func (s *store) ids() []string {
+	ids := make([]string, 0, len(s.live))
+	for id := range s.live {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func (s *store) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,10 @@
+package jobs
+
+import "github.com/katana-project/katana/internal/errors"
+
+var (
+	// ErrQueueFull is an error about too many jobs waiting for a worker.
+	ErrQueueFull = errors.WithCode(errors.New("job queue is full"), errors.CodeBadRequest)
+	// ErrQueueClosed is an error about queueing a job after the queue has been closed.
+	ErrQueueClosed = errors.WithCode(errors.New("job queue is closed"), errors.CodeNotReady)
+)
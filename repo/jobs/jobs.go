@@ -0,0 +1,528 @@
+package jobs
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/event"
+	"github.com/katana-project/katana/repo/media"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// queueSize is the maximum number of jobs waiting for a worker.
+const queueSize = 256
+
+// Type is a type of job operation.
+type Type string
+
+const (
+	// TypeRemux is the type of a job remuxing media (repo.Repository.Remux).
+	TypeRemux Type = "remux"
+	// TypeTranscode is the type of a job transcoding media (repo.Repository.Transcode).
+	TypeTranscode Type = "transcode"
+	// TypeScan is the type of a job scanning a repository for media (repo.MutableRepository.Scan).
+	TypeScan Type = "scan"
+	// TypeChecksum is the type of a job computing checksums of a repository's new and modified media (checksum.Catalog.Update).
+	TypeChecksum Type = "checksum"
+	// TypeVerify is the type of a job verifying checksums of a repository's media (checksum.Catalog.Verify).
+	TypeVerify Type = "verify"
+	// TypeBulk is the type of a job applying an operation to multiple media of a repository, outcomes are reported with ReportOutcome.
+	TypeBulk Type = "bulk"
+	// TypeProbe is the type of a job probing a repository's media that haven't been probed yet (repo.Repository.Probe).
+	TypeProbe Type = "probe"
+	// TypeRefresh is the type of a job refreshing the metadata of a repository's media due to be refreshed (refresh.Refresher.RefreshDue).
+	TypeRefresh Type = "refresh"
+	// TypeReplicate is the type of a job mirroring a repository of another server into a repository (replica.Replicator.Replicate).
+	TypeReplicate Type = "replicate"
+	// TypeArchive is the type of a job moving a media file to cold storage (repo.MutableRepository.Archive).
+	TypeArchive Type = "archive"
+	// TypeRestore is the type of a job moving a media file back from cold storage (repo.MutableRepository.Restore).
+	TypeRestore Type = "restore"
+	// TypePreview is the type of a job making a preview clip of media (repo.Repository.Preview).
+	TypePreview Type = "preview"
+	// TypeIdentify is the type of a job hashing the video frames of a repository's media (identify.Identifier.Hash).
+	TypeIdentify Type = "identify"
+	// TypeDASH is the type of a job transcoding the MPEG-DASH representations of media (repo.Repository.Transcode).
+	TypeDASH Type = "dash"
+	// TypeStoryboard is the type of a job making the trick-play thumbnail sprite sheets of media (repo.Repository.Storyboard).
+	TypeStoryboard Type = "storyboard"
+)
+
+// State is a job lifecycle state.
+type State string
+
+const (
+	// StateQueued is the state of a job waiting for a free worker.
+	StateQueued State = "queued"
+	// StateRunning is the state of a job being processed.
+	StateRunning State = "running"
+	// StateCompleted is the state of a successfully finished job.
+	StateCompleted State = "completed"
+	// StateFailed is the state of a job that finished with an error.
+	StateFailed State = "failed"
+	// StateCanceled is the state of a job canceled before finishing.
+	StateCanceled State = "canceled"
+)
+
+// Finished checks whether the state is terminal, i.e. the job won't change anymore.
+func (s State) Finished() bool {
+	return s == StateCompleted || s == StateFailed || s == StateCanceled
+}
+
+// Outcome is the per-media outcome of a job processing multiple media.
+type Outcome struct {
+	// Succeeded are the IDs of successfully processed media, in the order of being reported.
+	Succeeded []string
+	// Failed are the errors of media that failed to be processed, keyed by media ID.
+	Failed map[string]error
+}
+
+type outcomeKey struct{}
+
+// ReportOutcome reports the outcome of processing media to the job running the operation of the context, if there is one.
+func ReportOutcome(ctx context.Context, mediaId string, err error) {
+	if fn, ok := ctx.Value(outcomeKey{}).(func(string, error)); ok {
+		fn(mediaId, err)
+	}
+}
+
+// Func is a job operation, it should abort when the context is canceled and report progress with repo.ReportProgress.
+type Func func(ctx context.Context) (media.Media, error)
+
+// Job is a queued media operation.
+type Job struct {
+	id      string
+	type_   Type
+	repoId  string
+	mediaId string
+	fn      Func
+
+	mu       sync.RWMutex
+	state    State
+	progress repo.Progress
+	created  time.Time
+	started  time.Time
+	finished time.Time
+	result   media.Media
+	outcome  *Outcome // nil if no outcome has been reported
+	err      error
+	cancel   context.CancelFunc
+	notify   func(j *Job) // called on state changes, without mu held
+}
+
+// ID returns the job ID, a random UUID.
+func (j *Job) ID() string {
+	return j.id
+}
+
+// Type returns the job operation type.
+func (j *Job) Type() Type {
+	return j.type_
+}
+
+// RepoID returns the ID of the repository containing the processed media.
+func (j *Job) RepoID() string {
+	return j.repoId
+}
+
+// MediaID returns the ID of the processed media, empty for jobs processing a whole repository.
+func (j *Job) MediaID() string {
+	return j.mediaId
+}
+
+// State returns the current job state.
+func (j *Job) State() State {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return j.state
+}
+
+// Progress returns the last reported job progress.
+func (j *Job) Progress() repo.Progress {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return j.progress
+}
+
+// Created returns the time of the job being queued.
+func (j *Job) Created() time.Time {
+	return j.created
+}
+
+// Started returns the time of the job starting to run, zero if it hasn't started.
+func (j *Job) Started() time.Time {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return j.started
+}
+
+// Finished returns the time of the job finishing, zero if it hasn't finished.
+func (j *Job) Finished() time.Time {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return j.finished
+}
+
+// ETA returns the estimated remaining time of the job, false if it can't be estimated (yet).
+func (j *Job) ETA() (time.Duration, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if j.state != StateRunning {
+		return 0, j.state.Finished()
+	}
+
+	percent := j.progress.Percent()
+	if percent <= 0 {
+		return 0, false
+	}
+
+	elapsed := time.Since(j.started)
+	return time.Duration(float64(elapsed) * (100 - percent) / percent), true
+}
+
+// Result returns the resulting media of a completed job, nil otherwise.
+func (j *Job) Result() media.Media {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return j.result
+}
+
+// Outcome returns the per-media outcomes reported by the job so far (ReportOutcome), nil if it hasn't reported any.
+func (j *Job) Outcome() *Outcome {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if j.outcome == nil {
+		return nil
+	}
+
+	outcome := &Outcome{Succeeded: append([]string{}, j.outcome.Succeeded...), Failed: make(map[string]error, len(j.outcome.Failed))}
+	for id, err := range j.outcome.Failed {
+		outcome.Failed[id] = err
+	}
+
+	return outcome
+}
+
+// Err returns the error of a failed job, nil otherwise.
+func (j *Job) Err() error {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return j.err
+}
+
+// run processes the job, unless it has been canceled while queued.
+func (j *Job) run(ctx context.Context) {
+	j.mu.Lock()
+	if j.state != StateQueued {
+		j.mu.Unlock()
+		return
+	}
+
+	ctx, j.cancel = context.WithCancel(ctx)
+	j.state = StateRunning
+	j.started = time.Now()
+	j.mu.Unlock()
+	j.notify(j)
+
+	res, err := j.fn(context.WithValue(repo.WithProgress(ctx, j.report), outcomeKey{}, j.reportOutcome))
+
+	j.mu.Lock()
+	j.finished = time.Now()
+	switch {
+	case ctx.Err() != nil:
+		j.state = StateCanceled
+	case err != nil:
+		j.state = StateFailed
+		j.err = err
+	default:
+		j.state = StateCompleted
+		j.result = res
+		j.progress.Processed = j.progress.Total
+	}
+	j.cancel() // release the context
+	j.mu.Unlock()
+	j.notify(j)
+}
+
+func (j *Job) report(p repo.Progress) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.progress = p
+}
+
+func (j *Job) reportOutcome(mediaId string, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.outcome == nil {
+		j.outcome = &Outcome{Failed: make(map[string]error)}
+	}
+	if err != nil {
+		j.outcome.Failed[mediaId] = err
+	} else {
+		j.outcome.Succeeded = append(j.outcome.Succeeded, mediaId)
+	}
+}
+
+// stop cancels the job, returns false if it had already finished.
+func (j *Job) stop() bool {
+	j.mu.Lock()
+	switch j.state {
+	case StateQueued:
+		j.state = StateCanceled
+		j.finished = time.Now()
+		j.mu.Unlock()
+
+		j.notify(j)
+	case StateRunning:
+		j.cancel() // state is changed by the worker
+		j.mu.Unlock()
+	default:
+		j.mu.Unlock()
+		return false
+	}
+
+	return true
+}
+
+// expired checks whether the job has finished before the cutoff time.
+func (j *Job) expired(cutoff time.Time) bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return j.state.Finished() && j.finished.Before(cutoff)
+}
+
+// Queue is a queue of jobs processed by a fixed number of workers.
+type Queue struct {
+	retention time.Duration
+	bus       *event.Bus
+	logger    *zap.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	quit   chan struct{} // closed when workers should stop taking jobs
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+	jobs   map[string]*Job
+	queue  chan *Job
+}
+
+// NewQueue creates a job queue processing jobs with the specified number of workers (minimum 1).
+// Finished jobs are kept for the retention period, after which they're forgotten.
+// Job state changes are published to the event bus, can be nil.
+func NewQueue(workers int, retention time.Duration, bus *event.Bus, logger *zap.Logger) *Queue {
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &Queue{
+		retention: retention,
+		bus:       bus,
+		logger:    logger,
+		ctx:       ctx,
+		cancel:    cancel,
+		quit:      make(chan struct{}),
+		jobs:      make(map[string]*Job),
+		queue:     make(chan *Job, queueSize),
+	}
+
+	q.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+
+	return q
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-q.quit:
+			return
+		default:
+		}
+
+		select {
+		case <-q.quit:
+			return
+		case j := <-q.queue:
+			j.run(q.ctx)
+			if q.logger != nil {
+				q.log(j)
+			}
+		}
+	}
+}
+
+// notify publishes a state change of a job.
+func (q *Queue) notify(j *Job) {
+	q.bus.Publish(event.Event{
+		Type:     event.TypeJobUpdated,
+		RepoID:   j.repoId,
+		MediaID:  j.mediaId,
+		JobID:    j.id,
+		JobType:  string(j.type_),
+		JobState: string(j.State()),
+	})
+}
+
+func (q *Queue) log(j *Job) {
+	fields := []zap.Field{
+		zap.String("id", j.id),
+		zap.String("type", string(j.type_)),
+		zap.String("repo", j.repoId),
+		zap.String("media", j.mediaId),
+	}
+
+	switch j.State() {
+	case StateCompleted:
+		q.logger.Info("job completed", fields...)
+	case StateFailed:
+		q.logger.Error("job failed", append(fields, zap.Error(j.Err()))...)
+	case StateCanceled:
+		q.logger.Info("job canceled", append(fields, zap.Float64("percent", j.Progress().Percent()))...)
+	}
+}
+
+// Enqueue queues a job operation on media, or on a whole repository if mediaId is empty.
+// ErrQueueFull is returned if there are too many jobs waiting, ErrQueueClosed if the queue has been closed.
+func (q *Queue) Enqueue(type_ Type, repoId, mediaId string, fn Func) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil, ErrQueueClosed
+	}
+	q.prune()
+
+	j := &Job{
+		id:      uuid.NewString(),
+		type_:   type_,
+		repoId:  repoId,
+		mediaId: mediaId,
+		fn:      fn,
+		state:   StateQueued,
+		created: time.Now(),
+		notify:  q.notify,
+	}
+	if len(q.queue) == cap(q.queue) { // only sent to with mu held, so it can't fill up in the meantime
+		return nil, ErrQueueFull
+	}
+
+	q.jobs[j.id] = j
+	q.notify(j) // before a worker can pick it up and announce it running
+	q.queue <- j
+
+	return j, nil
+}
+
+// prune forgets finished jobs past the retention period, mu must be held.
+func (q *Queue) prune() {
+	cutoff := time.Now().Add(-q.retention)
+	for id, j := range q.jobs {
+		if j.expired(cutoff) {
+			delete(q.jobs, id)
+		}
+	}
+}
+
+// Get gets a job by its ID, returns nil if not found.
+func (q *Queue) Get(id string) *Job {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return q.jobs[id]
+}
+
+// Idle checks whether there are no queued or running jobs.
+func (q *Queue) Idle() bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	for _, j := range q.jobs {
+		if !j.State().Finished() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Cancel cancels a job by its ID, returns false if it wasn't found or it had already finished.
+func (q *Queue) Cancel(id string) bool {
+	j := q.Get(id)
+	if j == nil {
+		return false
+	}
+
+	return j.stop()
+}
+
+// Shutdown stops taking jobs and waits for the running ones to finish, until the context is done, they're canceled then.
+// Jobs that haven't started are canceled right away, jobs are only kept in memory.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.quit)
+	}
+	q.mu.Unlock()
+
+	for done := false; !done; { // cancel jobs that never got to a worker, no more are enqueued
+		select {
+		case j := <-q.queue:
+			if j.stop() && q.logger != nil {
+				q.log(j)
+			}
+		default:
+			done = true
+		}
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(stopped)
+	}()
+
+	if ctx.Err() == nil {
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			if q.logger != nil {
+				q.logger.Warn("timed out waiting for running jobs, canceling them")
+			}
+		}
+	}
+
+	q.cancel() // releases the context if all jobs have finished
+	<-stopped
+
+	return nil
+}
+
+// Close cancels all unfinished jobs and waits for the workers to stop.
+// Jobs are only kept in memory, canceled jobs are logged along with their progress.
+func (q *Queue) Close() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // don't wait for running jobs
+
+	return q.Shutdown(ctx)
+}
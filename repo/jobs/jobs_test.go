@@ -0,0 +1,230 @@
+package jobs
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"testing"
+	"time"
+)
+
+// wait polls a job until it finishes.
+func wait(t *testing.T, j *Job) State {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if state := j.State(); state.Finished() {
+			return state
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("job %s did not finish in time", j.ID())
+	return ""
+}
+
+func TestQueue(t *testing.T) {
+	q := NewQueue(1, time.Hour, nil, nil)
+	defer q.Close()
+
+	t.Run("Complete", func(t *testing.T) {
+		j, err := q.Enqueue(TypeRemux, "repo", "media", func(ctx context.Context) (media.Media, error) {
+			repo.ReportProgress(ctx, repo.Progress{Processed: 50, Total: 100, Written: 10})
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if state := wait(t, j); state != StateCompleted {
+			t.Errorf("expected state %s, got %s", StateCompleted, state)
+		}
+		if p := j.Progress(); p.Percent() != 100 || p.Written != 10 {
+			t.Errorf("unexpected progress %+v", p)
+		}
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		j, err := q.Enqueue(TypeTranscode, "repo", "media", func(_ context.Context) (media.Media, error) {
+			return nil, errors.New("test")
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if state := wait(t, j); state != StateFailed || j.Err() == nil {
+			t.Errorf("expected state %s with an error, got %s", StateFailed, state)
+		}
+	})
+
+	t.Run("Outcome", func(t *testing.T) {
+		j, err := q.Enqueue(TypeBulk, "repo", "", func(ctx context.Context) (media.Media, error) {
+			ReportOutcome(ctx, "a", nil)
+			ReportOutcome(ctx, "b", errors.New("test"))
+			ReportOutcome(ctx, "c", nil)
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if state := wait(t, j); state != StateCompleted {
+			t.Errorf("expected state %s, got %s", StateCompleted, state)
+		}
+		if o := j.Outcome(); o == nil || len(o.Succeeded) != 2 || o.Succeeded[1] != "c" || o.Failed["b"] == nil {
+			t.Errorf("unexpected outcome %+v", o)
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		started := make(chan struct{})
+		running, err := q.Enqueue(TypeRemux, "repo", "media", func(ctx context.Context) (media.Media, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		queued, err := q.Enqueue(TypeRemux, "repo", "media", func(_ context.Context) (media.Media, error) {
+			t.Error("canceled queued job was run")
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		<-started
+		if !q.Cancel(queued.ID()) || !q.Cancel(running.ID()) {
+			t.Fatal("failed to cancel jobs")
+		}
+
+		for _, j := range []*Job{running, queued} {
+			if state := wait(t, j); state != StateCanceled {
+				t.Errorf("expected state %s, got %s", StateCanceled, state)
+			}
+		}
+		if q.Cancel(running.ID()) {
+			t.Error("canceled a finished job")
+		}
+	})
+}
+
+func TestQueueShutdown(t *testing.T) {
+	t.Run("Drain", func(t *testing.T) {
+		q := NewQueue(1, time.Hour, nil, nil)
+
+		var (
+			started = make(chan struct{})
+			release = make(chan struct{})
+		)
+		running, err := q.Enqueue(TypeRemux, "repo", "media", func(ctx context.Context) (media.Media, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		queued, err := q.Enqueue(TypeRemux, "repo", "media", func(_ context.Context) (media.Media, error) {
+			t.Error("queued job was run after shutting down")
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		<-started
+		time.AfterFunc(50*time.Millisecond, func() { close(release) })
+		if err := q.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if state := running.State(); state != StateCompleted {
+			t.Errorf("expected running job state %s, got %s", StateCompleted, state)
+		}
+		if state := queued.State(); state != StateCanceled {
+			t.Errorf("expected queued job state %s, got %s", StateCanceled, state)
+		}
+		if _, err := q.Enqueue(TypeRemux, "repo", "media", nil); !errors.Is(err, ErrQueueClosed) {
+			t.Errorf("expected %v, got %v", ErrQueueClosed, err)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		q := NewQueue(1, time.Hour, nil, nil)
+
+		started := make(chan struct{})
+		running, err := q.Enqueue(TypeRemux, "repo", "media", func(ctx context.Context) (media.Media, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		<-started
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		if err := q.Shutdown(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if state := running.State(); state != StateCanceled {
+			t.Errorf("expected running job state %s, got %s", StateCanceled, state)
+		}
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	q := NewQueue(1, time.Hour, nil, nil)
+	defer q.Close()
+
+	r := NewRegistry(q)
+
+	release := make(chan struct{})
+	first, err := r.Enqueue("a", TypeRemux, "repo", "media", func(_ context.Context) (media.Media, error) {
+		<-release
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := r.Enqueue("a", TypeRemux, "repo", "media", func(_ context.Context) (media.Media, error) {
+		t.Error("job of a key with an unfinished job was run")
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != first {
+		t.Error("expected the unfinished job of the key")
+	}
+
+	close(release)
+	wait(t, first)
+	if j := r.Get("a"); j != first {
+		t.Error("expected the finished job to be kept for the retention period")
+	}
+
+	second, err := r.Enqueue("a", TypeRemux, "repo", "media", func(_ context.Context) (media.Media, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second == first {
+		t.Error("expected a new job once the last one of the key finished")
+	}
+	wait(t, second)
+
+	q.retention = 0 // finished jobs expire right away
+	if j := r.Get("a"); j != nil {
+		t.Error("expected the expired job to be forgotten")
+	}
+	if len(r.jobs) != 0 {
+		t.Errorf("expected no jobs left, got %d", len(r.jobs))
+	}
+}
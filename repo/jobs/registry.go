@@ -0,0 +1,58 @@
+package jobs
+
+import (
+	"sync"
+	"time"
+)
+
+// Registry is a set of keyed jobs of a queue, a job isn't queued for a key while the last one of it is unfinished.
+// Jobs are forgotten along with the queue forgetting them, once they've been finished for its retention period.
+type Registry struct {
+	queue *Queue
+
+	mu   sync.Mutex
+	jobs map[string]*Job // key -> last job
+}
+
+// NewRegistry creates a job registry of a queue.
+func NewRegistry(queue *Queue) *Registry {
+	return &Registry{queue: queue, jobs: make(map[string]*Job)}
+}
+
+// Enqueue queues a job like Queue.Enqueue under a key, the unfinished job of the key is returned instead if there's one.
+func (r *Registry) Enqueue(key string, type_ Type, repoId, mediaId string, fn Func) (*Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune()
+	if j, ok := r.jobs[key]; ok && !j.State().Finished() {
+		return j, nil
+	}
+
+	j, err := r.queue.Enqueue(type_, repoId, mediaId, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	r.jobs[key] = j
+	return j, nil
+}
+
+// Get returns the last job of a key, nil if there's none or it has been forgotten.
+func (r *Registry) Get(key string) *Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune()
+	return r.jobs[key]
+}
+
+// prune forgets jobs finished before the retention period of the queue, mu must be held.
+func (r *Registry) prune() {
+	cutoff := time.Now().Add(-r.queue.retention)
+	for key, j := range r.jobs {
+		if j.expired(cutoff) {
+			delete(r.jobs, key)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package repo
+
+import (
+	"context"
+	"go.uber.org/zap"
+)
+
+// loggerKey is the context key of a request-scoped logger.
+type loggerKey struct{}
+
+// WithLogger returns a copy of the context carrying a logger, which is used instead of the repository's logger
+// by repository operations that take a context (Repository.Remux, Repository.Transcode, Repository.ExtractSubtitle).
+// It's meant for loggers enriched with fields identifying the request that triggered the operation.
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// Logger returns the logger carried by the context, the fallback logger if there is none.
+func Logger(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok && logger != nil {
+		return logger
+	}
+
+	return fallback
+}
@@ -0,0 +1,61 @@
+package media
+
+import (
+	"golang.org/x/text/language"
+	"strings"
+	"unicode"
+)
+
+// AudioTrack is an embedded audio track of media.
+type AudioTrack struct {
+	// Codec is the FFmpeg name of the audio codec, such as "aac", "ac3" or "flac".
+	Codec string
+	// Language is the track language, language.Und if unknown.
+	Language language.Tag
+	// Title is the title of the track, such as "English 5.1" or "Commentary", empty if it has none.
+	Title string
+	// Stream is the stream index of the track.
+	Stream int
+}
+
+// languageWords are the lowercase language names and ISO 639-2 codes recognized by GuessLanguage, mapped to their languages.
+// Two-letter codes are left out, they're too often ordinary words ("it", "de", ...).
+var languageWords = map[string]language.Tag{
+	"english": language.English, "eng": language.English,
+	"french": language.French, "fre": language.French, "fra": language.French, "français": language.French, "francais": language.French,
+	"german": language.German, "ger": language.German, "deu": language.German, "deutsch": language.German,
+	"spanish": language.Spanish, "spa": language.Spanish, "español": language.Spanish, "espanol": language.Spanish, "castellano": language.Spanish,
+	"italian": language.Italian, "ita": language.Italian, "italiano": language.Italian,
+	"portuguese": language.Portuguese, "por": language.Portuguese, "português": language.Portuguese,
+	"dutch": language.Dutch, "dut": language.Dutch, "nld": language.Dutch,
+	"russian": language.Russian, "rus": language.Russian,
+	"polish": language.Polish, "pol": language.Polish,
+	"czech": language.Czech, "cze": language.Czech, "ces": language.Czech,
+	"swedish": language.Swedish, "swe": language.Swedish,
+	"japanese": language.Japanese, "jpn": language.Japanese,
+	"korean": language.Korean, "kor": language.Korean,
+	"chinese": language.Chinese, "chi": language.Chinese, "zho": language.Chinese, "mandarin": language.Chinese,
+	"hindi": language.Hindi, "hin": language.Hindi,
+	"arabic": language.Arabic, "ara": language.Arabic,
+	"turkish": language.Turkish, "tur": language.Turkish,
+}
+
+// GuessLanguage guesses the language of an audio track by the first language name or ISO 639-2 code among the words of a text,
+// such as its title ("English 5.1"), returns language.Und if there's none.
+// If upper is true, only uppercase words count, like the tags of release file names ("Movie.2020.FRENCH.1080p", "[JPN]"),
+// so that titles mentioning a language ("The French Connection") aren't mistaken for one.
+func GuessLanguage(s string, upper bool) language.Tag {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		if upper && strings.ToUpper(word) != word {
+			continue
+		}
+		if tag, ok := languageWords[strings.ToLower(word)]; ok {
+			return tag
+		}
+	}
+
+	return language.Und
+}
@@ -0,0 +1,29 @@
+package media
+
+import (
+	"golang.org/x/text/language"
+	"testing"
+)
+
+func TestGuessLanguage(t *testing.T) {
+	tests := []struct {
+		s        string
+		upper    bool
+		expected language.Tag
+	}{
+		{"English 5.1", false, language.English},
+		{"Commentary", false, language.Und},
+		{"jpn stereo", false, language.Japanese},
+		{"Castellano (Latino) / English", false, language.Spanish},
+		{"Movie.2020.FRENCH.1080p.mkv", true, language.French},
+		{"Movie (2020) [JPN].mkv", true, language.Japanese},
+		{"The French Connection (1971).mkv", true, language.Und},
+		{"It Follows (2014).mkv", true, language.Und},
+	}
+
+	for _, test := range tests {
+		if tag := GuessLanguage(test.s, test.upper); tag != test.expected {
+			t.Errorf("%q: expected %s, got %s", test.s, test.expected, tag)
+		}
+	}
+}
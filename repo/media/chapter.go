@@ -0,0 +1,12 @@
+package media
+
+import "time"
+
+// Chapter is a chapter marker of media.
+type Chapter struct {
+	// Title is the title of the chapter, empty if it has none.
+	Title string
+	// Start and End are the times the chapter starts and ends at.
+	Start time.Duration
+	End   time.Duration
+}
@@ -0,0 +1,237 @@
+package dash
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"io"
+	"os"
+	"time"
+)
+
+// Track is the layout of a fragmented MP4 file holding a single DASH representation.
+type Track struct {
+	// InitRange is the byte range of the initialization segment (the ftyp and moov boxes), inclusive.
+	InitRange [2]int64
+	// IndexRange is the byte range of the segment index (the sidx box), inclusive.
+	IndexRange [2]int64
+	// Duration is the total duration of the indexed subsegments.
+	Duration time.Duration
+	// Size is the file size in bytes.
+	Size int64
+
+	// Video is whether the track is a video track, audio otherwise.
+	Video bool
+	// Codecs is the RFC 6381 codec string of the track, such as "avc1.64001f" or "mp4a.40.2".
+	Codecs string
+	// Width and Height are the video dimensions in pixels, zero for audio.
+	Width, Height int
+}
+
+// box is an ISO base media file format box header.
+type box struct {
+	typ         string
+	start, size int64 // of the whole box, the header included
+	header      int64 // header size
+}
+
+func (b *box) end() int64 {
+	return b.start + b.size
+}
+
+// readBox reads the header of a box at an offset, boxes may not extend past the limit.
+func readBox(r io.ReaderAt, off, limit int64) (*box, error) {
+	var buf [16]byte
+	if _, err := r.ReadAt(buf[:8], off); err != nil {
+		return nil, errors.Wrap(err, "failed to read box header")
+	}
+
+	b := &box{typ: string(buf[4:8]), start: off, size: int64(binary.BigEndian.Uint32(buf[:4])), header: 8}
+	switch b.size {
+	case 0: // extends to the end
+		b.size = limit - off
+	case 1: // 64-bit size
+		if _, err := r.ReadAt(buf[8:16], off+8); err != nil {
+			return nil, errors.Wrap(err, "failed to read box size")
+		}
+
+		b.size, b.header = int64(binary.BigEndian.Uint64(buf[8:16])), 16
+	}
+	if b.size < b.header || b.end() > limit {
+		return nil, fmt.Errorf("invalid size of box %q at %d", b.typ, off)
+	}
+
+	return b, nil
+}
+
+// children reads the headers of the boxes in a byte range.
+func children(r io.ReaderAt, start, end int64) ([]*box, error) {
+	var boxes []*box
+	for off := start; off+8 <= end; {
+		b, err := readBox(r, off, end)
+		if err != nil {
+			return nil, err
+		}
+
+		boxes = append(boxes, b)
+		off = b.end()
+	}
+
+	return boxes, nil
+}
+
+// find finds the first box of a type among boxes, nil if there's none.
+func find(boxes []*box, typ string) *box {
+	for _, b := range boxes {
+		if b.typ == typ {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// path finds a box by the types of its ancestors in a box, nil if there's none.
+func path(r io.ReaderAt, parent *box, types ...string) (*box, error) {
+	b := parent
+	for _, typ := range types {
+		boxes, err := children(r, b.start+b.header, b.end())
+		if err != nil {
+			return nil, err
+		}
+		if b = find(boxes, typ); b == nil {
+			return nil, nil
+		}
+	}
+
+	return b, nil
+}
+
+// ReadTrack reads the layout of a fragmented MP4 file with a single track and a leading segment index.
+func ReadTrack(name string) (*Track, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open file")
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to stat file")
+	}
+
+	return readTrack(f, fi.Size())
+}
+
+func readTrack(r io.ReaderAt, size int64) (*Track, error) {
+	boxes, err := children(r, 0, size)
+	if err != nil {
+		return nil, err
+	}
+
+	moov, sidx := find(boxes, "moov"), find(boxes, "sidx")
+	if moov == nil || sidx == nil {
+		return nil, errors.New("file is not a fragmented MP4 with a segment index")
+	}
+
+	t := &Track{
+		InitRange:  [2]int64{0, moov.end() - 1},
+		IndexRange: [2]int64{sidx.start, sidx.end() - 1},
+		Size:       size,
+	}
+	if t.Duration, err = readIndexDuration(r, sidx); err != nil {
+		return nil, err
+	}
+	if err := readSampleEntry(r, moov, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// readIndexDuration sums the subsegment durations of a sidx box.
+func readIndexDuration(r io.ReaderAt, sidx *box) (time.Duration, error) {
+	buf := make([]byte, sidx.size-sidx.header)
+	if _, err := r.ReadAt(buf, sidx.start+sidx.header); err != nil {
+		return 0, errors.Wrap(err, "failed to read segment index")
+	}
+
+	off := 12 // version, flags, reference ID, timescale
+	if len(buf) < off {
+		return 0, errors.New("truncated segment index")
+	}
+	if buf[0] == 0 {
+		off += 8 // 32-bit earliest presentation time and first offset
+	} else {
+		off += 16
+	}
+	off += 2 // reserved
+	if len(buf) < off+2 {
+		return 0, errors.New("truncated segment index")
+	}
+
+	var (
+		timescale = binary.BigEndian.Uint32(buf[8:12])
+		count     = int(binary.BigEndian.Uint16(buf[off : off+2]))
+		total     uint64
+	)
+	off += 2
+	if timescale == 0 || len(buf) < off+count*12 {
+		return 0, errors.New("invalid segment index")
+	}
+	for i := 0; i < count; i++ {
+		total += uint64(binary.BigEndian.Uint32(buf[off+4 : off+8]))
+		off += 12
+	}
+
+	return time.Duration(total * uint64(time.Second) / uint64(timescale)), nil
+}
+
+// readSampleEntry reads the codec and dimensions of the first track from its sample description.
+func readSampleEntry(r io.ReaderAt, moov *box, t *Track) error {
+	stsd, err := path(r, moov, "trak", "mdia", "minf", "stbl", "stsd")
+	if err != nil {
+		return err
+	}
+	if stsd == nil {
+		return errors.New("missing sample description")
+	}
+
+	entry, err := readBox(r, stsd.start+stsd.header+8, stsd.end()) // after version, flags and entry count
+	if err != nil {
+		return err
+	}
+
+	switch entry.typ {
+	case "avc1", "avc3":
+		// visual sample entry: 6 reserved, data reference index, 16 pre-defined/reserved, width, height, ..., 78 bytes in total
+		var buf [4]byte
+		if _, err := r.ReadAt(buf[:], entry.start+entry.header+24); err != nil {
+			return errors.Wrap(err, "failed to read video dimensions")
+		}
+		t.Video, t.Width, t.Height = true, int(binary.BigEndian.Uint16(buf[:2])), int(binary.BigEndian.Uint16(buf[2:]))
+
+		boxes, err := children(r, entry.start+entry.header+78, entry.end())
+		if err != nil {
+			return err
+		}
+
+		t.Codecs = entry.typ
+		if avcC := find(boxes, "avcC"); avcC != nil { // configuration version, profile, compatibility, level
+			if _, err := r.ReadAt(buf[:], avcC.start+avcC.header); err != nil {
+				return errors.Wrap(err, "failed to read AVC configuration")
+			}
+			t.Codecs = fmt.Sprintf("%s.%02x%02x%02x", entry.typ, buf[1], buf[2], buf[3])
+		}
+	case "mp4a":
+		t.Codecs = "mp4a.40.2" // AAC-LC, the profile of the FFmpeg AAC encoder
+	case "Opus":
+		t.Codecs = "opus"
+	case "fLaC":
+		t.Codecs = "flac"
+	default:
+		return fmt.Errorf("unsupported sample entry %q", entry.typ)
+	}
+
+	return nil
+}
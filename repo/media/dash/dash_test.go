@@ -0,0 +1,111 @@
+package dash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mkbox(typ string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	b := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(b, typ...), body...)
+}
+
+// mkvideo makes a fragmented MP4 file with an H.264 track of 1280x720 and two 2 second subsegments.
+func mkvideo() []byte {
+	visual := make([]byte, 78)
+	binary.BigEndian.PutUint16(visual[24:], 1280)
+	binary.BigEndian.PutUint16(visual[26:], 720)
+	avc1 := mkbox("avc1", visual, mkbox("avcC", []byte{1, 0x64, 0x00, 0x1f}))
+	stsd := mkbox("stsd", []byte{0, 0, 0, 0, 0, 0, 0, 1}, avc1)
+	moov := mkbox("moov", mkbox("mvhd", make([]byte, 100)), mkbox("trak", mkbox("mdia", mkbox("minf", mkbox("stbl", stsd)))))
+
+	sidx := []byte{0, 0, 0, 0, 0, 0, 0, 1} // version 0, reference ID 1
+	sidx = binary.BigEndian.AppendUint32(sidx, 1000)
+	sidx = append(sidx, make([]byte, 8+2)...) // earliest presentation time, first offset, reserved
+	sidx = binary.BigEndian.AppendUint16(sidx, 2)
+	for i := 0; i < 2; i++ {
+		sidx = binary.BigEndian.AppendUint32(sidx, 100)
+		sidx = binary.BigEndian.AppendUint32(sidx, 2000)
+		sidx = binary.BigEndian.AppendUint32(sidx, 1<<31)
+	}
+
+	return bytes.Join([][]byte{
+		mkbox("ftyp", []byte("isom")),
+		moov,
+		mkbox("sidx", sidx),
+		mkbox("moof", make([]byte, 20)),
+		mkbox("mdat", make([]byte, 72)),
+	}, nil)
+}
+
+func TestReadTrack(t *testing.T) {
+	data := mkvideo()
+	name := filepath.Join(t.TempDir(), "video.mp4")
+	if err := os.WriteFile(name, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	track, err := ReadTrack(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ftypSize, moovSize := int64(12), int64(binary.BigEndian.Uint32(data[12:]))
+	if expected := [2]int64{0, ftypSize + moovSize - 1}; track.InitRange != expected {
+		t.Errorf("expected init range %v, got %v", expected, track.InitRange)
+	}
+	if track.IndexRange[0] != ftypSize+moovSize || track.IndexRange[1] != ftypSize+moovSize+8+48-1 {
+		t.Errorf("unexpected index range %v", track.IndexRange)
+	}
+	if track.Duration != 4*time.Second {
+		t.Errorf("expected duration 4s, got %s", track.Duration)
+	}
+	if !track.Video || track.Codecs != "avc1.64001f" || track.Width != 1280 || track.Height != 720 {
+		t.Errorf("unexpected track %+v", track)
+	}
+	if track.Size != int64(len(data)) {
+		t.Errorf("expected size %d, got %d", len(data), track.Size)
+	}
+
+	if _, err := readTrack(bytes.NewReader(data[:12]), 12); err == nil {
+		t.Error("expected error of a file without a segment index")
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteManifest(&buf, []*Representation{
+		{ID: "v720", URL: "v720", Track: &Track{Video: true, Codecs: "avc1.64001f", Width: 1280, Height: 720, Duration: 10 * time.Second, Size: 1250000, InitRange: [2]int64{0, 99}, IndexRange: [2]int64{100, 199}}},
+		{ID: "a", URL: "a", Track: &Track{Codecs: "mp4a.40.2", Duration: 10500 * time.Millisecond, Size: 10}},
+		{ID: "v480", URL: "v480", Track: &Track{Video: true, Codecs: "avc1.64001e", Width: 854, Height: 480, Duration: 10 * time.Second, Size: 10}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m mpd
+	if err := xml.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.MediaPresentationDuration != "PT10.500S" || m.Type != "static" {
+		t.Errorf("unexpected manifest %+v", m)
+	}
+	if len(m.AdaptationSets) != 2 || m.AdaptationSets[0].ContentType != "video" || len(m.AdaptationSets[0].Representations) != 2 {
+		t.Fatalf("expected video and audio adaptation sets, got %+v", m.AdaptationSets)
+	}
+
+	v := m.AdaptationSets[0].Representations[0]
+	if v.Bandwidth != 1000000 || v.SegmentBase.IndexRange != "100-199" || v.SegmentBase.Initialization.Range != "0-99" || v.BaseURL != "v720" {
+		t.Errorf("unexpected representation %+v", v)
+	}
+	if a := m.AdaptationSets[1].Representations[0]; strings.Contains(buf.String(), `width="0"`) || a.Codecs != "mp4a.40.2" {
+		t.Errorf("unexpected audio representation %+v", a)
+	}
+}
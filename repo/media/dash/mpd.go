@@ -0,0 +1,143 @@
+// Package dash writes MPEG-DASH manifests of fragmented MP4 files for adaptive streaming,
+// in the on-demand profile: each representation is a single file, its segments are byte ranges listed by its segment index.
+package dash
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"io"
+	"time"
+)
+
+// MIME is the MIME type of DASH manifests.
+const MIME = "application/dash+xml"
+
+// Representation is a track of media available for adaptive streaming.
+type Representation struct {
+	// ID is the representation ID, unique in the manifest.
+	ID string
+	// URL is the URL of the representation file, relative to the manifest.
+	URL string
+	// Track is the layout of the representation file.
+	Track *Track
+}
+
+// bandwidth returns the average bit rate of the representation in bits per second.
+func (r *Representation) bandwidth() int64 {
+	if r.Track.Duration <= 0 {
+		return 0
+	}
+
+	return int64(float64(r.Track.Size*8) / r.Track.Duration.Seconds())
+}
+
+type mpd struct {
+	XMLName                   xml.Name        `xml:"urn:mpeg:dash:schema:mpd:2011 MPD"`
+	Profiles                  string          `xml:"profiles,attr"`
+	Type                      string          `xml:"type,attr"`
+	MediaPresentationDuration string          `xml:"mediaPresentationDuration,attr"`
+	MinBufferTime             string          `xml:"minBufferTime,attr"`
+	AdaptationSets            []adaptationSet `xml:"Period>AdaptationSet"`
+}
+
+type adaptationSet struct {
+	ContentType             string           `xml:"contentType,attr"`
+	MIMEType                string           `xml:"mimeType,attr"`
+	SubsegmentAlignment     bool             `xml:"subsegmentAlignment,attr"`
+	SubsegmentStartsWithSAP int              `xml:"subsegmentStartsWithSAP,attr"`
+	Representations         []representation `xml:"Representation"`
+}
+
+type representation struct {
+	ID          string      `xml:"id,attr"`
+	Bandwidth   int64       `xml:"bandwidth,attr"`
+	Codecs      string      `xml:"codecs,attr"`
+	Width       int         `xml:"width,attr,omitempty"`
+	Height      int         `xml:"height,attr,omitempty"`
+	BaseURL     string      `xml:"BaseURL"`
+	SegmentBase segmentBase `xml:"SegmentBase"`
+}
+
+type segmentBase struct {
+	IndexRange     string         `xml:"indexRange,attr"`
+	Initialization initialization `xml:"Initialization"`
+}
+
+type initialization struct {
+	Range string `xml:"range,attr"`
+}
+
+// formatDuration formats a duration as an XML schema duration in seconds ("PT12.345S").
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("PT%.3fS", d.Seconds())
+}
+
+func formatRange(r [2]int64) string {
+	return fmt.Sprintf("%d-%d", r[0], r[1])
+}
+
+// WriteManifest writes a static manifest of representations, video ones are in one adaptation set, audio ones in another.
+// The presentation lasts as long as the longest representation.
+func WriteManifest(w io.Writer, reps []*Representation) error {
+	var (
+		sets     [2]*adaptationSet // video, audio
+		duration time.Duration
+	)
+	for _, r := range reps {
+		if r.Track.Duration > duration {
+			duration = r.Track.Duration
+		}
+
+		i, contentType := 1, "audio"
+		if r.Track.Video {
+			i, contentType = 0, "video"
+		}
+		if sets[i] == nil {
+			sets[i] = &adaptationSet{
+				ContentType:             contentType,
+				MIMEType:                contentType + "/mp4",
+				SubsegmentAlignment:     true,
+				SubsegmentStartsWithSAP: 1,
+			}
+		}
+
+		sets[i].Representations = append(sets[i].Representations, representation{
+			ID:        r.ID,
+			Bandwidth: r.bandwidth(),
+			Codecs:    r.Track.Codecs,
+			Width:     r.Track.Width,
+			Height:    r.Track.Height,
+			BaseURL:   r.URL,
+			SegmentBase: segmentBase{
+				IndexRange:     formatRange(r.Track.IndexRange),
+				Initialization: initialization{Range: formatRange(r.Track.InitRange)},
+			},
+		})
+	}
+
+	m := &mpd{
+		Profiles:                  "urn:mpeg:dash:profile:isoff-on-demand:2011",
+		Type:                      "static",
+		MediaPresentationDuration: formatDuration(duration),
+		MinBufferTime:             "PT2S",
+	}
+	for _, set := range sets {
+		if set != nil {
+			m.AdaptationSets = append(m.AdaptationSets, *set)
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errors.Wrap(err, "failed to write header")
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(m); err != nil {
+		return errors.Wrap(err, "failed to encode manifest")
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,71 @@
+package media
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ExtraKind is a kind of auxiliary file accompanying media.
+type ExtraKind string
+
+const (
+	// ExtraKindTheme is theme music, played by clients while browsing the media.
+	ExtraKindTheme ExtraKind = "theme"
+	// ExtraKindTrailer is a trailer video.
+	ExtraKindTrailer ExtraKind = "trailer"
+)
+
+// extraExtMIMEs are the extensions of theme music and trailer files mapped to their MIME types.
+var extraExtMIMEs = map[string]string{
+	".mp3":  "audio/mpeg",
+	".flac": "audio/flac",
+	".m4a":  "audio/mp4",
+	".ogg":  "audio/ogg",
+	".opus": "audio/ogg",
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".mkv":  "video/x-matroska",
+	".webm": "video/webm",
+	".mov":  "video/quicktime",
+	".avi":  "video/x-msvideo",
+}
+
+// Extra is an auxiliary file of media, such as theme music or a trailer, streamed as-is.
+type Extra struct {
+	// ID is the extra ID, unique within the media, alphanumeric, lowercase, non-blank ([a-z0-9-_]).
+	ID string
+	// Kind is the kind of the extra.
+	Kind ExtraKind
+	// Path is the absolute path of the extra file.
+	Path string
+	// MIME is the MIME type of the extra file.
+	MIME string
+}
+
+// ExtraMIME returns the MIME type of an extra file extension (including the leading dot), empty if it's not an audio or video file.
+func ExtraMIME(ext string) string {
+	return extraExtMIMEs[strings.ToLower(ext)]
+}
+
+// ExtraKindOf returns the kind of extra a file is by its name, empty if it's not an extra file.
+// Theme music is an audio file named "theme", trailers are video files named "trailer" or suffixed with "-trailer"
+// and any video files in a "trailers" directory.
+// Example: "theme.mp3", "trailer.mkv", "Movie (2020)-trailer.mp4", "trailers/Teaser.mp4"
+func ExtraKindOf(path string) ExtraKind {
+	var (
+		name = filepath.Base(path)
+		ext  = filepath.Ext(name)
+		stem = strings.ToLower(strings.TrimSuffix(name, ext))
+		typ  = ExtraMIME(ext)
+	)
+	switch {
+	case strings.HasPrefix(typ, "audio/") && stem == "theme":
+		return ExtraKindTheme
+	case strings.HasPrefix(typ, "video/") && (stem == "trailer" || strings.HasSuffix(stem, "-trailer")):
+		return ExtraKindTrailer
+	case strings.HasPrefix(typ, "video/") && strings.EqualFold(filepath.Base(filepath.Dir(path)), "trailers"):
+		return ExtraKindTrailer
+	}
+
+	return ""
+}
@@ -17,17 +17,90 @@ var (
 		MIME:      "video/x-matroska",
 		Extension: "mkv",
 	}
+	// FormatWebM is the WebM container format (.webm, video/webm).
+	FormatWebM = &Format{
+		Name:      "WebM",
+		MIME:      "video/webm",
+		Extension: "webm",
+	}
+	// FormatMPEGTS is the MPEG transport stream container format (.ts, video/mp2t).
+	FormatMPEGTS = &Format{
+		Name:      "MPEG-TS",
+		MIME:      "video/mp2t",
+		Extension: "ts",
+	}
+	// FormatOGG is the Ogg container format of video (.ogv, video/ogg).
+	FormatOGG = &Format{
+		Name:      "OGG",
+		MIME:      "video/ogg",
+		Extension: "ogv",
+	}
+	// FormatOGA is the Ogg container format of audio (.oga, audio/ogg).
+	FormatOGA = &Format{
+		Name:      "OGA",
+		MIME:      "audio/ogg",
+		Extension: "oga",
+	}
+	// FormatFLAC is the FLAC audio format (.flac, audio/flac).
+	FormatFLAC = &Format{
+		Name:      "FLAC",
+		MIME:      "audio/flac",
+		Extension: "flac",
+	}
+	// FormatMP3 is the MP3 audio format (.mp3, audio/mpeg).
+	FormatMP3 = &Format{
+		Name:      "MP3",
+		MIME:      "audio/mpeg",
+		Extension: "mp3",
+	}
+	// FormatM4A is the MP4 container format of audio (.m4a, audio/mp4).
+	FormatM4A = &Format{
+		Name:      "M4A",
+		MIME:      "audio/mp4",
+		Extension: "m4a",
+	}
+	// FormatAVI is the Audio Video Interleave container format (.avi, video/x-msvideo).
+	FormatAVI = &Format{
+		Name:      "AVI",
+		MIME:      "video/x-msvideo",
+		Extension: "avi",
+	}
+	// FormatMOV is the QuickTime container format (.mov, video/quicktime).
+	FormatMOV = &Format{
+		Name:      "MOV",
+		MIME:      "video/quicktime",
+		Extension: "mov",
+	}
 
-	formats = []*Format{FormatMP4, FormatMKV}
+	formats = []*Format{
+		FormatMP4, FormatMKV, FormatWebM, FormatMPEGTS, FormatOGG, FormatAVI, FormatMOV,
+		FormatOGA, FormatFLAC, FormatMP3, FormatM4A,
+	}
 
+	// formatsByMime are the formats by their MIME type, including other MIME types of them detected in files.
+	formatsByMime = map[string]*Format{
+		"application/ogg": FormatOGA,
+		"audio/mp3":       FormatMP3,
+		"audio/x-mpeg":    FormatMP3,
+		"audio/x-m4a":     FormatM4A,
+		"video/avi":       FormatAVI,
+		"video/msvideo":   FormatAVI,
+	}
+	// formatsByExt are the formats by their file extension, including other extensions of them.
+	formatsByExt = map[string]*Format{
+		"m2ts": FormatMPEGTS,
+		"mts":  FormatMPEGTS,
+		"ogg":  FormatOGA,
+		"opus": FormatOGA,
+	}
 	formatsByName = make(map[string]*Format, len(formats))
-	formatsByMime = make(map[string]*Format, len(formats))
 )
 
 func init() {
 	for _, format := range formats {
 		formatsByName[strings.ToLower(format.Name)] = format
 		formatsByMime[format.MIME] = format
+		formatsByExt[format.Extension] = format
 	}
 }
 
@@ -55,7 +128,7 @@ func FindFormat(name string) *Format {
 	return nil
 }
 
-// FindFormatMIME tries to find a format by its MIME type, returns nil if not found.
+// FindFormatMIME tries to find a format by its MIME type or its alias, returns nil if not found.
 func FindFormatMIME(mime string) *Format {
 	if format, ok := formatsByMime[mime]; ok {
 		return format
@@ -64,6 +137,16 @@ func FindFormatMIME(mime string) *Format {
 	return nil
 }
 
+// FindFormatExtension tries to find a format by a file extension, with or without the leading dot, returns nil if not found.
+// It's meant for files whose format can't be detected by their contents, such as MPEG transport streams.
+func FindFormatExtension(ext string) *Format {
+	if format, ok := formatsByExt[strings.ToLower(strings.TrimPrefix(ext, "."))]; ok {
+		return format
+	}
+
+	return nil
+}
+
 // FindUnsupportedFormat tries to find a format by its MIME type, creating an unsupported Format if not found.
 func FindUnsupportedFormat(mime, ext string) *Format {
 	if format := FindFormatMIME(mime); format != nil {
@@ -73,6 +156,11 @@ func FindUnsupportedFormat(mime, ext string) *Format {
 	return &Format{MIME: mime, Extension: ext}
 }
 
+// Audio returns whether the format is of audio only, remuxing video to it keeps only the audio streams.
+func (f *Format) Audio() bool {
+	return strings.HasPrefix(f.MIME, "audio/")
+}
+
 // Supported returns whether the format is known to available de/muxers.
 func (f *Format) Supported() bool {
 	_, ok := formatsByMime[f.MIME]
@@ -0,0 +1,36 @@
+package media
+
+import "testing"
+
+func TestFindFormat(t *testing.T) {
+	for mime, expected := range map[string]*Format{
+		"video/webm":      FormatWebM,
+		"audio/x-m4a":     FormatM4A,
+		"audio/mp4":       FormatM4A,
+		"audio/mp3":       FormatMP3,
+		"application/ogg": FormatOGA,
+		"video/avi":       FormatAVI,
+		"text/plain":      nil,
+	} {
+		if f := FindFormatMIME(mime); f != expected {
+			t.Errorf("expected format %v of %s, got %v", expected, mime, f)
+		}
+	}
+	for ext, expected := range map[string]*Format{
+		".ts":   FormatMPEGTS,
+		"M2TS":  FormatMPEGTS,
+		".opus": FormatOGA,
+		".mov":  FormatMOV,
+		".txt":  nil,
+	} {
+		if f := FindFormatExtension(ext); f != expected {
+			t.Errorf("expected format %v of %s, got %v", expected, ext, f)
+		}
+	}
+	if f := FindFormat("mpeg-ts"); f != FormatMPEGTS {
+		t.Errorf("expected MPEG-TS format by its name, got %v", f)
+	}
+	if !FormatFLAC.Audio() || FormatWebM.Audio() {
+		t.Error("expected only audio formats to be audio")
+	}
+}
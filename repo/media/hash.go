@@ -0,0 +1,65 @@
+package media
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/katana-project/katana/internal/errors"
+	"go.uber.org/multierr"
+	"io"
+	"os"
+)
+
+// hashPrefixSize is the number of leading bytes of a file read by Hash.
+const hashPrefixSize = 1024 * 1024
+
+// Hash makes a fingerprint of a file's contents, an MD5 hash of its first megabyte and its size.
+// It's cheap to compute for large files and stays the same when the file is moved or renamed.
+func Hash(path string) (_ string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open file")
+	}
+	defer func() {
+		if err0 := f.Close(); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to close file"))
+		}
+	}()
+
+	return hash(f)
+}
+
+// HashMedia makes a fingerprint of a media file's contents (see Hash), it's read with Opener if implemented.
+func HashMedia(m Media) (_ string, err error) {
+	opener, ok := m.(Opener)
+	if !ok {
+		return Hash(m.Path())
+	}
+
+	r, err := opener.Open()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open file")
+	}
+	defer func() {
+		if err0 := r.Close(); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to close file"))
+		}
+	}()
+
+	return hash(r)
+}
+
+func hash(r io.ReadSeeker) (string, error) {
+	h := md5.New()
+	if _, err := io.Copy(h, io.LimitReader(r, hashPrefixSize)); err != nil {
+		return "", errors.Wrap(err, "failed to read file")
+	}
+
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to seek file")
+	}
+
+	_ = binary.Write(h, binary.LittleEndian, size)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
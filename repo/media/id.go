@@ -0,0 +1,120 @@
+package media
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/katana-project/katana/repo/media/meta"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	// IDStrategySlug makes IDs from sanitized file names,
+	// falls back to IDStrategyHash for names with letters that can't be transliterated (non-Latin scripts).
+	// Example: "Babovřesky 3.avi" -> "babovresky-3-avi"
+	IDStrategySlug IDStrategy = slugID
+	// IDStrategyHash makes IDs from a truncated SHA-256 hash of the path relative to the repository root.
+	// Example: "Movies/Babovřesky 3.avi" -> "85b30757d3c60288"
+	IDStrategyHash IDStrategy = hashID
+	// IDStrategyUUID makes IDs from a name-based (version 5) UUID of the path relative to the repository root.
+	// Example: "Movies/Babovřesky 3.avi" -> "a8d998ed-c022-5169-8071-8b91ca39820d"
+	IDStrategyUUID IDStrategy = uuidID
+	// IDStrategyContent makes IDs from a truncated fingerprint of the file contents (see Hash),
+	// the IDs stay the same when the file is renamed or moved, but change when it's replaced.
+	// Example: "Movies/Babovřesky 3.avi" -> "0f343b0931126a20"
+	IDStrategyContent IDStrategy = contentID
+	// IDStrategyMeta makes IDs from the discovered metadata, falls back to IDStrategySlug for media with unknown metadata.
+	// Example: "Movies/Babovřesky 3.avi" -> "babovresky-3-2015", "Bocchi the Rock! 01.mkv" -> "bocchi-the-rock-2022-s01e01"
+	IDStrategyMeta IDStrategy = metaID
+
+	// idNamespace is the UUID namespace for IDStrategyUUID.
+	idNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/katana-project/katana"))
+)
+
+// IDStrategy makes a media ID from a file path relative to the repository root and the file's discovered metadata,
+// the file is located at filepath.Join(root, path).
+// The resulting IDs are stable for the same input, so media keeps its ID across repository scans,
+// but they may collide, the repository makes them unique.
+type IDStrategy func(root, path string, m meta.Metadata) (string, error)
+
+func slugID(_, path string, _ meta.Metadata) (string, error) {
+	return slug(path), nil
+}
+
+func slug(path string) string {
+	var (
+		name = filepath.Base(path)
+		stem = strings.TrimSuffix(name, filepath.Ext(name))
+	)
+	if strings.IndexFunc(Transliterate(stem), isNonLatinLetter) >= 0 { // would be stripped, making for colliding IDs
+		return pathHash(path)
+	}
+
+	return SanitizeID(name)
+}
+
+func isNonLatinLetter(r rune) bool {
+	return r > unicode.MaxASCII && unicode.IsLetter(r)
+}
+
+func hashID(_, path string, _ meta.Metadata) (string, error) {
+	return pathHash(path), nil
+}
+
+func pathHash(path string) string {
+	sum := sha256.Sum256([]byte(filepath.ToSlash(path)))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+func uuidID(_, path string, _ meta.Metadata) (string, error) {
+	return uuid.NewSHA1(idNamespace, []byte(filepath.ToSlash(path))).String(), nil
+}
+
+func contentID(root, path string, _ meta.Metadata) (string, error) {
+	sum, err := Hash(filepath.Join(root, path))
+	if err != nil {
+		return "", err
+	}
+
+	return sum[:16], nil
+}
+
+func metaID(_, path string, m meta.Metadata) (string, error) {
+	if m == nil || m.Title() == "" {
+		return slug(path), nil
+	}
+
+	var s string
+	switch m.Type() {
+	case meta.TypeMovie:
+		s = withYear(m.Title(), m.ReleaseDate())
+	case meta.TypeEpisode:
+		em, ok := m.(meta.EpisodeMetadata)
+		if !ok || em.Series() == nil {
+			return slug(path), nil
+		}
+
+		s = fmt.Sprintf("%s S%02dE%02d", withYear(em.Series().Title(), em.Series().ReleaseDate()), em.Season(), em.Episode())
+	default:
+		return slug(path), nil
+	}
+
+	if strings.IndexFunc(Transliterate(s), isNonLatinLetter) >= 0 { // would be stripped, making for colliding IDs
+		return slug(path), nil
+	}
+
+	return SanitizeID(s), nil
+}
+
+func withYear(title string, date time.Time) string {
+	if date.IsZero() {
+		return title
+	}
+
+	return fmt.Sprintf("%s %d", title, date.Year())
+}
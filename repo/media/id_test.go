@@ -0,0 +1,98 @@
+package media
+
+import (
+	"github.com/katana-project/katana/repo/media/meta"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSanitizeID(t *testing.T) {
+	tests := map[string]string{
+		"Test.mkv":                      "test-mkv",
+		"Babovřesky 3 (2015).avi":       "babovresky-3-2015-avi",
+		"Die Straße S01E01.mkv":         "die-strasse-s01e01-mkv",
+		"Łódź_Ærø.mp4":                  "lodz_aero-mp4",
+		"Chicago.Med.S01E10 cz.tit.avi": "chicago-med-s01e10-cz-tit-avi",
+	}
+
+	for s, expected := range tests {
+		if id := SanitizeID(s); id != expected {
+			t.Errorf("expected %s for %s, got %s", expected, s, id)
+		}
+	}
+}
+
+func TestIDStrategies(t *testing.T) {
+	const path = "Anime/ぼっち・ざ・ろっく！ 01.mkv"
+
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Anime"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, path), []byte("episode"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	strategies := map[string]IDStrategy{
+		"slug":    IDStrategySlug,
+		"hash":    IDStrategyHash,
+		"uuid":    IDStrategyUUID,
+		"content": IDStrategyContent,
+		"meta":    IDStrategyMeta,
+	}
+	for name, strategy := range strategies {
+		id, err := strategy(root, path, nil)
+		if err != nil {
+			t.Fatalf("failed to make %s ID: %v", name, err)
+		}
+		if !ValidID(id) {
+			t.Errorf("invalid %s ID %s", name, id)
+		}
+		if id0, _ := strategy(root, path, nil); id != id0 {
+			t.Errorf("unstable %s ID %s", name, id)
+		}
+	}
+
+	if id, _ := IDStrategySlug(root, "Movies/Babovřesky 3.avi", nil); id != "babovresky-3-avi" {
+		t.Errorf("unexpected slug ID %s", id)
+	}
+
+	contentId, _ := IDStrategyContent(root, path, nil)
+
+	moved := filepath.Join("Anime", "Bocchi the Rock", "01.mkv")
+	if err := os.MkdirAll(filepath.Join(root, filepath.Dir(moved)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(root, path), filepath.Join(root, moved)); err != nil {
+		t.Fatal(err)
+	}
+
+	if id, _ := IDStrategyContent(root, moved, nil); id != contentId {
+		t.Errorf("expected content ID %s after moving, got %s", contentId, id)
+	}
+}
+
+func TestIDStrategyMeta(t *testing.T) {
+	var (
+		released = time.Date(2022, 10, 9, 0, 0, 0, 0, time.UTC)
+		series   = meta.NewMovieOrSeriesMetadata(meta.NewMetadata(meta.TypeSeries, "Bocchi the Rock!", "", "", released, 0, nil), nil, nil, nil, nil)
+	)
+
+	tests := []struct {
+		path     string
+		meta     meta.Metadata
+		expected string
+	}{
+		{"Movies/Babovresky 3.avi", meta.NewMetadata(meta.TypeMovie, "Babovřesky 3", "", "", time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC), 0, nil), "babovresky-3-2015"},
+		{"Anime/S01/02.mkv", meta.NewEpisodeMetadata(meta.NewMetadata(meta.TypeEpisode, "Guitar Hero", "", "", released, 0, nil), series, 1, 2), "bocchi-the-rock-2022-s01e02"},
+		{"Anime/Episode 1.mkv", meta.NewMetadata(meta.TypeUnknown, "Episode 1", "", "", time.Time{}, 0, nil), "episode-1-mkv"},
+		{"Anime/Ep 01.mkv", meta.NewMetadata(meta.TypeMovie, "ぼっち・ざ・ろっく！", "", "", released, 0, nil), "ep-01-mkv"},
+	}
+	for _, test := range tests {
+		if id, _ := IDStrategyMeta("", test.path, test.meta); id != test.expected {
+			t.Errorf("expected %s for %s, got %s", test.expected, test.path, id)
+		}
+	}
+}
@@ -4,14 +4,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/katana-project/katana/repo/media/meta"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 	"regexp"
 	"strings"
+	"unicode"
 )
 
 var (
 	idPattern               = regexp.MustCompile("^[a-z0-9-_]+$")
 	idCharExclusivePattern  = regexp.MustCompile("[^a-z0-9-_]")
 	commonDelimiterReplacer = strings.NewReplacer(" ", "-", ".", "-")
+
+	// ligatureReplacer replaces Latin letters that don't decompose into a base letter and a combining mark.
+	ligatureReplacer = strings.NewReplacer(
+		"ß", "ss", "æ", "ae", "Æ", "AE", "œ", "oe", "Œ", "OE", "ø", "o", "Ø", "O",
+		"ł", "l", "Ł", "L", "đ", "d", "Đ", "D", "þ", "th", "Þ", "TH", "ı", "i",
+	)
 )
 
 // Media is a media file.
@@ -31,14 +41,27 @@ func ValidID(s string) bool {
 	return idPattern.MatchString(s)
 }
 
-// SanitizeID sanitizes a string to be usable as a media ID.
-// Example: "Test.mkv" -> "test-mkv"
+// SanitizeID sanitizes a string to be usable as a media ID, Latin diacritics are transliterated to their base letters.
+// Example: "Test.mkv" -> "test-mkv", "Babovřesky 3.avi" -> "babovresky-3-avi"
 func SanitizeID(s string) string {
-	spaceLessLowerCase := strings.ToLower(commonDelimiterReplacer.Replace(s))
+	spaceLessLowerCase := strings.ToLower(commonDelimiterReplacer.Replace(Transliterate(s)))
 
 	return idCharExclusivePattern.ReplaceAllLiteralString(spaceLessLowerCase, "")
 }
 
+// Transliterate strips diacritics from Latin letters and replaces common ligatures, other characters are kept as-is.
+// Example: "Babovřesky" -> "Baboversky", "Straße" -> "Strasse"
+func Transliterate(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+	result, _, err := transform.String(t, ligatureReplacer.Replace(s))
+	if err != nil {
+		return s // shouldn't be possible, the transformers don't fail on valid input
+	}
+
+	return result
+}
+
 // BasicMedia is a JSON-serializable generic Media.
 type BasicMedia struct {
 	ID_     string
@@ -0,0 +1,159 @@
+package anilist
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/repo/media/meta"
+	"golang.org/x/text/language"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	tagPattern          = regexp.MustCompile("<[^>]*>")
+	episodeTitlePattern = regexp.MustCompile(`(?i)^Episode (\d+) - (.+)$`)
+	// countryLanguages are the original languages of anime by their country of origin.
+	countryLanguages = map[string]language.Tag{"JP": language.Japanese, "KR": language.Korean, "CN": language.Chinese, "TW": language.Chinese}
+	// sequelFormats are the formats of sequels continuing a series, as opposed to movies and specials, by preference.
+	sequelFormats = map[string]int{"TV": 1, "TV_SHORT": 1, "ONA": 2, "OVA": 3}
+)
+
+// title returns the English title, the romanized one if there isn't any.
+func (a *anime) title() string {
+	if a.Title.English != nil && *a.Title.English != "" {
+		return *a.Title.English
+	}
+	if a.Title.Romaji != nil {
+		return *a.Title.Romaji
+	}
+
+	return ""
+}
+
+// originalTitle returns the native title, the romanized one if there isn't any.
+func (a *anime) originalTitle() string {
+	if a.Title.Native != nil && *a.Title.Native != "" {
+		return *a.Title.Native
+	}
+	if a.Title.Romaji != nil {
+		return *a.Title.Romaji
+	}
+
+	return ""
+}
+
+// overview returns the description stripped of HTML markup, which AniList includes even in plain text descriptions.
+func (a *anime) overview() string {
+	if a.Description == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(html.UnescapeString(tagPattern.ReplaceAllLiteralString(*a.Description, "")))
+}
+
+// sequel returns the ID of the anime's sequel in a series format (not a movie or a special), zero if there's none.
+// TV sequels are preferred over OVAs, which are sometimes sequels alongside the next season.
+func (a *anime) sequel() int {
+	var id, pref int
+	for _, edge := range a.Relations.Edges {
+		if edge.RelationType != "SEQUEL" || edge.Node.Type != "ANIME" {
+			continue
+		}
+		if p, ok := sequelFormats[edge.Node.Format]; ok && (pref == 0 || p < pref) {
+			id, pref = edge.Node.ID, p
+		}
+	}
+
+	return id
+}
+
+// metadata converts the anime to movie or series metadata, depending on its format.
+func (a *anime) metadata() meta.MovieOrSeriesMetadata {
+	type_ := meta.TypeSeries
+	if a.Format == "MOVIE" {
+		type_ = meta.TypeMovie
+	}
+
+	var rating float32
+	if a.AverageScore != nil {
+		rating = float32(*a.AverageScore) / 10
+	}
+
+	var images []meta.Image
+	if a.BannerImage != nil && *a.BannerImage != "" {
+		images = append(images, meta.NewImage(meta.ImageTypeBackdrop, *a.BannerImage, true, "Backdrop"))
+	}
+	if a.CoverImage.ExtraLarge != nil && *a.CoverImage.ExtraLarge != "" {
+		images = append(images, meta.NewImage(meta.ImageTypePoster, *a.CoverImage.ExtraLarge, true, "Poster"))
+	}
+
+	var cast []meta.CastMember
+	for _, edge := range a.Characters.Edges {
+		if len(edge.VoiceActors) == 0 {
+			continue
+		}
+
+		va := edge.VoiceActors[0]
+		var img meta.Image
+		if va.Image.Large != nil && *va.Image.Large != "" {
+			img = meta.NewImage(meta.ImageTypeAvatar, *va.Image.Large, true, va.Name.Full)
+		}
+		cast = append(cast, meta.NewCastMember(va.Name.Full, edge.Node.Name.Full, img))
+	}
+
+	var (
+		languages []language.Tag
+		countries []language.Region
+	)
+	if a.CountryOfOrigin != nil {
+		if region, err := language.ParseRegion(*a.CountryOfOrigin); err == nil {
+			countries = append(countries, region)
+		}
+		if lang, ok := countryLanguages[*a.CountryOfOrigin]; ok {
+			languages = append(languages, lang)
+		}
+	}
+
+	return meta.NewMovieOrSeriesMetadata(
+		meta.NewMetadata(type_, a.title(), a.originalTitle(), a.overview(), a.StartDate.time(), rating, images),
+		a.Genres,
+		cast,
+		languages,
+		countries,
+	)
+}
+
+// episodeTitle returns the title of an episode from the anime's streaming episodes, a generic one if it's not listed.
+// Example: "Episode 13 - A Place to Return To" -> "A Place to Return To"
+func (a *anime) episodeTitle(episode int) string {
+	for _, se := range a.StreamingEpisodes {
+		groups := episodeTitlePattern.FindStringSubmatch(se.Title)
+		if groups == nil {
+			continue
+		}
+		if n, err := strconv.Atoi(groups[1]); err == nil && n == episode {
+			return groups[2]
+		}
+	}
+
+	return fmt.Sprintf("Episode %d", episode)
+}
+
+// time returns the date as a time, unknown months and days are the first ones, zero if the year isn't known.
+func (fd fuzzyDate) time() time.Time {
+	if fd.Year == nil {
+		return time.Time{}
+	}
+
+	month, day := 1, 1
+	if fd.Month != nil && *fd.Month > 0 {
+		month = *fd.Month
+	}
+	if fd.Day != nil && *fd.Day > 0 {
+		day = *fd.Day
+	}
+
+	return time.Date(*fd.Year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
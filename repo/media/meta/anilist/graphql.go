@@ -0,0 +1,211 @@
+package anilist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"io"
+	"net/http"
+)
+
+// DefaultURL is the URL of AniList's GraphQL API.
+const DefaultURL = "https://graphql.anilist.co"
+
+// mediaFields are the fields of anime fetched for metadata.
+const mediaFields = `
+	id
+	format
+	episodes
+	title { romaji english native }
+	description(asHtml: false)
+	startDate { year month day }
+	averageScore
+	genres
+	countryOfOrigin
+	coverImage { extraLarge }
+	bannerImage
+	streamingEpisodes { title }
+	relations { edges { relationType node { id type format } } }
+	characters(sort: [ROLE, RELEVANCE], perPage: 25) {
+		edges {
+			node { name { full } }
+			voiceActors(language: JAPANESE) { name { full } image { large } }
+		}
+	}
+`
+
+const (
+	// searchQuery looks up the best match of a search query, format and formatNot restrict the format of anime.
+	searchQuery = `query ($search: String, $format: MediaFormat, $formatNot: MediaFormat) {
+	Media(search: $search, type: ANIME, format: $format, format_not: $formatNot) {` + mediaFields + `}
+}`
+	// mediaQuery looks up anime by its ID.
+	mediaQuery = `query ($id: Int) {
+	Media(id: $id, type: ANIME) {` + mediaFields + `}
+}`
+	// candidatesQuery looks up the matches of a search query, with only the fields needed for telling them apart.
+	candidatesQuery = `query ($search: String, $format: MediaFormat, $formatNot: MediaFormat) {
+	Page(perPage: 10) {
+		media(search: $search, type: ANIME, format: $format, format_not: $formatNot, sort: SEARCH_MATCH) {
+			id
+			format
+			title { romaji english native }
+			description(asHtml: false)
+			startDate { year month day }
+			coverImage { extraLarge }
+		}
+	}
+}`
+	// airingQuery looks up the air date of an episode.
+	airingQuery = `query ($id: Int, $episode: Int) {
+	AiringSchedule(mediaId: $id, episode: $episode) { airingAt }
+}`
+)
+
+// request is a GraphQL request.
+type request struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// response is a GraphQL response.
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+		Status  int    `json:"status"`
+	} `json:"errors"`
+}
+
+// errNotFound is an error about a missing result, AniList responds with a 404 status code to queries of a single missing item.
+var errNotFound = errors.New("not found")
+
+// query sends a GraphQL query and unmarshals its data into v, errNotFound is returned if there's no result.
+func (s *source) query(query string, variables map[string]interface{}, v interface{}) error {
+	body, err := json.Marshal(&request{Query: query, Variables: variables})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal query")
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to make request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return errNotFound
+	}
+
+	var res response
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil && resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return errors.Wrap(err, "failed to unmarshal response")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if len(res.Errors) > 0 {
+			return fmt.Errorf("non-2xx status code %d: %s", resp.StatusCode, res.Errors[0].Message)
+		}
+
+		return fmt.Errorf("non-2xx status code %d: %s", resp.StatusCode, resp.Status)
+	}
+	if len(res.Errors) > 0 {
+		if res.Errors[0].Status == http.StatusNotFound {
+			return errNotFound
+		}
+
+		return fmt.Errorf("query failed: %s", res.Errors[0].Message)
+	}
+
+	if err := json.Unmarshal(res.Data, v); err != nil {
+		return errors.Wrap(err, "failed to unmarshal response data")
+	}
+
+	return nil
+}
+
+// anime is an AniList media of the ANIME type.
+type anime struct {
+	ID       int    `json:"id"`
+	Format   string `json:"format"`   // TV, TV_SHORT, MOVIE, SPECIAL, OVA, ONA, MUSIC
+	Episodes *int   `json:"episodes"` // null if not known yet, such as for airing anime
+	Title    struct {
+		Romaji  *string `json:"romaji"`
+		English *string `json:"english"`
+		Native  *string `json:"native"`
+	} `json:"title"`
+	Description     *string   `json:"description"`
+	StartDate       fuzzyDate `json:"startDate"`
+	AverageScore    *int      `json:"averageScore"` // 0-100
+	Genres          []string  `json:"genres"`
+	CountryOfOrigin *string   `json:"countryOfOrigin"` // ISO 3166-1 alpha-2
+	CoverImage      struct {
+		ExtraLarge *string `json:"extraLarge"`
+	} `json:"coverImage"`
+	BannerImage       *string `json:"bannerImage"`
+	StreamingEpisodes []struct {
+		Title string `json:"title"`
+	} `json:"streamingEpisodes"`
+	Relations struct {
+		Edges []struct {
+			RelationType string `json:"relationType"`
+			Node         struct {
+				ID     int    `json:"id"`
+				Type   string `json:"type"`
+				Format string `json:"format"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"relations"`
+	Characters struct {
+		Edges []struct {
+			Node struct {
+				Name name `json:"name"`
+			} `json:"node"`
+			VoiceActors []struct {
+				Name  name `json:"name"`
+				Image struct {
+					Large *string `json:"large"`
+				} `json:"image"`
+			} `json:"voiceActors"`
+		} `json:"edges"`
+	} `json:"characters"`
+}
+
+type name struct {
+	Full string `json:"full"`
+}
+
+// fuzzyDate is a date of which only some parts may be known.
+type fuzzyDate struct {
+	Year  *int `json:"year"`
+	Month *int `json:"month"`
+	Day   *int `json:"day"`
+}
+
+// mediaResponse is the data of searchQuery and mediaQuery.
+type mediaResponse struct {
+	Media *anime `json:"Media"`
+}
+
+// pageResponse is the data of candidatesQuery.
+type pageResponse struct {
+	Page struct {
+		Media []*anime `json:"media"`
+	} `json:"Page"`
+}
+
+// airingResponse is the data of airingQuery.
+type airingResponse struct {
+	AiringSchedule *struct {
+		AiringAt int64 `json:"airingAt"` // Unix time
+	} `json:"AiringSchedule"`
+}
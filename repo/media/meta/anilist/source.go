@@ -0,0 +1,311 @@
+package anilist
+
+import (
+	"github.com/erni27/imcache"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media/meta"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// idPrefix is the prefix of AniList IDs in queries.
+	idPrefix = "anilist:"
+	// maxSequels is the maximum length of a followed chain of sequels, in case of cycles.
+	maxSequels = 32
+)
+
+var (
+	// absoluteEpisodePattern matches a file name or a query ending with an absolute episode number,
+	// optionally followed by a version and short tags, such as "Noragami Aragoto 13 CZ" or "One Piece - 1071v2 [1080p]".
+	absoluteEpisodePattern = regexp.MustCompile(`^(.+?)[\s._-]+(?i:E|EP|Episode\s?)?(\d{1,4})(?i:v\d)?(?:[\s._-]+[A-Z]{1,4})*$`)
+	resolutionPattern      = regexp.MustCompile(`(?i)\b(2160|1440|1080|720|480|360)[pi]\b`)
+	bracketPattern         = regexp.MustCompile(`\[[^]]*]|\([^)]*\)|\{[^}]*}`)
+	delimiterReplacer      = strings.NewReplacer("_", " ", ".", " ")
+)
+
+type source struct {
+	client *http.Client
+	url    string
+	exp    imcache.Expiration
+
+	animeCache   imcache.Cache[int, *anime]
+	searchCache  imcache.Cache[searchKey, int]        // search -> anime ID, zero if not found
+	episodeCache imcache.Cache[episodeKey, time.Time] // episode -> air date, zero if not known
+}
+
+type searchKey struct {
+	query string
+	type_ meta.Type
+}
+
+type episodeKey struct {
+	id, episode int
+}
+
+// NewSource creates a metadata source that resolves anime queries using AniList's GraphQL API at url.
+// Each season of an anime is a series of its own on AniList, episodes numbered absolutely across seasons
+// (such as "Noragami 15") are resolved by following the series' sequels.
+func NewSource(client *http.Client, url string, cacheExp imcache.Expiration) meta.Source {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if url == "" {
+		url = DefaultURL
+	}
+
+	return &source{client: client, url: url, exp: cacheExp}
+}
+
+// FromFile tries to resolve the file name as a query, a trailing number is presumed to be an absolute episode number.
+func (s *source) FromFile(path string) (meta.Metadata, error) {
+	var (
+		fileName       = filepath.Base(path)
+		nameWithoutExt = strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	)
+
+	query := resolutionPattern.ReplaceAllLiteralString(bracketPattern.ReplaceAllLiteralString(nameWithoutExt, ""), "")
+	query = strings.Join(strings.Fields(delimiterReplacer.Replace(query)), " ")
+
+	return s.FromQuery(&meta.Query{Query: query, Type: meta.TypeUnknown, Season: -1, Episode: -1})
+}
+
+// FromQuery tries to resolve the query using AniList's GraphQL API.
+// Queries with an absolute episode number and queries without an episode number ending with a number
+// are resolved as an absolute episode ("Noragami Aragoto 13"), the latter as a title if that fails. Queries with an AniList ID ("anilist:12345") are resolved directly.
+// Specials (season 0) are entries of their own on AniList, they're not resolved as episodes.
+func (s *source) FromQuery(query *meta.Query) (meta.Metadata, error) {
+	if query.ID != "" {
+		return s.fromID(query)
+	}
+
+	if query.Season >= 0 && query.Episode >= 0 {
+		if query.Season == 0 || query.Episode == 0 {
+			return nil, nil
+		}
+
+		return s.searchEpisode(query.Query, query.Season, query.Episode)
+	}
+	if query.Absolute > 0 {
+		return s.searchEpisode(query.Query, 1, query.Absolute)
+	}
+
+	if query.Type != meta.TypeMovie {
+		if groups := absoluteEpisodePattern.FindStringSubmatch(query.Query); groups != nil {
+			episode, _ := strconv.Atoi(groups[2]) // will never error
+			if episode > 0 {
+				m, err := s.searchEpisode(groups[1], 1, episode)
+				if err != nil || m != nil {
+					return m, err
+				}
+			}
+		}
+	}
+
+	id, err := s.search(query.Query, query.Type)
+	if err != nil || id == 0 {
+		return nil, err
+	}
+
+	a, err := s.fetchAnime(id)
+	if err != nil || a == nil {
+		return nil, err
+	}
+
+	return a.metadata(), nil
+}
+
+func (s *source) fromID(query *meta.Query) (meta.Metadata, error) {
+	rawId, ok := strings.CutPrefix(query.ID, idPrefix)
+	if !ok {
+		return nil, nil // another source's ID
+	}
+
+	id, err := strconv.Atoi(rawId)
+	if err != nil || id <= 0 {
+		return nil, &meta.ErrInvalidQuery{Query: query.ID, Type: query.Type}
+	}
+
+	a, err := s.fetchAnime(id)
+	if err != nil || a == nil {
+		return nil, err
+	}
+	if query.Type == meta.TypeEpisode {
+		if query.Season < 0 || query.Episode < 0 {
+			return nil, &meta.ErrInvalidQuery{Query: query.ID, Type: query.Type}
+		}
+		if query.Season == 0 || query.Episode == 0 {
+			return nil, nil
+		}
+
+		return s.resolveEpisode(a, query.Season, query.Episode)
+	}
+
+	return a.metadata(), nil
+}
+
+// Search looks up anime matching the query using AniList's GraphQL API, series include all formats but movies.
+// Queries with an AniList ID are not searched, they're resolved directly by FromQuery.
+func (s *source) Search(query *meta.Query) ([]meta.MetadataCandidate, error) {
+	if query.ID != "" || query.Query == "" {
+		return nil, nil
+	}
+
+	variables := map[string]interface{}{"search": query.Query}
+	switch query.Type {
+	case meta.TypeMovie:
+		variables["format"] = "MOVIE"
+	case meta.TypeSeries, meta.TypeEpisode:
+		variables["formatNot"] = "MOVIE"
+	}
+
+	var res pageResponse
+	if err := s.query(candidatesQuery, variables, &res); err != nil && !errors.Is(err, errNotFound) {
+		return nil, errors.Wrap(err, "failed to search anime")
+	}
+
+	candidates := make([]meta.MetadataCandidate, len(res.Page.Media))
+	for i, a := range res.Page.Media { // partial, not cached
+		titles := []string{a.title(), a.originalTitle()}
+		if a.Title.Romaji != nil { // searched by most users
+			titles = append(titles, *a.Title.Romaji)
+		}
+
+		candidates[i] = meta.NewCandidate(idPrefix+strconv.Itoa(a.ID), a.metadata(), meta.Score(query.Query, i, len(res.Page.Media), titles...))
+	}
+
+	return meta.SortCandidates(candidates), nil
+}
+
+// search looks up the ID of the best match of a query, returns zero if there's none.
+func (s *source) search(query string, type_ meta.Type) (int, error) {
+	key := searchKey{query: strings.ToLower(query), type_: type_}
+	if id, ok := s.searchCache.Get(key); ok {
+		return id, nil
+	}
+
+	variables := map[string]interface{}{"search": query}
+	switch type_ {
+	case meta.TypeMovie:
+		variables["format"] = "MOVIE"
+	case meta.TypeSeries, meta.TypeEpisode:
+		variables["formatNot"] = "MOVIE"
+	}
+
+	var res mediaResponse
+	if err := s.query(searchQuery, variables, &res); err != nil && !errors.Is(err, errNotFound) {
+		return 0, errors.Wrap(err, "failed to search anime")
+	}
+
+	var id int
+	if res.Media != nil {
+		id = res.Media.ID
+		s.animeCache.Set(id, res.Media, s.exp)
+	}
+
+	s.searchCache.Set(key, id, s.exp)
+	return id, nil
+}
+
+// fetchAnime fetches anime by its ID, returns nil if it's not found.
+func (s *source) fetchAnime(id int) (*anime, error) {
+	if a, ok := s.animeCache.Get(id); ok {
+		return a, nil
+	}
+
+	var res mediaResponse
+	if err := s.query(mediaQuery, map[string]interface{}{"id": id}, &res); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "failed to fetch anime")
+	}
+	if res.Media == nil {
+		return nil, nil
+	}
+
+	s.animeCache.Set(id, res.Media, s.exp)
+	return res.Media, nil
+}
+
+// searchEpisode looks up a series and resolves an episode of it, returns nil if either isn't found.
+func (s *source) searchEpisode(query string, season, episode int) (meta.Metadata, error) {
+	id, err := s.search(query, meta.TypeSeries)
+	if err != nil || id == 0 {
+		return nil, err
+	}
+
+	a, err := s.fetchAnime(id)
+	if err != nil || a == nil {
+		return nil, err
+	}
+
+	return s.resolveEpisode(a, season, episode)
+}
+
+// resolveEpisode resolves an episode of a season of anime, seasons after the first are its sequels.
+// Episode numbers past the episode count of a season are continued in its sequel (absolute numbering),
+// the returned episode is numbered relative to the sequel, which is the series of the episode.
+// Returns nil if the sequel isn't found.
+func (s *source) resolveEpisode(a *anime, season, episode int) (meta.Metadata, error) {
+	for i := 0; i < maxSequels && (season > 1 || a.Episodes != nil && *a.Episodes > 0 && episode > *a.Episodes); i++ {
+		if season > 1 {
+			season--
+		} else {
+			episode -= *a.Episodes
+		}
+
+		id := a.sequel()
+		if id == 0 {
+			return nil, nil
+		}
+
+		next, err := s.fetchAnime(id)
+		if err != nil || next == nil {
+			return nil, err
+		}
+		a = next
+	}
+	if season > 1 || a.Episodes != nil && *a.Episodes > 0 && episode > *a.Episodes {
+		return nil, nil // sequel cycle
+	}
+
+	airDate, err := s.fetchAirDate(a.ID, episode)
+	if err != nil {
+		return nil, err
+	}
+
+	title := a.episodeTitle(episode)
+	return meta.NewEpisodeMetadata(
+		meta.NewMetadata(meta.TypeEpisode, title, title, "", airDate, 0, nil),
+		a.metadata(),
+		1,
+		episode,
+	), nil
+}
+
+// fetchAirDate fetches the air date of an episode, returns a zero time if it's not known (AniList only knows recent ones).
+func (s *source) fetchAirDate(id, episode int) (time.Time, error) {
+	key := episodeKey{id: id, episode: episode}
+	if t, ok := s.episodeCache.Get(key); ok {
+		return t, nil
+	}
+
+	var res airingResponse
+	if err := s.query(airingQuery, map[string]interface{}{"id": id, "episode": episode}, &res); err != nil && !errors.Is(err, errNotFound) {
+		return time.Time{}, errors.Wrap(err, "failed to fetch episode air date")
+	}
+
+	var t time.Time
+	if res.AiringSchedule != nil {
+		t = time.Unix(res.AiringSchedule.AiringAt, 0).UTC()
+	}
+
+	s.episodeCache.Set(key, t, s.exp)
+	return t, nil
+}
@@ -0,0 +1,154 @@
+package anilist
+
+import (
+	"encoding/json"
+	"github.com/erni27/imcache"
+	"github.com/katana-project/katana/repo/media/meta"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testAnime are the anime known by the test API, by their ID.
+var testAnime = map[int]string{
+	1: `{"id": 1, "format": "TV", "episodes": 12, "title": {"romaji": "Noragami", "english": "Noragami", "native": "ノラガミ"},
+		"description": "Yato is a minor god.<br>", "startDate": {"year": 2014, "month": 1, "day": 5}, "averageScore": 75,
+		"genres": ["Action"], "countryOfOrigin": "JP",
+		"relations": {"edges": [{"relationType": "SEQUEL", "node": {"id": 3, "type": "ANIME", "format": "OVA"}}, {"relationType": "SEQUEL", "node": {"id": 2, "type": "ANIME", "format": "TV"}}]}}`,
+	2: `{"id": 2, "format": "TV", "episodes": 13, "title": {"romaji": "Noragami Aragoto", "english": "Noragami Aragoto"},
+		"startDate": {"year": 2015, "month": 10},
+		"streamingEpisodes": [{"title": "Episode 2 - A Plea for Help"}]}`,
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+
+		var media string
+		switch {
+		case strings.Contains(req.Query, "AiringSchedule"):
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"data": {"AiringSchedule": null}, "errors": [{"message": "Not Found.", "status": 404}]}`))
+			return
+		case strings.Contains(req.Query, "Page("): // ranked worse than AniList would, for checking the scoring
+			_, _ = w.Write([]byte(`{"data": {"Page": {"media": [` + testAnime[2] + `, ` + testAnime[1] + `]}}}`))
+			return
+		case req.Variables["id"] != nil:
+			media = testAnime[int(req.Variables["id"].(float64))]
+		case req.Variables["search"] != nil:
+			switch strings.ToLower(req.Variables["search"].(string)) {
+			case "noragami":
+				media = testAnime[1]
+			case "noragami aragoto":
+				media = testAnime[2]
+			}
+		}
+		if media == "" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"data": {"Media": null}, "errors": [{"message": "Not Found.", "status": 404}]}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data": {"Media": ` + media + `}}`))
+	}))
+}
+
+func TestSource_FromQuery(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	s := NewSource(srv.Client(), srv.URL, imcache.WithNoExpiration())
+	tests := []struct {
+		name                  string
+		query                 *meta.Query
+		series, title         string
+		season, episode       int
+		movieOrSeries, absent bool
+	}{
+		{name: "absolute", query: &meta.Query{Query: "Noragami 14", Season: -1, Episode: -1}, series: "Noragami Aragoto", title: "A Plea for Help", season: 1, episode: 2},
+		{name: "absolute number", query: &meta.Query{Query: "Noragami", Type: meta.TypeEpisode, Season: -1, Episode: -1, Absolute: 14}, series: "Noragami Aragoto", title: "A Plea for Help", season: 1, episode: 2},
+		{name: "absolute with tags", query: &meta.Query{Query: "Noragami Aragoto 13 CZ", Season: -1, Episode: -1}, series: "Noragami Aragoto", title: "Episode 13", season: 1, episode: 13},
+		{name: "season", query: &meta.Query{Query: "Noragami", Type: meta.TypeEpisode, Season: 2, Episode: 2}, series: "Noragami Aragoto", title: "A Plea for Help", season: 1, episode: 2},
+		{name: "series", query: &meta.Query{Query: "Noragami", Season: -1, Episode: -1}, series: "Noragami", movieOrSeries: true},
+		{name: "id", query: &meta.Query{ID: "anilist:2", Type: meta.TypeSeries, Season: -1, Episode: -1}, series: "Noragami Aragoto", movieOrSeries: true},
+		{name: "past last sequel", query: &meta.Query{Query: "Noragami", Type: meta.TypeEpisode, Season: 1, Episode: 30}, absent: true},
+		{name: "special", query: &meta.Query{Query: "Noragami", Type: meta.TypeEpisode, Season: 0, Episode: 1}, absent: true},
+		{name: "another source's ID", query: &meta.Query{ID: "tmdb:1", Type: meta.TypeSeries, Season: -1, Episode: -1}, absent: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := s.FromQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			switch {
+			case tt.absent:
+				if m != nil {
+					t.Errorf("expected no metadata, got %+v", m)
+				}
+			case tt.movieOrSeries:
+				msm, ok := m.(meta.MovieOrSeriesMetadata)
+				if !ok || msm.Type() != meta.TypeSeries || msm.Title() != tt.series {
+					t.Errorf("expected series %s, got %+v", tt.series, m)
+				}
+			default:
+				em, ok := m.(meta.EpisodeMetadata)
+				if !ok {
+					t.Fatalf("expected episode metadata, got %+v", m)
+				}
+				if em.Series().Title() != tt.series || em.Title() != tt.title || em.Season() != tt.season || em.Episode() != tt.episode {
+					t.Errorf(
+						"expected %s S%02dE%02d %s, got %s S%02dE%02d %s",
+						tt.series, tt.season, tt.episode, tt.title, em.Series().Title(), em.Season(), em.Episode(), em.Title(),
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestSource_Search(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	s := NewSource(srv.Client(), srv.URL, imcache.WithNoExpiration())
+	candidates, err := s.Search(&meta.Query{Query: "Noragami", Season: -1, Episode: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %d", len(candidates))
+	}
+
+	best := candidates[0]
+	if best.ID != "anilist:1" || best.Title != "Noragami" || best.Type != meta.TypeSeries {
+		t.Errorf("expected exact title match first, got %+v", best)
+	}
+	if best.Score <= candidates[1].Score {
+		t.Errorf("expected descending scores, got %f, %f", best.Score, candidates[1].Score)
+	}
+}
+
+func TestAnime_Metadata(t *testing.T) {
+	var a anime
+	if err := json.Unmarshal([]byte(testAnime[1]), &a); err != nil {
+		t.Fatal(err)
+	}
+
+	m := a.metadata()
+	if m.OriginalTitle() != "ノラガミ" || m.Overview() != "Yato is a minor god." || m.VoteRating() != 7.5 {
+		t.Errorf("unexpected metadata %+v", m)
+	}
+	if got := m.ReleaseDate().Format("2006-01-02"); got != "2014-01-05" {
+		t.Errorf("expected release date 2014-01-05, got %s", got)
+	}
+	if langs := m.Languages(); len(langs) != 1 || langs[0].String() != "ja" {
+		t.Errorf("expected Japanese, got %v", langs)
+	}
+}
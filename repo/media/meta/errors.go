@@ -1,6 +1,9 @@
 package meta
 
-import "fmt"
+import (
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+)
 
 // ErrInvalidQuery is an error about an invalid metadata query, most likely missing/unexpected data.
 type ErrInvalidQuery struct {
@@ -14,3 +17,8 @@ type ErrInvalidQuery struct {
 func (eiq *ErrInvalidQuery) Error() string {
 	return fmt.Sprintf("invalid metadata query %s of type %d", eiq.Query, eiq.Type)
 }
+
+// Code returns the error code (errors.CodeBadRequest).
+func (eiq *ErrInvalidQuery) Code() errors.Code {
+	return errors.CodeBadRequest
+}
@@ -0,0 +1,40 @@
+package meta
+
+// GallerySource is a Source that knows more images of movies and series than their metadata includes, for artwork selection.
+type GallerySource interface {
+	Source
+
+	// Gallery returns all backdrops and posters of a movie or series, sorted by popularity.
+	// The movie or series is looked up by its title and release year, nil is returned if it's not found.
+	Gallery(m Metadata) ([]Image, error)
+}
+
+// Gallery returns all images of a movie or series from a source (GallerySource.Gallery),
+// returns nil if the source doesn't know more images.
+func Gallery(source Source, m Metadata) ([]Image, error) {
+	if gs, ok := source.(GallerySource); ok {
+		return gs.Gallery(m)
+	}
+
+	return nil, nil
+}
+
+// Gallery returns all images of a movie or series from the first source that knows them, may return nil.
+func (cs *compositeSource) Gallery(m Metadata) ([]Image, error) {
+	for _, source := range cs.sources {
+		images, err := Gallery(source, m)
+		if err != nil {
+			return nil, err
+		}
+		if images != nil {
+			return images, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Gallery returns all images of a movie or series from the delegate source, may return nil.
+func (fas *fileAnalysisSource) Gallery(m Metadata) ([]Image, error) {
+	return Gallery(fas.Source, m)
+}
@@ -75,3 +75,37 @@ func (bi *BasicImage) Remote() bool {
 func (bi *BasicImage) Description() string {
 	return bi.Description_
 }
+
+// WithImage returns a copy of metadata with an image replacing its images of the same type, such as to choose a preferred poster.
+// The images of the series are replaced for episodes, metadata is converted to its Basic* variant.
+func WithImage(m Metadata, i Image) Metadata {
+	switch metaVariant := m.(type) {
+	case EpisodeMetadata:
+		bem := *NewBasicEpisodeMetadata(metaVariant)
+		if series := metaVariant.Series(); series != nil {
+			bem.Series_ = WithImage(series, i).(*BasicMovieOrSeriesMetadata)
+		}
+
+		return &bem
+	case MovieOrSeriesMetadata:
+		bmsm := *NewBasicMovieOrSeriesMetadata(metaVariant)
+		bmsm.BasicMetadata = withImage(bmsm.BasicMetadata, i)
+
+		return &bmsm
+	}
+
+	return withImage(NewBasicMetadata(m), i)
+}
+
+// withImage returns a copy of basic metadata with an image replacing its images of the same type, listed first.
+func withImage(bm *BasicMetadata, i Image) *BasicMetadata {
+	bm0 := *bm
+	bm0.Images_ = []*BasicImage{NewBasicImage(i)}
+	for _, bi := range bm.Images_ {
+		if bi.Type_ != i.Type() {
+			bm0.Images_ = append(bm0.Images_, bi)
+		}
+	}
+
+	return &bm0
+}
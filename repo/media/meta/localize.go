@@ -0,0 +1,38 @@
+package meta
+
+import "golang.org/x/text/language"
+
+// GenreLocalizer is a Source that can translate the genre names of its metadata to other languages.
+type GenreLocalizer interface {
+	Source
+
+	// LocalizeGenres translates genre names of movies or series (the type) to a language, names it doesn't know are kept as-is.
+	LocalizeGenres(genres []string, type_ Type, lang language.Tag) ([]string, error)
+}
+
+// LocalizeGenres translates genre names with a source (GenreLocalizer.LocalizeGenres),
+// returns them as-is if the source can't translate them or the language is undetermined.
+func LocalizeGenres(source Source, genres []string, type_ Type, lang language.Tag) ([]string, error) {
+	if gl, ok := source.(GenreLocalizer); ok && len(genres) > 0 && lang != language.Und {
+		return gl.LocalizeGenres(genres, type_, lang)
+	}
+
+	return genres, nil
+}
+
+// LocalizeGenres translates genre names with each source in turn, each translates the names it knows.
+func (cs *compositeSource) LocalizeGenres(genres []string, type_ Type, lang language.Tag) ([]string, error) {
+	for _, source := range cs.sources {
+		var err error
+		if genres, err = LocalizeGenres(source, genres, type_, lang); err != nil {
+			return nil, err
+		}
+	}
+
+	return genres, nil
+}
+
+// LocalizeGenres translates genre names with the delegate source.
+func (fas *fileAnalysisSource) LocalizeGenres(genres []string, type_ Type, lang language.Tag) ([]string, error) {
+	return LocalizeGenres(fas.Source, genres, type_, lang)
+}
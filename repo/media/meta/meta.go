@@ -45,6 +45,7 @@ type BasicMetadata struct {
 	ReleaseDate_   time.Time     `json:"release_date"`
 	VoteRating_    float32       `json:"vote_rating"`
 	Images_        []*BasicImage `json:"images"`
+	Confidence_    float32       `json:"confidence,omitempty"`
 }
 
 // NewMetadata creates a Metadata with set values.
@@ -90,6 +91,7 @@ func NewBasicMetadata(m Metadata) *BasicMetadata {
 		ReleaseDate_:   m.ReleaseDate(),
 		VoteRating_:    m.VoteRating(),
 		Images_:        images0,
+		Confidence_:    Confidence(m),
 	}
 }
 
@@ -119,3 +121,41 @@ func (bm *BasicMetadata) Images() []Image {
 
 	return images
 }
+func (bm *BasicMetadata) Confidence() float32 {
+	return bm.Confidence_
+}
+
+// Confidence returns the confidence of metadata being of the media it was resolved for from its file, between 0 and 1.
+// Zero means it wasn't scored, such as of metadata resolved by an ID or from a source that doesn't search.
+func Confidence(m Metadata) float32 {
+	if c, ok := m.(interface{ Confidence() float32 }); ok {
+		return c.Confidence()
+	}
+
+	return 0
+}
+
+// WithConfidence returns a copy of metadata with its match confidence (see Confidence) replaced.
+func WithConfidence(m Metadata, confidence float32) Metadata {
+	switch metaVariant := m.(type) {
+	case EpisodeMetadata:
+		bem := *NewBasicEpisodeMetadata(metaVariant)
+		bm := *bem.BasicMetadata
+		bm.Confidence_ = confidence
+		bem.BasicMetadata = &bm
+
+		return &bem
+	case MovieOrSeriesMetadata:
+		bmsm := *NewBasicMovieOrSeriesMetadata(metaVariant)
+		bm := *bmsm.BasicMetadata
+		bm.Confidence_ = confidence
+		bmsm.BasicMetadata = &bm
+
+		return &bmsm
+	}
+
+	bm := *NewBasicMetadata(m)
+	bm.Confidence_ = confidence
+
+	return &bm
+}
@@ -139,3 +139,24 @@ func (bmsm *BasicMovieOrSeriesMetadata) Countries() []language.Region {
 
 	return countries
 }
+
+// WithGenres returns a copy of movie or series metadata with its genres replaced, episodes get the genres of their series replaced.
+// Other metadata has no genres, it's returned as-is.
+func WithGenres(m Metadata, genres []string) Metadata {
+	switch metaVariant := m.(type) {
+	case EpisodeMetadata:
+		bem := *NewBasicEpisodeMetadata(metaVariant)
+		if series := metaVariant.Series(); series != nil {
+			bem.Series_ = WithGenres(series, genres).(*BasicMovieOrSeriesMetadata)
+		}
+
+		return &bem
+	case MovieOrSeriesMetadata:
+		bmsm := *NewBasicMovieOrSeriesMetadata(metaVariant)
+		bmsm.Genres_ = append([]string{}, genres...)
+
+		return &bmsm
+	}
+
+	return m
+}
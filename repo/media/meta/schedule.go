@@ -0,0 +1,54 @@
+package meta
+
+import "time"
+
+// AiringEpisode is an episode of a series and its air date, it may not be present in any repository.
+type AiringEpisode struct {
+	// Title is the episode title, can be empty if it's not known yet.
+	Title string
+	// Season is the season number, such as 1.
+	Season int
+	// Episode is the episode number, such as 1.
+	Episode int
+	// AirDate is the date the episode airs or aired on, in UTC.
+	AirDate time.Time
+}
+
+// ScheduleSource is a Source that knows the air dates of series' episodes, including episodes that haven't aired yet.
+type ScheduleSource interface {
+	Source
+
+	// Schedule returns the episodes of a series airing in a date range, inclusive, sorted by air date.
+	// The series is looked up by its title and release year, nil is returned if it's not found, an empty slice if nothing airs.
+	Schedule(series Metadata, from, to time.Time) ([]*AiringEpisode, error)
+}
+
+// Schedule returns the episodes of a series airing in a date range from a source (ScheduleSource.Schedule),
+// returns nil if the source doesn't know air dates.
+func Schedule(source Source, series Metadata, from, to time.Time) ([]*AiringEpisode, error) {
+	if ss, ok := source.(ScheduleSource); ok {
+		return ss.Schedule(series, from, to)
+	}
+
+	return nil, nil
+}
+
+// Schedule returns the episodes of a series airing in a date range from the first source that knows them, may return nil.
+func (cs *compositeSource) Schedule(series Metadata, from, to time.Time) ([]*AiringEpisode, error) {
+	for _, source := range cs.sources {
+		episodes, err := Schedule(source, series, from, to)
+		if err != nil {
+			return nil, err
+		}
+		if episodes != nil {
+			return episodes, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Schedule returns the episodes of a series airing in a date range from the delegate source, may return nil.
+func (fas *fileAnalysisSource) Schedule(series Metadata, from, to time.Time) ([]*AiringEpisode, error) {
+	return Schedule(fas.Source, series, from, to)
+}
@@ -0,0 +1,166 @@
+package meta
+
+import (
+	"golang.org/x/exp/slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxCandidates is the maximum number of candidates returned by a search of a source.
+const maxCandidates = 10
+
+// LowConfidence is the match confidence (see Confidence) below which metadata is presumed to be of other media.
+const LowConfidence float32 = 0.5
+
+// MetadataCandidate is a possible match of a search query, for letting the user pick the right one before re-matching media.
+type MetadataCandidate struct {
+	// ID is the source-prefixed ID of the movie or series ("tmdb:12345"), usable in a Query to resolve it.
+	ID string
+	// Type is the type of the movie or series, TypeMovie or TypeSeries.
+	Type Type
+	// Title is the title, such as "Bocchi the Rock!".
+	Title string
+	// OriginalTitle is the title as in the original release, such as "ぼっち・ざ・ろっく！".
+	OriginalTitle string
+	// Overview is the plot overview.
+	Overview string
+	// ReleaseDate is the date of release, zero if it's not known.
+	ReleaseDate time.Time
+	// Poster is the poster image, nil if there's none.
+	Poster Image
+	// Score is the likelihood of the candidate being the searched one, between 0 and 1.
+	Score float32
+}
+
+// NewCandidate creates a candidate from the metadata of a movie or series, the poster is its first poster image.
+func NewCandidate(id string, m Metadata, score float32) MetadataCandidate {
+	c := MetadataCandidate{
+		ID:            id,
+		Type:          m.Type(),
+		Title:         m.Title(),
+		OriginalTitle: m.OriginalTitle(),
+		Overview:      m.Overview(),
+		ReleaseDate:   m.ReleaseDate(),
+		Score:         score,
+	}
+	for _, img := range m.Images() {
+		if img.Type() == ImageTypePoster {
+			c.Poster = img
+			break
+		}
+	}
+
+	return c
+}
+
+// Score scores a search result by the similarity of its titles to the query and its rank in the results of the source,
+// results ranked first by the source are more likely to be the searched one if their titles are alike.
+func Score(query string, rank, total int, titles ...string) float32 {
+	var similarity float32
+	for _, title := range titles {
+		if s := titleSimilarity(query, title); s > similarity {
+			similarity = s
+		}
+	}
+
+	var position float32 = 1
+	if total > 1 {
+		position = 1 - float32(rank)/float32(total)
+	}
+
+	return 0.8*similarity + 0.2*position
+}
+
+// matchConfidence scores metadata resolved for a query by the similarity of its titles to the query,
+// the titles of the series for episodes, it's lowered if the release year is off by more than a year from the queried one.
+func matchConfidence(query *Query, m Metadata) float32 {
+	titled := m
+	if em, ok := m.(EpisodeMetadata); ok && em.Series() != nil {
+		titled = em.Series()
+	}
+
+	confidence := Score(query.Query, 0, 1, titled.Title(), titled.OriginalTitle())
+	if year := titled.ReleaseDate().Year(); query.Year > 0 && (year < query.Year-1 || year > query.Year+1) {
+		confidence *= 0.75
+	}
+
+	return confidence
+}
+
+// titleSimilarity returns the Dice coefficient of the words of a query and a title, ignoring case and punctuation, 1 if they're equal.
+func titleSimilarity(query, title string) float32 {
+	qw, tw := words(query), words(title)
+	if len(qw) == 0 || len(tw) == 0 {
+		return 0
+	}
+	if slices.Equal(qw, tw) {
+		return 1
+	}
+
+	counts := make(map[string]int, len(qw))
+	for _, w := range qw {
+		counts[w]++
+	}
+
+	var common int
+	for _, w := range tw {
+		if counts[w] > 0 {
+			counts[w]--
+			common++
+		}
+	}
+
+	return float32(2*common) / float32(len(qw)+len(tw))
+}
+
+// words splits a string into lowercase words, separated by anything but letters and digits.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// SortCandidates sorts candidates by their score, descending, and cuts them to the maximum number of candidates.
+func SortCandidates(candidates []MetadataCandidate) []MetadataCandidate {
+	slices.SortStableFunc(candidates, func(a, b MetadataCandidate) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+
+		return 0
+	})
+	if len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+
+	return candidates
+}
+
+// Search always returns nil.
+func (ds *dummySource) Search(_ *Query) ([]MetadataCandidate, error) {
+	return nil, nil
+}
+
+// Search always returns nil, literal metadata isn't a match of anything.
+func (lms *literalSource) Search(_ *Query) ([]MetadataCandidate, error) {
+	return nil, nil
+}
+
+// Search collects the candidates of all sources, sorted by their score.
+func (cs *compositeSource) Search(query *Query) ([]MetadataCandidate, error) {
+	var candidates []MetadataCandidate
+	for _, source := range cs.sources {
+		c, err := source.Search(query)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates = append(candidates, c...)
+	}
+
+	return SortCandidates(candidates), nil
+}
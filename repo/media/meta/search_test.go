@@ -0,0 +1,43 @@
+package meta
+
+import "testing"
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		rank, total   int
+		titles        []string
+		better, worse float32 // exclusive bounds
+	}{
+		{name: "exact", query: "Bocchi the Rock!", rank: 0, total: 1, titles: []string{"Bocchi the Rock!"}, better: 0.99, worse: 1.01},
+		{name: "case and punctuation", query: "bocchi the rock", rank: 1, total: 2, titles: []string{"Bocchi the Rock!"}, better: 0.89, worse: 0.91},
+		{name: "original title", query: "ぼっち ざ ろっく", rank: 0, total: 1, titles: []string{"Bocchi the Rock!", "ぼっち・ざ・ろっく！"}, better: 0.99, worse: 1.01},
+		{name: "partial", query: "Bocchi", rank: 0, total: 2, titles: []string{"Bocchi the Rock!"}, better: 0.59, worse: 0.61},
+		{name: "unrelated", query: "Noragami", rank: 1, total: 2, titles: []string{"Bocchi the Rock!"}, better: 0.09, worse: 0.11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if s := Score(tt.query, tt.rank, tt.total, tt.titles...); s <= tt.better || s >= tt.worse {
+				t.Errorf("expected score between %f and %f, got %f", tt.better, tt.worse, s)
+			}
+		})
+	}
+}
+
+func TestSortCandidates(t *testing.T) {
+	var candidates []MetadataCandidate
+	for i := 0; i < maxCandidates+5; i++ {
+		candidates = append(candidates, MetadataCandidate{ID: "test", Score: float32(i) / 20})
+	}
+
+	sorted := SortCandidates(candidates)
+	if len(sorted) != maxCandidates {
+		t.Fatalf("expected %d candidates, got %d", maxCandidates, len(sorted))
+	}
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i-1].Score < sorted[i].Score {
+			t.Errorf("expected descending scores, got %f before %f", sorted[i-1].Score, sorted[i].Score)
+		}
+	}
+}
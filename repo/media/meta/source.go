@@ -14,9 +14,22 @@ var (
 	literalSource0 = &literalSource{}
 
 	episodePattern          = regexp.MustCompile("(?i)S(\\d+) ?[EX](\\d+)")
-	encodingPattern         = regexp.MustCompile("(?i)[Hx]\\.?26\\d|HEVC|MPEG(?:-\\d)?|DivX|VP\\d|AV\\d")
-	resolutionPattern       = regexp.MustCompile("(?i)(2160|1440|1080|720|480|360|240|144)([pi])")
+	crossEpisodePattern     = regexp.MustCompile(`\b(\d{1,2})x(\d{2,3})\b`)                                 // 1x09
+	nextEpisodePattern      = regexp.MustCompile(`(?i)^(?:-?E|-(?:\d{1,2}x)?)(\d{2,3})(?:[^0-9a-z]|E\d|$)`) // following an episode, -E02, E02, -02, -1x02
+	yearPattern             = regexp.MustCompile(`\b(?:19|20)\d{2}\b`)
+	absoluteEpisodePattern  = regexp.MustCompile(`(?i)^(?:(E|EP)(\d{1,4})|(\d{2,4}))(?:v\d)?$`) // 103, 07v2, EP5
 	commonDelimiterReplacer = strings.NewReplacer("-", " ", "_", " ", ".", " ")
+
+	// tagPattern matches file name tokens following the title, such as resolutions, codecs, sources, audio, release flags
+	// and language markers, optionally suffixed with the release group ("x264-GROUP").
+	tagPattern = regexp.MustCompile(`(?i)^(?:` +
+		`(?:2160|1440|1080|720|480|360|240|144)[pi]|4k|uhd|` +
+		`[hx]\.?26\d|hevc|avc|mpeg(?:-?\d)?|xvid|divx|vp\d|av\d|10bit|hdr(?:10)?|dv|` +
+		`blu-?ray|bdrip|brrip|bdremux|remux|web-?dl|web-?rip|hdtv|dvd(?:rip)?|hdrip|` +
+		`aac\d?|ac-?3|eac-?3|dts(?:-?hd)?|ddp?\d?|truehd|atmos|` +
+		`proper|repack|` +
+		`multi|dual|cz|cze|czech|sk|eng|czdab|czsub|cztit|dabing|dab|tit|titulky|subbed|dubbed` +
+		`)(?:-[^-]+)?$`)
 )
 
 // Source is a source of Metadata.
@@ -25,6 +38,9 @@ type Source interface {
 	FromFile(path string) (Metadata, error)
 	// FromQuery tries to resolve metadata for a custom query, may return nil.
 	FromQuery(query *Query) (Metadata, error)
+	// Search looks up the movies and series matching a query, sorted by their score, may return nil.
+	// Unlike FromQuery, which resolves the best match, it returns multiple candidates for the user to pick from.
+	Search(query *Query) ([]MetadataCandidate, error)
 }
 
 // Query is a search query for a movie or a series episode.
@@ -37,6 +53,16 @@ type Query struct {
 	Season int `json:"season"`
 	// Episode is the episode number in the season, 0 means don't search for a specific episode.
 	Episode int `json:"episode"`
+	// EpisodeEnd is the last episode number of a multi-episode file ("S01E01-E02"), 0 means it's a single episode.
+	EpisodeEnd int `json:"episode_end,omitempty"`
+	// ID is a source-prefixed ID of the movie or series ("tmdb:12345"), used instead of searching by Query.
+	// Sources ignore IDs with a prefix of another source.
+	ID string `json:"id,omitempty"`
+	// Year is the release year of the movie or the first air year of the series, 0 means any year.
+	Year int `json:"year,omitempty"`
+	// Absolute is the episode number counted across all seasons ("Show 103"), used if Season and Episode are unknown,
+	// 0 means it's not known.
+	Absolute int `json:"absolute,omitempty"`
 }
 
 // dummySource is a Source that discovers nothing.
@@ -75,16 +101,29 @@ func (lms *literalSource) FromFile(path string) (Metadata, error) {
 	return NewMetadata(TypeUnknown, nameWithoutExt, nameWithoutExt, "", time.Now(), 10, nil), nil
 }
 
-// FromQuery returns a literal metadata representation of the query.
+// FromQuery returns a literal metadata representation of the query, ID-only queries are not represented.
 func (lms *literalSource) FromQuery(query *Query) (Metadata, error) {
+	if query.ID != "" && query.Query == "" {
+		return nil, nil
+	}
+
 	parentType := query.Type
 	if query.Season >= 0 && query.Episode >= 0 {
 		parentType = TypeSeries
 	}
 
-	genericMeta := NewMetadata(parentType, query.Query, query.Query, "", time.Now(), 10, nil)
+	title := query.Query
+	if query.Absolute > 0 && (query.Season < 0 || query.Episode < 0) {
+		title = fmt.Sprintf("%s %d", title, query.Absolute) // not known to be a series, but distinct from other episodes
+		parentType = TypeUnknown
+	}
+
+	genericMeta := NewMetadata(parentType, title, title, "", time.Now(), 10, nil)
 	if query.Season >= 0 && query.Episode >= 0 {
 		info := fmt.Sprintf("S%02dE%02d", query.Season, query.Episode)
+		if query.EpisodeEnd > 0 {
+			info = fmt.Sprintf("%s-E%02d", info, query.EpisodeEnd)
+		}
 
 		return NewEpisodeMetadata(
 			NewMetadata(TypeEpisode, info, info, "", genericMeta.ReleaseDate(), 10, nil),
@@ -156,33 +195,100 @@ func NewFileAnalysisSource(metaSource Source) Source {
 }
 
 // FromFile tries to create a metadata query from a file and resolve it using FromQuery.
+// The title is cut at the episode number ("S01E01", "1x01"), the release year or the first tag following it,
+// such as a resolution, codec, source, release group or language marker.
+// Multi-episode files ("S01E01-E02") are resolved as their first episode, a trailing number with at least two digits
+// is presumed to be an absolute episode number ("Show 103"), resolved as the title with the number if that fails.
+// The resolved metadata is scored by how well it matches the query (see Confidence).
 func (fas *fileAnalysisSource) FromFile(path string) (Metadata, error) {
-	var (
-		fileName       = filepath.Base(path)
-		nameWithoutExt = strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	fileName := filepath.Base(path)
+	query := parseFileName(strings.TrimSuffix(fileName, filepath.Ext(fileName)))
 
+	m, err := fas.FromQuery(query)
+	if err == nil && m == nil && query.Absolute > 0 {
 		query = &Query{
-			Query:   nameWithoutExt,
+			Query:   fmt.Sprintf("%s %d", query.Query, query.Absolute),
 			Type:    TypeUnknown,
 			Season:  -1,
 			Episode: -1,
+			Year:    query.Year,
 		}
-	)
+		m, err = fas.FromQuery(query)
+	}
+	if err != nil || m == nil {
+		return m, err
+	}
+
+	return WithConfidence(m, matchConfidence(query, m)), nil
+}
+
+// parseFileName makes a metadata query from a file name without its extension.
+func parseFileName(name string) *Query {
+	query := &Query{Type: TypeUnknown, Season: -1, Episode: -1}
 
-	episodeGroups := episodePattern.FindStringSubmatch(nameWithoutExt)
-	if episodeGroups != nil { // presume episode
-		query.Query = nameWithoutExt[:strings.Index(nameWithoutExt, episodeGroups[0])]
+	var loc []int // of the episode number
+	if loc = episodePattern.FindStringSubmatchIndex(name); loc == nil {
+		loc = crossEpisodePattern.FindStringSubmatchIndex(name)
+	}
+	if loc != nil { // presume episode
 		query.Type = TypeEpisode
-		query.Season, _ = strconv.Atoi(strings.TrimLeft(episodeGroups[1], "0"))  // will never error
-		query.Episode, _ = strconv.Atoi(strings.TrimLeft(episodeGroups[2], "0")) // will never error
-	} else {
-		query.Query = resolutionPattern.ReplaceAllLiteralString(nameWithoutExt, "") // step 1: remove resolution
-		query.Query = encodingPattern.ReplaceAllLiteralString(query.Query, "")      // step 2: remove encoding format/codec
-		query.Query = stripBracketLike(query.Query)
+		query.Season, _ = strconv.Atoi(name[loc[2]:loc[3]])  // will never error
+		query.Episode, _ = strconv.Atoi(name[loc[4]:loc[5]]) // will never error
+
+		// multi-episode, such as "S01E01-E02", "S01E01E02E03" or "1x01-1x02"
+		for end, last := loc[1], query.Episode; ; {
+			nl := nextEpisodePattern.FindStringSubmatchIndex(name[end:])
+			if nl == nil {
+				break
+			}
+
+			next, _ := strconv.Atoi(name[end+nl[2] : end+nl[3]]) // will never error
+			if next <= last {
+				break
+			}
+
+			query.EpisodeEnd, last = next, next
+			end += nl[3] // the next one may follow right away
+		}
+		name = name[:loc[0]]
+	}
+
+	// the last year not starting the name, such as in "2012 (2009)", the series year of episodes ("Doctor Who (2005) S01E01")
+	if locs := yearPattern.FindAllStringIndex(name, -1); len(locs) > 0 {
+		if yl := locs[len(locs)-1]; yl[0] > 0 {
+			year, _ := strconv.Atoi(name[yl[0]:yl[1]]) // will never error
+			if year <= time.Now().Year()+1 {           // not a title, such as "Blade Runner 2049"
+				query.Year = year
+				name = name[:yl[0]]
+			}
+		}
+	}
+
+	tokens := strings.FieldsFunc(stripBracketLike(name), func(r rune) bool {
+		return r == ' ' || r == '.' || r == '_' // not dashes, those are a part of tags ("WEB-DL")
+	})
+	for i, token := range tokens {
+		if i > 0 && tagPattern.MatchString(token) { // the release group follows the tags
+			tokens = tokens[:i]
+			break
+		}
+	}
+	tokens = strings.Fields(commonDelimiterReplacer.Replace(strings.Join(tokens, " ")))
+
+	if query.Type == TypeUnknown && query.Year == 0 && len(tokens) > 1 {
+		last := tokens[len(tokens)-1]
+		if groups := absoluteEpisodePattern.FindStringSubmatch(last); groups != nil && !yearPattern.MatchString(last) { // a title year otherwise
+			number := groups[2] + groups[3] // only one is matched
+			if absolute, _ := strconv.Atoi(number); absolute > 0 {
+				query.Type = TypeEpisode
+				query.Absolute = absolute
+				tokens = tokens[:len(tokens)-1]
+			}
+		}
 	}
 
-	query.Query = strings.Join(strings.Fields(commonDelimiterReplacer.Replace(query.Query)), " ")
-	return fas.FromQuery(query)
+	query.Query = strings.Join(tokens, " ")
+	return query
 }
 
 func stripBracketLike(s string) string {
@@ -1,6 +1,9 @@
 package meta
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 type loggingSource struct {
 	t *testing.T
@@ -12,7 +15,12 @@ func (ls *loggingSource) FromFile(path string) (Metadata, error) {
 }
 
 func (ls *loggingSource) FromQuery(query *Query) (Metadata, error) {
-	ls.t.Logf("query %s, type %d, season %d, episode %d", query.Query, query.Type, query.Season, query.Episode)
+	ls.t.Logf("query %s, type %d, season %d, episode %d, year %d, absolute %d", query.Query, query.Type, query.Season, query.Episode, query.Year, query.Absolute)
+	return nil, nil
+}
+
+func (ls *loggingSource) Search(query *Query) ([]MetadataCandidate, error) {
+	ls.t.Logf("search %s, type %d", query.Query, query.Type)
 	return nil, nil
 }
 
@@ -25,3 +33,142 @@ func TestFileAnalysisSource_FromFile(t *testing.T) {
 	metaSource.FromFile("chicago.med.s06e09.720p.hdtv.x264-syncopy[eztv.re].mkv")
 	metaSource.FromFile("Babovřesky 3 (2015) [juraison+].avi")
 }
+
+func TestParseFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want Query
+	}{
+		{name: "Chicago.Med.S01E10 cz.tit.", want: Query{Query: "Chicago Med", Type: TypeEpisode, Season: 1, Episode: 10}},
+		{name: "Nemocnice Chicago Med s01x09 CZdab", want: Query{Query: "Nemocnice Chicago Med", Type: TypeEpisode, Season: 1, Episode: 9}},
+		{name: "chicago.med.s06e09.720p.hdtv.x264-syncopy[eztv.re]", want: Query{Query: "chicago med", Type: TypeEpisode, Season: 6, Episode: 9}},
+		{name: "Show.S01E01-E02.1080p.WEB-DL", want: Query{Query: "Show", Type: TypeEpisode, Season: 1, Episode: 1, EpisodeEnd: 2}},
+		{name: "Show S01E01E02E03", want: Query{Query: "Show", Type: TypeEpisode, Season: 1, Episode: 1, EpisodeEnd: 3}},
+		{name: "Show.S02E05-06.720p", want: Query{Query: "Show", Type: TypeEpisode, Season: 2, Episode: 5, EpisodeEnd: 6}},
+		{name: "Show 1x01-1x02", want: Query{Query: "Show", Type: TypeEpisode, Season: 1, Episode: 1, EpisodeEnd: 2}},
+		{name: "Show.S01E01-720p", want: Query{Query: "Show", Type: TypeEpisode, Season: 1, Episode: 1}},
+		{name: "Show 2x05", want: Query{Query: "Show", Type: TypeEpisode, Season: 2, Episode: 5}},
+		{name: "Doctor Who (2005) S01E01", want: Query{Query: "Doctor Who", Type: TypeEpisode, Season: 1, Episode: 1, Year: 2005}},
+		{name: "Babovřesky 3 (2015) [juraison+]", want: Query{Query: "Babovřesky 3", Type: TypeUnknown, Season: -1, Episode: -1, Year: 2015}},
+		{name: "The.Matrix.1999.REMUX.2160p.BluRay.x265-GROUP", want: Query{Query: "The Matrix", Type: TypeUnknown, Season: -1, Episode: -1, Year: 1999}},
+		{name: "Spider-Man.Into.the.Spider-Verse.2018.WEBRip", want: Query{Query: "Spider Man Into the Spider Verse", Type: TypeUnknown, Season: -1, Episode: -1, Year: 2018}},
+		{name: "2012 (2009)", want: Query{Query: "2012", Type: TypeUnknown, Season: -1, Episode: -1, Year: 2009}},
+		{name: "Blade Runner 2049", want: Query{Query: "Blade Runner 2049", Type: TypeUnknown, Season: -1, Episode: -1}},
+		{name: "Charlotte's Web", want: Query{Query: "Charlotte's Web", Type: TypeUnknown, Season: -1, Episode: -1}},
+		{name: "Toy Story 3", want: Query{Query: "Toy Story 3", Type: TypeUnknown, Season: -1, Episode: -1}},
+		{name: "Noragami Aragoto 13 CZ", want: Query{Query: "Noragami Aragoto", Type: TypeEpisode, Season: -1, Episode: -1, Absolute: 13}},
+		{name: "[SubsPlease] One Piece - 1071v2 (1080p) [ABCD1234]", want: Query{Query: "One Piece", Type: TypeEpisode, Season: -1, Episode: -1, Absolute: 1071}},
+		{name: "Show 103", want: Query{Query: "Show", Type: TypeEpisode, Season: -1, Episode: -1, Absolute: 103}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := parseFileName(test.name); *got != test.want {
+				t.Errorf("expected %+v, got %+v", test.want, *got)
+			}
+		})
+	}
+}
+
+// scheduleSource is a ScheduleSource knowing a single episode of every series.
+type scheduleSource struct {
+	dummySource
+}
+
+func (ss *scheduleSource) Schedule(_ Metadata, from, _ time.Time) ([]*AiringEpisode, error) {
+	return []*AiringEpisode{{Season: 1, Episode: 1, AirDate: from}}, nil
+}
+
+func TestSchedule(t *testing.T) {
+	var (
+		series = NewMetadata(TypeSeries, "Series", "Series", "", time.Time{}, 0, nil)
+		from   = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to     = from.AddDate(0, 0, 7)
+	)
+
+	if episodes, err := Schedule(NewLiteralSource(), series, from, to); err != nil || episodes != nil {
+		t.Errorf("expected no episodes from a source without air dates, got %v, %v", episodes, err)
+	}
+
+	source := NewFileAnalysisSource(NewCompositeSource(NewLiteralSource(), &scheduleSource{}))
+	if episodes, err := Schedule(source, series, from, to); err != nil || len(episodes) != 1 {
+		t.Errorf("expected an episode from a delegate source, got %v, %v", episodes, err)
+	}
+}
+
+func TestWithImage(t *testing.T) {
+	var (
+		backdrop = NewImage(ImageTypeBackdrop, "https://example.com/backdrop.jpg", true, "Backdrop")
+		poster   = NewImage(ImageTypePoster, "https://example.com/poster.jpg", true, "Poster")
+		chosen   = NewImage(ImageTypePoster, "https://example.com/other.jpg", true, "Poster")
+		series   = NewMovieOrSeriesMetadata(NewMetadata(TypeSeries, "Series", "", "", time.Time{}, 0, []Image{backdrop, poster}), nil, nil, nil, nil)
+		episode  = NewEpisodeMetadata(NewMetadata(TypeEpisode, "Episode", "", "", time.Time{}, 0, nil), series, 1, 1)
+	)
+
+	em, ok := WithImage(episode, chosen).(EpisodeMetadata)
+	if !ok {
+		t.Fatal("expected episode metadata")
+	}
+
+	images := em.Series().Images()
+	if len(images) != 2 || images[0].Path() != chosen.Path() || images[1].Path() != backdrop.Path() {
+		t.Errorf("expected chosen poster to replace the series poster, got %v", images)
+	}
+	if images := series.Images(); images[1].Path() != poster.Path() {
+		t.Error("expected original metadata to be unchanged")
+	}
+}
+
+func TestWithGenres(t *testing.T) {
+	var (
+		series  = NewMovieOrSeriesMetadata(NewMetadata(TypeSeries, "Series", "", "", time.Time{}, 0, nil), []string{"Drama"}, nil, nil, nil)
+		episode = NewEpisodeMetadata(NewMetadata(TypeEpisode, "Episode", "", "", time.Time{}, 0, nil), series, 1, 1)
+	)
+
+	em, ok := WithGenres(episode, []string{"Drama", "Crime"}).(EpisodeMetadata)
+	if !ok {
+		t.Fatal("expected episode metadata")
+	}
+	if genres := em.Series().Genres(); len(genres) != 2 || genres[1] != "Crime" {
+		t.Errorf("expected series genres to be replaced, got %v", genres)
+	}
+	if genres := series.Genres(); len(genres) != 1 {
+		t.Error("expected original metadata to be unchanged")
+	}
+}
+
+// fixedSource is a Source resolving every query to the same metadata.
+type fixedSource struct {
+	Source
+	m Metadata
+}
+
+func (fs *fixedSource) FromQuery(_ *Query) (Metadata, error) {
+	return fs.m, nil
+}
+
+func TestFileAnalysisSource_Confidence(t *testing.T) {
+	series := NewMovieOrSeriesMetadata(NewMetadata(TypeSeries, "Chicago Med", "", "", time.Date(2015, 11, 17, 0, 0, 0, 0, time.UTC), 0, nil), nil, nil, nil, nil)
+	episode := NewEpisodeMetadata(NewMetadata(TypeEpisode, "Episode", "", "", time.Time{}, 0, nil), series, 1, 10)
+
+	m, err := NewFileAnalysisSource(&fixedSource{m: episode}).FromFile("Chicago.Med.S01E10.720p.mkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.(EpisodeMetadata); !ok {
+		t.Fatal("expected episode metadata")
+	}
+	if c := Confidence(m); c != 1 {
+		t.Errorf("expected confidence 1 of an exact match, got %f", c)
+	}
+	if c := Confidence(episode); c != 0 {
+		t.Errorf("expected original metadata to be unscored, got %f", c)
+	}
+
+	m, err = NewFileAnalysisSource(&fixedSource{m: series}).FromFile("Grey's Anatomy (2005).mkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := Confidence(m); c == 0 || c >= LowConfidence {
+		t.Errorf("expected low confidence of another series, got %f", c)
+	}
+}
@@ -0,0 +1,185 @@
+package tmdb
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/tmdb"
+	"golang.org/x/exp/slices"
+	"strconv"
+	"strings"
+)
+
+type galleryKey struct {
+	type_ meta.Type
+	id    int
+}
+
+// galleryEntry is an image in a movie or series images response, the same for both.
+type galleryEntry = struct {
+	AspectRatio *float32 `json:"aspect_ratio,omitempty"`
+	FilePath    *string  `json:"file_path"`
+	Height      *int     `json:"height,omitempty"`
+	Iso6391     *string  `json:"iso_639_1"`
+	VoteAverage *float32 `json:"vote_average,omitempty"`
+	VoteCount   *int     `json:"vote_count,omitempty"`
+	Width       *int     `json:"width,omitempty"`
+}
+
+// galleryData is the body of a movie or series images response.
+type galleryData struct {
+	Backdrops *[]galleryEntry `json:"backdrops,omitempty"`
+	Id        *int            `json:"id,omitempty"`
+	Logos     *[]galleryEntry `json:"logos,omitempty"`
+	Posters   *[]galleryEntry `json:"posters,omitempty"`
+}
+
+// Gallery returns all backdrops and posters of a movie or series, backdrops first, each sorted by their rating.
+// Images in the source's language and ones without text are included, the movie or series is looked up by its title and release year.
+func (s *source) Gallery(m meta.Metadata) ([]meta.Image, error) {
+	var (
+		id  int
+		err error
+	)
+	switch m.Type() {
+	case meta.TypeMovie:
+		id, err = s.lookupMovie(m)
+	case meta.TypeSeries:
+		id, err = s.lookupSeries(m)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		return nil, nil // not found
+	}
+
+	key := galleryKey{type_: m.Type(), id: id}
+	if images, ok := s.galleryCache.Get(key); ok {
+		return images, nil
+	}
+
+	var (
+		lang, _, _ = strings.Cut(s.lang, "-") // ISO 639-1 code
+		include    = lang + ",null"
+		data       *galleryData
+	)
+	if m.Type() == meta.TypeMovie {
+		res, err := s.client.MovieImagesWithResponse(context.Background(), int32(id), &tmdb.MovieImagesParams{IncludeImageLanguage: &include})
+		if err == nil && res.StatusCode() == 404 {
+			return nil, nil // movie not found
+		}
+		if err == nil {
+			err = s.checkStatus(res)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch movie images")
+		}
+
+		data = (*galleryData)(res.JSON200)
+	} else {
+		res, err := s.client.TvSeriesImagesWithResponse(context.Background(), int32(id), &tmdb.TvSeriesImagesParams{IncludeImageLanguage: &include})
+		if err == nil && res.StatusCode() == 404 {
+			return nil, nil // series not found
+		}
+		if err == nil {
+			err = s.checkStatus(res)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch series images")
+		}
+
+		data = (*galleryData)(res.JSON200)
+	}
+
+	config, err := s.fetchConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	url := config.JSON200.Images.SecureBaseUrl
+	if url == nil {
+		url = config.JSON200.Images.BaseUrl
+	}
+
+	images := make([]meta.Image, 0) // not nil, the movie or series is known
+	for _, group := range []struct {
+		entries *[]galleryEntry
+		type_   meta.ImageType
+		desc    string
+	}{
+		{entries: data.Backdrops, type_: meta.ImageTypeBackdrop, desc: "Backdrop"},
+		{entries: data.Posters, type_: meta.ImageTypePoster, desc: "Poster"},
+	} {
+		if group.entries == nil {
+			continue
+		}
+
+		entries := slices.Clone(*group.entries)
+		slices.SortStableFunc(entries, func(a, b galleryEntry) int {
+			return compareVotes(b.VoteAverage, a.VoteAverage) // descending
+		})
+		for _, e := range entries {
+			if e.FilePath != nil {
+				images = append(images, &image{type_: group.type_, path: *e.FilePath, desc: group.desc, baseUrl: *url})
+			}
+		}
+	}
+
+	s.galleryCache.Set(key, images, s.exp)
+	return images, nil
+}
+
+// lookupMovie searches for a movie by its title and release year, returns zero if it's not found.
+func (s *source) lookupMovie(movie meta.Metadata) (int, error) {
+	params := &tmdb.SearchMovieParams{Query: movie.Title(), Language: &s.lang}
+	if rd := movie.ReleaseDate(); !rd.IsZero() && !rd.Equal(invalidTime) {
+		year := strconv.Itoa(rd.Year())
+		params.PrimaryReleaseYear = &year
+	}
+
+	key := strings.ToLower(params.Query)
+	if params.PrimaryReleaseYear != nil {
+		key = fmt.Sprintf("%s (%s)", key, *params.PrimaryReleaseYear)
+	}
+	if id, ok := s.movieIdCache.Get(key); ok {
+		return id, nil
+	}
+
+	res, err := s.client.SearchMovieWithResponse(context.Background(), params)
+	if err == nil {
+		err = s.checkStatus(res)
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to search movie")
+	}
+
+	var id int
+	if results := *res.JSON200.Results; len(results) > 0 && results[0].Id != nil {
+		id = *results[0].Id
+	}
+
+	s.movieIdCache.Set(key, id, s.exp)
+	return id, nil
+}
+
+// compareVotes compares optional vote averages, missing ones are the lowest.
+func compareVotes(a, b *float32) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	case *a < *b:
+		return -1
+	case *a > *b:
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,101 @@
+package tmdb
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/tmdb"
+	"golang.org/x/text/language"
+	"strings"
+)
+
+type genreKey struct {
+	series bool
+	lang   string
+}
+
+// LocalizeGenres translates genre names in the source's language to another language by their TMDB genre IDs,
+// names that aren't TMDB genres are kept as-is.
+func (s *source) LocalizeGenres(genres []string, type_ meta.Type, lang language.Tag) ([]string, error) {
+	if type_ != meta.TypeMovie && type_ != meta.TypeSeries {
+		return genres, nil
+	}
+
+	target := lang.String()
+	if strings.EqualFold(target, s.lang) {
+		return genres, nil
+	}
+
+	series := type_ == meta.TypeSeries
+	from, err := s.fetchGenres(series, s.lang)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.fetchGenres(series, target)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]int, len(from)) // lowercase name -> ID
+	for id, name := range from {
+		ids[strings.ToLower(name)] = id
+	}
+
+	localized := make([]string, len(genres))
+	for i, genre := range genres {
+		localized[i] = genre
+		if id, ok := ids[strings.ToLower(genre)]; ok {
+			if name, ok := to[id]; ok && name != "" {
+				localized[i] = name
+			}
+		}
+	}
+
+	return localized, nil
+}
+
+// fetchGenres fetches the movie or series genre names in a language, keyed by their IDs.
+func (s *source) fetchGenres(series bool, lang string) (map[int]string, error) {
+	key := genreKey{series: series, lang: lang}
+	if genres, ok := s.genreCache.Get(key); ok {
+		return genres, nil
+	}
+
+	var entries []struct {
+		Id   *int    `json:"id,omitempty"`
+		Name *string `json:"name,omitempty"`
+	}
+	if series {
+		res, err := s.client.GenreTvListWithResponse(context.Background(), &tmdb.GenreTvListParams{Language: &lang})
+		if err == nil {
+			err = s.checkStatus(res)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch series genres")
+		}
+		if res.JSON200.Genres != nil {
+			entries = *res.JSON200.Genres
+		}
+	} else {
+		res, err := s.client.GenreMovieListWithResponse(context.Background(), &tmdb.GenreMovieListParams{Language: &lang})
+		if err == nil {
+			err = s.checkStatus(res)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch movie genres")
+		}
+		if res.JSON200.Genres != nil {
+			entries = *res.JSON200.Genres
+		}
+	}
+
+	genres := make(map[int]string, len(entries))
+	for _, e := range entries {
+		if e.Id != nil && e.Name != nil {
+			genres[*e.Id] = *e.Name
+		}
+	}
+
+	s.genreCache.Set(key, genres, s.exp)
+	return genres, nil
+}
@@ -0,0 +1,146 @@
+package tmdb
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/tmdb"
+	"golang.org/x/exp/slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type seasonKey struct {
+	id, season int
+}
+
+// Schedule returns the episodes of a series airing in a date range, inclusive, sorted by air date.
+// Only the seasons that may air in the range are fetched, the series is looked up by its title and first air date year.
+func (s *source) Schedule(series meta.Metadata, from, to time.Time) ([]*meta.AiringEpisode, error) {
+	id, err := s.lookupSeries(series)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		return nil, nil // series not found
+	}
+
+	m, err := s.fetchSeries(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch series metadata")
+	}
+	if m == nil {
+		return nil, nil // series not found
+	}
+
+	sm, ok := m.(*seriesMetadata)
+	if !ok || sm.data.JSON200.Seasons == nil {
+		return make([]*meta.AiringEpisode, 0), nil
+	}
+
+	var (
+		seasons  = *sm.data.JSON200.Seasons
+		episodes = make([]*meta.AiringEpisode, 0) // not nil, the series is known
+	)
+	for i, season := range seasons {
+		if season.SeasonNumber == nil || season.AirDate == nil {
+			continue // not announced yet
+		}
+
+		airDate, err := time.Parse(time.DateOnly, *season.AirDate)
+		if err != nil || airDate.After(to) {
+			continue
+		}
+		if i+1 < len(seasons) && seasons[i+1].AirDate != nil { // the next season starts before the range, this one has ended
+			if next, err := time.Parse(time.DateOnly, *seasons[i+1].AirDate); err == nil && next.Before(from) {
+				continue
+			}
+		}
+
+		res, err := s.fetchSeason(id, *season.SeasonNumber)
+		if err != nil {
+			return nil, err
+		}
+		if res == nil || res.JSON200.Episodes == nil {
+			continue
+		}
+
+		for _, e := range *res.JSON200.Episodes {
+			if e.AirDate == nil || e.EpisodeNumber == nil {
+				continue
+			}
+
+			airDate, err := time.Parse(time.DateOnly, *e.AirDate)
+			if err != nil || airDate.Before(from) || airDate.After(to) {
+				continue
+			}
+
+			ae := &meta.AiringEpisode{Season: *season.SeasonNumber, Episode: *e.EpisodeNumber, AirDate: airDate}
+			if e.Name != nil {
+				ae.Title = *e.Name
+			}
+			episodes = append(episodes, ae)
+		}
+	}
+
+	slices.SortStableFunc(episodes, func(a, b *meta.AiringEpisode) int {
+		return a.AirDate.Compare(b.AirDate)
+	})
+	return episodes, nil
+}
+
+// lookupSeries searches for a series by its title and first air date year, returns zero if it's not found.
+func (s *source) lookupSeries(series meta.Metadata) (int, error) {
+	params := &tmdb.SearchTvParams{Query: series.Title(), Language: &s.lang}
+	if rd := series.ReleaseDate(); !rd.IsZero() && !rd.Equal(invalidTime) {
+		year := strconv.Itoa(rd.Year())
+		params.FirstAirDateYear = &year
+	}
+
+	key := strings.ToLower(params.Query)
+	if params.FirstAirDateYear != nil {
+		key = fmt.Sprintf("%s (%s)", key, *params.FirstAirDateYear)
+	}
+	if id, ok := s.seriesIdCache.Get(key); ok {
+		return id, nil
+	}
+
+	res, err := s.client.SearchTvWithResponse(context.Background(), params)
+	if err == nil {
+		err = s.checkStatus(res)
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to search series")
+	}
+
+	var id int
+	if results := *res.JSON200.Results; len(results) > 0 && results[0].Id != nil {
+		id = *results[0].Id
+	}
+
+	s.seriesIdCache.Set(key, id, s.exp)
+	return id, nil
+}
+
+func (s *source) fetchSeason(id, season int) (*tmdb.TvSeasonDetailsResponse, error) {
+	key := seasonKey{id: id, season: season}
+	if res, ok := s.seasonCache.Get(key); ok {
+		return res, nil
+	}
+
+	res, err := s.client.TvSeasonDetailsWithResponse(context.Background(), int32(id), int32(season), &tmdb.TvSeasonDetailsParams{Language: &s.lang})
+	if err == nil && res.StatusCode() == 404 {
+		return nil, nil // season not found
+	}
+	if err == nil {
+		err = s.checkStatus(res)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch season details")
+	}
+
+	s.seasonCache.Set(key, res, s.exp)
+	return res, nil
+}
@@ -0,0 +1,157 @@
+package tmdb
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/tmdb"
+	"time"
+)
+
+// searchResult is a movie or series of the results of a search.
+type searchResult struct {
+	id                             int
+	type_                          meta.Type
+	title, originalTitle, overview string
+	releaseDate, posterPath        *string
+}
+
+// Search looks up movies and series matching the query using The Movie Database's API, both are searched if it has no type.
+// Queries with a TMDB ID are not searched, they're resolved directly by FromQuery.
+func (s *source) Search(query *meta.Query) ([]meta.MetadataCandidate, error) {
+	if query.ID != "" || query.Query == "" {
+		return nil, nil
+	}
+
+	var results [][]searchResult
+	if query.Type != meta.TypeSeries && query.Type != meta.TypeEpisode {
+		movies, err := s.searchMovies(query.Query)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, movies)
+	}
+	if query.Type != meta.TypeMovie {
+		series, err := s.searchSeriesList(query.Query)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, series)
+	}
+
+	config, err := s.fetchConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	imageUrl := config.JSON200.Images.SecureBaseUrl
+	if imageUrl == nil {
+		imageUrl = config.JSON200.Images.BaseUrl
+	}
+
+	var candidates []meta.MetadataCandidate
+	for _, group := range results {
+		for rank, r := range group {
+			c := meta.MetadataCandidate{
+				ID:            fmt.Sprintf("%s%d", idPrefix, r.id),
+				Type:          r.type_,
+				Title:         r.title,
+				OriginalTitle: r.originalTitle,
+				Overview:      r.overview,
+				Score:         meta.Score(query.Query, rank, len(group), r.title, r.originalTitle),
+			}
+			if r.releaseDate != nil {
+				c.ReleaseDate, _ = time.Parse(time.DateOnly, *r.releaseDate) // zero if not known
+			}
+			if r.posterPath != nil && *r.posterPath != "" && imageUrl != nil {
+				c.Poster = &image{type_: meta.ImageTypePoster, path: *r.posterPath, desc: "Poster", baseUrl: *imageUrl}
+			}
+
+			candidates = append(candidates, c)
+		}
+	}
+
+	return meta.SortCandidates(candidates), nil
+}
+
+func (s *source) searchMovies(query string) ([]searchResult, error) {
+	res, err := s.client.SearchMovieWithResponse(context.Background(), &tmdb.SearchMovieParams{
+		Query:    query,
+		Language: &s.lang,
+	})
+	if err == nil {
+		err = s.checkStatus(res)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search movie")
+	}
+	if res.JSON200.Results == nil {
+		return nil, nil
+	}
+
+	results := make([]searchResult, 0, len(*res.JSON200.Results))
+	for _, r := range *res.JSON200.Results {
+		if r.Id == nil {
+			continue
+		}
+
+		results = append(results, searchResult{
+			id:            *r.Id,
+			type_:         meta.TypeMovie,
+			title:         deref(r.Title),
+			originalTitle: deref(r.OriginalTitle),
+			overview:      deref(r.Overview),
+			releaseDate:   r.ReleaseDate,
+			posterPath:    r.PosterPath,
+		})
+	}
+
+	return results, nil
+}
+
+func (s *source) searchSeriesList(query string) ([]searchResult, error) {
+	res, err := s.client.SearchTvWithResponse(context.Background(), &tmdb.SearchTvParams{
+		Query:    query,
+		Language: &s.lang,
+	})
+	if err == nil {
+		err = s.checkStatus(res)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search series")
+	}
+	if res.JSON200.Results == nil {
+		return nil, nil
+	}
+
+	results := make([]searchResult, 0, len(*res.JSON200.Results))
+	for _, r := range *res.JSON200.Results {
+		if r.Id == nil {
+			continue
+		}
+
+		results = append(results, searchResult{
+			id:            *r.Id,
+			type_:         meta.TypeSeries,
+			title:         deref(r.Name),
+			originalTitle: deref(r.OriginalName),
+			overview:      deref(r.Overview),
+			releaseDate:   r.FirstAirDate,
+			posterPath:    r.PosterPath,
+		})
+	}
+
+	return results, nil
+}
+
+// deref dereferences a string, returns an empty string if it's nil.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -3,6 +3,7 @@ package tmdb
 import (
 	"github.com/katana-project/katana/repo/media/meta"
 	"github.com/katana-project/tmdb"
+	"golang.org/x/exp/slices"
 	"golang.org/x/text/language"
 	"time"
 )
@@ -136,3 +137,31 @@ func (sm *seriesMetadata) Images() []meta.Image {
 
 	return images
 }
+
+// absoluteEpisode translates an episode number counted across all seasons to a season and an episode number in it,
+// specials (season 0) are not counted. Returns false if the series has fewer episodes.
+func (sm *seriesMetadata) absoluteEpisode(absolute int) (season, episode int, ok bool) {
+	if sm.data.JSON200.Seasons == nil {
+		return 0, 0, false
+	}
+
+	var counts [][2]int // season number, episode count
+	for _, s := range *sm.data.JSON200.Seasons {
+		if s.SeasonNumber != nil && *s.SeasonNumber > 0 && s.EpisodeCount != nil && *s.EpisodeCount > 0 {
+			counts = append(counts, [2]int{*s.SeasonNumber, *s.EpisodeCount})
+		}
+	}
+	slices.SortFunc(counts, func(a, b [2]int) int {
+		return a[0] - b[0]
+	})
+
+	for _, c := range counts {
+		if absolute <= c[1] {
+			return c[0], absolute, true
+		}
+
+		absolute -= c[1]
+	}
+
+	return 0, 0, false
+}
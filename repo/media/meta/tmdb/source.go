@@ -9,9 +9,13 @@ import (
 	"github.com/katana-project/tmdb"
 	"golang.org/x/text/language"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
+// idPrefix is the prefix of TMDB IDs in queries.
+const idPrefix = "tmdb:"
+
 type source struct {
 	client tmdb.ClientWithResponsesInterface
 	lang   string
@@ -20,6 +24,11 @@ type source struct {
 	config           *tmdb.ConfigurationDetailsResponse // TODO: expire?
 	movieSeriesCache imcache.Cache[int, meta.MovieOrSeriesMetadata]
 	episodeCache     imcache.Cache[episodeKey, meta.EpisodeMetadata]
+	seriesIdCache    imcache.Cache[string, int] // "title (year)" -> series ID, zero if not found
+	seasonCache      imcache.Cache[seasonKey, *tmdb.TvSeasonDetailsResponse]
+	movieIdCache     imcache.Cache[string, int] // "title (year)" -> movie ID, zero if not found
+	galleryCache     imcache.Cache[galleryKey, []meta.Image]
+	genreCache       imcache.Cache[genreKey, map[int]string] // genre ID -> name
 }
 
 type episodeKey struct {
@@ -42,17 +51,61 @@ func (s *source) FromFile(path string) (meta.Metadata, error) {
 }
 
 // FromQuery tries to resolve the query using The Movie Database's API.
+// Queries with a TMDB ID ("tmdb:12345") are resolved directly, they need a type, because IDs are not unique across types.
 func (s *source) FromQuery(query *meta.Query) (meta.Metadata, error) {
+	if query.ID != "" {
+		return s.fromID(query)
+	}
+
 	switch query.Type {
 	case meta.TypeMovie:
-		return s.searchMovie(query.Query)
+		return s.searchMovie(query.Query, query.Year)
 	case meta.TypeSeries, meta.TypeEpisode:
-		return s.searchSeries(query.Query, query.Season, query.Episode)
+		season, episode := query.Season, query.Episode
+		if (season < 0 || episode < 0) && query.Absolute > 0 {
+			season, episode = -1, query.Absolute // mapped to a season once the series is found
+		}
+
+		return s.searchSeries(query.Query, query.Year, season, episode)
+	}
+
+	if query.Year > 0 { // the multi search can't be narrowed by year, movies are the more likely to have it in the name
+		m, err := s.searchMovie(query.Query, query.Year)
+		if err != nil || m != nil {
+			return m, err
+		}
 	}
 
 	return s.searchMulti(query.Query)
 }
 
+func (s *source) fromID(query *meta.Query) (meta.Metadata, error) {
+	rawId, ok := strings.CutPrefix(query.ID, idPrefix)
+	if !ok {
+		return nil, nil // another source's ID
+	}
+
+	id, err := strconv.Atoi(rawId)
+	if err != nil || id <= 0 {
+		return nil, &meta.ErrInvalidQuery{Query: query.ID, Type: query.Type}
+	}
+
+	switch query.Type {
+	case meta.TypeMovie:
+		return s.fetchMovie(id)
+	case meta.TypeSeries:
+		return s.fetchSeries(id)
+	case meta.TypeEpisode:
+		if query.Season < 0 || query.Episode < 0 {
+			return nil, &meta.ErrInvalidQuery{Query: query.ID, Type: query.Type}
+		}
+
+		return s.fetchEpisode(id, query.Season, query.Episode)
+	}
+
+	return nil, &meta.ErrInvalidQuery{Query: query.ID, Type: query.Type}
+}
+
 func (s *source) fetchConfiguration() (*tmdb.ConfigurationDetailsResponse, error) {
 	if s.config != nil {
 		return s.config, nil
@@ -96,10 +149,21 @@ func (s *source) searchMulti(query string) (meta.Metadata, error) {
 	return nil, nil
 }
 
-func (s *source) searchMovie(query string) (meta.Metadata, error) {
+// yearParam makes an optional year query parameter, nil for any year.
+func yearParam(year int) *string {
+	if year <= 0 {
+		return nil
+	}
+
+	y := strconv.Itoa(year)
+	return &y
+}
+
+func (s *source) searchMovie(query string, year int) (meta.Metadata, error) {
 	res, err := s.client.SearchMovieWithResponse(context.Background(), &tmdb.SearchMovieParams{
-		Query:    query,
-		Language: &s.lang,
+		Query:              query,
+		PrimaryReleaseYear: yearParam(year),
+		Language:           &s.lang,
 	})
 	if err == nil {
 		err = s.checkStatus(res)
@@ -116,10 +180,13 @@ func (s *source) searchMovie(query string) (meta.Metadata, error) {
 	return nil, nil
 }
 
-func (s *source) searchSeries(query string, season, episode int) (meta.Metadata, error) {
+// searchSeries looks up a series and resolves it or an episode of it, a negative season with an episode number
+// resolves the episode by its absolute number.
+func (s *source) searchSeries(query string, year, season, episode int) (meta.Metadata, error) {
 	res, err := s.client.SearchTvWithResponse(context.Background(), &tmdb.SearchTvParams{
-		Query:    query,
-		Language: &s.lang,
+		Query:            query,
+		FirstAirDateYear: yearParam(year),
+		Language:         &s.lang,
 	})
 	if err == nil {
 		err = s.checkStatus(res)
@@ -131,6 +198,20 @@ func (s *source) searchSeries(query string, season, episode int) (meta.Metadata,
 	results := *res.JSON200.Results
 	if len(results) > 0 {
 		id := *(results[0].Id)
+		if season < 0 && episode > 0 {
+			m, err := s.fetchSeries(id)
+			if err != nil || m == nil {
+				return nil, err
+			}
+
+			sm, ok := m.(*seriesMetadata)
+			if !ok {
+				return nil, nil // a movie cached under the same ID
+			}
+			if season, episode, ok = sm.absoluteEpisode(episode); !ok {
+				return nil, nil // past the last episode
+			}
+		}
 		if season >= 0 && episode >= 0 {
 			return s.fetchEpisode(id, season, episode)
 		}
@@ -147,6 +228,9 @@ func (s *source) fetchMovie(id int) (meta.MovieOrSeriesMetadata, error) {
 	}
 
 	res, err := s.client.MovieDetailsWithResponse(context.Background(), int32(id), &tmdb.MovieDetailsParams{Language: &s.lang})
+	if err == nil && res.StatusCode() == 404 {
+		return nil, nil // movie not found
+	}
 	if err == nil {
 		err = s.checkStatus(res)
 	}
@@ -182,6 +266,9 @@ func (s *source) fetchSeries(id int) (meta.MovieOrSeriesMetadata, error) {
 	}
 
 	res, err := s.client.TvSeriesDetailsWithResponse(context.Background(), int32(id), &tmdb.TvSeriesDetailsParams{Language: &s.lang})
+	if err == nil && res.StatusCode() == 404 {
+		return nil, nil // series not found
+	}
 	if err == nil {
 		err = s.checkStatus(res)
 	}
@@ -221,6 +308,9 @@ func (s *source) fetchEpisode(id, season, episode int) (meta.EpisodeMetadata, er
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to fetch series metadata")
 	}
+	if seriesMeta == nil {
+		return nil, nil // series not found
+	}
 
 	episodeRes, err := s.client.TvEpisodeDetailsWithResponse(context.Background(), int32(id), int32(season), int32(episode), &tmdb.TvEpisodeDetailsParams{Language: &s.lang})
 	if err != nil {
@@ -0,0 +1,157 @@
+package tmdb
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRateLimit is the default maximum number of requests per second, below TMDB's limit of around 50.
+	DefaultRateLimit = 40
+	// DefaultMaxRetries is the default maximum number of retries of a throttled request.
+	DefaultMaxRetries = 5
+	// DefaultMaxBackoff is the default maximum delay before retrying a throttled request.
+	DefaultMaxBackoff = time.Minute
+
+	// initialBackoff is the delay before the first retry of a throttled request that doesn't specify one, doubled with each retry.
+	initialBackoff = time.Second
+)
+
+// LimitOptions are the request rate limiting and retry options of the TMDB API client transport.
+type LimitOptions struct {
+	// RateLimit is the maximum number of requests per second, defaults to DefaultRateLimit, negative disables limiting.
+	RateLimit float64
+	// MaxRetries is the maximum number of retries of a throttled request, defaults to DefaultMaxRetries, negative disables retries.
+	MaxRetries int
+	// MaxBackoff is the maximum delay before retrying a throttled request, defaults to DefaultMaxBackoff.
+	MaxBackoff time.Duration
+}
+
+// transport is a http.RoundTripper spacing out requests and retrying throttled (429 Too Many Requests) ones with an exponential backoff.
+type transport struct {
+	base http.RoundTripper
+
+	interval   time.Duration // minimum period between requests, zero if they're not limited
+	maxRetries int
+	maxBackoff time.Duration
+
+	mu   sync.Mutex
+	next time.Time // earliest time of the next request
+}
+
+// NewTransport creates a http.RoundTripper for the TMDB API client, limiting the request rate of base and retrying throttled requests.
+// The options can be nil, the defaults are used then; base can be nil, http.DefaultTransport is used then.
+func NewTransport(base http.RoundTripper, opts *LimitOptions) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if opts == nil {
+		opts = &LimitOptions{}
+	}
+
+	t := &transport{base: base, maxRetries: opts.MaxRetries, maxBackoff: opts.MaxBackoff}
+	switch {
+	case opts.RateLimit == 0:
+		t.interval = time.Duration(float64(time.Second) / DefaultRateLimit)
+	case opts.RateLimit > 0:
+		t.interval = time.Duration(float64(time.Second) / opts.RateLimit)
+	}
+	if t.maxRetries == 0 {
+		t.maxRetries = DefaultMaxRetries
+	}
+	if t.maxBackoff <= 0 {
+		t.maxBackoff = DefaultMaxBackoff
+	}
+
+	return t
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	backoff := initialBackoff
+	for retry := 0; ; retry++ {
+		if err := sleep(req, t.reserve()); err != nil {
+			return nil, err
+		}
+
+		res, err := t.base.RoundTrip(req)
+		if err != nil || res.StatusCode != http.StatusTooManyRequests || retry >= t.maxRetries {
+			return res, err
+		}
+
+		if req.Body != nil && req.GetBody == nil { // can't be sent again
+			return res, nil
+		}
+
+		delay := retryAfter(res, backoff)
+		if delay > t.maxBackoff {
+			delay = t.maxBackoff
+		}
+		backoff *= 2
+
+		res.Body.Close() // not read by anyone, the retry's response is returned instead
+		if req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		t.delay(delay) // throttling applies to all requests with the key, the retry waits for its slot
+	}
+}
+
+// reserve reserves a slot for a request and returns the time to wait for it.
+func (t *transport) reserve() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+
+	wait := t.next.Sub(now)
+	t.next = t.next.Add(t.interval)
+	return wait
+}
+
+// delay postpones all requests by at least d from now.
+func (t *transport) delay(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if until := time.Now().Add(d); t.next.Before(until) {
+		t.next = until
+	}
+}
+
+// retryAfter returns the delay requested by the Retry-After header of a throttled response in seconds, the fallback if there's none.
+func retryAfter(res *http.Response, fallback time.Duration) time.Duration {
+	if secs, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+
+	return fallback
+}
+
+// sleep waits for the duration, returning early with the error of the request's context if it's canceled.
+func sleep(req *http.Request, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,75 @@
+package tmdb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTransport_RoundTrip(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		opts     *LimitOptions
+		status   int
+		requests int32
+	}{
+		{name: "retried", opts: nil, status: http.StatusOK, requests: 3},
+		{name: "retries exhausted", opts: &LimitOptions{MaxRetries: 1}, status: http.StatusTooManyRequests, requests: 2},
+		{name: "retries disabled", opts: &LimitOptions{MaxRetries: -1}, status: http.StatusTooManyRequests, requests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+
+			client := &http.Client{Transport: NewTransport(nil, tt.opts)}
+			res, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = res.Body.Close()
+
+			if res.StatusCode != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, res.StatusCode)
+			}
+			if n := requests.Load(); n != tt.requests {
+				t.Errorf("expected %d requests, got %d", tt.requests, n)
+			}
+		})
+	}
+}
+
+func TestTransport_RateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(nil, &LimitOptions{RateLimit: 20})}
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		res, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = res.Body.Close()
+	}
+
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond { // 4 intervals of 50ms after the first request
+		t.Errorf("expected requests to be spaced out, 5 took %s", elapsed)
+	}
+}
@@ -0,0 +1,33 @@
+package media
+
+import (
+	"io"
+	"strings"
+)
+
+// Opener is implemented by media of remote repositories (S3, WebDAV, HTTP, ...), their Path is a URL rather than a local path.
+// Media with a URL readable by FFmpeg as-is (see repo.Features.Protocols) are converted without Opener,
+// others (authenticated, custom schemes such as "s3://bucket/key") are copied to a local file first.
+type Opener interface {
+	// Open opens the media file for reading.
+	Open() (io.ReadSeekCloser, error)
+}
+
+// URLScheme returns the lowercase scheme of a path that is a URL, such as "https", or an empty string for local paths.
+func URLScheme(path string) string {
+	scheme, _, ok := strings.Cut(path, "://")
+	if !ok || len(scheme) < 2 { // single letters are Windows drive letters
+		return ""
+	}
+
+	for i, c := range scheme { // ALPHA *( ALPHA / DIGIT / "+" / "-" / "." ), RFC 3986
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case i > 0 && ('0' <= c && c <= '9' || c == '+' || c == '-' || c == '.'):
+		default:
+			return ""
+		}
+	}
+
+	return strings.ToLower(scheme)
+}
@@ -0,0 +1,19 @@
+package media
+
+import "testing"
+
+func TestURLScheme(t *testing.T) {
+	for path, expected := range map[string]string{
+		"https://example.com/movie.mkv": "https",
+		"S3://bucket/movie.mkv":         "s3",
+		"webdav+https://host/movie.mkv": "webdav+https",
+		"/media/movie.mkv":              "",
+		`C:\media\movie.mkv`:            "",
+		"c://media/movie.mkv":           "",
+		"1ab://host/movie.mkv":          "",
+	} {
+		if scheme := URLScheme(path); scheme != expected {
+			t.Errorf("expected scheme %q of %s, got %q", expected, path, scheme)
+		}
+	}
+}
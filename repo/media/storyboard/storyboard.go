@@ -0,0 +1,128 @@
+// Package storyboard lays out trick-play thumbnails, video frames taken periodically, into sprite sheets
+// and writes WebVTT thumbnail tracks pointing at their tiles, for hover-seek previews in web players.
+package storyboard
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"time"
+)
+
+// MIME is the MIME type of sprite sheets.
+const MIME = "image/jpeg"
+
+// Layout is the shape of a storyboard.
+type Layout struct {
+	// Interval is the period between thumbnails.
+	Interval time.Duration `json:"interval"`
+	// Width and Height are the thumbnail dimensions in pixels, frames are letterboxed to fit.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// Columns and Rows are the numbers of thumbnails in a row and column of a sprite sheet.
+	Columns int `json:"columns"`
+	Rows    int `json:"rows"`
+}
+
+// Default is the storyboard layout of mux repositories, a 160x90 thumbnail every 10 seconds in sheets of 10x10.
+var Default = &Layout{Interval: 10 * time.Second, Width: 160, Height: 90, Columns: 10, Rows: 10}
+
+// Storyboard is the layout of the trick-play thumbnails of media.
+type Storyboard struct {
+	Layout
+	// Duration is the duration of the media.
+	Duration time.Duration `json:"duration"`
+	// Paths are the paths of the sprite sheet files in order, set by the repository that made them.
+	Paths []string `json:"-"`
+}
+
+// Thumbnails returns the number of thumbnails, the first one is at the start of the media.
+func (sb *Storyboard) Thumbnails() int {
+	n := int((sb.Duration + sb.Interval - 1) / sb.Interval)
+	if n < 1 {
+		return 1
+	}
+
+	return n
+}
+
+// Sheets returns the number of sprite sheets.
+func (sb *Storyboard) Sheets() int {
+	perSheet := sb.Columns * sb.Rows
+	return (sb.Thumbnails() + perSheet - 1) / perSheet
+}
+
+// Sheet returns the time of the first thumbnail of a sprite sheet and the number of its thumbnails.
+func (sb *Storyboard) Sheet(i int) (time.Duration, int) {
+	var (
+		perSheet = sb.Columns * sb.Rows
+		first    = i * perSheet
+		count    = sb.Thumbnails() - first
+	)
+	if count > perSheet {
+		count = perSheet
+	}
+
+	return time.Duration(first) * sb.Interval, count
+}
+
+// WriteSheet writes a JPEG sprite sheet of thumbnails, laid out row by row.
+// Rows without thumbnails are left out, so the last sheet may be shorter.
+func (l *Layout) WriteSheet(w io.Writer, thumbnails []*image.RGBA) error {
+	if len(thumbnails) == 0 || len(thumbnails) > l.Columns*l.Rows {
+		return fmt.Errorf("invalid thumbnail count %d", len(thumbnails))
+	}
+
+	rows := (len(thumbnails) + l.Columns - 1) / l.Columns
+	sheet := image.NewRGBA(image.Rect(0, 0, l.Columns*l.Width, rows*l.Height))
+	for i, thumbnail := range thumbnails {
+		x, y := (i%l.Columns)*l.Width, (i/l.Columns)*l.Height
+		draw.Draw(sheet, image.Rect(x, y, x+l.Width, y+l.Height), thumbnail, thumbnail.Bounds().Min, draw.Src)
+	}
+
+	if err := jpeg.Encode(w, sheet, &jpeg.Options{Quality: 75}); err != nil {
+		return errors.Wrap(err, "failed to encode sprite sheet")
+	}
+
+	return nil
+}
+
+// WriteTrack writes a WebVTT thumbnail track of a storyboard, each cue points at the tile of its thumbnail
+// with a media fragment ("<sheet URL>#xywh=<x>,<y>,<width>,<height>"), url returns the URL of a sprite sheet by its index.
+func WriteTrack(w io.Writer, sb *Storyboard, url func(sheet int) string) error {
+	if _, err := io.WriteString(w, "WEBVTT\n"); err != nil {
+		return err
+	}
+
+	var (
+		n        = sb.Thumbnails()
+		perSheet = sb.Columns * sb.Rows
+	)
+	for i := 0; i < n; i++ {
+		start, end := time.Duration(i)*sb.Interval, time.Duration(i+1)*sb.Interval
+		if end > sb.Duration && sb.Duration > start {
+			end = sb.Duration
+		}
+
+		tile := i % perSheet
+		_, err := fmt.Fprintf(
+			w, "\n%s --> %s\n%s#xywh=%d,%d,%d,%d\n",
+			formatTimestamp(start), formatTimestamp(end), url(i/perSheet),
+			(tile%sb.Columns)*sb.Width, (tile/sb.Columns)*sb.Height, sb.Width, sb.Height,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatTimestamp formats a duration as a WebVTT timestamp ("hh:mm:ss.ttt").
+func formatTimestamp(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3_600_000, ms/60_000%60, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,85 @@
+package storyboard
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStoryboard_Sheets(t *testing.T) {
+	sb := &Storyboard{Layout: *Default, Duration: 1005 * time.Second}
+	if n := sb.Thumbnails(); n != 101 {
+		t.Errorf("expected 101 thumbnails, got %d", n)
+	}
+	if n := sb.Sheets(); n != 2 {
+		t.Errorf("expected 2 sheets, got %d", n)
+	}
+	if start, count := sb.Sheet(1); start != 1000*time.Second || count != 1 {
+		t.Errorf("expected the last sheet to start at 1000s with 1 thumbnail, got %s and %d", start, count)
+	}
+
+	if n := (&Storyboard{Layout: *Default}).Thumbnails(); n != 1 {
+		t.Errorf("expected 1 thumbnail of empty media, got %d", n)
+	}
+}
+
+func TestLayout_WriteSheet(t *testing.T) {
+	l := &Layout{Width: 4, Height: 2, Columns: 3, Rows: 3}
+
+	thumbnails := make([]*image.RGBA, 4)
+	for i := range thumbnails {
+		thumbnails[i] = image.NewRGBA(image.Rect(0, 0, 4, 2))
+	}
+	thumbnails[3].Set(0, 0, color.White)
+
+	var buf bytes.Buffer
+	if err := l.WriteSheet(&buf, thumbnails); err != nil {
+		t.Fatal(err)
+	}
+
+	sheet, err := jpeg.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := sheet.Bounds().Size(); size != image.Pt(12, 4) {
+		t.Errorf("expected a 12x4 sheet of 2 rows, got %v", size)
+	}
+	if r, _, _, _ := sheet.At(0, 2).RGBA(); r < 0x8000 {
+		t.Error("expected the 4th thumbnail in the second row")
+	}
+
+	if err := l.WriteSheet(&buf, make([]*image.RGBA, 10)); err == nil {
+		t.Error("expected error of too many thumbnails")
+	}
+}
+
+func TestWriteTrack(t *testing.T) {
+	sb := &Storyboard{Layout: Layout{Interval: 10 * time.Second, Width: 160, Height: 90, Columns: 2, Rows: 1}, Duration: 25 * time.Second}
+
+	var buf bytes.Buffer
+	if err := WriteTrack(&buf, sb, func(sheet int) string { return fmt.Sprintf("storyboard/%d.jpg", sheet) }); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"WEBVTT",
+		"",
+		"00:00:00.000 --> 00:00:10.000",
+		"storyboard/0.jpg#xywh=0,0,160,90",
+		"",
+		"00:00:10.000 --> 00:00:20.000",
+		"storyboard/0.jpg#xywh=160,0,160,90",
+		"",
+		"00:00:20.000 --> 00:00:25.000",
+		"storyboard/1.jpg#xywh=0,0,160,90",
+		"",
+	}, "\n")
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,58 @@
+package media
+
+import (
+	"golang.org/x/text/language"
+	"strings"
+)
+
+const (
+	// SubtitleCodecSubRip is the FFmpeg name of the SubRip (.srt) subtitle codec.
+	SubtitleCodecSubRip = "subrip"
+	// SubtitleCodecASS is the FFmpeg name of the Advanced SubStation Alpha (.ass, .ssa) subtitle codec.
+	SubtitleCodecASS = "ass"
+	// SubtitleCodecWebVTT is the FFmpeg name of the WebVTT (.vtt) subtitle codec.
+	SubtitleCodecWebVTT = "webvtt"
+)
+
+// subtitleExtCodecs are the sidecar subtitle file extensions mapped to their codecs.
+var subtitleExtCodecs = map[string]string{
+	".srt": SubtitleCodecSubRip,
+	".ass": SubtitleCodecASS,
+	".ssa": SubtitleCodecASS,
+	".vtt": SubtitleCodecWebVTT,
+}
+
+// Subtitle is a subtitle track of media, embedded in the media file or in an external (sidecar) file.
+type Subtitle struct {
+	// ID is the track ID, unique within the media, alphanumeric, lowercase, non-blank ([a-z0-9-_]).
+	ID string
+	// Codec is the FFmpeg name of the subtitle codec, such as "subrip", "ass" or "hdmv_pgs_subtitle".
+	Codec string
+	// Language is the track language, language.Und if unknown.
+	Language language.Tag
+	// Path is the absolute path of the file containing the track, empty for embedded tracks that haven't been extracted.
+	Path string
+	// Stream is the stream index of an embedded track, -1 for sidecar tracks.
+	Stream int
+}
+
+// External checks whether the track is in a sidecar file.
+func (s *Subtitle) External() bool {
+	return s.Stream < 0
+}
+
+// Text checks whether the track is text-based, i.e. convertible to WebVTT.
+// Bitmap subtitles (PGS, VobSub) are not, they would need to go through OCR.
+func (s *Subtitle) Text() bool {
+	switch s.Codec {
+	case SubtitleCodecSubRip, SubtitleCodecASS, "ssa", SubtitleCodecWebVTT:
+		return true
+	}
+
+	return false
+}
+
+// SubtitleCodec returns the subtitle codec of a sidecar file extension (including the leading dot), empty if it's not a subtitle file.
+func SubtitleCodec(ext string) string {
+	return subtitleExtCodecs[strings.ToLower(ext)]
+}
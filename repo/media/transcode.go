@@ -0,0 +1,144 @@
+package media
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// TranscodeProfile is a target encoding of transcoded media.
+type TranscodeProfile struct {
+	// Format is the target container format.
+	Format *Format `json:"format"`
+	// VideoCodec is the target video encoder name, such as "libx264", empty keeps the source codec.
+	VideoCodec string `json:"video_codec"`
+	// AudioCodec is the target audio encoder name, such as "aac", empty keeps the source codec.
+	AudioCodec string `json:"audio_codec"`
+	// VideoBitRate is the target video bit rate in bits per second, zero means the encoder default.
+	VideoBitRate int64 `json:"video_bit_rate"`
+	// AudioBitRate is the target audio bit rate in bits per second, zero means the encoder default.
+	AudioBitRate int64 `json:"audio_bit_rate"`
+	// Width is the target video width in pixels, zero keeps the source width.
+	Width int `json:"width"`
+	// Height is the target video height in pixels, zero keeps the source height.
+	Height int `json:"height"`
+	// Start is the source timestamp to start transcoding at, for seeking ahead while streaming; zero transcodes from the beginning.
+	Start time.Duration `json:"start"`
+	// Watermark is the text and/or image overlaid on the video, nil for none; it requires a video codec.
+	Watermark *Watermark `json:"watermark"`
+	// Streams limits the transcoded streams to the video or audio ones, such as for separate DASH representations;
+	// empty keeps all streams.
+	Streams StreamSelection `json:"streams"`
+	// Fragmented is whether the output is a fragmented MP4 indexed by a leading segment index (sidx box),
+	// with fragments starting at keyframes forced in a fixed interval, for DASH on-demand streaming.
+	// It requires an MP4 family format.
+	Fragmented bool `json:"fragmented"`
+}
+
+// StreamSelection is a subset of the streams of transcoded media.
+type StreamSelection string
+
+const (
+	// StreamsVideo selects the video streams.
+	StreamsVideo StreamSelection = "video"
+	// StreamsAudio selects the first audio stream.
+	StreamsAudio StreamSelection = "audio"
+)
+
+// WatermarkPosition is the corner of a video a watermark is placed in.
+type WatermarkPosition string
+
+const (
+	WatermarkTopLeft     WatermarkPosition = "top-left"
+	WatermarkTopRight    WatermarkPosition = "top-right"
+	WatermarkBottomLeft  WatermarkPosition = "bottom-left"
+	WatermarkBottomRight WatermarkPosition = "bottom-right"
+	WatermarkCenter      WatermarkPosition = "center"
+)
+
+// Watermark is a text and/or image overlaid on transcoded video, such as the name of the watching user.
+type Watermark struct {
+	// Text is the overlaid text, none if empty.
+	Text string `json:"text"`
+	// Image is the absolute path of the overlaid image (i.e. a PNG with transparency), none if empty.
+	// The text is drawn over the image if both are set.
+	Image string `json:"image"`
+	// Position is the placement of the watermark, bottom-right if empty.
+	Position WatermarkPosition `json:"position"`
+	// Opacity is the opacity of the watermark between 0 and 1, opaque if it's not positive.
+	Opacity float64 `json:"opacity"`
+}
+
+// Key returns a short string uniquely identifying the profile's settings, usable in file names.
+// Profiles starting past the beginning get the start offset in milliseconds appended ("<settings>-<ms>"),
+// so each segment is cached separately.
+func (tp *TranscodeProfile) Key() string {
+	sum := md5.Sum([]byte(fmt.Sprintf(
+		"%s;%s;%s;%d;%d;%dx%d",
+		tp.Format.MIME, tp.VideoCodec, tp.AudioCodec, tp.VideoBitRate, tp.AudioBitRate, tp.Width, tp.Height,
+	)))
+
+	if wm := tp.Watermark; wm != nil { // appended, so that keys of profiles without one don't change
+		sum = md5.Sum([]byte(fmt.Sprintf("%x;%s;%s;%s;%g", sum, wm.Text, wm.Image, wm.Position, wm.Opacity)))
+	}
+	if tp.Streams != "" || tp.Fragmented {
+		sum = md5.Sum([]byte(fmt.Sprintf("%x;%s;%t", sum, tp.Streams, tp.Fragmented)))
+	}
+
+	key := hex.EncodeToString(sum[:4])
+	if tp.Start > 0 {
+		key = fmt.Sprintf("%s-%d", key, tp.Start.Milliseconds())
+	}
+
+	return key
+}
+
+// TranscodeOption is a setting of a TranscodeProfile beyond the target format and video codec,
+// not every conversion backend honors (see repo.Features.TranscodeOptions).
+type TranscodeOption string
+
+const (
+	// OptionAudioEncoding is the re-encoding of audio streams, set by AudioCodec.
+	OptionAudioEncoding TranscodeOption = "audio_encoding"
+	// OptionSeeking is starting past the beginning, set by Start.
+	OptionSeeking TranscodeOption = "seeking"
+	// OptionBitRate is rate control of re-encoded streams, set by VideoBitRate and AudioBitRate.
+	OptionBitRate TranscodeOption = "bit_rate"
+	// OptionResolution is scaling re-encoded video, set by Width and Height.
+	OptionResolution TranscodeOption = "resolution"
+	// OptionWatermark is overlaying a watermark on re-encoded video, set by Watermark.
+	OptionWatermark TranscodeOption = "watermark"
+	// OptionFragmenting is writing a fragmented MP4 of selected streams, set by Fragmented and Streams.
+	OptionFragmenting TranscodeOption = "fragmenting"
+)
+
+// TranscodeOptions returns all transcode options.
+func TranscodeOptions() []TranscodeOption {
+	return []TranscodeOption{OptionAudioEncoding, OptionSeeking, OptionBitRate, OptionResolution, OptionWatermark, OptionFragmenting}
+}
+
+// Options returns the transcode options the profile sets.
+func (tp *TranscodeProfile) Options() []TranscodeOption {
+	var opts []TranscodeOption
+	if tp.AudioCodec != "" {
+		opts = append(opts, OptionAudioEncoding)
+	}
+	if tp.Start > 0 {
+		opts = append(opts, OptionSeeking)
+	}
+	if tp.VideoBitRate > 0 || tp.AudioBitRate > 0 {
+		opts = append(opts, OptionBitRate)
+	}
+	if tp.Width > 0 || tp.Height > 0 {
+		opts = append(opts, OptionResolution)
+	}
+	if tp.Watermark != nil {
+		opts = append(opts, OptionWatermark)
+	}
+	if tp.Fragmented || tp.Streams != "" {
+		opts = append(opts, OptionFragmenting)
+	}
+
+	return opts
+}
@@ -0,0 +1,60 @@
+package media
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTranscodeProfile_Options(t *testing.T) {
+	if opts := (&TranscodeProfile{Format: FormatMP4, VideoCodec: "libx264"}).Options(); len(opts) != 0 {
+		t.Errorf("expected no options for a video codec, got %v", opts)
+	}
+
+	p := &TranscodeProfile{Format: FormatMP4, AudioCodec: "aac", Start: 90 * time.Second}
+	opts := p.Options()
+	if len(opts) != 2 || opts[0] != OptionAudioEncoding || opts[1] != OptionSeeking {
+		t.Errorf("unexpected options %v", opts)
+	}
+
+	p = &TranscodeProfile{Format: FormatMP4, VideoCodec: "libx264", VideoBitRate: 2_000_000, Height: 720}
+	opts = p.Options()
+	if len(opts) != 2 || opts[0] != OptionBitRate || opts[1] != OptionResolution {
+		t.Errorf("unexpected options %v", opts)
+	}
+
+	p = &TranscodeProfile{Format: FormatMP4, VideoCodec: "libx264", Watermark: &Watermark{Text: "alice"}}
+	opts = p.Options()
+	if len(opts) != 1 || opts[0] != OptionWatermark {
+		t.Errorf("unexpected options %v", opts)
+	}
+
+	p = &TranscodeProfile{Format: FormatMP4, AudioCodec: "aac", Streams: StreamsAudio, Fragmented: true}
+	opts = p.Options()
+	if len(opts) != 2 || opts[0] != OptionAudioEncoding || opts[1] != OptionFragmenting {
+		t.Errorf("unexpected options %v", opts)
+	}
+}
+
+func TestTranscodeProfile_Key(t *testing.T) {
+	base := &TranscodeProfile{Format: FormatMP4, VideoCodec: "libx264"}
+	if key := base.Key(); key != (&TranscodeProfile{Format: FormatMP4, VideoCodec: "libx264"}).Key() {
+		t.Errorf("expected stable key, got %s", key)
+	}
+
+	alice := &TranscodeProfile{Format: FormatMP4, VideoCodec: "libx264", Watermark: &Watermark{Text: "alice"}}
+	bob := &TranscodeProfile{Format: FormatMP4, VideoCodec: "libx264", Watermark: &Watermark{Text: "bob"}}
+	if alice.Key() == base.Key() || alice.Key() == bob.Key() {
+		t.Errorf("expected watermarks to be cached separately, got %s, %s and %s", base.Key(), alice.Key(), bob.Key())
+	}
+
+	seek := &TranscodeProfile{Format: FormatMP4, VideoCodec: "libx264", Watermark: &Watermark{Text: "alice"}, Start: 90 * time.Second}
+	if expected := alice.Key() + "-90000"; seek.Key() != expected {
+		t.Errorf("expected key %s, got %s", expected, seek.Key())
+	}
+
+	video := &TranscodeProfile{Format: FormatMP4, VideoCodec: "libx264", Streams: StreamsVideo, Fragmented: true}
+	audio := &TranscodeProfile{Format: FormatMP4, VideoCodec: "libx264", Streams: StreamsAudio, Fragmented: true}
+	if video.Key() == base.Key() || video.Key() == audio.Key() {
+		t.Errorf("expected stream selections to be cached separately, got %s, %s and %s", base.Key(), video.Key(), audio.Key())
+	}
+}
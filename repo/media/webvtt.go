@@ -0,0 +1,204 @@
+package media
+
+import (
+	"bufio"
+	"fmt"
+	"golang.org/x/exp/slices"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// srtTimingPattern matches a SubRip cue timing line, coordinates after the end time are ignored.
+	srtTimingPattern = regexp.MustCompile(`^\s*(\d+:\d{1,2}:\d{1,2}[,.]\d{1,3})\s*-->\s*(\d+:\d{1,2}:\d{1,2}[,.]\d{1,3})`)
+	// srtUnsupportedTagPattern matches SubRip tags that don't have a WebVTT equivalent.
+	srtUnsupportedTagPattern = regexp.MustCompile(`(?i)</?font[^>]*>|\{\\[^}]*}`)
+	// assOverridePattern matches ASS override blocks ("{\an8}", "{\i1}").
+	assOverridePattern = regexp.MustCompile(`\{[^}]*}`)
+
+	// assTextReplacer replaces ASS escape sequences with their plain text equivalents.
+	assTextReplacer = strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ")
+	// vttTextEscaper escapes characters with a special meaning in WebVTT cue text.
+	vttTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
+
+// cue is a timed piece of subtitle text.
+type cue struct {
+	start, end time.Duration
+	text       string
+}
+
+// WriteWebVTT converts text subtitles of a codec (SubRip, ASS or WebVTT) to WebVTT.
+func WriteWebVTT(w io.Writer, r io.Reader, codec string) error {
+	var (
+		cues []cue
+		err  error
+	)
+	switch codec {
+	case SubtitleCodecWebVTT:
+		_, err = io.Copy(w, r)
+		return err
+	case SubtitleCodecSubRip:
+		cues, err = parseSubRip(r)
+	case SubtitleCodecASS, "ssa":
+		cues, err = parseASS(r)
+	default:
+		return fmt.Errorf("unsupported subtitle codec %s", codec)
+	}
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString("WEBVTT\n")
+	for _, c := range cues {
+		_, _ = fmt.Fprintf(bw, "\n%s --> %s\n%s\n", formatVTTTime(c.start), formatVTTTime(c.end), c.text)
+	}
+
+	return bw.Flush()
+}
+
+func parseSubRip(r io.Reader) ([]cue, error) {
+	var (
+		cues    []cue
+		current *cue
+		lines   []string
+	)
+	flush := func() {
+		if current != nil && len(lines) > 0 {
+			current.text = strings.Join(lines, "\n")
+			cues = append(cues, *current)
+		}
+
+		current, lines = nil, nil
+	}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimRight(strings.TrimPrefix(s.Text(), "\ufeff"), "\r")
+		if match := srtTimingPattern.FindStringSubmatch(line); match != nil {
+			flush()
+
+			start, err := parseTime(match[1])
+			if err != nil {
+				return nil, err
+			}
+			end, err := parseTime(match[2])
+			if err != nil {
+				return nil, err
+			}
+
+			current = &cue{start: start, end: end}
+			continue
+		}
+		if current == nil { // cue index or garbage
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		lines = append(lines, strings.ReplaceAll(srtUnsupportedTagPattern.ReplaceAllString(line, ""), "-->", "->"))
+	}
+	flush()
+
+	return cues, s.Err()
+}
+
+func parseASS(r io.Reader) ([]cue, error) {
+	var (
+		cues   []cue
+		events bool
+		format = []string{"layer", "start", "end", "style", "name", "marginl", "marginr", "marginv", "effect", "text"}
+	)
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(s.Text(), "\ufeff"))
+		if strings.HasPrefix(line, "[") {
+			events = strings.EqualFold(line, "[Events]")
+			continue
+		}
+		if !events {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "format":
+			format = strings.Split(strings.ToLower(strings.ReplaceAll(value, " ", "")), ",")
+		case "dialogue":
+			fields := strings.SplitN(strings.TrimSpace(value), ",", len(format)) // text is last and can contain commas
+
+			var (
+				c   cue
+				err error
+			)
+			for i, field := range fields {
+				switch format[i] {
+				case "start":
+					c.start, err = parseTime(field)
+				case "end":
+					c.end, err = parseTime(field)
+				case "text":
+					c.text = strings.TrimSpace(assTextReplacer.Replace(assOverridePattern.ReplaceAllString(field, "")))
+					c.text = vttTextEscaper.Replace(c.text)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if c.text != "" {
+				cues = append(cues, c)
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(cues, func(a, b cue) int { // dialogue lines aren't required to be in order
+		return int(a.start - b.start)
+	})
+	return cues, nil
+}
+
+// parseTime parses a SubRip ("00:01:02,345") or ASS ("0:01:02.34") timestamp.
+func parseTime(s string) (time.Duration, error) {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(s), ",", "."), ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("malformed timestamp %s", s)
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("malformed timestamp %s", s)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("malformed timestamp %s", s)
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed timestamp %s", s)
+	}
+
+	return time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds*float64(time.Second)).Round(time.Millisecond), nil
+}
+
+// formatVTTTime formats a WebVTT timestamp ("00:01:02.345").
+func formatVTTTime(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
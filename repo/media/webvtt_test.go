@@ -0,0 +1,36 @@
+package media
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteWebVTT(t *testing.T) {
+	tests := map[string]struct {
+		codec, input, expected string
+	}{
+		"subrip": {
+			codec:    SubtitleCodecSubRip,
+			input:    "\ufeff1\r\n00:00:01,000 --> 00:00:02,500\r\n<font color=\"red\">Hello</font>\r\n<i>world</i>\r\n\r\n2\r\n00:01:00,000 --> 00:01:01,000\r\nBye\r\n",
+			expected: "WEBVTT\n\n00:00:01.000 --> 00:00:02.500\nHello\n<i>world</i>\n\n00:01:00.000 --> 00:01:01.000\nBye\n",
+		},
+		"ass": {
+			codec: SubtitleCodecASS,
+			input: "[Script Info]\nTitle: Test\n\n[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n" +
+				"Dialogue: 0,0:01:00.00,0:01:01.00,Default,,0,0,0,,Bye\n" +
+				"Dialogue: 0,0:00:01.00,0:00:02.50,Default,,0,0,0,,{\\i1}Hello, world{\\i0}\\N<3\n",
+			expected: "WEBVTT\n\n00:00:01.000 --> 00:00:02.500\nHello, world\n&lt;3\n\n00:01:00.000 --> 00:01:01.000\nBye\n",
+		},
+	}
+
+	for name, test := range tests {
+		var buf bytes.Buffer
+		if err := WriteWebVTT(&buf, strings.NewReader(test.input), test.codec); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if buf.String() != test.expected {
+			t.Errorf("%s: expected %q, got %q", name, test.expected, buf.String())
+		}
+	}
+}
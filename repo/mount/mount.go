@@ -0,0 +1,166 @@
+package mount
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// checkInterval is the period between checks of the repository root's availability.
+const checkInterval = 10 * time.Second
+
+// mountRepo is a wrapping repo.MutableRepository monitoring the availability of its root directory.
+// While it's unavailable, the repository is degraded (repo.Health): its media are kept, but scans and additions are refused,
+// so that an unmounted volume isn't mistaken for removed files.
+type mountRepo struct {
+	repo.MutableRepository
+
+	logger *zap.Logger
+
+	mu     sync.RWMutex
+	health repo.Health
+
+	stop chan struct{}
+	done chan struct{} // closed when the monitor stops
+}
+
+// NewRepository creates a repository monitoring the availability of its root directory, it recovers automatically
+// once the root is available again, rescanning it.
+func NewRepository(repo repo.MutableRepository, logger *zap.Logger) repo.MutableRepository {
+	if mr, ok := repo.(*mountRepo); ok {
+		return mr
+	}
+
+	mr := &mountRepo{
+		MutableRepository: repo,
+		logger:            logger,
+		stop:              make(chan struct{}),
+		done:              make(chan struct{}),
+	}
+	mr.check() // before the startup scan
+
+	go mr.monitor()
+	return mr
+}
+
+func (mr *mountRepo) Health() repo.Health {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	return mr.health
+}
+
+// unavailable returns an error if the repository is degraded.
+func (mr *mountRepo) unavailable() error {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	if mr.health.Degraded {
+		return &repo.ErrUnavailable{Path: mr.Path(), Reason: mr.health.Reason}
+	}
+
+	return nil
+}
+
+func (mr *mountRepo) monitor() {
+	defer close(mr.done)
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-mr.stop:
+			return
+		case <-ticker.C:
+		}
+
+		if mr.check() { // pick up changes made while it was unavailable
+			if err := mr.MutableRepository.Scan(repo.ScanIncremental); err != nil && mr.logger != nil {
+				mr.logger.Error("failed to scan recovered repository", zap.String("repo", mr.ID()), zap.Error(err))
+			}
+		}
+	}
+}
+
+// check checks the availability of the repository root, updating the health, returns true if the repository recovered.
+func (mr *mountRepo) check() (recovered bool) {
+	err := repo.CheckRoots(mr.MutableRepository)
+
+	mr.mu.Lock()
+	degraded := err != nil
+	if degraded == mr.health.Degraded {
+		mr.mu.Unlock()
+		return false
+	}
+
+	mr.health = repo.Health{Degraded: degraded, Since: time.Now()}
+	if degraded {
+		eu := err.(*repo.ErrUnavailable)
+		mr.health.Reason = eu.Reason
+		if len(mr.Roots()) > 1 { // name the unavailable one
+			mr.health.Reason = fmt.Sprintf("%s: %s", eu.Path, eu.Reason)
+		}
+	}
+	mr.mu.Unlock()
+
+	event := repo.Event{Type: repo.EventRepoRecovered, RepoID: mr.ID()}
+	if degraded {
+		event.Type = repo.EventRepoDegraded
+		if mr.logger != nil {
+			mr.logger.Warn("repository root unavailable, degrading", zap.String("repo", mr.ID()), zap.String("path", mr.Path()), zap.Error(err))
+		}
+	} else if mr.logger != nil {
+		mr.logger.Info("repository root available again, recovering", zap.String("repo", mr.ID()), zap.String("path", mr.Path()))
+	}
+	mr.Events().Publish(event)
+
+	return !degraded
+}
+
+func (mr *mountRepo) Scan(mode repo.ScanMode) error {
+	if err := mr.unavailable(); err != nil {
+		return err
+	}
+
+	return mr.MutableRepository.Scan(mode)
+}
+
+func (mr *mountRepo) Add(m media.Media) error {
+	if err := mr.unavailable(); err != nil {
+		return err
+	}
+
+	return mr.MutableRepository.Add(m)
+}
+
+func (mr *mountRepo) AddPath(path string) error {
+	if err := mr.unavailable(); err != nil {
+		return err
+	}
+
+	return mr.MutableRepository.AddPath(path)
+}
+
+func (mr *mountRepo) Move(m media.Media, path string) error {
+	if err := mr.unavailable(); err != nil {
+		return err
+	}
+
+	return mr.MutableRepository.Move(m, path)
+}
+
+// Close stops the monitor before closing the underlying repository.
+func (mr *mountRepo) Close() error {
+	close(mr.stop)
+	<-mr.done
+
+	return mr.MutableRepository.Close()
+}
+
+func (mr *mountRepo) Mutable() repo.MutableRepository {
+	return mr
+}
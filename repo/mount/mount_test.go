@@ -0,0 +1,76 @@
+package mount
+
+import (
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMountRepo_Degrade(t *testing.T) {
+	var (
+		root = t.TempDir()
+		path = filepath.Join(root, "Movie.mkv")
+	)
+	if err := os.WriteFile(path, []byte("movie"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(media.NewMedia("movie", path, nil, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []repo.EventType
+	r.Events().Subscribe(func(e repo.Event) {
+		events = append(events, e.Type)
+	})
+
+	mr := NewRepository(r, nil)
+	defer mr.Close()
+
+	if mr.Health().Degraded {
+		t.Fatal("expected available repository not to be degraded")
+	}
+
+	if err := os.Remove(path); err != nil { // unmounted, the mount point is left empty
+		t.Fatal(err)
+	}
+	if mr.(*mountRepo).check() {
+		t.Error("expected unavailable repository not to recover")
+	}
+
+	health := mr.Health()
+	if !health.Degraded || health.Reason == "" {
+		t.Fatalf("expected repository with an empty root to be degraded, got %+v", health)
+	}
+	if mr.Get("movie") == nil {
+		t.Error("expected media to be kept while degraded")
+	}
+
+	var eu *repo.ErrUnavailable
+	if err := mr.Scan(repo.ScanIncremental); !errors.As(err, &eu) {
+		t.Errorf("expected scan of degraded repository to fail with ErrUnavailable, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("movie"), 0644); err != nil { // mounted again
+		t.Fatal(err)
+	}
+	if !mr.(*mountRepo).check() {
+		t.Error("expected available repository to recover")
+	}
+	if mr.Health().Degraded {
+		t.Error("expected recovered repository not to be degraded")
+	}
+
+	expected := []repo.EventType{repo.EventRepoDegraded, repo.EventRepoRecovered}
+	if len(events) != len(expected) || events[0] != expected[0] || events[1] != expected[1] {
+		t.Errorf("expected events %v, got %v", expected, events)
+	}
+}
@@ -0,0 +1,55 @@
+package mux
+
+import (
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"golang.org/x/text/language"
+)
+
+func (mr *muxRepo) AudioTracks(id string) ([]*media.AudioTrack, error) {
+	m := mr.MutableRepository.Get(id)
+	if m == nil {
+		return nil, nil
+	}
+	if !mr.cap.Has(repo.CapabilityRemux) || !mr.readable(m) {
+		return []*media.AudioTrack{}, nil
+	}
+
+	if pe, ok := mr.probes.get(id, mr.MutableRepository.FileStat(id)); ok {
+		return pe.audioTracks(), nil
+	}
+
+	pe, err := mr.probe(m) // not probed ahead, e.g. added before probing was enabled
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read audio tracks")
+	}
+
+	return pe.audioTracks(), nil
+}
+
+func (mr *muxRepo) Query(q *repo.Query) ([]media.Media, int, error) {
+	if q.AudioLanguage == language.Und || !mr.cap.Has(repo.CapabilityRemux) {
+		return mr.MutableRepository.Query(q)
+	}
+
+	// selected ahead, the filter is called with the repository locked
+	selected := make(map[string]struct{})
+	for _, item := range mr.MutableRepository.Items() {
+		if pe, ok := mr.probes.get(item.ID(), mr.MutableRepository.FileStat(item.ID())); ok && pe.hasAudioLanguage(q.AudioLanguage) {
+			selected[item.ID()] = struct{}{}
+		}
+	}
+
+	q0, filter := *q, q.Filter
+	q0.AudioLanguage = language.Und
+	q0.Filter = func(m media.Media) bool {
+		if _, ok := selected[m.ID()]; !ok {
+			return false
+		}
+
+		return filter == nil || filter(m)
+	}
+
+	return mr.MutableRepository.Query(&q0)
+}
@@ -0,0 +1,209 @@
+package mux
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"go.uber.org/zap"
+	"image"
+	"time"
+)
+
+const (
+	// BackendLibav is the ID of the backend using the FFmpeg libraries through the katana-project/mux CGO bindings.
+	BackendLibav = "libav"
+	// BackendExec is the ID of the backend running the ffmpeg and ffprobe binaries.
+	BackendExec = "exec"
+)
+
+// Backend is a media conversion engine of mux repositories.
+// Operations report progress with repo.ReportProgress and log with repo.Logger of their context.
+type Backend interface {
+	// Name returns the backend ID.
+	Name() string
+	// Features returns the media processing components available to the backend.
+	Features() *repo.Features
+	// Unsupported checks whether the backend has the components required by the remux and transcode capabilities,
+	// returns the reason of a capability being unsupported or an empty string if it's supported.
+	Unsupported(c repo.Capability) string
+	// CanMux checks whether files of a format can be written.
+	CanMux(format *media.Format) bool
+	// CanDemux checks whether files of a format can be read.
+	CanDemux(format *media.Format) bool
+
+	// Remux copies the streams of a media file into a file of another format, streams unsupported by the format are stripped.
+	// Formats indexed after their samples may be written fragmented if the context carries a repo.PartialFunc.
+	Remux(ctx context.Context, src, dst string, format *media.Format) error
+	// Transcode re-encodes a media file according to a profile.
+	Transcode(ctx context.Context, src, dst string, profile *media.TranscodeProfile) error
+	// Preview encodes a short silent MP4 montage of segments spread over a media file, for hover previews.
+	Preview(ctx context.Context, src, dst string, profile *PreviewProfile) error
+	// Subtitles lists the embedded subtitle streams of a media file, their IDs are left empty.
+	Subtitles(path string) ([]*media.Subtitle, error)
+	// AudioTracks lists the embedded audio streams of a media file, their languages are language.Und if they aren't tagged.
+	AudioTracks(path string) ([]*media.AudioTrack, error)
+	// Chapters lists the chapter markers of a media file's container in order,
+	// returns repo.ErrUnsupportedFormat if the backend can't read them.
+	Chapters(path string) ([]*media.Chapter, error)
+	// ExtractSubtitle copies an embedded subtitle stream of a media file into a file of a text subtitle format.
+	ExtractSubtitle(ctx context.Context, src, dst string, stream int, format SubtitleFormat) error
+	// Tags reads the tags of a media file's container, such as "title", "artist" and "album", the keys are lowercase.
+	Tags(path string) (map[string]string, error)
+	// Info reads the stream count and duration of a media file.
+	Info(ctx context.Context, path string) (*ContainerInfo, error)
+	// Frames reads grayscale video frames spread evenly over a media file, scaled to width x height pixels,
+	// for perceptual hashing. Fewer frames are returned if some couldn't be decoded.
+	Frames(ctx context.Context, path string, count, width, height int) ([]*image.Gray, error)
+	// Thumbnails reads up to count video frames of a media file every interval from start, letterboxed to width x height pixels,
+	// for storyboards. Frames are taken from the nearest keyframes, fewer are returned past the end of the media.
+	Thumbnails(ctx context.Context, path string, start, interval time.Duration, count, width, height int) ([]*image.RGBA, error)
+	// ExtractCover copies the cover art (an attached picture) of a media file into an image file,
+	// returns false if the file has no cover art.
+	ExtractCover(ctx context.Context, src, dst string) (bool, error)
+}
+
+// ContainerInfo is the stream layout of a media file, checked to validate conversion outputs.
+type ContainerInfo struct {
+	// Streams is the number of streams, attached pictures (cover art) are excluded if the backend can tell them apart.
+	Streams int
+	// Duration is the duration of the media, zero if the backend can't read it.
+	Duration time.Duration
+}
+
+// PreviewProfile is the shape of a preview clip, a montage of short segments spread over a media file.
+type PreviewProfile struct {
+	// Segments is the number of segments, fewer are taken from media too short for them.
+	Segments int
+	// Length is the duration of each segment.
+	Length time.Duration
+	// Height is the video height in pixels, the aspect ratio is kept.
+	Height int
+}
+
+// DefaultPreview is the preview clip profile of mux repositories, three 5 second segments in 240p.
+var DefaultPreview = &PreviewProfile{Segments: 3, Length: 5 * time.Second, Height: 240}
+
+// segments returns the start times of the segments of a media file with a duration, spread evenly over it.
+// A single segment at the start is returned if the duration is unknown or shorter than the segments.
+func (pp *PreviewProfile) segments(duration time.Duration) []time.Duration {
+	n := pp.Segments
+	if n < 1 {
+		n = 1
+	}
+	if duration <= 0 || duration < time.Duration(n)*pp.Length {
+		return []time.Duration{0}
+	}
+
+	starts := make([]time.Duration, n)
+	for i := range starts { // centered in n equal parts, so that intros and credits are skipped
+		starts[i] = duration*time.Duration(2*i+1)/time.Duration(2*n) - pp.Length/2
+	}
+
+	return starts
+}
+
+// coverCodecs are the codecs of attached pictures (cover art).
+var coverCodecs = []string{"mjpeg", "png", "bmp", "gif", "webp"}
+
+// SubtitleFormat is a container format of an extracted text subtitle track.
+type SubtitleFormat struct {
+	// Name is the FFmpeg muxer name, such as "srt".
+	Name string
+	// Extension is the file extension, **without leading dots**.
+	Extension string
+	// MIME is the MIME type.
+	MIME string
+}
+
+// BackendOptions are the options of creating a backend.
+type BackendOptions struct {
+	// FFmpegPath and FFprobePath are the paths or names (looked up in PATH) of the binaries used by the exec backend,
+	// default to "ffmpeg" and "ffprobe".
+	FFmpegPath, FFprobePath string
+}
+
+// NewBackend creates a backend by its ID, an empty ID is the libav backend if it's available in the build, exec otherwise.
+func NewBackend(name string, opts *BackendOptions, logger *zap.Logger) (Backend, error) {
+	if opts == nil {
+		opts = &BackendOptions{}
+	}
+
+	switch name {
+	case "":
+		if libavAvailable {
+			return newLibavBackend(logger), nil
+		}
+
+		return newExecBackend(opts.FFmpegPath, opts.FFprobePath, logger)
+	case BackendLibav:
+		if !libavAvailable {
+			return nil, fmt.Errorf("backend %s is not available, the build lacks CGO", name)
+		}
+
+		return newLibavBackend(logger), nil
+	case BackendExec:
+		return newExecBackend(opts.FFmpegPath, opts.FFprobePath, logger)
+	}
+
+	return nil, fmt.Errorf("unknown backend %s", name)
+}
+
+// MissingMuxers returns the media formats that can't be remuxed or transcoded to by a backend.
+func MissingMuxers(b Backend) []*media.Format {
+	var missing []*media.Format
+	for _, f := range media.Formats() {
+		if !b.CanMux(f) {
+			missing = append(missing, f)
+		}
+	}
+
+	return missing
+}
+
+// FormatSupport is the availability of conversions of a media format by a backend.
+type FormatSupport struct {
+	// Format is the media format.
+	Format *media.Format
+	// Demux is whether files of the format can be read, as a source of conversions.
+	Demux bool
+	// Remux is whether streams can be copied into files of the format.
+	Remux bool
+	// Transcode is whether streams can be re-encoded into files of the format.
+	Transcode bool
+}
+
+// Support returns the availability of conversions of all default formats by a backend, in the order of media.Formats.
+func Support(b Backend) []FormatSupport {
+	transcode := b.Unsupported(repo.CapabilityTranscode) == ""
+
+	support := make([]FormatSupport, len(media.Formats()))
+	for i, f := range media.Formats() {
+		canMux := b.CanMux(f)
+		support[i] = FormatSupport{
+			Format:    f,
+			Demux:     b.CanDemux(f),
+			Remux:     canMux,
+			Transcode: canMux && transcode,
+		}
+	}
+
+	return support
+}
+
+// unsupported checks whether the components required by the remux and transcode capabilities are available,
+// see Backend.Unsupported.
+func unsupported(b Backend, c repo.Capability, hasVideoEncoder bool) string {
+	f := b.Features()
+	if len(f.Demuxers) == 0 {
+		return "FFmpeg demuxers missing"
+	}
+	if len(MissingMuxers(b)) == len(media.Formats()) {
+		return "FFmpeg muxers missing for all target formats"
+	}
+	if c.Has(repo.CapabilityTranscode) && (len(f.Decoders) == 0 || !hasVideoEncoder) {
+		return "FFmpeg video encoders or decoders missing"
+	}
+
+	return ""
+}
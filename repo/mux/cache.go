@@ -0,0 +1,484 @@
+package mux
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Layout is a naming layout of cache files.
+type Layout string
+
+const (
+	// LayoutFlat is the layout of cache files stored directly in the operation directory ("remux/<hash>.mp4").
+	LayoutFlat Layout = "flat"
+	// LayoutFormat is the layout of cache files stored in a subdirectory per target format ("remux/mp4/<hash>.mp4").
+	LayoutFormat Layout = "format"
+)
+
+const (
+	// partSuffix is the file name suffix of cache files being made.
+	partSuffix = ".part"
+	// anyFormat is the retention key matching all formats without their own retention.
+	anyFormat = "*"
+	// defaultInterval is the default period between janitor runs.
+	defaultInterval = time.Hour
+)
+
+// CachePolicy is a naming and retention policy of the operation cache.
+type CachePolicy struct {
+	// Layout is the naming layout of new cache files, defaults to LayoutFlat.
+	// Files made with a different layout are still found and cleaned up.
+	Layout Layout
+	// Remux and Transcode are the retention periods of remuxed and transcoded files since their last use,
+	// keyed by the target format extension (e.g. "mp4") or "*" for any other format.
+	// Files without a retention period are kept for as long as their source media exists.
+	Remux, Transcode map[string]time.Duration
+	// Interval is the period between janitor runs, expired files are only removed when the janitor runs, defaults to 1 hour.
+	Interval time.Duration
+	// MaxBytes is the size limit of the cache, the least recently used files are removed when a file is made
+	// or the janitor runs and it's exceeded, zero means no limit.
+	MaxBytes int64
+	// MaxAge is the retention period of all cache files since their last use, shortening longer per-format periods,
+	// zero means no limit.
+	MaxAge time.Duration
+}
+
+// normalize validates the policy and makes a copy with lower-case retention keys.
+func (cp *CachePolicy) normalize() (*CachePolicy, error) {
+	if cp == nil {
+		return &CachePolicy{Layout: LayoutFlat}, nil
+	}
+
+	res := &CachePolicy{
+		Layout:    cp.Layout,
+		Remux:     make(map[string]time.Duration, len(cp.Remux)),
+		Transcode: make(map[string]time.Duration, len(cp.Transcode)),
+		Interval:  cp.Interval,
+		MaxBytes:  cp.MaxBytes,
+		MaxAge:    cp.MaxAge,
+	}
+	if res.Interval <= 0 {
+		res.Interval = defaultInterval
+	}
+
+	switch res.Layout {
+	case "":
+		res.Layout = LayoutFlat
+	case LayoutFlat, LayoutFormat:
+	default:
+		return nil, fmt.Errorf("unknown cache layout %s", cp.Layout)
+	}
+
+	for k, v := range cp.Remux {
+		res.Remux[strings.ToLower(k)] = v
+	}
+	for k, v := range cp.Transcode {
+		res.Transcode[strings.ToLower(k)] = v
+	}
+
+	return res, nil
+}
+
+// expires checks whether any cache files are subject to expiry.
+func (cp *CachePolicy) expires() bool {
+	if cp.MaxAge > 0 {
+		return true
+	}
+	for _, retention := range []map[string]time.Duration{cp.Remux, cp.Transcode} {
+		for _, v := range retention {
+			if v > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// cachePath makes the path of a cache file ("<name>.<ext>") in an operation directory according to the layout.
+// The parent directories are created if needed.
+func (mr *muxRepo) cachePath(dir, name, ext string) (string, error) {
+	if mr.cache.Layout == LayoutFormat {
+		dir = filepath.Join(dir, ext)
+		if err := os.MkdirAll(dir, 0); err != nil {
+			return "", errors.Wrap(err, "failed to make format directory")
+		}
+	}
+
+	return filepath.Join(dir, name+"."+ext), nil
+}
+
+// produce makes a cache file using the make function, unless it exists already.
+// The file is made under a temporary name (<dst>.part), flushed and renamed when complete, so it's never used partially.
+// Concurrent calls for the same file wait for the first one, unless the context is marked with repo.WithNoWait,
+// in which case repo.ErrNotReady is returned; it's returned for a file not made yet if it's marked with repo.WithCachedOnly.
+// The temporary name is passed to the context's repo.PartialFunc right before the file is made, by the call holding the file's lock,
+// a context carrying one always waits, calls waiting for another one making the file don't pass it.
+// The context passed to the make function is also canceled when the repository is closed, repo.ErrClosed is returned afterward.
+func (mr *muxRepo) produce(ctx context.Context, dst string, make func(ctx context.Context, tmp string) error) error {
+	if _, err := os.Stat(dst); err == nil { // FAST PATH: already made
+		mr.touch(dst)
+		return nil
+	}
+	if repo.CachedOnly(ctx) {
+		return &repo.ErrNotReady{Path: dst}
+	}
+
+	partial := repo.Partial(ctx)
+	if !mr.ops.Enter() {
+		return repo.ErrClosed
+	}
+	defer mr.ops.Leave()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-mr.ctx.Done(): // closing
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var made bool
+	action := func() (interface{}, error) {
+		if _, err := os.Stat(dst); err == nil { // made while waiting
+			mr.touch(dst)
+			return nil, nil
+		}
+
+		tmp := dst + partSuffix
+		if partial != nil { // made by this call, the file is written readable
+			partial(tmp)
+		}
+		if err := make(ctx, tmp); err != nil {
+			if err0 := os.Remove(tmp); err0 != nil && !errors.Is(err0, fs.ErrNotExist) {
+				err = multierr.Append(err, errors.Wrap(err0, "failed to remove partial file"))
+			}
+
+			return nil, err
+		}
+		if err := syncFile(tmp); err != nil { // a crash right after the rename mustn't leave an empty file behind
+			_ = os.Remove(tmp)
+			return nil, err
+		}
+		if err := os.Rename(tmp, dst); err != nil {
+			return nil, errors.Wrap(err, "failed to rename complete file")
+		}
+
+		made = true
+		return nil, nil
+	}
+
+	var err error
+	if repo.NoWait(ctx) && partial == nil { // the partial file is read while waiting
+		var ok bool
+		if _, ok, err = mr.mu.TryDo(dst, action); !ok {
+			return &repo.ErrNotReady{Path: dst}
+		}
+	} else {
+		_, err = mr.mu.Do(dst, action)
+	}
+	if err == nil && made {
+		mr.enforceLimit(dst)
+	}
+
+	return err
+}
+
+// syncFile flushes a written file to the disk.
+func syncFile(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return errors.Wrap(err, "failed to open file")
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "failed to sync file")
+	}
+
+	return f.Close()
+}
+
+// touch marks a cache file as used, postponing its expiry.
+func (mr *muxRepo) touch(path string) {
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil && mr.logger != nil {
+		mr.logger.Warn("failed to touch cache file", zap.String("path", path), zap.Error(err))
+	}
+}
+
+// retention returns the retention period of a cache file, zero if it doesn't expire.
+func (mr *muxRepo) retention(path string) time.Duration {
+	retention := mr.cache.Remux
+	if mr.transcodePath != "" && strings.HasPrefix(path, mr.transcodePath+string(filepath.Separator)) {
+		retention = mr.cache.Transcode
+	}
+
+	v, ok := retention[strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))]
+	if !ok {
+		v = retention[anyFormat]
+	}
+	if mr.cache.MaxAge > 0 && (v <= 0 || v > mr.cache.MaxAge) {
+		v = mr.cache.MaxAge
+	}
+
+	return v
+}
+
+// expired checks whether a cache file hasn't been used for longer than its retention period.
+func (mr *muxRepo) expired(path string, d fs.DirEntry) (bool, error) {
+	retention := mr.retention(path)
+	if retention <= 0 {
+		return false, nil
+	}
+
+	fi, err := d.Info()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) { // removed in the meantime
+			return false, nil
+		}
+
+		return false, errors.Wrap(err, "failed to stat file")
+	}
+
+	return time.Since(fi.ModTime()) > retention, nil
+}
+
+// removeCacheFile removes a cache file, logging the reason.
+func (mr *muxRepo) removeCacheFile(path, reason string) error {
+	_, err := mr.mu.Do(path, func() (interface{}, error) {
+		return nil, os.Remove(path)
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if mr.logger != nil {
+		mr.logger.Info(
+			reason,
+			zap.String("repo", mr.MutableRepository.ID()),
+			zap.String("repo_path", mr.MutableRepository.Path()),
+			zap.String("path", path),
+		)
+	}
+
+	return nil
+}
+
+// removeIdleCacheFile removes a cache file like removeCacheFile, unless it's being made or removed already.
+// Returns whether the file was removed.
+func (mr *muxRepo) removeIdleCacheFile(path, reason string) (bool, error) {
+	_, ok, err := mr.mu.TryDo(path, func() (interface{}, error) {
+		return nil, os.Remove(path)
+	})
+	if !ok || errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if mr.logger != nil {
+		mr.logger.Info(
+			reason,
+			zap.String("repo", mr.MutableRepository.ID()),
+			zap.String("repo_path", mr.MutableRepository.Path()),
+			zap.String("path", path),
+		)
+	}
+
+	return true, nil
+}
+
+// removePartFile removes a cache file left partially made by an interrupted operation.
+func (mr *muxRepo) removePartFile(path string) error {
+	var (
+		dst      = strings.TrimSuffix(path, partSuffix)
+		_, ok, _ = mr.mu.TryDo(dst, func() (interface{}, error) { return nil, nil })
+	)
+	if !ok { // still being made
+		return nil
+	}
+
+	return mr.removeCacheFile(path, "removed partial cache file")
+}
+
+// cacheFile is a complete cache file.
+type cacheFile struct {
+	path string
+	size int64
+	used time.Time // modification time, bumped by touch
+}
+
+// cacheFiles lists the complete cache files, partial ones are skipped.
+func (mr *muxRepo) cacheFiles() ([]cacheFile, error) {
+	var files []cacheFile
+	err := mr.walkCache(func(path string, d fs.DirEntry) error {
+		if strings.HasSuffix(path, partSuffix) {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) { // removed in the meantime
+				return nil
+			}
+
+			return errors.Wrap(err, "failed to stat file")
+		}
+
+		files = append(files, cacheFile{path: path, size: fi.Size(), used: fi.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to walk cache files")
+	}
+
+	return files, nil
+}
+
+// evict removes the least recently used cache files until the cache fits its size limit.
+// The keep file (i.e. the one just made) and files being made are never removed.
+func (mr *muxRepo) evict(keep string) error {
+	if mr.cache.MaxBytes <= 0 {
+		return nil
+	}
+
+	files, err := mr.cacheFiles()
+	if err != nil {
+		return err
+	}
+
+	var size int64
+	for _, f := range files {
+		size += f.size
+	}
+	if size <= mr.cache.MaxBytes {
+		return nil
+	}
+
+	slices.SortFunc(files, func(a, b cacheFile) int {
+		return a.used.Compare(b.used)
+	})
+	for _, f := range files {
+		if size <= mr.cache.MaxBytes {
+			break
+		}
+		if f.path == keep {
+			continue
+		}
+
+		removed, err := mr.removeIdleCacheFile(f.path, "evicted least recently used cache file")
+		if err != nil {
+			return err
+		}
+		if removed {
+			size -= f.size
+		}
+	}
+
+	return nil
+}
+
+// enforceLimit evicts cache files after one was made, logging failures.
+func (mr *muxRepo) enforceLimit(keep string) {
+	if err := mr.evict(keep); err != nil && mr.logger != nil {
+		mr.logger.Error(
+			"failed to evict cache files",
+			zap.String("repo", mr.MutableRepository.ID()),
+			zap.String("path", mr.path),
+			zap.Error(err),
+		)
+	}
+}
+
+// CacheUsage returns the number and size of the complete cache files and the size limit.
+func (mr *muxRepo) CacheUsage() (*repo.CacheUsage, error) {
+	files, err := mr.cacheFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &repo.CacheUsage{Files: len(files), MaxBytes: mr.cache.MaxBytes, MaxAge: mr.cache.MaxAge}
+	for _, f := range files {
+		usage.Bytes += f.size
+	}
+
+	return usage, nil
+}
+
+// PurgeCache removes all complete cache files, except ones being made or removed concurrently.
+func (mr *muxRepo) PurgeCache() (*repo.CacheUsage, error) {
+	files, err := mr.cacheFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &repo.CacheUsage{MaxBytes: mr.cache.MaxBytes, MaxAge: mr.cache.MaxAge}
+	for _, f := range files {
+		removed, err := mr.removeIdleCacheFile(f.path, "purged cache file")
+		if err != nil {
+			return nil, err
+		}
+		if removed {
+			usage.Files++
+			usage.Bytes += f.size
+		}
+	}
+
+	return usage, nil
+}
+
+// clean removes expired and leftover partial cache files, then evicts files over the size limit.
+func (mr *muxRepo) clean() error {
+	err := mr.walkCache(func(path string, d fs.DirEntry) error {
+		if strings.HasSuffix(path, partSuffix) {
+			return mr.removePartFile(path)
+		}
+
+		expired, err := mr.expired(path, d)
+		if err != nil || !expired {
+			return err
+		}
+
+		return mr.removeCacheFile(path, "removed expired cache file")
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to walk + delete expired files")
+	}
+
+	return mr.evict("")
+}
+
+// janitor periodically cleans the cache until the context is canceled.
+func (mr *muxRepo) janitor(ctx context.Context) {
+	ticker := time.NewTicker(mr.cache.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := mr.clean(); err != nil && mr.logger != nil {
+				mr.logger.Error(
+					"failed to clean cache",
+					zap.String("repo", mr.MutableRepository.ID()),
+					zap.String("path", mr.path),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+}
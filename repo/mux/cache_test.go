@@ -0,0 +1,50 @@
+package mux
+
+import (
+	"context"
+	"github.com/katana-project/katana/repo"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMuxRepo_ProducePartial(t *testing.T) {
+	mr := &muxRepo{cache: &CachePolicy{Layout: LayoutFlat}, ctx: context.Background()}
+	dst := filepath.Join(t.TempDir(), "test.mp4")
+
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+		done    = make(chan error, 1)
+		paths   []string
+	)
+	go func() {
+		ctx := repo.WithPartial(context.Background(), func(path string) { paths = append(paths, path) })
+		done <- mr.produce(ctx, dst, func(_ context.Context, tmp string) error {
+			close(started)
+			<-release
+			return os.WriteFile(tmp, []byte("test"), 0644)
+		})
+	}()
+	<-started
+
+	waited := make(chan error, 1)
+	go func() {
+		ctx := repo.WithPartial(context.Background(), func(path string) { t.Errorf("unexpected partial file %s of a waiting call", path) })
+		waited <- mr.produce(ctx, dst, func(_ context.Context, _ string) error {
+			t.Error("expected the file made while waiting not to be made again")
+			return nil
+		})
+	}()
+	close(release)
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := <-waited; err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != dst+partSuffix {
+		t.Errorf("expected the partial file to be passed once by the making call, got %v", paths)
+	}
+}
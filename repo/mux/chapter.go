@@ -0,0 +1,28 @@
+package mux
+
+import (
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+)
+
+func (mr *muxRepo) Chapters(id string) ([]*media.Chapter, error) {
+	m := mr.MutableRepository.Get(id)
+	if m == nil {
+		return nil, nil
+	}
+	if !mr.cap.Has(repo.CapabilityRemux) || !mr.readable(m) {
+		return []*media.Chapter{}, nil
+	}
+
+	if pe, ok := mr.probes.get(id, mr.MutableRepository.FileStat(id)); ok {
+		return pe.chapters()
+	}
+
+	pe, err := mr.probe(m) // not probed ahead, e.g. added before probing was enabled
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read chapters")
+	}
+
+	return pe.chapters()
+}
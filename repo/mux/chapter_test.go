@@ -0,0 +1,140 @@
+package mux
+
+import (
+	"encoding/json"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// chapterBackend is a Backend probing fixed chapters, calls of other operations panic.
+type chapterBackend struct {
+	Backend
+
+	chapters []*media.Chapter
+	err      error
+	probed   int
+}
+
+func (cb *chapterBackend) Features() *repo.Features {
+	return &repo.Features{}
+}
+
+func (cb *chapterBackend) Subtitles(_ string) ([]*media.Subtitle, error) {
+	return []*media.Subtitle{}, nil
+}
+
+func (cb *chapterBackend) AudioTracks(_ string) ([]*media.AudioTrack, error) {
+	return []*media.AudioTrack{}, nil
+}
+
+func (cb *chapterBackend) Chapters(_ string) ([]*media.Chapter, error) {
+	cb.probed++
+	return cb.chapters, cb.err
+}
+
+// newChapterRepo creates a remux-capable repository of one media file, probed with the backend.
+func newChapterRepo(t *testing.T, backend Backend) *muxRepo {
+	root := t.TempDir()
+	path := filepath.Join(root, "Test.mkv")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(media.NewMedia("test", path, nil, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+
+	probes, err := newProbeStore(filepath.Join(t.TempDir(), probesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &muxRepo{MutableRepository: r, cap: repo.CapabilityRemux, backend: backend, probes: probes}
+}
+
+func TestProbeResult_Chapters(t *testing.T) {
+	out := `{"chapters": [
+		{"start_time": "0.000000", "end_time": "90.500000", "tags": {"title": "Opening"}},
+		{"start_time": "90.500000", "end_time": "N/A"},
+		{"start_time": "90.500000", "end_time": "600.000000"}
+	]}`
+
+	var res probeResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatal(err)
+	}
+
+	chapters := res.chapters()
+	if len(chapters) != 2 {
+		t.Fatalf("expected 2 chapters, the one with an unparseable time left out, got %d", len(chapters))
+	}
+	if c := chapters[0]; c.Title != "Opening" || c.Start != 0 || c.End != 90500*time.Millisecond {
+		t.Errorf("unexpected first chapter %+v", c)
+	}
+	if c := chapters[1]; c.Title != "" || c.Start != 90500*time.Millisecond || c.End != 10*time.Minute {
+		t.Errorf("unexpected second chapter %+v", c)
+	}
+}
+
+func TestProbeEntry_Chapters(t *testing.T) {
+	pe := &probeEntry{Chapters: []probeChapter{{Title: "Opening", Start: 0, End: time.Minute}}}
+	chapters, err := pe.chapters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chapters) != 1 || chapters[0].Title != "Opening" || chapters[0].End != time.Minute {
+		t.Errorf("unexpected chapters %+v", chapters)
+	}
+
+	var euf *repo.ErrUnsupportedFormat
+	if _, err := (&probeEntry{Chapters: []probeChapter{}, NoChapters: true}).chapters(); !errors.As(err, &euf) {
+		t.Errorf("expected unsupported format error, got %v", err)
+	}
+}
+
+func TestMuxRepo_Chapters(t *testing.T) {
+	backend := &chapterBackend{chapters: []*media.Chapter{{Title: "Opening", End: time.Minute}, {Start: time.Minute, End: 2 * time.Minute}}}
+	mr := newChapterRepo(t, backend)
+
+	for i := 0; i < 2; i++ {
+		chapters, err := mr.Chapters("test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(chapters) != 2 || chapters[0].Title != "Opening" || chapters[1].Start != time.Minute {
+			t.Errorf("unexpected chapters %+v", chapters)
+		}
+	}
+	if backend.probed != 1 {
+		t.Errorf("expected the file to be probed once, got %d", backend.probed)
+	}
+
+	if chapters, err := mr.Chapters("missing"); chapters != nil || err != nil {
+		t.Errorf("expected nil for missing media, got %v, %v", chapters, err)
+	}
+}
+
+func TestMuxRepo_ChaptersUnsupported(t *testing.T) {
+	backend := &chapterBackend{err: &repo.ErrUnsupportedFormat{Format: "container", Operation: "chapter reading"}}
+	mr := newChapterRepo(t, backend)
+
+	var euf *repo.ErrUnsupportedFormat
+	for i := 0; i < 2; i++ {
+		if _, err := mr.Chapters("test"); !errors.As(err, &euf) {
+			t.Errorf("expected unsupported format error, got %v", err)
+		}
+	}
+	if backend.probed != 1 {
+		t.Errorf("expected the file to be probed once, the entry kept without chapters, got %d", backend.probed)
+	}
+}
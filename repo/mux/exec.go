@@ -0,0 +1,826 @@
+package mux
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+	"golang.org/x/text/language"
+	"image"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// execFormat is a container format written and read by the ffmpeg binary.
+type execFormat struct {
+	// muxer and demuxer are the FFmpeg muxer and demuxer names.
+	muxer, demuxer string
+	// subtitles is whether the format can carry the text and bitmap subtitle codecs found in media files.
+	subtitles bool
+	// indexed is whether the format's index is written after the samples, unless it's fragmented (the MP4 family).
+	indexed bool
+}
+
+// execFormats are the media.Formats mapped to their ffmpeg binary variants.
+var execFormats = map[*media.Format]execFormat{
+	media.FormatMP4:    {muxer: "mp4", demuxer: "mp4", indexed: true}, // only supports mov_text subtitles
+	media.FormatMKV:    {muxer: "matroska", demuxer: "matroska", subtitles: true},
+	media.FormatWebM:   {muxer: "webm", demuxer: "webm"}, // only supports WebVTT subtitles
+	media.FormatMPEGTS: {muxer: "mpegts", demuxer: "mpegts"},
+	media.FormatOGG:    {muxer: "ogg", demuxer: "ogg"},
+	media.FormatAVI:    {muxer: "avi", demuxer: "avi"},
+	media.FormatMOV:    {muxer: "mov", demuxer: "mov", indexed: true},
+	media.FormatOGA:    {muxer: "oga", demuxer: "ogg"},
+	media.FormatFLAC:   {muxer: "flac", demuxer: "flac"},
+	media.FormatMP3:    {muxer: "mp3", demuxer: "mp3"},
+	media.FormatM4A:    {muxer: "ipod", demuxer: "m4a", indexed: true}, // the M4A flavor of MP4
+}
+
+// execBackend is a Backend running the ffmpeg and ffprobe binaries, for builds and platforms without the CGO bindings.
+type execBackend struct {
+	ffmpeg, ffprobe string
+	logger          *zap.Logger
+
+	features        *repo.Features
+	hasVideoEncoder bool
+}
+
+func newExecBackend(ffmpegPath, ffprobePath string, logger *zap.Logger) (Backend, error) {
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	if ffprobePath == "" {
+		ffprobePath = "ffprobe"
+	}
+
+	ffmpegPath, err := exec.LookPath(ffmpegPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find ffmpeg binary")
+	}
+	ffprobePath, err = exec.LookPath(ffprobePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find ffprobe binary")
+	}
+
+	eb := &execBackend{ffmpeg: ffmpegPath, ffprobe: ffprobePath, logger: logger}
+	if err := eb.probe(); err != nil {
+		return nil, errors.Wrap(err, "failed to probe ffmpeg components")
+	}
+
+	return eb, nil
+}
+
+func (eb *execBackend) Name() string {
+	return BackendExec
+}
+
+func (eb *execBackend) Features() *repo.Features {
+	return eb.features
+}
+
+func (eb *execBackend) Unsupported(c repo.Capability) string {
+	return unsupported(eb, c, eb.hasVideoEncoder)
+}
+
+func (eb *execBackend) CanMux(format *media.Format) bool {
+	ef, ok := execFormats[format]
+	return ok && slices.Contains(eb.features.Muxers, ef.muxer)
+}
+
+func (eb *execBackend) CanDemux(format *media.Format) bool {
+	ef, ok := execFormats[format]
+	return ok && slices.Contains(eb.features.Demuxers, ef.demuxer)
+}
+
+// probe lists the components available to the ffmpeg binary.
+func (eb *execBackend) probe() error {
+	eb.features = &repo.Features{TranscodeOptions: media.TranscodeOptions(), Fragmenting: true, Storyboards: true, Previews: true}
+
+	formats, err := eb.list("-formats", "--")
+	if err != nil {
+		return err
+	}
+	for _, f := range formats { // flags: D = demuxing, E = muxing
+		if strings.ContainsRune(f.flags, 'D') {
+			eb.features.Demuxers = append(eb.features.Demuxers, f.names...)
+		}
+		if strings.ContainsRune(f.flags, 'E') {
+			eb.features.Muxers = append(eb.features.Muxers, f.names...)
+		}
+	}
+
+	encoders, err := eb.list("-encoders", "------")
+	if err != nil {
+		return err
+	}
+	for _, e := range encoders { // the first flag is the type: V = video, A = audio, S = subtitle
+		eb.features.Encoders = append(eb.features.Encoders, e.names...)
+		if strings.HasPrefix(e.flags, "V") {
+			eb.hasVideoEncoder = true
+		}
+	}
+
+	decoders, err := eb.list("-decoders", "------")
+	if err != nil {
+		return err
+	}
+	for _, d := range decoders {
+		eb.features.Decoders = append(eb.features.Decoders, d.names...)
+	}
+
+	out, err := eb.output(context.Background(), eb.ffmpeg, "-hide_banner", "-protocols")
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(out, "\n") { // "Supported file protocols:", "Input:", <names>, "Output:", <names>
+		line = strings.TrimSpace(line)
+		if line == "Output:" {
+			break
+		}
+		if line != "" && !strings.HasSuffix(line, ":") && line != "file" { // local paths are always readable
+			eb.features.Protocols = append(eb.features.Protocols, line)
+		}
+	}
+
+	out, err = eb.output(context.Background(), eb.ffmpeg, "-hide_banner", "-hwaccels")
+	if err != nil {
+		return err
+	}
+	for i, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); i > 0 && line != "" { // skip the "Hardware acceleration methods:" heading
+			eb.features.HWAccels = append(eb.features.HWAccels, line)
+		}
+	}
+	slices.Sort(eb.features.HWAccels)
+
+	return nil
+}
+
+// listEntry is an entry of a component list printed by the ffmpeg binary.
+type listEntry struct {
+	flags string
+	names []string
+}
+
+// list runs an ffmpeg component listing option, such as "-encoders", entries start after the separator line.
+func (eb *execBackend) list(option, separator string) ([]listEntry, error) {
+	out, err := eb.output(context.Background(), eb.ffmpeg, "-hide_banner", option)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		entries []listEntry
+		started bool
+	)
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if !started {
+			started = line == separator
+			continue
+		}
+
+		// " DE matroska,webm   Matroska / WebM", " V....D libx264   libx264 H.264 / AVC ..."
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		entries = append(entries, listEntry{flags: fields[0], names: strings.Split(fields[1], ",")})
+	}
+
+	return entries, nil
+}
+
+// output runs a binary and returns its standard output.
+func (eb *execBackend) output(ctx context.Context, name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", commandError(ctx, cmd, err, &stderr)
+	}
+
+	return stdout.String(), nil
+}
+
+// probeResult is the JSON output of ffprobe.
+type probeResult struct {
+	Streams  []probeStream `json:"streams"`
+	Chapters []struct {
+		StartTime string `json:"start_time"`
+		EndTime   string `json:"end_time"`
+		Tags      struct {
+			Title string `json:"title"`
+		} `json:"tags"`
+	} `json:"chapters"`
+	Format struct {
+		Duration string            `json:"duration"`
+		Tags     map[string]string `json:"tags"`
+	} `json:"format"`
+}
+
+// probeStream is a stream in the JSON output of ffprobe.
+type probeStream struct {
+	Index     int    `json:"index"`
+	CodecType string `json:"codec_type"`
+	CodecName string `json:"codec_name"`
+	Tags      struct {
+		Language string `json:"language"`
+		Title    string `json:"title"`
+	} `json:"tags"`
+	Disposition struct {
+		AttachedPic int `json:"attached_pic"`
+	} `json:"disposition"`
+}
+
+// probeFile reads the streams, chapters, duration and tags of a media file with ffprobe.
+func (eb *execBackend) probeFile(ctx context.Context, path string) (*probeResult, error) {
+	out, err := eb.output(
+		ctx, eb.ffprobe,
+		"-v", "error",
+		"-show_entries", "format=duration:format_tags:stream=index,codec_type,codec_name:stream_tags=language,title:stream_disposition=attached_pic:chapter=start_time,end_time:chapter_tags=title",
+		"-of", "json",
+		path,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to probe file")
+	}
+
+	var res probeResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal probe result")
+	}
+
+	return &res, nil
+}
+
+func (eb *execBackend) Remux(ctx context.Context, src, dst string, format *media.Format) error {
+	ef, ok := execFormats[format]
+	if !ok || !eb.CanMux(format) {
+		return &repo.ErrUnsupportedFormat{
+			Format:    format.Name,
+			Operation: "muxing",
+		}
+	}
+
+	args := append(streamMaps(format, ef), "-c", "copy")
+	if ef.indexed && repo.Partial(ctx) != nil { // readable while being written
+		args = append(args, "-movflags", "frag_keyframe+empty_moov+default_base_moof")
+	}
+
+	args = append(args, "-f", ef.muxer)
+	return eb.convert(ctx, src, dst, nil, args)
+}
+
+func (eb *execBackend) Transcode(ctx context.Context, src, dst string, profile *media.TranscodeProfile) error {
+	ef, ok := execFormats[profile.Format]
+	if !ok || !eb.CanMux(profile.Format) {
+		return &repo.ErrUnsupportedFormat{
+			Format:    profile.Format.Name,
+			Operation: "muxing",
+		}
+	}
+
+	// copied streams can't be filtered or rate controlled
+	if profile.VideoCodec == "" && (profile.Width > 0 || profile.Height > 0 || profile.VideoBitRate > 0 || profile.Watermark != nil) {
+		return &repo.ErrUnsupportedFormat{
+			Format:    profile.Format.Name,
+			Operation: "video conversion without a video codec",
+		}
+	}
+	if profile.AudioCodec == "" && profile.AudioBitRate > 0 {
+		return &repo.ErrUnsupportedFormat{
+			Format:    profile.Format.Name,
+			Operation: "audio conversion without an audio codec",
+		}
+	}
+	if profile.Fragmented && !ef.indexed {
+		return &repo.ErrUnsupportedFormat{
+			Format:    profile.Format.Name,
+			Operation: "fragmenting",
+		}
+	}
+
+	var inArgs []string
+	if profile.Start > 0 { // input option, seeks before decoding
+		inArgs = []string{"-ss", strconv.FormatFloat(profile.Start.Seconds(), 'f', 3, 64)}
+	}
+
+	var (
+		maps    = streamMaps(profile.Format, ef)
+		filters []string
+		args    []string
+	)
+	switch profile.Streams {
+	case media.StreamsVideo:
+		maps = []string{"-map", "0:V?"}
+	case media.StreamsAudio:
+		maps = []string{"-map", "0:a:0"}
+	}
+	if profile.VideoCodec != "" && profile.Streams != media.StreamsAudio {
+		if profile.Width > 0 || profile.Height > 0 {
+			width, height := profile.Width, profile.Height
+			if width <= 0 {
+				width = -2 // keep the aspect ratio, rounded to an even number for encoders
+			}
+			if height <= 0 {
+				height = -2
+			}
+
+			filters = append(filters, fmt.Sprintf("scale=%d:%d", width, height))
+		}
+
+		wm := profile.Watermark
+		if profile.Format.Audio() { // no video to overlay
+			wm = nil
+		}
+		if wm != nil && wm.Image != "" {
+			// a second input needs a complex filtergraph, only the first video stream is watermarked
+			graph := "[0:V:0]"
+			if len(filters) > 0 {
+				graph += strings.Join(filters, ",") + ","
+			}
+			graph += "null[base];[1:v]" + imageFilter(wm) + "[wm];[base][wm]" + overlayFilter(wm)
+			if wm.Text != "" {
+				graph += "," + textFilter(wm)
+			}
+			graph += "[v]"
+
+			for i, arg := range maps {
+				if arg == "0:V?" {
+					maps[i] = "[v]"
+				}
+			}
+			args = append(args, "-i", wm.Image, "-filter_complex", graph)
+		} else {
+			if wm != nil && wm.Text != "" {
+				filters = append(filters, textFilter(wm))
+			}
+			if len(filters) > 0 {
+				args = append(args, "-vf", strings.Join(filters, ","))
+			}
+		}
+	}
+
+	args = append(append(args, maps...), "-c", "copy")
+	if profile.VideoCodec != "" {
+		args = append(args, "-c:v", profile.VideoCodec)
+		if profile.VideoBitRate > 0 {
+			args = append(args, "-b:v", strconv.FormatInt(profile.VideoBitRate, 10))
+		}
+	}
+	if profile.AudioCodec != "" {
+		args = append(args, "-c:a", profile.AudioCodec)
+		if profile.AudioBitRate > 0 {
+			args = append(args, "-b:a", strconv.FormatInt(profile.AudioBitRate, 10))
+		}
+	}
+	if profile.Fragmented {
+		if profile.VideoCodec != "" && profile.Streams != media.StreamsAudio { // fragments of all representations start at the same time
+			args = append(args, "-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%d)", int(fragmentInterval.Seconds())), "-sc_threshold", "0")
+		}
+		args = append(args, "-movflags", "dash+global_sidx")
+	}
+	args = append(args, "-f", ef.muxer)
+
+	return eb.convert(ctx, src, dst, inArgs, args)
+}
+
+// fragmentInterval is the keyframe interval of fragmented transcodes, the length of their fragments.
+const fragmentInterval = 4 * time.Second
+
+// previewEncoders are the H.264 encoders of preview clips in order of preference, playable by browsers in MP4.
+var previewEncoders = []string{"libx264", "libopenh264"}
+
+func (eb *execBackend) Preview(ctx context.Context, src, dst string, profile *PreviewProfile) error {
+	idx := slices.IndexFunc(previewEncoders, func(e string) bool { return slices.Contains(eb.features.Encoders, e) })
+	if idx < 0 || !eb.CanMux(media.FormatMP4) {
+		return &repo.ErrUnsupportedFormat{
+			Format:    media.FormatMP4.Name,
+			Operation: "preview encoding",
+		}
+	}
+
+	info, err := eb.Info(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	// each segment is a separate seeking input, the last one is the source input added by convert
+	var (
+		starts = profile.segments(info.Duration)
+		inArgs []string
+		graph  strings.Builder
+		length = strconv.FormatFloat(profile.Length.Seconds(), 'f', 3, 64)
+	)
+	for i, start := range starts {
+		inArgs = append(inArgs, "-ss", strconv.FormatFloat(start.Seconds(), 'f', 3, 64), "-t", length)
+		if i < len(starts)-1 {
+			inArgs = append(inArgs, "-i", src)
+		}
+
+		fmt.Fprintf(&graph, "[%d:V:0]scale=-2:%d,setsar=1,fps=24[s%d];", i, profile.Height, i)
+	}
+	for i := range starts {
+		fmt.Fprintf(&graph, "[s%d]", i)
+	}
+	fmt.Fprintf(&graph, "concat=n=%d:v=1:a=0[v]", len(starts))
+
+	args := []string{
+		"-filter_complex", graph.String(), "-map", "[v]", "-an", "-sn",
+		"-c:v", previewEncoders[idx], "-b:v", "400k", "-pix_fmt", "yuv420p",
+		"-movflags", "+faststart", "-f", execFormats[media.FormatMP4].muxer,
+	}
+	return eb.convert(ctx, src, dst, inArgs, args)
+}
+
+// watermarkMargin is the distance of a watermark from the video edges in pixels.
+const watermarkMargin = 16
+
+// watermarkCoords returns the ffmpeg expressions of a watermark's position, with the variable names of the frame and watermark sizes.
+func watermarkCoords(wm *media.Watermark, frameW, frameH, w, h string) (x, y string) {
+	var (
+		left   = strconv.Itoa(watermarkMargin)
+		right  = fmt.Sprintf("%s-%s-%d", frameW, w, watermarkMargin)
+		top    = left
+		bottom = fmt.Sprintf("%s-%s-%d", frameH, h, watermarkMargin)
+	)
+
+	switch wm.Position {
+	case media.WatermarkTopLeft:
+		return left, top
+	case media.WatermarkTopRight:
+		return right, top
+	case media.WatermarkBottomLeft:
+		return left, bottom
+	case media.WatermarkCenter:
+		return fmt.Sprintf("(%s-%s)/2", frameW, w), fmt.Sprintf("(%s-%s)/2", frameH, h)
+	default:
+		return right, bottom
+	}
+}
+
+// opacity returns the opacity of a watermark, clamped to (0, 1].
+func opacity(wm *media.Watermark) float64 {
+	if wm.Opacity <= 0 || wm.Opacity > 1 {
+		return 1
+	}
+
+	return wm.Opacity
+}
+
+// textFilter returns the ffmpeg filter drawing the text of a watermark, scaled to the frame height.
+func textFilter(wm *media.Watermark) string {
+	x, y := watermarkCoords(wm, "w", "h", "tw", "th")
+	return fmt.Sprintf(
+		"drawtext=expansion=none:text=%s:fontcolor=white@%g:shadowcolor=black@%g:shadowx=1:shadowy=1:fontsize=h/24:x=%s:y=%s",
+		escapeFilterValue(wm.Text), opacity(wm), opacity(wm), x, y,
+	)
+}
+
+// imageFilter returns the ffmpeg filter applying the opacity of a watermark to its image.
+func imageFilter(wm *media.Watermark) string {
+	return fmt.Sprintf("format=rgba,colorchannelmixer=aa=%g", opacity(wm))
+}
+
+// overlayFilter returns the ffmpeg filter placing the image of a watermark over the frame.
+func overlayFilter(wm *media.Watermark) string {
+	x, y := watermarkCoords(wm, "W", "H", "w", "h")
+	return fmt.Sprintf("overlay=x=%s:y=%s", x, y)
+}
+
+// filterValueEscaper escapes special characters of a filter option value,
+// filterGraphEscaper those of the filtergraph description containing it.
+var (
+	filterValueEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `:`, `\:`)
+	filterGraphEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `[`, `\[`, `]`, `\]`, `,`, `\,`, `;`, `\;`)
+)
+
+// escapeFilterValue escapes a literal filter option value for use in a filtergraph description.
+func escapeFilterValue(s string) string {
+	return filterGraphEscaper.Replace(filterValueEscaper.Replace(s))
+}
+
+// streamMaps returns the ffmpeg options selecting the input streams copied to a format, attached pictures (cover art) are skipped.
+// Only audio streams are selected for audio formats.
+func streamMaps(f *media.Format, ef execFormat) []string {
+	if f.Audio() {
+		return []string{"-map", "0:a?"}
+	}
+
+	maps := []string{"-map", "0:V?", "-map", "0:a?"}
+	if ef.subtitles {
+		maps = append(maps, "-map", "0:s?")
+	}
+
+	return maps
+}
+
+func (eb *execBackend) Subtitles(path string) ([]*media.Subtitle, error) {
+	res, err := eb.probeFile(context.Background(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	subtitles := make([]*media.Subtitle, 0)
+	for _, stream := range res.Streams {
+		if stream.CodecType != "subtitle" {
+			continue
+		}
+
+		lang := language.Und
+		if tag, err := language.Parse(stream.Tags.Language); err == nil { // ISO 639-2, such as "eng"
+			lang = tag
+		}
+
+		subtitles = append(subtitles, &media.Subtitle{
+			Codec:    stream.CodecName,
+			Language: lang,
+			Stream:   stream.Index,
+		})
+	}
+
+	return subtitles, nil
+}
+
+func (eb *execBackend) AudioTracks(path string) ([]*media.AudioTrack, error) {
+	res, err := eb.probeFile(context.Background(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]*media.AudioTrack, 0)
+	for _, stream := range res.Streams {
+		if stream.CodecType != "audio" {
+			continue
+		}
+
+		lang := language.Und
+		if tag, err := language.Parse(stream.Tags.Language); err == nil {
+			lang = tag
+		}
+
+		tracks = append(tracks, &media.AudioTrack{
+			Codec:    stream.CodecName,
+			Language: lang,
+			Title:    stream.Tags.Title,
+			Stream:   stream.Index,
+		})
+	}
+
+	return tracks, nil
+}
+
+func (eb *execBackend) Chapters(path string) ([]*media.Chapter, error) {
+	res, err := eb.probeFile(context.Background(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.chapters(), nil
+}
+
+// chapters returns the chapters of the probe result, ones with unparseable times are left out.
+func (res *probeResult) chapters() []*media.Chapter {
+	chapters := make([]*media.Chapter, 0, len(res.Chapters))
+	for _, c := range res.Chapters {
+		start, err := strconv.ParseFloat(c.StartTime, 64)
+		if err != nil {
+			continue
+		}
+		end, err := strconv.ParseFloat(c.EndTime, 64)
+		if err != nil {
+			continue
+		}
+
+		chapters = append(chapters, &media.Chapter{
+			Title: c.Tags.Title,
+			Start: time.Duration(start * float64(time.Second)),
+			End:   time.Duration(end * float64(time.Second)),
+		})
+	}
+
+	return chapters
+}
+
+func (eb *execBackend) ExtractSubtitle(ctx context.Context, src, dst string, stream int, format SubtitleFormat) error {
+	if !slices.Contains(eb.features.Muxers, format.Name) {
+		return &repo.ErrUnsupportedFormat{
+			Format:    format.Name,
+			Operation: "muxing",
+		}
+	}
+
+	return eb.convert(ctx, src, dst, nil, []string{"-map", fmt.Sprintf("0:%d", stream), "-c", "copy", "-f", format.Name})
+}
+
+func (eb *execBackend) Tags(path string) (map[string]string, error) {
+	res, err := eb.probeFile(context.Background(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string, len(res.Format.Tags))
+	for key, value := range res.Format.Tags {
+		tags[strings.ToLower(key)] = value
+	}
+
+	return tags, nil
+}
+
+func (eb *execBackend) Info(ctx context.Context, path string) (*ContainerInfo, error) {
+	res, err := eb.probeFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ContainerInfo{}
+	for _, stream := range res.Streams {
+		if stream.Disposition.AttachedPic != 1 {
+			info.Streams++
+		}
+	}
+	if secs, err := strconv.ParseFloat(res.Format.Duration, 64); err == nil {
+		info.Duration = time.Duration(secs * float64(time.Second))
+	}
+
+	return info, nil
+}
+
+func (eb *execBackend) Frames(ctx context.Context, path string, count, width, height int) ([]*image.Gray, error) {
+	info, err := eb.Info(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	frames := make([]*image.Gray, 0, count)
+	for i := 0; i < count; i++ {
+		at := info.Duration * time.Duration(2*i+1) / time.Duration(2*count) // centered in count equal parts
+		out, err := eb.output(
+			ctx, eb.ffmpeg,
+			"-hide_banner", "-nostdin", "-loglevel", "error",
+			"-ss", strconv.FormatFloat(at.Seconds(), 'f', 3, 64), "-i", path,
+			"-map", "0:V:0", "-frames:v", "1",
+			"-vf", fmt.Sprintf("scale=%d:%d,format=gray", width, height),
+			"-f", "rawvideo", "pipe:1",
+		)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+
+			continue
+		}
+		if len(out) != width*height { // past the end or no video
+			continue
+		}
+
+		frames = append(frames, &image.Gray{Pix: []byte(out), Stride: width, Rect: image.Rect(0, 0, width, height)})
+	}
+
+	return frames, nil
+}
+
+func (eb *execBackend) Thumbnails(ctx context.Context, path string, start, interval time.Duration, count, width, height int) ([]*image.RGBA, error) {
+	// decoding only keyframes is fast enough for a whole storyboard sheet, the fps filter picks the nearest ones
+	out, err := eb.output(
+		ctx, eb.ffmpeg,
+		"-hide_banner", "-nostdin", "-loglevel", "error",
+		"-skip_frame", "nokey", "-ss", strconv.FormatFloat(start.Seconds(), 'f', 3, 64), "-i", path,
+		"-map", "0:V:0", "-frames:v", strconv.Itoa(count),
+		"-vf", fmt.Sprintf(
+			"fps=1/%s,scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1,format=rgba",
+			strconv.FormatFloat(interval.Seconds(), 'f', 3, 64), width, height, width, height,
+		),
+		"-f", "rawvideo", "pipe:1",
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to extract thumbnails")
+	}
+
+	var (
+		size       = width * height * 4
+		thumbnails = make([]*image.RGBA, 0, len(out)/size)
+	)
+	for i := 0; i+size <= len(out); i += size {
+		thumbnails = append(thumbnails, &image.RGBA{Pix: []byte(out[i : i+size]), Stride: width * 4, Rect: image.Rect(0, 0, width, height)})
+	}
+
+	return thumbnails, nil
+}
+
+func (eb *execBackend) ExtractCover(ctx context.Context, src, dst string) (bool, error) {
+	res, err := eb.probeFile(ctx, src)
+	if err != nil {
+		return false, err
+	}
+
+	coverIdx := slices.IndexFunc(res.Streams, func(s probeStream) bool {
+		return s.CodecType == "video" && s.Disposition.AttachedPic == 1 && slices.Contains(coverCodecs, s.CodecName)
+	})
+	if coverIdx < 0 {
+		return false, nil
+	}
+
+	args := []string{"-map", fmt.Sprintf("0:%d", res.Streams[coverIdx].Index), "-c", "copy", "-frames:v", "1", "-f", "image2"}
+	if err := eb.convert(ctx, src, dst, nil, args); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// convert runs ffmpeg converting the source file to the destination file with input and output options,
+// progress is reported from the ffmpeg progress output.
+func (eb *execBackend) convert(ctx context.Context, src, dst string, inArgs, outArgs []string) error {
+	var duration time.Duration
+	if res, err := eb.probeFile(ctx, src); err == nil {
+		if secs, err := strconv.ParseFloat(res.Format.Duration, 64); err == nil {
+			duration = time.Duration(secs * float64(time.Second))
+		}
+	} else if logger := repo.Logger(ctx, eb.logger); logger != nil { // only progress is affected
+		logger.Warn("failed to probe media duration", zap.String("src", src), zap.Error(err))
+	}
+
+	args := []string{"-hide_banner", "-nostdin", "-nostats", "-loglevel", "error", "-progress", "pipe:1", "-y"}
+	args = append(args, inArgs...)
+	args = append(args, "-i", src)
+	args = append(args, outArgs...)
+	args = append(args, dst)
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, eb.ffmpeg, args...)
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return errors.Wrap(err, "failed to open ffmpeg output")
+	}
+	if err := cmd.Start(); err != nil {
+		return errors.Wrap(err, "failed to start ffmpeg")
+	}
+
+	progress := newProgressReporter(ctx, src, dst)
+	readProgress(stdout, func(outTime time.Duration) {
+		if duration > 0 && progress.progress.Total > 0 { // estimate the source position from the output time
+			ratio := outTime.Seconds() / duration.Seconds()
+			if ratio > 1 {
+				ratio = 1
+			}
+
+			progress.update(int(float64(progress.progress.Total) * ratio))
+		}
+	})
+
+	if err := cmd.Wait(); err != nil {
+		return commandError(ctx, cmd, err, &stderr)
+	}
+
+	progress.done()
+	return nil
+}
+
+// readProgress reads the key=value lines of the ffmpeg progress output until it ends, passing on the output timestamps.
+func readProgress(r io.Reader, fn func(outTime time.Duration)) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok || key != "out_time_us" {
+			continue
+		}
+
+		if us, err := strconv.ParseInt(value, 10, 64); err == nil && us >= 0 {
+			fn(time.Duration(us) * time.Microsecond)
+		}
+	}
+
+	_, _ = io.Copy(io.Discard, r) // don't block ffmpeg after a read error
+}
+
+// commandError makes an error of a failed command, carrying its error output.
+func commandError(ctx context.Context, cmd *exec.Cmd, err error, stderr *bytes.Buffer) error {
+	if ctxErr := ctx.Err(); ctxErr != nil { // killed
+		return ctxErr
+	}
+
+	msg := strings.TrimSpace(stderr.String())
+	if i := strings.LastIndexByte(msg, '\n'); i != -1 { // the last line is usually the cause
+		msg = msg[i+1:]
+	}
+	if msg == "" {
+		return errors.Wrapf(err, "%s failed", cmd.Path)
+	}
+
+	return errors.Wrapf(err, "%s failed (%s)", cmd.Path, msg)
+}
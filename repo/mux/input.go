@@ -0,0 +1,111 @@
+package mux
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// spillDir is the name of the cache subdirectory of local copies of remote media files, made for the duration of a conversion.
+const spillDir = "spill"
+
+// readable checks whether the backend can read a media file as-is, i.e. it's local or its URL protocol is supported.
+func (mr *muxRepo) readable(m media.Media) bool {
+	scheme := media.URLScheme(m.Path())
+	return scheme == "" || slices.Contains(mr.backend.Features().Protocols, scheme)
+}
+
+// input returns the path of a media file for the backend to read, the release function must be called when it's done.
+// Remote media the backend can't read (see media.Opener) are copied into a local spill file, removed when released.
+func (mr *muxRepo) input(ctx context.Context, m media.Media) (string, func(), error) {
+	if mr.readable(m) {
+		return m.Path(), func() {}, nil
+	}
+
+	opener, ok := m.(media.Opener)
+	if !ok {
+		return "", nil, &repo.ErrUnsupportedFormat{
+			Format:    media.URLScheme(m.Path()),
+			Operation: "reading remote media",
+		}
+	}
+
+	path, err := mr.spill(ctx, opener, m.Format())
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to copy remote media")
+	}
+
+	return path, func() {
+		if err := os.Remove(path); err != nil && mr.logger != nil {
+			mr.logger.Warn("failed to remove spill file", zap.String("path", path), zap.Error(err))
+		}
+	}, nil
+}
+
+// spill copies a remote media file into a new spill file, returns its path.
+func (mr *muxRepo) spill(ctx context.Context, opener media.Opener, format *media.Format) (_ string, err error) {
+	dir := filepath.Join(mr.path, spillDir)
+	if err := os.MkdirAll(dir, 0); err != nil {
+		return "", errors.Wrap(err, "failed to make spill directory")
+	}
+
+	pattern := "*"
+	if format != nil && format.Extension != "" { // some demuxers are picked by it
+		pattern += "." + format.Extension
+	}
+
+	f, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create spill file")
+	}
+	defer func() {
+		if err0 := f.Close(); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to close spill file"))
+		}
+		if err != nil {
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	r, err := opener.Open()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open remote file")
+	}
+	defer r.Close()
+
+	if _, err := io.Copy(f, &ctxReader{ctx: ctx, r: r}); err != nil {
+		return "", errors.Wrap(err, "failed to copy remote file")
+	}
+
+	return f.Name(), nil
+}
+
+// ctxReader is an io.Reader that stops reading when its context is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *ctxReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return cr.r.Read(p)
+}
+
+// removeSpills removes spill files left behind by an unclean shutdown.
+func removeSpills(path string) error {
+	if err := os.RemoveAll(filepath.Join(path, spillDir)); err != nil {
+		return errors.Wrap(err, "failed to remove spill files")
+	}
+
+	return nil
+}
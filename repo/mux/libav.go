@@ -0,0 +1,460 @@
+//go:build cgo
+
+package mux
+
+import (
+	"context"
+	"github.com/katana-project/ffmpeg"
+	"github.com/katana-project/ffmpeg/avcodec"
+	"github.com/katana-project/ffmpeg/avutil"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/mux"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+	"golang.org/x/text/language"
+	"image"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// libavAvailable is whether the libav backend is available in the build.
+const libavAvailable = true
+
+// formats are the media.Formats mapped to their mux variants.
+var formats = make(map[*media.Format]*format)
+
+func init() {
+	avutil.SetLogLevel(avutil.LogWarning)
+
+	for _, f := range media.Formats() {
+		var (
+			muxer   = mux.FindMuxer(f.Name, f.Extension, f.MIME)
+			demuxer = mux.FindDemuxer(f.Name, f.Extension, f.MIME)
+		)
+		if muxer == nil && demuxer == nil {
+			continue // don't include missing formats
+		}
+
+		formats[f] = &format{
+			Format:  f,
+			muxer:   muxer,
+			demuxer: demuxer,
+		}
+	}
+}
+
+// format is a muxer + demuxer combination.
+type format struct {
+	*media.Format
+
+	muxer   *mux.Muxer
+	demuxer *mux.Demuxer
+}
+
+// libavProtocols are the input protocols assumed to be available, the bindings can't enumerate them (avio_enum_protocols).
+// Network protocols are part of default FFmpeg builds, HTTPS needs it to be built with a TLS library.
+var libavProtocols = []string{"http", "https"}
+
+// hwAccels are the codec name suffixes of hardware acceleration methods, such as "h264_nvenc".
+var hwAccels = []string{"nvenc", "cuvid", "vaapi", "qsv", "videotoolbox", "amf", "v4l2m2m", "mediacodec", "mf", "rkmpp", "omx"}
+
+var (
+	probeOnce sync.Once
+	features  *repo.Features
+
+	// hasVideoEncoder is whether there's an encoder for video streams, needed by transcoding.
+	hasVideoEncoder bool
+)
+
+// libavBackend is a Backend using the FFmpeg libraries through the katana-project/mux CGO bindings.
+type libavBackend struct {
+	logger *zap.Logger
+}
+
+func newLibavBackend(logger *zap.Logger) Backend {
+	return &libavBackend{logger: logger}
+}
+
+func (lb *libavBackend) Name() string {
+	return BackendLibav
+}
+
+func (lb *libavBackend) Features() *repo.Features {
+	probeOnce.Do(func() {
+		// the mux bindings expose no scaler, filters, seeking, rate control, muxer options or audio encoder parameters,
+		// only video can be re-encoded as-is (see Transcode), remuxed files can't be fragmented, thumbnails can't be taken and previews can't be cut
+		features = &repo.Features{Protocols: libavProtocols}
+		for _, m := range mux.AvailableMuxers() {
+			features.Muxers = append(features.Muxers, m.Name())
+		}
+		for _, d := range mux.AvailableDemuxers() {
+			features.Demuxers = append(features.Demuxers, d.Name())
+		}
+
+		iterState := &ffmpeg.IterationState{}
+		for {
+			c := avcodec.CodecIterate(iterState)
+			if c == nil {
+				break
+			}
+
+			name := c.Name()
+			if c.IsEncoder() != 0 {
+				features.Encoders = append(features.Encoders, name)
+				if c.Type() == avutil.MediaTypeVideo {
+					hasVideoEncoder = true
+				}
+			}
+			if c.IsDecoder() != 0 {
+				features.Decoders = append(features.Decoders, name)
+			}
+
+			if i := strings.LastIndexByte(name, '_'); i != -1 {
+				if hw := name[i+1:]; slices.Contains(hwAccels, hw) && !slices.Contains(features.HWAccels, hw) {
+					features.HWAccels = append(features.HWAccels, hw)
+				}
+			}
+		}
+
+		slices.Sort(features.HWAccels)
+	})
+
+	return features
+}
+
+func (lb *libavBackend) Unsupported(c repo.Capability) string {
+	lb.Features() // probe the encoders
+	return unsupported(lb, c, hasVideoEncoder)
+}
+
+func (lb *libavBackend) CanMux(format *media.Format) bool {
+	muxDem, ok := formats[format]
+	return ok && muxDem.muxer != nil
+}
+
+func (lb *libavBackend) CanDemux(format *media.Format) bool {
+	muxDem, ok := formats[format]
+	return ok && muxDem.demuxer != nil
+}
+
+func (lb *libavBackend) Remux(ctx context.Context, src, dst string, format *media.Format) (err error) {
+	muxDem, ok := formats[format]
+	if !ok || muxDem.muxer == nil {
+		return &repo.ErrUnsupportedFormat{
+			Format:    format.Name,
+			Operation: "muxing",
+		}
+	}
+	muxer := muxDem.muxer
+
+	inCtx, err := mux.NewInputContext(src)
+	if err != nil {
+		return errors.Wrap(err, "failed to open input context")
+	}
+	defer inCtx.Close()
+
+	outCtx, err := mux.NewOutputContext(muxer, dst)
+	if err != nil {
+		return errors.Wrap(err, "failed to open output context")
+	}
+	defer outCtx.Close()
+
+	var (
+		streams = inCtx.Streams()
+
+		streamMapping   = make([]int, len(streams))
+		lastStreamIndex = 0
+	)
+	for i, inStream := range streams {
+		var (
+			codec   = inStream.Codec()
+			remapId = -1
+		)
+		if muxer.SupportsCodec(codec) {
+			outStream := outCtx.NewStream(codec)
+			if err := inStream.CopyParameters(outStream); err != nil {
+				return errors.Wrapf(err, "failed to copy stream %d parameters", i)
+			}
+
+			remapId = lastStreamIndex
+			lastStreamIndex++
+		} else if logger := repo.Logger(ctx, lb.logger); logger != nil { // codec not supported in container, strip
+			logger.Warn(
+				"skipping unsupported codec in stream",
+				zap.String("codec", codec.Name()),
+				zap.String("format", muxer.Name()),
+				zap.Int("stream", i),
+				zap.String("src", src),
+				zap.String("dst", dst),
+			)
+		}
+
+		streamMapping[i] = remapId
+	}
+
+	pkt := mux.NewPacket()
+	defer pkt.Close()
+
+	if err := outCtx.WriteHeader(); err != nil {
+		return errors.Wrap(err, "failed to write header")
+	}
+
+	progress := newProgressReporter(ctx, src, dst)
+	for {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+
+		err = inCtx.ReadFrame(pkt)
+		if err != nil {
+			if err != io.EOF {
+				err = errors.Wrap(err, "failed to read frame")
+			}
+
+			break
+		}
+		progress.update(pkt.Pos())
+
+		streamIdx := pkt.StreamIndex()
+		if remapId := streamMapping[streamIdx]; remapId >= 0 {
+			pkt.SetStreamIndex(remapId)
+
+			pkt.Rescale(
+				inCtx.Stream(streamIdx).TimeBase(),
+				outCtx.Stream(remapId).TimeBase(),
+			)
+			pkt.ResetPos()
+
+			if err := outCtx.WriteFrame(pkt); err != nil {
+				err = errors.Wrap(err, "failed to write frame")
+				break
+			}
+			// WriteFrame takes ownership of the packet and resets it, no need to clear here
+		} else {
+			if err := pkt.Clear(); err != nil {
+				err = errors.Wrap(err, "failed to clear packet")
+				break
+			}
+		}
+	}
+	if err == nil || err == io.EOF {
+		if err := outCtx.WriteEnd(); err != nil {
+			return errors.Wrap(err, "failed to write end")
+		}
+
+		progress.done()
+		return nil
+	}
+
+	return err
+}
+
+func (lb *libavBackend) Subtitles(path string) ([]*media.Subtitle, error) {
+	inCtx, err := mux.NewInputContext(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open input context")
+	}
+	defer inCtx.Close()
+
+	subtitles := make([]*media.Subtitle, 0)
+	for _, stream := range inCtx.Streams() {
+		if stream.Type() != mux.MediaTypeSubtitle {
+			continue
+		}
+
+		subtitles = append(subtitles, &media.Subtitle{
+			Codec:    stream.Codec().Name(),
+			Language: language.Und, // the mux bindings don't expose stream metadata (language tags)
+			Stream:   stream.Index(),
+		})
+	}
+
+	return subtitles, nil
+}
+
+func (lb *libavBackend) AudioTracks(path string) ([]*media.AudioTrack, error) {
+	inCtx, err := mux.NewInputContext(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open input context")
+	}
+	defer inCtx.Close()
+
+	tracks := make([]*media.AudioTrack, 0)
+	for _, stream := range inCtx.Streams() {
+		if stream.Type() != mux.MediaTypeAudio {
+			continue
+		}
+
+		tracks = append(tracks, &media.AudioTrack{
+			Codec:    stream.Codec().Name(),
+			Language: language.Und, // the mux bindings don't expose stream metadata (language and title tags)
+			Stream:   stream.Index(),
+		})
+	}
+
+	return tracks, nil
+}
+
+func (lb *libavBackend) Chapters(_ string) ([]*media.Chapter, error) {
+	// the mux bindings don't expose chapters
+	return nil, &repo.ErrUnsupportedFormat{
+		Format:    "container",
+		Operation: "chapter reading",
+	}
+}
+
+func (lb *libavBackend) ExtractSubtitle(ctx context.Context, src, dst string, streamIdx int, format SubtitleFormat) error {
+	muxer := mux.FindMuxer(format.Name, format.Extension, format.MIME)
+	if muxer == nil {
+		return &repo.ErrUnsupportedFormat{
+			Format:    format.Name,
+			Operation: "muxing",
+		}
+	}
+
+	return copyStream(ctx, src, dst, streamIdx, muxer)
+}
+
+func (lb *libavBackend) Tags(path string) (map[string]string, error) {
+	inCtx, err := mux.NewInputContext(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open input context")
+	}
+	defer inCtx.Close()
+
+	tags := make(map[string]string)
+	for key, value := range inCtx.Metadata() {
+		tags[strings.ToLower(key)] = value
+	}
+
+	return tags, nil
+}
+
+func (lb *libavBackend) Frames(_ context.Context, _ string, _, _, _ int) ([]*image.Gray, error) {
+	// the mux bindings don't expose a scaler or seeking
+	return nil, &repo.ErrUnsupportedFormat{
+		Format:    "video",
+		Operation: "frame extraction",
+	}
+}
+
+func (lb *libavBackend) Thumbnails(_ context.Context, _ string, _, _ time.Duration, _, _, _ int) ([]*image.RGBA, error) {
+	// the mux bindings don't expose a scaler or seeking
+	return nil, &repo.ErrUnsupportedFormat{
+		Format:    "video",
+		Operation: "thumbnail extraction",
+	}
+}
+
+func (lb *libavBackend) Info(_ context.Context, path string) (*ContainerInfo, error) {
+	inCtx, err := mux.NewInputContext(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open input context")
+	}
+	defer inCtx.Close()
+
+	// the mux bindings expose neither the duration nor stream dispositions
+	return &ContainerInfo{Streams: len(inCtx.Streams())}, nil
+}
+
+func (lb *libavBackend) ExtractCover(ctx context.Context, src, dst string) (bool, error) {
+	muxer := mux.FindMuxer("image2", "", "")
+	if muxer == nil {
+		return false, &repo.ErrUnsupportedFormat{
+			Format:    "image2",
+			Operation: "muxing",
+		}
+	}
+
+	inCtx, err := mux.NewInputContext(src)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to open input context")
+	}
+
+	// the mux bindings don't expose stream dispositions, image streams are presumed to be attached pictures
+	coverIdx := -1
+	for _, stream := range inCtx.Streams() {
+		if stream.Type() == mux.MediaTypeVideo && slices.Contains(coverCodecs, stream.Codec().Name()) {
+			coverIdx = stream.Index()
+			break
+		}
+	}
+	_ = inCtx.Close()
+	if coverIdx < 0 {
+		return false, nil
+	}
+
+	if err := copyStream(ctx, src, dst, coverIdx, muxer); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// copyStream copies a stream of a media file into a file of its own, written by muxer.
+func copyStream(ctx context.Context, src, dst string, streamIdx int, muxer *mux.Muxer) error {
+	inCtx, err := mux.NewInputContext(src)
+	if err != nil {
+		return errors.Wrap(err, "failed to open input context")
+	}
+	defer inCtx.Close()
+
+	outCtx, err := mux.NewOutputContext(muxer, dst)
+	if err != nil {
+		return errors.Wrap(err, "failed to open output context")
+	}
+	defer outCtx.Close()
+
+	inStream := inCtx.Stream(streamIdx)
+	if err := inStream.CopyParameters(outCtx.NewStream(inStream.Codec())); err != nil {
+		return errors.Wrapf(err, "failed to copy stream %d parameters", streamIdx)
+	}
+
+	if err := outCtx.WriteHeader(); err != nil {
+		return errors.Wrap(err, "failed to write header")
+	}
+
+	pkt := mux.NewPacket()
+	defer pkt.Close()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := inCtx.ReadFrame(pkt); err != nil {
+			if err != io.EOF {
+				return errors.Wrap(err, "failed to read frame")
+			}
+
+			break
+		}
+
+		if pkt.StreamIndex() != streamIdx {
+			if err := pkt.Clear(); err != nil {
+				return errors.Wrap(err, "failed to clear packet")
+			}
+
+			continue
+		}
+
+		pkt.SetStreamIndex(0)
+		pkt.Rescale(inStream.TimeBase(), outCtx.Stream(0).TimeBase())
+		pkt.ResetPos()
+
+		if err := outCtx.WriteFrame(pkt); err != nil {
+			return errors.Wrap(err, "failed to write frame")
+		}
+		// WriteFrame takes ownership of the packet and resets it, no need to clear here
+	}
+
+	if err := outCtx.WriteEnd(); err != nil {
+		return errors.Wrap(err, "failed to write end")
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !cgo
+
+package mux
+
+import "go.uber.org/zap"
+
+// libavAvailable is whether the libav backend is available in the build.
+const libavAvailable = false
+
+// newLibavBackend is never called without CGO, see libavAvailable.
+func newLibavBackend(_ *zap.Logger) Backend {
+	return nil
+}
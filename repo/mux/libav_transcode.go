@@ -0,0 +1,372 @@
+//go:build cgo
+
+package mux
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/mux"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"io"
+)
+
+// transcodeStream is the transcoding state of an input stream.
+type transcodeStream struct {
+	// index is the output stream index.
+	index int
+	// inTimeBase and outTimeBase are the input and output stream time bases.
+	inTimeBase, outTimeBase *mux.Rational
+	// decoder and encoder are the stream de/encoders, nil if the stream is copied.
+	decoder, encoder *mux.CodecIO
+}
+
+func (ts *transcodeStream) Close() error {
+	if ts.decoder == nil {
+		return nil
+	}
+
+	return multierr.Combine(ts.decoder.Close(), ts.encoder.Close())
+}
+
+func (lb *libavBackend) Preview(_ context.Context, _, _ string, _ *PreviewProfile) error {
+	// the mux bindings don't expose a scaler or seeking, segments can't be cut and scaled down
+	return &repo.ErrUnsupportedFormat{
+		Format:    media.FormatMP4.Name,
+		Operation: "preview encoding",
+	}
+}
+
+func (lb *libavBackend) Transcode(ctx context.Context, src, dst string, profile *media.TranscodeProfile) (err error) {
+	muxDem, ok := formats[profile.Format]
+	if !ok || muxDem.muxer == nil {
+		return &repo.ErrUnsupportedFormat{
+			Format:    profile.Format.Name,
+			Operation: "muxing",
+		}
+	}
+	muxer := muxDem.muxer
+
+	// the mux bindings don't expose muxer options or stream selection
+	if profile.Fragmented || profile.Streams != "" {
+		return &repo.ErrUnsupportedFormat{
+			Format:    profile.Format.Name,
+			Operation: "fragmenting",
+		}
+	}
+
+	// the mux bindings don't expose encoder rate control options or a scaler
+	if profile.VideoBitRate > 0 || profile.AudioBitRate > 0 {
+		return &repo.ErrUnsupportedFormat{
+			Format:    profile.Format.Name,
+			Operation: "bit rate control",
+		}
+	}
+	if profile.Width > 0 || profile.Height > 0 {
+		return &repo.ErrUnsupportedFormat{
+			Format:    profile.Format.Name,
+			Operation: "resolution scaling",
+		}
+	}
+
+	// the mux bindings don't expose filters, so nothing can be drawn over frames
+	if profile.Watermark != nil {
+		return &repo.ErrUnsupportedFormat{
+			Format:    profile.Format.Name,
+			Operation: "watermarking",
+		}
+	}
+
+	// the mux input context can't seek and packet timestamps aren't exposed, so packets before the start can't be skipped either
+	if profile.Start > 0 {
+		return &repo.ErrUnsupportedFormat{
+			Format:    profile.Format.Name,
+			Operation: "seeking",
+		}
+	}
+
+	var videoCodec, audioCodec *mux.Codec
+	if profile.VideoCodec != "" {
+		if videoCodec = mux.FindCodec(profile.VideoCodec); videoCodec == nil {
+			return &repo.ErrUnsupportedFormat{
+				Format:    profile.VideoCodec,
+				Operation: "encoding",
+			}
+		}
+	}
+	if profile.AudioCodec != "" {
+		if audioCodec = mux.FindCodec(profile.AudioCodec); audioCodec == nil {
+			return &repo.ErrUnsupportedFormat{
+				Format:    profile.AudioCodec,
+				Operation: "encoding",
+			}
+		}
+	}
+
+	inCtx, err := mux.NewInputContext(src)
+	if err != nil {
+		return errors.Wrap(err, "failed to open input context")
+	}
+	defer inCtx.Close()
+
+	outCtx, err := mux.NewOutputContext(muxer, dst)
+	if err != nil {
+		return errors.Wrap(err, "failed to open output context")
+	}
+	defer outCtx.Close()
+
+	var (
+		streams = inCtx.Streams()
+
+		transcodeStreams = make([]*transcodeStream, len(streams))
+		lastStreamIndex  = 0
+	)
+	defer func() {
+		for _, ts := range transcodeStreams {
+			if ts != nil {
+				err = multierr.Append(err, ts.Close())
+			}
+		}
+	}()
+	for i, inStream := range streams {
+		var (
+			codec  = inStream.Codec()
+			target *mux.Codec
+		)
+		switch inStream.Type() {
+		case mux.MediaTypeVideo:
+			target = videoCodec
+		case mux.MediaTypeAudio:
+			target = audioCodec
+		}
+
+		if target == nil || target.Name() == codec.Name() { // copy the stream as-is, like in remux
+			if !muxer.SupportsCodec(codec) { // codec not supported in container, strip
+				if logger := repo.Logger(ctx, lb.logger); logger != nil {
+					logger.Warn(
+						"skipping unsupported codec in stream",
+						zap.String("codec", codec.Name()),
+						zap.String("format", muxer.Name()),
+						zap.Int("stream", i),
+						zap.String("src", src),
+						zap.String("dst", dst),
+					)
+				}
+
+				continue
+			}
+
+			outStream := outCtx.NewStream(codec)
+			if err := inStream.CopyParameters(outStream); err != nil {
+				return errors.Wrapf(err, "failed to copy stream %d parameters", i)
+			}
+
+			transcodeStreams[i] = &transcodeStream{index: lastStreamIndex}
+			lastStreamIndex++
+			continue
+		}
+
+		ts, err := lb.openTranscodeStream(inStream, target, muxer)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open stream %d for transcoding", i)
+		}
+
+		transcodeStreams[i] = ts
+		if err := ts.encoder.CopyCodecParameters(outCtx.NewStream(target)); err != nil {
+			return errors.Wrapf(err, "failed to copy stream %d encoder parameters", i)
+		}
+
+		ts.index = lastStreamIndex
+		lastStreamIndex++
+	}
+
+	if err := outCtx.WriteHeader(); err != nil {
+		return errors.Wrap(err, "failed to write header")
+	}
+
+	for i, ts := range transcodeStreams { // output time bases are only final after writing the header
+		if ts != nil {
+			ts.inTimeBase = inCtx.Stream(i).TimeBase()
+			ts.outTimeBase = outCtx.Stream(ts.index).TimeBase()
+		}
+	}
+
+	var (
+		pkt    = mux.NewPacket()
+		encPkt = mux.NewPacket()
+		frm    = mux.NewFrame()
+	)
+	defer pkt.Close()
+	defer encPkt.Close()
+	defer frm.Close()
+
+	progress := newProgressReporter(ctx, src, dst)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := inCtx.ReadFrame(pkt); err != nil {
+			if err != io.EOF {
+				return errors.Wrap(err, "failed to read frame")
+			}
+
+			break
+		}
+		progress.update(pkt.Pos())
+
+		ts := transcodeStreams[pkt.StreamIndex()]
+		switch {
+		case ts == nil: // stripped stream
+			if err := pkt.Clear(); err != nil {
+				return errors.Wrap(err, "failed to clear packet")
+			}
+		case ts.decoder == nil: // copied stream
+			pkt.SetStreamIndex(ts.index)
+			pkt.Rescale(ts.inTimeBase, ts.outTimeBase)
+			pkt.ResetPos()
+
+			if err := outCtx.WriteFrame(pkt); err != nil {
+				return errors.Wrap(err, "failed to write frame")
+			}
+			// WriteFrame takes ownership of the packet and resets it, no need to clear here
+		default:
+			err := ts.decoder.WritePacket(pkt)
+			if err0 := pkt.Clear(); err0 != nil {
+				return errors.Wrap(err0, "failed to clear packet")
+			}
+			if err != nil && err != mux.ErrAgain {
+				return errors.Wrap(err, "failed to decode packet")
+			}
+
+			if err := ts.transcode(outCtx, frm, encPkt); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, ts := range transcodeStreams { // flush the buffered frames
+		if ts == nil || ts.decoder == nil {
+			continue
+		}
+
+		if err := ts.decoder.Flush(); err != nil {
+			return errors.Wrap(err, "failed to flush decoder")
+		}
+		if err := ts.transcode(outCtx, frm, encPkt); err != nil {
+			return err
+		}
+		if err := ts.encoder.Flush(); err != nil {
+			return errors.Wrap(err, "failed to flush encoder")
+		}
+		if err := ts.writeEncoded(outCtx, encPkt); err != nil {
+			return err
+		}
+	}
+
+	if err := outCtx.WriteEnd(); err != nil {
+		return errors.Wrap(err, "failed to write end")
+	}
+
+	progress.done()
+	return nil
+}
+
+func (lb *libavBackend) openTranscodeStream(inStream *mux.Stream, target *mux.Codec, muxer *mux.Muxer) (_ *transcodeStream, err error) {
+	if inStream.Type() != mux.MediaTypeVideo {
+		// the mux bindings only carry video parameters (dimensions, pixel format) over to encoders
+		return nil, &repo.ErrUnsupportedFormat{
+			Format:    target.Name(),
+			Operation: "audio encoding",
+		}
+	}
+	if !muxer.SupportsCodec(target) {
+		return nil, &repo.ErrUnsupportedFormat{
+			Format:    target.Name(),
+			Operation: "muxing into " + muxer.Name(),
+		}
+	}
+
+	decoder, err := inStream.Decoder()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open decoder")
+	}
+
+	encoder := target.NewEncoder()
+	if encoder == nil {
+		return nil, multierr.Append(
+			&repo.ErrUnsupportedFormat{
+				Format:    target.Name(),
+				Operation: "encoding",
+			},
+			decoder.Close(),
+		)
+	}
+
+	ts := &transcodeStream{decoder: decoder, encoder: encoder}
+	defer func() {
+		if err != nil {
+			err = multierr.Append(err, ts.Close())
+		}
+	}()
+
+	if err := decoder.CopyParameters(encoder); err != nil {
+		return nil, errors.Wrap(err, "failed to copy decoder parameters")
+	}
+	if err := encoder.Open(); err != nil {
+		return nil, errors.Wrap(err, "failed to open encoder")
+	}
+
+	return ts, nil
+}
+
+// transcode moves all decoded frames to the encoder and writes the encoded packets.
+func (ts *transcodeStream) transcode(outCtx *mux.IOContext, frm *mux.Frame, encPkt *mux.Packet) error {
+	for {
+		if err := ts.decoder.ReadFrame(frm); err != nil {
+			if err == mux.ErrAgain || err == io.EOF {
+				break
+			}
+
+			return errors.Wrap(err, "failed to decode frame")
+		}
+
+		err := ts.encoder.WriteFrame(frm)
+		if err0 := frm.Clear(); err0 != nil {
+			return errors.Wrap(err0, "failed to clear frame")
+		}
+		if err != nil && err != mux.ErrAgain {
+			return errors.Wrap(err, "failed to encode frame")
+		}
+
+		if err := ts.writeEncoded(outCtx, encPkt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeEncoded writes all available encoded packets.
+func (ts *transcodeStream) writeEncoded(outCtx *mux.IOContext, encPkt *mux.Packet) error {
+	for {
+		if err := ts.encoder.ReadPacket(encPkt); err != nil {
+			if err == mux.ErrAgain || err == io.EOF {
+				return nil
+			}
+
+			return errors.Wrap(err, "failed to receive encoded packet")
+		}
+
+		// decoded frames keep the input stream timestamps, the encoder carries them over to the packets
+		encPkt.SetStreamIndex(ts.index)
+		encPkt.Rescale(ts.inTimeBase, ts.outTimeBase)
+		encPkt.ResetPos()
+
+		if err := outCtx.WriteFrame(encPkt); err != nil {
+			return errors.Wrap(err, "failed to write frame")
+		}
+	}
+}
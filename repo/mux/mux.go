@@ -1,18 +1,17 @@
 package mux
 
 import (
-	"crypto/md5"
-	"encoding/binary"
-	"encoding/hex"
-	"github.com/katana-project/ffmpeg/avutil"
+	"context"
+	"fmt"
 	"github.com/katana-project/katana/internal/errors"
 	"github.com/katana-project/katana/internal/sync"
+	"github.com/katana-project/katana/internal/trace"
 	"github.com/katana-project/katana/repo"
 	"github.com/katana-project/katana/repo/media"
-	"github.com/katana-project/mux"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
-	"io"
+	"golang.org/x/exp/maps"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -22,66 +21,58 @@ import (
 // capMask is the mask for the repository capability input.
 const capMask = repo.CapabilityRemux | repo.CapabilityTranscode
 
-// formats are the media.Formats mapped to their mux variants.
-var formats = make(map[*media.Format]*format)
-
-func init() {
-	avutil.SetLogLevel(avutil.LogWarning)
-
-	for _, f := range media.Formats() {
-		var (
-			muxer   = mux.FindMuxer(f.Name, f.Extension, f.MIME)
-			demuxer = mux.FindDemuxer(f.Name, f.Extension, f.MIME)
-		)
-		if muxer == nil && demuxer == nil {
-			continue // don't include missing formats
-		}
-
-		formats[f] = &format{
-			Format:  f,
-			muxer:   muxer,
-			demuxer: demuxer,
-		}
-	}
-}
-
-// format is a muxer + demuxer combination.
-type format struct {
-	*media.Format
-
-	muxer   *mux.Muxer
-	demuxer *mux.Demuxer
-}
-
-// muxRepo is a repo.MuxingRepository implementation that uses the mux library.
+// muxRepo is a repo.MuxingRepository implementation that converts media with a Backend.
 type muxRepo struct {
 	repo.MutableRepository
 
 	path, remuxPath, transcodePath string
 
-	cap    repo.Capability
-	logger *zap.Logger
-
-	mu sync.KMutex
+	cap         repo.Capability
+	unavailable map[repo.Capability]string // requested capabilities left out when negotiating, mapped to the reasons
+	cache       *CachePolicy
+	backend     Backend
+	logger      *zap.Logger
+
+	probes      *probeStore
+	probeQueue  chan string  // IDs of added media to probe, nil if they're only probed on demand
+	probing     sync.Tracker // running probe workers
+	unsubscribe func()       // unregisters the event observer queueing added media, nil if there's none
+
+	mu    sync.KMutex
+	valid validations  // remuxed cache files validated since the start
+	ops   sync.Tracker // running conversions
+	ctx   context.Context
+	stop  context.CancelFunc
 }
 
-// relocatedMedia is a media.Media delegate that changes the destination path and MIME type.
+// relocatedMedia is a media.Media delegate that changes the destination path and format.
 type relocatedMedia struct {
 	media.Media
 
-	path, mime string
+	path   string
+	format *media.Format
 }
 
 func (rm *relocatedMedia) Path() string {
 	return rm.path
 }
 
-func (rm *relocatedMedia) MIME() string {
-	return rm.mime
+func (rm *relocatedMedia) Format() *media.Format {
+	return rm.format
 }
 
-// NewRepository creates a new mux-backed repo.MutableRepository.
-func NewRepository(r repo.MutableRepository, cap repo.Capability, path string, logger *zap.Logger) (repo.MutableRepository, error) {
+// NewRepository creates a new mux-backed repo.MutableRepository, converting media with the backend.
+// The cache policy can be nil, cache files are then laid out flat and kept for as long as their source media exists.
+// Media added to the repository are probed ahead of requests by probeWorkers workers (see repo.Repository.Probe),
+// none are probed until requested if it isn't positive; the results are persisted in the cache directory.
+// The requested capabilities are negotiated (see repo.Negotiate), the ones the backend doesn't support or that a read-only cache directory
+// couldn't hold conversions for are left out and reported by repo.Repository.Unavailable.
+func NewRepository(r repo.MutableRepository, cap repo.Capability, path string, cache *CachePolicy, probeWorkers int, backend Backend, logger *zap.Logger) (repo.MutableRepository, error) {
+	cache, err := cache.normalize()
+	if err != nil {
+		return nil, err
+	}
+
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to make path absolute")
@@ -90,6 +81,23 @@ func NewRepository(r repo.MutableRepository, cap repo.Capability, path string, l
 	if err := os.MkdirAll(absPath, 0); err != nil {
 		return nil, errors.Wrap(err, "failed to make directories")
 	}
+	if err := removeSpills(absPath); err != nil {
+		return nil, err
+	}
+
+	writeErr := checkWritable(absPath) // remote media are copied into it too, before they're converted
+	cap, unavailable := repo.Negotiate(cap&capMask, func(c repo.Capability) string {
+		if writeErr != nil {
+			return fmt.Sprintf("cache directory isn't writable: %s", writeErr.Error())
+		}
+
+		return backend.Unsupported(c)
+	})
+	for c, reason := range unavailable {
+		if logger != nil {
+			logger.Warn("disabled repository capability", zap.String("repo", r.ID()), zap.Stringer("capability", c), zap.String("reason", reason))
+		}
+	}
 
 	var (
 		remuxPath     string
@@ -112,22 +120,64 @@ func NewRepository(r repo.MutableRepository, cap repo.Capability, path string, l
 		}
 	}
 
-	return &muxRepo{
+	probes, err := newProbeStore(filepath.Join(absPath, probesFile))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	mr := &muxRepo{
 		MutableRepository: r,
 		path:              absPath,
 		remuxPath:         remuxPath,
 		transcodePath:     transcodePath,
-		cap:               cap & capMask,
+		cap:               cap,
+		unavailable:       unavailable,
+		cache:             cache,
+		backend:           backend,
 		logger:            logger,
-	}, nil
+		probes:            probes,
+		ctx:               ctx,
+		stop:              stop,
+	}
+	if cache.expires() || cache.MaxBytes > 0 {
+		go mr.janitor(ctx)
+	}
+	if probeWorkers > 0 && mr.cap.Has(repo.CapabilityRemux) {
+		mr.startProbing(probeWorkers)
+	}
+
+	return mr, nil
+}
+
+// checkWritable checks whether files can be made in a directory.
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".writable-*")
+	if err != nil {
+		return err
+	}
+
+	return multierr.Append(f.Close(), os.Remove(f.Name()))
 }
 
 func (mr *muxRepo) Capabilities() repo.Capability {
 	return mr.MutableRepository.Capabilities() | mr.cap
 }
 
-func (mr *muxRepo) Scan() error {
-	if err := mr.MutableRepository.Scan(); err != nil {
+func (mr *muxRepo) Unavailable() map[repo.Capability]string {
+	inner := mr.MutableRepository.Unavailable()
+	if len(mr.unavailable) == 0 {
+		return inner
+	}
+
+	unavailable := make(map[repo.Capability]string, len(inner)+len(mr.unavailable))
+	maps.Copy(unavailable, inner)
+	maps.Copy(unavailable, mr.unavailable)
+	return unavailable
+}
+
+func (mr *muxRepo) Scan(mode repo.ScanMode) error {
+	if err := mr.MutableRepository.Scan(mode); err != nil {
 		return err
 	}
 
@@ -135,8 +185,9 @@ func (mr *muxRepo) Scan() error {
 		items  = mr.MutableRepository.Items() // snapshot repo items
 		hashes = make(map[string]struct{}, len(items))
 	)
+	mr.probes.prune(items)
 	for _, item := range items {
-		hash, err := makeHash(item.Path())
+		hash, err := media.HashMedia(item)
 		if err != nil {
 			return errors.Wrap(err, "failed to make hash")
 		}
@@ -145,27 +196,19 @@ func (mr *muxRepo) Scan() error {
 	}
 
 	err := mr.walkCache(func(path string, d fs.DirEntry) error {
-		var (
-			name = d.Name()
-			hash = strings.TrimSuffix(name, filepath.Ext(name))
-		)
-		if _, ok := hashes[hash]; !ok { // doesn't exist in repo, remove
-			_, err := mr.mu.Do(path, func() (interface{}, error) {
-				return nil, os.Remove(path)
-			})
-			if mr.logger != nil {
-				mr.logger.Info(
-					"removed unused cache file",
-					zap.String("repo", mr.MutableRepository.ID()),
-					zap.String("repo_path", mr.MutableRepository.Path()),
-					zap.String("path", path),
-				)
-			}
+		if strings.HasSuffix(path, partSuffix) {
+			return mr.removePartFile(path)
+		}
+		if _, ok := hashes[cacheHash(d.Name())]; !ok { // doesn't exist in repo, remove
+			return mr.removeCacheFile(path, "removed unused cache file")
+		}
 
+		expired, err := mr.expired(path, d)
+		if err != nil || !expired {
 			return err
 		}
 
-		return nil
+		return mr.removeCacheFile(path, "removed expired cache file")
 	})
 	if err != nil {
 		return errors.Wrap(err, "failed to walk + delete media")
@@ -175,8 +218,12 @@ func (mr *muxRepo) Scan() error {
 }
 
 func (mr *muxRepo) Remove(m media.Media) error {
-	hash, err := makeHash(m.Path())
+	hash, err := media.HashMedia(m)
 	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) { // deleted already, cache files are removed on the next scan
+			return mr.MutableRepository.Remove(m)
+		}
+
 		return errors.Wrap(err, "failed to make hash")
 	}
 
@@ -188,47 +235,29 @@ func (mr *muxRepo) Remove(m media.Media) error {
 }
 
 func (mr *muxRepo) RemovePath(path string) error {
-	hash, err := makeHash(path)
-	if err != nil {
-		return errors.Wrap(err, "failed to make hash")
+	if media.URLScheme(path) != "" { // remote, cache files are removed on the next scan
+		return mr.MutableRepository.RemovePath(path)
 	}
 
-	if err := mr.MutableRepository.RemovePath(path); err != nil {
-		return err
-	}
-
-	return mr.remove(hash)
-}
-
-func makeHash(path string) (_ string, err error) {
-	f, err := os.Open(path)
+	hash, err := media.Hash(path)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to open file")
-	}
-	defer func() {
-		if err0 := f.Close(); err0 != nil {
-			err = multierr.Append(err, errors.Wrap(err0, "failed to close file"))
+		if errors.Is(err, fs.ErrNotExist) { // deleted already, cache files are removed on the next scan
+			return mr.MutableRepository.RemovePath(path)
 		}
-	}()
 
-	fi, err := f.Stat()
-	if err != nil {
-		return "", errors.Wrap(err, "failed to stat file")
+		return errors.Wrap(err, "failed to make hash")
 	}
 
-	h := md5.New()
-	if _, err = io.Copy(h, io.LimitReader(f, 1024*1024)); err != nil {
-		return "", errors.Wrap(err, "failed to read file")
+	if err := mr.MutableRepository.RemovePath(path); err != nil {
+		return err
 	}
 
-	_ = binary.Write(h, binary.LittleEndian, fi.Size())
-	return hex.EncodeToString(h.Sum(nil)), err
+	return mr.remove(hash)
 }
 
 func (mr *muxRepo) remove(hash string) error {
 	err := mr.walkCache(func(path string, d fs.DirEntry) error {
-		name := d.Name()
-		if strings.TrimSuffix(name, filepath.Ext(name)) == hash {
+		if cacheHash(d.Name()) == hash {
 			_, err := mr.mu.Do(path, func() (interface{}, error) {
 				return nil, os.Remove(path)
 			})
@@ -244,14 +273,24 @@ func (mr *muxRepo) remove(hash string) error {
 	return nil
 }
 
+// cacheHash returns the media hash part of a cache file name ("<hash>.<ext>" or "<hash>-<profile key>.<ext>", optionally with a ".part" suffix).
+func cacheHash(name string) string {
+	name, _, _ = strings.Cut(name, ".")
+	name, _, _ = strings.Cut(name, "-")
+
+	return name
+}
+
 type walkFunc func(path string, d fs.DirEntry) error
 
 func (mr *muxRepo) walkCache(fn walkFunc) error {
-	if err := walkFiles(mr.remuxPath, fn); err != nil {
-		return err
-	}
-	if err := walkFiles(mr.transcodePath, fn); err != nil {
-		return err
+	for _, path := range []string{mr.remuxPath, mr.transcodePath} {
+		if path == "" { // capability not enabled
+			continue
+		}
+		if err := walkFiles(path, fn); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -266,7 +305,19 @@ func walkFiles(path string, fn walkFunc) error {
 	})
 }
 
-func (mr *muxRepo) Remux(id string, format *media.Format) (media.Media, error) {
+func (mr *muxRepo) Remux(ctx context.Context, id string, format *media.Format) (_ media.Media, err error) {
+	ctx, span := trace.Start(
+		ctx,
+		"mux.remux",
+		attribute.String("repo.id", mr.MutableRepository.ID()),
+		attribute.String("media.id", id),
+		attribute.String("media.format", format.Name),
+	)
+	defer func() {
+		trace.Fail(span, err)
+		span.End()
+	}()
+
 	m := mr.MutableRepository.Get(id)
 	if m == nil {
 		return nil, nil
@@ -278,139 +329,62 @@ func (mr *muxRepo) Remux(id string, format *media.Format) (media.Media, error) {
 		return m, nil
 	}
 
-	path := m.Path()
-
-	hash, err := makeHash(path)
+	hash, err := media.HashMedia(m)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to make hash")
 	}
 
-	remuxedPath := filepath.Join(mr.remuxPath, hash+"."+format.Extension)
-	res, err := mr.mu.Do(path, func() (interface{}, error) {
-		remuxMedia := &relocatedMedia{
-			Media: m,
-			path:  remuxedPath,
-			mime:  format.MIME,
-		}
-		if _, err := os.Stat(remuxMedia.path); err == nil {
-			return remuxMedia, nil // already remuxed
-		}
-
-		muxDem, ok := formats[format]
-		if !ok || muxDem.muxer == nil {
-			return nil, &repo.ErrUnsupportedFormat{
-				Format:    format.Name,
-				Operation: "muxing",
-			}
-		}
-
-		if err := mr.remux(muxDem.muxer, path, remuxMedia.path); err != nil {
-			return nil, errors.Wrap(err, "failed to remux")
-		}
-
-		return remuxMedia, nil
-	})
+	remuxedPath, err := mr.cachePath(mr.remuxPath, hash, format.Extension)
 	if err != nil {
 		return nil, err
 	}
 
-	return res.(media.Media), nil
-}
-
-func (mr *muxRepo) remux(muxer *mux.Muxer, src, dst string) (err error) {
-	inCtx, err := mux.NewInputContext(src)
-	if err != nil {
-		return errors.Wrap(err, "failed to open input context")
-	}
-	defer inCtx.Close()
-
-	outCtx, err := mux.NewOutputContext(muxer, dst)
-	if err != nil {
-		return errors.Wrap(err, "failed to open output context")
-	}
-	defer outCtx.Close()
-
-	var (
-		streams = inCtx.Streams()
-
-		streamMapping   = make([]int, len(streams))
-		lastStreamIndex = 0
-	)
-	for i, inStream := range streams {
-		var (
-			codec   = inStream.Codec()
-			remapId = -1
-		)
-		if muxer.SupportsCodec(codec) {
-			outStream := outCtx.NewStream(codec)
-			if err := inStream.CopyParameters(outStream); err != nil {
-				return errors.Wrapf(err, "failed to copy stream %d parameters", i)
-			}
-
-			remapId = lastStreamIndex
-			lastStreamIndex++
-		} else if mr.logger != nil { // codec not supported in container, strip
-			mr.logger.Warn(
-				"skipping unsupported codec in stream",
-				zap.String("codec", codec.Name()),
-				zap.String("format", muxer.Name()),
-				zap.Int("stream", i),
-				zap.String("src", src),
-				zap.String("dst", dst),
-			)
-		}
-
-		streamMapping[i] = remapId
+	if err := mr.checkCached(ctx, m, remuxedPath); err != nil {
+		return nil, err
 	}
-
-	pkt := mux.NewPacket()
-	defer pkt.Close()
-
-	if err := outCtx.WriteHeader(); err != nil {
-		return errors.Wrap(err, "failed to write header")
+	if !mr.backend.Features().Fragmenting { // the partial file wouldn't be readable
+		ctx = repo.WithPartial(ctx, nil)
 	}
 
-	for {
-		err = inCtx.ReadFrame(pkt)
+	err = mr.produce(ctx, remuxedPath, func(ctx context.Context, tmp string) error {
+		path, release, err := mr.input(ctx, m)
 		if err != nil {
-			if err != io.EOF {
-				err = errors.Wrap(err, "failed to read frame")
-			}
-
-			break
+			return err
 		}
+		defer release()
 
-		streamIdx := pkt.StreamIndex()
-		if remapId := streamMapping[streamIdx]; remapId >= 0 {
-			pkt.SetStreamIndex(remapId)
-
-			pkt.Rescale(
-				inCtx.Stream(streamIdx).TimeBase(),
-				outCtx.Stream(remapId).TimeBase(),
-			)
-			pkt.ResetPos()
-
-			if err := outCtx.WriteFrame(pkt); err != nil {
-				err = errors.Wrap(err, "failed to write frame")
-				break
-			}
-			// WriteFrame takes ownership of the packet and resets it, no need to clear here
-		} else {
-			if err := pkt.Clear(); err != nil {
-				err = errors.Wrap(err, "failed to clear packet")
-				break
-			}
+		if err := mr.backend.Remux(ctx, path, tmp, format); err != nil {
+			return errors.Wrap(err, "failed to remux")
 		}
-	}
-	if err == nil || err == io.EOF {
-		if err := outCtx.WriteEnd(); err != nil {
-			return errors.Wrap(err, "failed to write end")
+		if err := mr.validate(ctx, path, tmp); err != nil { // a backend can end early without an error
+			return errors.Wrap(err, "invalid remux output")
 		}
 
+		mr.validated(tmp, remuxedPath)
 		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &relocatedMedia{
+		Media:  m,
+		path:   remuxedPath,
+		format: format,
+	}, nil
+}
+
+// Close cancels running conversions and waits for them to clean up their partial files before closing the underlying repository.
+// Probe results are saved a final time.
+func (mr *muxRepo) Close() error {
+	if mr.unsubscribe != nil {
+		mr.unsubscribe()
 	}
+	mr.stop()
+	mr.ops.Close()
+	mr.probing.Close()
 
-	return err
+	return multierr.Append(mr.probes.flush(), mr.MutableRepository.Close())
 }
 
 func (mr *muxRepo) Mutable() repo.MutableRepository {
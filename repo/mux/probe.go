@@ -0,0 +1,390 @@
+package mux
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// probesFile is the name of the file in the cache directory persisting the container information of media.
+	probesFile = "probes.json"
+	// probeQueueSize is the maximum number of added media waiting to be probed, the rest is left for Probe.
+	probeQueueSize = 1024
+	// probeSaveInterval is the minimum period between saves of the probe results while media are being probed.
+	probeSaveInterval = time.Minute
+)
+
+// probeSubtitle is a JSON-serializable embedded subtitle track of a media file.
+type probeSubtitle struct {
+	Codec    string `json:"codec"`
+	Language string `json:"language"`
+	Stream   int    `json:"stream"`
+}
+
+// probeAudio is a JSON-serializable embedded audio track of a media file.
+type probeAudio struct {
+	Codec    string `json:"codec"`
+	Language string `json:"language"`
+	Title    string `json:"title,omitempty"`
+	Stream   int    `json:"stream"`
+}
+
+// probeChapter is a JSON-serializable chapter marker of a media file.
+type probeChapter struct {
+	Title string        `json:"title,omitempty"`
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+}
+
+// probeEntry is the container information of a media file, read ahead of requests needing it.
+type probeEntry struct {
+	Size      int64           `json:"size"`
+	ModTime   time.Time       `json:"mod_time"`
+	Subtitles []probeSubtitle `json:"subtitles"`
+	Audio     []probeAudio    `json:"audio"`
+	Chapters  []probeChapter  `json:"chapters"`
+	// NoChapters is whether the backend can't read chapters, Chapters is empty then.
+	NoChapters bool `json:"no_chapters,omitempty"`
+}
+
+// matches checks whether the entry was probed from the file of the stat, i.e. the file wasn't modified since.
+// Entries persisted before audio tracks or chapters were probed don't match, the files are probed again.
+func (pe *probeEntry) matches(stat repo.FileStat) bool {
+	return pe.Size == stat.Size && pe.ModTime.Equal(stat.ModTime) && pe.Audio != nil && (pe.Chapters != nil || pe.NoChapters)
+}
+
+// subtitles returns the embedded subtitle tracks of the entry with their IDs set.
+func (pe *probeEntry) subtitles() []*media.Subtitle {
+	subtitles := make([]*media.Subtitle, len(pe.Subtitles))
+	for i, s := range pe.Subtitles {
+		subtitles[i] = &media.Subtitle{
+			ID:       fmt.Sprintf("%s%d", embeddedPrefix, s.Stream),
+			Codec:    s.Codec,
+			Language: parseLanguage(s.Language),
+			Stream:   s.Stream,
+		}
+	}
+
+	return subtitles
+}
+
+// audioTracks returns the embedded audio tracks of the entry.
+func (pe *probeEntry) audioTracks() []*media.AudioTrack {
+	tracks := make([]*media.AudioTrack, len(pe.Audio))
+	for i, a := range pe.Audio {
+		tracks[i] = &media.AudioTrack{
+			Codec:    a.Codec,
+			Language: parseLanguage(a.Language),
+			Title:    a.Title,
+			Stream:   a.Stream,
+		}
+	}
+
+	return tracks
+}
+
+// chapters returns the chapter markers of the entry, repo.ErrUnsupportedFormat if the backend couldn't read them.
+func (pe *probeEntry) chapters() ([]*media.Chapter, error) {
+	if pe.NoChapters {
+		return nil, &repo.ErrUnsupportedFormat{
+			Format:    "container",
+			Operation: "chapter reading",
+		}
+	}
+
+	chapters := make([]*media.Chapter, len(pe.Chapters))
+	for i, c := range pe.Chapters {
+		chapters[i] = &media.Chapter{Title: c.Title, Start: c.Start, End: c.End}
+	}
+
+	return chapters, nil
+}
+
+// hasAudioLanguage checks whether the entry has an audio track in a language, regions and scripts are disregarded.
+func (pe *probeEntry) hasAudioLanguage(lang language.Tag) bool {
+	base, _ := lang.Base()
+	for _, a := range pe.Audio {
+		if b, _ := parseLanguage(a.Language).Base(); b == base {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseLanguage parses a persisted language tag, language.Und if it's invalid.
+func parseLanguage(s string) language.Tag {
+	tag, err := language.Parse(s)
+	if err != nil {
+		return language.Und
+	}
+
+	return tag
+}
+
+// probeStore is a persisted store of probe results, keyed by media ID.
+type probeStore struct {
+	path string
+
+	mu       sync.Mutex
+	entries  map[string]*probeEntry
+	dirty    bool
+	lastSave time.Time
+}
+
+func newProbeStore(path string) (*probeStore, error) {
+	ps := &probeStore{path: path, entries: make(map[string]*probeEntry)}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ps, nil
+		}
+
+		return nil, errors.Wrap(err, "failed to read probe results")
+	}
+	if err := json.Unmarshal(b, &ps.entries); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal probe results")
+	}
+	if ps.entries == nil {
+		ps.entries = make(map[string]*probeEntry)
+	}
+
+	return ps, nil
+}
+
+// get returns the entry of media, if it was probed from the file of the stat.
+func (ps *probeStore) get(id string, stat repo.FileStat) (*probeEntry, bool) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	pe, ok := ps.entries[id]
+	if !ok || !pe.matches(stat) {
+		return nil, false
+	}
+
+	return pe, true
+}
+
+// set stores the entry of media, the store is saved if it hasn't been saved for a while.
+func (ps *probeStore) set(id string, pe *probeEntry) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	ps.entries[id] = pe
+	ps.dirty = true
+	if time.Since(ps.lastSave) < probeSaveInterval {
+		return nil
+	}
+
+	return ps.save()
+}
+
+// remove forgets the entry of media.
+func (ps *probeStore) remove(id string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if _, ok := ps.entries[id]; ok {
+		delete(ps.entries, id)
+		ps.dirty = true
+	}
+}
+
+// prune forgets the entries of media not in the items.
+func (ps *probeStore) prune(items []media.Media) {
+	ids := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		ids[item.ID()] = struct{}{}
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for id := range ps.entries {
+		if _, ok := ids[id]; !ok {
+			delete(ps.entries, id)
+			ps.dirty = true
+		}
+	}
+}
+
+// flush saves the store if it changed since the last save.
+func (ps *probeStore) flush() error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	return ps.save()
+}
+
+// save saves the store if it changed since the last save, mu must be held.
+func (ps *probeStore) save() error {
+	if !ps.dirty {
+		return nil
+	}
+
+	b, err := json.Marshal(ps.entries)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal probe results")
+	}
+
+	tmp := ps.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return errors.Wrap(err, "failed to write probe results")
+	}
+	if err := os.Rename(tmp, ps.path); err != nil {
+		return errors.Wrap(err, "failed to replace probe results")
+	}
+
+	ps.dirty = false
+	ps.lastSave = time.Now()
+	return nil
+}
+
+// startProbing starts the workers probing media added to the repository, they're stopped along with the repository.
+func (mr *muxRepo) startProbing(workers int) {
+	mr.probeQueue = make(chan string, probeQueueSize)
+	for i := 0; i < workers; i++ {
+		if !mr.probing.Enter() {
+			break
+		}
+
+		go func() {
+			defer mr.probing.Leave()
+
+			for {
+				select {
+				case <-mr.ctx.Done():
+					return
+				case id := <-mr.probeQueue:
+					mr.probeQueued(id)
+				}
+			}
+		}()
+	}
+
+	mr.unsubscribe = mr.MutableRepository.Events().Subscribe(func(e repo.Event) {
+		switch e.Type {
+		case repo.EventMediaAdded, repo.EventMediaUpdated, repo.EventMediaMoved:
+			select {
+			case mr.probeQueue <- e.Media.ID():
+			default: // full, left for the next backfill
+			}
+		case repo.EventMediaRemoved:
+			mr.probes.remove(e.Media.ID())
+		}
+	})
+}
+
+// probeQueued probes media queued by an event, unless it has been probed already.
+func (mr *muxRepo) probeQueued(id string) {
+	m := mr.MutableRepository.Get(id)
+	if m == nil || !mr.readable(m) { // removed in the meantime or remote, probing it would take a full copy of the file
+		return
+	}
+	if _, ok := mr.probes.get(id, mr.MutableRepository.FileStat(id)); ok {
+		return
+	}
+
+	if _, err := mr.probe(m); err != nil && mr.logger != nil {
+		mr.logger.Warn("failed to probe media", zap.String("repo", mr.MutableRepository.ID()), zap.String("path", m.Path()), zap.Error(err))
+	}
+}
+
+// probe reads the container information of a media file and stores it.
+// Audio tracks without a language tag get the language named in their title or,
+// if there's only one, in the tags of the file name ("Movie.2020.FRENCH.1080p.mkv").
+func (mr *muxRepo) probe(m media.Media) (*probeEntry, error) {
+	stat := mr.MutableRepository.FileStat(m.ID()) // before probing, a file modified meanwhile is probed again
+
+	subtitles, err := mr.backend.Subtitles(m.Path())
+	if err != nil {
+		return nil, err
+	}
+	tracks, err := mr.backend.AudioTracks(m.Path())
+	if err != nil {
+		return nil, err
+	}
+	chapters, err := mr.backend.Chapters(m.Path())
+	var noChapters *repo.ErrUnsupportedFormat
+	if err != nil && !errors.As(err, &noChapters) { // unsupported by the backend, the rest is still probed
+		return nil, err
+	}
+
+	pe := &probeEntry{
+		Size:       stat.Size,
+		ModTime:    stat.ModTime,
+		Subtitles:  make([]probeSubtitle, len(subtitles)),
+		Audio:      make([]probeAudio, len(tracks)),
+		Chapters:   make([]probeChapter, len(chapters)),
+		NoChapters: noChapters != nil,
+	}
+	for i, s := range subtitles {
+		pe.Subtitles[i] = probeSubtitle{Codec: s.Codec, Language: s.Language.String(), Stream: s.Stream}
+	}
+	for i, t := range tracks {
+		lang := t.Language
+		if lang == language.Und {
+			lang = media.GuessLanguage(t.Title, false)
+		}
+		if lang == language.Und && len(tracks) == 1 {
+			lang = media.GuessLanguage(filepath.Base(m.Path()), true)
+		}
+
+		pe.Audio[i] = probeAudio{Codec: t.Codec, Language: lang.String(), Title: t.Title, Stream: t.Stream}
+	}
+	for i, c := range chapters {
+		pe.Chapters[i] = probeChapter{Title: c.Title, Start: c.Start, End: c.End}
+	}
+	if err := mr.probes.set(m.ID(), pe); err != nil && mr.logger != nil {
+		mr.logger.Error("failed to save probe results", zap.String("repo", mr.MutableRepository.ID()), zap.Error(err))
+	}
+
+	return pe, nil
+}
+
+func (mr *muxRepo) Probe(ctx context.Context) error {
+	if !mr.cap.Has(repo.CapabilityRemux) {
+		return mr.MutableRepository.Probe(ctx)
+	}
+
+	var pending []media.Media
+	for _, item := range mr.MutableRepository.Items() {
+		if !mr.readable(item) {
+			continue
+		}
+		if _, ok := mr.probes.get(item.ID(), mr.MutableRepository.FileStat(item.ID())); !ok {
+			pending = append(pending, item)
+		}
+	}
+
+	progress := repo.Progress{Total: int64(len(pending))}
+	repo.ReportProgress(ctx, progress)
+	for _, item := range pending {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if _, err := mr.probe(item); err != nil {
+			if logger := repo.Logger(ctx, mr.logger); logger != nil { // removed in the meantime, unreadable, ...
+				logger.Warn("failed to probe media", zap.String("repo", mr.MutableRepository.ID()), zap.String("path", item.Path()), zap.Error(err))
+			}
+		}
+
+		progress.Processed++
+		repo.ReportProgress(ctx, progress)
+	}
+
+	return mr.probes.flush()
+}
@@ -0,0 +1,53 @@
+package mux
+
+import (
+	"context"
+	"github.com/katana-project/katana/repo"
+	"os"
+	"time"
+)
+
+// progressInterval is the minimum interval between progress reports.
+const progressInterval = 500 * time.Millisecond
+
+// progressReporter tracks and reports the progress of a remux or transcode operation.
+type progressReporter struct {
+	ctx      context.Context
+	dst      string
+	progress repo.Progress
+	last     time.Time
+}
+
+func newProgressReporter(ctx context.Context, src, dst string) *progressReporter {
+	pr := &progressReporter{ctx: ctx, dst: dst}
+	if fi, err := os.Stat(src); err == nil {
+		pr.progress.Total = fi.Size()
+	}
+
+	return pr
+}
+
+// update tracks the source byte position of a read packet, -1 if unknown, and reports progress if due.
+func (pr *progressReporter) update(pos int) {
+	if int64(pos) > pr.progress.Processed {
+		pr.progress.Processed = int64(pos)
+	}
+	if time.Since(pr.last) >= progressInterval {
+		pr.report()
+	}
+}
+
+// done reports the operation as fully processed.
+func (pr *progressReporter) done() {
+	pr.progress.Processed = pr.progress.Total
+	pr.report()
+}
+
+func (pr *progressReporter) report() {
+	if fi, err := os.Stat(pr.dst); err == nil {
+		pr.progress.Written = fi.Size()
+	}
+
+	pr.last = time.Now()
+	repo.ReportProgress(pr.ctx, pr.progress)
+}
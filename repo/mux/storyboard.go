@@ -0,0 +1,186 @@
+package mux
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/internal/trace"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/storyboard"
+	"go.opentelemetry.io/otel/attribute"
+	"os"
+)
+
+// storyboardKey is the cache file name suffix of storyboards, in place of a transcode profile key.
+// The layout is kept in "<hash>-storyboard.json", the sprite sheets in "<hash>-storyboard-<index>.jpg".
+const storyboardKey = "storyboard"
+
+func (mr *muxRepo) Storyboard(ctx context.Context, id string) (_ *storyboard.Storyboard, err error) {
+	ctx, span := trace.Start(
+		ctx,
+		"mux.storyboard",
+		attribute.String("repo.id", mr.MutableRepository.ID()),
+		attribute.String("media.id", id),
+	)
+	defer func() {
+		trace.Fail(span, err)
+		span.End()
+	}()
+
+	if !mr.cap.Has(repo.CapabilityTranscode) {
+		return nil, &repo.ErrUnsupportedOperation{
+			Operation: "storyboard",
+			Repo:      mr.MutableRepository.ID(),
+		}
+	}
+
+	m := mr.MutableRepository.Get(id)
+	if m == nil {
+		return nil, nil
+	}
+	if m.Format().Audio() {
+		return nil, &repo.ErrUnsupportedFormat{
+			Format:    m.Format().Name,
+			Operation: "storyboard",
+		}
+	}
+	if !mr.backend.Features().Storyboards { // not queued just to fail taking thumbnails
+		return nil, &repo.ErrUnsupportedFormat{
+			Format:    "video",
+			Operation: "thumbnail extraction",
+		}
+	}
+
+	hash, err := media.HashMedia(m)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make hash")
+	}
+
+	// kept with transcoded files, sharing their retention
+	layoutPath, err := mr.cachePath(mr.transcodePath, hash+"-"+storyboardKey, "json")
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		err = mr.produce(ctx, layoutPath, func(ctx context.Context, tmp string) error {
+			return mr.makeStoryboard(ctx, m, hash, tmp)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		sb, complete, err := mr.readStoryboard(layoutPath, hash)
+		if err != nil || complete {
+			return sb, err
+		}
+
+		// sheets were evicted, they're made again along with the layout
+		if err := mr.removeCacheFile(layoutPath, "removed incomplete storyboard"); err != nil {
+			return nil, errors.Wrap(err, "failed to remove incomplete storyboard")
+		}
+	}
+}
+
+// sheetPath returns the path of a storyboard sprite sheet.
+func (mr *muxRepo) sheetPath(hash string, sheet int) (string, error) {
+	return mr.cachePath(mr.transcodePath, fmt.Sprintf("%s-%s-%d", hash, storyboardKey, sheet), "jpg")
+}
+
+// makeStoryboard makes the sprite sheets of media, then writes the storyboard layout to a file, marking it complete.
+// Sheets made already are kept, progress is reported in sheets.
+func (mr *muxRepo) makeStoryboard(ctx context.Context, m media.Media, hash, dst string) error {
+	path, release, err := mr.input(ctx, m)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	info, err := mr.backend.Info(ctx, path)
+	if err != nil {
+		return err
+	}
+	if info.Duration <= 0 {
+		return &repo.ErrUnsupportedFormat{
+			Format:    m.Format().Name,
+			Operation: "storyboard",
+		}
+	}
+
+	var (
+		sb       = &storyboard.Storyboard{Layout: *storyboard.Default, Duration: info.Duration}
+		progress = repo.Progress{Total: int64(sb.Sheets())}
+	)
+	repo.ReportProgress(ctx, progress)
+	for i := 0; i < sb.Sheets(); i++ {
+		sheetPath, err := mr.sheetPath(hash, i)
+		if err != nil {
+			return err
+		}
+
+		err = mr.produce(ctx, sheetPath, func(ctx context.Context, tmp string) error {
+			start, count := sb.Sheet(i)
+			thumbnails, err := mr.backend.Thumbnails(ctx, path, start, sb.Interval, count, sb.Width, sb.Height)
+			if err != nil {
+				return err
+			}
+			if len(thumbnails) == 0 {
+				return fmt.Errorf("no thumbnails from %s", start)
+			}
+
+			f, err := os.Create(tmp)
+			if err != nil {
+				return errors.Wrap(err, "failed to create sprite sheet")
+			}
+			defer f.Close()
+
+			return sb.WriteSheet(f, thumbnails)
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to make sprite sheet")
+		}
+
+		progress.Processed++
+		repo.ReportProgress(ctx, progress)
+	}
+
+	b, err := json.Marshal(sb)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal storyboard")
+	}
+	if err := os.WriteFile(dst, b, 0644); err != nil {
+		return errors.Wrap(err, "failed to write storyboard")
+	}
+
+	return nil
+}
+
+// readStoryboard reads a storyboard layout file and sets the paths of its sprite sheets, marking them as used.
+// Returns false if any of the sheets is missing.
+func (mr *muxRepo) readStoryboard(path, hash string) (*storyboard.Storyboard, bool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to read storyboard")
+	}
+
+	var sb storyboard.Storyboard
+	if err := json.Unmarshal(b, &sb); err != nil {
+		return nil, false, errors.Wrap(err, "failed to unmarshal storyboard")
+	}
+
+	sb.Paths = make([]string, sb.Sheets())
+	for i := range sb.Paths {
+		if sb.Paths[i], err = mr.sheetPath(hash, i); err != nil {
+			return nil, false, err
+		}
+		if _, err := os.Stat(sb.Paths[i]); err != nil {
+			return nil, false, nil
+		}
+
+		mr.touch(sb.Paths[i])
+	}
+
+	return &sb, true, nil
+}
@@ -0,0 +1,121 @@
+package mux
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"strings"
+)
+
+// embeddedPrefix is the track ID prefix of embedded subtitle tracks, followed by the stream index.
+const embeddedPrefix = "stream-"
+
+// subtitleFormats are the text subtitle codecs mapped to their container formats.
+var subtitleFormats = map[string]SubtitleFormat{
+	media.SubtitleCodecSubRip: {Name: "srt", Extension: "srt", MIME: "application/x-subrip"},
+	media.SubtitleCodecASS:    {Name: "ass", Extension: "ass", MIME: "text/x-ass"},
+	"ssa":                     {Name: "ass", Extension: "ass", MIME: "text/x-ass"},
+	media.SubtitleCodecWebVTT: {Name: "webvtt", Extension: "vtt", MIME: "text/vtt"},
+}
+
+func (mr *muxRepo) Subtitles(id string) ([]*media.Subtitle, error) {
+	subtitles, err := mr.MutableRepository.Subtitles(id)
+	if err != nil || subtitles == nil || !mr.cap.Has(repo.CapabilityRemux) {
+		return subtitles, err
+	}
+
+	m := mr.MutableRepository.Get(id)
+	if m == nil { // removed in the meantime
+		return nil, nil
+	}
+
+	embedded, err := mr.embeddedSubtitles(m)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read embedded subtitles")
+	}
+
+	return append(embedded, subtitles...), nil
+}
+
+// embeddedSubtitles lists the embedded subtitle tracks of a media file, probing it only if it hasn't been probed yet.
+// Tracks of remote media the backend can't read are left out, listing them would take a full copy of the file.
+func (mr *muxRepo) embeddedSubtitles(m media.Media) ([]*media.Subtitle, error) {
+	if !mr.readable(m) {
+		return nil, nil
+	}
+
+	if pe, ok := mr.probes.get(m.ID(), mr.MutableRepository.FileStat(m.ID())); ok {
+		return pe.subtitles(), nil
+	}
+
+	pe, err := mr.probe(m) // not probed ahead, e.g. added before probing was enabled
+	if err != nil {
+		return nil, err
+	}
+
+	return pe.subtitles(), nil
+}
+
+func (mr *muxRepo) ExtractSubtitle(ctx context.Context, id, trackId string) (*media.Subtitle, error) {
+	if !strings.HasPrefix(trackId, embeddedPrefix) {
+		return mr.MutableRepository.ExtractSubtitle(ctx, id, trackId)
+	}
+	if !mr.cap.Has(repo.CapabilityRemux) {
+		return nil, &repo.ErrUnsupportedOperation{
+			Operation: "subtitle extraction",
+			Repo:      mr.MutableRepository.ID(),
+		}
+	}
+
+	m := mr.MutableRepository.Get(id)
+	if m == nil {
+		return nil, nil
+	}
+
+	subtitles, err := mr.embeddedSubtitles(m)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read embedded subtitles")
+	}
+
+	var subtitle *media.Subtitle
+	for _, s := range subtitles {
+		if s.ID == trackId {
+			subtitle = s
+			break
+		}
+	}
+	if subtitle == nil {
+		return nil, nil
+	}
+
+	subFmt, ok := subtitleFormats[subtitle.Codec]
+	if !ok { // bitmap subtitles
+		return nil, &repo.ErrUnsupportedFormat{
+			Format:    subtitle.Codec,
+			Operation: "subtitle extraction",
+		}
+	}
+
+	hash, err := media.HashMedia(m)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make hash")
+	}
+
+	if subtitle.Path, err = mr.cachePath(mr.remuxPath, hash+"-"+trackId, subFmt.Extension); err != nil {
+		return nil, err
+	}
+
+	err = mr.produce(ctx, subtitle.Path, func(ctx context.Context, tmp string) error {
+		if err := mr.backend.ExtractSubtitle(ctx, m.Path(), tmp, subtitle.Stream, subFmt); err != nil {
+			return errors.Wrap(err, "failed to extract subtitles")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subtitle, nil
+}
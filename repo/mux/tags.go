@@ -0,0 +1,186 @@
+package mux
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media/meta"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// dateLayouts are the layouts of release dates in tags, from the most to the least precise.
+	dateLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02", "2006-01", "2006"}
+	// genreSeparator splits the genre tags of multiple genres, such as "Rock; Pop".
+	genreSeparator = strings.NewReplacer(";", ",", "/", ",")
+)
+
+// tagSource is a metadata source reading the tags embedded in media containers.
+type tagSource struct {
+	backend   Backend
+	coverPath string
+}
+
+// NewTagSource creates a metadata source that reads the title, artist, album, episode and date tags and the cover art
+// embedded in media containers (MKV, MP4, FLAC, MP3), for files where web lookups are meaningless, such as music and home video.
+// Cover art is extracted into the coverPath directory, it's not extracted if coverPath is empty.
+// Files without a title tag are not resolved, queries are never resolved.
+func NewTagSource(backend Backend, coverPath string) meta.Source {
+	return &tagSource{backend: backend, coverPath: coverPath}
+}
+
+// FromFile reads the tags of the file.
+// Tagged episodes (MP4 "show", "season_number" and "episode_sort" tags) are resolved as episodes, tagged music ("artist" and "album")
+// as generic metadata with the artist and album in the overview and other files as movies.
+func (ts *tagSource) FromFile(path string) (meta.Metadata, error) {
+	tags, err := ts.backend.Tags(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read tags")
+	}
+
+	title := strings.TrimSpace(tags["title"])
+	if title == "" {
+		return nil, nil
+	}
+
+	var images []meta.Image
+	if ts.coverPath != "" {
+		coverPath, err := ts.cover(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to extract cover art")
+		}
+		if coverPath != "" {
+			images = append(images, meta.NewImage(meta.ImageTypePoster, coverPath, false, "Cover art"))
+		}
+	}
+
+	var (
+		overview    = firstTag(tags, "description", "synopsis", "summary", "comment")
+		releaseDate = parseDate(firstTag(tags, "date", "date_released", "year", "creation_time"))
+		genres      = splitGenres(tags["genre"])
+	)
+	if show := strings.TrimSpace(tags["show"]); show != "" {
+		episode, err := strconv.Atoi(firstTag(tags, "episode_sort", "episode_id"))
+		if err == nil {
+			season, err := strconv.Atoi(tags["season_number"])
+			if err != nil {
+				season = 1
+			}
+
+			return meta.NewEpisodeMetadata(
+				meta.NewMetadata(meta.TypeEpisode, title, title, overview, releaseDate, 0, nil),
+				meta.NewMovieOrSeriesMetadata(meta.NewMetadata(meta.TypeSeries, show, show, "", time.Time{}, 0, images), genres, nil, nil, nil),
+				season,
+				episode,
+			), nil
+		}
+	}
+
+	artist, album := firstTag(tags, "artist", "album_artist"), tags["album"]
+	if artist != "" || album != "" {
+		if overview == "" {
+			overview = strings.Join(nonEmpty(artist, album), " — ")
+		}
+
+		return meta.NewMetadata(meta.TypeUnknown, title, title, overview, releaseDate, 0, images), nil
+	}
+
+	return meta.NewMovieOrSeriesMetadata(
+		meta.NewMetadata(meta.TypeMovie, title, title, overview, releaseDate, 0, images),
+		genres,
+		nil,
+		nil,
+		nil,
+	), nil
+}
+
+// FromQuery always returns nil, there's nothing to read tags from.
+func (ts *tagSource) FromQuery(_ *meta.Query) (meta.Metadata, error) {
+	return nil, nil
+}
+
+// Search always returns nil, there's nothing to read tags from.
+func (ts *tagSource) Search(_ *meta.Query) ([]meta.MetadataCandidate, error) {
+	return nil, nil
+}
+
+// cover extracts the cover art of a file, unless it's been extracted since the file was last modified.
+// Returns the path of the image, empty if the file has no cover art.
+func (ts *tagSource) cover(path string) (string, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to stat file")
+	}
+
+	hash := sha1.Sum([]byte(path))
+	dst := filepath.Join(ts.coverPath, hex.EncodeToString(hash[:])+".img")
+	if dstStat, err := os.Stat(dst); err == nil && !dstStat.ModTime().Before(stat.ModTime()) {
+		return dst, nil
+	}
+
+	if err := os.MkdirAll(ts.coverPath, 0); err != nil {
+		return "", errors.Wrap(err, "failed to make cover directory")
+	}
+
+	tmp := dst + ".part"
+	ok, err := ts.backend.ExtractCover(context.Background(), path, tmp)
+	if err != nil || !ok {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		return "", errors.Wrap(err, "failed to rename cover art")
+	}
+
+	return dst, nil
+}
+
+// firstTag returns the value of the first present tag of keys, trimmed of whitespace.
+func firstTag(tags map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := strings.TrimSpace(tags[key]); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// parseDate parses the release date of a tag, returns a zero time if it's not in a known layout.
+func parseDate(value string) time.Time {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// splitGenres splits a genre tag, such as "Rock; Pop" -> ["Rock", "Pop"].
+func splitGenres(value string) []string {
+	var genres []string
+	for _, genre := range strings.Split(genreSeparator.Replace(value), ",") {
+		if genre = strings.TrimSpace(genre); genre != "" {
+			genres = append(genres, genre)
+		}
+	}
+
+	return genres
+}
+
+func nonEmpty(values ...string) []string {
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
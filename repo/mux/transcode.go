@@ -0,0 +1,152 @@
+package mux
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/internal/trace"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func (mr *muxRepo) Transcode(ctx context.Context, id string, profile *media.TranscodeProfile) (_ media.Media, err error) {
+	ctx, span := trace.Start(
+		ctx,
+		"mux.transcode",
+		attribute.String("repo.id", mr.MutableRepository.ID()),
+		attribute.String("media.id", id),
+		attribute.String("media.format", profile.Format.Name),
+		attribute.String("transcode.video_codec", profile.VideoCodec),
+		attribute.String("transcode.audio_codec", profile.AudioCodec),
+	)
+	defer func() {
+		trace.Fail(span, err)
+		span.End()
+	}()
+
+	if !mr.cap.Has(repo.CapabilityTranscode) {
+		return nil, &repo.ErrUnsupportedOperation{
+			Operation: "transcode",
+			Repo:      mr.MutableRepository.ID(),
+		}
+	}
+
+	if opt, ok := mr.backend.Features().UnsupportedOption(profile); ok {
+		return nil, &repo.ErrUnsupportedFormat{
+			Format:    profile.Format.Name,
+			Operation: string(opt),
+		}
+	}
+
+	m := mr.MutableRepository.Get(id)
+	if m == nil {
+		return nil, nil
+	}
+
+	hash, err := media.HashMedia(m)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make hash")
+	}
+
+	transcodedPath, err := mr.cachePath(mr.transcodePath, hash+"-"+profile.Key(), profile.Format.Extension)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mr.produce(ctx, transcodedPath, func(ctx context.Context, tmp string) error {
+		path, release, err := mr.input(ctx, m)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		if err := mr.backend.Transcode(ctx, path, tmp, profile); err != nil {
+			return errors.Wrap(err, "failed to transcode")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &relocatedMedia{
+		Media:  m,
+		path:   transcodedPath,
+		format: profile.Format,
+	}, nil
+}
+
+// previewKey is the cache file name suffix of preview clips, in place of a transcode profile key.
+const previewKey = "preview"
+
+func (mr *muxRepo) Preview(ctx context.Context, id string) (_ media.Media, err error) {
+	ctx, span := trace.Start(
+		ctx,
+		"mux.preview",
+		attribute.String("repo.id", mr.MutableRepository.ID()),
+		attribute.String("media.id", id),
+	)
+	defer func() {
+		trace.Fail(span, err)
+		span.End()
+	}()
+
+	if !mr.cap.Has(repo.CapabilityTranscode) {
+		return nil, &repo.ErrUnsupportedOperation{
+			Operation: "preview",
+			Repo:      mr.MutableRepository.ID(),
+		}
+	}
+
+	m := mr.MutableRepository.Get(id)
+	if m == nil {
+		return nil, nil
+	}
+	if m.Format().Audio() {
+		return nil, &repo.ErrUnsupportedFormat{
+			Format:    m.Format().Name,
+			Operation: "preview",
+		}
+	}
+	if !mr.backend.Features().Previews { // not queued just to fail encoding
+		return nil, &repo.ErrUnsupportedFormat{
+			Format:    media.FormatMP4.Name,
+			Operation: "preview encoding",
+		}
+	}
+
+	hash, err := media.HashMedia(m)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make hash")
+	}
+
+	// kept with transcoded files, sharing their retention
+	previewPath, err := mr.cachePath(mr.transcodePath, hash+"-"+previewKey, media.FormatMP4.Extension)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mr.produce(ctx, previewPath, func(ctx context.Context, tmp string) error {
+		path, release, err := mr.input(ctx, m)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		if err := mr.backend.Preview(ctx, path, tmp, DefaultPreview); err != nil {
+			return errors.Wrap(err, "failed to make preview")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &relocatedMedia{
+		Media:  m,
+		path:   previewPath,
+		format: media.FormatMP4,
+	}, nil
+}
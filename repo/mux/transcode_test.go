@@ -0,0 +1,20 @@
+package mux
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"testing"
+)
+
+func TestMuxRepo_TranscodeUnsupportedOption(t *testing.T) {
+	mr := newChapterRepo(t, &chapterBackend{}) // no transcode options, the backend would panic if called
+	mr.cap = repo.CapabilityTranscode
+
+	var euf *repo.ErrUnsupportedFormat
+	_, err := mr.Transcode(context.Background(), "test", &media.TranscodeProfile{Format: media.FormatMP4, VideoCodec: "libx264", Height: 720})
+	if !errors.As(err, &euf) || euf.Operation != string(media.OptionResolution) {
+		t.Errorf("expected unsupported resolution error, got %v", err)
+	}
+}
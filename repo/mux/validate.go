@@ -0,0 +1,119 @@
+package mux
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media"
+	"go.uber.org/zap"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// durationSlack is how much shorter a remuxed file can be than its source, the streams of a container don't end at once.
+const durationSlack = 2 * time.Second
+
+// validations is a record of remuxed cache files validated during the process' lifetime, by their size.
+// Modification times can't be used, they're bumped when the files are used (muxRepo.touch).
+type validations struct {
+	mu    sync.Mutex
+	files map[string]int64 // path -> size
+}
+
+// has checks whether a cache file of a size was validated.
+func (v *validations) has(path string, size int64) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	s, ok := v.files[path]
+	return ok && s == size
+}
+
+// add records a validated cache file of a size.
+func (v *validations) add(path string, size int64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.files == nil {
+		v.files = make(map[string]int64)
+	}
+	v.files[path] = size
+}
+
+// validate checks whether a remuxed file is complete, it must have streams and last about as long as its source.
+// The durations are only compared if the backend can read both, src can be empty if the source shouldn't be read.
+func (mr *muxRepo) validate(ctx context.Context, src, dst string) error {
+	out, err := mr.backend.Info(ctx, dst)
+	if err != nil {
+		return errors.Wrap(err, "failed to read remuxed file")
+	}
+	if out.Streams == 0 {
+		return errors.New("remuxed file has no streams")
+	}
+	if out.Duration <= 0 || src == "" {
+		return nil
+	}
+
+	in, err := mr.backend.Info(ctx, src)
+	if err != nil || in.Duration <= 0 { // the source was converted, the output is what matters
+		return nil
+	}
+	if out.Duration+durationSlack < in.Duration {
+		return fmt.Errorf("remuxed file is truncated, it lasts %s of %s", out.Duration.Round(time.Second), in.Duration.Round(time.Second))
+	}
+
+	return nil
+}
+
+// validated records a remuxed file as valid under the path of the cache file it's renamed to.
+func (mr *muxRepo) validated(tmp, dst string) {
+	if fi, err := os.Stat(tmp); err == nil {
+		mr.valid.add(dst, fi.Size())
+	}
+}
+
+// checkCached validates a remuxed cache file made before, such as by a previous process that died while writing it,
+// once per file per process. An invalid file is removed, so that it's made again.
+func (mr *muxRepo) checkCached(ctx context.Context, m media.Media, path string) error {
+	if fi, err := os.Stat(path); err != nil || mr.valid.has(path, fi.Size()) { // not made yet or validated
+		return nil
+	}
+
+	src := m.Path()
+	if media.URLScheme(src) != "" { // not downloaded just for this
+		src = ""
+	}
+
+	_, err := mr.mu.Do(path, func() (interface{}, error) {
+		fi, err := os.Stat(path)
+		if err != nil { // removed in the meantime
+			return nil, nil
+		}
+
+		verr := mr.validate(ctx, src, path)
+		if verr == nil {
+			mr.valid.add(path, fi.Size())
+			return nil, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil { // interrupted, not invalid
+			return nil, ctxErr
+		}
+
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, errors.Wrap(err, "failed to remove invalid cache file")
+		}
+		if mr.logger != nil {
+			mr.logger.Warn(
+				"removed invalid cache file",
+				zap.String("repo", mr.MutableRepository.ID()),
+				zap.String("path", path),
+				zap.String("reason", verr.Error()),
+			)
+		}
+
+		return nil, nil
+	})
+	return err
+}
@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// caseInsensitive checks whether the filesystem of an absolute directory path is case-insensitive.
+// The check stats the directory under a name with swapped letter case, the OS default is assumed if that's not possible.
+func caseInsensitive(path string) bool {
+	var (
+		name    = filepath.Base(path)
+		altName = strings.Map(swapCase, name)
+	)
+	if name == altName { // no letters to swap
+		return runtime.GOOS == "windows" || runtime.GOOS == "darwin"
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	altFi, err := os.Stat(filepath.Join(filepath.Dir(path), altName))
+	if err != nil {
+		return false // not found, case-sensitive
+	}
+
+	return os.SameFile(fi, altFi)
+}
+
+func swapCase(r rune) rune {
+	if unicode.IsUpper(r) {
+		return unicode.ToLower(r)
+	}
+
+	return unicode.ToUpper(r)
+}
+
+// rootPrefix is the prefix of paths relative to the additional root directories of a repository (Repository.Roots),
+// followed by the index of the root, such as "#1/Movies/Movie.mkv" for the second root.
+// Paths relative to the first root aren't prefixed, so adding roots to a repository keeps its paths.
+const rootPrefix = "#"
+
+// RelPath relativizes an absolute path to the root directory containing it, prefixed for the additional roots (see rootPrefix).
+// Paths outside of all roots are relativized to the first one.
+func RelPath(roots []string, path string) (string, error) {
+	for i, root := range roots {
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return "", err
+		}
+		if !isLocal(relPath) {
+			continue
+		}
+
+		if i > 0 {
+			relPath = rootPrefix + strconv.Itoa(i) + string(filepath.Separator) + relPath
+		}
+		return relPath, nil
+	}
+
+	return filepath.Rel(roots[0], path)
+}
+
+// AbsPath resolves a path made by RelPath to an absolute path.
+func AbsPath(roots []string, relPath string) string {
+	root, relPath := SplitRoot(roots, relPath)
+
+	return filepath.Join(root, relPath)
+}
+
+// SplitRoot splits a path made by RelPath into its root directory and the path relative to that directory.
+func SplitRoot(roots []string, relPath string) (string, string) {
+	relPath = filepath.FromSlash(relPath)
+	if first, rest, ok := strings.Cut(relPath, string(filepath.Separator)); ok && strings.HasPrefix(first, rootPrefix) {
+		if i, err := strconv.Atoi(strings.TrimPrefix(first, rootPrefix)); err == nil && i > 0 && i < len(roots) {
+			return roots[i], rest
+		}
+	}
+
+	return roots[0], relPath
+}
+
+// isLocal checks whether a relative path doesn't lead out of its base directory.
+func isLocal(relPath string) bool {
+	return relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator))
+}
+
+// overlapping checks whether any two of absolute directory paths are the same or nested in each other.
+func overlapping(paths []string) (string, string, bool) {
+	for i, a := range paths {
+		for _, b := range paths[i+1:] {
+			if rel, err := filepath.Rel(a, b); err == nil && isLocal(rel) {
+				return a, b, true
+			}
+			if rel, err := filepath.Rel(b, a); err == nil && isLocal(rel) {
+				return a, b, true
+			}
+		}
+	}
+
+	return "", "", false
+}
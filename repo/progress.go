@@ -0,0 +1,44 @@
+package repo
+
+import (
+	"context"
+	"math"
+)
+
+// Progress is the progress of a long-running repository operation, such as remuxing.
+type Progress struct {
+	// Processed is the number of processed source bytes.
+	Processed int64
+	// Total is the size of the source in bytes, zero if unknown.
+	Total int64
+	// Written is the number of bytes written to the destination.
+	Written int64
+}
+
+// Percent returns the processed fraction of the source in percent, zero if the total is unknown.
+func (p Progress) Percent() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+
+	return math.Min(float64(p.Processed)/float64(p.Total)*100, 100)
+}
+
+// ProgressFunc is a receiver of Progress reports.
+type ProgressFunc func(p Progress)
+
+// progressKey is the context key of a ProgressFunc.
+type progressKey struct{}
+
+// WithProgress returns a copy of the context carrying a ProgressFunc,
+// which is called by repository operations that support progress reporting (Repository.Remux, Repository.Transcode).
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// ReportProgress reports progress to the ProgressFunc carried by the context, if there is one.
+func ReportProgress(ctx context.Context, p Progress) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(p)
+	}
+}
@@ -0,0 +1,203 @@
+package repo
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"golang.org/x/exp/slices"
+	"golang.org/x/text/language"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SortKey is a media attribute to sort by.
+type SortKey string
+
+const (
+	// SortTitle sorts media by their title case-insensitively, media without metadata by their file name.
+	SortTitle SortKey = "title"
+	// SortReleaseDate sorts media by their release date, media without metadata first.
+	SortReleaseDate SortKey = "release_date"
+	// SortAdded sorts media by the time of being added, which is the modification time of the file at that time.
+	SortAdded SortKey = "added_at"
+)
+
+// MatchFilter is a selection of media by how well their metadata matched.
+type MatchFilter string
+
+const (
+	// MatchUnmatched selects media without metadata.
+	MatchUnmatched MatchFilter = "unmatched"
+	// MatchLowConfidence selects media with metadata presumed to be of other media (see meta.LowConfidence).
+	MatchLowConfidence MatchFilter = "low_confidence"
+)
+
+// Query is a filtered, sorted and paginated selection of media in a repository.
+type Query struct {
+	// Type is the metadata type of selected media, nil selects all types.
+	// Media without metadata are of the meta.TypeUnknown type.
+	Type *meta.Type
+	// Genre is the genre name of selected media, case-insensitive, empty selects all genres.
+	// Episodes are of the genres of their series.
+	Genre string
+	// Tag is a user-defined tag of selected media (see MutableRepository.SetTags), empty selects all media.
+	Tag string
+	// Year is the release year of selected media, zero selects all years.
+	Year int
+	// Match selects media by how well their metadata matched, empty selects all media.
+	Match MatchFilter
+	// AudioLanguage is the language of an audio track of selected media, regions and scripts are disregarded,
+	// language.Und selects all media. Audio tracks are only known to repositories with the CapabilityRemux capability,
+	// for media that has been probed (see Repository.Probe), other repositories select no media.
+	AudioLanguage language.Tag
+	// Filter is an additional predicate of selected media, such as their playback state, nil selects all media.
+	Filter func(m media.Media) bool
+
+	// Sort is the sort key, defaults to SortTitle, media with the same key are sorted by their ID.
+	Sort SortKey
+	// Descending is whether the sort order is reversed.
+	Descending bool
+
+	// Offset is the number of selected media to skip.
+	Offset int
+	// Limit is the maximum number of selected media to return, zero means no limit.
+	Limit int
+}
+
+// matches checks whether media is selected by the query filters, tags are the media's user-defined tags.
+func (q *Query) matches(m media.Media, tags []string) bool {
+	mm := m.Meta()
+	if q.Type != nil {
+		type_ := meta.TypeUnknown
+		if mm != nil {
+			type_ = mm.Type()
+		}
+		if type_ != *q.Type {
+			return false
+		}
+	}
+	if q.Year != 0 && (mm == nil || mm.ReleaseDate().Year() != q.Year) {
+		return false
+	}
+	if q.Genre != "" && !slices.ContainsFunc(Genres(mm), func(genre string) bool { return strings.EqualFold(genre, q.Genre) }) {
+		return false
+	}
+	if q.Tag != "" && !slices.Contains(tags, strings.ToLower(q.Tag)) {
+		return false
+	}
+	switch q.Match {
+	case MatchUnmatched:
+		if mm != nil {
+			return false
+		}
+	case MatchLowConfidence:
+		if c := meta.Confidence(mm); mm == nil || c == 0 || c >= meta.LowConfidence {
+			return false
+		}
+	}
+	if q.AudioLanguage != language.Und { // not known to the repository
+		return false
+	}
+	if q.Filter != nil && !q.Filter(m) {
+		return false
+	}
+
+	return true
+}
+
+// Genres returns the genre names of metadata, episodes take the genres of their series.
+func Genres(m meta.Metadata) []string {
+	switch mm := m.(type) {
+	case meta.EpisodeMetadata:
+		if series := mm.Series(); series != nil {
+			return series.Genres()
+		}
+	case meta.MovieOrSeriesMetadata:
+		return mm.Genres()
+	}
+
+	return nil
+}
+
+// apply selects, sorts and paginates media, returns the page and the number of all selected media.
+// added returns the time of media being added to the repository, tags returns their user-defined tags.
+func (q *Query) apply(items []media.Media, added func(id string) time.Time, tags func(id string) []string) ([]media.Media, int, error) {
+	var compare func(a, b media.Media) int
+	switch q.Sort {
+	case SortTitle, "":
+		compare = func(a, b media.Media) int {
+			return strings.Compare(sortTitle(a), sortTitle(b))
+		}
+	case SortReleaseDate:
+		compare = func(a, b media.Media) int {
+			return compareTime(releaseDate(a), releaseDate(b))
+		}
+	case SortAdded:
+		compare = func(a, b media.Media) int {
+			return compareTime(added(a.ID()), added(b.ID()))
+		}
+	default:
+		return nil, 0, fmt.Errorf("unknown sort key %s", q.Sort)
+	}
+
+	selected := make([]media.Media, 0, len(items))
+	for _, item := range items {
+		if q.matches(item, tags(item.ID())) {
+			selected = append(selected, item)
+		}
+	}
+
+	slices.SortFunc(selected, func(a, b media.Media) int {
+		res := compare(a, b)
+		if res == 0 {
+			res = strings.Compare(a.ID(), b.ID())
+		}
+		if q.Descending {
+			return -res
+		}
+
+		return res
+	})
+
+	total := len(selected)
+	if q.Offset > 0 {
+		if q.Offset >= total {
+			return []media.Media{}, total, nil
+		}
+
+		selected = selected[q.Offset:]
+	}
+	if q.Limit > 0 && q.Limit < len(selected) {
+		selected = selected[:q.Limit]
+	}
+
+	return selected, total, nil
+}
+
+func sortTitle(m media.Media) string {
+	if mm := m.Meta(); mm != nil {
+		return strings.ToLower(mm.Title())
+	}
+
+	return strings.ToLower(stem(filepath.Base(m.Path())))
+}
+
+func releaseDate(m media.Media) time.Time {
+	if mm := m.Meta(); mm != nil {
+		return mm.ReleaseDate()
+	}
+
+	return time.Time{}
+}
+
+func compareTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,341 @@
+package quota
+
+import (
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// warnRatio is the fraction of the quota past which the usage is near it.
+const warnRatio = 0.9
+
+// level is a state of the storage usage relative to the quota.
+type level int
+
+const (
+	levelOK   level = iota // below warnRatio
+	levelNear              // at or past warnRatio
+	levelOver              // past the quota
+)
+
+// quotaRepo is a wrapping repo.MutableRepository with a soft storage quota.
+// Media already present in the repository is kept when over quota, only new media is refused, including media found by scans.
+// A repo.EventQuotaWarning event is published when the usage gets near or over the quota, and when media is refused.
+type quotaRepo struct {
+	repo.MutableRepository
+
+	quota  int64
+	logger *zap.Logger
+
+	mu    sync.Mutex
+	sizes map[string]int64 // media ID -> file size
+	usage int64
+	level level
+	warn  bool // whether a warning is to be published once mu is released
+}
+
+// NewRepository creates a repository with a soft storage quota in bytes.
+func NewRepository(repo repo.MutableRepository, quota int64, logger *zap.Logger) (repo.MutableRepository, error) {
+	qr := &quotaRepo{
+		MutableRepository: repo,
+		quota:             quota,
+		logger:            logger,
+	}
+
+	sizes, err := qr.measure()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to measure repository usage")
+	}
+	for _, size := range sizes {
+		qr.usage += size
+	}
+	qr.sizes = sizes
+	qr.update()
+	qr.notify()
+
+	return qr, nil
+}
+
+// measure stats the media files of the repository, media whose file is missing are left out.
+// It doesn't need mu to be held.
+func (qr *quotaRepo) measure() (map[string]int64, error) {
+	var (
+		items = qr.MutableRepository.Items()
+		sizes = make(map[string]int64, len(items))
+	)
+	for _, item := range items {
+		fi, err := os.Stat(item.Path())
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue // removed in the meantime, not taking up space anymore
+			}
+
+			return nil, errors.Wrap(err, "failed to stat media")
+		}
+
+		sizes[item.ID()] = fi.Size()
+	}
+
+	return sizes, nil
+}
+
+// levelOf returns the level of a storage usage.
+func (qr *quotaRepo) levelOf(usage int64) level {
+	switch {
+	case usage > qr.quota:
+		return levelOver
+	case float64(usage) >= float64(qr.quota)*warnRatio:
+		return levelNear
+	default:
+		return levelOK
+	}
+}
+
+// update updates the level of the current usage, a warning is due if it rose, mu must be held or not shared yet.
+func (qr *quotaRepo) update() {
+	lvl := qr.levelOf(qr.usage)
+	if lvl > qr.level {
+		qr.warn = true
+		if qr.logger != nil {
+			msg := "repository storage usage near quota"
+			if lvl == levelOver {
+				msg = "repository storage quota exceeded"
+			}
+
+			qr.logger.Warn(
+				msg,
+				zap.String("repo", qr.ID()),
+				zap.String("repo_path", qr.Path()),
+				zap.Int64("usage", qr.usage),
+				zap.Int64("quota", qr.quota),
+			)
+		}
+	}
+
+	qr.level = lvl
+}
+
+// notify publishes a due warning, mu mustn't be held, repository events are published outside of locks.
+func (qr *quotaRepo) notify() {
+	qr.mu.Lock()
+	warn := qr.warn
+	qr.warn = false
+	qr.mu.Unlock()
+
+	if warn {
+		qr.Events().Publish(repo.Event{Type: repo.EventQuotaWarning, RepoID: qr.ID()})
+	}
+}
+
+// check checks whether a file fits into the quota, mu must be held.
+func (qr *quotaRepo) check(path string) (int64, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to stat file")
+	}
+
+	size := fi.Size()
+	if err := qr.fits(path, size); err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// fits checks whether a file of a size fits into the quota, mu must be held.
+func (qr *quotaRepo) fits(path string, size int64) error {
+	if qr.usage+size <= qr.quota {
+		return nil
+	}
+
+	qr.warn = true
+	if qr.logger != nil {
+		qr.logger.Warn(
+			"repository storage quota exceeded, refusing media",
+			zap.String("repo", qr.ID()),
+			zap.String("repo_path", qr.Path()),
+			zap.String("path", path),
+			zap.Int64("size", size),
+			zap.Int64("usage", qr.usage),
+			zap.Int64("quota", qr.quota),
+		)
+	}
+
+	return &repo.ErrQuotaExceeded{
+		Path:  path,
+		Repo:  qr.ID(),
+		Size:  size,
+		Usage: qr.usage,
+		Quota: qr.quota,
+	}
+}
+
+// track counts the file size of media in the usage, mu must be held.
+func (qr *quotaRepo) track(id string, size int64) {
+	qr.usage += size - qr.sizes[id]
+	qr.sizes[id] = size
+	qr.update()
+}
+
+// untrack stops counting the file size of media in the usage, mu must be held.
+func (qr *quotaRepo) untrack(id string) {
+	qr.usage -= qr.sizes[id]
+	delete(qr.sizes, id)
+	qr.update()
+}
+
+// Scan scans the repository and measures its usage again, without holding mu while doing so.
+// Media found by the scan is checked against the quota like added media, media not fitting is removed again.
+func (qr *quotaRepo) Scan(mode repo.ScanMode) error {
+	if err := qr.MutableRepository.Scan(mode); err != nil {
+		return err
+	}
+
+	sizes, err := qr.measure()
+	if err != nil {
+		return errors.Wrap(err, "failed to measure repository usage")
+	}
+
+	refused := qr.reconcile(sizes)
+	qr.notify()
+	for _, m := range refused {
+		if err := qr.MutableRepository.Remove(m); err != nil {
+			return errors.Wrap(err, "failed to remove refused media")
+		}
+	}
+
+	return nil
+}
+
+// reconcile swaps in measured file sizes, returning the newly found media not fitting into the quota.
+func (qr *quotaRepo) reconcile(sizes map[string]int64) []media.Media {
+	qr.mu.Lock()
+	defer qr.mu.Unlock()
+
+	var (
+		usage int64
+		found []media.Media
+	)
+	for id, size := range sizes {
+		m := qr.MutableRepository.Get(id)
+		if m == nil { // removed in the meantime
+			delete(sizes, id)
+			continue
+		}
+		if _, ok := qr.sizes[id]; !ok {
+			found = append(found, m)
+			continue
+		}
+
+		usage += size
+	}
+	for id, size := range qr.sizes {
+		if _, ok := sizes[id]; !ok && qr.MutableRepository.Get(id) != nil { // added in the meantime
+			sizes[id] = size
+			usage += size
+		}
+	}
+
+	qr.sizes, qr.usage = sizes, usage
+
+	// in a stable order, so that the same media is refused on every scan
+	slices.SortFunc(found, func(a, b media.Media) int {
+		return strings.Compare(a.Path(), b.Path())
+	})
+
+	var refused []media.Media
+	for _, m := range found {
+		size := sizes[m.ID()]
+		delete(sizes, m.ID())
+		if err := qr.fits(m.Path(), size); err != nil {
+			refused = append(refused, m)
+			continue
+		}
+
+		sizes[m.ID()] = size
+		qr.usage += size
+	}
+
+	qr.update()
+	return refused
+}
+
+func (qr *quotaRepo) Add(m media.Media) error {
+	defer qr.notify() // after unlocking
+	qr.mu.Lock()
+	defer qr.mu.Unlock()
+
+	size, err := qr.check(m.Path())
+	if err != nil {
+		return err
+	}
+	if err := qr.MutableRepository.Add(m); err != nil {
+		return err
+	}
+
+	qr.track(m.ID(), size)
+	return nil
+}
+
+func (qr *quotaRepo) AddPath(path string) error {
+	if media.SubtitleCodec(filepath.Ext(path)) != "" { // sidecar subtitles are not media, don't count them
+		return qr.MutableRepository.AddPath(path)
+	}
+
+	defer qr.notify() // after unlocking
+	qr.mu.Lock()
+	defer qr.mu.Unlock()
+
+	size, err := qr.check(path)
+	if err != nil {
+		return err
+	}
+	if err := qr.MutableRepository.AddPath(path); err != nil {
+		return err
+	}
+
+	if m := qr.MutableRepository.Find(path); m != nil {
+		qr.track(m.ID(), size)
+	}
+	return nil
+}
+
+func (qr *quotaRepo) Remove(m media.Media) error {
+	defer qr.notify() // after unlocking
+	qr.mu.Lock()
+	defer qr.mu.Unlock()
+
+	if err := qr.MutableRepository.Remove(m); err != nil {
+		return err
+	}
+
+	qr.untrack(m.ID())
+	return nil
+}
+
+func (qr *quotaRepo) RemovePath(path string) error {
+	defer qr.notify() // after unlocking
+	qr.mu.Lock()
+	defer qr.mu.Unlock()
+
+	m := qr.MutableRepository.Find(path)
+	if err := qr.MutableRepository.RemovePath(path); err != nil {
+		return err
+	}
+
+	if m != nil {
+		qr.untrack(m.ID())
+	}
+	return nil
+}
+
+func (qr *quotaRepo) Mutable() repo.MutableRepository {
+	return qr
+}
@@ -0,0 +1,166 @@
+package quota
+
+import (
+	"bytes"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// mkvHeader is a Matroska EBML header, for the files to be recognized as media by scans.
+var mkvHeader = []byte{0x1a, 0x45, 0xdf, 0xa3, 0x8b, 0x42, 0x82, 0x88, 'm', 'a', 't', 'r', 'o', 's', 'k', 'a'}
+
+// writeFile writes a Matroska file of a size into a directory, returning its path.
+func writeFile(t *testing.T, dir, name string, size int) string {
+	path := filepath.Join(dir, name)
+	data := append(append([]byte(nil), mkvHeader...), bytes.Repeat([]byte{0}, size-len(mkvHeader))...)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// newRepo creates a repository with media of the sizes, keyed by their IDs.
+func newRepo(t *testing.T, sizes map[string]int) (repo.MutableRepository, string) {
+	root := t.TempDir()
+	r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for id, size := range sizes {
+		if err := r.Add(media.NewMedia(id, writeFile(t, root, id+".mkv", size), nil, media.FormatMKV)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return r, root
+}
+
+// countWarnings counts the quota warnings published by a repository.
+func countWarnings(r repo.Repository) *int {
+	var warnings int
+	r.Events().Subscribe(func(e repo.Event) {
+		if e.Type == repo.EventQuotaWarning {
+			warnings++
+		}
+	})
+
+	return &warnings
+}
+
+func TestQuotaRepo_Threshold(t *testing.T) {
+	r, root := newRepo(t, map[string]int{"a": 50})
+	warnings := countWarnings(r)
+
+	qr, err := NewRepository(r, 100, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lvl := qr.(*quotaRepo).level; lvl != levelOK {
+		t.Errorf("expected usage below the threshold, got level %d", lvl)
+	}
+
+	if err := qr.Add(media.NewMedia("b", writeFile(t, root, "b.mkv", 40), nil, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+	if lvl := qr.(*quotaRepo).level; lvl != levelNear {
+		t.Errorf("expected usage past the threshold, got level %d", lvl)
+	}
+	if *warnings != 1 {
+		t.Errorf("expected a warning when reaching the threshold, got %d", *warnings)
+	}
+
+	if err := qr.Remove(qr.Get("b")); err != nil {
+		t.Fatal(err)
+	}
+	if lvl := qr.(*quotaRepo).level; lvl != levelOK {
+		t.Errorf("expected usage below the threshold after removing media, got level %d", lvl)
+	}
+	if *warnings != 1 {
+		t.Errorf("expected no warning going below the threshold, got %d", *warnings)
+	}
+}
+
+func TestQuotaRepo_Refuse(t *testing.T) {
+	r, root := newRepo(t, map[string]int{"a": 50, "b": 30})
+	warnings := countWarnings(r)
+
+	qr, err := NewRepository(r, 100, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := media.NewMedia("c", writeFile(t, root, "c.mkv", 30), nil, media.FormatMKV)
+	err = qr.Add(c)
+	eqe, ok := err.(*repo.ErrQuotaExceeded)
+	if !ok {
+		t.Fatalf("expected quota exceeded error, got %v", err)
+	}
+	if eqe.Size != 30 || eqe.Usage != 80 || eqe.Quota != 100 {
+		t.Errorf("unexpected quota exceeded error %+v", eqe)
+	}
+	if qr.Get("c") != nil {
+		t.Error("expected refused media not to be added")
+	}
+	if *warnings != 1 {
+		t.Errorf("expected a warning when refusing media, got %d", *warnings)
+	}
+
+	if err := qr.Remove(qr.Get("b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := qr.Add(c); err != nil {
+		t.Fatalf("expected media to fit after removing other media, got %v", err)
+	}
+	if usage := qr.(*quotaRepo).usage; usage != 80 {
+		t.Errorf("expected usage of 80, got %d", usage)
+	}
+}
+
+func TestQuotaRepo_Measure(t *testing.T) {
+	r, root := newRepo(t, map[string]int{"a": 50, "b": 30})
+	if err := os.Remove(filepath.Join(root, "b.mkv")); err != nil {
+		t.Fatal(err)
+	}
+
+	qr, err := NewRepository(r, 100, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage := qr.(*quotaRepo).usage; usage != 50 {
+		t.Errorf("expected usage of 50 without the missing file, got %d", usage)
+	}
+}
+
+func TestQuotaRepo_Scan(t *testing.T) {
+	r, root := newRepo(t, map[string]int{"a": 50})
+	warnings := countWarnings(r)
+
+	qr, err := NewRepository(r, 100, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := writeFile(t, root, "b.mkv", 30)
+	c := writeFile(t, root, "c.mkv", 30)
+	if err := qr.Scan(repo.ScanIncremental); err != nil {
+		t.Fatal(err)
+	}
+	if qr.Find(b) == nil {
+		t.Error("expected found media fitting into the quota to be added")
+	}
+	if qr.Find(c) != nil {
+		t.Error("expected found media over the quota to be refused")
+	}
+	if usage := qr.(*quotaRepo).usage; usage != 80 {
+		t.Errorf("expected usage of 80 after scanning, got %d", usage)
+	}
+	if *warnings != 1 {
+		t.Errorf("expected a warning when refusing found media, got %d", *warnings)
+	}
+}
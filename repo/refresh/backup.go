@@ -0,0 +1,16 @@
+package refresh
+
+import (
+	"github.com/katana-project/katana/backup"
+	"github.com/katana-project/katana/config"
+)
+
+func init() {
+	backup.Register("refresh.json", func(cfg *config.Config) string {
+		if cfg.Refresh == nil {
+			return ""
+		}
+
+		return cfg.Refresh.Path
+	})
+}
@@ -0,0 +1,33 @@
+package refresh
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+)
+
+// ErrRunning is an error about a repository's metadata being refreshed already.
+type ErrRunning struct {
+	// Repo is the repository ID.
+	Repo string
+}
+
+// Error returns the string representation of the error.
+func (er *ErrRunning) Error() string {
+	return fmt.Sprintf("metadata of repository %s is being refreshed already", er.Repo)
+}
+
+// Code returns the error code (errors.CodeNotReady).
+func (er *ErrRunning) Code() errors.Code {
+	return errors.CodeNotReady
+}
+
+// ErrNoMetadata is an error about the metadata sources not finding any metadata of media.
+type ErrNoMetadata struct {
+	// ID is the media ID.
+	ID string
+}
+
+// Error returns the string representation of the error.
+func (enm *ErrNoMetadata) Error() string {
+	return fmt.Sprintf("no metadata found for media %s", enm.ID)
+}
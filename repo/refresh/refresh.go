@@ -0,0 +1,311 @@
+package refresh
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// saveInterval is the minimum period between saves of the refresh times during a long refresh.
+const saveInterval = time.Minute
+
+// entry is a JSON-serializable refresh state of media.
+type entry struct {
+	Refreshed time.Time   `json:"refreshed"`       // the last time the metadata was resolved, or the media was first seen
+	Query     *meta.Query `json:"query,omitempty"` // the query of a manual re-match, nil if the metadata is resolved from the file
+}
+
+// Refresher re-resolves the metadata of media older than a maximum age, because ratings and artwork change over time.
+// The times of refreshes and the queries of manual re-matches are persisted to a JSON file, after refreshes and on Close.
+type Refresher struct {
+	path     string
+	maxAge   time.Duration
+	jitter   time.Duration
+	interval time.Duration // minimum period between refreshes of a repository's media, zero if they're not limited
+	logger   *zap.Logger
+
+	mu       sync.Mutex
+	repos    map[string]map[string]*entry // repository ID -> media ID -> entry
+	dirty    bool
+	lastSave time.Time
+
+	running sync.Map // repository ID -> struct{}, refreshes of a repository are exclusive
+}
+
+// NewRefresher creates a metadata refresher persisted to a JSON file.
+// Metadata is refreshed once it's older than maxAge plus a per-media jitter of up to jitter, so that media added together
+// aren't all refreshed at once; rate is the maximum number of refreshes per second of a repository, non-positive doesn't limit it.
+func NewRefresher(path string, maxAge, jitter time.Duration, rate float64, logger *zap.Logger) (*Refresher, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rf := &Refresher{
+		path:   absPath,
+		maxAge: maxAge,
+		jitter: jitter,
+		logger: logger,
+		repos:  make(map[string]map[string]*entry),
+	}
+	if rate > 0 {
+		rf.interval = time.Duration(float64(time.Second) / rate)
+	}
+	if err := rf.load(); err != nil {
+		return nil, err
+	}
+
+	return rf, nil
+}
+
+func (rf *Refresher) load() error {
+	b, err := os.ReadFile(rf.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return errors.Wrap(err, "failed to read metadata refresh times")
+	}
+
+	if err := json.Unmarshal(b, &rf.repos); err != nil {
+		return errors.Wrap(err, "failed to unmarshal metadata refresh times")
+	}
+	if rf.repos == nil {
+		rf.repos = make(map[string]map[string]*entry)
+	}
+
+	return nil
+}
+
+// save saves the refresh times if they changed since the last save, mu must be held.
+func (rf *Refresher) save() error {
+	if !rf.dirty {
+		return nil
+	}
+
+	b, err := json.Marshal(rf.repos)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal metadata refresh times")
+	}
+	if err := os.MkdirAll(filepath.Dir(rf.path), 0); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	tmp := rf.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return errors.Wrap(err, "failed to write metadata refresh times")
+	}
+	if err := os.Rename(tmp, rf.path); err != nil {
+		return errors.Wrap(err, "failed to replace metadata refresh times")
+	}
+
+	rf.dirty = false
+	rf.lastSave = time.Now()
+	return nil
+}
+
+// entry returns the entry of media, it's made if there's none, i.e. the media is seen for the first time, mu must be held.
+func (rf *Refresher) entry(repoId, mediaId string) *entry {
+	entries, ok := rf.repos[repoId]
+	if !ok {
+		entries = make(map[string]*entry)
+		rf.repos[repoId] = entries
+	}
+
+	e, ok := entries[mediaId]
+	if !ok { // its metadata was resolved when it was added
+		e = &entry{Refreshed: time.Now()}
+		entries[mediaId] = e
+		rf.dirty = true
+	}
+
+	return e
+}
+
+// due checks whether the metadata of media is due to be refreshed, mu must be held.
+func (rf *Refresher) due(repoId, mediaId string, now time.Time) bool {
+	var offset time.Duration
+	if rf.jitter > 0 { // stable, so that the media isn't pushed back with every check
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(repoId + "/" + mediaId))
+		offset = time.Duration(h.Sum64() % uint64(rf.jitter))
+	}
+
+	return now.Sub(rf.entry(repoId, mediaId).Refreshed) >= rf.maxAge+offset
+}
+
+// Due returns the number of a repository's media due to have their metadata refreshed, forgetting those of removed media.
+// Locked media are never due.
+func (rf *Refresher) Due(r repo.Repository) int {
+	items := r.Items()
+	ids := make(map[string]struct{}, len(items))
+
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	var (
+		now = time.Now()
+		n   int
+	)
+	for _, item := range items {
+		ids[item.ID()] = struct{}{}
+		if !r.Locked(item.ID()) && rf.due(r.ID(), item.ID(), now) {
+			n++
+		}
+	}
+	for id := range rf.repos[r.ID()] {
+		if _, ok := ids[id]; !ok {
+			delete(rf.repos[r.ID()], id)
+			rf.dirty = true
+		}
+	}
+
+	return n
+}
+
+// Matched records the query of a manual re-match of media, so that its metadata is refreshed with the query rather than from its file.
+func (rf *Refresher) Matched(repoId, mediaId string, query *meta.Query) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	e := rf.entry(repoId, mediaId)
+	e.Refreshed, e.Query = time.Now(), query
+	rf.dirty = true
+}
+
+// Refresh refreshes the metadata of media now, regardless of its age, and returns the updated media.
+// Media re-matched manually are resolved with their query (see Matched), the rest from their file.
+// ErrNoMetadata is returned if the metadata sources don't find any.
+func (rf *Refresher) Refresh(mr repo.MutableRepository, m media.Media) (media.Media, error) {
+	rf.mu.Lock()
+	query := rf.entry(mr.ID(), m.ID()).Query
+	rf.mu.Unlock()
+
+	m, err := Media(mr, m, query)
+	if err != nil {
+		return nil, err
+	}
+
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	rf.entry(mr.ID(), m.ID()).Refreshed = time.Now()
+	rf.dirty = true
+	if time.Since(rf.lastSave) >= saveInterval {
+		if err := rf.save(); err != nil && rf.logger != nil {
+			rf.logger.Error("failed to save metadata refresh times", zap.String("path", rf.path), zap.Error(err))
+		}
+	}
+
+	return m, nil
+}
+
+// RefreshDue refreshes the metadata of a repository's media due to be refreshed, spaced out by the rate limit, locked media are skipped.
+// Failures of single media are logged, they're retried in the next refresh; progress is reported in refreshed media.
+// ErrRunning is returned if the repository is being refreshed already.
+func (rf *Refresher) RefreshDue(ctx context.Context, mr repo.MutableRepository) (err error) {
+	repoId := mr.ID()
+	if _, running := rf.running.LoadOrStore(repoId, struct{}{}); running {
+		return &ErrRunning{Repo: repoId}
+	}
+	defer func() {
+		rf.running.Delete(repoId)
+
+		rf.mu.Lock()
+		defer rf.mu.Unlock()
+
+		err = multierr.Append(err, rf.save())
+	}()
+
+	var (
+		now     = time.Now()
+		pending []media.Media
+	)
+	rf.mu.Lock()
+	for _, item := range mr.Items() {
+		if !mr.Locked(item.ID()) && rf.due(repoId, item.ID(), now) {
+			pending = append(pending, item)
+		}
+	}
+	rf.mu.Unlock()
+
+	var (
+		logger   = repo.Logger(ctx, rf.logger)
+		progress = repo.Progress{Total: int64(len(pending))}
+	)
+	repo.ReportProgress(ctx, progress)
+
+	for i, item := range pending {
+		if i > 0 && rf.interval > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(rf.interval):
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if _, err := rf.Refresh(mr, item); err != nil && logger != nil { // removed in the meantime, source unavailable, ...
+			logger.Warn("failed to refresh media metadata", zap.String("repo", repoId), zap.String("path", item.Path()), zap.Error(err))
+		}
+
+		progress.Processed++
+		repo.ReportProgress(ctx, progress)
+	}
+
+	return nil
+}
+
+// Close saves the refresh times a final time.
+func (rf *Refresher) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	return rf.save()
+}
+
+// Media resolves the metadata of media again with a repository's metadata sources and updates the media.
+// The metadata is resolved with the query if it's not nil, from the media's file otherwise.
+// ErrNoMetadata is returned if the metadata sources don't find any, repo.ErrLocked if the media is locked.
+func Media(mr repo.MutableRepository, m media.Media, query *meta.Query) (media.Media, error) {
+	if mr.Locked(m.ID()) {
+		return nil, &repo.ErrLocked{ID: m.ID(), Repo: mr.ID()}
+	}
+
+	var (
+		mm  meta.Metadata
+		err error
+	)
+	if query != nil {
+		mm, err = mr.Source().FromQuery(query)
+	} else {
+		mm, err = mr.Source().FromFile(m.Path())
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve metadata")
+	}
+	if mm == nil {
+		return nil, &ErrNoMetadata{ID: m.ID()}
+	}
+
+	m = media.NewMedia(m.ID(), m.Path(), mm, m.Format())
+	if err := mr.Update(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
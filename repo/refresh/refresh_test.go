@@ -0,0 +1,115 @@
+package refresh
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRefresher_RefreshDue(t *testing.T) {
+	root := t.TempDir()
+	r, err := repo.NewRepository("test", "Test", root, meta.NewLiteralSource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"movie", "rematched"} {
+		path := filepath.Join(root, id+".mkv")
+		if err := os.WriteFile(path, []byte(id), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Add(media.NewMedia(id, path, meta.NewMetadata(meta.TypeMovie, "Stale", "", "", time.Time{}, 0, nil), media.FormatMKV)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rfPath := filepath.Join(t.TempDir(), "refresh.json")
+	rf, err := NewRefresher(rfPath, time.Hour, 0, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := rf.Due(r); n != 0 {
+		t.Errorf("expected no media first seen to be due, got %d", n)
+	}
+
+	rf.Matched("test", "rematched", &meta.Query{Query: "Matched", Type: meta.TypeMovie, Season: -1, Episode: -1})
+	for _, e := range rf.repos["test"] { // age the metadata
+		e.Refreshed = e.Refreshed.Add(-2 * time.Hour)
+	}
+	if n := rf.Due(r); n != 2 {
+		t.Errorf("expected 2 media due, got %d", n)
+	}
+
+	if err := rf.RefreshDue(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	if n := rf.Due(r); n != 0 {
+		t.Errorf("expected no media due after refreshing, got %d", n)
+	}
+	if title := r.Get("movie").Meta().Title(); title == "Stale" {
+		t.Error("expected metadata to be resolved from the file again")
+	}
+	if title := r.Get("rematched").Meta().Title(); title != "Matched" {
+		t.Errorf("expected metadata to be resolved with the re-match query, got title %s", title)
+	}
+	if err := rf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rf, err = NewRefresher(rfPath, time.Hour, 0, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := rf.repos["test"]["rematched"].Query; q == nil || q.Query != "Matched" {
+		t.Errorf("expected persisted re-match query, got %+v", q)
+	}
+}
+
+func TestRefresher_Locked(t *testing.T) {
+	root := t.TempDir()
+	r, err := repo.NewRepository("test", "Test", root, meta.NewLiteralSource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(root, "movie.mkv")
+	if err := os.WriteFile(path, []byte("movie"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(media.NewMedia("movie", path, meta.NewMetadata(meta.TypeMovie, "Curated", "", "", time.Time{}, 0, nil), media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SetLocked("movie", true); err != nil {
+		t.Fatal(err)
+	}
+
+	rf, err := NewRefresher(filepath.Join(t.TempDir(), "refresh.json"), time.Hour, 0, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rf.Due(r)
+	for _, e := range rf.repos["test"] { // age the metadata
+		e.Refreshed = e.Refreshed.Add(-2 * time.Hour)
+	}
+	if n := rf.Due(r); n != 0 {
+		t.Errorf("expected no locked media due, got %d", n)
+	}
+
+	if err := rf.RefreshDue(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	if title := r.Get("movie").Meta().Title(); title != "Curated" {
+		t.Errorf("expected locked metadata to be kept, got title %s", title)
+	}
+
+	var locked *repo.ErrLocked
+	if _, err := rf.Refresh(r, r.Get("movie")); !errors.As(err, &locked) {
+		t.Errorf("expected lock error, got %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+package replica
+
+import (
+	"github.com/katana-project/katana/backup"
+	"github.com/katana-project/katana/config"
+)
+
+func init() {
+	backup.RegisterRepo("replica.json", (*config.Repo).ReplicaPath)
+}
@@ -0,0 +1,82 @@
+package replica
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"net/http"
+)
+
+// ErrRunning is an error about a repository being replicated already.
+type ErrRunning struct {
+	// Repo is the repository ID.
+	Repo string
+}
+
+// Error returns the string representation of the error.
+func (er *ErrRunning) Error() string {
+	return fmt.Sprintf("repository %s is being replicated already", er.Repo)
+}
+
+// Code returns the error code (errors.CodeNotReady).
+func (er *ErrRunning) Code() errors.Code {
+	return errors.CodeNotReady
+}
+
+// ErrStatus is an error about the origin server responding with an unexpected status.
+type ErrStatus struct {
+	// URL is the requested URL.
+	URL string
+	// Status is the HTTP status code of the response.
+	Status int
+	// Description is the error description of the response, can be empty.
+	Description string
+}
+
+// Error returns the string representation of the error.
+func (es *ErrStatus) Error() string {
+	if es.Description == "" {
+		return fmt.Sprintf("unexpected status %d from %s", es.Status, es.URL)
+	}
+
+	return fmt.Sprintf("unexpected status %d from %s: %s", es.Status, es.URL, es.Description)
+}
+
+// Code returns the error code (errors.CodeUnauthorized if the origin refused the token, errors.CodeUnavailable otherwise).
+func (es *ErrStatus) Code() errors.Code {
+	if es.Status == http.StatusUnauthorized {
+		return errors.CodeUnauthorized
+	}
+
+	return errors.CodeUnavailable
+}
+
+// ErrSizeMismatch is an error about a downloaded media file not having the size listed in the manifest,
+// such as when it changed at the origin during the download.
+type ErrSizeMismatch struct {
+	// ID is the media ID.
+	ID string
+	// Expected is the size listed in the manifest.
+	Expected int64
+	// Actual is the size of the downloaded file.
+	Actual int64
+}
+
+// Error returns the string representation of the error.
+func (esm *ErrSizeMismatch) Error() string {
+	return fmt.Sprintf("downloaded file of media %s has %d bytes, expected %d", esm.ID, esm.Actual, esm.Expected)
+}
+
+// ErrIncomplete is an error about media that failed to be replicated, they're retried by the next replication.
+type ErrIncomplete struct {
+	// Repo is the repository ID.
+	Repo string
+	// Failed is the number of media that failed to be replicated.
+	Failed int
+	// Total is the number of media in the manifest.
+	Total int
+}
+
+// Error returns the string representation of the error.
+func (ei *ErrIncomplete) Error() string {
+	return fmt.Sprintf("failed to replicate %d of %d media of repository %s", ei.Failed, ei.Total, ei.Repo)
+}
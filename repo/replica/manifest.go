@@ -0,0 +1,85 @@
+package replica
+
+import (
+	"encoding/json"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"path/filepath"
+	"time"
+)
+
+// Item is media of a replication manifest.
+type Item struct {
+	// ID is the media ID, it's kept by replicas.
+	ID string `json:"id"`
+	// Path is the slash-separated path of the media file, relative to the repository directory containing it.
+	Path string `json:"path"`
+	// Size is the size of the media file in bytes, zero if it's not known yet.
+	Size int64 `json:"size"`
+	// ModTime is the modification time of the media file, zero if it's not known yet.
+	ModTime time.Time `json:"mod_time"`
+	// Digest is the fingerprint digest of the media (repo.FingerprintItem).
+	Digest string `json:"digest"`
+	// Media is the JSON representation of the media (media.BasicMedia) without its path.
+	Media json.RawMessage `json:"media"`
+	// Tags are the user-defined tags of the media.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Manifest is a description of a repository's contents, the origin of replication.
+type Manifest struct {
+	// Repo is the repository ID.
+	Repo string `json:"repo"`
+	// Hash is the fingerprint hash of the repository (repo.Fingerprint).
+	Hash string `json:"hash"`
+	// Items are the media of the repository.
+	Items []Item `json:"items"`
+}
+
+// MakeManifest makes the replication manifest of a repository's current contents, no media files are read.
+func MakeManifest(r repo.Repository) (*Manifest, error) {
+	var (
+		items   = r.Items()
+		roots   = r.Roots()
+		digests = make(map[string]string, len(items))
+		m       = &Manifest{Repo: r.ID(), Items: make([]Item, 0, len(items))}
+	)
+	for _, item := range items {
+		var (
+			id   = item.ID()
+			stat = r.FileStat(id)
+			tags = r.Tags(id)
+		)
+
+		digest, err := repo.FingerprintItem(item, stat.Size, tags)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fingerprint media %s", id)
+		}
+
+		media0, err := json.Marshal(media.NewMedia(id, "", item.Meta(), item.Format()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal media %s", id)
+		}
+
+		relPath, err := repo.RelPath(roots, item.Path())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to relativize path of media %s", id)
+		}
+		_, relPath = repo.SplitRoot(roots, relPath) // replicas may be laid out over other directories
+
+		digests[id] = digest
+		m.Items = append(m.Items, Item{
+			ID:      id,
+			Path:    filepath.ToSlash(relPath),
+			Size:    stat.Size,
+			ModTime: stat.ModTime,
+			Digest:  digest,
+			Media:   media0,
+			Tags:    tags,
+		})
+	}
+
+	m.Hash = repo.NewFingerprint(digests).Hash
+	return m, nil
+}
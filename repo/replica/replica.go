@@ -0,0 +1,563 @@
+package replica
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Conflict is a rule for resolving media changed both in a replica and at its origin since the last replication.
+type Conflict string
+
+const (
+	// ConflictOrigin replaces the local changes with the ones of the origin.
+	ConflictOrigin Conflict = "origin"
+	// ConflictLocal keeps the local changes, the media is replicated again once only the origin changes it.
+	ConflictLocal Conflict = "local"
+)
+
+// Options is the configuration of a Replicator.
+type Options struct {
+	// URL is the base URL of the origin server, such as "https://katana.example.com".
+	URL string
+	// Token is a static API token of the origin server, can be empty if it doesn't require authentication.
+	Token string
+	// Repo is the ID of the origin repository.
+	Repo string
+	// Files is whether media files are downloaded too, only the metadata and tags of media present locally are replicated otherwise,
+	// such as when the files are copied by other means.
+	Files bool
+	// Conflict is the rule for media changed both locally and at the origin, defaults to ConflictOrigin.
+	Conflict Conflict
+	// Prune is whether media removed from the origin are removed locally too, along with their files if Files is set.
+	Prune bool
+	// Interval is the period between replications, zero replicates only when requested.
+	Interval time.Duration
+}
+
+// state is a JSON-serializable replication state of media, the digests of the last replication.
+type state struct {
+	Origin string `json:"origin"` // the digest of the origin's media
+	Local  string `json:"local"`  // the digest of the local media after replicating it
+}
+
+// Replicator mirrors a repository of another server over its API into a local repository, for off-site backups.
+// Media are compared with their digests as of the last replication, so that changes on either side are told apart;
+// the digests are persisted to a JSON file after replications, they're only kept in memory if it has no path.
+// Downloads are written to dot-prefixed partial files next to their destination and resumed by later replications if interrupted.
+type Replicator struct {
+	opts   Options
+	path   string
+	client *http.Client
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	states map[string]*state // media ID -> state
+	dirty  bool
+	last   time.Time // the end of the last replication, or the creation of the replicator
+
+	running atomic.Bool
+}
+
+// NewReplicator creates a replicator with its state persisted to a JSON file, path can be empty.
+// The client makes the requests to the origin server, http.DefaultClient is used if it's nil.
+func NewReplicator(path string, opts *Options, client *http.Client, logger *zap.Logger) (*Replicator, error) {
+	if opts.URL == "" || opts.Repo == "" {
+		return nil, errors.New("missing origin URL or repository ID")
+	}
+	if _, err := url.Parse(opts.URL); err != nil {
+		return nil, errors.Wrap(err, "failed to parse origin URL")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	rp := &Replicator{
+		opts:   *opts,
+		client: client,
+		logger: logger,
+		states: make(map[string]*state),
+		last:   time.Now(),
+	}
+	rp.opts.URL = strings.TrimSuffix(opts.URL, "/")
+	switch rp.opts.Conflict {
+	case "":
+		rp.opts.Conflict = ConflictOrigin
+	case ConflictOrigin, ConflictLocal:
+	default:
+		return nil, fmt.Errorf("unknown conflict rule %s", opts.Conflict)
+	}
+	if path != "" {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+
+		rp.path = absPath
+		if err := rp.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	return rp, nil
+}
+
+func (rp *Replicator) load() error {
+	b, err := os.ReadFile(rp.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return errors.Wrap(err, "failed to read replication state")
+	}
+
+	if err := json.Unmarshal(b, &rp.states); err != nil {
+		return errors.Wrap(err, "failed to unmarshal replication state")
+	}
+	if rp.states == nil {
+		rp.states = make(map[string]*state)
+	}
+
+	return nil
+}
+
+// save saves the replication state if it changed since the last save, mu must be held.
+func (rp *Replicator) save() error {
+	if !rp.dirty || rp.path == "" {
+		return nil
+	}
+
+	b, err := json.Marshal(rp.states)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal replication state")
+	}
+	if err := os.MkdirAll(filepath.Dir(rp.path), 0); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	tmp := rp.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return errors.Wrap(err, "failed to write replication state")
+	}
+	if err := os.Rename(tmp, rp.path); err != nil {
+		return errors.Wrap(err, "failed to replace replication state")
+	}
+
+	rp.dirty = false
+	return nil
+}
+
+// Due checks whether the interval since the last replication has passed, never if replicating only when requested.
+func (rp *Replicator) Due() bool {
+	if rp.opts.Interval <= 0 || rp.running.Load() {
+		return false
+	}
+
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	return time.Since(rp.last) >= rp.opts.Interval
+}
+
+// Replicate mirrors the origin repository into a local repository, progress is reported in replicated media.
+// Failures of single media are logged and ErrIncomplete is returned at the end, they're retried by the next replication.
+// ErrRunning is returned if the repository is being replicated already.
+func (rp *Replicator) Replicate(ctx context.Context, mr repo.MutableRepository) (err error) {
+	if !rp.running.CompareAndSwap(false, true) {
+		return &ErrRunning{Repo: mr.ID()}
+	}
+	defer func() {
+		rp.running.Store(false)
+
+		rp.mu.Lock()
+		defer rp.mu.Unlock()
+
+		rp.last = time.Now()
+		err = multierr.Append(err, rp.save())
+	}()
+
+	manifest, err := rp.fetchManifest(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		logger   = repo.Logger(ctx, rp.logger)
+		progress = repo.Progress{Total: int64(len(manifest.Items))}
+		seen     = make(map[string]struct{}, len(manifest.Items))
+		failed   int
+	)
+	repo.ReportProgress(ctx, progress)
+
+	for i := range manifest.Items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		item := &manifest.Items[i]
+		seen[item.ID] = struct{}{}
+		if err := rp.replicate(ctx, mr, item); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			failed++
+			if logger != nil {
+				logger.Warn("failed to replicate media", zap.String("repo", mr.ID()), zap.String("id", item.ID), zap.Error(err))
+			}
+		}
+
+		progress.Processed++
+		repo.ReportProgress(ctx, progress)
+	}
+	if len(manifest.Items) > 0 { // an empty origin is more likely a failure of it than a deliberate wipe
+		rp.prune(mr, seen, logger)
+	}
+
+	if failed > 0 {
+		return &ErrIncomplete{Repo: mr.ID(), Failed: failed, Total: len(manifest.Items)}
+	}
+	if logger != nil {
+		logger.Info("replicated repository", zap.String("repo", mr.ID()), zap.String("origin", rp.opts.URL), zap.Int("media", len(manifest.Items)))
+	}
+
+	return nil
+}
+
+// replicate replicates media of the manifest, if it wasn't changed only locally.
+func (rp *Replicator) replicate(ctx context.Context, mr repo.MutableRepository, item *Item) error {
+	var origin media.BasicMedia
+	if err := json.Unmarshal(item.Media, &origin); err != nil {
+		return errors.Wrap(err, "failed to unmarshal media")
+	}
+
+	rp.mu.Lock()
+	st := rp.states[item.ID]
+	rp.mu.Unlock()
+
+	var (
+		local        = mr.Get(item.ID)
+		localDigest  string // empty if the media isn't present locally
+		originDigest = item.Digest
+		err          error
+	)
+	if local != nil {
+		if localDigest, err = digest(mr, local); err != nil {
+			return err
+		}
+		if !rp.opts.Files { // the local file is compared by other means, only the index is
+			if originDigest, err = repo.FingerprintItem(&origin, mr.FileStat(item.ID).Size, item.Tags); err != nil {
+				return errors.Wrap(err, "failed to fingerprint media")
+			}
+		}
+	}
+
+	switch {
+	case localDigest == originDigest: // in sync, replicated by other means
+		rp.record(item.ID, &state{Origin: item.Digest, Local: localDigest})
+		return nil
+	case !rp.apply(st, localDigest, item.Digest):
+		return nil
+	}
+
+	if local == nil {
+		if !rp.opts.Files {
+			return nil // nothing to apply the metadata to
+		}
+
+		if local, err = rp.add(ctx, mr, item, &origin); err != nil {
+			return err
+		}
+	} else if local, err = rp.update(ctx, mr, item, local, &origin); err != nil {
+		return err
+	}
+
+	if err := mr.SetTags(item.ID, item.Tags); err != nil {
+		return errors.Wrap(err, "failed to set tags")
+	}
+	if localDigest, err = digest(mr, local); err != nil {
+		return err
+	}
+
+	rp.record(item.ID, &state{Origin: item.Digest, Local: localDigest})
+	return nil
+}
+
+// apply decides whether the origin's media replaces the local one, by what changed since the last replication.
+// Local digests are empty for media not present locally.
+func (rp *Replicator) apply(st *state, local, origin string) bool {
+	switch {
+	case st == nil: // not replicated yet, the local media predates replication
+		return local == "" || rp.opts.Conflict == ConflictOrigin
+	case local == st.Local: // changed only at the origin
+		return true
+	case origin == st.Origin: // changed only locally
+		return false
+	}
+
+	return rp.opts.Conflict == ConflictOrigin
+}
+
+// add downloads the file of media missing locally and adds it to the repository.
+func (rp *Replicator) add(ctx context.Context, mr repo.MutableRepository, item *Item, origin media.Media) (media.Media, error) {
+	relPath := filepath.FromSlash(item.Path)
+	if !filepath.IsLocal(relPath) {
+		return nil, fmt.Errorf("path %s leads out of the repository directory", item.Path)
+	}
+
+	path := filepath.Join(mr.Path(), relPath)
+	if mr.Ignored(path, false) {
+		return nil, fmt.Errorf("path %s is excluded from the repository", item.Path)
+	}
+	if m := mr.Find(path); m != nil { // added locally under another ID
+		return nil, &repo.ErrDuplicatePath{Path: path, Repo: mr.Path()}
+	}
+
+	if err := rp.download(ctx, item, path); err != nil {
+		return nil, err
+	}
+
+	m := media.NewMedia(item.ID, path, origin.Meta(), origin.Format())
+	err := mr.Add(m)
+	if err != nil {
+		var duplicatePath *repo.ErrDuplicatePath
+		if cur := mr.Find(path); errors.As(err, &duplicatePath) && cur != nil { // added by a filesystem watcher meanwhile, under an ID of its own
+			if err = mr.Remove(cur); err == nil {
+				err = mr.Add(m)
+			}
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to add media")
+	}
+
+	return m, nil
+}
+
+// update replaces the metadata of local media with the origin's, its file is downloaded again if it differs from the origin's.
+func (rp *Replicator) update(ctx context.Context, mr repo.MutableRepository, item *Item, local, origin media.Media) (media.Media, error) {
+	path := local.Path()
+	if rp.opts.Files {
+		fi, err := os.Stat(path)
+		if err != nil || fi.Size() != item.Size || (!item.ModTime.IsZero() && fi.ModTime().Unix() != item.ModTime.Unix()) {
+			if err := rp.download(ctx, item, path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	m := media.NewMedia(item.ID, path, origin.Meta(), origin.Format())
+	if err := mr.Update(m); err != nil {
+		return nil, errors.Wrap(err, "failed to update media")
+	}
+
+	return m, nil
+}
+
+// prune forgets media removed from the origin, they're removed locally too if pruning and not changed locally since.
+func (rp *Replicator) prune(mr repo.MutableRepository, seen map[string]struct{}, logger *zap.Logger) {
+	rp.mu.Lock()
+	removed := make(map[string]*state)
+	for id, st := range rp.states {
+		if _, ok := seen[id]; !ok {
+			removed[id] = st
+			delete(rp.states, id)
+			rp.dirty = true
+		}
+	}
+	rp.mu.Unlock()
+
+	if !rp.opts.Prune {
+		return
+	}
+	for id, st := range removed {
+		m := mr.Get(id)
+		if m == nil {
+			continue
+		}
+		if d, err := digest(mr, m); err != nil || d != st.Local {
+			continue // changed locally, it's local media now
+		}
+
+		err := mr.Remove(m)
+		if err == nil && rp.opts.Files {
+			if err = os.Remove(m.Path()); errors.Is(err, fs.ErrNotExist) {
+				err = nil
+			}
+		}
+		if err != nil && logger != nil {
+			logger.Warn("failed to prune media", zap.String("repo", mr.ID()), zap.String("id", id), zap.Error(err))
+		}
+	}
+}
+
+// record records the replication state of media.
+func (rp *Replicator) record(id string, st *state) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	if cur, ok := rp.states[id]; !ok || *cur != *st {
+		rp.states[id] = st
+		rp.dirty = true
+	}
+}
+
+// digest makes the fingerprint digest of local media.
+func digest(mr repo.MutableRepository, m media.Media) (string, error) {
+	d, err := repo.FingerprintItem(m, mr.FileStat(m.ID()).Size, mr.Tags(m.ID()))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fingerprint media")
+	}
+
+	return d, nil
+}
+
+// fetchManifest requests the manifest of the origin repository.
+func (rp *Replicator) fetchManifest(ctx context.Context) (*Manifest, error) {
+	res, err := rp.get(ctx, fmt.Sprintf("%s/api/v1/repos/%s/replica", rp.opts.URL, url.PathEscape(rp.opts.Repo)), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, statusErr(res)
+	}
+
+	var m Manifest
+	if err := json.NewDecoder(res.Body).Decode(&m); err != nil {
+		return nil, errors.Wrap(err, "failed to decode manifest")
+	}
+
+	return &m, nil
+}
+
+// download downloads the file of media to a path, resuming a partial download of the same file.
+// The file is written to a dot-prefixed partial file ignored by repositories, it replaces the path once it's complete.
+func (rp *Replicator) download(ctx context.Context, item *Item, path string) (err error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	partPath := filepath.Join(dir, "."+filepath.Base(path)+".replica")
+	f, err := os.OpenFile(partPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to open partial file")
+	}
+	defer func() {
+		if f != nil {
+			err = multierr.Append(err, f.Close())
+		}
+	}()
+
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return errors.Wrap(err, "failed to seek partial file")
+	}
+
+	header := make(http.Header)
+	if offset > 0 && !item.ModTime.IsZero() && (item.Size == 0 || offset <= item.Size) { // resumed only if the file didn't change since
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		header.Set("If-Range", item.ModTime.UTC().Format(http.TimeFormat))
+	}
+
+	res, err := rp.get(ctx, fmt.Sprintf("%s/api/v1/repos/%s/media/%s/download", rp.opts.URL, url.PathEscape(rp.opts.Repo), url.PathEscape(item.ID)), header)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		if offset != item.Size { // not complete after all
+			return multierr.Append(statusErr(res), os.Truncate(partPath, 0))
+		}
+	case http.StatusOK: // not resumed, from the start
+		if err := f.Truncate(0); err != nil {
+			return errors.Wrap(err, "failed to truncate partial file")
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return errors.Wrap(err, "failed to seek partial file")
+		}
+	default:
+		return statusErr(res)
+	}
+
+	if res.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		if _, err := io.Copy(f, res.Body); err != nil { // kept for resuming
+			return errors.Wrap(err, "failed to download file")
+		}
+	}
+
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return errors.Wrap(err, "failed to seek partial file")
+	}
+	if item.Size > 0 && size != item.Size {
+		return multierr.Append(&ErrSizeMismatch{ID: item.ID, Expected: item.Size, Actual: size}, os.Truncate(partPath, 0))
+	}
+
+	err, f = f.Close(), nil
+	if err != nil {
+		return errors.Wrap(err, "failed to close partial file")
+	}
+	if !item.ModTime.IsZero() { // for comparing it with the origin's
+		if err := os.Chtimes(partPath, item.ModTime, item.ModTime); err != nil {
+			return errors.Wrap(err, "failed to set modification time")
+		}
+	}
+	if err := os.Rename(partPath, path); err != nil {
+		return errors.Wrap(err, "failed to replace file")
+	}
+
+	return nil
+}
+
+// get makes an authenticated GET request to the origin server.
+func (rp *Replicator) get(ctx context.Context, url0 string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url0, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if rp.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+rp.opts.Token)
+	}
+
+	res, err := rp.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to request origin")
+	}
+
+	return res, nil
+}
+
+// statusErr makes an ErrStatus of a response, with the description of an API error in its body, if any.
+func statusErr(res *http.Response) error {
+	var body struct {
+		Description string `json:"description"`
+	}
+	_ = json.NewDecoder(io.LimitReader(res.Body, 64*1024)).Decode(&body)
+
+	return &ErrStatus{URL: res.Request.URL.String(), Status: res.StatusCode, Description: body.Description}
+}
@@ -0,0 +1,217 @@
+package replica
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testToken = "secret"
+
+// newTestOrigin creates a repository with media and a server serving it like the API does.
+// The returned counter counts the resumed downloads.
+func newTestOrigin(t *testing.T) (repo.MutableRepository, *httptest.Server, *atomic.Int32) {
+	root := t.TempDir()
+	r, err := repo.NewRepository("origin", "Origin", root, meta.NewLiteralSource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for id, title := range map[string]string{"movie": "Movie", "other": "Other"} {
+		path := filepath.Join(root, "Movies", id+".mkv")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, bytes.Repeat([]byte(id), 1024), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Add(media.NewMedia(id, path, meta.NewMetadata(meta.TypeMovie, title, "", "", time.Time{}, 0, nil), media.FormatMKV)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.SetTags("movie", []string{"kids"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var resumed atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer "+testToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		path := strings.TrimPrefix(req.URL.Path, "/api/v1/repos/origin/")
+		if path == "replica" {
+			m, err := MakeManifest(r)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(m)
+			return
+		}
+
+		id, ok := strings.CutSuffix(strings.TrimPrefix(path, "media/"), "/download")
+		m := r.Get(id)
+		if !ok || m == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if req.Header.Get("Range") != "" {
+			resumed.Add(1)
+		}
+
+		f, err := os.Open(m.Path())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer f.Close()
+
+		fi, _ := f.Stat()
+		http.ServeContent(w, req, fi.Name(), fi.ModTime(), f)
+	}))
+
+	return r, srv, &resumed
+}
+
+func newTestReplica(t *testing.T) repo.MutableRepository {
+	r, err := repo.NewRepository("replica", "Replica", t.TempDir(), meta.NewLiteralSource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return r
+}
+
+func TestReplicator_Replicate(t *testing.T) {
+	origin, srv, _ := newTestOrigin(t)
+	defer srv.Close()
+
+	local := newTestReplica(t)
+	rp, err := NewReplicator(filepath.Join(t.TempDir(), "replica.json"), &Options{URL: srv.URL, Token: testToken, Repo: "origin", Files: true, Prune: true}, srv.Client(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rp.Replicate(context.Background(), local); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"movie", "other"} {
+		m := local.Get(id)
+		if m == nil {
+			t.Fatalf("expected media %s to be replicated", id)
+		}
+
+		b, err := os.ReadFile(m.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, bytes.Repeat([]byte(id), 1024)) {
+			t.Errorf("expected file of media %s to be copied", id)
+		}
+		if rel, _ := filepath.Rel(local.Path(), m.Path()); rel != filepath.Join("Movies", id+".mkv") {
+			t.Errorf("expected file of media %s at the origin's relative path, got %s", id, rel)
+		}
+	}
+	if tags := local.Tags("movie"); len(tags) != 1 || tags[0] != "kids" {
+		t.Errorf("expected replicated tags, got %v", tags)
+	}
+
+	// local change only, kept
+	if err := local.SetTags("movie", []string{"mine"}); err != nil {
+		t.Fatal(err)
+	}
+	// origin change only, replicated
+	other := origin.Get("other")
+	if err := origin.Update(media.NewMedia("other", other.Path(), meta.NewMetadata(meta.TypeMovie, "Renamed", "", "", time.Time{}, 0, nil), other.Format())); err != nil {
+		t.Fatal(err)
+	}
+	if err := rp.Replicate(context.Background(), local); err != nil {
+		t.Fatal(err)
+	}
+	if tags := local.Tags("movie"); len(tags) != 1 || tags[0] != "mine" {
+		t.Errorf("expected local tags to be kept, got %v", tags)
+	}
+	if title := local.Get("other").Meta().Title(); title != "Renamed" {
+		t.Errorf("expected origin metadata to be replicated, got title %s", title)
+	}
+
+	// removed at the origin, pruned
+	if err := origin.Remove(origin.Get("other")); err != nil {
+		t.Fatal(err)
+	}
+	path := local.Get("other").Path()
+	if err := rp.Replicate(context.Background(), local); err != nil {
+		t.Fatal(err)
+	}
+	if local.Get("other") != nil {
+		t.Error("expected media removed at the origin to be pruned")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected file of pruned media to be removed, got %v", err)
+	}
+}
+
+func TestReplicator_Resume(t *testing.T) {
+	_, srv, resumed := newTestOrigin(t)
+	defer srv.Close()
+
+	local := newTestReplica(t)
+	partPath := filepath.Join(local.Path(), "Movies", ".movie.mkv.replica")
+	if err := os.MkdirAll(filepath.Dir(partPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(partPath, bytes.Repeat([]byte("movie"), 512), 0644); err != nil { // interrupted halfway
+		t.Fatal(err)
+	}
+
+	rp, err := NewReplicator("", &Options{URL: srv.URL, Token: testToken, Repo: "origin", Files: true}, srv.Client(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rp.Replicate(context.Background(), local); err != nil {
+		t.Fatal(err)
+	}
+	if n := resumed.Load(); n != 1 {
+		t.Errorf("expected 1 resumed download, got %d", n)
+	}
+
+	b, err := os.ReadFile(filepath.Join(local.Path(), "Movies", "movie.mkv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, bytes.Repeat([]byte("movie"), 1024)) {
+		t.Error("expected resumed file to be complete")
+	}
+	if _, err := os.Stat(partPath); !os.IsNotExist(err) {
+		t.Errorf("expected partial file to be replaced, got %v", err)
+	}
+}
+
+func TestReplicator_Unauthorized(t *testing.T) {
+	_, srv, _ := newTestOrigin(t)
+	defer srv.Close()
+
+	rp, err := NewReplicator("", &Options{URL: srv.URL, Token: "wrong", Repo: "origin"}, srv.Client(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = rp.Replicate(context.Background(), newTestReplica(t))
+	if es, ok := err.(*ErrStatus); !ok || es.Status != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized status error, got %v", err)
+	}
+}
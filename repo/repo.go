@@ -1,11 +1,14 @@
 package repo
 
 import (
+	"context"
+	"fmt"
 	"github.com/gabriel-vasile/mimetype"
 	"github.com/katana-project/katana/config"
 	"github.com/katana-project/katana/internal/errors"
 	"github.com/katana-project/katana/repo/media"
 	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/katana/repo/media/storyboard"
 	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
@@ -21,9 +24,7 @@ import (
 var (
 	allowedMimeGroups = []string{"video", "audio"}
 
-	idPattern               = regexp.MustCompile("^[a-z0-9-_]+$")
-	idCharExclusivePattern  = regexp.MustCompile("[^a-z0-9-_]")
-	commonDelimiterReplacer = strings.NewReplacer(" ", "-", ".", "-")
+	idPattern = regexp.MustCompile("^[a-z0-9-_]+$")
 )
 
 // Capability is a collection of option flags (integers ORed together).
@@ -38,6 +39,8 @@ const (
 	CapabilityRemux
 	// CapabilityTranscode is a flag of a repository that is able to transcode media.
 	CapabilityTranscode
+	// CapabilityArchive is a flag of a repository that is able to move media files to cold storage and back.
+	CapabilityArchive
 )
 
 // Capabilities translates capabilities from the configuration.
@@ -57,11 +60,91 @@ func Capabilities(caps []config.Capability) Capability {
 	return c
 }
 
+// IDStrategy translates a media ID strategy from the configuration, returns nil if unknown.
+func IDStrategy(strategy config.IDStrategy) media.IDStrategy {
+	switch strategy {
+	case config.IDStrategySlug:
+		return media.IDStrategySlug
+	case config.IDStrategyHash:
+		return media.IDStrategyHash
+	case config.IDStrategyUUID:
+		return media.IDStrategyUUID
+	case config.IDStrategyContent:
+		return media.IDStrategyContent
+	case config.IDStrategyMeta:
+		return media.IDStrategyMeta
+	}
+
+	return nil
+}
+
 // Has checks whether a Capability can be addressed from this one.
 func (c Capability) Has(flag Capability) bool {
 	return (c & flag) != 0
 }
 
+// String returns the string representation of the capability flags, their configuration IDs joined by commas.
+func (c Capability) String() string {
+	var names []string
+	if c.Has(CapabilityWatch) {
+		names = append(names, string(config.CapabilityWatch))
+	}
+	if c.Has(CapabilityIndex) {
+		names = append(names, "index")
+	}
+	if c.Has(CapabilityRemux) {
+		names = append(names, string(config.CapabilityRemux))
+	}
+	if c.Has(CapabilityTranscode) {
+		names = append(names, string(config.CapabilityTranscode))
+	}
+	if c.Has(CapabilityArchive) {
+		names = append(names, "archive")
+	}
+
+	return strings.Join(names, ",")
+}
+
+// Negotiate checks the prerequisites of requested capabilities, such as a wrapper checking the repository it wraps at construction,
+// returns the capabilities that would work and the reasons of the others being unavailable (nil if there are none).
+// The check returns the reason of a single capability being unavailable or an empty string if it's available.
+func Negotiate(requested Capability, check func(c Capability) string) (Capability, map[Capability]string) {
+	var (
+		available   Capability
+		unavailable map[Capability]string
+	)
+	for c := Capability(1); c != 0 && c <= requested; c <<= 1 {
+		if !requested.Has(c) {
+			continue
+		}
+
+		if reason := check(c); reason != "" {
+			if unavailable == nil {
+				unavailable = make(map[Capability]string)
+			}
+
+			unavailable[c] = reason
+			continue
+		}
+
+		available |= c
+	}
+
+	return available, unavailable
+}
+
+// CacheUsage is the usage of a repository's cache of remuxed and transcoded files.
+type CacheUsage struct {
+	// Files is the number of complete cache files.
+	Files int
+	// Bytes is the total size of the files.
+	Bytes int64
+	// MaxBytes is the size limit of the cache, zero if there's none.
+	MaxBytes int64
+	// MaxAge is the retention period of all files since their last use, zero if there's none.
+	MaxAge time.Duration
+}
+
 // Repository is an immutable media repository or an immutable view of one.
 type Repository interface {
 	// ID returns the repository ID, alphanumeric, lowercase, non-blank ([a-z0-9-_]).
@@ -69,9 +152,16 @@ type Repository interface {
 	// Name returns the repository name.
 	Name() string
 	// Path returns the path to the root directory of this repository, absolute.
+	// It's the first of Roots for repositories spread over multiple directories.
 	Path() string
+	// Roots returns the paths to the root directories of this repository, absolute, such as directories on multiple drives.
+	// Media paths are made relative to the root containing them with RelPath.
+	Roots() []string
 	// Capabilities returns the capabilities of this repository.
 	Capabilities() Capability
+	// Unavailable returns the capabilities requested from this repository that were left out when negotiating them (see Negotiate),
+	// mapped to the reasons, such as a prerequisite the wrapped repository doesn't meet. It's nil if none were left out.
+	Unavailable() map[Capability]string
 
 	// Get tries to get media by its ID in this repository, returns nil if not found.
 	Get(id string) media.Media
@@ -79,10 +169,69 @@ type Repository interface {
 	Find(path string) media.Media
 	// Items returns the pieces of media in this repository.
 	Items() []media.Media
+	// Query returns a page of media selected and sorted by a query and the number of all selected media.
+	Query(q *Query) ([]media.Media, int, error)
+	// Revision returns the current revision of this repository, usable for detecting changes of its media.
+	Revision() Revision
+	// FileStat returns the size and modification time of a media file when its format was last detected,
+	// zero if it's not known yet or the ID wasn't found.
+	FileStat(id string) FileStat
+	// Tags returns the user-defined tags of media, sorted, nil if it has none or the ID wasn't found.
+	Tags(id string) []string
+	// Locked checks whether media is locked against automatic changes (see MutableRepository.SetLocked),
+	// false if it isn't or the ID wasn't found.
+	Locked(id string) bool
+	// ArchiveStatus returns the cold storage status of media, ArchiveStatusNone if it's not archived or the ID wasn't found.
+	ArchiveStatus(id string) ArchiveStatus
+	// Events returns the registry of observers of this repository's changes, shared with the repositories it wraps.
+	Events() *Events
+	// Health returns the availability of this repository's files.
+	Health() Health
 
 	// Remux remuxes media to the desired container format and returns the remuxed media or nil, if the ID wasn't found.
+	// The operation is aborted when the context is canceled, progress is reported to the context's ProgressFunc.
+	// ErrUnsupportedOperation may be returned if the repository does not have the CapabilityRemux capability.
+	Remux(ctx context.Context, id string, format *media.Format) (media.Media, error)
+	// Transcode transcodes media to the desired profile and returns the transcoded media or nil, if the ID wasn't found.
+	// The operation is aborted when the context is canceled, progress is reported to the context's ProgressFunc.
+	// ErrUnsupportedOperation may be returned if the repository does not have the CapabilityTranscode capability.
+	Transcode(ctx context.Context, id string, profile *media.TranscodeProfile) (media.Media, error)
+	// Preview makes a short low-resolution preview clip of media, a silent MP4 montage of segments spread over it,
+	// and returns it as media or nil, if the ID wasn't found.
+	// ErrUnsupportedOperation may be returned if the repository does not have the CapabilityTranscode capability.
+	Preview(ctx context.Context, id string) (media.Media, error)
+	// Storyboard makes the trick-play thumbnail sprite sheets of media and returns their layout with the sheet paths set
+	// or nil, if the ID wasn't found. Progress is reported to the context's ProgressFunc in sheets.
+	// ErrUnsupportedOperation may be returned if the repository does not have the CapabilityTranscode capability.
+	Storyboard(ctx context.Context, id string) (*storyboard.Storyboard, error)
+	// Probe reads the container information of media that haven't been probed yet (i.e. embedded subtitle tracks and chapters),
+	// so that requests don't wait on opening media files.
+	// The operation is aborted when the context is canceled, progress is reported to the context's ProgressFunc in probed media.
 	// ErrUnsupportedOperation may be returned if the repository does not have the CapabilityRemux capability.
-	Remux(id string, format *media.Format) (media.Media, error)
+	Probe(ctx context.Context) error
+	// CacheUsage returns the usage of the cache of remuxed and transcoded files.
+	// ErrUnsupportedOperation may be returned if the repository has neither the CapabilityRemux nor the CapabilityTranscode capability.
+	CacheUsage() (*CacheUsage, error)
+	// PurgeCache removes the remuxed and transcoded files of the cache, except ones being made, and returns the usage freed.
+	// ErrUnsupportedOperation may be returned if the repository has neither the CapabilityRemux nor the CapabilityTranscode capability.
+	PurgeCache() (*CacheUsage, error)
+
+	// Subtitles returns the subtitle tracks of media or nil, if the ID wasn't found.
+	// Embedded tracks are only listed by repositories with the CapabilityRemux capability, sidecar files always.
+	Subtitles(id string) ([]*media.Subtitle, error)
+	// ExtractSubtitle returns a subtitle track of media with its path set to a file containing only the track or nil, if the IDs weren't found.
+	// ErrUnsupportedOperation may be returned for embedded tracks if the repository does not have the CapabilityRemux capability,
+	// ErrUnsupportedFormat for tracks that can't be extracted.
+	ExtractSubtitle(ctx context.Context, id, trackId string) (*media.Subtitle, error)
+	// AudioTracks returns the embedded audio tracks of media or nil, if the ID wasn't found.
+	// Tracks are only listed by repositories with the CapabilityRemux capability, their languages are detected when probing.
+	AudioTracks(id string) ([]*media.AudioTrack, error)
+	// Chapters returns the chapter markers of media in order or nil, if the ID wasn't found.
+	// Chapters are only listed by repositories with the CapabilityRemux capability, they're read when probing.
+	// ErrUnsupportedFormat may be returned if the repository's conversion backend can't read chapters.
+	Chapters(id string) ([]*media.Chapter, error)
+	// Extras returns the theme music and trailers of media or nil, if the ID wasn't found.
+	Extras(id string) ([]*media.Extra, error)
 
 	// Source returns the metadata source for this repository.
 	Source() meta.Source
@@ -100,28 +249,84 @@ func ValidID(s string) bool {
 	return idPattern.MatchString(s)
 }
 
-// SanitizeID sanitizes a string to be usable as a repository ID.
-// Example: "My Shows" -> "my-shows"
-func SanitizeID(s string) string {
-	spaceLessLowerCase := strings.ToLower(commonDelimiterReplacer.Replace(s))
+// ValidTag checks whether the supplied string is a valid media tag, the same form as IDs ([a-z0-9-_]).
+func ValidTag(s string) bool {
+	return idPattern.MatchString(s)
+}
+
+// normalizeTags trims, lowercases, deduplicates and sorts tags, blank ones are dropped.
+// Returns nil if there are no tags left.
+func normalizeTags(tags []string) ([]string, error) {
+	var res []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if !ValidTag(tag) {
+			return nil, &ErrInvalidTag{Tag: tag, Expected: idPattern.String()}
+		}
 
-	return idCharExclusivePattern.ReplaceAllLiteralString(spaceLessLowerCase, "")
+		res = append(res, tag)
+	}
+	slices.Sort(res)
+
+	return slices.Compact(res), nil
+}
+
+// SanitizeID sanitizes a string to be usable as a repository ID, Latin diacritics are transliterated to their base letters.
+// Example: "My Shows" -> "my-shows", "Seriály" -> "serialy"
+func SanitizeID(s string) string {
+	return media.SanitizeID(s) // same rules
 }
 
 // MutableRepository is a mutable media repository.
 type MutableRepository interface {
 	Repository
 
-	// Scan tries to recursively discover missing media from the repository root directory.
-	Scan() error
+	// Scan tries to recursively discover missing media from the repository root directory,
+	// the formats of media files that changed are detected again, media that aren't media anymore are removed.
+	Scan(mode ScanMode) error
 	// Add adds media to the repository.
 	Add(m media.Media) error
+	// AddUnverified adds media to the repository without accessing its file, such as media restored from an index.
+	// The file is expected to exist, its addition time, sidecar subtitles and extras are only discovered by the next Scan.
+	// stat is the size and modification time of the file when its format was last detected, can be zero if unknown.
+	AddUnverified(m media.Media, stat FileStat) error
 	// AddPath adds media at the supplied path to the repository.
 	AddPath(path string) error
+	// Update replaces media of the same ID and path in the repository, such as to change its metadata.
+	Update(m media.Media) error
+	// Move relinks media to the absolute path its file was moved or renamed to, keeping its ID, metadata and addition time.
+	Move(m media.Media, path string) error
+	// SetTags replaces the user-defined tags of media, such as "kids" or "4k-demo", they're kept along with its ID.
+	// Tags are trimmed, lowercased and deduplicated, ErrInvalidTag is returned for tags that aren't valid (ValidTag).
+	SetTags(id string, tags []string) error
+	// SetLocked locks manually curated media against automatic changes, such as periodic metadata refreshes, or unlocks it.
+	// The lock is kept along with its ID, like tags.
+	SetLocked(id string, locked bool) error
 	// Remove removes media from the repository.
 	Remove(m media.Media) error
+	// Archive moves the file of media to cold storage, the media is kept in the repository with its metadata.
+	// Files of archived media that disappear from the repository directory don't remove their media.
+	// The operation is aborted when the context is canceled.
+	// ErrUnsupportedOperation may be returned if the repository does not have the CapabilityArchive capability.
+	Archive(ctx context.Context, id string) error
+	// Restore moves the file of archived media back from cold storage, media that isn't archived is left as is.
+	// The operation is aborted when the context is canceled.
+	// ErrUnsupportedOperation may be returned if the repository does not have the CapabilityArchive capability.
+	Restore(ctx context.Context, id string) error
 	// RemovePath removes media with the supplied absolute path from the repository.
 	RemovePath(path string) error
+	// Ignored checks whether a file or directory at the supplied absolute path is excluded from handling,
+	// i.e. it or one of its parent directories is dot-prefixed or it doesn't pass the repository's Filter.
+	Ignored(path string, dir bool) bool
+
+	// BeginBatch starts a batch of changes, persisting them is deferred until the matching Commit.
+	// Batches can be nested, changes are persisted once the outermost one is committed.
+	BeginBatch()
+	// Commit ends a batch of changes started by BeginBatch, persisting them if it's the outermost one.
+	Commit() error
 }
 
 // NopMutable wraps a Repository and no-ops unimplemented mutation functions.
@@ -138,21 +343,50 @@ type nopMutableRepo struct {
 	Repository
 }
 
-func (nmr *nopMutableRepo) Scan() error {
+func (nmr *nopMutableRepo) Scan(_ ScanMode) error {
 	return errors.ErrUnsupported
 }
 func (nmr *nopMutableRepo) Add(_ media.Media) error {
 	return errors.ErrUnsupported
 }
+func (nmr *nopMutableRepo) AddUnverified(_ media.Media, _ FileStat) error {
+	return errors.ErrUnsupported
+}
 func (nmr *nopMutableRepo) AddPath(_ string) error {
 	return errors.ErrUnsupported
 }
+func (nmr *nopMutableRepo) Update(_ media.Media) error {
+	return errors.ErrUnsupported
+}
+func (nmr *nopMutableRepo) Move(_ media.Media, _ string) error {
+	return errors.ErrUnsupported
+}
+func (nmr *nopMutableRepo) SetTags(_ string, _ []string) error {
+	return errors.ErrUnsupported
+}
+func (nmr *nopMutableRepo) SetLocked(_ string, _ bool) error {
+	return errors.ErrUnsupported
+}
 func (nmr *nopMutableRepo) Remove(_ media.Media) error {
 	return errors.ErrUnsupported
 }
 func (nmr *nopMutableRepo) RemovePath(_ string) error {
 	return errors.ErrUnsupported
 }
+func (nmr *nopMutableRepo) Archive(_ context.Context, _ string) error {
+	return errors.ErrUnsupported
+}
+func (nmr *nopMutableRepo) Restore(_ context.Context, _ string) error {
+	return errors.ErrUnsupported
+}
+func (nmr *nopMutableRepo) Ignored(_ string, _ bool) bool {
+	return false
+}
+func (nmr *nopMutableRepo) BeginBatch() {
+}
+func (nmr *nopMutableRepo) Commit() error {
+	return nil
+}
 func (nmr *nopMutableRepo) Mutable() MutableRepository {
 	return nmr
 }
@@ -161,43 +395,95 @@ func (nmr *nopMutableRepo) Mutable() MutableRepository {
 type mutableRepo struct {
 	id         string
 	name       string
-	path       string
+	path       string   // the first root
+	roots      []string // absolute
 	metaSource meta.Source
+	idStrategy media.IDStrategy
+	filter     *Filter
 	logger     *zap.Logger
 
+	// foldCase is whether the repository filesystem is case-insensitive, paths are case-folded for lookups then
+	foldCase bool
+
 	mu sync.RWMutex
 
 	// these two should be kept in sync - use addItem and removeItem
 	itemsById   map[string]media.Media
-	itemsByPath map[string]media.Media
+	itemsByPath map[string]media.Media // keyed by pathKey
+
+	subtitles map[string][]*media.Subtitle // media ID -> sidecar subtitle tracks
+	extras    map[string][]*media.Extra    // media ID -> theme music and trailers
+	added     map[string]time.Time         // media ID -> file modification time when added, stable across restarts
+	stats     map[string]FileStat          // media ID -> file stat when its format was detected
+	tags      map[string][]string          // media ID -> user-defined tags, kept across updates and moves
+	locked    map[string]struct{}          // IDs of media locked against automatic changes, kept across updates and moves
+	skipped   map[string]FileStat          // path key -> file stat of a non-media file, not detected again until it changes
+	revision  Revision                     // bumped by addItem and removeItem
+
+	events *Events
 }
 
 // NewRepository creates a file-based CRUD repository.
-func NewRepository(id, name, path string, metaSource meta.Source, logger *zap.Logger) (MutableRepository, error) {
+// Media IDs are made using idStrategy, media.IDStrategySlug is used if nil.
+func NewRepository(id, name, path string, metaSource meta.Source, idStrategy media.IDStrategy, logger *zap.Logger) (MutableRepository, error) {
+	return NewMultiRootRepository(id, name, []string{path}, metaSource, idStrategy, nil, logger)
+}
+
+// NewMultiRootRepository creates a file-based CRUD repository spread over multiple root directories, such as on multiple drives.
+// The roots must not be nested in each other, media IDs are made using idStrategy, media.IDStrategySlug is used if nil.
+// Files not passing filter are excluded from handling, all files are handled if it's nil (except dot-prefixed ones).
+func NewMultiRootRepository(id, name string, paths []string, metaSource meta.Source, idStrategy media.IDStrategy, filter *Filter, logger *zap.Logger) (MutableRepository, error) {
 	if !ValidID(id) {
 		return nil, &ErrInvalidID{
 			ID:       id,
 			Expected: idPattern.String(),
 		}
 	}
+	if len(paths) == 0 {
+		return nil, errors.New("no repository root directories")
+	}
 
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return nil, err
+	roots := make([]string, len(paths))
+	for i, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := os.MkdirAll(absPath, 0); err != nil {
+			return nil, errors.Wrap(err, "failed to make directories")
+		}
+		roots[i] = absPath
+	}
+	if a, b, ok := overlapping(roots); ok {
+		return nil, fmt.Errorf("repository root directories %s and %s overlap", a, b)
 	}
 
-	if err := os.MkdirAll(absPath, 0); err != nil {
-		return nil, errors.Wrap(err, "failed to make directories")
+	if idStrategy == nil {
+		idStrategy = media.IDStrategySlug
 	}
 
 	return &mutableRepo{
 		id:          id,
 		name:        name,
-		path:        absPath,
+		path:        roots[0],
+		roots:       roots,
 		itemsById:   make(map[string]media.Media),
 		itemsByPath: make(map[string]media.Media),
+		subtitles:   make(map[string][]*media.Subtitle),
+		extras:      make(map[string][]*media.Extra),
+		added:       make(map[string]time.Time),
+		stats:       make(map[string]FileStat),
+		tags:        make(map[string][]string),
+		locked:      make(map[string]struct{}),
+		skipped:     make(map[string]FileStat),
+		revision:    Revision{Modified: time.Now()},
+		events:      &Events{},
 		logger:      logger,
 		metaSource:  metaSource,
+		idStrategy:  idStrategy,
+		filter:      filter,
+		foldCase:    caseInsensitive(roots[0]),
 	}, nil
 }
 
@@ -213,21 +499,115 @@ func (mr *mutableRepo) Path() string {
 	return mr.path
 }
 
+func (mr *mutableRepo) Roots() []string {
+	return slices.Clone(mr.roots)
+}
+
+// rel relativizes an absolute path to the root containing it (RelPath).
+func (mr *mutableRepo) rel(path string) (string, error) {
+	return RelPath(mr.roots, path)
+}
+
 func (mr *mutableRepo) Capabilities() Capability {
 	return 0
 }
 
-func (mr *mutableRepo) addItem(id, path string, m media.Media) {
+func (mr *mutableRepo) Unavailable() map[Capability]string {
+	return nil
+}
+
+// pathKey normalizes a path relative to the repository root for use as an itemsByPath key.
+func (mr *mutableRepo) pathKey(relPath string) string {
+	key := filepath.Clean(filepath.FromSlash(relPath))
+	if mr.foldCase {
+		key = strings.ToLower(key)
+	}
+
+	return key
+}
+
+// relPathKey relativizes an absolute or relative path and normalizes it for use as an itemsByPath key.
+func (mr *mutableRepo) relPathKey(path string) (string, error) {
+	path = filepath.Clean(filepath.FromSlash(path))
+	if !filepath.IsAbs(path) {
+		return mr.pathKey(path), nil
+	}
+
+	roots := mr.roots
+	if mr.foldCase { // relativize case-insensitively
+		roots = make([]string, len(mr.roots))
+		for i, root := range mr.roots {
+			roots[i] = strings.ToLower(root)
+		}
+		path = strings.ToLower(path)
+	}
+
+	relPath, err := RelPath(roots, path)
+	if err != nil {
+		return "", err
+	}
+
+	return mr.pathKey(relPath), nil
+}
+
+func (mr *mutableRepo) addItem(id, path string, m media.Media, added time.Time, stat FileStat) {
 	mr.itemsById[id] = m
-	mr.itemsByPath[path] = m
+	mr.itemsByPath[mr.pathKey(path)] = m
+	mr.added[id] = added
+	mr.stats[id] = stat
+	mr.revision.bump()
+}
+
+// makeID makes a media ID for a file using the ID strategy, IDs already taken by other media
+// are made unique with a numeric suffix, such as "episode-1-mkv-2", mu must be held.
+func (mr *mutableRepo) makeID(relPath string, m meta.Metadata) (string, error) {
+	root, rootRelPath := SplitRoot(mr.roots, relPath)
+	id, err := mr.idStrategy(root, rootRelPath, m)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to make media ID")
+	}
+	if _, ok := mr.itemsById[id]; !ok {
+		return id, nil
+	}
+
+	unique := id
+	for i := 2; ; i++ {
+		unique = fmt.Sprintf("%s-%d", id, i)
+		if _, ok := mr.itemsById[unique]; !ok {
+			break
+		}
+	}
+	if mr.logger != nil {
+		mr.logger.Warn(
+			"media ID collision, using a suffixed ID",
+			zap.String("repo", mr.id),
+			zap.String("repo_path", mr.path),
+			zap.String("path", relPath),
+			zap.String("id", id),
+			zap.String("unique_id", unique),
+		)
+	}
+
+	return unique, nil
 }
 
 func (mr *mutableRepo) removeItem(id, path string) bool {
 	length := len(mr.itemsById) - 1
 	delete(mr.itemsById, id)
-	delete(mr.itemsByPath, path)
+	delete(mr.itemsByPath, mr.pathKey(path))
+	delete(mr.subtitles, id)
+	delete(mr.extras, id)
+	delete(mr.added, id)
+	delete(mr.stats, id)
+	delete(mr.tags, id)
+	delete(mr.locked, id)
+
+	removed := len(mr.itemsById) == length
+	if removed {
+		mr.revision.bump()
+	}
 
-	return len(mr.itemsById) == length
+	return removed
 }
 
 func (mr *mutableRepo) checkFormat(path string, format *media.Format) error {
@@ -248,11 +628,21 @@ func (mr *mutableRepo) detectAndCheckFormat(path string) (*media.Format, error)
 		return nil, errors.Wrap(err, "failed to detect MIME type")
 	}
 
-	format := media.FindUnsupportedFormat(t.String(), filepath.Ext(path))
+	ext := filepath.Ext(path)
+	if t.Is("application/octet-stream") {
+		if format := media.FindFormatExtension(ext); format != nil { // no reliable signature, such as of MPEG-TS
+			return format, mr.checkFormat(path, format)
+		}
+	}
+
+	format := media.FindUnsupportedFormat(t.String(), ext)
 	return format, mr.checkFormat(path, format)
 }
 
-func (mr *mutableRepo) Scan() error {
+func (mr *mutableRepo) Scan(mode ScanMode) error {
+	var events []Event
+	defer func() { mr.events.Publish(events...) }() // after unlocking
+
 	mr.mu.Lock()
 	defer mr.mu.Unlock()
 
@@ -263,17 +653,19 @@ func (mr *mutableRepo) Scan() error {
 				"finished repository scan",
 				zap.String("id", mr.id),
 				zap.String("path", mr.path),
+				zap.Bool("full", mode == ScanFull),
 				zap.Int64("elapsed_ms", time.Since(scanTime).Milliseconds()),
 			)
 		}()
 	}
 
-	err := filepath.WalkDir(mr.path, func(path string, d fs.DirEntry, err error) error {
+	seen := make(map[string]struct{}, len(mr.skipped)) // path keys of walked files
+	walk := func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if strings.HasPrefix(d.Name(), ".") { // dot-prefixed files/directories are excluded from handling
+		if mr.Ignored(path, d.IsDir()) {
 			if d.IsDir() {
 				return filepath.SkipDir
 			}
@@ -281,47 +673,263 @@ func (mr *mutableRepo) Scan() error {
 			return nil
 		}
 
-		if !d.IsDir() {
-			relPath, err := filepath.Rel(mr.path, path)
+		if !d.IsDir() && media.SubtitleCodec(filepath.Ext(path)) == "" { // subtitles and extras are discovered after the walk
+			relPath, err := mr.rel(path)
 			if err != nil {
 				return err // shouldn't be possible
 			}
+			if media.ExtraKindOf(path) != "" {
+				if m, ok := mr.itemsByPath[mr.pathKey(relPath)]; ok { // indexed as media before extras were recognized
+					mr.removeItem(m.ID(), relPath)
+					events = append(events, Event{Type: EventMediaRemoved, RepoID: mr.id, Media: m})
+				}
 
-			if _, ok := mr.itemsByPath[relPath]; !ok {
-				format, err := mr.detectAndCheckFormat(path)
-				if err != nil {
-					var eimt ErrInvalidMediaType
-					if errors.Is(err, &eimt) { // invalid MIME type, skip
-						if mr.logger != nil {
-							mr.logger.Warn(
-								"invalid MIME type, skipping",
-								zap.String("repo", mr.id),
-								zap.String("repo_path", mr.path),
-								zap.String("path", relPath),
-								zap.String("type", eimt.Type),
-							)
-						}
-
-						return nil
-					}
+				return nil
+			}
+
+			fi, err := d.Info()
+			if err != nil {
+				return nil // removed meanwhile
+			}
+
+			key := mr.pathKey(relPath)
+			seen[key] = struct{}{}
+
+			var (
+				stat  = NewFileStat(fi)
+				m, ok = mr.itemsByPath[key]
+			)
+			if ok {
+				if mr.added[m.ID()].IsZero() { // added unverified
+					mr.added[m.ID()] = fi.ModTime()
+				}
 
-					return err // wrapped in checkFormat already
+				prev := mr.stats[m.ID()]
+				if mode == ScanIncremental && (prev.IsZero() || prev.Equal(stat)) { // unknown stats are trusted, like the index
+					mr.stats[m.ID()] = stat
+					return nil
 				}
 
-				m, err := mr.metaSource.FromFile(path)
+				event, err := mr.redetect(m, relPath, stat)
 				if err != nil {
-					return errors.Wrap(err, "failed to discover metadata")
+					return err
+				}
+				if event != nil {
+					events = append(events, *event)
 				}
 
-				id := media.SanitizeID(d.Name())
-				mr.addItem(id, relPath, media.NewMedia(id, path, m, format))
+				return nil
+			}
+			if prev, ok := mr.skipped[key]; ok && mode == ScanIncremental && prev.Equal(stat) {
+				return nil
 			}
+
+			format, err := mr.detectAndCheckFormat(path)
+			if err != nil {
+				var eimt *ErrInvalidMediaType
+				if errors.As(err, &eimt) { // invalid MIME type, skip
+					mr.skipped[key] = stat
+					if mr.logger != nil {
+						mr.logger.Warn(
+							"invalid MIME type, skipping",
+							zap.String("repo", mr.id),
+							zap.String("repo_path", mr.path),
+							zap.String("path", relPath),
+							zap.String("type", eimt.Type),
+						)
+					}
+
+					return nil
+				}
+
+				return err // wrapped in checkFormat already
+			}
+			delete(mr.skipped, key)
+
+			mm, err := mr.metaSource.FromFile(path)
+			if err != nil {
+				return errors.Wrap(err, "failed to discover metadata")
+			}
+
+			id, err := mr.makeID(relPath, mm)
+			if err != nil {
+				return err
+			}
+			m0 := media.NewMedia(id, path, mm, format)
+
+			mr.addItem(id, relPath, m0, fi.ModTime(), stat)
+			events = append(events, Event{Type: EventMediaAdded, RepoID: mr.id, Media: m0})
 		}
 
 		return nil
-	})
+	}
+	for _, root := range mr.roots {
+		if err := filepath.WalkDir(root, walk); err != nil {
+			return errors.Wrap(err, "failed to walk repository files")
+		}
+	}
+	for key := range mr.skipped {
+		if _, ok := seen[key]; !ok { // removed
+			delete(mr.skipped, key)
+		}
+	}
+	for id, m := range mr.itemsById {
+		if !mr.Ignored(m.Path(), false) {
+			continue
+		}
+
+		relPath, err := mr.rel(m.Path())
+		if err != nil {
+			return err // shouldn't be possible
+		}
+
+		mr.removeItem(id, relPath) // indexed before it was excluded
+		events = append(events, Event{Type: EventMediaRemoved, RepoID: mr.id, Media: m})
+	}
+	if err := mr.discoverSubtitles(maps.Values(mr.itemsById)); err != nil {
+		return errors.Wrap(err, "failed to discover subtitles")
+	}
+	if err := mr.discoverExtras(maps.Values(mr.itemsById)); err != nil {
+		return errors.Wrap(err, "failed to discover extras")
+	}
+
+	events = append(events, Event{Type: EventScanCompleted, RepoID: mr.id})
+	return nil
+}
+
+// redetect detects the format of a changed media file again, media whose file isn't media anymore are removed.
+// Returns the event of the change, nil if the format stayed the same, mu must be held.
+func (mr *mutableRepo) redetect(m media.Media, relPath string, stat FileStat) (*Event, error) {
+	format, err := mr.detectAndCheckFormat(m.Path())
+	if err != nil {
+		var eimt *ErrInvalidMediaType
+		if !errors.As(err, &eimt) {
+			return nil, err // wrapped in checkFormat already
+		}
+
+		mr.removeItem(m.ID(), relPath)
+		mr.skipped[mr.pathKey(relPath)] = stat
+		if mr.logger != nil {
+			mr.logger.Warn(
+				"changed media file is of an invalid MIME type, removing",
+				zap.String("repo", mr.id),
+				zap.String("repo_path", mr.path),
+				zap.String("path", relPath),
+				zap.String("type", eimt.Type),
+			)
+		}
+
+		return &Event{Type: EventMediaRemoved, RepoID: mr.id, Media: m}, nil
+	}
+
+	if prev := m.Format(); prev != nil && *prev == *format {
+		mr.stats[m.ID()] = stat
+		return nil, nil
+	}
+
+	m0 := media.NewMedia(m.ID(), m.Path(), m.Meta(), format)
+	mr.addItem(m.ID(), relPath, m0, mr.added[m.ID()], stat) // replaces the old item
+	return &Event{Type: EventMediaUpdated, RepoID: mr.id, Media: m0}, nil
+}
+
+func (mr *mutableRepo) FileStat(id string) FileStat {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	return mr.stats[id]
+}
+
+func (mr *mutableRepo) Tags(id string) []string {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	return slices.Clone(mr.tags[id])
+}
+
+func (mr *mutableRepo) SetTags(id string, tags []string) error {
+	tags, err := normalizeTags(tags)
 	if err != nil {
-		return errors.Wrap(err, "failed to walk repository files")
+		return err
+	}
+
+	var events []Event
+	defer func() { mr.events.Publish(events...) }() // after unlocking
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	m, ok := mr.itemsById[id]
+	if !ok {
+		return &ErrMediaNotFound{
+			ID:   id,
+			Repo: mr.path,
+		}
+	}
+	if slices.Equal(mr.tags[id], tags) {
+		return nil
+	}
+
+	if tags == nil {
+		delete(mr.tags, id)
+	} else {
+		mr.tags[id] = tags
+	}
+	mr.revision.bump()
+	events = append(events, Event{Type: EventMediaUpdated, RepoID: mr.id, Media: m})
+	if mr.logger != nil {
+		mr.logger.Info(
+			"updated media tags in repository",
+			zap.String("repo", mr.id),
+			zap.String("repo_path", mr.path),
+			zap.String("id", id),
+			zap.Strings("tags", tags),
+		)
+	}
+
+	return nil
+}
+
+func (mr *mutableRepo) Locked(id string) bool {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	_, ok := mr.locked[id]
+	return ok
+}
+
+func (mr *mutableRepo) SetLocked(id string, locked bool) error {
+	var events []Event
+	defer func() { mr.events.Publish(events...) }() // after unlocking
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	m, ok := mr.itemsById[id]
+	if !ok {
+		return &ErrMediaNotFound{
+			ID:   id,
+			Repo: mr.path,
+		}
+	}
+	if _, ok := mr.locked[id]; ok == locked {
+		return nil
+	}
+
+	if locked {
+		mr.locked[id] = struct{}{}
+	} else {
+		delete(mr.locked, id)
+	}
+	mr.revision.bump()
+	events = append(events, Event{Type: EventMediaUpdated, RepoID: mr.id, Media: m})
+	if mr.logger != nil {
+		mr.logger.Info(
+			"updated media lock in repository",
+			zap.String("repo", mr.id),
+			zap.String("repo_path", mr.path),
+			zap.String("id", id),
+			zap.Bool("locked", locked),
+		)
 	}
 
 	return nil
@@ -335,26 +943,24 @@ func (mr *mutableRepo) Get(id string) media.Media {
 }
 
 func (mr *mutableRepo) Find(path string) media.Media {
-	relPath := path
-	if filepath.IsAbs(path) { // relativize path
-		var err error
-		if relPath, err = filepath.Rel(mr.path, path); err != nil {
-			return nil // fast path: can't be made relative
-		}
+	key, err := mr.relPathKey(path)
+	if err != nil {
+		return nil // fast path: can't be made relative
 	}
 
 	mr.mu.RLock()
 	defer mr.mu.RUnlock()
 
-	return mr.itemsByPath[relPath]
+	return mr.itemsByPath[key]
 }
 
 func (mr *mutableRepo) add(id, path string, m media.Media) error {
-	if _, err := os.Stat(path); err != nil { // catches non-existent files
+	fi, err := os.Stat(path)
+	if err != nil { // catches non-existent files
 		return errors.Wrap(err, "failed to stat file")
 	}
 
-	relPath, err := filepath.Rel(mr.path, path)
+	relPath, err := mr.rel(path)
 	if err != nil {
 		return &ErrInvalidMediaPath{
 			Path: path,
@@ -362,6 +968,9 @@ func (mr *mutableRepo) add(id, path string, m media.Media) error {
 		}
 	}
 
+	var events []Event
+	defer func() { mr.events.Publish(events...) }() // after unlocking
+
 	mr.mu.Lock()
 	defer mr.mu.Unlock()
 
@@ -371,14 +980,33 @@ func (mr *mutableRepo) add(id, path string, m media.Media) error {
 			Repo: mr.path,
 		}
 	}
-	if _, ok := mr.itemsByPath[relPath]; ok {
+	if _, ok := mr.itemsByPath[mr.pathKey(relPath)]; ok {
 		return &ErrDuplicatePath{
 			Path: relPath,
 			Repo: mr.path,
 		}
 	}
 
-	mr.addItem(id, relPath, m)
+	mr.addItem(id, relPath, m, fi.ModTime(), NewFileStat(fi))
+	events = append(events, Event{Type: EventMediaAdded, RepoID: mr.id, Media: m})
+	if err := mr.discoverSubtitles([]media.Media{m}); err != nil && mr.logger != nil {
+		mr.logger.Warn(
+			"failed to discover subtitles",
+			zap.String("repo", mr.id),
+			zap.String("repo_path", mr.path),
+			zap.String("path", relPath),
+			zap.Error(err),
+		)
+	}
+	if err := mr.discoverExtras([]media.Media{m}); err != nil && mr.logger != nil {
+		mr.logger.Warn(
+			"failed to discover extras",
+			zap.String("repo", mr.id),
+			zap.String("repo_path", mr.path),
+			zap.String("path", relPath),
+			zap.Error(err),
+		)
+	}
 	if mr.logger != nil {
 		mr.logger.Info(
 			"added media to repository",
@@ -409,7 +1037,61 @@ func (mr *mutableRepo) Add(m media.Media) error {
 	return mr.add(id, path, m)
 }
 
+func (mr *mutableRepo) AddUnverified(m media.Media, stat FileStat) error {
+	id := m.ID()
+	if !media.ValidID(id) {
+		return &ErrInvalidID{
+			ID:       id,
+			Expected: "^[a-z0-9-_]+$", // media.idPattern
+		}
+	}
+
+	path := m.Path()
+	if err := mr.checkFormat(path, m.Format()); err != nil {
+		return errors.Wrap(err, "failed format check")
+	}
+
+	relPath, err := mr.rel(path)
+	if err != nil {
+		return &ErrInvalidMediaPath{
+			Path: path,
+			Root: mr.path,
+		}
+	}
+
+	var events []Event
+	defer func() { mr.events.Publish(events...) }() // after unlocking
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.itemsById[id]; ok {
+		return &ErrDuplicateID{
+			ID:   id,
+			Repo: mr.path,
+		}
+	}
+	if _, ok := mr.itemsByPath[mr.pathKey(relPath)]; ok {
+		return &ErrDuplicatePath{
+			Path: relPath,
+			Repo: mr.path,
+		}
+	}
+
+	mr.addItem(id, relPath, m, time.Time{}, stat) // addition time filled in by Scan
+	events = append(events, Event{Type: EventMediaAdded, RepoID: mr.id, Media: m})
+
+	return nil
+}
+
 func (mr *mutableRepo) AddPath(path string) error {
+	if media.SubtitleCodec(filepath.Ext(path)) != "" {
+		return mr.updateSubtitles(path)
+	}
+	if media.ExtraKindOf(path) != "" {
+		return mr.updateExtras(path)
+	}
+
 	format, err := mr.detectAndCheckFormat(path)
 	if err != nil {
 		return errors.Wrap(err, "failed format check")
@@ -420,21 +1102,170 @@ func (mr *mutableRepo) AddPath(path string) error {
 		return errors.Wrap(err, "failed to discover metadata")
 	}
 
-	id := media.SanitizeID(filepath.Base(path))
+	relPath, err := mr.rel(path)
+	if err != nil {
+		return &ErrInvalidMediaPath{
+			Path: path,
+			Root: mr.path,
+		}
+	}
+
+	mr.mu.RLock()
+	id, err := mr.makeID(relPath, m)
+	mr.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
 	return mr.add(id, path, media.NewMedia(id, path, m, format))
 }
 
+func (mr *mutableRepo) Update(m media.Media) error {
+	id := m.ID()
+	relPath, err := mr.rel(m.Path())
+	if err != nil {
+		return &ErrInvalidMediaPath{
+			Path: m.Path(),
+			Root: mr.path,
+		}
+	}
+
+	var events []Event
+	defer func() { mr.events.Publish(events...) }() // after unlocking
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.itemsById[id]; !ok {
+		return &ErrMediaNotFound{
+			ID:   id,
+			Repo: mr.path,
+		}
+	}
+	if cur, ok := mr.itemsByPath[mr.pathKey(relPath)]; !ok || cur.ID() != id { // path changed
+		return &ErrDuplicateID{
+			ID:   id,
+			Repo: mr.path,
+		}
+	}
+
+	mr.addItem(id, relPath, m, mr.added[id], mr.stats[id]) // replaces the old item
+	events = append(events, Event{Type: EventMediaUpdated, RepoID: mr.id, Media: m})
+	if mr.logger != nil {
+		mr.logger.Info(
+			"updated media in repository",
+			zap.String("repo", mr.id),
+			zap.String("repo_path", mr.path),
+			zap.String("id", id),
+			zap.String("path", relPath),
+		)
+	}
+
+	return nil
+}
+
+func (mr *mutableRepo) Move(m media.Media, path string) error {
+	id := m.ID()
+	relPath, err := mr.rel(path)
+	if err != nil {
+		return &ErrInvalidMediaPath{
+			Path: path,
+			Root: mr.path,
+		}
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil { // catches non-existent files
+		return errors.Wrap(err, "failed to stat file")
+	}
+
+	var events []Event
+	defer func() { mr.events.Publish(events...) }() // after unlocking
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	cur, ok := mr.itemsById[id]
+	if !ok {
+		return &ErrMediaNotFound{
+			ID:   id,
+			Repo: mr.path,
+		}
+	}
+	if _, ok := mr.itemsByPath[mr.pathKey(relPath)]; ok {
+		return &ErrDuplicatePath{
+			Path: relPath,
+			Repo: mr.path,
+		}
+	}
+
+	oldRelPath, err := mr.rel(cur.Path())
+	if err != nil {
+		return err // shouldn't be possible
+	}
+
+	added, tags := mr.added[id], mr.tags[id]
+	_, locked := mr.locked[id]
+	m0 := media.NewMedia(id, path, cur.Meta(), cur.Format())
+	mr.removeItem(id, oldRelPath)
+	mr.addItem(id, relPath, m0, added, NewFileStat(fi))
+	if tags != nil {
+		mr.tags[id] = tags
+	}
+	if locked {
+		mr.locked[id] = struct{}{}
+	}
+	events = append(events, Event{Type: EventMediaMoved, RepoID: mr.id, Media: m0, OldPath: cur.Path()})
+	if err := mr.discoverSubtitles([]media.Media{m0}); err != nil && mr.logger != nil {
+		mr.logger.Warn(
+			"failed to discover subtitles",
+			zap.String("repo", mr.id),
+			zap.String("repo_path", mr.path),
+			zap.String("path", relPath),
+			zap.Error(err),
+		)
+	}
+	if err := mr.discoverExtras([]media.Media{m0}); err != nil && mr.logger != nil {
+		mr.logger.Warn(
+			"failed to discover extras",
+			zap.String("repo", mr.id),
+			zap.String("repo_path", mr.path),
+			zap.String("path", relPath),
+			zap.Error(err),
+		)
+	}
+	if mr.logger != nil {
+		mr.logger.Info(
+			"moved media in repository",
+			zap.String("repo", mr.id),
+			zap.String("repo_path", mr.path),
+			zap.String("id", id),
+			zap.String("old_path", oldRelPath),
+			zap.String("path", relPath),
+		)
+	}
+
+	return nil
+}
+
 func (mr *mutableRepo) Remove(m media.Media) error {
 	id := m.ID()
-	relPath, err := filepath.Rel(mr.path, m.Path())
+	relPath, err := mr.rel(m.Path())
 	if err != nil {
 		return nil // fast path: can't be made relative
 	}
 
+	var events []Event
+	defer func() { mr.events.Publish(events...) }() // after unlocking
+
 	mr.mu.Lock()
 	defer mr.mu.Unlock()
 
+	cur := mr.itemsById[id]
 	ok := mr.removeItem(id, relPath)
+	if ok {
+		events = append(events, Event{Type: EventMediaRemoved, RepoID: mr.id, Media: cur})
+	}
 	if ok && mr.logger != nil { // don't log anything if it were a no-op
 		mr.logger.Info(
 			"removed media from repository",
@@ -449,11 +1280,21 @@ func (mr *mutableRepo) Remove(m media.Media) error {
 }
 
 func (mr *mutableRepo) RemovePath(path string) error {
-	relPath, err := filepath.Rel(mr.path, path)
+	if media.SubtitleCodec(filepath.Ext(path)) != "" {
+		return mr.updateSubtitles(path)
+	}
+	if media.ExtraKindOf(path) != "" {
+		return mr.updateExtras(path)
+	}
+
+	relPath, err := mr.relPathKey(path)
 	if err != nil {
 		return nil // fast path: can't be made relative
 	}
 
+	var events []Event
+	defer func() { mr.events.Publish(events...) }() // after unlocking
+
 	mr.mu.Lock()
 	defer mr.mu.Unlock()
 
@@ -463,6 +1304,7 @@ func (mr *mutableRepo) RemovePath(path string) error {
 	}
 
 	mr.removeItem(m.ID(), relPath)
+	events = append(events, Event{Type: EventMediaRemoved, RepoID: mr.id, Media: m})
 	if mr.logger != nil {
 		mr.logger.Info(
 			"removed media from repository",
@@ -475,6 +1317,51 @@ func (mr *mutableRepo) RemovePath(path string) error {
 	return nil
 }
 
+func (mr *mutableRepo) Ignored(path string, dir bool) bool {
+	relPath, err := mr.rel(path)
+	if err != nil || !isLocal(relPath) {
+		return false // not in the repository
+	}
+
+	_, relPath = SplitRoot(mr.roots, relPath)
+	if relPath == "." {
+		return false
+	}
+
+	elems := strings.Split(filepath.ToSlash(relPath), "/")
+	for i, elem := range elems {
+		parent := i < len(elems)-1
+		if strings.HasPrefix(elem, ".") || !mr.filter.Match(strings.Join(elems[:i+1], "/"), dir || parent) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (mr *mutableRepo) BeginBatch() {
+	// changes are kept in memory, persisted by a wrapper (index.NewRepository)
+}
+
+func (mr *mutableRepo) Commit() error {
+	return nil
+}
+
+func (mr *mutableRepo) Health() Health {
+	return Health{} // availability is monitored by a wrapper (mount.NewRepository)
+}
+
+func (mr *mutableRepo) Events() *Events {
+	return mr.events
+}
+
+func (mr *mutableRepo) Revision() Revision {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	return mr.revision
+}
+
 func (mr *mutableRepo) Items() []media.Media {
 	mr.mu.RLock()
 	defer mr.mu.RUnlock()
@@ -482,13 +1369,156 @@ func (mr *mutableRepo) Items() []media.Media {
 	return maps.Values(mr.itemsById)
 }
 
-func (mr *mutableRepo) Remux(_ string, _ *media.Format) (media.Media, error) {
+func (mr *mutableRepo) Query(q *Query) ([]media.Media, int, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	return q.apply(maps.Values(mr.itemsById), func(id string) time.Time {
+		return mr.added[id]
+	}, func(id string) []string {
+		return mr.tags[id]
+	})
+}
+
+func (mr *mutableRepo) Remux(_ context.Context, _ string, _ *media.Format) (media.Media, error) {
 	return nil, &ErrUnsupportedOperation{
 		Operation: "remux",
 		Repo:      mr.id,
 	}
 }
 
+func (mr *mutableRepo) Transcode(_ context.Context, _ string, _ *media.TranscodeProfile) (media.Media, error) {
+	return nil, &ErrUnsupportedOperation{
+		Operation: "transcode",
+		Repo:      mr.id,
+	}
+}
+
+func (mr *mutableRepo) Preview(_ context.Context, _ string) (media.Media, error) {
+	return nil, &ErrUnsupportedOperation{
+		Operation: "preview",
+		Repo:      mr.id,
+	}
+}
+
+func (mr *mutableRepo) Storyboard(_ context.Context, _ string) (*storyboard.Storyboard, error) {
+	return nil, &ErrUnsupportedOperation{
+		Operation: "storyboard",
+		Repo:      mr.id,
+	}
+}
+
+func (mr *mutableRepo) Probe(_ context.Context) error {
+	return &ErrUnsupportedOperation{
+		Operation: "probe",
+		Repo:      mr.id,
+	}
+}
+
+func (mr *mutableRepo) CacheUsage() (*CacheUsage, error) {
+	return nil, &ErrUnsupportedOperation{
+		Operation: "cache usage",
+		Repo:      mr.id,
+	}
+}
+
+func (mr *mutableRepo) PurgeCache() (*CacheUsage, error) {
+	return nil, &ErrUnsupportedOperation{
+		Operation: "purge cache",
+		Repo:      mr.id,
+	}
+}
+
+func (mr *mutableRepo) ArchiveStatus(_ string) ArchiveStatus {
+	return ArchiveStatusNone
+}
+
+func (mr *mutableRepo) Archive(_ context.Context, _ string) error {
+	return &ErrUnsupportedOperation{
+		Operation: "archive",
+		Repo:      mr.id,
+	}
+}
+
+func (mr *mutableRepo) Restore(_ context.Context, _ string) error {
+	return &ErrUnsupportedOperation{
+		Operation: "restore",
+		Repo:      mr.id,
+	}
+}
+
+func (mr *mutableRepo) Subtitles(id string) ([]*media.Subtitle, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	if _, ok := mr.itemsById[id]; !ok {
+		return nil, nil
+	}
+
+	return append([]*media.Subtitle{}, mr.subtitles[id]...), nil
+}
+
+func (mr *mutableRepo) AudioTracks(id string) ([]*media.AudioTrack, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	if _, ok := mr.itemsById[id]; !ok {
+		return nil, nil
+	}
+
+	return []*media.AudioTrack{}, nil
+}
+
+func (mr *mutableRepo) Chapters(id string) ([]*media.Chapter, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	if _, ok := mr.itemsById[id]; !ok {
+		return nil, nil
+	}
+
+	return []*media.Chapter{}, nil
+}
+
+func (mr *mutableRepo) ExtractSubtitle(_ context.Context, id, trackId string) (*media.Subtitle, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	for _, subtitle := range mr.subtitles[id] {
+		if subtitle.ID == trackId {
+			return subtitle, nil // sidecar files don't need extracting
+		}
+	}
+
+	return nil, nil
+}
+
+// updateSubtitles rediscovers the sidecar subtitle tracks of media next to an added or removed subtitle file.
+func (mr *mutableRepo) updateSubtitles(path string) error {
+	if _, err := mr.rel(path); err != nil {
+		return &ErrInvalidMediaPath{
+			Path: path,
+			Root: mr.path,
+		}
+	}
+
+	var events []Event
+	defer func() { mr.events.Publish(events...) }() // after unlocking
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	items, err := mr.discoverDirSubtitles(filepath.Dir(path))
+	if err != nil {
+		return errors.Wrap(err, "failed to discover subtitles")
+	}
+
+	for _, item := range items {
+		events = append(events, Event{Type: EventMediaUpdated, RepoID: mr.id, Media: item})
+	}
+	return nil
+}
+
 func (mr *mutableRepo) Source() meta.Source {
 	return mr.metaSource
 }
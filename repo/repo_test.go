@@ -0,0 +1,727 @@
+package repo
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"golang.org/x/exp/slices"
+	"golang.org/x/text/language"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMutableRepo_Find(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Shows"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(root, "Shows", "Test.mkv")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(media.NewMedia("test-mkv", path, nil, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []string{path, "Shows/Test.mkv", "./Shows//Test.mkv", "Shows/../Shows/Test.mkv", filepath.Join(root, ".", "Shows", "Test.mkv")} {
+		if r.Find(p) == nil {
+			t.Errorf("media not found for path %s", p)
+		}
+	}
+
+	if caseInsensitive(root) && r.Find("shows/test.MKV") == nil {
+		t.Errorf("media not found for differently cased path on case-insensitive filesystem")
+	}
+}
+
+func TestMutableRepo_Subtitles(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"Movie.mkv", "Movie.Extended.mkv", "Movie.en.srt", "Movie.Extended.cs.forced.ass", "Other.srt"} {
+		if err := os.WriteFile(filepath.Join(root, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, name := range map[string]string{"movie": "Movie.mkv", "movie-extended": "Movie.Extended.mkv"} {
+		if err := r.Add(media.NewMedia(id, filepath.Join(root, name), nil, media.FormatMKV)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]string{"movie": "file-en-srt:en", "movie-extended": "file-cs-forced-ass:cs"}
+	for id, expected := range tests {
+		subtitles, err := r.Subtitles(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(subtitles) != 1 || subtitles[0].ID+":"+subtitles[0].Language.String() != expected {
+			t.Errorf("expected subtitle %s for %s, got %v", expected, id, subtitles)
+		}
+	}
+
+	if err := os.Remove(filepath.Join(root, "Movie.en.srt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RemovePath(filepath.Join(root, "Movie.en.srt")); err != nil {
+		t.Fatal(err)
+	}
+	if subtitles, _ := r.Subtitles("movie"); len(subtitles) != 0 {
+		t.Errorf("expected no subtitles after removal, got %v", subtitles)
+	}
+}
+
+func TestMutableRepo_Revision(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "Test.mkv")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := media.NewMedia("test-mkv", path, nil, media.FormatMKV)
+	if err := r.Add(m); err != nil {
+		t.Fatal(err)
+	}
+	if rev := r.Revision(); rev.Number != 1 {
+		t.Errorf("expected revision 1 after adding, got %d", rev.Number)
+	}
+
+	for i := 0; i < 2; i++ { // second removal is a no-op
+		if err := r.Remove(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if rev := r.Revision(); rev.Number != 2 {
+		t.Errorf("expected revision 2 after removing, got %d", rev.Number)
+	}
+}
+
+func TestMutableRepo_Update(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"Test.mkv", "Other.mkv"} {
+		if err := os.WriteFile(filepath.Join(root, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(root, "Test.mkv")
+	if err := r.Add(media.NewMedia("test-mkv", path, nil, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+
+	m := meta.NewMetadata(meta.TypeMovie, "Test", "Test", "", time.Time{}, 0, nil)
+	if err := r.Update(media.NewMedia("test-mkv", path, m, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Find("Test.mkv"); got == nil || got.Meta() != m {
+		t.Errorf("expected updated metadata, got %v", got)
+	}
+
+	var notFound *ErrMediaNotFound
+	if err := r.Update(media.NewMedia("other", path, nil, media.FormatMKV)); !errors.As(err, &notFound) {
+		t.Errorf("expected ErrMediaNotFound, got %v", err)
+	}
+
+	var duplicateId *ErrDuplicateID
+	if err := r.Update(media.NewMedia("test-mkv", filepath.Join(root, "Other.mkv"), nil, media.FormatMKV)); !errors.As(err, &duplicateId) {
+		t.Errorf("expected ErrDuplicateID for a changed path, got %v", err)
+	}
+}
+
+func TestMutableRepo_Move(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"Test.mkv", "Other.mkv"} {
+		if err := os.WriteFile(filepath.Join(root, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		path = filepath.Join(root, "Test.mkv")
+		m    = meta.NewMetadata(meta.TypeMovie, "Test", "Test", "", time.Time{}, 0, nil)
+	)
+	for _, item := range []media.Media{
+		media.NewMedia("test-mkv", path, m, media.FormatMKV),
+		media.NewMedia("other-mkv", filepath.Join(root, "Other.mkv"), nil, media.FormatMKV),
+	} {
+		if err := r.Add(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var events []Event
+	r.Events().Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+
+	newPath := filepath.Join(root, "Test (2000).mkv")
+	if err := os.Rename(path, newPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Move(r.Get("test-mkv"), newPath); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Find(newPath); got == nil || got.ID() != "test-mkv" || got.Meta() != m {
+		t.Errorf("expected moved media with its ID and metadata, got %v", got)
+	}
+	if r.Find(path) != nil {
+		t.Error("expected old path to be gone")
+	}
+	if len(events) != 1 || events[0].Type != EventMediaMoved || events[0].OldPath != path {
+		t.Errorf("expected a media moved event, got %+v", events)
+	}
+
+	var duplicatePath *ErrDuplicatePath
+	if err := r.Move(r.Get("test-mkv"), filepath.Join(root, "Other.mkv")); !errors.As(err, &duplicatePath) {
+		t.Errorf("expected ErrDuplicatePath, got %v", err)
+	}
+}
+
+func TestMutableRepo_Tags(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"Test.mkv", "Other.mkv"} {
+		if err := os.WriteFile(filepath.Join(root, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(root, "Test.mkv")
+	for _, item := range []media.Media{
+		media.NewMedia("test-mkv", path, nil, media.FormatMKV),
+		media.NewMedia("other-mkv", filepath.Join(root, "Other.mkv"), nil, media.FormatMKV),
+	} {
+		if err := r.Add(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := r.SetTags("test-mkv", []string{" Kids", "4k-demo", "kids", ""}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SetLocked("test-mkv", true); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Tags("test-mkv"); !slices.Equal(got, []string{"4k-demo", "kids"}) {
+		t.Errorf("expected normalized tags, got %v", got)
+	}
+
+	var invalidTag *ErrInvalidTag
+	if err := r.SetTags("test-mkv", []string{"not a tag"}); !errors.As(err, &invalidTag) {
+		t.Errorf("expected ErrInvalidTag, got %v", err)
+	}
+	var notFound *ErrMediaNotFound
+	if err := r.SetTags("missing", []string{"kids"}); !errors.As(err, &notFound) {
+		t.Errorf("expected ErrMediaNotFound, got %v", err)
+	}
+
+	m := meta.NewMetadata(meta.TypeMovie, "Test", "Test", "", time.Time{}, 0, nil)
+	if err := r.Update(media.NewMedia("test-mkv", path, m, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+	newPath := filepath.Join(root, "Test (2000).mkv")
+	if err := os.Rename(path, newPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Move(r.Get("test-mkv"), newPath); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Tags("test-mkv"); !slices.Equal(got, []string{"4k-demo", "kids"}) {
+		t.Errorf("expected tags to be kept across updates and moves, got %v", got)
+	}
+	if !r.Locked("test-mkv") || r.Locked("other-mkv") {
+		t.Error("expected lock to be kept across updates and moves")
+	}
+
+	page, total, err := r.Query(&Query{Tag: "Kids"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || page[0].ID() != "test-mkv" {
+		t.Errorf("expected only tagged media, got %v of %d", page, total)
+	}
+
+	if err := r.SetTags("test-mkv", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Tags("test-mkv"); got != nil {
+		t.Errorf("expected no tags, got %v", got)
+	}
+}
+
+func TestMutableRepo_Query(t *testing.T) {
+	root := t.TempDir()
+
+	r, err := NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := []struct {
+		id    string
+		meta  meta.Metadata
+		added time.Time
+	}{
+		{"b", meta.WithConfidence(meta.NewMetadata(meta.TypeMovie, "Bravo", "", "", time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC), 0, nil), 0.3), time.Unix(3, 0)},
+		{"a", meta.NewMetadata(meta.TypeMovie, "alpha", "", "", time.Date(2002, 1, 1, 0, 0, 0, 0, time.UTC), 0, nil), time.Unix(2, 0)},
+		{"c", nil, time.Unix(1, 0)},
+	}
+	for _, item := range items {
+		path := filepath.Join(root, item.id+".mkv")
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, item.added, item.added); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Add(media.NewMedia(item.id, path, item.meta, media.FormatMKV)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	movie := meta.TypeMovie
+	tests := []struct {
+		name  string
+		query Query
+		ids   []string
+		total int
+	}{
+		{"title", Query{}, []string{"a", "b", "c"}, 3},
+		{"release date descending", Query{Sort: SortReleaseDate, Descending: true}, []string{"a", "b", "c"}, 3},
+		{"added", Query{Sort: SortAdded}, []string{"c", "a", "b"}, 3},
+		{"type", Query{Type: &movie}, []string{"a", "b"}, 2},
+		{"year", Query{Year: 2001}, []string{"b"}, 1},
+		{"page", Query{Offset: 1, Limit: 1}, []string{"b"}, 3},
+		{"page past end", Query{Offset: 5}, []string{}, 3},
+		{"unmatched", Query{Match: MatchUnmatched}, []string{"c"}, 1},
+		{"low confidence", Query{Match: MatchLowConfidence}, []string{"b"}, 1},
+		{"filter", Query{Filter: func(m media.Media) bool { return m.ID() != "a" }}, []string{"b", "c"}, 2},
+		{"audio language", Query{AudioLanguage: language.English}, []string{}, 0}, // not probed
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, total, err := r.Query(&tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ids := make([]string, len(page))
+			for i, m := range page {
+				ids[i] = m.ID()
+			}
+			if total != tt.total || strings.Join(ids, ",") != strings.Join(tt.ids, ",") {
+				t.Errorf("expected %v of %d, got %v of %d", tt.ids, tt.total, ids, total)
+			}
+		})
+	}
+}
+
+func TestMutableRepo_AddUnverified(t *testing.T) {
+	root := t.TempDir()
+	for name, modified := range map[string]time.Time{"a.mkv": time.Unix(2, 0), "b.mkv": time.Unix(1, 0), "a.en.srt": time.Unix(0, 0)} {
+		path := filepath.Join(root, name)
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := r.AddUnverified(media.NewMedia(id, filepath.Join(root, id+".mkv"), nil, media.FormatMKV), FileStat{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if subtitles, _ := r.Subtitles("a"); len(subtitles) != 0 {
+		t.Errorf("expected no subtitles before scan, got %v", subtitles)
+	}
+
+	if err := r.Scan(ScanIncremental); err != nil {
+		t.Fatal(err)
+	}
+	if subtitles, _ := r.Subtitles("a"); len(subtitles) != 1 {
+		t.Errorf("expected a subtitle after scan, got %v", subtitles)
+	}
+
+	page, _, err := r.Query(&Query{Sort: SortAdded})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || page[0].ID() != "b" {
+		t.Errorf("expected media sorted by modification time after scan, got %v", page)
+	}
+}
+
+func TestMutableRepo_Events(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "a.mkv"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var types []EventType
+	unsubscribe := r.Events().Subscribe(func(e Event) {
+		r.Items() // deadlocks if called with the repository locked
+		types = append(types, e.Type)
+	})
+
+	path := filepath.Join(root, "a.mkv")
+	if err := r.Add(media.NewMedia("a", path, nil, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Scan(ScanIncremental); err != nil { // nothing new
+		t.Fatal(err)
+	}
+
+	m := r.Get("a")
+	if err := r.Update(media.NewMedia(m.ID(), m.Path(), nil, m.Format())); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Remove(m); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Remove(m); err != nil { // no-op
+		t.Fatal(err)
+	}
+
+	unsubscribe()
+	if err := r.Add(m); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []EventType{EventMediaAdded, EventScanCompleted, EventMediaUpdated, EventMediaRemoved}
+	if !slices.Equal(types, expected) {
+		t.Errorf("expected events %v, got %v", expected, types)
+	}
+}
+
+func TestMutableRepo_ScanIncremental(t *testing.T) {
+	var (
+		root = t.TempDir()
+		path = filepath.Join(root, "a.mkv")
+		mkv  = []byte("\x1a\x45\xdf\xa3\xa3\x42\x86\x81\x01\x42\xf7\x81\x01\x42\xf2\x81\x04\x42\xf3\x81\x08\x42\x82\x88matroska")
+	)
+	if err := os.WriteFile(path, mkv, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Scan(ScanIncremental); err != nil {
+		t.Fatal(err)
+	}
+
+	m := r.Find(path)
+	if m == nil {
+		t.Fatal("expected media to be discovered")
+	}
+	if r.FileStat(m.ID()).Size != int64(len(mkv)) {
+		t.Errorf("expected file stat of size %d, got %+v", len(mkv), r.FileStat(m.ID()))
+	}
+
+	// replaced by a non-media file of the same size and modification time, not detected again
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, bytes.Repeat([]byte("a"), len(mkv)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, fi.ModTime(), fi.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Scan(ScanIncremental); err != nil {
+		t.Fatal(err)
+	}
+	if r.Find(path) == nil {
+		t.Fatal("expected unchanged file to be skipped by an incremental scan")
+	}
+
+	if err := r.Scan(ScanFull); err != nil {
+		t.Fatal(err)
+	}
+	if r.Find(path) != nil {
+		t.Error("expected file that isn't media anymore to be removed by a full scan")
+	}
+}
+
+func TestMutableRepo_ScanIDCollision(t *testing.T) {
+	var (
+		root = t.TempDir()
+		mkv  = []byte("\x1a\x45\xdf\xa3\xa3\x42\x86\x81\x01\x42\xf7\x81\x01\x42\xf2\x81\x04\x42\xf3\x81\x08\x42\x82\x88matroska")
+	)
+	for _, dir := range []string{"A", "B"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, dir, "episode-1.mkv"), mkv, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Scan(ScanIncremental); err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := r.Find(filepath.Join(root, "A", "episode-1.mkv")), r.Find(filepath.Join(root, "B", "episode-1.mkv"))
+	if a == nil || b == nil {
+		t.Fatal("expected both media to be discovered")
+	}
+	if a.ID() != "episode-1-mkv" || b.ID() != "episode-1-mkv-2" {
+		t.Errorf("expected unique IDs, got %s and %s", a.ID(), b.ID())
+	}
+}
+
+func TestMutableRepo_ScanMultiRoot(t *testing.T) {
+	var (
+		roots = []string{t.TempDir(), t.TempDir()}
+		mkv   = []byte("\x1a\x45\xdf\xa3\xa3\x42\x86\x81\x01\x42\xf7\x81\x01\x42\xf2\x81\x04\x42\xf3\x81\x08\x42\x82\x88matroska")
+	)
+	for i, root := range roots {
+		if err := os.WriteFile(filepath.Join(root, fmt.Sprintf("movie-%d.mkv", i)), mkv, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := NewMultiRootRepository("test", "Test", roots, meta.NewDummySource(), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Scan(ScanIncremental); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, root := range roots {
+		path := filepath.Join(root, fmt.Sprintf("movie-%d.mkv", i))
+		if r.Find(path) == nil {
+			t.Errorf("expected media in directory %d to be discovered", i)
+		}
+		rel, err := RelPath(roots, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if AbsPath(roots, rel) != path {
+			t.Errorf("expected relative path %s to resolve to %s, got %s", rel, path, AbsPath(roots, rel))
+		}
+	}
+	if rel, _ := RelPath(roots, filepath.Join(roots[1], "movie-1.mkv")); rel != filepath.Join("#1", "movie-1.mkv") {
+		t.Errorf("expected prefixed relative path, got %s", rel)
+	}
+
+	if _, err := NewMultiRootRepository("test", "Test", []string{roots[0], filepath.Join(roots[0], "nested")}, meta.NewDummySource(), nil, nil, nil); err == nil {
+		t.Error("expected overlapping directories to be rejected")
+	}
+}
+
+func TestMutableRepo_Extras(t *testing.T) {
+	var (
+		root = t.TempDir()
+		mkv  = []byte("\x1a\x45\xdf\xa3\xa3\x42\x86\x81\x01\x42\xf7\x81\x01\x42\xf2\x81\x04\x42\xf3\x81\x08\x42\x82\x88matroska")
+	)
+	files := map[string][]byte{
+		"Movies/Movie.mkv":                 mkv,
+		"Movies/Movie-trailer.mkv":         mkv,
+		"Movies/Other.mkv":                 mkv,
+		"Show/theme.mp3":                   []byte("ID3"),
+		"Show/trailers/Teaser.mkv":         mkv,
+		"Show/Season 01/Show S01E01.mkv":   mkv,
+		"Show/Season 01/.hidden-theme.mp3": []byte("ID3"),
+	}
+	for name, data := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Scan(ScanIncremental); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(r.Items()); n != 3 {
+		t.Fatalf("expected extras not to be discovered as media, got %d media", n)
+	}
+
+	extraIds := func(path string) []string {
+		extras, err := r.Extras(r.Find(filepath.Join(root, filepath.FromSlash(path))).ID())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := make([]string, len(extras))
+		for i, extra := range extras {
+			ids[i] = string(extra.Kind) + ":" + extra.ID
+		}
+		slices.Sort(ids)
+		return ids
+	}
+	if ids := extraIds("Movies/Movie.mkv"); !slices.Equal(ids, []string{"trailer:movie-trailer-mkv"}) {
+		t.Errorf("unexpected movie extras %v", ids)
+	}
+	if ids := extraIds("Movies/Other.mkv"); len(ids) != 0 {
+		t.Errorf("expected no extras of another movie, got %v", ids)
+	}
+	if ids := extraIds("Show/Season 01/Show S01E01.mkv"); !slices.Equal(ids, []string{"theme:theme-mp3", "trailer:trailers-teaser-mkv"}) {
+		t.Errorf("unexpected episode extras %v", ids)
+	}
+
+	if err := os.Remove(filepath.Join(root, "Show", "theme.mp3")); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RemovePath(filepath.Join(root, "Show", "theme.mp3")); err != nil {
+		t.Fatal(err)
+	}
+	if ids := extraIds("Show/Season 01/Show S01E01.mkv"); !slices.Equal(ids, []string{"trailer:trailers-teaser-mkv"}) {
+		t.Errorf("expected removed theme to be gone, got %v", ids)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	f, err := NewFilter([]string{"**/sample/*", "*.nfo", "/Downloads"}, []string{"*.mkv", "*.mp4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		dir  bool
+		want bool
+	}{
+		{path: "Movie.mkv", want: true},
+		{path: "Movies/Movie.mp4", want: true},
+		{path: "Movies/Movie.avi", want: false},
+		{path: "Movies/Movie.nfo", want: false},
+		{path: "Movies/Movie/sample/Sample.mkv", want: false},
+		{path: "sample/Sample.mkv", want: false},
+		{path: "Movies/sample", dir: true, want: true},
+		{path: "Downloads", dir: true, want: false},
+		{path: "Movies/Downloads", dir: true, want: true},
+		{path: "Movies", dir: true, want: true},
+	}
+	for _, tt := range tests {
+		if got := f.Match(tt.path, tt.dir); got != tt.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.dir, got, tt.want)
+		}
+	}
+
+	if _, err := NewFilter([]string{"[a-"}, nil); err == nil {
+		t.Error("expected malformed pattern to be rejected")
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	requested := CapabilityWatch | CapabilityRemux | CapabilityTranscode
+	available, unavailable := Negotiate(requested, func(c Capability) string {
+		if c == CapabilityTranscode {
+			return "no encoders"
+		}
+
+		return ""
+	})
+	if available != CapabilityWatch|CapabilityRemux {
+		t.Errorf("expected watch and remux capabilities, got %s", available)
+	}
+	if len(unavailable) != 1 || unavailable[CapabilityTranscode] != "no encoders" {
+		t.Errorf("expected transcode capability to be unavailable, got %v", unavailable)
+	}
+
+	if _, unavailable := Negotiate(CapabilityIndex, func(_ Capability) string { return "" }); unavailable != nil {
+		t.Errorf("expected no unavailable capabilities, got %v", unavailable)
+	}
+}
+
+func TestMutableRepo_ScanFilter(t *testing.T) {
+	var (
+		root = t.TempDir()
+		mkv  = []byte("\x1a\x45\xdf\xa3\xa3\x42\x86\x81\x01\x42\xf7\x81\x01\x42\xf2\x81\x04\x42\xf3\x81\x08\x42\x82\x88matroska")
+	)
+	for _, name := range []string{"Movie/Movie.mkv", "Movie/Sample/Sample.mkv"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, mkv, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sample := filepath.Join(root, "Movie", "Sample", "Sample.mkv")
+	r, err := NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddPath(sample); err != nil { // indexed before the pattern was configured
+		t.Fatal(err)
+	}
+	r.(*mutableRepo).filter, err = NewFilter([]string{"Sample"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !r.Ignored(sample, false) || !r.Ignored(filepath.Join(root, "Movie", "Sample"), true) {
+		t.Error("expected file in ignored directory to be ignored")
+	}
+	if err := r.Scan(ScanIncremental); err != nil {
+		t.Fatal(err)
+	}
+	if r.Find(sample) != nil {
+		t.Error("expected ignored media to be removed")
+	}
+	if r.Find(filepath.Join(root, "Movie", "Movie.mkv")) == nil {
+		t.Error("expected media to be discovered")
+	}
+}
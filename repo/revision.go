@@ -0,0 +1,18 @@
+package repo
+
+import "time"
+
+// Revision is a position in the change history of a repository, it changes with every media addition or removal.
+// Revisions are not persisted, the history starts over when the repository is created again.
+type Revision struct {
+	// Number is the number of changes since creating the repository.
+	Number uint64
+	// Modified is the time of the last change, the time of creating the repository if there were none.
+	Modified time.Time
+}
+
+// bump records a change made at the current time.
+func (r *Revision) bump() {
+	r.Number++
+	r.Modified = time.Now()
+}
@@ -0,0 +1,40 @@
+package repo
+
+import (
+	"io/fs"
+	"time"
+)
+
+// ScanMode is the thoroughness of a repository scan.
+type ScanMode uint8
+
+const (
+	// ScanIncremental detects the formats of new files and files that changed since they were last detected,
+	// by their size and modification time (FileStat), unchanged files are skipped.
+	ScanIncremental ScanMode = iota
+	// ScanFull detects the formats of all files, such as after the format detection was improved.
+	ScanFull
+)
+
+// FileStat is the size and modification time of a file, used for telling whether it changed.
+type FileStat struct {
+	// Size is the file size in bytes.
+	Size int64
+	// ModTime is the file modification time.
+	ModTime time.Time
+}
+
+// NewFileStat creates a FileStat from file info.
+func NewFileStat(fi fs.FileInfo) FileStat {
+	return FileStat{Size: fi.Size(), ModTime: fi.ModTime()}
+}
+
+// IsZero checks whether the stat is unknown.
+func (s FileStat) IsZero() bool {
+	return s.Size == 0 && s.ModTime.IsZero()
+}
+
+// Equal checks whether two stats are of the same file contents.
+func (s FileStat) Equal(other FileStat) bool {
+	return s.Size == other.Size && s.ModTime.Equal(other.ModTime)
+}
@@ -0,0 +1,135 @@
+package repo
+
+import (
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media"
+	"golang.org/x/text/language"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// findSubtitles finds the sidecar subtitle tracks of media among the entries of its directory.
+// A sidecar file belongs to media with the longest matching name stem, optionally followed by dot-separated tags.
+// Example: "Movie.en.srt" belongs to "Movie.mkv", "Movie.Extended.en.srt" belongs to "Movie.Extended.mkv" if present.
+func findSubtitles(mediaPath string, entries []fs.DirEntry, foldCase bool) []*media.Subtitle {
+	fold := func(s string) string {
+		if foldCase {
+			return strings.ToLower(s)
+		}
+
+		return s
+	}
+
+	var (
+		mediaStem = fold(stem(filepath.Base(mediaPath)))
+		stems     []string // stems of other non-subtitle files, possible owners
+	)
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && media.SubtitleCodec(filepath.Ext(name)) == "" {
+			stems = append(stems, fold(stem(name)))
+		}
+	}
+
+	var subtitles []*media.Subtitle
+	for _, entry := range entries {
+		var (
+			name  = entry.Name()
+			ext   = filepath.Ext(name)
+			codec = media.SubtitleCodec(ext)
+		)
+		if entry.IsDir() || codec == "" || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		subStem := fold(stem(name))
+		if owner := subtitleOwner(subStem, stems); owner != mediaStem {
+			continue
+		}
+
+		tags := strings.TrimPrefix(subStem[len(mediaStem):], ".")
+		subtitles = append(subtitles, &media.Subtitle{
+			ID:       "file-" + media.SanitizeID(strings.TrimPrefix(tags+ext, ".")),
+			Codec:    codec,
+			Language: subtitleLanguage(tags),
+			Path:     filepath.Join(filepath.Dir(mediaPath), name),
+			Stream:   -1,
+		})
+	}
+
+	return subtitles
+}
+
+// subtitleOwner returns the longest stem that a subtitle file stem belongs to, empty if none.
+func subtitleOwner(subStem string, stems []string) string {
+	var owner string
+	for _, s := range stems {
+		if len(s) > len(owner) && (subStem == s || strings.HasPrefix(subStem, s+".")) {
+			owner = s
+		}
+	}
+
+	return owner
+}
+
+// subtitleLanguage parses the language of a sidecar subtitle file from its dot-separated name tags ("en.forced").
+func subtitleLanguage(tags string) language.Tag {
+	for _, tag := range strings.Split(tags, ".") {
+		if len(tag) < 2 || len(tag) > 3 && !strings.Contains(tag, "-") { // not a language code, like "forced"
+			continue
+		}
+		if lang, err := language.Parse(tag); err == nil {
+			return lang
+		}
+	}
+
+	return language.Und
+}
+
+func stem(name string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// discoverSubtitles finds the sidecar subtitle tracks of media, mu must be held.
+func (mr *mutableRepo) discoverSubtitles(items []media.Media) error {
+	entries := make(map[string][]fs.DirEntry) // directory -> entries
+	for _, item := range items {
+		var (
+			path = item.Path()
+			dir  = filepath.Dir(path)
+		)
+
+		dirEntries, ok := entries[dir]
+		if !ok {
+			var err error
+			if dirEntries, err = os.ReadDir(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+
+			entries[dir] = dirEntries
+		}
+
+		if subtitles := findSubtitles(path, dirEntries, mr.foldCase); len(subtitles) > 0 {
+			mr.subtitles[item.ID()] = subtitles
+		} else {
+			delete(mr.subtitles, item.ID())
+		}
+	}
+
+	return nil
+}
+
+// discoverDirSubtitles finds the sidecar subtitle tracks of all media in a directory, mu must be held.
+// The media in the directory is returned.
+func (mr *mutableRepo) discoverDirSubtitles(dir string) ([]media.Media, error) {
+	var items []media.Media
+	for _, item := range mr.itemsById {
+		if mr.pathKey(filepath.Dir(item.Path())) == mr.pathKey(dir) {
+			items = append(items, item)
+		}
+	}
+
+	return items, mr.discoverSubtitles(items)
+}
@@ -0,0 +1,311 @@
+package trash
+
+import (
+	"encoding/json"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// janitorInterval is the period between removals of expired items.
+const janitorInterval = time.Hour
+
+// Item is removed media whose file is held in a trash bin.
+type Item struct {
+	// Media is the removed media, its path is the original path relative to the repository's roots (repo.RelPath).
+	Media *media.BasicMedia `json:"media"`
+	// File is the name of the media file in the trash directory of its root.
+	File string `json:"file"`
+	// Trashed is the time of the media being removed.
+	Trashed time.Time `json:"trashed"`
+	// Tags are the user-defined tags of the media, given back on restoring it.
+	Tags []string `json:"tags,omitempty"`
+	// Locked is whether the media was locked against automatic changes, it's locked again on restoring it.
+	Locked bool `json:"locked,omitempty"`
+}
+
+// Bin is a trash bin of a repository, the files of removed media are moved to a ".katana/trash" directory in their root
+// instead of being deleted, until they're restored or they expire after a retention period.
+// The bin's manifest is kept in the trash directory of the repository's first root.
+type Bin struct {
+	repo      repo.MutableRepository
+	retention time.Duration
+	path      string // manifest file
+	logger    *zap.Logger
+
+	mu    sync.Mutex
+	items map[string]*Item // media ID -> item
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewBin creates a trash bin of a repository and starts removing items older than retention, a zero retention keeps them forever.
+func NewBin(r repo.MutableRepository, retention time.Duration, logger *zap.Logger) (*Bin, error) {
+	b := &Bin{
+		repo:      r,
+		retention: retention,
+		path:      filepath.Join(trashDir(r.Path()), "trash.json"),
+		logger:    logger,
+		items:     make(map[string]*Item),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if err := b.load(); err != nil {
+		return nil, err
+	}
+
+	go b.janitor()
+	return b, nil
+}
+
+// trashDir returns the trash directory of a repository root.
+func trashDir(root string) string {
+	return filepath.Join(root, ".katana", "trash")
+}
+
+func (b *Bin) load() error {
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return errors.Wrap(err, "failed to read trash manifest")
+	}
+
+	var items []*Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return errors.Wrap(err, "failed to unmarshal trash manifest")
+	}
+	for _, item := range items {
+		b.items[item.Media.ID()] = item
+	}
+
+	return nil
+}
+
+// save saves the manifest, mu must be held.
+func (b *Bin) save() error {
+	data, err := json.Marshal(maps.Values(b.items))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal trash manifest")
+	}
+	if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return errors.Wrap(err, "failed to write trash manifest")
+	}
+	if err := os.Rename(tmp, b.path); err != nil {
+		return errors.Wrap(err, "failed to replace trash manifest")
+	}
+
+	return nil
+}
+
+// filePath returns the absolute path of an item's file in the trash.
+func (b *Bin) filePath(item *Item) string {
+	root, _ := repo.SplitRoot(b.repo.Roots(), item.Media.Path())
+	return filepath.Join(trashDir(root), item.File)
+}
+
+// Put removes media from the repository and moves its file to the trash.
+// Media trashed earlier under the same ID is replaced, its file is deleted.
+func (b *Bin) Put(m media.Media) error {
+	relPath, err := repo.RelPath(b.repo.Roots(), m.Path())
+	if err != nil {
+		return err
+	}
+
+	var (
+		root, _ = repo.SplitRoot(b.repo.Roots(), relPath)
+		item    = &Item{
+			Media:   media.NewMedia(m.ID(), relPath, m.Meta(), m.Format()).(*media.BasicMedia),
+			File:    m.ID() + filepath.Ext(m.Path()),
+			Trashed: time.Now(),
+			Tags:    b.repo.Tags(m.ID()),
+			Locked:  b.repo.Locked(m.ID()),
+		}
+		dst = filepath.Join(trashDir(root), item.File)
+	)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if prev, ok := b.items[m.ID()]; ok {
+		if err := os.Remove(b.filePath(prev)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return errors.Wrap(err, "failed to delete previously trashed file")
+		}
+		delete(b.items, m.ID())
+	}
+
+	// remove before moving the file, cache files are found by the file contents
+	if err := b.repo.Remove(m); err != nil {
+		return errors.Wrap(err, "failed to remove media")
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+	if err := os.Rename(m.Path(), dst); err != nil { // the trash is on the same filesystem as the root
+		return errors.Wrap(err, "failed to move media file to trash")
+	}
+
+	b.items[m.ID()] = item
+	if b.logger != nil {
+		b.logger.Info("moved media to trash", zap.String("repo", b.repo.ID()), zap.String("id", m.ID()), zap.String("path", relPath))
+	}
+
+	return b.save()
+}
+
+// Retention returns the duration for which trashed media is kept, zero if it's kept forever.
+func (b *Bin) Retention() time.Duration {
+	return b.retention
+}
+
+// Items returns the trashed media, most recently removed first.
+func (b *Bin) Items() []*Item {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	items := maps.Values(b.items)
+	slices.SortFunc(items, func(a, b *Item) int {
+		return b.Trashed.Compare(a.Trashed)
+	})
+
+	return items
+}
+
+// Restore moves the file of trashed media back to its original path and adds the media to the repository again,
+// keeping its ID and metadata. Returns the restored media or nil, if the ID wasn't found.
+// repo.ErrDuplicateID or repo.ErrDuplicatePath is returned if the ID or the path were taken in the meantime.
+func (b *Bin) Restore(id string) (media.Media, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	item, ok := b.items[id]
+	if !ok {
+		return nil, nil
+	}
+
+	path := repo.AbsPath(b.repo.Roots(), item.Media.Path())
+	if b.repo.Get(id) != nil {
+		return nil, &repo.ErrDuplicateID{ID: id, Repo: b.repo.Path()}
+	}
+	if _, err := os.Lstat(path); !errors.Is(err, fs.ErrNotExist) {
+		return nil, &repo.ErrDuplicatePath{Path: item.Media.Path(), Repo: b.repo.Path()}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, errors.Wrap(err, "failed to make directories")
+	}
+	if err := os.Rename(b.filePath(item), path); err != nil {
+		return nil, errors.Wrap(err, "failed to move media file from trash")
+	}
+
+	m := media.NewMedia(id, path, item.Media.Meta(), item.Media.Format())
+	if err := b.repo.Add(m); err != nil {
+		var duplicatePath *repo.ErrDuplicatePath
+		if !errors.As(err, &duplicatePath) {
+			return nil, errors.Wrap(err, "failed to add media")
+		}
+
+		// added by a filesystem watcher already, the metadata is restored
+		if cur := b.repo.Find(path); cur != nil && cur.ID() == id {
+			if err := b.repo.Update(m); err != nil {
+				return nil, errors.Wrap(err, "failed to update media")
+			}
+		} else if cur != nil {
+			m = cur
+		}
+	}
+
+	if len(item.Tags) > 0 {
+		if err := b.repo.SetTags(m.ID(), item.Tags); err != nil {
+			return nil, errors.Wrap(err, "failed to restore media tags")
+		}
+	}
+	if item.Locked {
+		if err := b.repo.SetLocked(m.ID(), true); err != nil {
+			return nil, errors.Wrap(err, "failed to restore media lock")
+		}
+	}
+
+	delete(b.items, id)
+	if b.logger != nil {
+		b.logger.Info("restored media from trash", zap.String("repo", b.repo.ID()), zap.String("id", id), zap.String("path", item.Media.Path()))
+	}
+
+	return m, b.save()
+}
+
+// Expire deletes the files of items trashed before a time, returns the number of deleted items.
+func (b *Bin) Expire(before time.Time) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var n int
+	for id, item := range b.items {
+		if !item.Trashed.Before(before) {
+			continue
+		}
+
+		if err := os.Remove(b.filePath(item)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return n, errors.Wrap(err, "failed to delete trashed file")
+		}
+
+		delete(b.items, id)
+		n++
+	}
+	if n == 0 {
+		return 0, nil
+	}
+
+	return n, b.save()
+}
+
+func (b *Bin) janitor() {
+	defer close(b.done)
+	if b.retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(janitorInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := b.Expire(time.Now().Add(-b.retention))
+		if b.logger != nil {
+			if err != nil {
+				b.logger.Error("failed to empty expired trash", zap.String("repo", b.repo.ID()), zap.Error(err))
+			} else if n > 0 {
+				b.logger.Info("deleted expired media from trash", zap.String("repo", b.repo.ID()), zap.Int("count", n))
+			}
+		}
+
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops removing expired items.
+func (b *Bin) Close() error {
+	close(b.stop)
+	<-b.done
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package trash
+
+import (
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBin_PutRestore(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "Movie.mkv")
+	if err := os.WriteFile(path, []byte("movie"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(media.NewMedia("movie", path, nil, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := NewBin(r, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Put(r.Get("movie")); err != nil {
+		t.Fatal(err)
+	}
+	if r.Get("movie") != nil {
+		t.Error("trashed media still in repository")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("trashed file still in place")
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err = NewBin(r, 0, nil) // reloaded from the manifest
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	if items := b.Items(); len(items) != 1 || items[0].Media.Path() != "Movie.mkv" {
+		t.Fatalf("expected trashed Movie.mkv, got %v", items)
+	}
+
+	m, err := b.Restore("movie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m == nil || m.ID() != "movie" || r.Get("movie") == nil {
+		t.Fatal("media not restored")
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "movie" {
+		t.Errorf("file not restored: %v", err)
+	}
+	if len(b.Items()) != 0 {
+		t.Error("restored media still in trash")
+	}
+}
+
+func TestBin_Expire(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "Movie.mkv")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(media.NewMedia("movie", path, nil, media.FormatMKV)); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := NewBin(r, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	if err := b.Put(r.Get("movie")); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := b.Expire(time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Errorf("expected nothing expired, got %d (%v)", n, err)
+	}
+	if n, err := b.Expire(time.Now().Add(time.Hour)); err != nil || n != 1 {
+		t.Errorf("expected 1 expired item, got %d (%v)", n, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".katana", "trash", "movie.mkv")); !os.IsNotExist(err) {
+		t.Error("expired file not deleted")
+	}
+	if m, err := b.Restore("movie"); err != nil || m != nil {
+		t.Errorf("expected expired media not to be restorable, got %v (%v)", m, err)
+	}
+}
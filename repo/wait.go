@@ -0,0 +1,56 @@
+package repo
+
+import "context"
+
+// noWaitKey is the context key of the no-wait flag.
+type noWaitKey struct{}
+
+// cachedOnlyKey is the context key of the cached-only flag.
+type cachedOnlyKey struct{}
+
+// WithNoWait returns a copy of the context that makes repository operations (Repository.Remux, Repository.Transcode)
+// return ErrNotReady instead of waiting for the same result being made by another operation, such as a background job.
+// The operation is still done by the caller if nobody else is doing it.
+func WithNoWait(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noWaitKey{}, true)
+}
+
+// NoWait checks whether the context was made with WithNoWait.
+func NoWait(ctx context.Context) bool {
+	noWait, _ := ctx.Value(noWaitKey{}).(bool)
+	return noWait
+}
+
+// WithCachedOnly returns a copy of the context that makes repository operations (Repository.Remux, Repository.Transcode,
+// Repository.Preview) return ErrNotReady instead of making a result that isn't cached, such as to leave it to a background job.
+func WithCachedOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cachedOnlyKey{}, true)
+}
+
+// CachedOnly checks whether the context was made with WithCachedOnly.
+func CachedOnly(ctx context.Context) bool {
+	cachedOnly, _ := ctx.Value(cachedOnlyKey{}).(bool)
+	return cachedOnly
+}
+
+// PartialFunc is a receiver of the path of a result file being written, such as to stream it before it's complete.
+type PartialFunc func(path string)
+
+// partialKey is the context key of a PartialFunc.
+type partialKey struct{}
+
+// WithPartial returns a copy of the context carrying a PartialFunc, which is called by Repository.Remux with the temporary path
+// of the result file right before the operation writing it starts, WithNoWait is ignored then. It's only called if the file
+// is written by the call carrying it and the backend writes it readable (see Features.Fragmenting), the call waits
+// for another one writing the file without calling it. The file is written progressively and may not exist yet,
+// it's moved to the result's path once complete or removed on failure.
+// Remuxed formats that can't be read before they're complete are written in a fragmented variant (i.e. MP4).
+func WithPartial(ctx context.Context, fn PartialFunc) context.Context {
+	return context.WithValue(ctx, partialKey{}, fn)
+}
+
+// Partial returns the PartialFunc carried by the context, nil if there's none.
+func Partial(ctx context.Context) PartialFunc {
+	fn, _ := ctx.Value(partialKey{}).(PartialFunc)
+	return fn
+}
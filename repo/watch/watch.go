@@ -4,6 +4,7 @@ import (
 	"github.com/fsnotify/fsnotify"
 	"github.com/katana-project/katana/internal/errors"
 	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"golang.org/x/exp/slices"
@@ -16,15 +17,47 @@ import (
 	"time"
 )
 
+const (
+	// reconnectInterval is the period between attempts of restarting a stopped watcher and checks whether a lost directory reappeared.
+	reconnectInterval = 5 * time.Second
+	// batchInterval is the maximum time changes made by events are kept in a batch before being persisted (repo.MutableRepository.Commit).
+	batchInterval = 5 * time.Second
+	// moveWindow is the time media of a renamed file waits for the file to appear under its new name before it's removed.
+	moveWindow = time.Second
+)
+
+// errStopped is returned by rescan when the repository is closed meanwhile.
+var errStopped = errors.New("watcher stopped")
+
 // watchRepo is a wrapping repo.MutableRepository with a repo.CapabilityWatch capability.
 type watchRepo struct {
 	repo.MutableRepository
 
-	logger  *zap.Logger
-	watcher *fsnotify.Watcher
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	watcher  *fsnotify.Watcher       // replaced when it stops unexpectedly
+	timers   map[string]*time.Timer  // path -> deduplicated event handler
+	moves    map[string]*pendingMove // old path -> media of a renamed file, waiting for its new name
+	lost     map[string]struct{}     // directories waiting to reappear, such as unmounted file systems
+	handlers sync.WaitGroup          // running event handlers and reconnects
+	closed   bool
+	stop     chan struct{} // closed when closing starts
+	done     chan struct{} // closed when the event loop stops
+
+	batchMu    sync.Mutex
+	batchTimer *time.Timer // commits the running batch of event changes, nil if there's none
+}
+
+// pendingMove is media of a renamed file, the rename's two halves come as separate events.
+type pendingMove struct {
+	media media.Media
+	stat  repo.FileStat // identifies the file under its new name, a rename keeps its size and modification time
+	timer *time.Timer   // removes the media when the window passes
 }
 
-// NewRepository creates a repository with a filesystem watcher.
+// NewRepository creates a repository with a filesystem watcher, watching all directories of the repository.
+// Files changed while the repository wasn't watched aren't reconciled, that's up to a following scan (repo.MutableRepository.Scan).
 func NewRepository(repo repo.MutableRepository, logger *zap.Logger) (repo.MutableRepository, error) {
 	if wr, ok := repo.(*watchRepo); ok {
 		return wr, nil
@@ -35,21 +68,26 @@ func NewRepository(repo repo.MutableRepository, logger *zap.Logger) (repo.Mutabl
 		return nil, errors.Wrap(err, "failed to make watcher")
 	}
 
-	err = filepath.WalkDir(repo.Path(), func(path string, d fs.DirEntry, err error) error {
+	walk := func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
 		if d.IsDir() {
-			if strings.HasPrefix(d.Name(), ".") {
-				return filepath.SkipDir // dot-prefixed files/directories are excluded from handling
+			if repo.Ignored(path, true) {
+				return filepath.SkipDir
 			}
 
 			return watcher.Add(path) // path is always absolute
 		}
 
 		return nil
-	})
+	}
+	for _, root := range repo.Roots() {
+		if err = filepath.WalkDir(root, walk); err != nil {
+			break
+		}
+	}
 	if err != nil {
 		watcher.Close()
 		return nil, errors.Wrap(err, "failed to walk repository files")
@@ -59,6 +97,11 @@ func NewRepository(repo repo.MutableRepository, logger *zap.Logger) (repo.Mutabl
 		MutableRepository: repo,
 		logger:            logger,
 		watcher:           watcher,
+		timers:            make(map[string]*time.Timer),
+		moves:             make(map[string]*pendingMove),
+		lost:              make(map[string]struct{}),
+		stop:              make(chan struct{}),
+		done:              make(chan struct{}),
 	}
 
 	go wr.handleFsEvents()
@@ -69,20 +112,70 @@ func (wr *watchRepo) Capabilities() repo.Capability {
 	return wr.MutableRepository.Capabilities() | repo.CapabilityWatch
 }
 
+// currentWatcher returns the watcher in use.
+func (wr *watchRepo) currentWatcher() *fsnotify.Watcher {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	return wr.watcher
+}
+
+// goHandler runs a function in a goroutine awaited by Close, unless the repository is closed already.
+func (wr *watchRepo) goHandler(fn func()) {
+	wr.mu.Lock()
+	if wr.closed {
+		wr.mu.Unlock()
+		return
+	}
+	wr.handlers.Add(1)
+	wr.mu.Unlock()
+
+	go func() {
+		defer wr.handlers.Done()
+		fn()
+	}()
+}
+
 func (wr *watchRepo) handleFsEvents() {
-	var (
-		waitFor = 100 * time.Millisecond
-		timers  = make(map[string]*time.Timer)
-		mu      sync.Mutex
-	)
+	defer close(wr.done)
+
+	for {
+		wr.receive(wr.currentWatcher())
+
+		select {
+		case <-wr.stop:
+			return
+		default:
+		}
+
+		if wr.logger != nil {
+			wr.logger.Warn("filesystem watcher stopped unexpectedly, restarting", zap.String("id", wr.ID()), zap.String("path", wr.Path()))
+		}
+		if !wr.restart() {
+			return
+		}
+	}
+}
 
+// receive handles the events of a watcher until it stops.
+func (wr *watchRepo) receive(watcher *fsnotify.Watcher) {
+	waitFor := 100 * time.Millisecond
 	for {
 		select {
-		case err, ok := <-wr.watcher.Errors:
+		case err, ok := <-watcher.Errors:
 			if !ok {
 				return
 			}
 
+			if errors.Is(err, fsnotify.ErrEventOverflow) { // events were dropped, look for the changes
+				if wr.logger != nil {
+					wr.logger.Warn("filesystem events overflowed, rescanning", zap.String("id", wr.ID()), zap.String("path", wr.Path()))
+				}
+
+				wr.goHandler(wr.rescanRoots)
+				continue
+			}
+
 			if wr.logger != nil {
 				wr.logger.Error(
 					"filesystem watch error",
@@ -91,51 +184,294 @@ func (wr *watchRepo) handleFsEvents() {
 					zap.Error(err),
 				)
 			}
-		case e, ok := <-wr.watcher.Events:
+		case e, ok := <-watcher.Events:
 			if !ok {
 				return
 			}
 
-			if e.Has(fsnotify.Create) || e.Has(fsnotify.Write) { // event deduplication - run handler 100ms after last event, else reset timer
-				mu.Lock()
-				t, ok := timers[e.Name]
-				mu.Unlock()
+			if e.Op == 0 && e.Name != "" { // unmounted file system, the watch is gone
+				wr.goHandler(func() {
+					wr.reconnect(e.Name)
+				})
+			} else if e.Has(fsnotify.Create) || e.Has(fsnotify.Write) { // event deduplication - run handler 100ms after last event, else reset timer
+				wr.mu.Lock()
+				if wr.closed {
+					wr.mu.Unlock()
+					return
+				}
 
+				t, ok := wr.timers[e.Name]
 				if !ok {
 					t = time.AfterFunc(math.MaxInt64, func() {
-						if err := wr.handleFsEvent(e); err != nil && wr.logger != nil {
-							wr.logger.Error(
-								"filesystem event handler error",
-								zap.String("id", wr.ID()),
-								zap.String("path", wr.Path()),
-								zap.Error(err),
-							)
+						wr.mu.Lock()
+						if wr.closed {
+							wr.mu.Unlock()
+							return
 						}
+						wr.handlers.Add(1)
+						wr.mu.Unlock()
+						defer wr.handlers.Done()
+
+						wr.handle(e)
 
-						mu.Lock()
-						delete(timers, e.Name)
-						mu.Unlock()
+						wr.mu.Lock()
+						delete(wr.timers, e.Name)
+						wr.mu.Unlock()
 					})
 					t.Stop()
 
-					mu.Lock()
-					timers[e.Name] = t
-					mu.Unlock()
+					wr.timers[e.Name] = t
 				}
+				wr.mu.Unlock()
 
 				t.Reset(waitFor)
 			} else if e.Has(fsnotify.Remove) || e.Has(fsnotify.Rename) { // no deduplication
-				if err := wr.handleFsEvent(e); err != nil && wr.logger != nil {
-					wr.logger.Error(
-						"filesystem event handler error",
-						zap.String("id", wr.ID()),
-						zap.String("path", wr.Path()),
-						zap.Error(err),
-					)
+				if slices.Contains(wr.Roots(), e.Name) { // the repository itself, it may come back, e.g. a remounted network share
+					wr.goHandler(func() {
+						wr.reconnect(e.Name)
+					})
+					continue
 				}
+
+				wr.handle(e)
+			}
+		}
+	}
+}
+
+// restart replaces a watcher that stopped unexpectedly, retrying until it succeeds or the repository is closed,
+// then rescans the repository for changes missed meanwhile. Returns false if the repository was closed.
+func (wr *watchRepo) restart() bool {
+	ticker := time.NewTicker(reconnectInterval)
+	defer ticker.Stop()
+
+	for {
+		watcher, err := fsnotify.NewWatcher()
+		if err == nil {
+			wr.mu.Lock()
+			select {
+			case <-wr.stop: // Close took the old one
+				wr.mu.Unlock()
+				_ = watcher.Close()
+				return false
+			default:
 			}
+
+			old := wr.watcher
+			wr.watcher = watcher
+			wr.mu.Unlock()
+
+			_ = old.Close()
+			wr.goHandler(wr.rescanRoots)
+			return true
+		}
+
+		if wr.logger != nil {
+			wr.logger.Error("failed to restart filesystem watcher", zap.String("id", wr.ID()), zap.Error(err))
+		}
+
+		select {
+		case <-wr.stop:
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconnect waits for a lost watched directory to reappear, then watches and rescans it, until the repository is closed.
+func (wr *watchRepo) reconnect(dir string) {
+	wr.mu.Lock()
+	for lostDir := range wr.lost {
+		if within(lostDir, dir) { // waiting for it or a parent already
+			wr.mu.Unlock()
+			return
+		}
+	}
+	wr.lost[dir] = struct{}{}
+	watcher := wr.watcher
+	wr.mu.Unlock()
+
+	defer func() {
+		wr.mu.Lock()
+		delete(wr.lost, dir)
+		wr.mu.Unlock()
+	}()
+
+	for _, path := range watcher.WatchList() { // stale, the kernel dropped them
+		if within(dir, path) {
+			_ = watcher.Remove(path)
 		}
 	}
+
+	if wr.logger != nil {
+		wr.logger.Warn("lost watched directory, waiting for it to reappear", zap.String("id", wr.ID()), zap.String("path", dir))
+	}
+
+	ticker := time.NewTicker(reconnectInterval)
+	defer ticker.Stop()
+
+	for {
+		if repo.CheckRoot(dir, wr.hasMedia(dir)) == nil { // a mount point is left empty
+			break
+		}
+
+		select {
+		case <-wr.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+
+	if wr.logger != nil {
+		wr.logger.Info("watched directory reappeared, rescanning", zap.String("id", wr.ID()), zap.String("path", dir))
+	}
+	wr.rescanLogged(dir)
+}
+
+// rescanRoots rescans all root directories of the repository, logging errors.
+func (wr *watchRepo) rescanRoots() {
+	for _, root := range wr.Roots() {
+		wr.rescanLogged(root)
+	}
+}
+
+// rescanLogged rescans a directory, logging errors.
+func (wr *watchRepo) rescanLogged(dir string) {
+	if err := wr.rescan(dir); err != nil && !errors.Is(err, errStopped) && wr.logger != nil {
+		wr.logger.Error(
+			"failed to rescan directory",
+			zap.String("id", wr.ID()),
+			zap.String("path", dir),
+			zap.Error(err),
+		)
+	}
+}
+
+// rescan watches the subtree of a directory and reconciles its media with the files in it, picking up changes
+// missed while it wasn't watched. The changes are persisted at once, when it finishes.
+func (wr *watchRepo) rescan(dir string) (err error) {
+	if err := repo.CheckRoot(dir, wr.hasMedia(dir)); err != nil { // not removed, just unreachable
+		return err
+	}
+
+	wr.MutableRepository.BeginBatch()
+	defer func() {
+		err = multierr.Append(err, wr.MutableRepository.Commit())
+	}()
+
+	watcher := wr.currentWatcher()
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-wr.stop:
+			return errStopped
+		default:
+		}
+
+		if wr.Ignored(path, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.IsDir() {
+			return watcher.Add(path)
+		}
+		if wr.Find(path) != nil {
+			return nil
+		}
+
+		if err := wr.addPath(path); err != nil {
+			var eimt *repo.ErrInvalidMediaType
+			if !errors.As(err, &eimt) && wr.logger != nil { // not media, skip
+				wr.logger.Warn("failed to add rescanned file", zap.String("id", wr.ID()), zap.String("path", path), zap.Error(err))
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to walk directory")
+	}
+
+	for _, m := range wr.Items() { // removed meanwhile
+		if !within(dir, m.Path()) {
+			continue
+		}
+
+		if _, err := os.Stat(m.Path()); errors.Is(err, fs.ErrNotExist) {
+			if err := wr.RemovePath(m.Path()); err != nil {
+				return errors.Wrapf(err, "failed to remove media %s", m.ID())
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasMedia checks whether there are media in a directory's subtree.
+func (wr *watchRepo) hasMedia(dir string) bool {
+	for _, m := range wr.Items() {
+		if within(dir, m.Path()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// within checks whether a path is a directory or inside of it.
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// batch starts a batch of the changes made by events, if there's none running, it's committed after batchInterval.
+func (wr *watchRepo) batch() {
+	wr.batchMu.Lock()
+	defer wr.batchMu.Unlock()
+
+	if wr.batchTimer != nil {
+		return
+	}
+
+	wr.MutableRepository.BeginBatch()
+	wr.batchTimer = time.AfterFunc(batchInterval, func() {
+		if err := wr.commit(); err != nil && wr.logger != nil {
+			wr.logger.Error("failed to persist filesystem event changes", zap.String("id", wr.ID()), zap.Error(err))
+		}
+	})
+}
+
+// commit commits the running batch of the changes made by events, if there's one.
+func (wr *watchRepo) commit() error {
+	wr.batchMu.Lock()
+	defer wr.batchMu.Unlock()
+
+	if wr.batchTimer == nil { // committed already
+		return nil
+	}
+
+	wr.batchTimer.Stop()
+	wr.batchTimer = nil
+	return wr.MutableRepository.Commit()
+}
+
+// handle handles a filesystem event, logging errors.
+func (wr *watchRepo) handle(event fsnotify.Event) {
+	if err := wr.handleFsEvent(event); err != nil && !errors.Is(err, errStopped) && wr.logger != nil {
+		wr.logger.Error(
+			"filesystem event handler error",
+			zap.String("id", wr.ID()),
+			zap.String("path", wr.Path()),
+			zap.Error(err),
+		)
+	}
 }
 
 func (wr *watchRepo) handleFsEvent(event fsnotify.Event) error {
@@ -153,28 +489,40 @@ func (wr *watchRepo) handleFsEvent(event fsnotify.Event) error {
 		return nil // dot-prefixed files/directories are excluded from handling
 	}
 
+	watcher := wr.currentWatcher()
 	if event.Has(fsnotify.Create) || event.Has(fsnotify.Write) {
 		fi, err := os.Stat(event.Name)
 		if err != nil {
 			return err
 		}
+		if wr.Ignored(event.Name, fi.IsDir()) {
+			if wr.logger != nil {
+				wr.logger.Info(
+					"ignored filesystem event, excluded by repository patterns",
+					zap.String("path", event.Name),
+					zap.String("repo", wr.ID()),
+				)
+			}
+			return nil
+		}
 
 		if fi.IsDir() {
 			if wr.logger != nil {
 				wr.logger.Info(
-					"adding filesystem watcher to directory",
+					"adding filesystem watcher to directory tree",
 					zap.String("path", event.Name),
 					zap.String("repo", wr.ID()),
 				)
 			}
-			return wr.watcher.Add(event.Name)
-		}
 
-		if err := wr.AddPath(event.Name); err != nil {
-			return err
+			// the directory may have been made with subdirectories and files already (mkdir -p, moved in),
+			// their events were missed before it was watched, so its whole subtree is watched and reconciled
+			return wr.rescan(event.Name)
 		}
+
+		return wr.addPath(event.Name)
 	} else if event.Has(fsnotify.Rename) || event.Has(fsnotify.Remove) {
-		if slices.Contains(wr.watcher.WatchList(), event.Name) {
+		if slices.Contains(watcher.WatchList(), event.Name) {
 			if wr.logger != nil {
 				wr.logger.Info(
 					"removing filesystem watcher from directory",
@@ -182,9 +530,23 @@ func (wr *watchRepo) handleFsEvent(event fsnotify.Event) error {
 					zap.String("repo", wr.ID()),
 				)
 			}
-			return wr.watcher.Remove(event.Name)
+			if event.Has(fsnotify.Rename) { // its files may reappear under the new name, picked up by its rescan
+				for _, m := range wr.Items() {
+					if within(event.Name, m.Path()) {
+						wr.deferRemoval(m)
+					}
+				}
+			}
+
+			return watcher.Remove(event.Name)
+		}
+
+		if m := wr.Find(event.Name); m != nil && event.Has(fsnotify.Rename) {
+			wr.deferRemoval(m)
+			return nil
 		}
 
+		wr.batch()
 		if err := wr.RemovePath(event.Name); err != nil {
 			return err
 		}
@@ -193,8 +555,124 @@ func (wr *watchRepo) handleFsEvent(event fsnotify.Event) error {
 	return nil
 }
 
+// deferRemoval defers removing media of a renamed file by moveWindow, so that it can be moved (repo.MutableRepository.Move)
+// once the file's new name is known, keeping its ID. Media of files with an unknown stat can't be recognized, they're removed at once.
+func (wr *watchRepo) deferRemoval(m media.Media) {
+	path, stat := m.Path(), wr.FileStat(m.ID())
+	if stat.IsZero() {
+		wr.removeMoved(path)
+		return
+	}
+
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	if _, ok := wr.moves[path]; ok || wr.closed {
+		return
+	}
+
+	wr.moves[path] = &pendingMove{
+		media: m,
+		stat:  stat,
+		timer: time.AfterFunc(moveWindow, func() {
+			wr.mu.Lock()
+			if _, ok := wr.moves[path]; !ok || wr.closed { // claimed or closing
+				wr.mu.Unlock()
+				return
+			}
+			delete(wr.moves, path)
+			wr.handlers.Add(1)
+			wr.mu.Unlock()
+			defer wr.handlers.Done()
+
+			wr.removeMoved(path)
+		}),
+	}
+}
+
+// removeMoved removes media of a renamed file whose new name didn't appear in the repository, logging errors.
+func (wr *watchRepo) removeMoved(path string) {
+	wr.batch()
+	if err := wr.RemovePath(path); err != nil && wr.logger != nil {
+		wr.logger.Error("failed to remove media of renamed file", zap.String("id", wr.ID()), zap.String("path", path), zap.Error(err))
+	}
+}
+
+// claimMove returns the media of a renamed file waiting for its new name (deferRemoval), matching a file's stat, nil if there's none.
+// A media of the same file name is preferred if there are several, none is returned if it's still ambiguous.
+func (wr *watchRepo) claimMove(path string, stat repo.FileStat) media.Media {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	var matches []string
+	for oldPath, pm := range wr.moves {
+		if pm.stat.Equal(stat) {
+			matches = append(matches, oldPath)
+		}
+	}
+	if len(matches) > 1 {
+		matches = slices.DeleteFunc(matches, func(oldPath string) bool {
+			return filepath.Base(oldPath) != filepath.Base(path)
+		})
+	}
+	if len(matches) != 1 {
+		return nil
+	}
+
+	pm := wr.moves[matches[0]]
+	pm.timer.Stop()
+	delete(wr.moves, matches[0])
+
+	return pm.media
+}
+
+// addPath adds media of a new file, the media of a renamed file is moved to it instead if it's the file's new name (claimMove).
+func (wr *watchRepo) addPath(path string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	wr.batch()
+	if m := wr.claimMove(path, repo.NewFileStat(fi)); m != nil {
+		err := wr.Move(m, path)
+		if err == nil {
+			return nil
+		}
+
+		if wr.logger != nil {
+			wr.logger.Warn("failed to move media of renamed file, adding it again", zap.String("id", wr.ID()), zap.String("path", path), zap.Error(err))
+		}
+		wr.removeMoved(m.Path())
+	}
+
+	return wr.AddPath(path)
+}
+
+// Close stops the watcher and waits for running event handlers, pending deduplicated events and removals of renamed files
+// are dropped (they're picked up by the next scan), and commits the running batch before closing the underlying repository.
 func (wr *watchRepo) Close() (err error) {
-	return multierr.Combine(wr.watcher.Close(), wr.MutableRepository.Close())
+	wr.mu.Lock()
+	close(wr.stop)
+	watcher := wr.watcher
+	wr.mu.Unlock()
+
+	err = watcher.Close()
+	<-wr.done
+
+	wr.mu.Lock()
+	wr.closed = true
+	for _, t := range wr.timers {
+		t.Stop()
+	}
+	for _, pm := range wr.moves {
+		pm.timer.Stop()
+	}
+	wr.mu.Unlock()
+	wr.handlers.Wait()
+
+	err = multierr.Append(err, wr.commit())
+	return multierr.Append(err, wr.MutableRepository.Close())
 }
 
 func (wr *watchRepo) Mutable() repo.MutableRepository {
@@ -0,0 +1,190 @@
+package watch
+
+import (
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media/meta"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// mkvHeader is the start of an EBML header of a Matroska file, enough for MIME type detection.
+var mkvHeader = []byte("\x1a\x45\xdf\xa3\xa3\x42\x86\x81\x01\x42\xf7\x81\x01\x42\xf2\x81\x04\x42\xf3\x81\x08\x42\x82\x88matroska")
+
+func TestWatchRepo_Rescan(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Shows"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gone := filepath.Join(root, "Shows", "Gone.mkv")
+	if err := os.WriteFile(gone, mkvHeader, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddPath(gone); err != nil {
+		t.Fatal(err)
+	}
+
+	wr, err := NewRepository(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wr.Close()
+
+	// changes made while the directory wasn't watched, e.g. on a remounted file system
+	missed := filepath.Join(root, "Shows", "Missed.mkv")
+	if err := os.WriteFile(missed, mkvHeader, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Shows", "Notes.txt"), []byte("not media"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "Shows", ".hidden.mkv"), mkvHeader, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := wr.(*watchRepo).rescan(filepath.Join(root, "Shows")); err != nil {
+		t.Fatal(err)
+	}
+
+	if wr.Find(missed) == nil {
+		t.Error("missed file not added")
+	}
+	if wr.Find(gone) != nil {
+		t.Error("removed file not removed")
+	}
+	if n := len(wr.Items()); n != 1 {
+		t.Errorf("expected 1 media, got %d", n)
+	}
+}
+
+func TestWatchRepo_NestedDirectory(t *testing.T) {
+	root := t.TempDir()
+
+	r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wr, err := NewRepository(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wr.Close()
+
+	// made at once, before the new directories are watched
+	season := filepath.Join(root, "Show", "Season 01")
+	if err := os.MkdirAll(season, 0755); err != nil {
+		t.Fatal(err)
+	}
+	episode := filepath.Join(season, "Episode.mkv")
+	if err := os.WriteFile(episode, mkvHeader, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); wr.Find(episode) == nil; {
+		if time.Now().After(deadline) {
+			t.Fatal("file in nested directory not added")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// later changes in the nested directory are watched too
+	later := filepath.Join(season, "Later.mkv")
+	if err := os.WriteFile(later, mkvHeader, 0644); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); wr.Find(later) == nil; {
+		if time.Now().After(deadline) {
+			t.Fatal("file added to nested directory later not added")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestWatchRepo_Rename(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Show"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(root, "Show", "Episode 1.mkv")
+	if err := os.WriteFile(path, mkvHeader, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddPath(path); err != nil {
+		t.Fatal(err)
+	}
+	id := r.Find(path).ID()
+
+	wr, err := NewRepository(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wr.Close()
+
+	moved := make(chan repo.Event, 2)
+	wr.Events().Subscribe(func(e repo.Event) {
+		if e.Type == repo.EventMediaMoved {
+			moved <- e
+		}
+	})
+
+	for _, newPath := range []string{
+		filepath.Join(root, "Show", "Show S01E01.mkv"), // renamed
+		filepath.Join(root, "Show S01E01.mkv"),         // moved to another directory
+	} {
+		if err := os.Rename(path, newPath); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case e := <-moved:
+			if e.Media.ID() != id || e.Media.Path() != newPath || e.OldPath != path {
+				t.Errorf("expected media %s moved from %s to %s, got %s moved from %s to %s", id, path, newPath, e.Media.ID(), e.OldPath, e.Media.Path())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("media of file renamed to %s not moved", newPath)
+		}
+		if m := wr.Get(id); m == nil || m.Path() != newPath {
+			t.Errorf("expected media %s at %s, got %v", id, newPath, m)
+		}
+
+		path = newPath
+	}
+	if n := len(wr.Items()); n != 1 {
+		t.Errorf("expected 1 media, got %d", n)
+	}
+}
+
+func TestWithin(t *testing.T) {
+	tests := []struct {
+		dir, path string
+		want      bool
+	}{
+		{dir: "/media", path: "/media", want: true},
+		{dir: "/media", path: "/media/Shows/Test.mkv", want: true},
+		{dir: "/media", path: "/media/..test.mkv", want: true},
+		{dir: "/media", path: "/media2/Test.mkv", want: false},
+		{dir: "/media/Shows", path: "/media/Movies/Test.mkv", want: false},
+	}
+	for _, tt := range tests {
+		if got := within(tt.dir, tt.path); got != tt.want {
+			t.Errorf("within(%q, %q) = %v, want %v", tt.dir, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package schema
+
+import _ "embed"
+
+// V1 is the OpenAPI specification of the v1 API.
+//
+//go:embed v1.yaml
+var V1 []byte
@@ -9,15 +9,84 @@ import (
 	"time"
 
 	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// Defines values for ArchiveStatus.
+const (
+	ArchiveStatusArchived  ArchiveStatus = "archived"
+	ArchiveStatusArchiving ArchiveStatus = "archiving"
+	ArchiveStatusNone      ArchiveStatus = "none"
+	ArchiveStatusRestoring ArchiveStatus = "restoring"
+)
+
+// Defines values for BulkAction.
+const (
+	AddGenre        BulkAction = "add_genre"
+	MarkUnwatched   BulkAction = "mark_unwatched"
+	MarkWatched     BulkAction = "mark_watched"
+	RefreshMetadata BulkAction = "refresh_metadata"
+	RemoveGenre     BulkAction = "remove_genre"
+	Trash           BulkAction = "trash"
+)
+
+// Defines values for CollectionSortKey.
+const (
+	CollectionSortKeyRating      CollectionSortKey = "rating"
+	CollectionSortKeyReleaseDate CollectionSortKey = "release_date"
+	CollectionSortKeyTitle       CollectionSortKey = "title"
+)
+
+// Defines values for DirectoryEntryType.
+const (
+	Directory DirectoryEntryType = "directory"
+	File      DirectoryEntryType = "file"
 )
 
 // Defines values for ErrorType.
 const (
 	BadRequest        ErrorType = "bad_request"
+	Gone              ErrorType = "gone"
 	InternalError     ErrorType = "internal_error"
 	MissingCapability ErrorType = "missing_capability"
 	NotFound          ErrorType = "not_found"
+	NotReady          ErrorType = "not_ready"
+	QuotaExceeded     ErrorType = "quota_exceeded"
+	Unauthorized      ErrorType = "unauthorized"
+	Unavailable       ErrorType = "unavailable"
 	UnknownFormat     ErrorType = "unknown_format"
+	Unsupported       ErrorType = "unsupported"
+)
+
+// Defines values for EventType.
+const (
+	JobUpdated    EventType = "job_updated"
+	MediaAdded    EventType = "media_added"
+	MediaMoved    EventType = "media_moved"
+	MediaRemoved  EventType = "media_removed"
+	MediaUpdated  EventType = "media_updated"
+	NewEpisode    EventType = "new_episode"
+	QuotaWarning  EventType = "quota_warning"
+	RepoDegraded  EventType = "repo_degraded"
+	RepoRecovered EventType = "repo_recovered"
+	ScanCompleted EventType = "scan_completed"
+)
+
+// Defines values for ExportFormat.
+const (
+	Csv  ExportFormat = "csv"
+	Json ExportFormat = "json"
+)
+
+// Defines values for ImageSize.
+const (
+	Original ImageSize = "original"
+	W1280    ImageSize = "w1280"
+	W185     ImageSize = "w185"
+	W300     ImageSize = "w300"
+	W500     ImageSize = "w500"
+	W780     ImageSize = "w780"
+	W92      ImageSize = "w92"
 )
 
 // Defines values for ImageType.
@@ -29,6 +98,53 @@ const (
 	ImageTypeUnknown  ImageType = "unknown"
 )
 
+// Defines values for JobState.
+const (
+	Canceled  JobState = "canceled"
+	Completed JobState = "completed"
+	Failed    JobState = "failed"
+	Queued    JobState = "queued"
+	Running   JobState = "running"
+)
+
+// Defines values for JobType.
+const (
+	JobTypeArchive    JobType = "archive"
+	JobTypeBulk       JobType = "bulk"
+	JobTypeChecksum   JobType = "checksum"
+	JobTypeDash       JobType = "dash"
+	JobTypeIdentify   JobType = "identify"
+	JobTypePreview    JobType = "preview"
+	JobTypeProbe      JobType = "probe"
+	JobTypeRefresh    JobType = "refresh"
+	JobTypeRemux      JobType = "remux"
+	JobTypeReplicate  JobType = "replicate"
+	JobTypeRestore    JobType = "restore"
+	JobTypeScan       JobType = "scan"
+	JobTypeStoryboard JobType = "storyboard"
+	JobTypeTranscode  JobType = "transcode"
+	JobTypeVerify     JobType = "verify"
+)
+
+// Defines values for MatchFilter.
+const (
+	MatchFilterLowConfidence MatchFilter = "low_confidence"
+	MatchFilterUnmatched     MatchFilter = "unmatched"
+)
+
+// Defines values for MediaExtraKind.
+const (
+	Theme   MediaExtraKind = "theme"
+	Trailer MediaExtraKind = "trailer"
+)
+
+// Defines values for MediaSortKey.
+const (
+	MediaSortKeyAddedAt     MediaSortKey = "added_at"
+	MediaSortKeyReleaseDate MediaSortKey = "release_date"
+	MediaSortKeyTitle       MediaSortKey = "title"
+)
+
 // Defines values for MetadataType.
 const (
 	MetadataTypeEpisode MetadataType = "episode"
@@ -39,12 +155,168 @@ const (
 
 // Defines values for RepositoryCapability.
 const (
-	Index     RepositoryCapability = "index"
-	Remux     RepositoryCapability = "remux"
-	Transcode RepositoryCapability = "transcode"
-	Watch     RepositoryCapability = "watch"
+	RepositoryCapabilityArchive   RepositoryCapability = "archive"
+	RepositoryCapabilityIndex     RepositoryCapability = "index"
+	RepositoryCapabilityRemux     RepositoryCapability = "remux"
+	RepositoryCapabilityTranscode RepositoryCapability = "transcode"
+	RepositoryCapabilityWatch     RepositoryCapability = "watch"
+)
+
+// Defines values for RuleField.
+const (
+	RuleFieldGenre   RuleField = "genre"
+	RuleFieldRating  RuleField = "rating"
+	RuleFieldRepo    RuleField = "repo"
+	RuleFieldTag     RuleField = "tag"
+	RuleFieldTitle   RuleField = "title"
+	RuleFieldType    RuleField = "type"
+	RuleFieldWatched RuleField = "watched"
+	RuleFieldYear    RuleField = "year"
+)
+
+// Defines values for RuleOp.
+const (
+	Contains RuleOp = "contains"
+	Eq       RuleOp = "eq"
+	Gt       RuleOp = "gt"
+	Gte      RuleOp = "gte"
+	Lt       RuleOp = "lt"
+	Lte      RuleOp = "lte"
+	Ne       RuleOp = "ne"
+)
+
+// Defines values for SortOrder.
+const (
+	Asc  SortOrder = "asc"
+	Desc SortOrder = "desc"
+)
+
+// Defines values for StreamMode.
+const (
+	StreamModeJob         StreamMode = "job"
+	StreamModeProgressive StreamMode = "progressive"
+	StreamModeWait        StreamMode = "wait"
+)
+
+// Defines values for SuggestionSource.
+const (
+	SuggestionSourceAcoustid SuggestionSource = "acoustid"
+	SuggestionSourceFrames   SuggestionSource = "frames"
 )
 
+// Defines values for TranscodeOption.
+const (
+	TranscodeOptionAudioEncoding TranscodeOption = "audio_encoding"
+	TranscodeOptionBitRate       TranscodeOption = "bit_rate"
+	TranscodeOptionFragmenting   TranscodeOption = "fragmenting"
+	TranscodeOptionResolution    TranscodeOption = "resolution"
+	TranscodeOptionSeeking       TranscodeOption = "seeking"
+	TranscodeOptionWatermark     TranscodeOption = "watermark"
+)
+
+// Defines values for WatchedFilter.
+const (
+	False      WatchedFilter = "false"
+	InProgress WatchedFilter = "in-progress"
+	True       WatchedFilter = "true"
+)
+
+// AddMediaRequest defines model for AddMediaRequest.
+type AddMediaRequest struct {
+	// Path The path of the media file, relative to the repository's directory or absolute within it.
+	Path string `json:"path"`
+}
+
+// ArchiveState defines model for ArchiveState.
+type ArchiveState struct {
+	Job    *Job          `json:"job,omitempty"`
+	Status ArchiveStatus `json:"status"`
+}
+
+// ArchiveStatus defines model for ArchiveStatus.
+type ArchiveStatus string
+
+// AudioTrack defines model for AudioTrack.
+type AudioTrack struct {
+	// Codec The audio codec name, such as "aac", "ac3" or "flac".
+	Codec string `json:"codec"`
+
+	// Language The track language (BCP 47 tag), null if unknown.
+	Language *string `json:"language"`
+
+	// Stream The stream index of the track in the media file.
+	Stream int `json:"stream"`
+
+	// Title The track title, such as "English 5.1" or "Commentary", null if it has none.
+	Title *string `json:"title"`
+}
+
+// BulkAction defines model for BulkAction.
+type BulkAction string
+
+// BulkRequest defines model for BulkRequest.
+type BulkRequest struct {
+	Action BulkAction `json:"action"`
+
+	// Genre The genre name of the `add_genre` and `remove_genre` actions.
+	Genre *string `json:"genre,omitempty"`
+
+	// MediaIds The IDs of the processed media.
+	MediaIds []string `json:"media_ids"`
+}
+
+// CacheUsage defines model for CacheUsage.
+type CacheUsage struct {
+	// Bytes The total size of the cache files in bytes.
+	Bytes int64 `json:"bytes"`
+
+	// Files The number of cache files.
+	Files int `json:"files"`
+
+	// MaxAge The longest time in seconds a cache file is kept since its last use, absent if there's none.
+	MaxAge *int64 `json:"max_age,omitempty"`
+
+	// MaxBytes The size limit of the cache in bytes, the least recently used files are removed when it's exceeded, absent if there's none.
+	MaxBytes *int64 `json:"max_bytes,omitempty"`
+}
+
+// CalendarEpisode defines model for CalendarEpisode.
+type CalendarEpisode struct {
+	// AirDate The day the episode airs or aired on, such as "2024-01-31".
+	AirDate string `json:"air_date"`
+
+	// Downloaded Whether the episode is in the repository.
+	Downloaded bool `json:"downloaded"`
+
+	// Episode The episode number.
+	Episode int `json:"episode"`
+
+	// MediaId The media ID of the episode, present if it's downloaded.
+	MediaId *string `json:"media_id,omitempty"`
+
+	// RepoId The ID of the repository with the series.
+	RepoId string `json:"repo_id"`
+
+	// Season The season number.
+	Season int `json:"season"`
+
+	// Series The series title.
+	Series string `json:"series"`
+
+	// Title The episode title, not present if it's not known yet.
+	Title *string `json:"title,omitempty"`
+}
+
+// CapabilityStatus defines model for CapabilityStatus.
+type CapabilityStatus struct {
+	// Available Whether the capability is available.
+	Available  bool                 `json:"available"`
+	Capability RepositoryCapability `json:"capability"`
+
+	// Reason The reason of the capability being unavailable, missing if available.
+	Reason *string `json:"reason,omitempty"`
+}
+
 // CastMember defines model for CastMember.
 type CastMember struct {
 	Image *Image `json:"image,omitempty"`
@@ -56,8 +328,165 @@ type CastMember struct {
 	Role string `json:"role"`
 }
 
+// Chapter defines model for Chapter.
+type Chapter struct {
+	// End The end time of the chapter in seconds.
+	End float64 `json:"end"`
+
+	// Start The start time of the chapter in seconds.
+	Start float64 `json:"start"`
+
+	// Title The chapter title, null if it has none.
+	Title *string `json:"title"`
+}
+
+// ClientUsage defines model for ClientUsage.
+type ClientUsage struct {
+	// Bytes The number of bytes served in the month.
+	Bytes int64 `json:"bytes"`
+
+	// Cap The monthly transfer cap in bytes, streaming is refused once it's reached, not present if unlimited.
+	Cap *int64 `json:"cap,omitempty"`
+
+	// Client The client ID, a username or `token:` followed by the start of an API token's hash.
+	Client string `json:"client"`
+
+	// Days The statistics per day, sorted by the date, days without any bytes served are not listed.
+	Days []DayStats `json:"days"`
+}
+
+// Collection defines model for Collection.
+type Collection struct {
+	Created     time.Time `json:"created"`
+	Description *string   `json:"description,omitempty"`
+	Id          string    `json:"id"`
+
+	// Items The media in the collection, the selected media of smart collections.
+	Items   []CollectionItem `json:"items"`
+	Name    string           `json:"name"`
+	Rules   *CollectionRules `json:"rules,omitempty"`
+	Updated time.Time        `json:"updated"`
+}
+
+// CollectionItem defines model for CollectionItem.
+type CollectionItem struct {
+	Media   *Media `json:"media,omitempty"`
+	MediaId string `json:"media_id"`
+	RepoId  string `json:"repo_id"`
+}
+
+// CollectionRequest defines model for CollectionRequest.
+type CollectionRequest struct {
+	Description *string `json:"description,omitempty"`
+
+	// Items The media in the collection, in order, must be empty for smart collections.
+	Items *[]MediaRef `json:"items,omitempty"`
+
+	// Name The display name of the collection, non-blank.
+	Name  string           `json:"name"`
+	Rules *CollectionRules `json:"rules,omitempty"`
+}
+
+// CollectionRules defines model for CollectionRules.
+type CollectionRules struct {
+	// Any Whether media matching any of the conditions are selected, instead of all of them.
+	Any *bool `json:"any,omitempty"`
+
+	// Conditions The conditions of selected media, all media are selected if there are none.
+	Conditions []RuleCondition `json:"conditions"`
+
+	// Descending Whether the sort order is reversed.
+	Descending *bool `json:"descending,omitempty"`
+
+	// Limit The maximum number of selected media, zero means no limit.
+	Limit *int               `json:"limit,omitempty"`
+	Sort  *CollectionSortKey `json:"sort,omitempty"`
+}
+
+// CollectionSortKey defines model for CollectionSortKey.
+type CollectionSortKey string
+
+// ConvertRequest defines model for ConvertRequest.
+type ConvertRequest struct {
+	// AudioCodec The target audio encoder name, such as "aac", the source codec is kept if not set.
+	// Audio re-encoding isn't supported by every conversion backend (see `TranscodeOption`).
+	AudioCodec *string `json:"audio_codec,omitempty"`
+
+	// Format The target format name.
+	Format string `json:"format"`
+
+	// Start The source position to start transcoding at in seconds, for seeking ahead, only with a video or audio codec.
+	// Starting past the beginning isn't supported by every conversion backend (see `TranscodeOption`).
+	Start *float64 `json:"start,omitempty"`
+
+	// VideoCodec The target video encoder name, such as "libx264", the source codec is kept if not set.
+	VideoCodec *string `json:"video_codec,omitempty"`
+}
+
+// CorruptedFile defines model for CorruptedFile.
+type CorruptedFile struct {
+	// Actual The checksum of the current file contents.
+	Actual string `json:"actual"`
+
+	// Expected The stored checksum, prefixed with the algorithm ("xxh3:...").
+	Expected string `json:"expected"`
+
+	// MediaId The ID of the corrupted media.
+	MediaId string `json:"media_id"`
+
+	// Path The path of the media file.
+	Path string `json:"path"`
+}
+
+// DayStats defines model for DayStats.
+type DayStats struct {
+	// Bytes The number of bytes served on the day.
+	Bytes int64 `json:"bytes"`
+
+	// Date The day in the server's time zone, such as "2024-01-31".
+	Date string `json:"date"`
+}
+
+// DirectoryEntry defines model for DirectoryEntry.
+type DirectoryEntry struct {
+	// Indexed Whether the file is media of the repository, always false for directories.
+	Indexed bool `json:"indexed"`
+
+	// MediaId The ID of the media of the file, missing if it's not indexed.
+	MediaId *string `json:"media_id,omitempty"`
+
+	// ModifiedAt The last modification time of the file.
+	ModifiedAt time.Time `json:"modified_at"`
+
+	// Name The file name.
+	Name string `json:"name"`
+
+	// Path The path of the file relative to the repository's directory, slash-separated.
+	Path string `json:"path"`
+
+	// Size The size of the file in bytes, missing for directories.
+	Size *int64             `json:"size,omitempty"`
+	Type DirectoryEntryType `json:"type"`
+}
+
+// DirectoryEntryType defines model for DirectoryEntryType.
+type DirectoryEntryType string
+
+// DirectoryListing defines model for DirectoryListing.
+type DirectoryListing struct {
+	// Entries The files and subdirectories of the directory, subdirectories first, then by name.
+	Entries []DirectoryEntry `json:"entries"`
+
+	// Path The path of the directory relative to the repository's directory, slash-separated, empty for the directory itself.
+	Path string `json:"path"`
+}
+
 // EpisodeMetadata defines model for EpisodeMetadata.
 type EpisodeMetadata struct {
+	// Confidence The confidence of the metadata being of the media, as matched by the title and release year of its file name,
+	// absent if it wasn't scored, such as of metadata matched by an ID. Below 0.5, the metadata is presumed to be of other media.
+	Confidence *float32 `json:"confidence,omitempty"`
+
 	// Episode The episode number.
 	Episode int `json:"episode"`
 
@@ -73,6 +502,10 @@ type EpisodeMetadata struct {
 	// ReleaseDate The media initial release date and time.
 	ReleaseDate time.Time `json:"release_date"`
 
+	// ReleaseDateDisplay The release date as a long date in the requested language (`lang` query parameter or `Accept-Language` header),
+	// such as "January 2, 2006", absent if the release date is unknown. English is used for languages without a known date format.
+	ReleaseDateDisplay *string `json:"release_date_display,omitempty"`
+
 	// Season The season containing the episode.
 	Season int            `json:"season"`
 	Series SeriesMetadata `json:"series"`
@@ -95,29 +528,263 @@ type Error struct {
 // ErrorType defines model for ErrorType.
 type ErrorType string
 
+// Event defines model for Event.
+type Event struct {
+	Job *EventJob `json:"job,omitempty"`
+
+	// MediaId The ID of the concerned media, not present for events about a whole repository.
+	MediaId *string `json:"media_id,omitempty"`
+
+	// RepoId The ID of the concerned repository.
+	RepoId string `json:"repo_id"`
+
+	// Time The time of the event.
+	Time time.Time `json:"time"`
+	Type EventType `json:"type"`
+}
+
+// EventJob defines model for EventJob.
+type EventJob struct {
+	// Id The job ID.
+	Id    string   `json:"id"`
+	State JobState `json:"state"`
+	Type  JobType  `json:"type"`
+}
+
+// EventType defines model for EventType.
+type EventType string
+
+// ExportFormat defines model for ExportFormat.
+type ExportFormat string
+
+// ExportedProgress defines model for ExportedProgress.
+type ExportedProgress struct {
+	// Hidden Whether the media is hidden from the user's listings.
+	Hidden bool `json:"hidden"`
+
+	// MediaId The media ID.
+	MediaId string `json:"media_id"`
+
+	// Position The resume position in seconds.
+	Position float64 `json:"position"`
+
+	// RepoId The repository ID.
+	RepoId string `json:"repo_id"`
+
+	// Title The media title, not present if the media is no longer in the repository.
+	Title *string       `json:"title,omitempty"`
+	Type  *MetadataType `json:"type,omitempty"`
+
+	// UpdatedAt The time of the last update, not present if the media hasn't been played.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+	// Watched Whether the media has been watched to the end.
+	Watched bool `json:"watched"`
+
+	// Year The media release year, not present if it's not known.
+	Year *int `json:"year,omitempty"`
+}
+
+// ExportedSeries defines model for ExportedSeries.
+type ExportedSeries struct {
+	// RepoId The repository ID.
+	RepoId string `json:"repo_id"`
+
+	// Series The series key, its lowercase title and release year, such as "noragami (2014)".
+	Series string `json:"series"`
+}
+
+// FFmpegFeatures defines model for FFmpegFeatures.
+type FFmpegFeatures struct {
+	// Decoders The names of available decoders.
+	Decoders []string `json:"decoders"`
+
+	// Demuxers The names of container formats available for reading.
+	Demuxers []string `json:"demuxers"`
+
+	// Encoders The names of available encoders.
+	Encoders []string `json:"encoders"`
+
+	// Fragmenting Whether remuxed MP4 family files can be written fragmented, readable while being written,
+	// which the `progressive` stream mode (see `StreamMode`) needs; only the `exec` backend can.
+	Fragmenting bool `json:"fragmenting"`
+
+	// HwAccels The hardware acceleration methods of available encoders and decoders, such as `nvenc` or `vaapi`.
+	HwAccels []string `json:"hw_accels"`
+
+	// Muxers The names of container formats available for writing.
+	Muxers []string `json:"muxers"`
+
+	// Previews Whether preview clips of video can be made (see the `getRepoMediaPreview` operation), only the `exec` backend can.
+	Previews bool `json:"previews"`
+
+	// Storyboards Whether thumbnails of video can be taken for storyboards (see the `getRepoMediaStoryboard` operation),
+	// only the `exec` backend can.
+	Storyboards bool `json:"storyboards"`
+
+	// TranscodeOptions The transcoding settings honored by the conversion backend, beyond the target format and video codec.
+	// The default `libav` backend honors none of them, only video is re-encoded; the `exec` backend honors all of them.
+	TranscodeOptions []TranscodeOption `json:"transcode_options"`
+}
+
+// FormatSupport defines model for FormatSupport.
+type FormatSupport struct {
+	// Audio Whether the format only carries audio, video streams are dropped when converting to it.
+	Audio bool `json:"audio"`
+
+	// Demux Whether media files of the format can be read by the conversion backend.
+	Demux bool `json:"demux"`
+
+	// Extension The format's preferred file extension, *without leading dots*.
+	Extension string `json:"extension"`
+
+	// Mime The format MIME type.
+	Mime string `json:"mime"`
+
+	// Name The format name, lowercase, the `format` value of stream URLs.
+	Name string `json:"name"`
+
+	// Remux Whether media can be remuxed to the format (a muxer is available).
+	Remux bool `json:"remux"`
+
+	// Transcode Whether media can be transcoded to the format (a muxer and encoders are available).
+	Transcode bool `json:"transcode"`
+}
+
 // Image defines model for Image.
 type Image struct {
 	// Description The image description.
 	Description *string `json:"description"`
 
-	// Path The image URL, a remote HTTP resource or raw data (data:image url).
-	Path string `json:"path"`
+	// Id The image ID, alphanumeric, lowercase, non-blank ([a-z0-9-_]).
+	Id   string    `json:"id"`
+	Type ImageType `json:"type"`
 
-	// Remote Whether the image path is a remote URL.
-	Remote bool      `json:"remote"`
-	Type   ImageType `json:"type"`
+	// Url The image URL relative to the API server URL, see the `getRepoMediaImage` operation.
+	Url string `json:"url"`
 }
 
+// ImageSize defines model for ImageSize.
+type ImageSize string
+
 // ImageType defines model for ImageType.
 type ImageType string
 
+// IntegrityReport defines model for IntegrityReport.
+type IntegrityReport struct {
+	// Checked The number of verified media files.
+	Checked int `json:"checked"`
+
+	// Corrupted The corrupted media files.
+	Corrupted []CorruptedFile `json:"corrupted"`
+
+	// VerifiedAt The date and time of the verification finishing.
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// Job defines model for Job.
+type Job struct {
+	// CreatedAt The date and time of the job being queued.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Error The error description of a failed job.
+	Error *string `json:"error"`
+
+	// FinishedAt The date and time of the job finishing.
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	// Id The job ID.
+	Id string `json:"id"`
+
+	// MediaId The ID of the converted media, absent for repository scans.
+	MediaId  *string     `json:"media_id,omitempty"`
+	Progress JobProgress `json:"progress"`
+
+	// RepoId The ID of the repository containing the processed media.
+	RepoId string `json:"repo_id"`
+
+	// StartedAt The date and time of the job starting to run.
+	StartedAt *time.Time  `json:"started_at,omitempty"`
+	State     JobState    `json:"state"`
+	Summary   *JobSummary `json:"summary,omitempty"`
+	Type      JobType     `json:"type"`
+}
+
+// JobFailure defines model for JobFailure.
+type JobFailure struct {
+	// Error The error description.
+	Error   string `json:"error"`
+	MediaId string `json:"media_id"`
+}
+
+// JobProgress defines model for JobProgress.
+type JobProgress struct {
+	// BytesWritten The number of bytes written to the converted media.
+	BytesWritten int64 `json:"bytes_written"`
+
+	// Eta The estimated remaining time in seconds, null if not known yet.
+	Eta *int `json:"eta"`
+
+	// Percent The processed fraction of the source media in percent.
+	Percent float32 `json:"percent"`
+}
+
+// JobState defines model for JobState.
+type JobState string
+
+// JobSummary defines model for JobSummary.
+type JobSummary struct {
+	// Failed The media that failed to be processed.
+	Failed []JobFailure `json:"failed"`
+
+	// Succeeded The IDs of successfully processed media.
+	Succeeded []string `json:"succeeded"`
+}
+
+// JobType defines model for JobType.
+type JobType string
+
+// MatchFilter defines model for MatchFilter.
+type MatchFilter string
+
+// MatchSuggestion defines model for MatchSuggestion.
+type MatchSuggestion struct {
+	// Album The album title of a recording, `acoustid` only.
+	Album *string `json:"album,omitempty"`
+
+	// Artists The artist names of a recording, `acoustid` only.
+	Artists *[]string `json:"artists,omitempty"`
+
+	// MediaId The ID of the media with similar frames, whose metadata can be matched by its ID, `frames` only.
+	MediaId *string `json:"media_id,omitempty"`
+
+	// RecordingId The MusicBrainz recording ID of a recording, `acoustid` only.
+	RecordingId *string `json:"recording_id,omitempty"`
+
+	// RepoId The repository ID of the media with similar frames, `frames` only.
+	RepoId *string `json:"repo_id,omitempty"`
+
+	// Score The likelihood of the suggestion being right, between 0 and 1.
+	Score  float64          `json:"score"`
+	Source SuggestionSource `json:"source"`
+
+	// Title The title of the match.
+	Title string `json:"title"`
+}
+
 // Media defines model for Media.
 type Media struct {
 	// Id The media ID, alphanumeric, lowercase, non-blank ([a-z0-9-_]).
 	Id string `json:"id"`
 
+	// Locked Whether the media is locked against automatic changes (see the `lockRepoMedia` operation).
+	Locked bool `json:"locked"`
+
 	// Meta The media metadata.
 	Meta *Media_Meta `json:"meta"`
+
+	// Tags The user-defined tags of the media, sorted.
+	Tags []string `json:"tags"`
 }
 
 // Media_Meta The media metadata.
@@ -125,6 +792,19 @@ type Media_Meta struct {
 	union json.RawMessage
 }
 
+// MediaExtra defines model for MediaExtra.
+type MediaExtra struct {
+	// Id The extra ID, alphanumeric, lowercase, non-blank ([a-z0-9-_]).
+	Id   string         `json:"id"`
+	Kind MediaExtraKind `json:"kind"`
+
+	// Mime The MIME type of the extra file, such as "audio/mpeg".
+	Mime string `json:"mime"`
+}
+
+// MediaExtraKind defines model for MediaExtraKind.
+type MediaExtraKind string
+
 // MediaFormat defines model for MediaFormat.
 type MediaFormat struct {
 	// Extension The format's preferred file extension, *without leading dots*.
@@ -137,8 +817,42 @@ type MediaFormat struct {
 	Name string `json:"name"`
 }
 
+// MediaRef defines model for MediaRef.
+type MediaRef struct {
+	// MediaId The media ID.
+	MediaId string `json:"media_id"`
+
+	// RepoId The repository ID or alias, aliases are resolved to the repository ID.
+	RepoId string `json:"repo_id"`
+}
+
+// MediaSortKey defines model for MediaSortKey.
+type MediaSortKey string
+
+// MediaStats defines model for MediaStats.
+type MediaStats struct {
+	// Days The statistics per day, sorted by the date, days without any bytes served are not listed.
+	Days []DayStats `json:"days"`
+
+	// TotalBytes The number of bytes served in all listed days.
+	TotalBytes int64 `json:"total_bytes"`
+}
+
+// MediaUpload defines model for MediaUpload.
+type MediaUpload struct {
+	// File The media file.
+	File openapi_types.File `json:"file"`
+
+	// Path The destination path of the media file relative to the repository's directory, defaults to the uploaded file name.
+	Path *string `json:"path,omitempty"`
+}
+
 // Metadata defines model for Metadata.
 type Metadata struct {
+	// Confidence The confidence of the metadata being of the media, as matched by the title and release year of its file name,
+	// absent if it wasn't scored, such as of metadata matched by an ID. Below 0.5, the metadata is presumed to be of other media.
+	Confidence *float32 `json:"confidence,omitempty"`
+
 	// Images The promotional images of the media.
 	Images []Image `json:"images"`
 
@@ -151,6 +865,10 @@ type Metadata struct {
 	// ReleaseDate The media initial release date and time.
 	ReleaseDate time.Time `json:"release_date"`
 
+	// ReleaseDateDisplay The release date as a long date in the requested language (`lang` query parameter or `Accept-Language` header),
+	// such as "January 2, 2006", absent if the release date is unknown. English is used for languages without a known date format.
+	ReleaseDateDisplay *string `json:"release_date_display,omitempty"`
+
 	// Title The media title.
 	Title string       `json:"title"`
 	Type  MetadataType `json:"type"`
@@ -159,6 +877,47 @@ type Metadata struct {
 	VoteRating float32 `json:"vote_rating"`
 }
 
+// MetadataCandidate defines model for MetadataCandidate.
+type MetadataCandidate struct {
+	// Id The source-prefixed ID of the movie or series, such as "tmdb:12345".
+	Id string `json:"id"`
+
+	// OriginalTitle The title as it was initially released (original language).
+	OriginalTitle *string `json:"original_title,omitempty"`
+
+	// Overview The plot overview.
+	Overview *string `json:"overview,omitempty"`
+
+	// PosterUrl The absolute URL of the poster image at the metadata source, absent if there's none.
+	PosterUrl *string `json:"poster_url,omitempty"`
+
+	// ReleaseDate The initial release date, absent if it's not known.
+	ReleaseDate *time.Time `json:"release_date,omitempty"`
+
+	// Score The likelihood of the candidate being the searched one.
+	Score float32 `json:"score"`
+
+	// Title The title.
+	Title string       `json:"title"`
+	Type  MetadataType `json:"type"`
+}
+
+// MetadataQuery defines model for MetadataQuery.
+type MetadataQuery struct {
+	// Episode The episode number in the season.
+	Episode *int `json:"episode,omitempty"`
+
+	// Id The source-prefixed ID of the movie or series, such as "tmdb:12345", used instead of the search query.
+	Id *string `json:"id,omitempty"`
+
+	// Query The search query, such as the movie or series title.
+	Query *string `json:"query,omitempty"`
+
+	// Season The season number, the episode is looked up if set along with `episode`.
+	Season *int          `json:"season,omitempty"`
+	Type   *MetadataType `json:"type,omitempty"`
+}
+
 // MetadataType defines model for MetadataType.
 type MetadataType string
 
@@ -167,6 +926,10 @@ type MovieMetadata struct {
 	// Cast The people casted in the media.
 	Cast []CastMember `json:"cast"`
 
+	// Confidence The confidence of the metadata being of the media, as matched by the title and release year of its file name,
+	// absent if it wasn't scored, such as of metadata matched by an ID. Below 0.5, the metadata is presumed to be of other media.
+	Confidence *float32 `json:"confidence,omitempty"`
+
 	// Countries The countries that took part in producing the media.
 	Countries []string `json:"countries"`
 
@@ -188,6 +951,10 @@ type MovieMetadata struct {
 	// ReleaseDate The media initial release date and time.
 	ReleaseDate time.Time `json:"release_date"`
 
+	// ReleaseDateDisplay The release date as a long date in the requested language (`lang` query parameter or `Accept-Language` header),
+	// such as "January 2, 2006", absent if the release date is unknown. English is used for languages without a known date format.
+	ReleaseDateDisplay *string `json:"release_date_display,omitempty"`
+
 	// Title The media title.
 	Title string       `json:"title"`
 	Type  MetadataType `json:"type"`
@@ -196,11 +963,90 @@ type MovieMetadata struct {
 	VoteRating float32 `json:"vote_rating"`
 }
 
+// PlaybackProgress defines model for PlaybackProgress.
+type PlaybackProgress struct {
+	// Position The resume position in seconds.
+	Position float64 `json:"position"`
+
+	// UpdatedAt The time of the last update, null if the media hasn't been played.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+	// Watched Whether the media has been watched to the end.
+	Watched bool `json:"watched"`
+}
+
+// PlaybackProgressRequest defines model for PlaybackProgressRequest.
+type PlaybackProgressRequest struct {
+	// Position The resume position in seconds.
+	Position float64 `json:"position"`
+
+	// Watched Whether the media has been watched to the end.
+	Watched bool `json:"watched"`
+}
+
+// ReplicaItem defines model for ReplicaItem.
+type ReplicaItem struct {
+	// Digest The hex-encoded SHA-256 digest of the media, see the `getRepoFingerprint` operation.
+	Digest string `json:"digest"`
+
+	// Id The media ID.
+	Id string `json:"id"`
+
+	// Media The media with its metadata in the repository's index format, without its path.
+	Media map[string]interface{} `json:"media"`
+
+	// ModTime The modification time of the media file, absent if it's not known yet.
+	ModTime *time.Time `json:"mod_time,omitempty"`
+
+	// Path The slash-separated path of the media file, relative to the repository directory containing it.
+	Path string `json:"path"`
+
+	// Size The size of the media file in bytes, zero if it's not known yet.
+	Size int64 `json:"size"`
+
+	// Tags The user-defined tags of the media.
+	Tags *[]string `json:"tags,omitempty"`
+}
+
+// ReplicaManifest defines model for ReplicaManifest.
+type ReplicaManifest struct {
+	// Hash The fingerprint hash of the repository, see the `getRepoFingerprint` operation.
+	Hash string `json:"hash"`
+
+	// Items The media of the repository.
+	Items []ReplicaItem `json:"items"`
+
+	// Repo The repository ID.
+	Repo string `json:"repo"`
+}
+
+// RepoFingerprint defines model for RepoFingerprint.
+type RepoFingerprint struct {
+	// Count The number of media.
+	Count int `json:"count"`
+
+	// Hash The hex-encoded SHA-256 digest of the sorted media IDs and their digests.
+	Hash string `json:"hash"`
+
+	// Items The hex-encoded SHA-256 digests of the media by their IDs, only if requested.
+	Items *map[string]string `json:"items,omitempty"`
+}
+
 // Repository defines model for Repository.
 type Repository struct {
+	// Aliases The alternative IDs of the repository, usable in place of the canonical ID.
+	Aliases *[]string `json:"aliases,omitempty"`
+
 	// Capabilities The repository's capabilities.
 	Capabilities []RepositoryCapability `json:"capabilities"`
 
+	// Degraded Whether the repository's files are unavailable, such as on an unmounted network share.
+	// Its media are kept and it recovers automatically once the files are available again.
+	Degraded bool `json:"degraded"`
+
+	// DegradedReason The reason of the repository being degraded, missing if it isn't.
+	DegradedReason *string `json:"degraded_reason,omitempty"`
+
 	// Id The repository ID, alphanumeric, lowercase, non-blank ([a-z0-9-_]).
 	Id string `json:"id"`
 
@@ -211,11 +1057,60 @@ type Repository struct {
 // RepositoryCapability defines model for RepositoryCapability.
 type RepositoryCapability string
 
+// RuleCondition defines model for RuleCondition.
+type RuleCondition struct {
+	Field RuleField `json:"field"`
+	Op    RuleOp    `json:"op"`
+
+	// Value The compared value, numbers and booleans are in their textual form.
+	Value string `json:"value"`
+}
+
+// RuleField defines model for RuleField.
+type RuleField string
+
+// RuleOp defines model for RuleOp.
+type RuleOp string
+
+// Season defines model for Season.
+type Season struct {
+	// Episodes The season's episodes, sorted by their number.
+	Episodes []Media `json:"episodes"`
+	Season   int     `json:"season"`
+	SeriesId string  `json:"series_id"`
+}
+
+// SeasonSummary defines model for SeasonSummary.
+type SeasonSummary struct {
+	// EpisodeCount The number of the season's episodes in the repository.
+	EpisodeCount int `json:"episode_count"`
+
+	// Season The season number, zero for specials.
+	Season int `json:"season"`
+}
+
+// Series defines model for Series.
+type Series struct {
+	// EpisodeCount The number of the series' episodes in the repository.
+	EpisodeCount int `json:"episode_count"`
+
+	// Id The series ID, made from its title and release year.
+	Id   string         `json:"id"`
+	Meta SeriesMetadata `json:"meta"`
+
+	// Seasons The seasons with episodes in the repository, sorted by number.
+	Seasons []SeasonSummary `json:"seasons"`
+}
+
 // SeriesMetadata defines model for SeriesMetadata.
 type SeriesMetadata struct {
 	// Cast The people casted in the media.
 	Cast []CastMember `json:"cast"`
 
+	// Confidence The confidence of the metadata being of the media, as matched by the title and release year of its file name,
+	// absent if it wasn't scored, such as of metadata matched by an ID. Below 0.5, the metadata is presumed to be of other media.
+	Confidence *float32 `json:"confidence,omitempty"`
+
 	// Countries The countries that took part in producing the media.
 	Countries []string `json:"countries"`
 
@@ -237,6 +1132,10 @@ type SeriesMetadata struct {
 	// ReleaseDate The media initial release date and time.
 	ReleaseDate time.Time `json:"release_date"`
 
+	// ReleaseDateDisplay The release date as a long date in the requested language (`lang` query parameter or `Accept-Language` header),
+	// such as "January 2, 2006", absent if the release date is unknown. English is used for languages without a known date format.
+	ReleaseDateDisplay *string `json:"release_date_display,omitempty"`
+
 	// Title The media title.
 	Title string       `json:"title"`
 	Type  MetadataType `json:"type"`
@@ -245,6 +1144,306 @@ type SeriesMetadata struct {
 	VoteRating float32 `json:"vote_rating"`
 }
 
+// SessionQuality defines model for SessionQuality.
+type SessionQuality struct {
+	// MaxAudioBitRate The maximum audio bit rate in bits per second.
+	MaxAudioBitRate *int64 `json:"max_audio_bit_rate,omitempty"`
+
+	// MaxHeight The maximum video height in pixels, such as 480, the aspect ratio is kept.
+	MaxHeight *int `json:"max_height,omitempty"`
+
+	// MaxVideoBitRate The maximum video bit rate in bits per second.
+	MaxVideoBitRate *int64 `json:"max_video_bit_rate,omitempty"`
+}
+
+// SortOrder defines model for SortOrder.
+type SortOrder string
+
+// StreamMode defines model for StreamMode.
+type StreamMode string
+
+// SubtitleTrack defines model for SubtitleTrack.
+type SubtitleTrack struct {
+	// Codec The subtitle codec name, such as "subrip", "ass" or "hdmv_pgs_subtitle".
+	Codec string `json:"codec"`
+
+	// Convertible Whether the track is text-based and can be converted to WebVTT.
+	Convertible bool `json:"convertible"`
+
+	// External Whether the track is in a sidecar file, rather than embedded in the media file.
+	External bool `json:"external"`
+
+	// Id The subtitle track ID, alphanumeric, lowercase, non-blank ([a-z0-9-_]).
+	Id string `json:"id"`
+
+	// Language The subtitle language (BCP 47 tag), null if unknown.
+	Language *string `json:"language"`
+}
+
+// SuggestionSource defines model for SuggestionSource.
+type SuggestionSource string
+
+// System defines model for System.
+type System struct {
+	Ffmpeg *FFmpegFeatures `json:"ffmpeg,omitempty"`
+
+	// LatestVersion The version of the latest release, missing if it wasn't checked.
+	LatestVersion *string `json:"latest_version,omitempty"`
+
+	// UpdateAvailable Whether a newer release was found by the periodic update check, installable with the `update` sub-command.
+	UpdateAvailable bool `json:"update_available"`
+
+	// Version The version of the server, such as `v1.2.0`, `dev` for development builds.
+	Version string `json:"version"`
+}
+
+// TagsRequest defines model for TagsRequest.
+type TagsRequest struct {
+	// Tags The tags, alphanumeric, lowercase, non-blank ([a-z0-9-_]), an empty list removes all tags.
+	Tags []string `json:"tags"`
+}
+
+// TranscodeOption defines model for TranscodeOption.
+type TranscodeOption string
+
+// TrashedMedia defines model for TrashedMedia.
+type TrashedMedia struct {
+	// Expires The time of the media file being deleted for good, absent if it's kept until restored.
+	Expires *time.Time `json:"expires,omitempty"`
+	Media   Media      `json:"media"`
+
+	// Path The original path of the media file, relative to the repository directory.
+	Path string `json:"path"`
+
+	// Trashed The time of the media being deleted.
+	Trashed time.Time `json:"trashed"`
+}
+
+// UserExport defines model for UserExport.
+type UserExport struct {
+	// ExportedAt The time of the export.
+	ExportedAt time.Time `json:"exported_at"`
+
+	// FollowedSeries The followed series, sorted by the repository ID and series key.
+	FollowedSeries []ExportedSeries `json:"followed_series"`
+
+	// Progress The playback progress of played or hidden media, sorted by the repository and media ID.
+	Progress []ExportedProgress `json:"progress"`
+
+	// User The user name, empty if authentication is disabled.
+	User string `json:"user"`
+}
+
+// WatchedFilter defines model for WatchedFilter.
+type WatchedFilter string
+
+// GetCalendarParams defines parameters for GetCalendar.
+type GetCalendarParams struct {
+	// From The first day of the range, inclusive, such as "2024-01-31", defaults to today (UTC).
+	From *string `form:"from,omitempty" json:"from,omitempty"`
+
+	// To The last day of the range, inclusive, defaults to 30 days after `from`, at most a year after it.
+	To *string `form:"to,omitempty" json:"to,omitempty"`
+
+	// RepoId The repository ID or alias to list the episodes of, all repositories are listed if not set.
+	RepoId *string `form:"repoId,omitempty" json:"repoId,omitempty"`
+}
+
+// GetEventsParams defines parameters for GetEvents.
+type GetEventsParams struct {
+	// RepoId The repository ID or alias to receive events of, events of all repositories are received if not set.
+	RepoId *string `form:"repoId,omitempty" json:"repoId,omitempty"`
+}
+
+// SearchMetaParams defines parameters for SearchMeta.
+type SearchMetaParams struct {
+	// Q The search query, such as the movie or series title.
+	Q string `form:"q" json:"q"`
+
+	// Type The type of the searched metadata, movies and series are searched if not set.
+	Type *MetadataType `form:"type,omitempty" json:"type,omitempty"`
+
+	// RepoId The repository ID or alias whose metadata sources are searched.
+	RepoId string `form:"repoId" json:"repoId"`
+}
+
+// GetRepoFingerprintParams defines parameters for GetRepoFingerprint.
+type GetRepoFingerprintParams struct {
+	// Items Whether the digests of the single media are listed too, for finding the media that differ.
+	Items *bool `form:"items,omitempty" json:"items,omitempty"`
+}
+
+// GetRepoDirectoryParams defines parameters for GetRepoDirectory.
+type GetRepoDirectoryParams struct {
+	// Path The path of the directory, relative to the repository's directory, defaults to the directory itself.
+	Path *string `form:"path,omitempty" json:"path,omitempty"`
+}
+
+// GetRepoMediaParams defines parameters for GetRepoMedia.
+type GetRepoMediaParams struct {
+	// Type The metadata type of listed media, media without metadata are of the `unknown` type.
+	Type *MetadataType `form:"type,omitempty" json:"type,omitempty"`
+
+	// Genre The genre name of listed media, case-insensitive, episodes are of the genres of their series.
+	Genre *string `form:"genre,omitempty" json:"genre,omitempty"`
+
+	// Tag A user-defined tag of listed media (see the `updateRepoMediaTags` operation), case-insensitive.
+	Tag *string `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// Year The release year of listed media.
+	Year *int `form:"year,omitempty" json:"year,omitempty"`
+
+	// Sort The sort key, media with the same key are sorted by their ID.
+	Sort *MediaSortKey `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Order The sort order.
+	Order *SortOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// Watched The playback state of listed media for the requesting user, `in-progress` selects started, but unfinished media.
+	Watched *WatchedFilter `form:"watched,omitempty" json:"watched,omitempty"`
+
+	// Match How well the metadata of listed media matched, `unmatched` selects media without metadata
+	// and `low_confidence` media with metadata presumed to be of other media, for fixing them (see the `updateRepoMediaMeta` operation).
+	// Likely matches of such media are suggested by the `getRepoMediaMetaSuggestions` operation.
+	Match *MatchFilter `form:"match,omitempty" json:"match,omitempty"`
+
+	// AudioLanguage The language (BCP 47 tag) of an audio track of listed media, such as `en`, regions and scripts are disregarded.
+	// Audio tracks are only known for probed media of remux-capable repositories (see the `getRepoMediaAudioTracks` operation).
+	AudioLanguage *string `form:"audio_language,omitempty" json:"audio_language,omitempty"`
+
+	// IncludeHidden Whether media hidden by the authenticated user (see the `hideRepoMedia` operation) are listed too.
+	IncludeHidden *bool `form:"include_hidden,omitempty" json:"include_hidden,omitempty"`
+
+	// Offset The number of listed media to skip.
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Limit The maximum number of listed media, all are listed if not set.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ScanRepoParams defines parameters for ScanRepo.
+type ScanRepoParams struct {
+	// Full Whether the formats of all files should be detected again, not only of new and changed ones.
+	Full *bool `form:"full,omitempty" json:"full,omitempty"`
+}
+
+// DeleteRepoMediaParams defines parameters for DeleteRepoMedia.
+type DeleteRepoMediaParams struct {
+	// DeleteFile Whether the media file should be deleted from the filesystem too, or moved to the trash if it's enabled.
+	DeleteFile *bool `form:"delete_file,omitempty" json:"delete_file,omitempty"`
+}
+
+// ConvertRepoMediaParams defines parameters for ConvertRepoMedia.
+type ConvertRepoMediaParams struct {
+	// XKatanaSession The client session ID, transcodes are limited to the session's quality cap if set (see `updateSessionQuality`).
+	XKatanaSession *string `json:"X-Katana-Session,omitempty"`
+}
+
+// UnhideRepoMediaParams defines parameters for UnhideRepoMedia.
+type UnhideRepoMediaParams struct {
+	// Series Whether the whole series of the episode should be shown, including episodes added later.
+	Series *bool `form:"series,omitempty" json:"series,omitempty"`
+}
+
+// HideRepoMediaParams defines parameters for HideRepoMedia.
+type HideRepoMediaParams struct {
+	// Series Whether the whole series of the episode should be hidden, including episodes added later.
+	Series *bool `form:"series,omitempty" json:"series,omitempty"`
+}
+
+// GetRepoMediaImagesParams defines parameters for GetRepoMediaImages.
+type GetRepoMediaImagesParams struct {
+	// Type The type of listed images, all are listed if not set.
+	Type *ImageType `form:"type,omitempty" json:"type,omitempty"`
+
+	// Page The page number, starting at 1.
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+}
+
+// GetRepoMediaImageParams defines parameters for GetRepoMediaImage.
+type GetRepoMediaImageParams struct {
+	// Size The image size, `original` if not specified.
+	Size *ImageSize `form:"size,omitempty" json:"size,omitempty"`
+}
+
+// GetRepoMediaStreamsParams defines parameters for GetRepoMediaStreams.
+type GetRepoMediaStreamsParams struct {
+	// Accept The acceptable media types with optional quality values, such as `video/mp4, video/*;q=0.5`.
+	Accept *string `json:"Accept,omitempty"`
+}
+
+// GetRepoMediaStreamParams defines parameters for GetRepoMediaStream.
+type GetRepoMediaStreamParams struct {
+	// Mode How media not remuxed to the format yet are streamed, ignored for the "raw" format.
+	Mode *StreamMode `form:"mode,omitempty" json:"mode,omitempty"`
+
+	// Start The source position to start the stream at in seconds, for seeking ahead without converting the media from the beginning first.
+	// The streams are then copied into the format from the position by a transcode, each position is cached separately.
+	// It needs the `transcode` capability and a conversion backend honoring the `seeking` transcoding setting (see `TranscodeOption`),
+	// it's not supported with the "raw" format and in the `progressive` mode.
+	Start *float64 `form:"start,omitempty" json:"start,omitempty"`
+}
+
+// DeleteSessionQualityParams defines parameters for DeleteSessionQuality.
+type DeleteSessionQualityParams struct {
+	// XKatanaSession The client session ID, an opaque string generated by the client for the lifetime of its session (such as an app launch).
+	XKatanaSession string `json:"X-Katana-Session"`
+}
+
+// GetSessionQualityParams defines parameters for GetSessionQuality.
+type GetSessionQualityParams struct {
+	// XKatanaSession The client session ID, an opaque string generated by the client for the lifetime of its session (such as an app launch).
+	XKatanaSession string `json:"X-Katana-Session"`
+}
+
+// UpdateSessionQualityParams defines parameters for UpdateSessionQuality.
+type UpdateSessionQualityParams struct {
+	// XKatanaSession The client session ID, an opaque string generated by the client for the lifetime of its session (such as an app launch).
+	XKatanaSession string `json:"X-Katana-Session"`
+}
+
+// GetUsageParams defines parameters for GetUsage.
+type GetUsageParams struct {
+	// Month The month in the server's local time zone (YYYY-MM), defaults to the current one.
+	Month *string `form:"month,omitempty" json:"month,omitempty"`
+}
+
+// ExportUserDataParams defines parameters for ExportUserData.
+type ExportUserDataParams struct {
+	// Format The format of the export, defaults to `json`.
+	Format *ExportFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// CreateCollectionJSONRequestBody defines body for CreateCollection for application/json ContentType.
+type CreateCollectionJSONRequestBody = CollectionRequest
+
+// UpdateCollectionJSONRequestBody defines body for UpdateCollection for application/json ContentType.
+type UpdateCollectionJSONRequestBody = CollectionRequest
+
+// AddRepoMediaJSONRequestBody defines body for AddRepoMedia for application/json ContentType.
+type AddRepoMediaJSONRequestBody = AddMediaRequest
+
+// AddRepoMediaMultipartRequestBody defines body for AddRepoMedia for multipart/form-data ContentType.
+type AddRepoMediaMultipartRequestBody = MediaUpload
+
+// BulkRepoMediaJSONRequestBody defines body for BulkRepoMedia for application/json ContentType.
+type BulkRepoMediaJSONRequestBody = BulkRequest
+
+// ConvertRepoMediaJSONRequestBody defines body for ConvertRepoMedia for application/json ContentType.
+type ConvertRepoMediaJSONRequestBody = ConvertRequest
+
+// UpdateRepoMediaMetaJSONRequestBody defines body for UpdateRepoMediaMeta for application/json ContentType.
+type UpdateRepoMediaMetaJSONRequestBody = MetadataQuery
+
+// UpdateRepoMediaProgressJSONRequestBody defines body for UpdateRepoMediaProgress for application/json ContentType.
+type UpdateRepoMediaProgressJSONRequestBody = PlaybackProgressRequest
+
+// UpdateRepoMediaTagsJSONRequestBody defines body for UpdateRepoMediaTags for application/json ContentType.
+type UpdateRepoMediaTagsJSONRequestBody = TagsRequest
+
+// UpdateSessionQualityJSONRequestBody defines body for UpdateSessionQuality for application/json ContentType.
+type UpdateSessionQualityJSONRequestBody = SessionQuality
+
 // AsMetadata returns the union data inside the Media_Meta as a Metadata
 func (t Media_Meta) AsMetadata() (Metadata, error) {
 	var body Metadata
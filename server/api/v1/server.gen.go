@@ -8,7 +8,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/oapi-codegen/runtime"
@@ -17,33 +19,303 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Gets the episodes of series in the library airing in a date range.
+	// (GET /calendar)
+	GetCalendar(w http.ResponseWriter, r *http.Request, params GetCalendarParams)
+	// Gets all collections.
+	// (GET /collections)
+	GetCollections(w http.ResponseWriter, r *http.Request)
+	// Creates a collection.
+	// (POST /collections)
+	CreateCollection(w http.ResponseWriter, r *http.Request)
+	// Deletes a collection.
+	// (DELETE /collections/{collectionId})
+	DeleteCollection(w http.ResponseWriter, r *http.Request, collectionId string)
+	// Gets a collection.
+	// (GET /collections/{collectionId})
+	GetCollectionById(w http.ResponseWriter, r *http.Request, collectionId string)
+	// Updates a collection.
+	// (PUT /collections/{collectionId})
+	UpdateCollection(w http.ResponseWriter, r *http.Request, collectionId string)
+	// Subscribes to change notifications.
+	// (GET /events)
+	GetEvents(w http.ResponseWriter, r *http.Request, params GetEventsParams)
+	// Gets the supported media formats.
+	// (GET /formats)
+	GetFormats(w http.ResponseWriter, r *http.Request)
+	// Cancels a job.
+	// (DELETE /jobs/{jobId})
+	CancelJob(w http.ResponseWriter, r *http.Request, jobId string)
+	// Gets a job.
+	// (GET /jobs/{jobId})
+	GetJobById(w http.ResponseWriter, r *http.Request, jobId string)
+	// Searches metadata match candidates.
+	// (GET /meta/search)
+	SearchMeta(w http.ResponseWriter, r *http.Request, params SearchMetaParams)
 	// Lists repositories.
 	// (GET /repos)
 	GetRepos(w http.ResponseWriter, r *http.Request)
 	// Gets a repository.
 	// (GET /repos/{id})
 	GetRepoById(w http.ResponseWriter, r *http.Request, id string)
+	// Gets a repository's capabilities.
+	// (GET /repos/{id}/capabilities)
+	GetRepoCapabilities(w http.ResponseWriter, r *http.Request, id string)
+	// Gets a repository's content fingerprint.
+	// (GET /repos/{id}/fingerprint)
+	GetRepoFingerprint(w http.ResponseWriter, r *http.Request, id string, params GetRepoFingerprintParams)
+	// Lists a directory of a repository.
+	// (GET /repos/{id}/fs)
+	GetRepoDirectory(w http.ResponseWriter, r *http.Request, id string, params GetRepoDirectoryParams)
+	// Hashes the video frames of a repository's media for identification.
+	// (POST /repos/{id}/identify)
+	IdentifyRepo(w http.ResponseWriter, r *http.Request, id string)
+	// Gets a repository's last integrity verification.
+	// (GET /repos/{id}/integrity)
+	GetRepoIntegrity(w http.ResponseWriter, r *http.Request, id string)
+	// Verifies a repository's integrity.
+	// (POST /repos/{id}/integrity)
+	VerifyRepo(w http.ResponseWriter, r *http.Request, id string)
 	// Lists a repository's media.
 	// (GET /repos/{id}/media)
-	GetRepoMedia(w http.ResponseWriter, r *http.Request, id string)
+	GetRepoMedia(w http.ResponseWriter, r *http.Request, id string, params GetRepoMediaParams)
+	// Adds media to a repository.
+	// (POST /repos/{id}/media)
+	AddRepoMedia(w http.ResponseWriter, r *http.Request, id string)
+	// Applies an operation to multiple media.
+	// (POST /repos/{id}/media/bulk)
+	BulkRepoMedia(w http.ResponseWriter, r *http.Request, id string)
+	// Probes a repository's media.
+	// (POST /repos/{id}/probe)
+	ProbeRepo(w http.ResponseWriter, r *http.Request, id string)
+	// Gets a repository's replication manifest.
+	// (GET /repos/{id}/replica)
+	GetRepoReplica(w http.ResponseWriter, r *http.Request, id string)
+	// Replicates a repository from its origin.
+	// (POST /repos/{id}/replicate)
+	ReplicateRepo(w http.ResponseWriter, r *http.Request, id string)
+	// Gets a repository's last scan.
+	// (GET /repos/{id}/scan)
+	GetRepoScan(w http.ResponseWriter, r *http.Request, id string)
+	// Scans a repository.
+	// (POST /repos/{id}/scan)
+	ScanRepo(w http.ResponseWriter, r *http.Request, id string, params ScanRepoParams)
+	// Gets a repository's series.
+	// (GET /repos/{id}/series)
+	GetRepoSeries(w http.ResponseWriter, r *http.Request, id string)
+	// Gets a season of a repository's series.
+	// (GET /repos/{id}/series/{seriesId}/seasons/{season})
+	GetRepoSeriesSeason(w http.ResponseWriter, r *http.Request, id string, seriesId string, season int)
+	// Purges a repository's cache.
+	// (DELETE /repos/{repoId}/cache)
+	PurgeRepoCache(w http.ResponseWriter, r *http.Request, repoId string)
+	// Gets a repository's cache usage.
+	// (GET /repos/{repoId}/cache)
+	GetRepoCache(w http.ResponseWriter, r *http.Request, repoId string)
+	// Removes a repository's media.
+	// (DELETE /repos/{repoId}/media/{mediaId})
+	DeleteRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params DeleteRepoMediaParams)
 	// Gets a repository's media.
 	// (GET /repos/{repoId}/media/{mediaId})
 	GetRepoMediaById(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Gets the cold storage status of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/archive)
+	GetRepoMediaArchive(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Queues moving a repository's media file to cold storage.
+	// (POST /repos/{repoId}/media/{mediaId}/archive)
+	ArchiveRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Lists the audio tracks of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/audio)
+	GetRepoMediaAudioTracks(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Lists the chapters of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/chapters)
+	GetRepoMediaChapters(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Queues a conversion of media.
+	// (POST /repos/{repoId}/media/{mediaId}/convert)
+	ConvertRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params ConvertRepoMediaParams)
+	// Gets the MPEG-DASH manifest of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/dash/manifest.mpd)
+	GetRepoMediaDashManifest(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Gets a representation of a repository's media for MPEG-DASH streaming.
+	// (GET /repos/{repoId}/media/{mediaId}/dash/{representationId})
+	GetRepoMediaDashRepresentation(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, representationId string)
 	// Downloads media.
 	// (GET /repos/{repoId}/media/{mediaId}/download)
 	GetRepoMediaDownload(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
-	// Lists the available variants of a repository's media.
+	// Lists the extras of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/extras)
+	GetRepoMediaExtras(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Gets an HTTP stream of an extra of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/extras/{extraId})
+	GetRepoMediaExtra(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, extraId string)
+	// Stops following the series of an episode for the authenticated user.
+	// (DELETE /repos/{repoId}/media/{mediaId}/follow)
+	UnfollowRepoMediaSeries(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Follows the series of an episode for the authenticated user.
+	// (POST /repos/{repoId}/media/{mediaId}/follow)
+	FollowRepoMediaSeries(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Shows hidden media in the authenticated user's listings again.
+	// (DELETE /repos/{repoId}/media/{mediaId}/hide)
+	UnhideRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params UnhideRepoMediaParams)
+	// Hides media from the authenticated user's listings.
+	// (POST /repos/{repoId}/media/{mediaId}/hide)
+	HideRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params HideRepoMediaParams)
+	// Lists the image gallery of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/images)
+	GetRepoMediaImages(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params GetRepoMediaImagesParams)
+	// Gets a promotional image of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/images/{imageId})
+	GetRepoMediaImage(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, imageId string, params GetRepoMediaImageParams)
+	// Chooses the preferred poster or backdrop of a repository's media.
+	// (PUT /repos/{repoId}/media/{mediaId}/images/{imageId}/preferred)
+	SetRepoMediaPreferredImage(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, imageId string)
+	// Unlocks a repository's media.
+	// (DELETE /repos/{repoId}/media/{mediaId}/lock)
+	UnlockRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Locks a repository's media against automatic changes.
+	// (POST /repos/{repoId}/media/{mediaId}/lock)
+	LockRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Re-matches a repository's media metadata.
+	// (PUT /repos/{repoId}/media/{mediaId}/meta)
+	UpdateRepoMediaMeta(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Refreshes a repository's media metadata.
+	// (POST /repos/{repoId}/media/{mediaId}/meta/refresh)
+	RefreshRepoMediaMeta(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Suggests likely metadata matches of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/meta/suggestions)
+	GetRepoMediaMetaSuggestions(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Gets a preview clip of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/preview)
+	GetRepoMediaPreview(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Gets the playback progress of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/progress)
+	GetRepoMediaProgress(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Updates the playback progress of a repository's media.
+	// (PUT /repos/{repoId}/media/{mediaId}/progress)
+	UpdateRepoMediaProgress(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Queues moving a repository's media file back from cold storage.
+	// (POST /repos/{repoId}/media/{mediaId}/restore)
+	RestoreRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Gets a repository's media streaming statistics.
+	// (GET /repos/{repoId}/media/{mediaId}/stats)
+	GetRepoMediaStats(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Gets the thumbnail track of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/storyboard)
+	GetRepoMediaStoryboard(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Gets a storyboard sprite sheet of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/storyboard/{sheet})
+	GetRepoMediaStoryboardSheet(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, sheet int)
+	// Lists the available variants of a repository's media or gets a negotiated HTTP media stream.
 	// (GET /repos/{repoId}/media/{mediaId}/stream)
-	GetRepoMediaStreams(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	GetRepoMediaStreams(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params GetRepoMediaStreamsParams)
 	// Gets a HTTP media stream.
 	// (GET /repos/{repoId}/media/{mediaId}/stream/{format})
-	GetRepoMediaStream(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, format string)
+	GetRepoMediaStream(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, format string, params GetRepoMediaStreamParams)
+	// Lists the subtitle tracks of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/subtitles)
+	GetRepoMediaSubtitles(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Gets a subtitle track as WebVTT.
+	// (GET /repos/{repoId}/media/{mediaId}/subtitles/{trackId}.vtt)
+	GetRepoMediaSubtitle(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, trackId string)
+	// Replaces a repository's media tags.
+	// (PUT /repos/{repoId}/media/{mediaId}/tags)
+	UpdateRepoMediaTags(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Gets a repository's trashed media.
+	// (GET /repos/{repoId}/trash)
+	GetRepoTrash(w http.ResponseWriter, r *http.Request, repoId string)
+	// Restores a repository's trashed media.
+	// (POST /repos/{repoId}/trash/{mediaId}/restore)
+	RestoreRepoTrash(w http.ResponseWriter, r *http.Request, repoId string, mediaId string)
+	// Removes the quality cap of the client session.
+	// (DELETE /session/quality)
+	DeleteSessionQuality(w http.ResponseWriter, r *http.Request, params DeleteSessionQualityParams)
+	// Gets the quality cap of the client session.
+	// (GET /session/quality)
+	GetSessionQuality(w http.ResponseWriter, r *http.Request, params GetSessionQualityParams)
+	// Sets a quality cap for the client session.
+	// (PUT /session/quality)
+	UpdateSessionQuality(w http.ResponseWriter, r *http.Request, params UpdateSessionQualityParams)
+	// Gets the server's system information.
+	// (GET /system)
+	GetSystem(w http.ResponseWriter, r *http.Request)
+	// Gets the bytes served to clients in a month.
+	// (GET /usage)
+	GetUsage(w http.ResponseWriter, r *http.Request, params GetUsageParams)
+	// Exports the requesting user's watch data.
+	// (GET /users/me/export)
+	ExportUserData(w http.ResponseWriter, r *http.Request, params ExportUserDataParams)
 }
 
 // Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
 
 type Unimplemented struct{}
 
+// Gets the episodes of series in the library airing in a date range.
+// (GET /calendar)
+func (_ Unimplemented) GetCalendar(w http.ResponseWriter, r *http.Request, params GetCalendarParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets all collections.
+// (GET /collections)
+func (_ Unimplemented) GetCollections(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Creates a collection.
+// (POST /collections)
+func (_ Unimplemented) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Deletes a collection.
+// (DELETE /collections/{collectionId})
+func (_ Unimplemented) DeleteCollection(w http.ResponseWriter, r *http.Request, collectionId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a collection.
+// (GET /collections/{collectionId})
+func (_ Unimplemented) GetCollectionById(w http.ResponseWriter, r *http.Request, collectionId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Updates a collection.
+// (PUT /collections/{collectionId})
+func (_ Unimplemented) UpdateCollection(w http.ResponseWriter, r *http.Request, collectionId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Subscribes to change notifications.
+// (GET /events)
+func (_ Unimplemented) GetEvents(w http.ResponseWriter, r *http.Request, params GetEventsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets the supported media formats.
+// (GET /formats)
+func (_ Unimplemented) GetFormats(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Cancels a job.
+// (DELETE /jobs/{jobId})
+func (_ Unimplemented) CancelJob(w http.ResponseWriter, r *http.Request, jobId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a job.
+// (GET /jobs/{jobId})
+func (_ Unimplemented) GetJobById(w http.ResponseWriter, r *http.Request, jobId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Searches metadata match candidates.
+// (GET /meta/search)
+func (_ Unimplemented) SearchMeta(w http.ResponseWriter, r *http.Request, params SearchMetaParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // Lists repositories.
 // (GET /repos)
 func (_ Unimplemented) GetRepos(w http.ResponseWriter, r *http.Request) {
@@ -56,9 +328,117 @@ func (_ Unimplemented) GetRepoById(w http.ResponseWriter, r *http.Request, id st
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// Gets a repository's capabilities.
+// (GET /repos/{id}/capabilities)
+func (_ Unimplemented) GetRepoCapabilities(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a repository's content fingerprint.
+// (GET /repos/{id}/fingerprint)
+func (_ Unimplemented) GetRepoFingerprint(w http.ResponseWriter, r *http.Request, id string, params GetRepoFingerprintParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Lists a directory of a repository.
+// (GET /repos/{id}/fs)
+func (_ Unimplemented) GetRepoDirectory(w http.ResponseWriter, r *http.Request, id string, params GetRepoDirectoryParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Hashes the video frames of a repository's media for identification.
+// (POST /repos/{id}/identify)
+func (_ Unimplemented) IdentifyRepo(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a repository's last integrity verification.
+// (GET /repos/{id}/integrity)
+func (_ Unimplemented) GetRepoIntegrity(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Verifies a repository's integrity.
+// (POST /repos/{id}/integrity)
+func (_ Unimplemented) VerifyRepo(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // Lists a repository's media.
 // (GET /repos/{id}/media)
-func (_ Unimplemented) GetRepoMedia(w http.ResponseWriter, r *http.Request, id string) {
+func (_ Unimplemented) GetRepoMedia(w http.ResponseWriter, r *http.Request, id string, params GetRepoMediaParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Adds media to a repository.
+// (POST /repos/{id}/media)
+func (_ Unimplemented) AddRepoMedia(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Applies an operation to multiple media.
+// (POST /repos/{id}/media/bulk)
+func (_ Unimplemented) BulkRepoMedia(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Probes a repository's media.
+// (POST /repos/{id}/probe)
+func (_ Unimplemented) ProbeRepo(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a repository's replication manifest.
+// (GET /repos/{id}/replica)
+func (_ Unimplemented) GetRepoReplica(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Replicates a repository from its origin.
+// (POST /repos/{id}/replicate)
+func (_ Unimplemented) ReplicateRepo(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a repository's last scan.
+// (GET /repos/{id}/scan)
+func (_ Unimplemented) GetRepoScan(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Scans a repository.
+// (POST /repos/{id}/scan)
+func (_ Unimplemented) ScanRepo(w http.ResponseWriter, r *http.Request, id string, params ScanRepoParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a repository's series.
+// (GET /repos/{id}/series)
+func (_ Unimplemented) GetRepoSeries(w http.ResponseWriter, r *http.Request, id string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a season of a repository's series.
+// (GET /repos/{id}/series/{seriesId}/seasons/{season})
+func (_ Unimplemented) GetRepoSeriesSeason(w http.ResponseWriter, r *http.Request, id string, seriesId string, season int) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Purges a repository's cache.
+// (DELETE /repos/{repoId}/cache)
+func (_ Unimplemented) PurgeRepoCache(w http.ResponseWriter, r *http.Request, repoId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a repository's cache usage.
+// (GET /repos/{repoId}/cache)
+func (_ Unimplemented) GetRepoCache(w http.ResponseWriter, r *http.Request, repoId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Removes a repository's media.
+// (DELETE /repos/{repoId}/media/{mediaId})
+func (_ Unimplemented) DeleteRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params DeleteRepoMediaParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
@@ -68,21 +448,255 @@ func (_ Unimplemented) GetRepoMediaById(w http.ResponseWriter, r *http.Request,
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// Gets the cold storage status of a repository's media.
+// (GET /repos/{repoId}/media/{mediaId}/archive)
+func (_ Unimplemented) GetRepoMediaArchive(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Queues moving a repository's media file to cold storage.
+// (POST /repos/{repoId}/media/{mediaId}/archive)
+func (_ Unimplemented) ArchiveRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Lists the audio tracks of a repository's media.
+// (GET /repos/{repoId}/media/{mediaId}/audio)
+func (_ Unimplemented) GetRepoMediaAudioTracks(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Lists the chapters of a repository's media.
+// (GET /repos/{repoId}/media/{mediaId}/chapters)
+func (_ Unimplemented) GetRepoMediaChapters(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Queues a conversion of media.
+// (POST /repos/{repoId}/media/{mediaId}/convert)
+func (_ Unimplemented) ConvertRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params ConvertRepoMediaParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets the MPEG-DASH manifest of a repository's media.
+// (GET /repos/{repoId}/media/{mediaId}/dash/manifest.mpd)
+func (_ Unimplemented) GetRepoMediaDashManifest(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a representation of a repository's media for MPEG-DASH streaming.
+// (GET /repos/{repoId}/media/{mediaId}/dash/{representationId})
+func (_ Unimplemented) GetRepoMediaDashRepresentation(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, representationId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // Downloads media.
 // (GET /repos/{repoId}/media/{mediaId}/download)
 func (_ Unimplemented) GetRepoMediaDownload(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// Lists the available variants of a repository's media.
+// Lists the extras of a repository's media.
+// (GET /repos/{repoId}/media/{mediaId}/extras)
+func (_ Unimplemented) GetRepoMediaExtras(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets an HTTP stream of an extra of a repository's media.
+// (GET /repos/{repoId}/media/{mediaId}/extras/{extraId})
+func (_ Unimplemented) GetRepoMediaExtra(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, extraId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Stops following the series of an episode for the authenticated user.
+// (DELETE /repos/{repoId}/media/{mediaId}/follow)
+func (_ Unimplemented) UnfollowRepoMediaSeries(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Follows the series of an episode for the authenticated user.
+// (POST /repos/{repoId}/media/{mediaId}/follow)
+func (_ Unimplemented) FollowRepoMediaSeries(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Shows hidden media in the authenticated user's listings again.
+// (DELETE /repos/{repoId}/media/{mediaId}/hide)
+func (_ Unimplemented) UnhideRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params UnhideRepoMediaParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Hides media from the authenticated user's listings.
+// (POST /repos/{repoId}/media/{mediaId}/hide)
+func (_ Unimplemented) HideRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params HideRepoMediaParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Lists the image gallery of a repository's media.
+// (GET /repos/{repoId}/media/{mediaId}/images)
+func (_ Unimplemented) GetRepoMediaImages(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params GetRepoMediaImagesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a promotional image of a repository's media.
+// (GET /repos/{repoId}/media/{mediaId}/images/{imageId})
+func (_ Unimplemented) GetRepoMediaImage(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, imageId string, params GetRepoMediaImageParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Chooses the preferred poster or backdrop of a repository's media.
+// (PUT /repos/{repoId}/media/{mediaId}/images/{imageId}/preferred)
+func (_ Unimplemented) SetRepoMediaPreferredImage(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, imageId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Unlocks a repository's media.
+// (DELETE /repos/{repoId}/media/{mediaId}/lock)
+func (_ Unimplemented) UnlockRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Locks a repository's media against automatic changes.
+// (POST /repos/{repoId}/media/{mediaId}/lock)
+func (_ Unimplemented) LockRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Re-matches a repository's media metadata.
+// (PUT /repos/{repoId}/media/{mediaId}/meta)
+func (_ Unimplemented) UpdateRepoMediaMeta(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Refreshes a repository's media metadata.
+// (POST /repos/{repoId}/media/{mediaId}/meta/refresh)
+func (_ Unimplemented) RefreshRepoMediaMeta(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Suggests likely metadata matches of a repository's media.
+// (GET /repos/{repoId}/media/{mediaId}/meta/suggestions)
+func (_ Unimplemented) GetRepoMediaMetaSuggestions(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a preview clip of a repository's media.
+// (GET /repos/{repoId}/media/{mediaId}/preview)
+func (_ Unimplemented) GetRepoMediaPreview(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets the playback progress of a repository's media.
+// (GET /repos/{repoId}/media/{mediaId}/progress)
+func (_ Unimplemented) GetRepoMediaProgress(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Updates the playback progress of a repository's media.
+// (PUT /repos/{repoId}/media/{mediaId}/progress)
+func (_ Unimplemented) UpdateRepoMediaProgress(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Queues moving a repository's media file back from cold storage.
+// (POST /repos/{repoId}/media/{mediaId}/restore)
+func (_ Unimplemented) RestoreRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a repository's media streaming statistics.
+// (GET /repos/{repoId}/media/{mediaId}/stats)
+func (_ Unimplemented) GetRepoMediaStats(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets the thumbnail track of a repository's media.
+// (GET /repos/{repoId}/media/{mediaId}/storyboard)
+func (_ Unimplemented) GetRepoMediaStoryboard(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a storyboard sprite sheet of a repository's media.
+// (GET /repos/{repoId}/media/{mediaId}/storyboard/{sheet})
+func (_ Unimplemented) GetRepoMediaStoryboardSheet(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, sheet int) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Lists the available variants of a repository's media or gets a negotiated HTTP media stream.
 // (GET /repos/{repoId}/media/{mediaId}/stream)
-func (_ Unimplemented) GetRepoMediaStreams(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+func (_ Unimplemented) GetRepoMediaStreams(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params GetRepoMediaStreamsParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
 // Gets a HTTP media stream.
 // (GET /repos/{repoId}/media/{mediaId}/stream/{format})
-func (_ Unimplemented) GetRepoMediaStream(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, format string) {
+func (_ Unimplemented) GetRepoMediaStream(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, format string, params GetRepoMediaStreamParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Lists the subtitle tracks of a repository's media.
+// (GET /repos/{repoId}/media/{mediaId}/subtitles)
+func (_ Unimplemented) GetRepoMediaSubtitles(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a subtitle track as WebVTT.
+// (GET /repos/{repoId}/media/{mediaId}/subtitles/{trackId}.vtt)
+func (_ Unimplemented) GetRepoMediaSubtitle(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, trackId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Replaces a repository's media tags.
+// (PUT /repos/{repoId}/media/{mediaId}/tags)
+func (_ Unimplemented) UpdateRepoMediaTags(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets a repository's trashed media.
+// (GET /repos/{repoId}/trash)
+func (_ Unimplemented) GetRepoTrash(w http.ResponseWriter, r *http.Request, repoId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Restores a repository's trashed media.
+// (POST /repos/{repoId}/trash/{mediaId}/restore)
+func (_ Unimplemented) RestoreRepoTrash(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Removes the quality cap of the client session.
+// (DELETE /session/quality)
+func (_ Unimplemented) DeleteSessionQuality(w http.ResponseWriter, r *http.Request, params DeleteSessionQualityParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets the quality cap of the client session.
+// (GET /session/quality)
+func (_ Unimplemented) GetSessionQuality(w http.ResponseWriter, r *http.Request, params GetSessionQualityParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Sets a quality cap for the client session.
+// (PUT /session/quality)
+func (_ Unimplemented) UpdateSessionQuality(w http.ResponseWriter, r *http.Request, params UpdateSessionQualityParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets the server's system information.
+// (GET /system)
+func (_ Unimplemented) GetSystem(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Gets the bytes served to clients in a month.
+// (GET /usage)
+func (_ Unimplemented) GetUsage(w http.ResponseWriter, r *http.Request, params GetUsageParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Exports the requesting user's watch data.
+// (GET /users/me/export)
+func (_ Unimplemented) ExportUserData(w http.ResponseWriter, r *http.Request, params ExportUserDataParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
@@ -95,12 +709,41 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
-// GetRepos operation middleware
-func (siw *ServerInterfaceWrapper) GetRepos(w http.ResponseWriter, r *http.Request) {
+// GetCalendar operation middleware
+func (siw *ServerInterfaceWrapper) GetCalendar(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCalendarParams
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "repoId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "repoId", r.URL.Query(), &params.RepoId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetRepos(w, r)
+		siw.Handler.GetCalendar(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -110,23 +753,27 @@ func (siw *ServerInterfaceWrapper) GetRepos(w http.ResponseWriter, r *http.Reque
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetRepoById operation middleware
-func (siw *ServerInterfaceWrapper) GetRepoById(w http.ResponseWriter, r *http.Request) {
+// GetCollections operation middleware
+func (siw *ServerInterfaceWrapper) GetCollections(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	var err error
-
-	// ------------- Path parameter "id" -------------
-	var id string
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCollections(w, r)
+	}))
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
-		return
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
 	}
 
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateCollection operation middleware
+func (siw *ServerInterfaceWrapper) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetRepoById(w, r, id)
+		siw.Handler.CreateCollection(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -136,23 +783,23 @@ func (siw *ServerInterfaceWrapper) GetRepoById(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetRepoMedia operation middleware
-func (siw *ServerInterfaceWrapper) GetRepoMedia(w http.ResponseWriter, r *http.Request) {
+// DeleteCollection operation middleware
+func (siw *ServerInterfaceWrapper) DeleteCollection(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "id" -------------
-	var id string
+	// ------------- Path parameter "collectionId" -------------
+	var collectionId string
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "collectionId", chi.URLParam(r, "collectionId"), &collectionId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "collectionId", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetRepoMedia(w, r, id)
+		siw.Handler.DeleteCollection(w, r, collectionId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -162,32 +809,49 @@ func (siw *ServerInterfaceWrapper) GetRepoMedia(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetRepoMediaById operation middleware
-func (siw *ServerInterfaceWrapper) GetRepoMediaById(w http.ResponseWriter, r *http.Request) {
+// GetCollectionById operation middleware
+func (siw *ServerInterfaceWrapper) GetCollectionById(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "repoId" -------------
-	var repoId string
+	// ------------- Path parameter "collectionId" -------------
+	var collectionId string
 
-	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "collectionId", chi.URLParam(r, "collectionId"), &collectionId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "collectionId", Err: err})
 		return
 	}
 
-	// ------------- Path parameter "mediaId" -------------
-	var mediaId string
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCollectionById(w, r, collectionId)
+	}))
 
-	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UpdateCollection operation middleware
+func (siw *ServerInterfaceWrapper) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "collectionId" -------------
+	var collectionId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "collectionId", chi.URLParam(r, "collectionId"), &collectionId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "collectionId", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetRepoMediaById(w, r, repoId, mediaId)
+		siw.Handler.UpdateCollection(w, r, collectionId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -197,32 +861,40 @@ func (siw *ServerInterfaceWrapper) GetRepoMediaById(w http.ResponseWriter, r *ht
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetRepoMediaDownload operation middleware
-func (siw *ServerInterfaceWrapper) GetRepoMediaDownload(w http.ResponseWriter, r *http.Request) {
+// GetEvents operation middleware
+func (siw *ServerInterfaceWrapper) GetEvents(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "repoId" -------------
-	var repoId string
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEventsParams
 
-	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// ------------- Optional query parameter "repoId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "repoId", r.URL.Query(), &params.RepoId)
 	if err != nil {
 		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
 		return
 	}
 
-	// ------------- Path parameter "mediaId" -------------
-	var mediaId string
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetEvents(w, r, params)
+	}))
 
-	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
-		return
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
 	}
 
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetFormats operation middleware
+func (siw *ServerInterfaceWrapper) GetFormats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetRepoMediaDownload(w, r, repoId, mediaId)
+		siw.Handler.GetFormats(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -232,32 +904,49 @@ func (siw *ServerInterfaceWrapper) GetRepoMediaDownload(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetRepoMediaStreams operation middleware
-func (siw *ServerInterfaceWrapper) GetRepoMediaStreams(w http.ResponseWriter, r *http.Request) {
+// CancelJob operation middleware
+func (siw *ServerInterfaceWrapper) CancelJob(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "repoId" -------------
-	var repoId string
+	// ------------- Path parameter "jobId" -------------
+	var jobId string
 
-	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "jobId", chi.URLParam(r, "jobId"), &jobId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "jobId", Err: err})
 		return
 	}
 
-	// ------------- Path parameter "mediaId" -------------
-	var mediaId string
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CancelJob(w, r, jobId)
+	}))
 
-	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetJobById operation middleware
+func (siw *ServerInterfaceWrapper) GetJobById(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "jobId" -------------
+	var jobId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "jobId", chi.URLParam(r, "jobId"), &jobId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "jobId", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetRepoMediaStreams(w, r, repoId, mediaId)
+		siw.Handler.GetJobById(w, r, jobId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -267,41 +956,55 @@ func (siw *ServerInterfaceWrapper) GetRepoMediaStreams(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetRepoMediaStream operation middleware
-func (siw *ServerInterfaceWrapper) GetRepoMediaStream(w http.ResponseWriter, r *http.Request) {
+// SearchMeta operation middleware
+func (siw *ServerInterfaceWrapper) SearchMeta(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "repoId" -------------
-	var repoId string
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchMetaParams
 
-	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// ------------- Required query parameter "q" -------------
+
+	if paramValue := r.URL.Query().Get("q"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "q"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "q", r.URL.Query(), &params.Q)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "q", Err: err})
 		return
 	}
 
-	// ------------- Path parameter "mediaId" -------------
-	var mediaId string
+	// ------------- Optional query parameter "type" -------------
 
-	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindQueryParameter("form", true, false, "type", r.URL.Query(), &params.Type)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "type", Err: err})
 		return
 	}
 
-	// ------------- Path parameter "format" -------------
-	var format string
+	// ------------- Required query parameter "repoId" -------------
 
-	err = runtime.BindStyledParameterWithOptions("simple", "format", chi.URLParam(r, "format"), &format, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if paramValue := r.URL.Query().Get("repoId"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "repoId"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "repoId", r.URL.Query(), &params.RepoId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetRepoMediaStream(w, r, repoId, mediaId, format)
+		siw.Handler.SearchMeta(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -311,427 +1014,7307 @@ func (siw *ServerInterfaceWrapper) GetRepoMediaStream(w http.ResponseWriter, r *
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
-}
+// GetRepos operation middleware
+func (siw *ServerInterfaceWrapper) GetRepos(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepos(w, r)
+	}))
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type UnmarshalingParamError struct {
-	ParamName string
-	Err       error
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-func (e *UnmarshalingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
-}
+// GetRepoById operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoById(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func (e *UnmarshalingParamError) Unwrap() error {
-	return e.Err
-}
+	var err error
 
-type RequiredParamError struct {
-	ParamName string
-}
+	// ------------- Path parameter "id" -------------
+	var id string
 
-func (e *RequiredParamError) Error() string {
-	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
-}
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-type RequiredHeaderError struct {
-	ParamName string
-	Err       error
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoById(w, r, id)
+	}))
 
-func (e *RequiredHeaderError) Error() string {
-	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-func (e *RequiredHeaderError) Unwrap() error {
-	return e.Err
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type InvalidParamFormatError struct {
-	ParamName string
-	Err       error
-}
+// GetRepoCapabilities operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoCapabilities(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func (e *InvalidParamFormatError) Error() string {
-	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
-}
+	var err error
 
-func (e *InvalidParamFormatError) Unwrap() error {
-	return e.Err
-}
+	// ------------- Path parameter "id" -------------
+	var id string
 
-type TooManyValuesForParamError struct {
-	ParamName string
-	Count     int
-}
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-func (e *TooManyValuesForParamError) Error() string {
-	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoCapabilities(w, r, id)
+	}))
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{})
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type ChiServerOptions struct {
-	BaseURL          string
-	BaseRouter       chi.Router
-	Middlewares      []MiddlewareFunc
-	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
-func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{
-		BaseRouter: r,
-	})
-}
+// GetRepoFingerprint operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoFingerprint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{
-		BaseURL:    baseURL,
-		BaseRouter: r,
-	})
-}
+	var err error
 
-// HandlerWithOptions creates http.Handler with additional options
-func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
-	r := options.BaseRouter
+	// ------------- Path parameter "id" -------------
+	var id string
 
-	if r == nil {
-		r = chi.NewRouter()
-	}
-	if options.ErrorHandlerFunc == nil {
-		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		}
-	}
-	wrapper := ServerInterfaceWrapper{
-		Handler:            si,
-		HandlerMiddlewares: options.Middlewares,
-		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
 	}
 
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/repos", wrapper.GetRepos)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/repos/{id}", wrapper.GetRepoById)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/repos/{id}/media", wrapper.GetRepoMedia)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}", wrapper.GetRepoMediaById)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/download", wrapper.GetRepoMediaDownload)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/stream", wrapper.GetRepoMediaStreams)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/stream/{format}", wrapper.GetRepoMediaStream)
-	})
-
-	return r
-}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRepoFingerprintParams
 
-type GetReposRequestObject struct {
-}
+	// ------------- Optional query parameter "items" -------------
 
-type GetReposResponseObject interface {
+	err = runtime.BindQueryParameter("form", true, false, "items", r.URL.Query(), &params.Items)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "items", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoFingerprint(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoDirectory operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoDirectory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRepoDirectoryParams
+
+	// ------------- Optional query parameter "path" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "path", r.URL.Query(), &params.Path)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "path", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoDirectory(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// IdentifyRepo operation middleware
+func (siw *ServerInterfaceWrapper) IdentifyRepo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.IdentifyRepo(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoIntegrity operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoIntegrity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoIntegrity(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// VerifyRepo operation middleware
+func (siw *ServerInterfaceWrapper) VerifyRepo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.VerifyRepo(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMedia operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRepoMediaParams
+
+	// ------------- Optional query parameter "type" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "type", r.URL.Query(), &params.Type)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "type", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "genre" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "genre", r.URL.Query(), &params.Genre)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "genre", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", r.URL.Query(), &params.Tag)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "year" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "year", r.URL.Query(), &params.Year)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "year", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", r.URL.Query(), &params.Order)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "order", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "watched" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "watched", r.URL.Query(), &params.Watched)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "watched", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "match" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "match", r.URL.Query(), &params.Match)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "match", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "audio_language" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "audio_language", r.URL.Query(), &params.AudioLanguage)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "audio_language", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "include_hidden" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_hidden", r.URL.Query(), &params.IncludeHidden)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_hidden", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMedia(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// AddRepoMedia operation middleware
+func (siw *ServerInterfaceWrapper) AddRepoMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AddRepoMedia(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// BulkRepoMedia operation middleware
+func (siw *ServerInterfaceWrapper) BulkRepoMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.BulkRepoMedia(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ProbeRepo operation middleware
+func (siw *ServerInterfaceWrapper) ProbeRepo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ProbeRepo(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoReplica operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoReplica(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoReplica(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ReplicateRepo operation middleware
+func (siw *ServerInterfaceWrapper) ReplicateRepo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReplicateRepo(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoScan operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoScan(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoScan(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ScanRepo operation middleware
+func (siw *ServerInterfaceWrapper) ScanRepo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ScanRepoParams
+
+	// ------------- Optional query parameter "full" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "full", r.URL.Query(), &params.Full)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "full", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ScanRepo(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoSeries operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoSeries(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoSeriesSeason operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoSeriesSeason(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "seriesId" -------------
+	var seriesId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "seriesId", chi.URLParam(r, "seriesId"), &seriesId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "seriesId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "season" -------------
+	var season int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "season", chi.URLParam(r, "season"), &season, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "season", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoSeriesSeason(w, r, id, seriesId, season)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PurgeRepoCache operation middleware
+func (siw *ServerInterfaceWrapper) PurgeRepoCache(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PurgeRepoCache(w, r, repoId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoCache operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoCache(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoCache(w, r, repoId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteRepoMedia operation middleware
+func (siw *ServerInterfaceWrapper) DeleteRepoMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteRepoMediaParams
+
+	// ------------- Optional query parameter "delete_file" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "delete_file", r.URL.Query(), &params.DeleteFile)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "delete_file", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteRepoMedia(w, r, repoId, mediaId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaById operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaById(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaById(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaArchive operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaArchive(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaArchive(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ArchiveRepoMedia operation middleware
+func (siw *ServerInterfaceWrapper) ArchiveRepoMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ArchiveRepoMedia(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaAudioTracks operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaAudioTracks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaAudioTracks(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaChapters operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaChapters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaChapters(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ConvertRepoMedia operation middleware
+func (siw *ServerInterfaceWrapper) ConvertRepoMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ConvertRepoMediaParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-Katana-Session" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Katana-Session")]; found {
+		var XKatanaSession string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Katana-Session", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Katana-Session", valueList[0], &XKatanaSession, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Katana-Session", Err: err})
+			return
+		}
+
+		params.XKatanaSession = &XKatanaSession
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ConvertRepoMedia(w, r, repoId, mediaId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaDashManifest operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaDashManifest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaDashManifest(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaDashRepresentation operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaDashRepresentation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "representationId" -------------
+	var representationId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "representationId", chi.URLParam(r, "representationId"), &representationId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "representationId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaDashRepresentation(w, r, repoId, mediaId, representationId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaDownload operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaDownload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaDownload(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaExtras operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaExtras(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaExtras(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaExtra operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaExtra(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "extraId" -------------
+	var extraId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "extraId", chi.URLParam(r, "extraId"), &extraId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "extraId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaExtra(w, r, repoId, mediaId, extraId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UnfollowRepoMediaSeries operation middleware
+func (siw *ServerInterfaceWrapper) UnfollowRepoMediaSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UnfollowRepoMediaSeries(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// FollowRepoMediaSeries operation middleware
+func (siw *ServerInterfaceWrapper) FollowRepoMediaSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.FollowRepoMediaSeries(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UnhideRepoMedia operation middleware
+func (siw *ServerInterfaceWrapper) UnhideRepoMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UnhideRepoMediaParams
+
+	// ------------- Optional query parameter "series" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "series", r.URL.Query(), &params.Series)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "series", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UnhideRepoMedia(w, r, repoId, mediaId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// HideRepoMedia operation middleware
+func (siw *ServerInterfaceWrapper) HideRepoMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params HideRepoMediaParams
+
+	// ------------- Optional query parameter "series" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "series", r.URL.Query(), &params.Series)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "series", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.HideRepoMedia(w, r, repoId, mediaId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaImages operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaImages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRepoMediaImagesParams
+
+	// ------------- Optional query parameter "type" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "type", r.URL.Query(), &params.Type)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "type", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", r.URL.Query(), &params.Page)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaImages(w, r, repoId, mediaId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaImage operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaImage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "imageId" -------------
+	var imageId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "imageId", chi.URLParam(r, "imageId"), &imageId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "imageId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRepoMediaImageParams
+
+	// ------------- Optional query parameter "size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "size", r.URL.Query(), &params.Size)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "size", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaImage(w, r, repoId, mediaId, imageId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SetRepoMediaPreferredImage operation middleware
+func (siw *ServerInterfaceWrapper) SetRepoMediaPreferredImage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "imageId" -------------
+	var imageId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "imageId", chi.URLParam(r, "imageId"), &imageId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "imageId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetRepoMediaPreferredImage(w, r, repoId, mediaId, imageId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UnlockRepoMedia operation middleware
+func (siw *ServerInterfaceWrapper) UnlockRepoMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UnlockRepoMedia(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// LockRepoMedia operation middleware
+func (siw *ServerInterfaceWrapper) LockRepoMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.LockRepoMedia(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UpdateRepoMediaMeta operation middleware
+func (siw *ServerInterfaceWrapper) UpdateRepoMediaMeta(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateRepoMediaMeta(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RefreshRepoMediaMeta operation middleware
+func (siw *ServerInterfaceWrapper) RefreshRepoMediaMeta(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RefreshRepoMediaMeta(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaMetaSuggestions operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaMetaSuggestions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaMetaSuggestions(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaPreview operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaPreview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaPreview(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaProgress operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaProgress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaProgress(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UpdateRepoMediaProgress operation middleware
+func (siw *ServerInterfaceWrapper) UpdateRepoMediaProgress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateRepoMediaProgress(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RestoreRepoMedia operation middleware
+func (siw *ServerInterfaceWrapper) RestoreRepoMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RestoreRepoMedia(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaStats operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaStats(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaStoryboard operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaStoryboard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaStoryboard(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaStoryboardSheet operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaStoryboardSheet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "sheet" -------------
+	var sheet int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "sheet", chi.URLParam(r, "sheet"), &sheet, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sheet", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaStoryboardSheet(w, r, repoId, mediaId, sheet)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaStreams operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaStreams(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRepoMediaStreamsParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Accept" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Accept")]; found {
+		var Accept string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Accept", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Accept", valueList[0], &Accept, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Accept", Err: err})
+			return
+		}
+
+		params.Accept = &Accept
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaStreams(w, r, repoId, mediaId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaStream operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaStream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "format" -------------
+	var format string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "format", chi.URLParam(r, "format"), &format, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRepoMediaStreamParams
+
+	// ------------- Optional query parameter "mode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "mode", r.URL.Query(), &params.Mode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mode", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "start" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "start", r.URL.Query(), &params.Start)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "start", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaStream(w, r, repoId, mediaId, format, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaSubtitles operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaSubtitles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaSubtitles(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoMediaSubtitle operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoMediaSubtitle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "trackId" -------------
+	var trackId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "trackId", chi.URLParam(r, "trackId"), &trackId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "trackId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoMediaSubtitle(w, r, repoId, mediaId, trackId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UpdateRepoMediaTags operation middleware
+func (siw *ServerInterfaceWrapper) UpdateRepoMediaTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateRepoMediaTags(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRepoTrash operation middleware
+func (siw *ServerInterfaceWrapper) GetRepoTrash(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRepoTrash(w, r, repoId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RestoreRepoTrash operation middleware
+func (siw *ServerInterfaceWrapper) RestoreRepoTrash(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "repoId" -------------
+	var repoId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "repoId", chi.URLParam(r, "repoId"), &repoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "repoId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "mediaId" -------------
+	var mediaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "mediaId", chi.URLParam(r, "mediaId"), &mediaId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mediaId", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RestoreRepoTrash(w, r, repoId, mediaId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteSessionQuality operation middleware
+func (siw *ServerInterfaceWrapper) DeleteSessionQuality(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteSessionQualityParams
+
+	headers := r.Header
+
+	// ------------- Required header parameter "X-Katana-Session" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Katana-Session")]; found {
+		var XKatanaSession string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Katana-Session", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Katana-Session", valueList[0], &XKatanaSession, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Katana-Session", Err: err})
+			return
+		}
+
+		params.XKatanaSession = XKatanaSession
+
+	} else {
+		err := fmt.Errorf("Header parameter X-Katana-Session is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "X-Katana-Session", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteSessionQuality(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetSessionQuality operation middleware
+func (siw *ServerInterfaceWrapper) GetSessionQuality(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSessionQualityParams
+
+	headers := r.Header
+
+	// ------------- Required header parameter "X-Katana-Session" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Katana-Session")]; found {
+		var XKatanaSession string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Katana-Session", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Katana-Session", valueList[0], &XKatanaSession, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Katana-Session", Err: err})
+			return
+		}
+
+		params.XKatanaSession = XKatanaSession
+
+	} else {
+		err := fmt.Errorf("Header parameter X-Katana-Session is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "X-Katana-Session", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSessionQuality(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UpdateSessionQuality operation middleware
+func (siw *ServerInterfaceWrapper) UpdateSessionQuality(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateSessionQualityParams
+
+	headers := r.Header
+
+	// ------------- Required header parameter "X-Katana-Session" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Katana-Session")]; found {
+		var XKatanaSession string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Katana-Session", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Katana-Session", valueList[0], &XKatanaSession, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Katana-Session", Err: err})
+			return
+		}
+
+		params.XKatanaSession = XKatanaSession
+
+	} else {
+		err := fmt.Errorf("Header parameter X-Katana-Session is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "X-Katana-Session", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateSessionQuality(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetSystem operation middleware
+func (siw *ServerInterfaceWrapper) GetSystem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSystem(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetUsage operation middleware
+func (siw *ServerInterfaceWrapper) GetUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsageParams
+
+	// ------------- Optional query parameter "month" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "month", r.URL.Query(), &params.Month)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "month", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUsage(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ExportUserData operation middleware
+func (siw *ServerInterfaceWrapper) ExportUserData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportUserDataParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportUserData(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/calendar", wrapper.GetCalendar)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/collections", wrapper.GetCollections)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/collections", wrapper.CreateCollection)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/collections/{collectionId}", wrapper.DeleteCollection)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/collections/{collectionId}", wrapper.GetCollectionById)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/collections/{collectionId}", wrapper.UpdateCollection)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/events", wrapper.GetEvents)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/formats", wrapper.GetFormats)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/jobs/{jobId}", wrapper.CancelJob)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/jobs/{jobId}", wrapper.GetJobById)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/meta/search", wrapper.SearchMeta)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos", wrapper.GetRepos)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{id}", wrapper.GetRepoById)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{id}/capabilities", wrapper.GetRepoCapabilities)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{id}/fingerprint", wrapper.GetRepoFingerprint)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{id}/fs", wrapper.GetRepoDirectory)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{id}/identify", wrapper.IdentifyRepo)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{id}/integrity", wrapper.GetRepoIntegrity)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{id}/integrity", wrapper.VerifyRepo)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{id}/media", wrapper.GetRepoMedia)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{id}/media", wrapper.AddRepoMedia)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{id}/media/bulk", wrapper.BulkRepoMedia)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{id}/probe", wrapper.ProbeRepo)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{id}/replica", wrapper.GetRepoReplica)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{id}/replicate", wrapper.ReplicateRepo)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{id}/scan", wrapper.GetRepoScan)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{id}/scan", wrapper.ScanRepo)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{id}/series", wrapper.GetRepoSeries)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{id}/series/{seriesId}/seasons/{season}", wrapper.GetRepoSeriesSeason)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/repos/{repoId}/cache", wrapper.PurgeRepoCache)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/cache", wrapper.GetRepoCache)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/repos/{repoId}/media/{mediaId}", wrapper.DeleteRepoMedia)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}", wrapper.GetRepoMediaById)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/archive", wrapper.GetRepoMediaArchive)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{repoId}/media/{mediaId}/archive", wrapper.ArchiveRepoMedia)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/audio", wrapper.GetRepoMediaAudioTracks)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/chapters", wrapper.GetRepoMediaChapters)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{repoId}/media/{mediaId}/convert", wrapper.ConvertRepoMedia)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/dash/manifest.mpd", wrapper.GetRepoMediaDashManifest)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/dash/{representationId}", wrapper.GetRepoMediaDashRepresentation)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/download", wrapper.GetRepoMediaDownload)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/extras", wrapper.GetRepoMediaExtras)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/extras/{extraId}", wrapper.GetRepoMediaExtra)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/repos/{repoId}/media/{mediaId}/follow", wrapper.UnfollowRepoMediaSeries)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{repoId}/media/{mediaId}/follow", wrapper.FollowRepoMediaSeries)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/repos/{repoId}/media/{mediaId}/hide", wrapper.UnhideRepoMedia)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{repoId}/media/{mediaId}/hide", wrapper.HideRepoMedia)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/images", wrapper.GetRepoMediaImages)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/images/{imageId}", wrapper.GetRepoMediaImage)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/repos/{repoId}/media/{mediaId}/images/{imageId}/preferred", wrapper.SetRepoMediaPreferredImage)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/repos/{repoId}/media/{mediaId}/lock", wrapper.UnlockRepoMedia)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{repoId}/media/{mediaId}/lock", wrapper.LockRepoMedia)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/repos/{repoId}/media/{mediaId}/meta", wrapper.UpdateRepoMediaMeta)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{repoId}/media/{mediaId}/meta/refresh", wrapper.RefreshRepoMediaMeta)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/meta/suggestions", wrapper.GetRepoMediaMetaSuggestions)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/preview", wrapper.GetRepoMediaPreview)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/progress", wrapper.GetRepoMediaProgress)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/repos/{repoId}/media/{mediaId}/progress", wrapper.UpdateRepoMediaProgress)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{repoId}/media/{mediaId}/restore", wrapper.RestoreRepoMedia)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/stats", wrapper.GetRepoMediaStats)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/storyboard", wrapper.GetRepoMediaStoryboard)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/storyboard/{sheet}", wrapper.GetRepoMediaStoryboardSheet)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/stream", wrapper.GetRepoMediaStreams)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/stream/{format}", wrapper.GetRepoMediaStream)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/subtitles", wrapper.GetRepoMediaSubtitles)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/media/{mediaId}/subtitles/{trackId}.vtt", wrapper.GetRepoMediaSubtitle)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/repos/{repoId}/media/{mediaId}/tags", wrapper.UpdateRepoMediaTags)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/repos/{repoId}/trash", wrapper.GetRepoTrash)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/repos/{repoId}/trash/{mediaId}/restore", wrapper.RestoreRepoTrash)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/session/quality", wrapper.DeleteSessionQuality)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/session/quality", wrapper.GetSessionQuality)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/session/quality", wrapper.UpdateSessionQuality)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/system", wrapper.GetSystem)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/usage", wrapper.GetUsage)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/users/me/export", wrapper.ExportUserData)
+	})
+
+	return r
+}
+
+type GetCalendarRequestObject struct {
+	Params GetCalendarParams
+}
+
+type GetCalendarResponseObject interface {
+	VisitGetCalendarResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetCalendar200JSONResponse []CalendarEpisode
+
+func (response GetCalendar200JSONResponse) VisitGetCalendarResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetCalendar400JSONResponse Error
+
+func (response GetCalendar400JSONResponse) VisitGetCalendarResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetCollectionsRequestObject struct {
+}
+
+type GetCollectionsResponseObject interface {
+	VisitGetCollectionsResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetCollections200JSONResponse []Collection
+
+func (response GetCollections200JSONResponse) VisitGetCollectionsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateCollectionRequestObject struct {
+	Body *CreateCollectionJSONRequestBody
+}
+
+type CreateCollectionResponseObject interface {
+	VisitCreateCollectionResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type CreateCollection201JSONResponse Collection
+
+func (response CreateCollection201JSONResponse) VisitCreateCollectionResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateCollection400JSONResponse Error
+
+func (response CreateCollection400JSONResponse) VisitCreateCollectionResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteCollectionRequestObject struct {
+	CollectionId string `json:"collectionId"`
+}
+
+type DeleteCollectionResponseObject interface {
+	VisitDeleteCollectionResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type DeleteCollection204Response struct {
+}
+
+func (response DeleteCollection204Response) VisitDeleteCollectionResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteCollection400JSONResponse Error
+
+func (response DeleteCollection400JSONResponse) VisitDeleteCollectionResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetCollectionByIdRequestObject struct {
+	CollectionId string `json:"collectionId"`
+}
+
+type GetCollectionByIdResponseObject interface {
+	VisitGetCollectionByIdResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetCollectionById200JSONResponse Collection
+
+func (response GetCollectionById200JSONResponse) VisitGetCollectionByIdResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetCollectionById400JSONResponse Error
+
+func (response GetCollectionById400JSONResponse) VisitGetCollectionByIdResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateCollectionRequestObject struct {
+	CollectionId string `json:"collectionId"`
+	Body         *UpdateCollectionJSONRequestBody
+}
+
+type UpdateCollectionResponseObject interface {
+	VisitUpdateCollectionResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type UpdateCollection200JSONResponse Collection
+
+func (response UpdateCollection200JSONResponse) VisitUpdateCollectionResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateCollection400JSONResponse Error
+
+func (response UpdateCollection400JSONResponse) VisitUpdateCollectionResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetEventsRequestObject struct {
+	Params GetEventsParams
+}
+
+type GetEventsResponseObject interface {
+	VisitGetEventsResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetEvents200TexteventStreamResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response GetEvents200TexteventStreamResponse) VisitGetEventsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "text/event-stream")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetEvents400JSONResponse Error
+
+func (response GetEvents400JSONResponse) VisitGetEventsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetFormatsRequestObject struct {
+}
+
+type GetFormatsResponseObject interface {
+	VisitGetFormatsResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetFormats200JSONResponse []FormatSupport
+
+func (response GetFormats200JSONResponse) VisitGetFormatsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CancelJobRequestObject struct {
+	JobId string `json:"jobId"`
+}
+
+type CancelJobResponseObject interface {
+	VisitCancelJobResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type CancelJob204Response struct {
+}
+
+func (response CancelJob204Response) VisitCancelJobResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type CancelJob400JSONResponse Error
+
+func (response CancelJob400JSONResponse) VisitCancelJobResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetJobByIdRequestObject struct {
+	JobId string `json:"jobId"`
+}
+
+type GetJobByIdResponseObject interface {
+	VisitGetJobByIdResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetJobById200JSONResponse Job
+
+func (response GetJobById200JSONResponse) VisitGetJobByIdResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetJobById400JSONResponse Error
+
+func (response GetJobById400JSONResponse) VisitGetJobByIdResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchMetaRequestObject struct {
+	Params SearchMetaParams
+}
+
+type SearchMetaResponseObject interface {
+	VisitSearchMetaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type SearchMeta200JSONResponse []MetadataCandidate
+
+func (response SearchMeta200JSONResponse) VisitSearchMetaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchMeta400JSONResponse Error
+
+func (response SearchMeta400JSONResponse) VisitSearchMetaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetReposRequestObject struct {
+}
+
+type GetReposResponseObject interface {
 	VisitGetReposResponse(w http.ResponseWriter, r *http.Request) error
 }
 
-type GetRepos200JSONResponse []Repository
+type GetRepos200JSONResponse []Repository
+
+func (response GetRepos200JSONResponse) VisitGetReposResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepos304Response struct {
+}
+
+func (response GetRepos304Response) VisitGetReposResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.WriteHeader(304)
+	return nil
+}
+
+type GetRepoByIdRequestObject struct {
+	Id string `json:"id"`
+}
+
+type GetRepoByIdResponseObject interface {
+	VisitGetRepoByIdResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoById200JSONResponse Repository
+
+func (response GetRepoById200JSONResponse) VisitGetRepoByIdResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoById400JSONResponse Error
+
+func (response GetRepoById400JSONResponse) VisitGetRepoByIdResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoCapabilitiesRequestObject struct {
+	Id string `json:"id"`
+}
+
+type GetRepoCapabilitiesResponseObject interface {
+	VisitGetRepoCapabilitiesResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoCapabilities200JSONResponse []CapabilityStatus
+
+func (response GetRepoCapabilities200JSONResponse) VisitGetRepoCapabilitiesResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoCapabilities400JSONResponse Error
+
+func (response GetRepoCapabilities400JSONResponse) VisitGetRepoCapabilitiesResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoFingerprintRequestObject struct {
+	Id     string `json:"id"`
+	Params GetRepoFingerprintParams
+}
+
+type GetRepoFingerprintResponseObject interface {
+	VisitGetRepoFingerprintResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoFingerprint200JSONResponse RepoFingerprint
+
+func (response GetRepoFingerprint200JSONResponse) VisitGetRepoFingerprintResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoFingerprint400JSONResponse Error
+
+func (response GetRepoFingerprint400JSONResponse) VisitGetRepoFingerprintResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoDirectoryRequestObject struct {
+	Id     string `json:"id"`
+	Params GetRepoDirectoryParams
+}
+
+type GetRepoDirectoryResponseObject interface {
+	VisitGetRepoDirectoryResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoDirectory200JSONResponse DirectoryListing
+
+func (response GetRepoDirectory200JSONResponse) VisitGetRepoDirectoryResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoDirectory400JSONResponse Error
+
+func (response GetRepoDirectory400JSONResponse) VisitGetRepoDirectoryResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type IdentifyRepoRequestObject struct {
+	Id string `json:"id"`
+}
+
+type IdentifyRepoResponseObject interface {
+	VisitIdentifyRepoResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type IdentifyRepo202JSONResponse Job
+
+func (response IdentifyRepo202JSONResponse) VisitIdentifyRepoResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type IdentifyRepo400JSONResponse Error
+
+func (response IdentifyRepo400JSONResponse) VisitIdentifyRepoResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoIntegrityRequestObject struct {
+	Id string `json:"id"`
+}
+
+type GetRepoIntegrityResponseObject interface {
+	VisitGetRepoIntegrityResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoIntegrity200JSONResponse IntegrityReport
+
+func (response GetRepoIntegrity200JSONResponse) VisitGetRepoIntegrityResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoIntegrity400JSONResponse Error
+
+func (response GetRepoIntegrity400JSONResponse) VisitGetRepoIntegrityResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type VerifyRepoRequestObject struct {
+	Id string `json:"id"`
+}
+
+type VerifyRepoResponseObject interface {
+	VisitVerifyRepoResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type VerifyRepo202JSONResponse Job
+
+func (response VerifyRepo202JSONResponse) VisitVerifyRepoResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type VerifyRepo400JSONResponse Error
+
+func (response VerifyRepo400JSONResponse) VisitVerifyRepoResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaRequestObject struct {
+	Id     string `json:"id"`
+	Params GetRepoMediaParams
+}
+
+type GetRepoMediaResponseObject interface {
+	VisitGetRepoMediaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMedia200ResponseHeaders struct {
+	XTotalCount int
+}
+
+type GetRepoMedia200JSONResponse struct {
+	Body    []Media
+	Headers GetRepoMedia200ResponseHeaders
+}
+
+func (response GetRepoMedia200JSONResponse) VisitGetRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", fmt.Sprint(response.Headers.XTotalCount))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetRepoMedia304Response struct {
+}
+
+func (response GetRepoMedia304Response) VisitGetRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.WriteHeader(304)
+	return nil
+}
+
+type GetRepoMedia400JSONResponse Error
+
+func (response GetRepoMedia400JSONResponse) VisitGetRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AddRepoMediaRequestObject struct {
+	Id            string `json:"id"`
+	JSONBody      *AddRepoMediaJSONRequestBody
+	MultipartBody *multipart.Reader
+}
+
+type AddRepoMediaResponseObject interface {
+	VisitAddRepoMediaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type AddRepoMedia201JSONResponse Media
+
+func (response AddRepoMedia201JSONResponse) VisitAddRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AddRepoMedia400JSONResponse Error
+
+func (response AddRepoMedia400JSONResponse) VisitAddRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type BulkRepoMediaRequestObject struct {
+	Id   string `json:"id"`
+	Body *BulkRepoMediaJSONRequestBody
+}
+
+type BulkRepoMediaResponseObject interface {
+	VisitBulkRepoMediaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type BulkRepoMedia202JSONResponse Job
+
+func (response BulkRepoMedia202JSONResponse) VisitBulkRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type BulkRepoMedia400JSONResponse Error
+
+func (response BulkRepoMedia400JSONResponse) VisitBulkRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ProbeRepoRequestObject struct {
+	Id string `json:"id"`
+}
+
+type ProbeRepoResponseObject interface {
+	VisitProbeRepoResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type ProbeRepo202JSONResponse Job
+
+func (response ProbeRepo202JSONResponse) VisitProbeRepoResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ProbeRepo400JSONResponse Error
+
+func (response ProbeRepo400JSONResponse) VisitProbeRepoResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoReplicaRequestObject struct {
+	Id string `json:"id"`
+}
+
+type GetRepoReplicaResponseObject interface {
+	VisitGetRepoReplicaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoReplica200JSONResponse ReplicaManifest
+
+func (response GetRepoReplica200JSONResponse) VisitGetRepoReplicaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoReplica400JSONResponse Error
+
+func (response GetRepoReplica400JSONResponse) VisitGetRepoReplicaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ReplicateRepoRequestObject struct {
+	Id string `json:"id"`
+}
+
+type ReplicateRepoResponseObject interface {
+	VisitReplicateRepoResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type ReplicateRepo202JSONResponse Job
+
+func (response ReplicateRepo202JSONResponse) VisitReplicateRepoResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ReplicateRepo400JSONResponse Error
+
+func (response ReplicateRepo400JSONResponse) VisitReplicateRepoResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoScanRequestObject struct {
+	Id string `json:"id"`
+}
+
+type GetRepoScanResponseObject interface {
+	VisitGetRepoScanResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoScan200JSONResponse Job
+
+func (response GetRepoScan200JSONResponse) VisitGetRepoScanResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoScan400JSONResponse Error
+
+func (response GetRepoScan400JSONResponse) VisitGetRepoScanResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ScanRepoRequestObject struct {
+	Id     string `json:"id"`
+	Params ScanRepoParams
+}
+
+type ScanRepoResponseObject interface {
+	VisitScanRepoResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type ScanRepo202JSONResponse Job
+
+func (response ScanRepo202JSONResponse) VisitScanRepoResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ScanRepo400JSONResponse Error
+
+func (response ScanRepo400JSONResponse) VisitScanRepoResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoSeriesRequestObject struct {
+	Id string `json:"id"`
+}
+
+type GetRepoSeriesResponseObject interface {
+	VisitGetRepoSeriesResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoSeries200JSONResponse []Series
+
+func (response GetRepoSeries200JSONResponse) VisitGetRepoSeriesResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoSeries304Response struct {
+}
+
+func (response GetRepoSeries304Response) VisitGetRepoSeriesResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.WriteHeader(304)
+	return nil
+}
+
+type GetRepoSeries400JSONResponse Error
+
+func (response GetRepoSeries400JSONResponse) VisitGetRepoSeriesResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoSeriesSeasonRequestObject struct {
+	Id       string `json:"id"`
+	SeriesId string `json:"seriesId"`
+	Season   int    `json:"season"`
+}
+
+type GetRepoSeriesSeasonResponseObject interface {
+	VisitGetRepoSeriesSeasonResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoSeriesSeason200JSONResponse Season
+
+func (response GetRepoSeriesSeason200JSONResponse) VisitGetRepoSeriesSeasonResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoSeriesSeason304Response struct {
+}
+
+func (response GetRepoSeriesSeason304Response) VisitGetRepoSeriesSeasonResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.WriteHeader(304)
+	return nil
+}
+
+type GetRepoSeriesSeason400JSONResponse Error
+
+func (response GetRepoSeriesSeason400JSONResponse) VisitGetRepoSeriesSeasonResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PurgeRepoCacheRequestObject struct {
+	RepoId string `json:"repoId"`
+}
+
+type PurgeRepoCacheResponseObject interface {
+	VisitPurgeRepoCacheResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type PurgeRepoCache200JSONResponse CacheUsage
+
+func (response PurgeRepoCache200JSONResponse) VisitPurgeRepoCacheResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PurgeRepoCache400JSONResponse Error
+
+func (response PurgeRepoCache400JSONResponse) VisitPurgeRepoCacheResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoCacheRequestObject struct {
+	RepoId string `json:"repoId"`
+}
+
+type GetRepoCacheResponseObject interface {
+	VisitGetRepoCacheResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoCache200JSONResponse CacheUsage
+
+func (response GetRepoCache200JSONResponse) VisitGetRepoCacheResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoCache400JSONResponse Error
+
+func (response GetRepoCache400JSONResponse) VisitGetRepoCacheResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteRepoMediaRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	Params  DeleteRepoMediaParams
+}
+
+type DeleteRepoMediaResponseObject interface {
+	VisitDeleteRepoMediaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type DeleteRepoMedia204Response struct {
+}
+
+func (response DeleteRepoMedia204Response) VisitDeleteRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteRepoMedia400JSONResponse Error
+
+func (response DeleteRepoMedia400JSONResponse) VisitDeleteRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaByIdRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type GetRepoMediaByIdResponseObject interface {
+	VisitGetRepoMediaByIdResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaById200JSONResponse Media
+
+func (response GetRepoMediaById200JSONResponse) VisitGetRepoMediaByIdResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaById304Response struct {
+}
+
+func (response GetRepoMediaById304Response) VisitGetRepoMediaByIdResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.WriteHeader(304)
+	return nil
+}
+
+type GetRepoMediaById400JSONResponse Error
+
+func (response GetRepoMediaById400JSONResponse) VisitGetRepoMediaByIdResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaArchiveRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type GetRepoMediaArchiveResponseObject interface {
+	VisitGetRepoMediaArchiveResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaArchive200JSONResponse ArchiveState
+
+func (response GetRepoMediaArchive200JSONResponse) VisitGetRepoMediaArchiveResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaArchive400JSONResponse Error
+
+func (response GetRepoMediaArchive400JSONResponse) VisitGetRepoMediaArchiveResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ArchiveRepoMediaRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type ArchiveRepoMediaResponseObject interface {
+	VisitArchiveRepoMediaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type ArchiveRepoMedia202JSONResponse Job
+
+func (response ArchiveRepoMedia202JSONResponse) VisitArchiveRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ArchiveRepoMedia400JSONResponse Error
+
+func (response ArchiveRepoMedia400JSONResponse) VisitArchiveRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaAudioTracksRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type GetRepoMediaAudioTracksResponseObject interface {
+	VisitGetRepoMediaAudioTracksResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaAudioTracks200JSONResponse []AudioTrack
+
+func (response GetRepoMediaAudioTracks200JSONResponse) VisitGetRepoMediaAudioTracksResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaAudioTracks400JSONResponse Error
+
+func (response GetRepoMediaAudioTracks400JSONResponse) VisitGetRepoMediaAudioTracksResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaChaptersRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type GetRepoMediaChaptersResponseObject interface {
+	VisitGetRepoMediaChaptersResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaChapters200JSONResponse []Chapter
+
+func (response GetRepoMediaChapters200JSONResponse) VisitGetRepoMediaChaptersResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaChapters400JSONResponse Error
+
+func (response GetRepoMediaChapters400JSONResponse) VisitGetRepoMediaChaptersResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaChapters501JSONResponse Error
+
+func (response GetRepoMediaChapters501JSONResponse) VisitGetRepoMediaChaptersResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(501)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ConvertRepoMediaRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	Params  ConvertRepoMediaParams
+	Body    *ConvertRepoMediaJSONRequestBody
+}
+
+type ConvertRepoMediaResponseObject interface {
+	VisitConvertRepoMediaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type ConvertRepoMedia202JSONResponse Job
+
+func (response ConvertRepoMedia202JSONResponse) VisitConvertRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ConvertRepoMedia400JSONResponse Error
+
+func (response ConvertRepoMedia400JSONResponse) VisitConvertRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaDashManifestRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type GetRepoMediaDashManifestResponseObject interface {
+	VisitGetRepoMediaDashManifestResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaDashManifest200ApplicationdashXmlResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response GetRepoMediaDashManifest200ApplicationdashXmlResponse) VisitGetRepoMediaDashManifestResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/dash+xml")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetRepoMediaDashManifest202JSONResponse Job
+
+func (response GetRepoMediaDashManifest202JSONResponse) VisitGetRepoMediaDashManifestResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaDashManifest400JSONResponse Error
+
+func (response GetRepoMediaDashManifest400JSONResponse) VisitGetRepoMediaDashManifestResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaDashRepresentationRequestObject struct {
+	RepoId           string `json:"repoId"`
+	MediaId          string `json:"mediaId"`
+	RepresentationId string `json:"representationId"`
+}
+
+type GetRepoMediaDashRepresentationResponseObject interface {
+	VisitGetRepoMediaDashRepresentationResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaDashRepresentation200ResponseHeaders struct {
+	ContentType string
+}
+
+type GetRepoMediaDashRepresentation200SchemaResponse struct {
+	Body          io.Reader
+	Headers       GetRepoMediaDashRepresentation200ResponseHeaders
+	ContentLength int64
+}
+
+func (response GetRepoMediaDashRepresentation200SchemaResponse) VisitGetRepoMediaDashRepresentationResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "schema")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Type", fmt.Sprint(response.Headers.ContentType))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetRepoMediaDashRepresentation400JSONResponse Error
+
+func (response GetRepoMediaDashRepresentation400JSONResponse) VisitGetRepoMediaDashRepresentationResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaDashRepresentation429JSONResponse Error
+
+func (response GetRepoMediaDashRepresentation429JSONResponse) VisitGetRepoMediaDashRepresentationResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaDashRepresentation503ResponseHeaders struct {
+	RetryAfter int
+}
+
+type GetRepoMediaDashRepresentation503JSONResponse struct {
+	Body    Error
+	Headers GetRepoMediaDashRepresentation503ResponseHeaders
+}
+
+func (response GetRepoMediaDashRepresentation503JSONResponse) VisitGetRepoMediaDashRepresentationResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetRepoMediaDownloadRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type GetRepoMediaDownloadResponseObject interface {
+	VisitGetRepoMediaDownloadResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaDownload200ResponseHeaders struct {
+	ContentDisposition string
+	ContentType        string
+}
+
+type GetRepoMediaDownload200SchemaResponse struct {
+	Body          io.Reader
+	Headers       GetRepoMediaDownload200ResponseHeaders
+	ContentLength int64
+}
+
+func (response GetRepoMediaDownload200SchemaResponse) VisitGetRepoMediaDownloadResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "schema")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.Header().Set("Content-Type", fmt.Sprint(response.Headers.ContentType))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetRepoMediaDownload400JSONResponse Error
+
+func (response GetRepoMediaDownload400JSONResponse) VisitGetRepoMediaDownloadResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaDownload410JSONResponse Error
+
+func (response GetRepoMediaDownload410JSONResponse) VisitGetRepoMediaDownloadResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(410)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaDownload429JSONResponse Error
+
+func (response GetRepoMediaDownload429JSONResponse) VisitGetRepoMediaDownloadResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaExtrasRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type GetRepoMediaExtrasResponseObject interface {
+	VisitGetRepoMediaExtrasResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaExtras200JSONResponse []MediaExtra
+
+func (response GetRepoMediaExtras200JSONResponse) VisitGetRepoMediaExtrasResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaExtras400JSONResponse Error
+
+func (response GetRepoMediaExtras400JSONResponse) VisitGetRepoMediaExtrasResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaExtraRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	ExtraId string `json:"extraId"`
+}
+
+type GetRepoMediaExtraResponseObject interface {
+	VisitGetRepoMediaExtraResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaExtra200ResponseHeaders struct {
+	ContentType string
+}
+
+type GetRepoMediaExtra200SchemaResponse struct {
+	Body          io.Reader
+	Headers       GetRepoMediaExtra200ResponseHeaders
+	ContentLength int64
+}
+
+func (response GetRepoMediaExtra200SchemaResponse) VisitGetRepoMediaExtraResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "schema")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Type", fmt.Sprint(response.Headers.ContentType))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetRepoMediaExtra400JSONResponse Error
+
+func (response GetRepoMediaExtra400JSONResponse) VisitGetRepoMediaExtraResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaExtra410JSONResponse Error
+
+func (response GetRepoMediaExtra410JSONResponse) VisitGetRepoMediaExtraResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(410)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UnfollowRepoMediaSeriesRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type UnfollowRepoMediaSeriesResponseObject interface {
+	VisitUnfollowRepoMediaSeriesResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type UnfollowRepoMediaSeries204Response struct {
+}
+
+func (response UnfollowRepoMediaSeries204Response) VisitUnfollowRepoMediaSeriesResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type UnfollowRepoMediaSeries400JSONResponse Error
+
+func (response UnfollowRepoMediaSeries400JSONResponse) VisitUnfollowRepoMediaSeriesResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type FollowRepoMediaSeriesRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type FollowRepoMediaSeriesResponseObject interface {
+	VisitFollowRepoMediaSeriesResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type FollowRepoMediaSeries204Response struct {
+}
+
+func (response FollowRepoMediaSeries204Response) VisitFollowRepoMediaSeriesResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type FollowRepoMediaSeries400JSONResponse Error
+
+func (response FollowRepoMediaSeries400JSONResponse) VisitFollowRepoMediaSeriesResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UnhideRepoMediaRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	Params  UnhideRepoMediaParams
+}
+
+type UnhideRepoMediaResponseObject interface {
+	VisitUnhideRepoMediaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type UnhideRepoMedia204Response struct {
+}
+
+func (response UnhideRepoMedia204Response) VisitUnhideRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type UnhideRepoMedia400JSONResponse Error
+
+func (response UnhideRepoMedia400JSONResponse) VisitUnhideRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type HideRepoMediaRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	Params  HideRepoMediaParams
+}
+
+type HideRepoMediaResponseObject interface {
+	VisitHideRepoMediaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type HideRepoMedia204Response struct {
+}
+
+func (response HideRepoMedia204Response) VisitHideRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type HideRepoMedia400JSONResponse Error
+
+func (response HideRepoMedia400JSONResponse) VisitHideRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaImagesRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	Params  GetRepoMediaImagesParams
+}
+
+type GetRepoMediaImagesResponseObject interface {
+	VisitGetRepoMediaImagesResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaImages200ResponseHeaders struct {
+	XTotalCount int
+}
+
+type GetRepoMediaImages200JSONResponse struct {
+	Body    []Image
+	Headers GetRepoMediaImages200ResponseHeaders
+}
+
+func (response GetRepoMediaImages200JSONResponse) VisitGetRepoMediaImagesResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", fmt.Sprint(response.Headers.XTotalCount))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetRepoMediaImages400JSONResponse Error
+
+func (response GetRepoMediaImages400JSONResponse) VisitGetRepoMediaImagesResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaImageRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	ImageId string `json:"imageId"`
+	Params  GetRepoMediaImageParams
+}
+
+type GetRepoMediaImageResponseObject interface {
+	VisitGetRepoMediaImageResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaImage200ResponseHeaders struct {
+	ContentType string
+}
+
+type GetRepoMediaImage200ImageResponse struct {
+	Body          io.Reader
+	Headers       GetRepoMediaImage200ResponseHeaders
+	ContentLength int64
+}
+
+func (response GetRepoMediaImage200ImageResponse) VisitGetRepoMediaImageResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "image/*")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Type", fmt.Sprint(response.Headers.ContentType))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetRepoMediaImage400JSONResponse Error
+
+func (response GetRepoMediaImage400JSONResponse) VisitGetRepoMediaImageResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SetRepoMediaPreferredImageRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	ImageId string `json:"imageId"`
+}
+
+type SetRepoMediaPreferredImageResponseObject interface {
+	VisitSetRepoMediaPreferredImageResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type SetRepoMediaPreferredImage200JSONResponse Media
+
+func (response SetRepoMediaPreferredImage200JSONResponse) VisitSetRepoMediaPreferredImageResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SetRepoMediaPreferredImage400JSONResponse Error
+
+func (response SetRepoMediaPreferredImage400JSONResponse) VisitSetRepoMediaPreferredImageResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UnlockRepoMediaRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type UnlockRepoMediaResponseObject interface {
+	VisitUnlockRepoMediaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type UnlockRepoMedia200JSONResponse Media
+
+func (response UnlockRepoMedia200JSONResponse) VisitUnlockRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UnlockRepoMedia400JSONResponse Error
+
+func (response UnlockRepoMedia400JSONResponse) VisitUnlockRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type LockRepoMediaRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type LockRepoMediaResponseObject interface {
+	VisitLockRepoMediaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type LockRepoMedia200JSONResponse Media
+
+func (response LockRepoMedia200JSONResponse) VisitLockRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type LockRepoMedia400JSONResponse Error
+
+func (response LockRepoMedia400JSONResponse) VisitLockRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateRepoMediaMetaRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	Body    *UpdateRepoMediaMetaJSONRequestBody
+}
+
+type UpdateRepoMediaMetaResponseObject interface {
+	VisitUpdateRepoMediaMetaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type UpdateRepoMediaMeta200JSONResponse Media
+
+func (response UpdateRepoMediaMeta200JSONResponse) VisitUpdateRepoMediaMetaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateRepoMediaMeta400JSONResponse Error
+
+func (response UpdateRepoMediaMeta400JSONResponse) VisitUpdateRepoMediaMetaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RefreshRepoMediaMetaRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type RefreshRepoMediaMetaResponseObject interface {
+	VisitRefreshRepoMediaMetaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type RefreshRepoMediaMeta200JSONResponse Media
+
+func (response RefreshRepoMediaMeta200JSONResponse) VisitRefreshRepoMediaMetaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RefreshRepoMediaMeta400JSONResponse Error
+
+func (response RefreshRepoMediaMeta400JSONResponse) VisitRefreshRepoMediaMetaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaMetaSuggestionsRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type GetRepoMediaMetaSuggestionsResponseObject interface {
+	VisitGetRepoMediaMetaSuggestionsResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaMetaSuggestions200JSONResponse []MatchSuggestion
+
+func (response GetRepoMediaMetaSuggestions200JSONResponse) VisitGetRepoMediaMetaSuggestionsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaMetaSuggestions400JSONResponse Error
+
+func (response GetRepoMediaMetaSuggestions400JSONResponse) VisitGetRepoMediaMetaSuggestionsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaPreviewRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type GetRepoMediaPreviewResponseObject interface {
+	VisitGetRepoMediaPreviewResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaPreview200Videomp4Response struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response GetRepoMediaPreview200Videomp4Response) VisitGetRepoMediaPreviewResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "video/mp4")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetRepoMediaPreview202JSONResponse Job
+
+func (response GetRepoMediaPreview202JSONResponse) VisitGetRepoMediaPreviewResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaPreview400JSONResponse Error
+
+func (response GetRepoMediaPreview400JSONResponse) VisitGetRepoMediaPreviewResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaPreview501JSONResponse Error
+
+func (response GetRepoMediaPreview501JSONResponse) VisitGetRepoMediaPreviewResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(501)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaProgressRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type GetRepoMediaProgressResponseObject interface {
+	VisitGetRepoMediaProgressResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaProgress200JSONResponse PlaybackProgress
+
+func (response GetRepoMediaProgress200JSONResponse) VisitGetRepoMediaProgressResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaProgress400JSONResponse Error
+
+func (response GetRepoMediaProgress400JSONResponse) VisitGetRepoMediaProgressResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateRepoMediaProgressRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	Body    *UpdateRepoMediaProgressJSONRequestBody
+}
+
+type UpdateRepoMediaProgressResponseObject interface {
+	VisitUpdateRepoMediaProgressResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type UpdateRepoMediaProgress200JSONResponse PlaybackProgress
+
+func (response UpdateRepoMediaProgress200JSONResponse) VisitUpdateRepoMediaProgressResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateRepoMediaProgress400JSONResponse Error
+
+func (response UpdateRepoMediaProgress400JSONResponse) VisitUpdateRepoMediaProgressResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RestoreRepoMediaRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type RestoreRepoMediaResponseObject interface {
+	VisitRestoreRepoMediaResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type RestoreRepoMedia202JSONResponse Job
+
+func (response RestoreRepoMedia202JSONResponse) VisitRestoreRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RestoreRepoMedia400JSONResponse Error
+
+func (response RestoreRepoMedia400JSONResponse) VisitRestoreRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStatsRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type GetRepoMediaStatsResponseObject interface {
+	VisitGetRepoMediaStatsResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaStats200JSONResponse MediaStats
+
+func (response GetRepoMediaStats200JSONResponse) VisitGetRepoMediaStatsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStats400JSONResponse Error
+
+func (response GetRepoMediaStats400JSONResponse) VisitGetRepoMediaStatsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStoryboardRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type GetRepoMediaStoryboardResponseObject interface {
+	VisitGetRepoMediaStoryboardResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaStoryboard200TextvttResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response GetRepoMediaStoryboard200TextvttResponse) VisitGetRepoMediaStoryboardResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "text/vtt")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetRepoMediaStoryboard202JSONResponse Job
+
+func (response GetRepoMediaStoryboard202JSONResponse) VisitGetRepoMediaStoryboardResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStoryboard400JSONResponse Error
+
+func (response GetRepoMediaStoryboard400JSONResponse) VisitGetRepoMediaStoryboardResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStoryboard501JSONResponse Error
+
+func (response GetRepoMediaStoryboard501JSONResponse) VisitGetRepoMediaStoryboardResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(501)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStoryboardSheetRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	Sheet   int    `json:"sheet"`
+}
+
+type GetRepoMediaStoryboardSheetResponseObject interface {
+	VisitGetRepoMediaStoryboardSheetResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaStoryboardSheet200ImagejpegResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response GetRepoMediaStoryboardSheet200ImagejpegResponse) VisitGetRepoMediaStoryboardSheetResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "image/jpeg")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetRepoMediaStoryboardSheet400JSONResponse Error
+
+func (response GetRepoMediaStoryboardSheet400JSONResponse) VisitGetRepoMediaStoryboardSheetResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStoryboardSheet501JSONResponse Error
+
+func (response GetRepoMediaStoryboardSheet501JSONResponse) VisitGetRepoMediaStoryboardSheetResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(501)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStoryboardSheet503ResponseHeaders struct {
+	RetryAfter int
+}
+
+type GetRepoMediaStoryboardSheet503JSONResponse struct {
+	Body    Error
+	Headers GetRepoMediaStoryboardSheet503ResponseHeaders
+}
+
+func (response GetRepoMediaStoryboardSheet503JSONResponse) VisitGetRepoMediaStoryboardSheetResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetRepoMediaStreamsRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	Params  GetRepoMediaStreamsParams
+}
+
+type GetRepoMediaStreamsResponseObject interface {
+	VisitGetRepoMediaStreamsResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaStreams200JSONResponse []MediaFormat
+
+func (response GetRepoMediaStreams200JSONResponse) VisitGetRepoMediaStreamsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStreams400JSONResponse Error
+
+func (response GetRepoMediaStreams400JSONResponse) VisitGetRepoMediaStreamsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStreams406JSONResponse Error
+
+func (response GetRepoMediaStreams406JSONResponse) VisitGetRepoMediaStreamsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(406)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStreams410JSONResponse Error
+
+func (response GetRepoMediaStreams410JSONResponse) VisitGetRepoMediaStreamsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(410)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStreams429JSONResponse Error
+
+func (response GetRepoMediaStreams429JSONResponse) VisitGetRepoMediaStreamsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStreams503ResponseHeaders struct {
+	RetryAfter int
+}
+
+type GetRepoMediaStreams503JSONResponse struct {
+	Body    Error
+	Headers GetRepoMediaStreams503ResponseHeaders
+}
+
+func (response GetRepoMediaStreams503JSONResponse) VisitGetRepoMediaStreamsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetRepoMediaStreamRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	Format  string `json:"format"`
+	Params  GetRepoMediaStreamParams
+}
+
+type GetRepoMediaStreamResponseObject interface {
+	VisitGetRepoMediaStreamResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaStream200ResponseHeaders struct {
+	ContentDisposition string
+	ContentType        string
+}
+
+type GetRepoMediaStream200SchemaResponse struct {
+	Body          io.Reader
+	Headers       GetRepoMediaStream200ResponseHeaders
+	ContentLength int64
+}
+
+func (response GetRepoMediaStream200SchemaResponse) VisitGetRepoMediaStreamResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "schema")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.Header().Set("Content-Type", fmt.Sprint(response.Headers.ContentType))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetRepoMediaStream202JSONResponse Job
+
+func (response GetRepoMediaStream202JSONResponse) VisitGetRepoMediaStreamResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStream400JSONResponse Error
+
+func (response GetRepoMediaStream400JSONResponse) VisitGetRepoMediaStreamResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStream410JSONResponse Error
+
+func (response GetRepoMediaStream410JSONResponse) VisitGetRepoMediaStreamResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(410)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStream429JSONResponse Error
+
+func (response GetRepoMediaStream429JSONResponse) VisitGetRepoMediaStreamResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaStream503ResponseHeaders struct {
+	RetryAfter int
+}
+
+type GetRepoMediaStream503JSONResponse struct {
+	Body    Error
+	Headers GetRepoMediaStream503ResponseHeaders
+}
+
+func (response GetRepoMediaStream503JSONResponse) VisitGetRepoMediaStreamResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetRepoMediaSubtitlesRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type GetRepoMediaSubtitlesResponseObject interface {
+	VisitGetRepoMediaSubtitlesResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaSubtitles200JSONResponse []SubtitleTrack
+
+func (response GetRepoMediaSubtitles200JSONResponse) VisitGetRepoMediaSubtitlesResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaSubtitles400JSONResponse Error
+
+func (response GetRepoMediaSubtitles400JSONResponse) VisitGetRepoMediaSubtitlesResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoMediaSubtitleRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	TrackId string `json:"trackId"`
+}
+
+type GetRepoMediaSubtitleResponseObject interface {
+	VisitGetRepoMediaSubtitleResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoMediaSubtitle200TextvttResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response GetRepoMediaSubtitle200TextvttResponse) VisitGetRepoMediaSubtitleResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "text/vtt")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetRepoMediaSubtitle400JSONResponse Error
+
+func (response GetRepoMediaSubtitle400JSONResponse) VisitGetRepoMediaSubtitleResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateRepoMediaTagsRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+	Body    *UpdateRepoMediaTagsJSONRequestBody
+}
+
+type UpdateRepoMediaTagsResponseObject interface {
+	VisitUpdateRepoMediaTagsResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type UpdateRepoMediaTags200JSONResponse Media
+
+func (response UpdateRepoMediaTags200JSONResponse) VisitUpdateRepoMediaTagsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateRepoMediaTags400JSONResponse Error
+
+func (response UpdateRepoMediaTags400JSONResponse) VisitUpdateRepoMediaTagsResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoTrashRequestObject struct {
+	RepoId string `json:"repoId"`
+}
+
+type GetRepoTrashResponseObject interface {
+	VisitGetRepoTrashResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetRepoTrash200JSONResponse []TrashedMedia
+
+func (response GetRepoTrash200JSONResponse) VisitGetRepoTrashResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRepoTrash400JSONResponse Error
+
+func (response GetRepoTrash400JSONResponse) VisitGetRepoTrashResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RestoreRepoTrashRequestObject struct {
+	RepoId  string `json:"repoId"`
+	MediaId string `json:"mediaId"`
+}
+
+type RestoreRepoTrashResponseObject interface {
+	VisitRestoreRepoTrashResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type RestoreRepoTrash200JSONResponse Media
+
+func (response RestoreRepoTrash200JSONResponse) VisitRestoreRepoTrashResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RestoreRepoTrash400JSONResponse Error
+
+func (response RestoreRepoTrash400JSONResponse) VisitRestoreRepoTrashResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteSessionQualityRequestObject struct {
+	Params DeleteSessionQualityParams
+}
+
+type DeleteSessionQualityResponseObject interface {
+	VisitDeleteSessionQualityResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type DeleteSessionQuality204Response struct {
+}
+
+func (response DeleteSessionQuality204Response) VisitDeleteSessionQualityResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteSessionQuality400JSONResponse Error
+
+func (response DeleteSessionQuality400JSONResponse) VisitDeleteSessionQualityResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetSessionQualityRequestObject struct {
+	Params GetSessionQualityParams
+}
+
+type GetSessionQualityResponseObject interface {
+	VisitGetSessionQualityResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetSessionQuality200JSONResponse SessionQuality
+
+func (response GetSessionQuality200JSONResponse) VisitGetSessionQualityResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetSessionQuality400JSONResponse Error
+
+func (response GetSessionQuality400JSONResponse) VisitGetSessionQualityResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateSessionQualityRequestObject struct {
+	Params UpdateSessionQualityParams
+	Body   *UpdateSessionQualityJSONRequestBody
+}
+
+type UpdateSessionQualityResponseObject interface {
+	VisitUpdateSessionQualityResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type UpdateSessionQuality200JSONResponse SessionQuality
+
+func (response UpdateSessionQuality200JSONResponse) VisitUpdateSessionQualityResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateSessionQuality400JSONResponse Error
+
+func (response UpdateSessionQuality400JSONResponse) VisitUpdateSessionQualityResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetSystemRequestObject struct {
+}
+
+type GetSystemResponseObject interface {
+	VisitGetSystemResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetSystem200JSONResponse System
+
+func (response GetSystem200JSONResponse) VisitGetSystemResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUsageRequestObject struct {
+	Params GetUsageParams
+}
+
+type GetUsageResponseObject interface {
+	VisitGetUsageResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type GetUsage200JSONResponse []ClientUsage
+
+func (response GetUsage200JSONResponse) VisitGetUsageResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUsage400JSONResponse Error
+
+func (response GetUsage400JSONResponse) VisitGetUsageResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ExportUserDataRequestObject struct {
+	Params ExportUserDataParams
+}
+
+type ExportUserDataResponseObject interface {
+	VisitExportUserDataResponse(w http.ResponseWriter, r *http.Request) error
+}
+
+type ExportUserData200ResponseHeaders struct {
+	ContentDisposition string
+}
+
+type ExportUserData200JSONResponse struct {
+	Body    UserExport
+	Headers ExportUserData200ResponseHeaders
+}
+
+func (response ExportUserData200JSONResponse) VisitExportUserDataResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExportUserData200TextcsvResponse struct {
+	Body          io.Reader
+	Headers       ExportUserData200ResponseHeaders
+	ContentLength int64
+}
+
+func (response ExportUserData200TextcsvResponse) VisitExportUserDataResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "text/csv")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportUserData400JSONResponse Error
+
+func (response ExportUserData400JSONResponse) VisitExportUserDataResponse(w http.ResponseWriter, _ *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Gets the episodes of series in the library airing in a date range.
+	// (GET /calendar)
+	GetCalendar(ctx context.Context, request GetCalendarRequestObject) (GetCalendarResponseObject, error)
+	// Gets all collections.
+	// (GET /collections)
+	GetCollections(ctx context.Context, request GetCollectionsRequestObject) (GetCollectionsResponseObject, error)
+	// Creates a collection.
+	// (POST /collections)
+	CreateCollection(ctx context.Context, request CreateCollectionRequestObject) (CreateCollectionResponseObject, error)
+	// Deletes a collection.
+	// (DELETE /collections/{collectionId})
+	DeleteCollection(ctx context.Context, request DeleteCollectionRequestObject) (DeleteCollectionResponseObject, error)
+	// Gets a collection.
+	// (GET /collections/{collectionId})
+	GetCollectionById(ctx context.Context, request GetCollectionByIdRequestObject) (GetCollectionByIdResponseObject, error)
+	// Updates a collection.
+	// (PUT /collections/{collectionId})
+	UpdateCollection(ctx context.Context, request UpdateCollectionRequestObject) (UpdateCollectionResponseObject, error)
+	// Subscribes to change notifications.
+	// (GET /events)
+	GetEvents(ctx context.Context, request GetEventsRequestObject) (GetEventsResponseObject, error)
+	// Gets the supported media formats.
+	// (GET /formats)
+	GetFormats(ctx context.Context, request GetFormatsRequestObject) (GetFormatsResponseObject, error)
+	// Cancels a job.
+	// (DELETE /jobs/{jobId})
+	CancelJob(ctx context.Context, request CancelJobRequestObject) (CancelJobResponseObject, error)
+	// Gets a job.
+	// (GET /jobs/{jobId})
+	GetJobById(ctx context.Context, request GetJobByIdRequestObject) (GetJobByIdResponseObject, error)
+	// Searches metadata match candidates.
+	// (GET /meta/search)
+	SearchMeta(ctx context.Context, request SearchMetaRequestObject) (SearchMetaResponseObject, error)
+	// Lists repositories.
+	// (GET /repos)
+	GetRepos(ctx context.Context, request GetReposRequestObject) (GetReposResponseObject, error)
+	// Gets a repository.
+	// (GET /repos/{id})
+	GetRepoById(ctx context.Context, request GetRepoByIdRequestObject) (GetRepoByIdResponseObject, error)
+	// Gets a repository's capabilities.
+	// (GET /repos/{id}/capabilities)
+	GetRepoCapabilities(ctx context.Context, request GetRepoCapabilitiesRequestObject) (GetRepoCapabilitiesResponseObject, error)
+	// Gets a repository's content fingerprint.
+	// (GET /repos/{id}/fingerprint)
+	GetRepoFingerprint(ctx context.Context, request GetRepoFingerprintRequestObject) (GetRepoFingerprintResponseObject, error)
+	// Lists a directory of a repository.
+	// (GET /repos/{id}/fs)
+	GetRepoDirectory(ctx context.Context, request GetRepoDirectoryRequestObject) (GetRepoDirectoryResponseObject, error)
+	// Hashes the video frames of a repository's media for identification.
+	// (POST /repos/{id}/identify)
+	IdentifyRepo(ctx context.Context, request IdentifyRepoRequestObject) (IdentifyRepoResponseObject, error)
+	// Gets a repository's last integrity verification.
+	// (GET /repos/{id}/integrity)
+	GetRepoIntegrity(ctx context.Context, request GetRepoIntegrityRequestObject) (GetRepoIntegrityResponseObject, error)
+	// Verifies a repository's integrity.
+	// (POST /repos/{id}/integrity)
+	VerifyRepo(ctx context.Context, request VerifyRepoRequestObject) (VerifyRepoResponseObject, error)
+	// Lists a repository's media.
+	// (GET /repos/{id}/media)
+	GetRepoMedia(ctx context.Context, request GetRepoMediaRequestObject) (GetRepoMediaResponseObject, error)
+	// Adds media to a repository.
+	// (POST /repos/{id}/media)
+	AddRepoMedia(ctx context.Context, request AddRepoMediaRequestObject) (AddRepoMediaResponseObject, error)
+	// Applies an operation to multiple media.
+	// (POST /repos/{id}/media/bulk)
+	BulkRepoMedia(ctx context.Context, request BulkRepoMediaRequestObject) (BulkRepoMediaResponseObject, error)
+	// Probes a repository's media.
+	// (POST /repos/{id}/probe)
+	ProbeRepo(ctx context.Context, request ProbeRepoRequestObject) (ProbeRepoResponseObject, error)
+	// Gets a repository's replication manifest.
+	// (GET /repos/{id}/replica)
+	GetRepoReplica(ctx context.Context, request GetRepoReplicaRequestObject) (GetRepoReplicaResponseObject, error)
+	// Replicates a repository from its origin.
+	// (POST /repos/{id}/replicate)
+	ReplicateRepo(ctx context.Context, request ReplicateRepoRequestObject) (ReplicateRepoResponseObject, error)
+	// Gets a repository's last scan.
+	// (GET /repos/{id}/scan)
+	GetRepoScan(ctx context.Context, request GetRepoScanRequestObject) (GetRepoScanResponseObject, error)
+	// Scans a repository.
+	// (POST /repos/{id}/scan)
+	ScanRepo(ctx context.Context, request ScanRepoRequestObject) (ScanRepoResponseObject, error)
+	// Gets a repository's series.
+	// (GET /repos/{id}/series)
+	GetRepoSeries(ctx context.Context, request GetRepoSeriesRequestObject) (GetRepoSeriesResponseObject, error)
+	// Gets a season of a repository's series.
+	// (GET /repos/{id}/series/{seriesId}/seasons/{season})
+	GetRepoSeriesSeason(ctx context.Context, request GetRepoSeriesSeasonRequestObject) (GetRepoSeriesSeasonResponseObject, error)
+	// Purges a repository's cache.
+	// (DELETE /repos/{repoId}/cache)
+	PurgeRepoCache(ctx context.Context, request PurgeRepoCacheRequestObject) (PurgeRepoCacheResponseObject, error)
+	// Gets a repository's cache usage.
+	// (GET /repos/{repoId}/cache)
+	GetRepoCache(ctx context.Context, request GetRepoCacheRequestObject) (GetRepoCacheResponseObject, error)
+	// Removes a repository's media.
+	// (DELETE /repos/{repoId}/media/{mediaId})
+	DeleteRepoMedia(ctx context.Context, request DeleteRepoMediaRequestObject) (DeleteRepoMediaResponseObject, error)
+	// Gets a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId})
+	GetRepoMediaById(ctx context.Context, request GetRepoMediaByIdRequestObject) (GetRepoMediaByIdResponseObject, error)
+	// Gets the cold storage status of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/archive)
+	GetRepoMediaArchive(ctx context.Context, request GetRepoMediaArchiveRequestObject) (GetRepoMediaArchiveResponseObject, error)
+	// Queues moving a repository's media file to cold storage.
+	// (POST /repos/{repoId}/media/{mediaId}/archive)
+	ArchiveRepoMedia(ctx context.Context, request ArchiveRepoMediaRequestObject) (ArchiveRepoMediaResponseObject, error)
+	// Lists the audio tracks of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/audio)
+	GetRepoMediaAudioTracks(ctx context.Context, request GetRepoMediaAudioTracksRequestObject) (GetRepoMediaAudioTracksResponseObject, error)
+	// Lists the chapters of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/chapters)
+	GetRepoMediaChapters(ctx context.Context, request GetRepoMediaChaptersRequestObject) (GetRepoMediaChaptersResponseObject, error)
+	// Queues a conversion of media.
+	// (POST /repos/{repoId}/media/{mediaId}/convert)
+	ConvertRepoMedia(ctx context.Context, request ConvertRepoMediaRequestObject) (ConvertRepoMediaResponseObject, error)
+	// Gets the MPEG-DASH manifest of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/dash/manifest.mpd)
+	GetRepoMediaDashManifest(ctx context.Context, request GetRepoMediaDashManifestRequestObject) (GetRepoMediaDashManifestResponseObject, error)
+	// Gets a representation of a repository's media for MPEG-DASH streaming.
+	// (GET /repos/{repoId}/media/{mediaId}/dash/{representationId})
+	GetRepoMediaDashRepresentation(ctx context.Context, request GetRepoMediaDashRepresentationRequestObject) (GetRepoMediaDashRepresentationResponseObject, error)
+	// Downloads media.
+	// (GET /repos/{repoId}/media/{mediaId}/download)
+	GetRepoMediaDownload(ctx context.Context, request GetRepoMediaDownloadRequestObject) (GetRepoMediaDownloadResponseObject, error)
+	// Lists the extras of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/extras)
+	GetRepoMediaExtras(ctx context.Context, request GetRepoMediaExtrasRequestObject) (GetRepoMediaExtrasResponseObject, error)
+	// Gets an HTTP stream of an extra of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/extras/{extraId})
+	GetRepoMediaExtra(ctx context.Context, request GetRepoMediaExtraRequestObject) (GetRepoMediaExtraResponseObject, error)
+	// Stops following the series of an episode for the authenticated user.
+	// (DELETE /repos/{repoId}/media/{mediaId}/follow)
+	UnfollowRepoMediaSeries(ctx context.Context, request UnfollowRepoMediaSeriesRequestObject) (UnfollowRepoMediaSeriesResponseObject, error)
+	// Follows the series of an episode for the authenticated user.
+	// (POST /repos/{repoId}/media/{mediaId}/follow)
+	FollowRepoMediaSeries(ctx context.Context, request FollowRepoMediaSeriesRequestObject) (FollowRepoMediaSeriesResponseObject, error)
+	// Shows hidden media in the authenticated user's listings again.
+	// (DELETE /repos/{repoId}/media/{mediaId}/hide)
+	UnhideRepoMedia(ctx context.Context, request UnhideRepoMediaRequestObject) (UnhideRepoMediaResponseObject, error)
+	// Hides media from the authenticated user's listings.
+	// (POST /repos/{repoId}/media/{mediaId}/hide)
+	HideRepoMedia(ctx context.Context, request HideRepoMediaRequestObject) (HideRepoMediaResponseObject, error)
+	// Lists the image gallery of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/images)
+	GetRepoMediaImages(ctx context.Context, request GetRepoMediaImagesRequestObject) (GetRepoMediaImagesResponseObject, error)
+	// Gets a promotional image of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/images/{imageId})
+	GetRepoMediaImage(ctx context.Context, request GetRepoMediaImageRequestObject) (GetRepoMediaImageResponseObject, error)
+	// Chooses the preferred poster or backdrop of a repository's media.
+	// (PUT /repos/{repoId}/media/{mediaId}/images/{imageId}/preferred)
+	SetRepoMediaPreferredImage(ctx context.Context, request SetRepoMediaPreferredImageRequestObject) (SetRepoMediaPreferredImageResponseObject, error)
+	// Unlocks a repository's media.
+	// (DELETE /repos/{repoId}/media/{mediaId}/lock)
+	UnlockRepoMedia(ctx context.Context, request UnlockRepoMediaRequestObject) (UnlockRepoMediaResponseObject, error)
+	// Locks a repository's media against automatic changes.
+	// (POST /repos/{repoId}/media/{mediaId}/lock)
+	LockRepoMedia(ctx context.Context, request LockRepoMediaRequestObject) (LockRepoMediaResponseObject, error)
+	// Re-matches a repository's media metadata.
+	// (PUT /repos/{repoId}/media/{mediaId}/meta)
+	UpdateRepoMediaMeta(ctx context.Context, request UpdateRepoMediaMetaRequestObject) (UpdateRepoMediaMetaResponseObject, error)
+	// Refreshes a repository's media metadata.
+	// (POST /repos/{repoId}/media/{mediaId}/meta/refresh)
+	RefreshRepoMediaMeta(ctx context.Context, request RefreshRepoMediaMetaRequestObject) (RefreshRepoMediaMetaResponseObject, error)
+	// Suggests likely metadata matches of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/meta/suggestions)
+	GetRepoMediaMetaSuggestions(ctx context.Context, request GetRepoMediaMetaSuggestionsRequestObject) (GetRepoMediaMetaSuggestionsResponseObject, error)
+	// Gets a preview clip of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/preview)
+	GetRepoMediaPreview(ctx context.Context, request GetRepoMediaPreviewRequestObject) (GetRepoMediaPreviewResponseObject, error)
+	// Gets the playback progress of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/progress)
+	GetRepoMediaProgress(ctx context.Context, request GetRepoMediaProgressRequestObject) (GetRepoMediaProgressResponseObject, error)
+	// Updates the playback progress of a repository's media.
+	// (PUT /repos/{repoId}/media/{mediaId}/progress)
+	UpdateRepoMediaProgress(ctx context.Context, request UpdateRepoMediaProgressRequestObject) (UpdateRepoMediaProgressResponseObject, error)
+	// Queues moving a repository's media file back from cold storage.
+	// (POST /repos/{repoId}/media/{mediaId}/restore)
+	RestoreRepoMedia(ctx context.Context, request RestoreRepoMediaRequestObject) (RestoreRepoMediaResponseObject, error)
+	// Gets a repository's media streaming statistics.
+	// (GET /repos/{repoId}/media/{mediaId}/stats)
+	GetRepoMediaStats(ctx context.Context, request GetRepoMediaStatsRequestObject) (GetRepoMediaStatsResponseObject, error)
+	// Gets the thumbnail track of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/storyboard)
+	GetRepoMediaStoryboard(ctx context.Context, request GetRepoMediaStoryboardRequestObject) (GetRepoMediaStoryboardResponseObject, error)
+	// Gets a storyboard sprite sheet of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/storyboard/{sheet})
+	GetRepoMediaStoryboardSheet(ctx context.Context, request GetRepoMediaStoryboardSheetRequestObject) (GetRepoMediaStoryboardSheetResponseObject, error)
+	// Lists the available variants of a repository's media or gets a negotiated HTTP media stream.
+	// (GET /repos/{repoId}/media/{mediaId}/stream)
+	GetRepoMediaStreams(ctx context.Context, request GetRepoMediaStreamsRequestObject) (GetRepoMediaStreamsResponseObject, error)
+	// Gets a HTTP media stream.
+	// (GET /repos/{repoId}/media/{mediaId}/stream/{format})
+	GetRepoMediaStream(ctx context.Context, request GetRepoMediaStreamRequestObject) (GetRepoMediaStreamResponseObject, error)
+	// Lists the subtitle tracks of a repository's media.
+	// (GET /repos/{repoId}/media/{mediaId}/subtitles)
+	GetRepoMediaSubtitles(ctx context.Context, request GetRepoMediaSubtitlesRequestObject) (GetRepoMediaSubtitlesResponseObject, error)
+	// Gets a subtitle track as WebVTT.
+	// (GET /repos/{repoId}/media/{mediaId}/subtitles/{trackId}.vtt)
+	GetRepoMediaSubtitle(ctx context.Context, request GetRepoMediaSubtitleRequestObject) (GetRepoMediaSubtitleResponseObject, error)
+	// Replaces a repository's media tags.
+	// (PUT /repos/{repoId}/media/{mediaId}/tags)
+	UpdateRepoMediaTags(ctx context.Context, request UpdateRepoMediaTagsRequestObject) (UpdateRepoMediaTagsResponseObject, error)
+	// Gets a repository's trashed media.
+	// (GET /repos/{repoId}/trash)
+	GetRepoTrash(ctx context.Context, request GetRepoTrashRequestObject) (GetRepoTrashResponseObject, error)
+	// Restores a repository's trashed media.
+	// (POST /repos/{repoId}/trash/{mediaId}/restore)
+	RestoreRepoTrash(ctx context.Context, request RestoreRepoTrashRequestObject) (RestoreRepoTrashResponseObject, error)
+	// Removes the quality cap of the client session.
+	// (DELETE /session/quality)
+	DeleteSessionQuality(ctx context.Context, request DeleteSessionQualityRequestObject) (DeleteSessionQualityResponseObject, error)
+	// Gets the quality cap of the client session.
+	// (GET /session/quality)
+	GetSessionQuality(ctx context.Context, request GetSessionQualityRequestObject) (GetSessionQualityResponseObject, error)
+	// Sets a quality cap for the client session.
+	// (PUT /session/quality)
+	UpdateSessionQuality(ctx context.Context, request UpdateSessionQualityRequestObject) (UpdateSessionQualityResponseObject, error)
+	// Gets the server's system information.
+	// (GET /system)
+	GetSystem(ctx context.Context, request GetSystemRequestObject) (GetSystemResponseObject, error)
+	// Gets the bytes served to clients in a month.
+	// (GET /usage)
+	GetUsage(ctx context.Context, request GetUsageRequestObject) (GetUsageResponseObject, error)
+	// Exports the requesting user's watch data.
+	// (GET /users/me/export)
+	ExportUserData(ctx context.Context, request ExportUserDataRequestObject) (ExportUserDataResponseObject, error)
+}
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// GetCalendar operation middleware
+func (sh *strictHandler) GetCalendar(w http.ResponseWriter, r *http.Request, params GetCalendarParams) {
+	var request GetCalendarRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetCalendar(ctx, request.(GetCalendarRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetCalendar")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetCalendarResponseObject); ok {
+		if err := validResponse.VisitGetCalendarResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetCollections operation middleware
+func (sh *strictHandler) GetCollections(w http.ResponseWriter, r *http.Request) {
+	var request GetCollectionsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetCollections(ctx, request.(GetCollectionsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetCollections")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetCollectionsResponseObject); ok {
+		if err := validResponse.VisitGetCollectionsResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateCollection operation middleware
+func (sh *strictHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	var request CreateCollectionRequestObject
+
+	var body CreateCollectionJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateCollection(ctx, request.(CreateCollectionRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateCollection")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateCollectionResponseObject); ok {
+		if err := validResponse.VisitCreateCollectionResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteCollection operation middleware
+func (sh *strictHandler) DeleteCollection(w http.ResponseWriter, r *http.Request, collectionId string) {
+	var request DeleteCollectionRequestObject
+
+	request.CollectionId = collectionId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteCollection(ctx, request.(DeleteCollectionRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteCollection")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteCollectionResponseObject); ok {
+		if err := validResponse.VisitDeleteCollectionResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetCollectionById operation middleware
+func (sh *strictHandler) GetCollectionById(w http.ResponseWriter, r *http.Request, collectionId string) {
+	var request GetCollectionByIdRequestObject
+
+	request.CollectionId = collectionId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetCollectionById(ctx, request.(GetCollectionByIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetCollectionById")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetCollectionByIdResponseObject); ok {
+		if err := validResponse.VisitGetCollectionByIdResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateCollection operation middleware
+func (sh *strictHandler) UpdateCollection(w http.ResponseWriter, r *http.Request, collectionId string) {
+	var request UpdateCollectionRequestObject
+
+	request.CollectionId = collectionId
+
+	var body UpdateCollectionJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateCollection(ctx, request.(UpdateCollectionRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateCollection")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateCollectionResponseObject); ok {
+		if err := validResponse.VisitUpdateCollectionResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetEvents operation middleware
+func (sh *strictHandler) GetEvents(w http.ResponseWriter, r *http.Request, params GetEventsParams) {
+	var request GetEventsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetEvents(ctx, request.(GetEventsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetEvents")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetEventsResponseObject); ok {
+		if err := validResponse.VisitGetEventsResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetFormats operation middleware
+func (sh *strictHandler) GetFormats(w http.ResponseWriter, r *http.Request) {
+	var request GetFormatsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetFormats(ctx, request.(GetFormatsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetFormats")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetFormatsResponseObject); ok {
+		if err := validResponse.VisitGetFormatsResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CancelJob operation middleware
+func (sh *strictHandler) CancelJob(w http.ResponseWriter, r *http.Request, jobId string) {
+	var request CancelJobRequestObject
+
+	request.JobId = jobId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CancelJob(ctx, request.(CancelJobRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CancelJob")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CancelJobResponseObject); ok {
+		if err := validResponse.VisitCancelJobResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetJobById operation middleware
+func (sh *strictHandler) GetJobById(w http.ResponseWriter, r *http.Request, jobId string) {
+	var request GetJobByIdRequestObject
+
+	request.JobId = jobId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetJobById(ctx, request.(GetJobByIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetJobById")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetJobByIdResponseObject); ok {
+		if err := validResponse.VisitGetJobByIdResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// SearchMeta operation middleware
+func (sh *strictHandler) SearchMeta(w http.ResponseWriter, r *http.Request, params SearchMetaParams) {
+	var request SearchMetaRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SearchMeta(ctx, request.(SearchMetaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SearchMeta")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SearchMetaResponseObject); ok {
+		if err := validResponse.VisitSearchMetaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepos operation middleware
+func (sh *strictHandler) GetRepos(w http.ResponseWriter, r *http.Request) {
+	var request GetReposRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepos(ctx, request.(GetReposRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepos")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetReposResponseObject); ok {
+		if err := validResponse.VisitGetReposResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoById operation middleware
+func (sh *strictHandler) GetRepoById(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetRepoByIdRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoById(ctx, request.(GetRepoByIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoById")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoByIdResponseObject); ok {
+		if err := validResponse.VisitGetRepoByIdResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoCapabilities operation middleware
+func (sh *strictHandler) GetRepoCapabilities(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetRepoCapabilitiesRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoCapabilities(ctx, request.(GetRepoCapabilitiesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoCapabilities")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoCapabilitiesResponseObject); ok {
+		if err := validResponse.VisitGetRepoCapabilitiesResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoFingerprint operation middleware
+func (sh *strictHandler) GetRepoFingerprint(w http.ResponseWriter, r *http.Request, id string, params GetRepoFingerprintParams) {
+	var request GetRepoFingerprintRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoFingerprint(ctx, request.(GetRepoFingerprintRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoFingerprint")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoFingerprintResponseObject); ok {
+		if err := validResponse.VisitGetRepoFingerprintResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoDirectory operation middleware
+func (sh *strictHandler) GetRepoDirectory(w http.ResponseWriter, r *http.Request, id string, params GetRepoDirectoryParams) {
+	var request GetRepoDirectoryRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoDirectory(ctx, request.(GetRepoDirectoryRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoDirectory")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoDirectoryResponseObject); ok {
+		if err := validResponse.VisitGetRepoDirectoryResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// IdentifyRepo operation middleware
+func (sh *strictHandler) IdentifyRepo(w http.ResponseWriter, r *http.Request, id string) {
+	var request IdentifyRepoRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.IdentifyRepo(ctx, request.(IdentifyRepoRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "IdentifyRepo")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(IdentifyRepoResponseObject); ok {
+		if err := validResponse.VisitIdentifyRepoResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoIntegrity operation middleware
+func (sh *strictHandler) GetRepoIntegrity(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetRepoIntegrityRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoIntegrity(ctx, request.(GetRepoIntegrityRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoIntegrity")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoIntegrityResponseObject); ok {
+		if err := validResponse.VisitGetRepoIntegrityResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// VerifyRepo operation middleware
+func (sh *strictHandler) VerifyRepo(w http.ResponseWriter, r *http.Request, id string) {
+	var request VerifyRepoRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.VerifyRepo(ctx, request.(VerifyRepoRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "VerifyRepo")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(VerifyRepoResponseObject); ok {
+		if err := validResponse.VisitVerifyRepoResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoMedia operation middleware
+func (sh *strictHandler) GetRepoMedia(w http.ResponseWriter, r *http.Request, id string, params GetRepoMediaParams) {
+	var request GetRepoMediaRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMedia(ctx, request.(GetRepoMediaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMedia")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// AddRepoMedia operation middleware
+func (sh *strictHandler) AddRepoMedia(w http.ResponseWriter, r *http.Request, id string) {
+	var request AddRepoMediaRequestObject
+
+	request.Id = id
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+
+		var body AddRepoMediaJSONRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+			return
+		}
+		request.JSONBody = &body
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if reader, err := r.MultipartReader(); err != nil {
+			sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode multipart body: %w", err))
+			return
+		} else {
+			request.MultipartBody = reader
+		}
+	}
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.AddRepoMedia(ctx, request.(AddRepoMediaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "AddRepoMedia")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(AddRepoMediaResponseObject); ok {
+		if err := validResponse.VisitAddRepoMediaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// BulkRepoMedia operation middleware
+func (sh *strictHandler) BulkRepoMedia(w http.ResponseWriter, r *http.Request, id string) {
+	var request BulkRepoMediaRequestObject
+
+	request.Id = id
+
+	var body BulkRepoMediaJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.BulkRepoMedia(ctx, request.(BulkRepoMediaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "BulkRepoMedia")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(BulkRepoMediaResponseObject); ok {
+		if err := validResponse.VisitBulkRepoMediaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ProbeRepo operation middleware
+func (sh *strictHandler) ProbeRepo(w http.ResponseWriter, r *http.Request, id string) {
+	var request ProbeRepoRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ProbeRepo(ctx, request.(ProbeRepoRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ProbeRepo")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ProbeRepoResponseObject); ok {
+		if err := validResponse.VisitProbeRepoResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoReplica operation middleware
+func (sh *strictHandler) GetRepoReplica(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetRepoReplicaRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoReplica(ctx, request.(GetRepoReplicaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoReplica")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoReplicaResponseObject); ok {
+		if err := validResponse.VisitGetRepoReplicaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ReplicateRepo operation middleware
+func (sh *strictHandler) ReplicateRepo(w http.ResponseWriter, r *http.Request, id string) {
+	var request ReplicateRepoRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ReplicateRepo(ctx, request.(ReplicateRepoRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ReplicateRepo")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ReplicateRepoResponseObject); ok {
+		if err := validResponse.VisitReplicateRepoResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoScan operation middleware
+func (sh *strictHandler) GetRepoScan(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetRepoScanRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoScan(ctx, request.(GetRepoScanRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoScan")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoScanResponseObject); ok {
+		if err := validResponse.VisitGetRepoScanResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ScanRepo operation middleware
+func (sh *strictHandler) ScanRepo(w http.ResponseWriter, r *http.Request, id string, params ScanRepoParams) {
+	var request ScanRepoRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ScanRepo(ctx, request.(ScanRepoRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ScanRepo")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ScanRepoResponseObject); ok {
+		if err := validResponse.VisitScanRepoResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoSeries operation middleware
+func (sh *strictHandler) GetRepoSeries(w http.ResponseWriter, r *http.Request, id string) {
+	var request GetRepoSeriesRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoSeries(ctx, request.(GetRepoSeriesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoSeries")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoSeriesResponseObject); ok {
+		if err := validResponse.VisitGetRepoSeriesResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoSeriesSeason operation middleware
+func (sh *strictHandler) GetRepoSeriesSeason(w http.ResponseWriter, r *http.Request, id string, seriesId string, season int) {
+	var request GetRepoSeriesSeasonRequestObject
+
+	request.Id = id
+	request.SeriesId = seriesId
+	request.Season = season
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoSeriesSeason(ctx, request.(GetRepoSeriesSeasonRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoSeriesSeason")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoSeriesSeasonResponseObject); ok {
+		if err := validResponse.VisitGetRepoSeriesSeasonResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PurgeRepoCache operation middleware
+func (sh *strictHandler) PurgeRepoCache(w http.ResponseWriter, r *http.Request, repoId string) {
+	var request PurgeRepoCacheRequestObject
+
+	request.RepoId = repoId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PurgeRepoCache(ctx, request.(PurgeRepoCacheRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PurgeRepoCache")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PurgeRepoCacheResponseObject); ok {
+		if err := validResponse.VisitPurgeRepoCacheResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoCache operation middleware
+func (sh *strictHandler) GetRepoCache(w http.ResponseWriter, r *http.Request, repoId string) {
+	var request GetRepoCacheRequestObject
+
+	request.RepoId = repoId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoCache(ctx, request.(GetRepoCacheRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoCache")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoCacheResponseObject); ok {
+		if err := validResponse.VisitGetRepoCacheResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteRepoMedia operation middleware
+func (sh *strictHandler) DeleteRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params DeleteRepoMediaParams) {
+	var request DeleteRepoMediaRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteRepoMedia(ctx, request.(DeleteRepoMediaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteRepoMedia")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteRepoMediaResponseObject); ok {
+		if err := validResponse.VisitDeleteRepoMediaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoMediaById operation middleware
+func (sh *strictHandler) GetRepoMediaById(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request GetRepoMediaByIdRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaById(ctx, request.(GetRepoMediaByIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaById")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaByIdResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaByIdResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoMediaArchive operation middleware
+func (sh *strictHandler) GetRepoMediaArchive(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request GetRepoMediaArchiveRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaArchive(ctx, request.(GetRepoMediaArchiveRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaArchive")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaArchiveResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaArchiveResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ArchiveRepoMedia operation middleware
+func (sh *strictHandler) ArchiveRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request ArchiveRepoMediaRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ArchiveRepoMedia(ctx, request.(ArchiveRepoMediaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ArchiveRepoMedia")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ArchiveRepoMediaResponseObject); ok {
+		if err := validResponse.VisitArchiveRepoMediaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoMediaAudioTracks operation middleware
+func (sh *strictHandler) GetRepoMediaAudioTracks(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request GetRepoMediaAudioTracksRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaAudioTracks(ctx, request.(GetRepoMediaAudioTracksRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaAudioTracks")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaAudioTracksResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaAudioTracksResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoMediaChapters operation middleware
+func (sh *strictHandler) GetRepoMediaChapters(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request GetRepoMediaChaptersRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaChapters(ctx, request.(GetRepoMediaChaptersRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaChapters")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaChaptersResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaChaptersResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ConvertRepoMedia operation middleware
+func (sh *strictHandler) ConvertRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params ConvertRepoMediaParams) {
+	var request ConvertRepoMediaRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+	request.Params = params
+
+	var body ConvertRepoMediaJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ConvertRepoMedia(ctx, request.(ConvertRepoMediaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ConvertRepoMedia")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ConvertRepoMediaResponseObject); ok {
+		if err := validResponse.VisitConvertRepoMediaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoMediaDashManifest operation middleware
+func (sh *strictHandler) GetRepoMediaDashManifest(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request GetRepoMediaDashManifestRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaDashManifest(ctx, request.(GetRepoMediaDashManifestRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaDashManifest")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaDashManifestResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaDashManifestResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoMediaDashRepresentation operation middleware
+func (sh *strictHandler) GetRepoMediaDashRepresentation(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, representationId string) {
+	var request GetRepoMediaDashRepresentationRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+	request.RepresentationId = representationId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaDashRepresentation(ctx, request.(GetRepoMediaDashRepresentationRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaDashRepresentation")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaDashRepresentationResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaDashRepresentationResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoMediaDownload operation middleware
+func (sh *strictHandler) GetRepoMediaDownload(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request GetRepoMediaDownloadRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaDownload(ctx, request.(GetRepoMediaDownloadRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaDownload")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaDownloadResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaDownloadResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoMediaExtras operation middleware
+func (sh *strictHandler) GetRepoMediaExtras(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request GetRepoMediaExtrasRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaExtras(ctx, request.(GetRepoMediaExtrasRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaExtras")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaExtrasResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaExtrasResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoMediaExtra operation middleware
+func (sh *strictHandler) GetRepoMediaExtra(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, extraId string) {
+	var request GetRepoMediaExtraRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+	request.ExtraId = extraId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaExtra(ctx, request.(GetRepoMediaExtraRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaExtra")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaExtraResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaExtraResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UnfollowRepoMediaSeries operation middleware
+func (sh *strictHandler) UnfollowRepoMediaSeries(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request UnfollowRepoMediaSeriesRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UnfollowRepoMediaSeries(ctx, request.(UnfollowRepoMediaSeriesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UnfollowRepoMediaSeries")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UnfollowRepoMediaSeriesResponseObject); ok {
+		if err := validResponse.VisitUnfollowRepoMediaSeriesResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// FollowRepoMediaSeries operation middleware
+func (sh *strictHandler) FollowRepoMediaSeries(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request FollowRepoMediaSeriesRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.FollowRepoMediaSeries(ctx, request.(FollowRepoMediaSeriesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "FollowRepoMediaSeries")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(FollowRepoMediaSeriesResponseObject); ok {
+		if err := validResponse.VisitFollowRepoMediaSeriesResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UnhideRepoMedia operation middleware
+func (sh *strictHandler) UnhideRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params UnhideRepoMediaParams) {
+	var request UnhideRepoMediaRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UnhideRepoMedia(ctx, request.(UnhideRepoMediaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UnhideRepoMedia")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UnhideRepoMediaResponseObject); ok {
+		if err := validResponse.VisitUnhideRepoMediaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// HideRepoMedia operation middleware
+func (sh *strictHandler) HideRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params HideRepoMediaParams) {
+	var request HideRepoMediaRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.HideRepoMedia(ctx, request.(HideRepoMediaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "HideRepoMedia")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(HideRepoMediaResponseObject); ok {
+		if err := validResponse.VisitHideRepoMediaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoMediaImages operation middleware
+func (sh *strictHandler) GetRepoMediaImages(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params GetRepoMediaImagesParams) {
+	var request GetRepoMediaImagesRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaImages(ctx, request.(GetRepoMediaImagesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaImages")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaImagesResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaImagesResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetRepoMediaImage operation middleware
+func (sh *strictHandler) GetRepoMediaImage(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, imageId string, params GetRepoMediaImageParams) {
+	var request GetRepoMediaImageRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+	request.ImageId = imageId
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaImage(ctx, request.(GetRepoMediaImageRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaImage")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaImageResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaImageResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// SetRepoMediaPreferredImage operation middleware
+func (sh *strictHandler) SetRepoMediaPreferredImage(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, imageId string) {
+	var request SetRepoMediaPreferredImageRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+	request.ImageId = imageId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SetRepoMediaPreferredImage(ctx, request.(SetRepoMediaPreferredImageRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SetRepoMediaPreferredImage")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SetRepoMediaPreferredImageResponseObject); ok {
+		if err := validResponse.VisitSetRepoMediaPreferredImageResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UnlockRepoMedia operation middleware
+func (sh *strictHandler) UnlockRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request UnlockRepoMediaRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UnlockRepoMedia(ctx, request.(UnlockRepoMediaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UnlockRepoMedia")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UnlockRepoMediaResponseObject); ok {
+		if err := validResponse.VisitUnlockRepoMediaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// LockRepoMedia operation middleware
+func (sh *strictHandler) LockRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request LockRepoMediaRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.LockRepoMedia(ctx, request.(LockRepoMediaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "LockRepoMedia")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(LockRepoMediaResponseObject); ok {
+		if err := validResponse.VisitLockRepoMediaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateRepoMediaMeta operation middleware
+func (sh *strictHandler) UpdateRepoMediaMeta(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request UpdateRepoMediaMetaRequestObject
 
-func (response GetRepos200JSONResponse) VisitGetReposResponse(w http.ResponseWriter, _ *http.Request) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	request.RepoId = repoId
+	request.MediaId = mediaId
 
-	return json.NewEncoder(w).Encode(response)
-}
+	var body UpdateRepoMediaMetaJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
-type GetRepoByIdRequestObject struct {
-	Id string `json:"id"`
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateRepoMediaMeta(ctx, request.(UpdateRepoMediaMetaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateRepoMediaMeta")
+	}
 
-type GetRepoByIdResponseObject interface {
-	VisitGetRepoByIdResponse(w http.ResponseWriter, r *http.Request) error
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateRepoMediaMetaResponseObject); ok {
+		if err := validResponse.VisitUpdateRepoMediaMetaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetRepoById200JSONResponse Repository
+// RefreshRepoMediaMeta operation middleware
+func (sh *strictHandler) RefreshRepoMediaMeta(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request RefreshRepoMediaMetaRequestObject
 
-func (response GetRepoById200JSONResponse) VisitGetRepoByIdResponse(w http.ResponseWriter, _ *http.Request) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	request.RepoId = repoId
+	request.MediaId = mediaId
 
-	return json.NewEncoder(w).Encode(response)
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RefreshRepoMediaMeta(ctx, request.(RefreshRepoMediaMetaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RefreshRepoMediaMeta")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RefreshRepoMediaMetaResponseObject); ok {
+		if err := validResponse.VisitRefreshRepoMediaMetaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetRepoById400JSONResponse Error
+// GetRepoMediaMetaSuggestions operation middleware
+func (sh *strictHandler) GetRepoMediaMetaSuggestions(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request GetRepoMediaMetaSuggestionsRequestObject
 
-func (response GetRepoById400JSONResponse) VisitGetRepoByIdResponse(w http.ResponseWriter, _ *http.Request) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+	request.RepoId = repoId
+	request.MediaId = mediaId
 
-	return json.NewEncoder(w).Encode(response)
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaMetaSuggestions(ctx, request.(GetRepoMediaMetaSuggestionsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaMetaSuggestions")
+	}
 
-type GetRepoMediaRequestObject struct {
-	Id string `json:"id"`
-}
+	response, err := handler(r.Context(), w, r, request)
 
-type GetRepoMediaResponseObject interface {
-	VisitGetRepoMediaResponse(w http.ResponseWriter, r *http.Request) error
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaMetaSuggestionsResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaMetaSuggestionsResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetRepoMedia200JSONResponse []Media
+// GetRepoMediaPreview operation middleware
+func (sh *strictHandler) GetRepoMediaPreview(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request GetRepoMediaPreviewRequestObject
 
-func (response GetRepoMedia200JSONResponse) VisitGetRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	request.RepoId = repoId
+	request.MediaId = mediaId
 
-	return json.NewEncoder(w).Encode(response)
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaPreview(ctx, request.(GetRepoMediaPreviewRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaPreview")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaPreviewResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaPreviewResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetRepoMedia400JSONResponse Error
+// GetRepoMediaProgress operation middleware
+func (sh *strictHandler) GetRepoMediaProgress(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request GetRepoMediaProgressRequestObject
 
-func (response GetRepoMedia400JSONResponse) VisitGetRepoMediaResponse(w http.ResponseWriter, _ *http.Request) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+	request.RepoId = repoId
+	request.MediaId = mediaId
 
-	return json.NewEncoder(w).Encode(response)
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaProgress(ctx, request.(GetRepoMediaProgressRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaProgress")
+	}
 
-type GetRepoMediaByIdRequestObject struct {
-	RepoId  string `json:"repoId"`
-	MediaId string `json:"mediaId"`
-}
+	response, err := handler(r.Context(), w, r, request)
 
-type GetRepoMediaByIdResponseObject interface {
-	VisitGetRepoMediaByIdResponse(w http.ResponseWriter, r *http.Request) error
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaProgressResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaProgressResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetRepoMediaById200JSONResponse Media
+// UpdateRepoMediaProgress operation middleware
+func (sh *strictHandler) UpdateRepoMediaProgress(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request UpdateRepoMediaProgressRequestObject
 
-func (response GetRepoMediaById200JSONResponse) VisitGetRepoMediaByIdResponse(w http.ResponseWriter, _ *http.Request) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	request.RepoId = repoId
+	request.MediaId = mediaId
 
-	return json.NewEncoder(w).Encode(response)
-}
+	var body UpdateRepoMediaProgressJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
-type GetRepoMediaById400JSONResponse Error
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateRepoMediaProgress(ctx, request.(UpdateRepoMediaProgressRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateRepoMediaProgress")
+	}
 
-func (response GetRepoMediaById400JSONResponse) VisitGetRepoMediaByIdResponse(w http.ResponseWriter, _ *http.Request) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+	response, err := handler(r.Context(), w, r, request)
 
-	return json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateRepoMediaProgressResponseObject); ok {
+		if err := validResponse.VisitUpdateRepoMediaProgressResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetRepoMediaDownloadRequestObject struct {
-	RepoId  string `json:"repoId"`
-	MediaId string `json:"mediaId"`
+// RestoreRepoMedia operation middleware
+func (sh *strictHandler) RestoreRepoMedia(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request RestoreRepoMediaRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RestoreRepoMedia(ctx, request.(RestoreRepoMediaRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RestoreRepoMedia")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RestoreRepoMediaResponseObject); ok {
+		if err := validResponse.VisitRestoreRepoMediaResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetRepoMediaDownloadResponseObject interface {
-	VisitGetRepoMediaDownloadResponse(w http.ResponseWriter, r *http.Request) error
+// GetRepoMediaStats operation middleware
+func (sh *strictHandler) GetRepoMediaStats(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request GetRepoMediaStatsRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaStats(ctx, request.(GetRepoMediaStatsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaStats")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaStatsResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaStatsResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetRepoMediaDownload200ResponseHeaders struct {
-	ContentDisposition string
-	ContentType        string
+// GetRepoMediaStoryboard operation middleware
+func (sh *strictHandler) GetRepoMediaStoryboard(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request GetRepoMediaStoryboardRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaStoryboard(ctx, request.(GetRepoMediaStoryboardRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaStoryboard")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaStoryboardResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaStoryboardResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetRepoMediaDownload200SchemaResponse struct {
-	Body          io.Reader
-	Headers       GetRepoMediaDownload200ResponseHeaders
-	ContentLength int64
+// GetRepoMediaStoryboardSheet operation middleware
+func (sh *strictHandler) GetRepoMediaStoryboardSheet(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, sheet int) {
+	var request GetRepoMediaStoryboardSheetRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+	request.Sheet = sheet
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaStoryboardSheet(ctx, request.(GetRepoMediaStoryboardSheetRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaStoryboardSheet")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaStoryboardSheetResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaStoryboardSheetResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-func (response GetRepoMediaDownload200SchemaResponse) VisitGetRepoMediaDownloadResponse(w http.ResponseWriter, _ *http.Request) error {
-	w.Header().Set("Content-Type", "schema")
-	if response.ContentLength != 0 {
-		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+// GetRepoMediaStreams operation middleware
+func (sh *strictHandler) GetRepoMediaStreams(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, params GetRepoMediaStreamsParams) {
+	var request GetRepoMediaStreamsRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaStreams(ctx, request.(GetRepoMediaStreamsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaStreams")
 	}
-	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
-	w.Header().Set("Content-Type", fmt.Sprint(response.Headers.ContentType))
-	w.WriteHeader(200)
 
-	if closer, ok := response.Body.(io.ReadCloser); ok {
-		defer closer.Close()
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaStreamsResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaStreamsResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
 	}
-	_, err := io.Copy(w, response.Body)
-	return err
 }
 
-type GetRepoMediaDownload400JSONResponse Error
+// GetRepoMediaStream operation middleware
+func (sh *strictHandler) GetRepoMediaStream(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, format string, params GetRepoMediaStreamParams) {
+	var request GetRepoMediaStreamRequestObject
 
-func (response GetRepoMediaDownload400JSONResponse) VisitGetRepoMediaDownloadResponse(w http.ResponseWriter, _ *http.Request) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+	request.RepoId = repoId
+	request.MediaId = mediaId
+	request.Format = format
+	request.Params = params
 
-	return json.NewEncoder(w).Encode(response)
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaStream(ctx, request.(GetRepoMediaStreamRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaStream")
+	}
 
-type GetRepoMediaStreamsRequestObject struct {
-	RepoId  string `json:"repoId"`
-	MediaId string `json:"mediaId"`
-}
+	response, err := handler(r.Context(), w, r, request)
 
-type GetRepoMediaStreamsResponseObject interface {
-	VisitGetRepoMediaStreamsResponse(w http.ResponseWriter, r *http.Request) error
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaStreamResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaStreamResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetRepoMediaStreams200JSONResponse []MediaFormat
+// GetRepoMediaSubtitles operation middleware
+func (sh *strictHandler) GetRepoMediaSubtitles(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request GetRepoMediaSubtitlesRequestObject
 
-func (response GetRepoMediaStreams200JSONResponse) VisitGetRepoMediaStreamsResponse(w http.ResponseWriter, _ *http.Request) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	request.RepoId = repoId
+	request.MediaId = mediaId
 
-	return json.NewEncoder(w).Encode(response)
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaSubtitles(ctx, request.(GetRepoMediaSubtitlesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaSubtitles")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaSubtitlesResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaSubtitlesResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetRepoMediaStreams400JSONResponse Error
+// GetRepoMediaSubtitle operation middleware
+func (sh *strictHandler) GetRepoMediaSubtitle(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, trackId string) {
+	var request GetRepoMediaSubtitleRequestObject
 
-func (response GetRepoMediaStreams400JSONResponse) VisitGetRepoMediaStreamsResponse(w http.ResponseWriter, _ *http.Request) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+	request.RepoId = repoId
+	request.MediaId = mediaId
+	request.TrackId = trackId
 
-	return json.NewEncoder(w).Encode(response)
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoMediaSubtitle(ctx, request.(GetRepoMediaSubtitleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoMediaSubtitle")
+	}
 
-type GetRepoMediaStreamRequestObject struct {
-	RepoId  string `json:"repoId"`
-	MediaId string `json:"mediaId"`
-	Format  string `json:"format"`
-}
+	response, err := handler(r.Context(), w, r, request)
 
-type GetRepoMediaStreamResponseObject interface {
-	VisitGetRepoMediaStreamResponse(w http.ResponseWriter, r *http.Request) error
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoMediaSubtitleResponseObject); ok {
+		if err := validResponse.VisitGetRepoMediaSubtitleResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetRepoMediaStream200ResponseHeaders struct {
-	ContentDisposition string
-	ContentType        string
-}
+// UpdateRepoMediaTags operation middleware
+func (sh *strictHandler) UpdateRepoMediaTags(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request UpdateRepoMediaTagsRequestObject
 
-type GetRepoMediaStream200SchemaResponse struct {
-	Body          io.Reader
-	Headers       GetRepoMediaStream200ResponseHeaders
-	ContentLength int64
-}
+	request.RepoId = repoId
+	request.MediaId = mediaId
 
-func (response GetRepoMediaStream200SchemaResponse) VisitGetRepoMediaStreamResponse(w http.ResponseWriter, _ *http.Request) error {
-	w.Header().Set("Content-Type", "schema")
-	if response.ContentLength != 0 {
-		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	var body UpdateRepoMediaTagsJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
 	}
-	w.Header().Set("Content-Disposition", fmt.Sprint(response.Headers.ContentDisposition))
-	w.Header().Set("Content-Type", fmt.Sprint(response.Headers.ContentType))
-	w.WriteHeader(200)
+	request.Body = &body
 
-	if closer, ok := response.Body.(io.ReadCloser); ok {
-		defer closer.Close()
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateRepoMediaTags(ctx, request.(UpdateRepoMediaTagsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateRepoMediaTags")
 	}
-	_, err := io.Copy(w, response.Body)
-	return err
-}
-
-type GetRepoMediaStream400JSONResponse Error
 
-func (response GetRepoMediaStream400JSONResponse) VisitGetRepoMediaStreamResponse(w http.ResponseWriter, _ *http.Request) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+	response, err := handler(r.Context(), w, r, request)
 
-	return json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateRepoMediaTagsResponseObject); ok {
+		if err := validResponse.VisitUpdateRepoMediaTagsResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-// StrictServerInterface represents all server handlers.
-type StrictServerInterface interface {
-	// Lists repositories.
-	// (GET /repos)
-	GetRepos(ctx context.Context, request GetReposRequestObject) (GetReposResponseObject, error)
-	// Gets a repository.
-	// (GET /repos/{id})
-	GetRepoById(ctx context.Context, request GetRepoByIdRequestObject) (GetRepoByIdResponseObject, error)
-	// Lists a repository's media.
-	// (GET /repos/{id}/media)
-	GetRepoMedia(ctx context.Context, request GetRepoMediaRequestObject) (GetRepoMediaResponseObject, error)
-	// Gets a repository's media.
-	// (GET /repos/{repoId}/media/{mediaId})
-	GetRepoMediaById(ctx context.Context, request GetRepoMediaByIdRequestObject) (GetRepoMediaByIdResponseObject, error)
-	// Downloads media.
-	// (GET /repos/{repoId}/media/{mediaId}/download)
-	GetRepoMediaDownload(ctx context.Context, request GetRepoMediaDownloadRequestObject) (GetRepoMediaDownloadResponseObject, error)
-	// Lists the available variants of a repository's media.
-	// (GET /repos/{repoId}/media/{mediaId}/stream)
-	GetRepoMediaStreams(ctx context.Context, request GetRepoMediaStreamsRequestObject) (GetRepoMediaStreamsResponseObject, error)
-	// Gets a HTTP media stream.
-	// (GET /repos/{repoId}/media/{mediaId}/stream/{format})
-	GetRepoMediaStream(ctx context.Context, request GetRepoMediaStreamRequestObject) (GetRepoMediaStreamResponseObject, error)
-}
-type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
-type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+// GetRepoTrash operation middleware
+func (sh *strictHandler) GetRepoTrash(w http.ResponseWriter, r *http.Request, repoId string) {
+	var request GetRepoTrashRequestObject
 
-type StrictHTTPServerOptions struct {
-	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
-	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
-}
+	request.RepoId = repoId
 
-func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
-		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		},
-		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		},
-	}}
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRepoTrash(ctx, request.(GetRepoTrashRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRepoTrash")
+	}
 
-func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
-}
+	response, err := handler(r.Context(), w, r, request)
 
-type strictHandler struct {
-	ssi         StrictServerInterface
-	middlewares []StrictMiddlewareFunc
-	options     StrictHTTPServerOptions
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetRepoTrashResponseObject); ok {
+		if err := validResponse.VisitGetRepoTrashResponse(w, r); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-// GetRepos operation middleware
-func (sh *strictHandler) GetRepos(w http.ResponseWriter, r *http.Request) {
-	var request GetReposRequestObject
+// RestoreRepoTrash operation middleware
+func (sh *strictHandler) RestoreRepoTrash(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
+	var request RestoreRepoTrashRequestObject
+
+	request.RepoId = repoId
+	request.MediaId = mediaId
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.GetRepos(ctx, request.(GetReposRequestObject))
+		return sh.ssi.RestoreRepoTrash(ctx, request.(RestoreRepoTrashRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "GetRepos")
+		handler = middleware(handler, "RestoreRepoTrash")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(GetReposResponseObject); ok {
-		if err := validResponse.VisitGetReposResponse(w, r); err != nil {
+	} else if validResponse, ok := response.(RestoreRepoTrashResponseObject); ok {
+		if err := validResponse.VisitRestoreRepoTrashResponse(w, r); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -739,25 +8322,25 @@ func (sh *strictHandler) GetRepos(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// GetRepoById operation middleware
-func (sh *strictHandler) GetRepoById(w http.ResponseWriter, r *http.Request, id string) {
-	var request GetRepoByIdRequestObject
+// DeleteSessionQuality operation middleware
+func (sh *strictHandler) DeleteSessionQuality(w http.ResponseWriter, r *http.Request, params DeleteSessionQualityParams) {
+	var request DeleteSessionQualityRequestObject
 
-	request.Id = id
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.GetRepoById(ctx, request.(GetRepoByIdRequestObject))
+		return sh.ssi.DeleteSessionQuality(ctx, request.(DeleteSessionQualityRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "GetRepoById")
+		handler = middleware(handler, "DeleteSessionQuality")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(GetRepoByIdResponseObject); ok {
-		if err := validResponse.VisitGetRepoByIdResponse(w, r); err != nil {
+	} else if validResponse, ok := response.(DeleteSessionQualityResponseObject); ok {
+		if err := validResponse.VisitDeleteSessionQualityResponse(w, r); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -765,25 +8348,25 @@ func (sh *strictHandler) GetRepoById(w http.ResponseWriter, r *http.Request, id
 	}
 }
 
-// GetRepoMedia operation middleware
-func (sh *strictHandler) GetRepoMedia(w http.ResponseWriter, r *http.Request, id string) {
-	var request GetRepoMediaRequestObject
+// GetSessionQuality operation middleware
+func (sh *strictHandler) GetSessionQuality(w http.ResponseWriter, r *http.Request, params GetSessionQualityParams) {
+	var request GetSessionQualityRequestObject
 
-	request.Id = id
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.GetRepoMedia(ctx, request.(GetRepoMediaRequestObject))
+		return sh.ssi.GetSessionQuality(ctx, request.(GetSessionQualityRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "GetRepoMedia")
+		handler = middleware(handler, "GetSessionQuality")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(GetRepoMediaResponseObject); ok {
-		if err := validResponse.VisitGetRepoMediaResponse(w, r); err != nil {
+	} else if validResponse, ok := response.(GetSessionQualityResponseObject); ok {
+		if err := validResponse.VisitGetSessionQualityResponse(w, r); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -791,26 +8374,32 @@ func (sh *strictHandler) GetRepoMedia(w http.ResponseWriter, r *http.Request, id
 	}
 }
 
-// GetRepoMediaById operation middleware
-func (sh *strictHandler) GetRepoMediaById(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
-	var request GetRepoMediaByIdRequestObject
+// UpdateSessionQuality operation middleware
+func (sh *strictHandler) UpdateSessionQuality(w http.ResponseWriter, r *http.Request, params UpdateSessionQualityParams) {
+	var request UpdateSessionQualityRequestObject
 
-	request.RepoId = repoId
-	request.MediaId = mediaId
+	request.Params = params
+
+	var body UpdateSessionQualityJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.GetRepoMediaById(ctx, request.(GetRepoMediaByIdRequestObject))
+		return sh.ssi.UpdateSessionQuality(ctx, request.(UpdateSessionQualityRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "GetRepoMediaById")
+		handler = middleware(handler, "UpdateSessionQuality")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(GetRepoMediaByIdResponseObject); ok {
-		if err := validResponse.VisitGetRepoMediaByIdResponse(w, r); err != nil {
+	} else if validResponse, ok := response.(UpdateSessionQualityResponseObject); ok {
+		if err := validResponse.VisitUpdateSessionQualityResponse(w, r); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -818,26 +8407,23 @@ func (sh *strictHandler) GetRepoMediaById(w http.ResponseWriter, r *http.Request
 	}
 }
 
-// GetRepoMediaDownload operation middleware
-func (sh *strictHandler) GetRepoMediaDownload(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
-	var request GetRepoMediaDownloadRequestObject
-
-	request.RepoId = repoId
-	request.MediaId = mediaId
+// GetSystem operation middleware
+func (sh *strictHandler) GetSystem(w http.ResponseWriter, r *http.Request) {
+	var request GetSystemRequestObject
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.GetRepoMediaDownload(ctx, request.(GetRepoMediaDownloadRequestObject))
+		return sh.ssi.GetSystem(ctx, request.(GetSystemRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "GetRepoMediaDownload")
+		handler = middleware(handler, "GetSystem")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(GetRepoMediaDownloadResponseObject); ok {
-		if err := validResponse.VisitGetRepoMediaDownloadResponse(w, r); err != nil {
+	} else if validResponse, ok := response.(GetSystemResponseObject); ok {
+		if err := validResponse.VisitGetSystemResponse(w, r); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -845,26 +8431,25 @@ func (sh *strictHandler) GetRepoMediaDownload(w http.ResponseWriter, r *http.Req
 	}
 }
 
-// GetRepoMediaStreams operation middleware
-func (sh *strictHandler) GetRepoMediaStreams(w http.ResponseWriter, r *http.Request, repoId string, mediaId string) {
-	var request GetRepoMediaStreamsRequestObject
+// GetUsage operation middleware
+func (sh *strictHandler) GetUsage(w http.ResponseWriter, r *http.Request, params GetUsageParams) {
+	var request GetUsageRequestObject
 
-	request.RepoId = repoId
-	request.MediaId = mediaId
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.GetRepoMediaStreams(ctx, request.(GetRepoMediaStreamsRequestObject))
+		return sh.ssi.GetUsage(ctx, request.(GetUsageRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "GetRepoMediaStreams")
+		handler = middleware(handler, "GetUsage")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(GetRepoMediaStreamsResponseObject); ok {
-		if err := validResponse.VisitGetRepoMediaStreamsResponse(w, r); err != nil {
+	} else if validResponse, ok := response.(GetUsageResponseObject); ok {
+		if err := validResponse.VisitGetUsageResponse(w, r); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -872,27 +8457,25 @@ func (sh *strictHandler) GetRepoMediaStreams(w http.ResponseWriter, r *http.Requ
 	}
 }
 
-// GetRepoMediaStream operation middleware
-func (sh *strictHandler) GetRepoMediaStream(w http.ResponseWriter, r *http.Request, repoId string, mediaId string, format string) {
-	var request GetRepoMediaStreamRequestObject
+// ExportUserData operation middleware
+func (sh *strictHandler) ExportUserData(w http.ResponseWriter, r *http.Request, params ExportUserDataParams) {
+	var request ExportUserDataRequestObject
 
-	request.RepoId = repoId
-	request.MediaId = mediaId
-	request.Format = format
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.GetRepoMediaStream(ctx, request.(GetRepoMediaStreamRequestObject))
+		return sh.ssi.ExportUserData(ctx, request.(ExportUserDataRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "GetRepoMediaStream")
+		handler = middleware(handler, "ExportUserData")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(GetRepoMediaStreamResponseObject); ok {
-		if err := validResponse.VisitGetRepoMediaStreamResponse(w, r); err != nil {
+	} else if validResponse, ok := response.(ExportUserDataResponseObject); ok {
+		if err := validResponse.VisitExportUserDataResponse(w, r); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// asset is a static file served from memory, precompressed with gzip for clients that accept it.
+type asset struct {
+	name        string
+	contentType string
+	modTime     time.Time
+
+	identity, gzip []byte
+	etag           string // of the identity encoding, other encodings have a suffix
+}
+
+// newAsset creates a static file handler, the data is compressed right away.
+func newAsset(name, contentType string, data []byte) (*asset, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	return &asset{
+		name:        name,
+		contentType: contentType,
+		modTime:     time.Now(),
+		identity:    data,
+		gzip:        buf.Bytes(),
+		etag:        hex.EncodeToString(sum[:16]),
+	}, nil
+}
+
+func (a *asset) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h := w.Header()
+	h.Set("Content-Type", a.contentType)
+	h.Add("Vary", "Accept-Encoding")
+
+	data, etag := a.identity, a.etag
+	if acceptsEncoding(r.Header.Get("Accept-Encoding"), "gzip") && len(a.gzip) < len(a.identity) {
+		data, etag = a.gzip, a.etag+"-gzip"
+		h.Set("Content-Encoding", "gzip")
+	}
+	h.Set("ETag", strconv.Quote(etag))
+
+	http.ServeContent(w, r, a.name, a.modTime, bytes.NewReader(data))
+}
+
+// acceptsEncoding checks whether an Accept-Encoding header accepts a content coding with a non-zero quality.
+// An explicitly listed coding takes precedence over "*".
+func acceptsEncoding(accept, coding string) bool {
+	wildcard := false
+	for _, part := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+
+		q := 1.0
+		if key, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.EqualFold(strings.TrimSpace(key), "q") {
+			if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = v
+			}
+		}
+
+		switch {
+		case strings.EqualFold(name, coding):
+			return q > 0
+		case name == "*":
+			wildcard = q > 0
+		}
+	}
+
+	return wildcard
+}
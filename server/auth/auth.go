@@ -0,0 +1,302 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/server/api/v1"
+	"go.uber.org/zap"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SessionCookie is the name of the session cookie set on logging in.
+const SessionCookie = "katana_session"
+
+// userKey is the request context key of the authenticated username.
+type userKey struct{}
+
+// User returns the username of the user that authenticated a request from its context.
+// It's empty for requests authenticated with API tokens and when authentication is not enforced.
+func User(ctx context.Context) string {
+	user, _ := ctx.Value(userKey{}).(string)
+	return user
+}
+
+// clientKey is the request context key of the authenticated client ID.
+type clientKey struct{}
+
+// Client returns the ID of the client that authenticated a request from its context, for accounting its transfers.
+// It's the username for users and TokenClient for API tokens, empty when authentication is not enforced.
+func Client(ctx context.Context) string {
+	client, _ := ctx.Value(clientKey{}).(string)
+	return client
+}
+
+// TokenClient returns the client ID of an API token by its hash (HashToken),
+// "token:" followed by the first 12 hexadecimal digits of the hash.
+func TokenClient(hash string) string {
+	return "token:" + strings.ToLower(hash)[:12]
+}
+
+// session is a logged-in user session.
+type session struct {
+	user    string
+	expires time.Time
+}
+
+// Authenticator authenticates requests with static API tokens (Authorization: Bearer <token>)
+// or session cookies of users logged in with a username and password.
+// Feeds, which are fetched by clients that can't send either (calendar apps), are authenticated with feed tokens in the URL.
+type Authenticator struct {
+	tokens     []string                 // token hashes
+	feedTokens []string                 // feed token hashes
+	users      map[string]*passwordHash // username -> password hash
+	dummy      *passwordHash            // verified for unknown users, see newDummyHash
+	throttle   *throttle
+	sessionTTL time.Duration
+	logger     *zap.Logger
+
+	mu       sync.Mutex
+	sessions map[string]*session // session token hash -> session
+}
+
+// NewAuthenticator creates an authenticator from API and feed token hashes (HashToken) and a mapping of usernames to password hashes (HashPassword).
+// Authentication is only enforced if there's at least one API token or user, sessions are kept in memory and expire after sessionTTL.
+func NewAuthenticator(tokens, feedTokens []string, users map[string]string, sessionTTL time.Duration, logger *zap.Logger) (*Authenticator, error) {
+	dummy, err := newDummyHash()
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Authenticator{
+		tokens:     make([]string, 0, len(tokens)),
+		feedTokens: make([]string, 0, len(feedTokens)),
+		users:      make(map[string]*passwordHash, len(users)),
+		dummy:      dummy,
+		throttle:   newThrottle(),
+		sessionTTL: sessionTTL,
+		logger:     logger,
+		sessions:   make(map[string]*session),
+	}
+	for _, token := range tokens {
+		token = strings.ToLower(token)
+		if !validTokenHash(token) {
+			return nil, &ErrInvalidHash{Hash: token}
+		}
+
+		a.tokens = append(a.tokens, token)
+	}
+	for _, token := range feedTokens {
+		token = strings.ToLower(token)
+		if !validTokenHash(token) {
+			return nil, &ErrInvalidHash{Hash: token}
+		}
+
+		a.feedTokens = append(a.feedTokens, token)
+	}
+	for user, hash := range users {
+		ph, err := parsePasswordHash(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		a.users[user] = ph
+	}
+
+	return a, nil
+}
+
+// Enabled checks whether authentication is enforced.
+func (a *Authenticator) Enabled() bool {
+	return a != nil && (len(a.tokens) > 0 || len(a.users) > 0)
+}
+
+// Authenticate checks whether a request carries a valid API token or session cookie,
+// returns the username of the session's user (empty for API tokens).
+func (a *Authenticator) Authenticate(r *http.Request) (string, bool) {
+	user, _, ok := a.authenticate(r)
+	return user, ok
+}
+
+// authenticate is Authenticate, returning the client ID too (see Client).
+func (a *Authenticator) authenticate(r *http.Request) (user, client string, ok bool) {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		hash := HashToken(strings.TrimSpace(token))
+		if matchHash(a.tokens, hash) {
+			return "", TokenClient(hash), true
+		}
+	}
+
+	if c, err := r.Cookie(SessionCookie); err == nil {
+		if s := a.session(c.Value); s != nil {
+			return s.user, s.user, true
+		}
+	}
+
+	return "", "", false
+}
+
+// session returns a session by its token, returns nil if not found or expired.
+func (a *Authenticator) session(token string) *session {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := HashToken(token)
+	s, ok := a.sessions[key]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(s.expires) {
+		delete(a.sessions, key)
+		return nil
+	}
+
+	return s
+}
+
+// Middleware rejects unauthenticated requests with 401 Unauthorized, if authentication is enabled.
+// The username of authenticated users is available to the next handler with User, the client ID with Client.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	if !a.Enabled() {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, client, ok := a.authenticate(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, v1.Error{Type: v1.Unauthorized, Description: "authentication required"})
+			return
+		}
+
+		ctx := context.WithValue(context.WithValue(r.Context(), userKey{}, user), clientKey{}, client)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// FeedMiddleware rejects unauthenticated feed requests with 401 Unauthorized, if authentication is enabled.
+// Feed requests are authenticated with a feed token in the "token" query parameter, or like other requests (Authenticate).
+// Feed tokens don't authenticate any other request, as URLs leak more easily than headers.
+func (a *Authenticator) FeedMiddleware(next http.Handler) http.Handler {
+	if !a.Enabled() {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := r.URL.Query().Get("token"); token != "" {
+			if matchHash(a.feedTokens, HashToken(token)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		a.Middleware(next).ServeHTTP(w, r)
+	})
+}
+
+// loginRequest is the JSON body of a login request.
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// HandleLogin handles logging in with a JSON body of a username and password, a session cookie is set on success.
+// Repeated failed attempts from a client address or for a username are backed off with 429 Too Many Requests (see throttle).
+func (a *Authenticator) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, v1.Error{Type: v1.BadRequest, Description: fmt.Sprintf("failed to decode request: %s", err.Error())})
+		return
+	}
+
+	addr := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+
+	var (
+		now  = time.Now()
+		keys = []string{"addr:" + addr, "user:" + req.Username}
+	)
+	if wait := a.throttle.wait(now, keys...); wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		writeError(w, http.StatusTooManyRequests, v1.Error{Type: v1.Unauthorized, Description: "too many failed login attempts"})
+		return
+	}
+
+	ph, ok := a.users[req.Username]
+	if !ok {
+		ph = a.dummy
+	}
+	if !ph.verify(req.Password) || !ok {
+		a.throttle.fail(now, keys...)
+		if a.logger != nil {
+			a.logger.Warn("failed login attempt", zap.String("user", req.Username), zap.String("addr", r.RemoteAddr))
+		}
+
+		writeError(w, http.StatusUnauthorized, v1.Error{Type: v1.Unauthorized, Description: "invalid username or password"})
+		return
+	}
+	a.throttle.reset(keys[1]) // the address is still backed off, attempts of other users may come from it
+
+	token, err := NewToken()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, v1.Error{Type: v1.InternalError, Description: "failed to generate session token"})
+		return
+	}
+
+	expires := now.Add(a.sessionTTL)
+
+	a.mu.Lock()
+	for key, s := range a.sessions { // prune expired sessions
+		if now.After(s.expires) {
+			delete(a.sessions, key)
+		}
+	}
+	a.sessions[HashToken(token)] = &session{user: req.Username, expires: expires}
+	a.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		Secure:   r.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleLogout handles logging out, the session of the session cookie is invalidated.
+func (a *Authenticator) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	if c, err := r.Cookie(SessionCookie); err == nil {
+		a.mu.Lock()
+		delete(a.sessions, HashToken(c.Value))
+		a.mu.Unlock()
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   r.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, code int, e v1.Error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	_ = json.NewEncoder(w).Encode(e)
+}
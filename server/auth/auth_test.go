@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPasswordHash(t *testing.T) {
+	// RFC 7914, section 11
+	ph, err := parsePasswordHash("pbkdf2-sha256$1$c2FsdA$VawEblbjCJ/sFpHCJUS2BflBhSFt3gRl5oudV8INrLxJypzM8Xm2RZkWZLOdd+8xfHG4RbHjC9UJESBB06GXgw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ph.verify("passwd") {
+		t.Error("expected password to match")
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ph, err := parsePasswordHash(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ph.verify("hunter2") {
+		t.Error("expected password to match")
+	}
+	if ph.verify("hunter3") {
+		t.Error("expected password not to match")
+	}
+}
+
+func TestAuthenticator(t *testing.T) {
+	token, err := NewToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := HashPassword("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewAuthenticator([]string{HashToken(token)}, nil, map[string]string{"user": hash}, time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var user, client string
+	h := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, client = User(r.Context()), Client(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec
+	}
+
+	if rec := serve(httptest.NewRequest(http.MethodGet, "/", nil)); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d without credentials, got %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	if rec := serve(r); rec.Code != http.StatusOK {
+		t.Errorf("expected status %d with token, got %d", http.StatusOK, rec.Code)
+	}
+	if expected := TokenClient(HashToken(token)); client != expected {
+		t.Errorf("expected token client %q, got %q", expected, client)
+	}
+
+	rec := httptest.NewRecorder()
+	a.HandleLogin(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"username":"user","password":"wrong"}`)))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d with wrong password, got %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	a.HandleLogin(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"username":"user","password":"hunter2"}`)))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d on login, got %d", http.StatusNoContent, rec.Code)
+	}
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != SessionCookie {
+		t.Fatalf("expected session cookie, got %v", cookies)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookies[0])
+	if rec := serve(r); rec.Code != http.StatusOK {
+		t.Errorf("expected status %d with session, got %d", http.StatusOK, rec.Code)
+	}
+	if user != "user" {
+		t.Errorf("expected session user %q, got %q", "user", user)
+	}
+	if client != "user" {
+		t.Errorf("expected session client %q, got %q", "user", client)
+	}
+
+	a.HandleLogout(httptest.NewRecorder(), r)
+	if rec := serve(r); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d after logout, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestAuthenticator_Feed(t *testing.T) {
+	token, err := NewToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	feedToken, err := NewToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewAuthenticator([]string{HashToken(token)}, []string{HashToken(feedToken)}, nil, time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	for name, test := range map[string]struct {
+		h        http.Handler
+		url      string
+		expected int
+	}{
+		"feed token":            {a.FeedMiddleware(ok), "/feed.ics?token=" + feedToken, http.StatusOK},
+		"wrong feed token":      {a.FeedMiddleware(ok), "/feed.ics?token=" + token, http.StatusUnauthorized},
+		"no feed token":         {a.FeedMiddleware(ok), "/feed.ics", http.StatusUnauthorized},
+		"feed token in the API": {a.Middleware(ok), "/api?token=" + feedToken, http.StatusUnauthorized},
+	} {
+		rec := httptest.NewRecorder()
+		test.h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.url, nil))
+		if rec.Code != test.expected {
+			t.Errorf("%s: expected status %d, got %d", name, test.expected, rec.Code)
+		}
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	var (
+		th  = newThrottle()
+		now = time.Now()
+	)
+	for i := 0; i < freeAttempts; i++ {
+		th.fail(now, "addr:a", "user:a")
+	}
+	if wait := th.wait(now, "addr:a", "user:b"); wait != 0 {
+		t.Errorf("expected no wait before %d failures, got %s", freeAttempts, wait)
+	}
+
+	th.fail(now, "addr:a", "user:a")
+	th.fail(now, "addr:a", "user:b")
+	if wait := th.wait(now, "addr:b", "user:a"); wait != initialBackoff {
+		t.Errorf("expected user wait %s, got %s", initialBackoff, wait)
+	}
+	if wait := th.wait(now, "addr:a", "user:c"); wait != 2*initialBackoff {
+		t.Errorf("expected address wait %s, got %s", 2*initialBackoff, wait)
+	}
+
+	th.reset("user:a")
+	if wait := th.wait(now, "addr:b", "user:a"); wait != 0 {
+		t.Errorf("expected no wait after reset, got %s", wait)
+	}
+
+	later := now.Add(2 * maxBackoff)
+	th.fail(later, "addr:c")
+	if _, ok := th.failures["addr:a"]; ok {
+		t.Error("expected forgotten failures to be pruned")
+	}
+}
+
+func TestAuthenticator_Throttle(t *testing.T) {
+	hash, err := HashPassword("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewAuthenticator(nil, nil, map[string]string{"user": hash}, time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	login := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		a.HandleLogin(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := login(`{"username":"nobody","password":"hunter2"}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d for an unknown user, got %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	for i := 0; i < freeAttempts; i++ { // the first failure was recorded by the login above
+		a.throttle.fail(time.Now(), "addr:192.0.2.1")
+	}
+	rec := login(`{"username":"user","password":"hunter2"}`)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d from a backed off address, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+	if retry := rec.Header().Get("Retry-After"); retry != "1" {
+		t.Errorf("expected Retry-After of 1 second, got %q", retry)
+	}
+}
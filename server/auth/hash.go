@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"golang.org/x/crypto/pbkdf2"
+	"strconv"
+	"strings"
+)
+
+const (
+	// tokenBytes is the number of random bytes of generated tokens.
+	tokenBytes = 32
+	// passwordScheme is the scheme prefix of password hashes.
+	passwordScheme = "pbkdf2-sha256"
+	// passwordIterations is the PBKDF2 iteration count of new password hashes.
+	passwordIterations = 600_000
+	// passwordSaltBytes is the number of random bytes of password hash salts.
+	passwordSaltBytes = 16
+	// passwordKeyBytes is the length of derived password keys.
+	passwordKeyBytes = 32
+)
+
+// ErrInvalidHash is an error about a malformed token or password hash.
+type ErrInvalidHash struct {
+	// Hash is the offending hash.
+	Hash string
+}
+
+// Error returns the string representation of the error.
+func (eih *ErrInvalidHash) Error() string {
+	return fmt.Sprintf("invalid hash %s", eih.Hash)
+}
+
+// NewToken generates a new random API token.
+func NewToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken makes the hex-encoded SHA-256 hash of a token, used for storing tokens in the configuration.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// matchHash checks whether a hash is one of the hashes, comparing it with each of them in constant time.
+func matchHash(hashes []string, hash string) bool {
+	var match int
+	for _, h := range hashes {
+		match |= subtle.ConstantTimeCompare([]byte(h), []byte(hash))
+	}
+
+	return match == 1
+}
+
+// validTokenHash checks whether a string is a hash made by HashToken.
+func validTokenHash(hash string) bool {
+	b, err := hex.DecodeString(hash)
+	return err == nil && len(b) == sha256.Size
+}
+
+// HashPassword makes a salted PBKDF2-SHA256 hash of a password, in the "pbkdf2-sha256$<iterations>$<salt>$<key>" format.
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := pbkdf2.Key([]byte(password), salt, passwordIterations, passwordKeyBytes, sha256.New)
+	return fmt.Sprintf(
+		"%s$%d$%s$%s",
+		passwordScheme,
+		passwordIterations,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// passwordHash is a parsed password hash.
+type passwordHash struct {
+	iterations int
+	salt, key  []byte
+}
+
+// parsePasswordHash parses a password hash made by HashPassword.
+func parsePasswordHash(hash string) (*passwordHash, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != passwordScheme {
+		return nil, &ErrInvalidHash{Hash: hash}
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return nil, &ErrInvalidHash{Hash: hash}
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, &ErrInvalidHash{Hash: hash}
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return nil, &ErrInvalidHash{Hash: hash}
+	}
+
+	return &passwordHash{iterations: iterations, salt: salt, key: key}, nil
+}
+
+// newDummyHash makes a password hash of a random salt and a zeroed key, matched by no password in practice.
+// It's verified in place of the hashes of unknown users, for their login attempts to take as long as the ones of known users.
+func newDummyHash() (*passwordHash, error) {
+	salt := make([]byte, passwordSaltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return &passwordHash{iterations: passwordIterations, salt: salt, key: make([]byte, passwordKeyBytes)}, nil
+}
+
+// verify checks whether a password matches the hash in constant time.
+func (ph *passwordHash) verify(password string) bool {
+	key := pbkdf2.Key([]byte(password), ph.salt, ph.iterations, len(ph.key), sha256.New)
+	return subtle.ConstantTimeCompare(key, ph.key) == 1
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// freeAttempts is the number of failed login attempts before further ones are delayed.
+	freeAttempts = 3
+	// initialBackoff is the delay after the first delayed failed login attempt, doubled with each following one.
+	initialBackoff = time.Second
+	// maxBackoff is the maximum delay after a failed login attempt, failures are forgotten once it passes without a new one.
+	maxBackoff = time.Minute
+)
+
+// failure is a record of consecutive failed login attempts.
+type failure struct {
+	count int
+	until time.Time // the next attempt is rejected before this time
+	last  time.Time
+}
+
+// throttle backs off login attempts after repeated failures, keyed by the client address and the username.
+type throttle struct {
+	mu       sync.Mutex
+	failures map[string]*failure
+}
+
+// newThrottle creates a new throttle.
+func newThrottle() *throttle {
+	return &throttle{failures: make(map[string]*failure)}
+}
+
+// wait returns how long attempts of the keys are rejected for, zero if they're not.
+func (t *throttle) wait(now time.Time, keys ...string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var wait time.Duration
+	for _, key := range keys {
+		if f, ok := t.failures[key]; ok && f.until.After(now) && f.until.Sub(now) > wait {
+			wait = f.until.Sub(now)
+		}
+	}
+
+	return wait
+}
+
+// fail records a failed attempt of the keys, forgotten failures are pruned.
+func (t *throttle) fail(now time.Time, keys ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, f := range t.failures {
+		if now.Sub(f.last) > maxBackoff {
+			delete(t.failures, key)
+		}
+	}
+
+	for _, key := range keys {
+		f, ok := t.failures[key]
+		if !ok {
+			f = &failure{}
+			t.failures[key] = f
+		}
+
+		f.count++
+		f.last = now
+		if f.count > freeAttempts {
+			backoff := initialBackoff
+			for i := freeAttempts + 1; i < f.count && backoff < maxBackoff; i++ {
+				backoff *= 2
+			}
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+
+			f.until = now.Add(backoff)
+		}
+	}
+}
+
+// reset forgets the failed attempts of the keys.
+func (t *throttle) reset(keys ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, key := range keys {
+		delete(t.failures, key)
+	}
+}
@@ -0,0 +1,16 @@
+package collection
+
+import (
+	"github.com/katana-project/katana/backup"
+	"github.com/katana-project/katana/config"
+)
+
+func init() {
+	backup.Register("collections.json", func(cfg *config.Config) string {
+		if cfg.Collections == nil {
+			return ""
+		}
+
+		return cfg.Collections.Path
+	})
+}
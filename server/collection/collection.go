@@ -0,0 +1,254 @@
+package collection
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ref is a reference to media in a repository.
+type Ref struct {
+	// Repo is the repository ID.
+	Repo string `json:"repo"`
+	// Media is the media ID.
+	Media string `json:"media"`
+}
+
+// Collection is a named, ordered list of media references across repositories, such as a playlist or a franchise.
+type Collection struct {
+	// ID is the collection ID, made from its name when created.
+	ID string `json:"id"`
+	// Name is the display name.
+	Name string `json:"name"`
+	// Description is an optional description, can be empty.
+	Description string `json:"description,omitempty"`
+	// Items are the referenced media in order, references aren't removed along with their media.
+	// Smart collections have no items, their media are selected by their rules.
+	Items []Ref `json:"items"`
+	// Rules are the rules of a smart collection, nil if it's a collection of fixed items.
+	Rules *Rules `json:"rules,omitempty"`
+	// Created is the time of the collection being created.
+	Created time.Time `json:"created"`
+	// Updated is the time of the last change.
+	Updated time.Time `json:"updated"`
+}
+
+// clone returns a deep copy of the collection.
+func (c *Collection) clone() *Collection {
+	c0 := *c
+	c0.Items = slices.Clone(c.Items)
+	c0.Rules = c.Rules.clone()
+
+	return &c0
+}
+
+// Store is a store of collections, persisted to a JSON file on every change.
+type Store struct {
+	path   string // empty if not persisted
+	logger *zap.Logger
+
+	mu          sync.Mutex
+	collections map[string]*Collection // collection ID -> collection
+}
+
+// NewStore creates a collection store persisted to a JSON file, the store is kept only in memory if path is empty.
+func NewStore(path string, logger *zap.Logger) (*Store, error) {
+	s := &Store{
+		logger:      logger,
+		collections: make(map[string]*Collection),
+	}
+	if path != "" {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+
+		s.path = absPath
+		if err := s.load(); err != nil {
+			return nil, errors.Wrap(err, "failed to load collections")
+		}
+	}
+
+	return s, nil
+}
+
+func (s *Store) load() error {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return errors.Wrap(err, "failed to read collections")
+	}
+
+	var collections []*Collection
+	if err := json.Unmarshal(b, &collections); err != nil {
+		return errors.Wrap(err, "failed to unmarshal collections")
+	}
+	for _, c := range collections {
+		if c.Items == nil {
+			c.Items = []Ref{}
+		}
+
+		s.collections[c.ID] = c
+	}
+
+	return nil
+}
+
+// save saves the collections, mu must be held.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	b, err := json.Marshal(maps.Values(s.collections))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal collections")
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return errors.Wrap(err, "failed to write collections")
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return errors.Wrap(err, "failed to replace collections")
+	}
+
+	return nil
+}
+
+// newID makes an unused collection ID from a name, suffixed with a number if it's taken, mu must be held.
+// Example: "Star Wars" -> "star-wars", "star-wars-2"
+func (s *Store) newID(name string) string {
+	base := media.SanitizeID(name)
+	if base == "" {
+		base = "collection"
+	}
+
+	id := base
+	for i := 2; s.collections[id] != nil; i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	return id
+}
+
+// All returns all collections, sorted by name.
+func (s *Store) All() []*Collection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	collections := make([]*Collection, 0, len(s.collections))
+	for _, c := range s.collections {
+		collections = append(collections, c.clone())
+	}
+	slices.SortFunc(collections, func(a, b *Collection) int {
+		if c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return collections
+}
+
+// Get returns a collection by its ID, returns nil if not found.
+func (s *Store) Get(id string) *Collection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.collections[id]; ok {
+		return c.clone()
+	}
+
+	return nil
+}
+
+// Create creates a collection with an ID made from its name and returns it, rules make it a smart collection (can be nil).
+func (s *Store) Create(name, description string, items []Ref, rules *Rules) (*Collection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	c := &Collection{
+		ID:          s.newID(name),
+		Name:        name,
+		Description: description,
+		Items:       append([]Ref{}, items...),
+		Rules:       rules.clone(),
+		Created:     now,
+		Updated:     now,
+	}
+
+	s.collections[c.ID] = c
+	if err := s.save(); err != nil {
+		delete(s.collections, c.ID)
+		return nil, err
+	}
+
+	return c.clone(), nil
+}
+
+// Update replaces the name, description, items and rules of a collection and returns it, returns nil if not found.
+func (s *Store) Update(id, name, description string, items []Ref, rules *Rules) (*Collection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, ok := s.collections[id]
+	if !ok {
+		return nil, nil
+	}
+
+	c := &Collection{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		Items:       append([]Ref{}, items...),
+		Rules:       rules.clone(),
+		Created:     prev.Created,
+		Updated:     time.Now(),
+	}
+
+	s.collections[id] = c
+	if err := s.save(); err != nil {
+		s.collections[id] = prev
+		return nil, err
+	}
+
+	return c.clone(), nil
+}
+
+// Delete deletes a collection, returns false if it wasn't found.
+func (s *Store) Delete(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[id]
+	if !ok {
+		return false, nil
+	}
+
+	delete(s.collections, id)
+	if err := s.save(); err != nil {
+		s.collections[id] = c
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,128 @@
+package collection
+
+import (
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "collections.json")
+	s, err := NewStore(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := []Ref{{Repo: "movies", Media: "a-new-hope"}, {Repo: "movies", Media: "the-empire-strikes-back"}}
+	c, err := s.Create("Star Wars", "", items, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ID != "star-wars" {
+		t.Errorf("expected ID star-wars, got %s", c.ID)
+	}
+
+	c2, err := s.Create("Star Wars", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c2.ID != "star-wars-2" {
+		t.Errorf("expected ID star-wars-2, got %s", c2.ID)
+	}
+
+	if _, err := s.Update(c.ID, "Star Wars", "The original trilogy.", append(items, Ref{Repo: "movies", Media: "return-of-the-jedi"}), nil); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.Delete(c2.ID); err != nil || !ok {
+		t.Fatalf("failed to delete collection: %v", err)
+	}
+
+	s, err = NewStore(path, nil) // reloaded from the file
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all := s.All()
+	if len(all) != 1 {
+		t.Fatalf("expected 1 collection, got %d", len(all))
+	}
+	if c := all[0]; c.Description != "The original trilogy." || len(c.Items) != 3 || c.Items[2].Media != "return-of-the-jedi" {
+		t.Errorf("unexpected collection %+v", c)
+	}
+	if s.Get("star-wars-2") != nil {
+		t.Error("deleted collection found")
+	}
+}
+
+func TestRules_Select(t *testing.T) {
+	movie := func(id, title string, year int, rating float32, genres ...string) media.Media {
+		m := meta.NewMovieOrSeriesMetadata(
+			meta.NewMetadata(meta.TypeMovie, title, title, "", time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), rating, nil),
+			genres, nil, nil, nil,
+		)
+
+		return media.NewMedia(id, "/movies/"+id+".mkv", m, media.FormatMKV)
+	}
+
+	subjects := []*Subject{
+		{Repo: "movies", Media: movie("hereditary", "Hereditary", 2018, 7.3, "Horror", "Drama")},
+		{Repo: "movies", Media: movie("the-shining", "The Shining", 1980, 8.2, "Horror")},
+		{Repo: "movies", Media: movie("the-witch", "The Witch", 2015, 6.9, "Horror"), Tags: []string{"halloween"}},
+		{Repo: "movies", Media: movie("midsommar", "Midsommar", 2019, 7.1, "Horror"), Watched: true},
+		{Repo: "other", Media: media.NewMedia("clip", "/other/Clip.mkv", nil, media.FormatMKV)},
+	}
+
+	tests := []struct {
+		name  string
+		rules Rules
+		ids   []string
+	}{
+		{"all", Rules{}, []string{"clip", "hereditary", "midsommar", "the-shining", "the-witch"}},
+		{
+			"genre, year and unwatched",
+			Rules{Conditions: []Condition{{FieldGenre, OpEq, "horror"}, {FieldYear, OpGt, "2015"}, {FieldWatched, OpEq, "false"}}},
+			[]string{"hereditary"},
+		},
+		{
+			"any",
+			Rules{Any: true, Conditions: []Condition{{FieldTag, OpEq, "halloween"}, {FieldTitle, OpContains, "shin"}}},
+			[]string{"the-shining", "the-witch"},
+		},
+		{"type", Rules{Conditions: []Condition{{FieldType, OpNe, "movie"}}}, []string{"clip"}},
+		{
+			"sorted and limited",
+			Rules{Conditions: []Condition{{FieldRating, OpGte, "7"}}, Sort: SortRating, Descending: true, Limit: 2},
+			[]string{"the-shining", "hereditary"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rules.Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for _, s := range tt.rules.Select(subjects) {
+				ids = append(ids, s.Media.ID())
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.ids, ",") {
+				t.Errorf("expected %v, got %v", tt.ids, ids)
+			}
+		})
+	}
+
+	invalid := []Rules{
+		{Conditions: []Condition{{"size", OpEq, "1"}}},
+		{Conditions: []Condition{{FieldYear, OpContains, "20"}}},
+		{Conditions: []Condition{{FieldYear, OpGt, "recent"}}},
+		{Sort: "size"},
+	}
+	for _, rules := range invalid {
+		if err := rules.Validate(); err == nil {
+			t.Errorf("expected rules %+v to be invalid", rules)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package collection
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"golang.org/x/exp/slices"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field is a media attribute compared by a rule condition.
+type Field string
+
+const (
+	// FieldTitle is the title of media, the file name of media without metadata.
+	FieldTitle Field = "title"
+	// FieldType is the metadata type of media, one of "unknown", "movie", "series" and "episode".
+	FieldType Field = "type"
+	// FieldGenre is a genre of media, episodes are of the genres of their series.
+	FieldGenre Field = "genre"
+	// FieldTag is a user-defined tag of media.
+	FieldTag Field = "tag"
+	// FieldYear is the release year of media.
+	FieldYear Field = "year"
+	// FieldRating is the average vote rating of media, between 0 and 10.
+	FieldRating Field = "rating"
+	// FieldRepo is the ID of the repository of media.
+	FieldRepo Field = "repo"
+	// FieldWatched is whether media has been watched by the evaluating user, "true" or "false".
+	FieldWatched Field = "watched"
+)
+
+// Op is a comparison operator of a rule condition.
+type Op string
+
+const (
+	// OpEq matches attributes equal to the value, case-insensitively, list attributes containing it.
+	OpEq Op = "eq"
+	// OpNe matches attributes not equal to the value, case-insensitively, list attributes not containing it.
+	OpNe Op = "ne"
+	// OpContains matches text attributes containing the value, case-insensitively.
+	OpContains Op = "contains"
+	// OpGt matches numeric attributes greater than the value.
+	OpGt Op = "gt"
+	// OpGte matches numeric attributes greater than or equal to the value.
+	OpGte Op = "gte"
+	// OpLt matches numeric attributes less than the value.
+	OpLt Op = "lt"
+	// OpLte matches numeric attributes less than or equal to the value.
+	OpLte Op = "lte"
+)
+
+// fieldOps are the operators applicable to fields.
+var fieldOps = map[Field][]Op{
+	FieldTitle:   {OpEq, OpNe, OpContains},
+	FieldType:    {OpEq, OpNe},
+	FieldGenre:   {OpEq, OpNe, OpContains},
+	FieldTag:     {OpEq, OpNe},
+	FieldYear:    {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte},
+	FieldRating:  {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte},
+	FieldRepo:    {OpEq, OpNe},
+	FieldWatched: {OpEq, OpNe},
+}
+
+// typeNames are the metadata types by their names in FieldType conditions.
+var typeNames = map[string]meta.Type{
+	"unknown": meta.TypeUnknown,
+	"movie":   meta.TypeMovie,
+	"series":  meta.TypeSeries,
+	"episode": meta.TypeEpisode,
+}
+
+// Condition is a comparison of a media attribute to a value.
+// Example: {Field: FieldYear, Op: OpGt, Value: "2015"}
+type Condition struct {
+	// Field is the compared media attribute.
+	Field Field `json:"field"`
+	// Op is the comparison operator.
+	Op Op `json:"op"`
+	// Value is the compared value, numbers and booleans are in their textual form.
+	Value string `json:"value"`
+}
+
+// SortKey is a media attribute to sort the media of smart collections by.
+type SortKey string
+
+const (
+	// SortTitle sorts media by their title case-insensitively.
+	SortTitle SortKey = "title"
+	// SortReleaseDate sorts media by their release date, media without metadata first.
+	SortReleaseDate SortKey = "release_date"
+	// SortRating sorts media by their vote rating.
+	SortRating SortKey = "rating"
+)
+
+// Rules are the conditions of media in a smart collection, such as
+// genre = horror AND year > 2015 AND watched = false.
+// The media are selected across all repositories whenever the collection is read, so they're always up-to-date.
+type Rules struct {
+	// Any is whether media matching any of the conditions are selected, instead of all of them.
+	Any bool `json:"any,omitempty"`
+	// Conditions are the conditions of selected media, all media are selected if there are none.
+	Conditions []Condition `json:"conditions"`
+	// Sort is the sort key, defaults to SortTitle, media with the same key are sorted by their repository and ID.
+	Sort SortKey `json:"sort,omitempty"`
+	// Descending is whether the sort order is reversed.
+	Descending bool `json:"descending,omitempty"`
+	// Limit is the maximum number of selected media, zero means no limit.
+	Limit int `json:"limit,omitempty"`
+}
+
+// clone returns a deep copy of the rules.
+func (r *Rules) clone() *Rules {
+	if r == nil {
+		return nil
+	}
+
+	r0 := *r
+	r0.Conditions = slices.Clone(r.Conditions)
+
+	return &r0
+}
+
+// Validate checks whether the rules are well-formed, i.e. the operators are applicable to the fields
+// and the values are of the fields' types.
+func (r *Rules) Validate() error {
+	for i, c := range r.Conditions {
+		ops, ok := fieldOps[c.Field]
+		if !ok {
+			return fmt.Errorf("condition %d: unknown field '%s'", i+1, c.Field)
+		}
+		if !slices.Contains(ops, c.Op) {
+			return fmt.Errorf("condition %d: operator '%s' not applicable to field '%s'", i+1, c.Op, c.Field)
+		}
+
+		var err error
+		switch c.Field {
+		case FieldYear:
+			_, err = strconv.Atoi(c.Value)
+		case FieldRating:
+			_, err = strconv.ParseFloat(c.Value, 32)
+		case FieldWatched:
+			_, err = strconv.ParseBool(c.Value)
+		case FieldType:
+			if _, ok := typeNames[strings.ToLower(c.Value)]; !ok {
+				err = fmt.Errorf("unknown type '%s'", c.Value)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("condition %d: invalid value '%s' of field '%s'", i+1, c.Value, c.Field)
+		}
+	}
+
+	switch r.Sort {
+	case "", SortTitle, SortReleaseDate, SortRating:
+	default:
+		return fmt.Errorf("unknown sort key '%s'", r.Sort)
+	}
+	if r.Limit < 0 {
+		return fmt.Errorf("negative limit %d", r.Limit)
+	}
+
+	return nil
+}
+
+// Subject is media evaluated against rules, along with its attributes kept outside of its metadata.
+type Subject struct {
+	// Repo is the repository ID.
+	Repo string
+	// Media is the media.
+	Media media.Media
+	// Tags are the user-defined tags of the media.
+	Tags []string
+	// Watched is whether the media has been watched by the evaluating user.
+	Watched bool
+}
+
+// Select selects, sorts and limits subjects matching the rules, the rules must be valid (see Validate).
+func (r *Rules) Select(subjects []*Subject) []*Subject {
+	selected := make([]*Subject, 0, len(subjects))
+	for _, s := range subjects {
+		if r.Match(s) {
+			selected = append(selected, s)
+		}
+	}
+
+	slices.SortFunc(selected, func(a, b *Subject) int {
+		res := r.compare(a, b)
+		if r.Descending {
+			res = -res
+		}
+		if res == 0 {
+			if res = strings.Compare(a.Repo, b.Repo); res == 0 {
+				res = strings.Compare(a.Media.ID(), b.Media.ID())
+			}
+		}
+
+		return res
+	})
+	if r.Limit > 0 && r.Limit < len(selected) {
+		selected = selected[:r.Limit]
+	}
+
+	return selected
+}
+
+// compare compares subjects by the sort key.
+func (r *Rules) compare(a, b *Subject) int {
+	switch r.Sort {
+	case SortReleaseDate:
+		return releaseDate(a.Media).Compare(releaseDate(b.Media))
+	case SortRating:
+		ra, rb := rating(a.Media), rating(b.Media)
+		switch {
+		case ra < rb:
+			return -1
+		case ra > rb:
+			return 1
+		}
+
+		return 0
+	}
+
+	return strings.Compare(strings.ToLower(title(a.Media)), strings.ToLower(title(b.Media)))
+}
+
+// Match checks whether a subject matches the rules, the rules must be valid (see Validate).
+func (r *Rules) Match(s *Subject) bool {
+	if len(r.Conditions) == 0 {
+		return true
+	}
+
+	for _, c := range r.Conditions {
+		if c.match(s) == r.Any {
+			return r.Any
+		}
+	}
+
+	return !r.Any
+}
+
+// match checks whether a subject matches the condition.
+func (c *Condition) match(s *Subject) bool {
+	m := s.Media.Meta()
+	switch c.Field {
+	case FieldTitle:
+		return c.matchText(title(s.Media))
+	case FieldType:
+		type_ := meta.TypeUnknown
+		if m != nil {
+			type_ = m.Type()
+		}
+
+		return c.matchEqual(type_ == typeNames[strings.ToLower(c.Value)])
+	case FieldGenre:
+		return c.matchList(repo.Genres(m))
+	case FieldTag:
+		return c.matchList(s.Tags)
+	case FieldYear:
+		if m == nil || m.ReleaseDate().IsZero() {
+			return false
+		}
+
+		value, _ := strconv.Atoi(c.Value)
+		return c.matchNumber(float64(m.ReleaseDate().Year()), float64(value))
+	case FieldRating:
+		if m == nil {
+			return false
+		}
+
+		value, _ := strconv.ParseFloat(c.Value, 32)
+		return c.matchNumber(float64(m.VoteRating()), value)
+	case FieldRepo:
+		return c.matchEqual(strings.EqualFold(s.Repo, c.Value))
+	case FieldWatched:
+		value, _ := strconv.ParseBool(c.Value)
+		return c.matchEqual(s.Watched == value)
+	}
+
+	return false
+}
+
+// matchEqual applies an equality operator to the result of an equality check.
+func (c *Condition) matchEqual(equal bool) bool {
+	return equal == (c.Op == OpEq)
+}
+
+// matchText applies the operator to a text attribute.
+func (c *Condition) matchText(s string) bool {
+	if c.Op == OpContains {
+		return strings.Contains(strings.ToLower(s), strings.ToLower(c.Value))
+	}
+
+	return c.matchEqual(strings.EqualFold(s, c.Value))
+}
+
+// matchList applies the operator to a list attribute, such as genres.
+func (c *Condition) matchList(list []string) bool {
+	if c.Op == OpContains {
+		return slices.ContainsFunc(list, func(s string) bool { return strings.Contains(strings.ToLower(s), strings.ToLower(c.Value)) })
+	}
+
+	return c.matchEqual(slices.ContainsFunc(list, func(s string) bool { return strings.EqualFold(s, c.Value) }))
+}
+
+// matchNumber applies the operator to a numeric attribute.
+func (c *Condition) matchNumber(n, value float64) bool {
+	switch c.Op {
+	case OpEq:
+		return n == value
+	case OpNe:
+		return n != value
+	case OpGt:
+		return n > value
+	case OpGte:
+		return n >= value
+	case OpLt:
+		return n < value
+	case OpLte:
+		return n <= value
+	}
+
+	return false
+}
+
+func title(m media.Media) string {
+	if mm := m.Meta(); mm != nil {
+		return mm.Title()
+	}
+
+	name := filepath.Base(m.Path())
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+func rating(m media.Media) float32 {
+	if mm := m.Meta(); mm != nil {
+		return mm.VoteRating()
+	}
+
+	return 0
+}
+
+func releaseDate(m media.Media) time.Time {
+	if mm := m.Meta(); mm != nil {
+		return mm.ReleaseDate()
+	}
+
+	return time.Time{}
+}
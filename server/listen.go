@@ -0,0 +1,55 @@
+package server
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/config"
+	"github.com/katana-project/katana/internal/errors"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// Listen opens the socket of a listener configuration.
+// A Unix domain socket file left behind by an unclean shutdown is replaced, one still in use is an error.
+func Listen(lc *config.Listener) (net.Listener, error) {
+	if lc.Host == "" && lc.Socket == "" {
+		return nil, errors.New("listener has no host or socket")
+	}
+	if lc.Socket == "" {
+		l, err := net.Listen("tcp", lc.Host)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to listen on %s", lc.Host)
+		}
+
+		return l, nil
+	}
+
+	if fi, err := os.Lstat(lc.Socket); err == nil {
+		if fi.Mode().Type() != fs.ModeSocket {
+			return nil, fmt.Errorf("socket path %s is not a socket", lc.Socket)
+		}
+		if conn, err := net.Dial("unix", lc.Socket); err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("socket %s is in use", lc.Socket)
+		}
+		if err := os.Remove(lc.Socket); err != nil {
+			return nil, errors.Wrap(err, "failed to remove stale socket")
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(lc.Socket), 0755); err != nil {
+		return nil, errors.Wrap(err, "failed to make socket directory")
+	}
+
+	l, err := net.Listen("unix", lc.Socket)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to listen on %s", lc.Socket)
+	}
+	if err := os.Chmod(lc.Socket, fs.FileMode(lc.SocketMode)); err != nil {
+		_ = l.Close()
+		return nil, errors.Wrap(err, "failed to change socket mode")
+	}
+
+	return l, nil
+}
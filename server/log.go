@@ -0,0 +1,52 @@
+package server
+
+import (
+	"github.com/go-chi/chi/v5/middleware"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// accessLogFormatter is a middleware.LogFormatter filtering the requests logged by another one.
+type accessLogFormatter struct {
+	middleware.LogFormatter
+
+	errorsOnly bool
+	skipPaths  []string
+}
+
+func (alf *accessLogFormatter) NewLogEntry(r *http.Request) middleware.LogEntry {
+	for _, prefix := range alf.skipPaths {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return nopLogEntry{}
+		}
+	}
+
+	entry := alf.LogFormatter.NewLogEntry(r)
+	if alf.errorsOnly {
+		return &errorLogEntry{LogEntry: entry}
+	}
+
+	return entry
+}
+
+// errorLogEntry is a middleware.LogEntry only writing error responses, panics are always written.
+type errorLogEntry struct {
+	middleware.LogEntry
+}
+
+func (ele *errorLogEntry) Write(status, bytes int, header http.Header, elapsed time.Duration, extra interface{}) {
+	if status >= 400 {
+		ele.LogEntry.Write(status, bytes, header, elapsed, extra)
+	}
+}
+
+// nopLogEntry is a middleware.LogEntry of a request that isn't logged, panics are still printed.
+type nopLogEntry struct{}
+
+func (nopLogEntry) Write(_, _ int, _ http.Header, _ time.Duration, _ interface{}) {
+}
+
+func (nopLogEntry) Panic(v interface{}, _ []byte) {
+	middleware.PrintPrettyStack(v)
+}
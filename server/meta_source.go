@@ -6,10 +6,14 @@ import (
 	"github.com/katana-project/katana/config"
 	"github.com/katana-project/katana/internal/errors"
 	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/katana/repo/media/meta/anilist"
 	"github.com/katana-project/katana/repo/media/meta/tmdb"
+	"github.com/katana-project/katana/repo/mux"
 	tmdbClient "github.com/katana-project/tmdb"
 	"github.com/mitchellh/mapstructure"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"golang.org/x/text/language"
+	"net/http"
 	"reflect"
 	"time"
 )
@@ -17,6 +21,9 @@ import (
 var (
 	// tmdbDefaultCacheExp is the default API response cache expiration.
 	tmdbDefaultCacheExp = imcache.WithExpiration(5 * time.Minute)
+	// anilistDefaultCacheExp is the default API response cache expiration of AniList,
+	// longer than TMDB's, because its rate limit is stricter.
+	anilistDefaultCacheExp = imcache.WithExpiration(30 * time.Minute)
 )
 
 // tmdbSourceOptions are the configuration options of the TMDB metadata source.
@@ -29,10 +36,31 @@ type tmdbSourceOptions struct {
 	Lang string `mapstructure:"lang"`
 	// CacheExp is the API response cache expiration duration in seconds, defaults to 5 minutes (60*5).
 	CacheExp int `mapstructure:"cache_exp"`
+	// RateLimit is the maximum number of API requests per second, defaults to 40, negative disables limiting.
+	RateLimit float64 `mapstructure:"rate_limit"`
+	// MaxRetries is the maximum number of retries of a throttled API request, defaults to 5, negative disables retries.
+	MaxRetries int `mapstructure:"max_retries"`
+	// MaxBackoff is the maximum delay before retrying a throttled API request in seconds, defaults to 1 minute (60).
+	MaxBackoff int `mapstructure:"max_backoff"`
 }
 
-// NewConfiguredMetaSource creates a metadata source from configuration.
-func NewConfiguredMetaSource(name config.MetadataSource, options map[string]interface{}) (meta.Source, error) {
+// anilistSourceOptions are the configuration options of the AniList metadata source.
+type anilistSourceOptions struct {
+	// URL is the URL of the AniList GraphQL API, defaults to "https://graphql.anilist.co".
+	URL string `mapstructure:"url"`
+	// CacheExp is the API response cache expiration duration in seconds, defaults to 30 minutes (60*30).
+	CacheExp int `mapstructure:"cache_exp"`
+}
+
+// tagSourceOptions are the configuration options of the embedded tag metadata source.
+type tagSourceOptions struct {
+	// CoverPath is the directory of extracted cover art, cover art is not extracted if it's empty.
+	CoverPath string `mapstructure:"cover_path"`
+}
+
+// NewConfiguredMetaSource creates a metadata source from configuration,
+// backend is the media conversion backend reading embedded tags, nil if it's not available.
+func NewConfiguredMetaSource(name config.MetadataSource, options map[string]interface{}, backend mux.Backend) (meta.Source, error) {
 	switch name {
 	case "literal":
 		return meta.NewLiteralSource(), nil
@@ -47,7 +75,15 @@ func NewConfiguredMetaSource(name config.MetadataSource, options map[string]inte
 			url = tmdbClient.DefaultServerBaseURL
 		}
 
-		client, err := tmdbClient.NewClientWithResponses(url, tmdbClient.WithToken(parsedOpts.Key))
+		client, err := tmdbClient.NewClientWithResponses(
+			url,
+			tmdbClient.WithToken(parsedOpts.Key),
+			tmdbClient.WithHTTPClient(&http.Client{Transport: tmdb.NewTransport(otelhttp.NewTransport(nil), &tmdb.LimitOptions{
+				RateLimit:  parsedOpts.RateLimit,
+				MaxRetries: parsedOpts.MaxRetries,
+				MaxBackoff: time.Duration(parsedOpts.MaxBackoff) * time.Second,
+			})}),
+		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create tmdb api client")
 		}
@@ -71,6 +107,32 @@ func NewConfiguredMetaSource(name config.MetadataSource, options map[string]inte
 		}
 
 		return tmdb.NewSource(client, lang, cacheExp), nil
+	case "anilist":
+		var parsedOpts anilistSourceOptions
+		if err := mapstructure.WeakDecode(options, &parsedOpts); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode metadata source %s options", name)
+		}
+
+		var (
+			cacheExp     = anilistDefaultCacheExp
+			cacheExpTime = time.Duration(parsedOpts.CacheExp) * time.Second
+		)
+		if cacheExpTime > 0 {
+			cacheExp = imcache.WithExpiration(cacheExpTime)
+		}
+
+		return anilist.NewSource(&http.Client{Transport: otelhttp.NewTransport(nil)}, parsedOpts.URL, cacheExp), nil
+	case "tags":
+		if backend == nil {
+			return nil, fmt.Errorf("metadata source %s needs a media conversion backend", name)
+		}
+
+		var parsedOpts tagSourceOptions
+		if err := mapstructure.WeakDecode(options, &parsedOpts); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode metadata source %s options", name)
+		}
+
+		return mux.NewTagSource(backend, parsedOpts.CoverPath), nil
 	case "analysis":
 		metaSources := make([]meta.Source, 0, len(options))
 		for sourceName, sourceOptions0 := range options {
@@ -82,7 +144,7 @@ func NewConfiguredMetaSource(name config.MetadataSource, options map[string]inte
 				)
 			}
 
-			ms, err := NewConfiguredMetaSource(config.MetadataSource(sourceName), sourceOptions)
+			ms, err := NewConfiguredMetaSource(config.MetadataSource(sourceName), sourceOptions, backend)
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to configure metadata sub-source %s", sourceName)
 			}
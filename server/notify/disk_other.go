@@ -0,0 +1,8 @@
+//go:build !unix
+
+package notify
+
+// freeSpace returns errUnsupported, free space checks are only implemented on Unix-like systems.
+func freeSpace(string) (int64, error) {
+	return 0, errUnsupported
+}
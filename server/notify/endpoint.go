@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"golang.org/x/exp/slices"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Service is a push notification service.
+type Service string
+
+const (
+	// ServiceGotify is the Gotify push service (https://gotify.net).
+	ServiceGotify Service = "gotify"
+	// ServiceNtfy is the ntfy push service (https://ntfy.sh).
+	ServiceNtfy Service = "ntfy"
+)
+
+// Kind is a kind of pushed notification.
+type Kind string
+
+const (
+	// KindMediaAdded is the kind of notifications about media added to a repository, batched per repository.
+	KindMediaAdded Kind = "media_added"
+	// KindScanFailed is the kind of notifications about a repository scan failing.
+	KindScanFailed Kind = "scan_failed"
+	// KindLowDisk is the kind of notifications about a repository's filesystem running low on free space.
+	KindLowDisk Kind = "low_disk"
+)
+
+// Kinds are all kinds of notifications.
+var Kinds = []Kind{KindMediaAdded, KindScanFailed, KindLowDisk}
+
+// Message is a push notification.
+type Message struct {
+	// Kind is the kind of the notification.
+	Kind Kind
+	// Title is the notification title.
+	Title string
+	// Body is the notification text.
+	Body string
+}
+
+// Endpoint is a Gotify or ntfy endpoint receiving notifications.
+type Endpoint struct {
+	// Service is the push service of the endpoint.
+	Service Service
+	// URL is the server URL for Gotify, such as "https://gotify.example.com", or the topic URL for ntfy, such as "https://ntfy.sh/katana".
+	URL string
+	// Token is the Gotify application token or the ntfy access token, can be empty for ntfy.
+	Token string
+	// Priority is the message priority, the service default if zero.
+	Priority int
+	// Kinds are the kinds of notifications pushed to the endpoint, all if empty.
+	Kinds []Kind
+}
+
+// NewEndpoint creates an endpoint, returns an error if the service is unknown or the URL is empty.
+func NewEndpoint(service Service, url, token string, priority int, kinds []Kind) (*Endpoint, error) {
+	if service != ServiceGotify && service != ServiceNtfy {
+		return nil, fmt.Errorf("unknown push service %s", service)
+	}
+	if url == "" {
+		return nil, fmt.Errorf("missing %s endpoint URL", service)
+	}
+	for _, k := range kinds {
+		if !slices.Contains(Kinds, k) {
+			return nil, fmt.Errorf("unknown notification kind %s", k)
+		}
+	}
+
+	return &Endpoint{Service: service, URL: url, Token: token, Priority: priority, Kinds: kinds}, nil
+}
+
+// Accepts checks whether notifications of a kind are pushed to the endpoint.
+func (e *Endpoint) Accepts(k Kind) bool {
+	return len(e.Kinds) == 0 || slices.Contains(e.Kinds, k)
+}
+
+// request makes the HTTP request pushing a message to the endpoint.
+func (e *Endpoint) request(ctx context.Context, msg *Message) (*http.Request, error) {
+	switch e.Service {
+	case ServiceGotify:
+		body := map[string]interface{}{"title": msg.Title, "message": msg.Body}
+		if e.Priority != 0 {
+			body["priority"] = e.Priority
+		}
+
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal message")
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(e.URL, "/")+"/message", bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Gotify-Key", e.Token)
+
+		return req, nil
+	case ServiceNtfy:
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, strings.NewReader(msg.Body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+		req.Header.Set("Title", mime.QEncoding.Encode("utf-8", msg.Title)) // headers are ASCII, ntfy decodes RFC 2047
+		req.Header.Set("Tags", string(msg.Kind))
+		if e.Priority != 0 {
+			req.Header.Set("Priority", strconv.Itoa(e.Priority))
+		}
+		if e.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+e.Token)
+		}
+
+		return req, nil
+	}
+
+	return nil, fmt.Errorf("unknown push service %s", e.Service)
+}
@@ -0,0 +1,292 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/event"
+	"github.com/katana-project/katana/repo/jobs"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// batchInterval is the period between pushing notifications about added media, so that a scan doesn't push one per file.
+	batchInterval = 30 * time.Second
+	// batchTitles is the maximum number of media titles listed in a notification about added media.
+	batchTitles = 10
+	// sendTimeout is the maximum duration of pushing a notification to an endpoint.
+	sendTimeout = 10 * time.Second
+)
+
+// errUnsupported is the error of free space checks on platforms they're not implemented on.
+var errUnsupported = errors.New("free space checks are not supported")
+
+// Notifier pushes notifications about events published to an event bus to Gotify and ntfy endpoints,
+// until the bus is closed. It also checks the free space of repositories' filesystems periodically.
+type Notifier struct {
+	endpoints    []*Endpoint
+	repos        map[string]repo.Repository
+	bus          *event.Bus
+	minFreeSpace int64
+	client       *http.Client
+	logger       *zap.Logger
+
+	added map[string][]string // repository ID -> titles of media added since the last batch
+	low   map[string]bool     // repository ID -> whether it's been reported low on free space
+	done  chan struct{}
+}
+
+// NewNotifier creates a notifier pushing to endpoints and starts it.
+// Repositories are checked for free space below minFreeSpace bytes every diskInterval, the check is disabled if minFreeSpace is zero.
+func NewNotifier(endpoints []*Endpoint, repos []repo.Repository, bus *event.Bus, minFreeSpace int64, diskInterval time.Duration, logger *zap.Logger) *Notifier {
+	reposById := make(map[string]repo.Repository, len(repos))
+	for _, r := range repos {
+		reposById[r.ID()] = r
+	}
+
+	n := &Notifier{
+		endpoints:    endpoints,
+		repos:        reposById,
+		bus:          bus,
+		minFreeSpace: minFreeSpace,
+		client:       &http.Client{Timeout: sendTimeout},
+		logger:       logger,
+		added:        make(map[string][]string),
+		low:          make(map[string]bool),
+		done:         make(chan struct{}),
+	}
+	go n.run(bus.Subscribe(), diskInterval)
+
+	return n
+}
+
+// Done returns a channel that's closed once the notifier stops, after the bus is closed and pending notifications are pushed.
+func (n *Notifier) Done() <-chan struct{} {
+	return n.done
+}
+
+func (n *Notifier) run(sub *event.Subscription, diskInterval time.Duration) {
+	defer close(n.done)
+
+	batch := time.NewTicker(batchInterval)
+	defer batch.Stop()
+
+	var diskC <-chan time.Time
+	if n.minFreeSpace > 0 && n.accepts(KindLowDisk) {
+		disk := time.NewTicker(diskInterval)
+		defer disk.Stop()
+
+		if n.checkDisk() {
+			diskC = disk.C
+		}
+	}
+
+	for {
+		select {
+		case e, ok := <-sub.C:
+			if !ok {
+				if n.bus.Closed() {
+					n.flush()
+					return
+				}
+
+				sub = n.bus.Subscribe() // dropped for lagging behind, events in between are lost
+				continue
+			}
+
+			n.handle(e)
+		case <-batch.C:
+			n.flush()
+		case <-diskC:
+			if !n.checkDisk() {
+				diskC = nil
+			}
+		}
+	}
+}
+
+// handle records or pushes a notification about an event.
+func (n *Notifier) handle(e event.Event) {
+	switch e.Type {
+	case event.TypeMediaAdded:
+		if n.accepts(KindMediaAdded) {
+			n.added[e.RepoID] = append(n.added[e.RepoID], n.title(e.RepoID, e.MediaID))
+		}
+	case event.TypeJobUpdated:
+		if e.JobType == string(jobs.TypeScan) && e.JobState == string(jobs.StateFailed) {
+			n.send(&Message{
+				Kind:  KindScanFailed,
+				Title: "Scan failed",
+				Body:  fmt.Sprintf("Scanning repository %s failed, see the server log for details.", n.repoName(e.RepoID)),
+			})
+		}
+	}
+}
+
+// flush pushes the notifications about media added since the last batch.
+func (n *Notifier) flush() {
+	for repoId, titles := range n.added {
+		slices.Sort(titles)
+
+		var b strings.Builder
+		for i, title := range titles {
+			if i == batchTitles {
+				_, _ = fmt.Fprintf(&b, "\nand %d more", len(titles)-batchTitles)
+				break
+			}
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(title)
+		}
+
+		title := "New media"
+		if len(titles) > 1 {
+			title = fmt.Sprintf("%d new media", len(titles))
+		}
+		n.send(&Message{
+			Kind:  KindMediaAdded,
+			Title: fmt.Sprintf("%s in %s", title, n.repoName(repoId)),
+			Body:  b.String(),
+		})
+	}
+
+	n.added = make(map[string][]string)
+}
+
+// checkDisk pushes notifications about repositories whose filesystems newly have less free space than the minimum,
+// returns false if free space can't be checked on this platform.
+func (n *Notifier) checkDisk() bool {
+	for repoId, r := range n.repos {
+		free, root, err := leastFreeSpace(r.Roots())
+		if err != nil {
+			if errors.Is(err, errUnsupported) {
+				if n.logger != nil {
+					n.logger.Warn("free space checks are not supported on this platform, low disk notifications are disabled")
+				}
+				return false
+			}
+			if n.logger != nil {
+				n.logger.Warn("failed to check free space", zap.String("repo", repoId), zap.Error(err))
+			}
+			continue
+		}
+
+		low := free < n.minFreeSpace
+		if low && !n.low[repoId] {
+			body := fmt.Sprintf("Repository %s has %s of free space left.", n.repoName(repoId), formatBytes(free))
+			if len(r.Roots()) > 1 {
+				body = fmt.Sprintf("Repository %s has %s of free space left in %s.", n.repoName(repoId), formatBytes(free), root)
+			}
+
+			n.send(&Message{Kind: KindLowDisk, Title: "Low disk space", Body: body})
+		}
+		n.low[repoId] = low
+	}
+
+	return true
+}
+
+// leastFreeSpace returns the free space of the filesystem of directories with the least of it, along with the directory.
+func leastFreeSpace(dirs []string) (least int64, leastDir string, _ error) {
+	for i, dir := range dirs {
+		free, err := freeSpace(dir)
+		if err != nil {
+			return 0, "", err
+		}
+		if i == 0 || free < least {
+			least, leastDir = free, dir
+		}
+	}
+
+	return least, leastDir, nil
+}
+
+// send pushes a message to the endpoints accepting its kind, failures are logged.
+func (n *Notifier) send(msg *Message) {
+	for _, e := range n.endpoints {
+		if !e.Accepts(msg.Kind) {
+			continue
+		}
+
+		if err := n.push(e, msg); err != nil && n.logger != nil {
+			n.logger.Error(
+				"failed to push notification",
+				zap.String("service", string(e.Service)),
+				zap.String("kind", string(msg.Kind)),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// push pushes a message to an endpoint.
+func (n *Notifier) push(e *Endpoint, msg *Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	req, err := e.request(ctx, msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to make request")
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096)) // allow reusing the connection
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// accepts checks whether any endpoint accepts notifications of a kind.
+func (n *Notifier) accepts(k Kind) bool {
+	return slices.ContainsFunc(n.endpoints, func(e *Endpoint) bool { return e.Accepts(k) })
+}
+
+// repoName returns the name of a repository, its ID if it's unknown.
+func (n *Notifier) repoName(repoId string) string {
+	if r, ok := n.repos[repoId]; ok {
+		return r.Name()
+	}
+
+	return repoId
+}
+
+// title returns the title of media, its ID if it has no metadata or is gone already.
+func (n *Notifier) title(repoId, mediaId string) string {
+	if r, ok := n.repos[repoId]; ok {
+		if m := r.Get(mediaId); m != nil && m.Meta() != nil {
+			return m.Meta().Title()
+		}
+	}
+
+	return mediaId
+}
+
+// formatBytes formats a byte count with a binary unit, such as "1.5 GiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
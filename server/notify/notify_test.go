@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"encoding/json"
+	"github.com/katana-project/katana/repo/event"
+	"github.com/katana-project/katana/repo/jobs"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// pushed is a notification received by a test server.
+type pushed struct {
+	path, title, body string
+}
+
+func TestNotifier(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []pushed
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+
+		p := pushed{path: r.URL.Path, title: r.Header.Get("Title"), body: string(b)}
+		if r.URL.Path == "/message" { // gotify
+			if r.Header.Get("X-Gotify-Key") != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			var msg struct {
+				Title   string `json:"title"`
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(b, &msg); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			p.title, p.body = msg.Title, msg.Message
+		}
+
+		mu.Lock()
+		received = append(received, p)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	gotify, err := NewEndpoint(ServiceGotify, srv.URL, "token", 0, []Kind{KindScanFailed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ntfy, err := NewEndpoint(ServiceNtfy, srv.URL+"/katana", "", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bus := event.NewBus()
+	n := NewNotifier([]*Endpoint{gotify, ntfy}, nil, bus, 0, 0, nil)
+
+	bus.Publish(event.Event{Type: event.TypeMediaAdded, RepoID: "repo", MediaID: "b"})
+	bus.Publish(event.Event{Type: event.TypeMediaAdded, RepoID: "repo", MediaID: "a"})
+	bus.Publish(event.Event{Type: event.TypeJobUpdated, RepoID: "repo", JobType: string(jobs.TypeScan), JobState: string(jobs.StateRunning)})
+	bus.Publish(event.Event{Type: event.TypeJobUpdated, RepoID: "repo", JobType: string(jobs.TypeScan), JobState: string(jobs.StateFailed)})
+	bus.Close()
+	<-n.Done() // added media are pushed on closing
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(received) != 3 {
+		t.Fatalf("expected 3 notifications, got %+v", received)
+	}
+	for i, p := range []pushed{
+		{path: "/message", title: "Scan failed", body: "Scanning repository repo failed, see the server log for details."},
+		{path: "/katana", title: "Scan failed", body: "Scanning repository repo failed, see the server log for details."},
+		{path: "/katana", title: "2 new media in repo", body: "a\nb"},
+	} {
+		if received[i] != p {
+			t.Errorf("expected notification %+v, got %+v", p, received[i])
+		}
+	}
+}
+
+func TestNewEndpoint(t *testing.T) {
+	if _, err := NewEndpoint("pushover", "https://example.com", "", 0, nil); err == nil {
+		t.Error("expected an error for an unknown service")
+	}
+	if _, err := NewEndpoint(ServiceNtfy, "", "", 0, nil); err == nil {
+		t.Error("expected an error for a missing URL")
+	}
+	if _, err := NewEndpoint(ServiceNtfy, "https://ntfy.sh/katana", "", 0, []Kind{"media_removed"}); err == nil {
+		t.Error("expected an error for an unknown notification kind")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, expected := range map[int64]string{
+		512:                     "512 B",
+		1536:                    "1.5 KiB",
+		10 * 1024 * 1024 * 1024: "10.0 GiB",
+	} {
+		if s := formatBytes(n); s != expected {
+			t.Errorf("expected %s for %d bytes, got %s", expected, n, s)
+		}
+	}
+}
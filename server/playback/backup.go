@@ -0,0 +1,10 @@
+package playback
+
+import (
+	"github.com/katana-project/katana/backup"
+	"github.com/katana-project/katana/config"
+)
+
+func init() {
+	backup.RegisterRepo("playback.json", (*config.Repo).PlaybackPath)
+}
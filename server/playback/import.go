@@ -0,0 +1,406 @@
+package playback
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ImportFormat is a format of watch state exports of media servers and trackers.
+type ImportFormat string
+
+const (
+	// ImportFormatKatana is the JSON user data export of a Katana server (GET /users/me/export).
+	ImportFormatKatana ImportFormat = "katana"
+	// ImportFormatPlex is a JSON library listing of the Plex API (/library/sections/{id}/allLeaves or /all, with Accept: application/json).
+	ImportFormatPlex ImportFormat = "plex"
+	// ImportFormatJellyfin is a JSON item listing of the Jellyfin API (/Users/{id}/Items?Recursive=true&Fields=Path,ProviderIds).
+	ImportFormatJellyfin ImportFormat = "jellyfin"
+	// ImportFormatTrakt is a JSON watch history of the Trakt API (/sync/history), as saved by Trakt export tools.
+	ImportFormatTrakt ImportFormat = "trakt"
+)
+
+// ImportFormats are the supported import formats.
+var ImportFormats = []ImportFormat{ImportFormatKatana, ImportFormatPlex, ImportFormatJellyfin, ImportFormatTrakt}
+
+// ticksPerSecond is the number of Jellyfin ticks (100 ns) in a second.
+const ticksPerSecond = 10_000_000
+
+// ImportEntry is the watch state of media in an export of another server, identified by what it knows of the media.
+// External IDs of the exports aren't kept, local media don't keep the IDs of their metadata.
+type ImportEntry struct {
+	// RepoID and MediaID are the repository and media ID of media exported by a Katana server, empty otherwise.
+	RepoID, MediaID string
+	// File is the base name of the media file, empty if it's not known.
+	File string
+	// Type is the type of the media, movie or episode, meta.TypeUnknown if it's not known.
+	Type meta.Type
+	// Title is the title of a movie or of the series of an episode.
+	Title string
+	// Year is the release year of a movie or of the series of an episode, zero if it's not known.
+	Year int
+	// Season and Episode are the season and episode number of an episode, -1 otherwise.
+	Season, Episode int
+	// Position is the resume position.
+	Position time.Duration
+	// Watched is whether the media has been watched.
+	Watched bool
+	// Updated is the time of the last change, zero if it's not known.
+	Updated time.Time
+}
+
+// String returns a description of the entry for logging, such as "Noragami (2014) S01E02".
+func (ie *ImportEntry) String() string {
+	s := ie.Title
+	if ie.Year > 0 {
+		s = fmt.Sprintf("%s (%d)", s, ie.Year)
+	}
+	if ie.Season >= 0 && ie.Episode >= 0 {
+		s = fmt.Sprintf("%s S%02dE%02d", s, ie.Season, ie.Episode)
+	}
+	if s == "" {
+		s = ie.File
+	}
+
+	return s
+}
+
+// ParseImport parses the watch state entries of an export in a format, entries of media that have never been played are left out.
+func ParseImport(r io.Reader, format ImportFormat) ([]*ImportEntry, error) {
+	var (
+		entries []*ImportEntry
+		err     error
+	)
+	switch format {
+	case ImportFormatKatana:
+		entries, err = parseKatana(r)
+	case ImportFormatPlex:
+		entries, err = parsePlex(r)
+	case ImportFormatJellyfin:
+		entries, err = parseJellyfin(r)
+	case ImportFormatTrakt:
+		entries, err = parseTrakt(r)
+	default:
+		return nil, fmt.Errorf("unknown import format '%s'", format)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s export", format)
+	}
+
+	return entries, nil
+}
+
+func parseKatana(r io.Reader) ([]*ImportEntry, error) {
+	var export struct {
+		Progress []struct {
+			RepoID    string     `json:"repo_id"`
+			MediaID   string     `json:"media_id"`
+			Title     string     `json:"title"`
+			Type      string     `json:"type"`
+			Year      int        `json:"year"`
+			Position  float64    `json:"position"`
+			Watched   bool       `json:"watched"`
+			UpdatedAt *time.Time `json:"updated_at"`
+		} `json:"progress"`
+	}
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, err
+	}
+
+	var entries []*ImportEntry
+	for _, p := range export.Progress {
+		if p.UpdatedAt == nil { // hidden, but never played
+			continue
+		}
+
+		type_ := meta.TypeUnknown
+		switch p.Type {
+		case "movie":
+			type_ = meta.TypeMovie
+		case "episode":
+			type_ = meta.TypeEpisode
+		}
+
+		// episodes are titled by their own title, they're only matched by their ID
+		entries = append(entries, &ImportEntry{
+			RepoID:   p.RepoID,
+			MediaID:  p.MediaID,
+			Type:     type_,
+			Title:    p.Title,
+			Year:     p.Year,
+			Season:   -1,
+			Episode:  -1,
+			Position: time.Duration(p.Position * float64(time.Second)),
+			Watched:  p.Watched,
+			Updated:  *p.UpdatedAt,
+		})
+	}
+
+	return entries, nil
+}
+
+func parsePlex(r io.Reader) ([]*ImportEntry, error) {
+	var listing struct {
+		MediaContainer struct {
+			Metadata []struct {
+				Type             string `json:"type"` // movie, episode
+				Title            string `json:"title"`
+				GrandparentTitle string `json:"grandparentTitle"` // the series of an episode
+				Year             int    `json:"year"`
+				ParentIndex      *int   `json:"parentIndex"` // season
+				Index            *int   `json:"index"`       // episode
+				ViewCount        int    `json:"viewCount"`
+				ViewOffset       int64  `json:"viewOffset"`   // ms
+				LastViewedAt     int64  `json:"lastViewedAt"` // Unix time
+				Media            []struct {
+					Part []struct {
+						File string `json:"file"`
+					} `json:"Part"`
+				} `json:"Media"`
+			} `json:"Metadata"`
+		} `json:"MediaContainer"`
+	}
+	if err := json.NewDecoder(r).Decode(&listing); err != nil {
+		return nil, err
+	}
+
+	var entries []*ImportEntry
+	for _, m := range listing.MediaContainer.Metadata {
+		if m.ViewCount == 0 && m.ViewOffset == 0 {
+			continue
+		}
+
+		entry := &ImportEntry{
+			Title:    m.Title,
+			Year:     m.Year,
+			Season:   -1,
+			Episode:  -1,
+			Position: time.Duration(m.ViewOffset) * time.Millisecond,
+			Watched:  m.ViewCount > 0,
+		}
+		if m.LastViewedAt > 0 {
+			entry.Updated = time.Unix(m.LastViewedAt, 0).UTC()
+		}
+		if len(m.Media) > 0 && len(m.Media[0].Part) > 0 {
+			entry.File = fileName(m.Media[0].Part[0].File)
+		}
+
+		switch m.Type {
+		case "movie":
+			entry.Type = meta.TypeMovie
+		case "episode":
+			entry.Type = meta.TypeEpisode
+			entry.Title, entry.Year = m.GrandparentTitle, 0 // the year is the episode's
+			if m.ParentIndex != nil && m.Index != nil {
+				entry.Season, entry.Episode = *m.ParentIndex, *m.Index
+			}
+		default:
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func parseJellyfin(r io.Reader) ([]*ImportEntry, error) {
+	var listing struct {
+		Items []struct {
+			Type              string `json:"Type"` // Movie, Episode
+			Name              string `json:"Name"`
+			SeriesName        string `json:"SeriesName"`
+			ProductionYear    int    `json:"ProductionYear"`
+			ParentIndexNumber *int   `json:"ParentIndexNumber"` // season
+			IndexNumber       *int   `json:"IndexNumber"`       // episode
+			Path              string `json:"Path"`
+			UserData          struct {
+				Played                bool       `json:"Played"`
+				PlaybackPositionTicks int64      `json:"PlaybackPositionTicks"`
+				LastPlayedDate        *time.Time `json:"LastPlayedDate"`
+			} `json:"UserData"`
+		} `json:"Items"`
+	}
+	if err := json.NewDecoder(r).Decode(&listing); err != nil {
+		return nil, err
+	}
+
+	var entries []*ImportEntry
+	for _, item := range listing.Items {
+		if !item.UserData.Played && item.UserData.PlaybackPositionTicks == 0 {
+			continue
+		}
+
+		entry := &ImportEntry{
+			File:     fileName(item.Path),
+			Title:    item.Name,
+			Year:     item.ProductionYear,
+			Season:   -1,
+			Episode:  -1,
+			Position: time.Duration(item.UserData.PlaybackPositionTicks) * (time.Second / ticksPerSecond),
+			Watched:  item.UserData.Played,
+		}
+		if item.UserData.LastPlayedDate != nil {
+			entry.Updated = item.UserData.LastPlayedDate.UTC()
+		}
+
+		switch item.Type {
+		case "Movie":
+			entry.Type = meta.TypeMovie
+		case "Episode":
+			entry.Type = meta.TypeEpisode
+			entry.Title, entry.Year = item.SeriesName, 0 // the year is the episode's
+			if item.ParentIndexNumber != nil && item.IndexNumber != nil {
+				entry.Season, entry.Episode = *item.ParentIndexNumber, *item.IndexNumber
+			}
+		default:
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func parseTrakt(r io.Reader) ([]*ImportEntry, error) {
+	type titled struct {
+		Title string `json:"title"`
+		Year  int    `json:"year"`
+	}
+
+	var history []struct {
+		WatchedAt time.Time `json:"watched_at"`
+		Type      string    `json:"type"` // movie, episode
+		Movie     *titled   `json:"movie"`
+		Show      *titled   `json:"show"`
+		Episode   *struct {
+			Season int `json:"season"`
+			Number int `json:"number"`
+		} `json:"episode"`
+	}
+	if err := json.NewDecoder(r).Decode(&history); err != nil {
+		return nil, err
+	}
+
+	var entries []*ImportEntry
+	for _, h := range history {
+		entry := &ImportEntry{Season: -1, Episode: -1, Watched: true, Updated: h.WatchedAt.UTC()}
+		switch {
+		case h.Type == "movie" && h.Movie != nil:
+			entry.Type, entry.Title, entry.Year = meta.TypeMovie, h.Movie.Title, h.Movie.Year
+		case h.Type == "episode" && h.Show != nil && h.Episode != nil:
+			entry.Type, entry.Title, entry.Year = meta.TypeEpisode, h.Show.Title, h.Show.Year
+			entry.Season, entry.Episode = h.Episode.Season, h.Episode.Number
+		default:
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// fileName returns the base name of a file path of another server, which may be of another OS.
+func fileName(p string) string {
+	if p == "" {
+		return ""
+	}
+
+	return path.Base(strings.ReplaceAll(p, "\\", "/"))
+}
+
+// MatchImport matches import entries to the media of a repository, returns the entries by the ID of their media
+// and the entries that didn't match any media. Entries are matched by their ID if they're of the same repository,
+// then by their file name, then by their title, release year and episode number.
+// The most recently updated entry of media matched by several is kept.
+func MatchImport(entries []*ImportEntry, repoId string, items []media.Media) (map[string]*ImportEntry, []*ImportEntry) {
+	var (
+		byId    = make(map[string]media.Media, len(items))
+		byFile  = make(map[string]media.Media, len(items))
+		byTitle = make(map[string][]media.Media)
+	)
+	for _, m := range items {
+		byId[m.ID()] = m
+		byFile[strings.ToLower(path.Base(strings.ReplaceAll(m.Path(), "\\", "/")))] = m
+
+		if mm := m.Meta(); mm != nil {
+			title := mm.Title()
+			if em, ok := mm.(meta.EpisodeMetadata); ok && em.Series() != nil {
+				title = em.Series().Title()
+			}
+
+			key := normalizeTitle(title)
+			byTitle[key] = append(byTitle[key], m)
+		}
+	}
+
+	var (
+		matched   = make(map[string]*ImportEntry)
+		unmatched []*ImportEntry
+	)
+	for _, entry := range entries {
+		var m media.Media
+		if entry.MediaID != "" && entry.RepoID == repoId {
+			m = byId[entry.MediaID]
+		}
+		if m == nil && entry.File != "" {
+			m = byFile[strings.ToLower(entry.File)]
+		}
+		if m == nil && entry.Title != "" && (entry.Type != meta.TypeEpisode || entry.Season >= 0) {
+			for _, candidate := range byTitle[normalizeTitle(entry.Title)] {
+				if entry.matches(candidate.Meta()) {
+					m = candidate
+					break
+				}
+			}
+		}
+
+		if m == nil {
+			unmatched = append(unmatched, entry)
+			continue
+		}
+		if prev, ok := matched[m.ID()]; !ok || entry.Updated.After(prev.Updated) {
+			matched[m.ID()] = entry
+		}
+	}
+
+	return matched, unmatched
+}
+
+// matches checks whether metadata with the entry's title is of the entry's media.
+func (ie *ImportEntry) matches(m meta.Metadata) bool {
+	releaseDate := m.ReleaseDate()
+	if em, ok := m.(meta.EpisodeMetadata); ok {
+		if ie.Type != meta.TypeEpisode || em.Season() != ie.Season || em.Episode() != ie.Episode {
+			return false
+		}
+		if em.Series() != nil {
+			releaseDate = em.Series().ReleaseDate()
+		}
+	} else if ie.Type == meta.TypeEpisode || ie.Type != meta.TypeUnknown && ie.Type != m.Type() {
+		return false
+	}
+
+	return ie.Year == 0 || releaseDate.IsZero() || releaseDate.Year() == ie.Year
+}
+
+// normalizeTitle normalizes a title for comparison, it's lowercased and stripped of everything except letters and digits.
+// Example: "Bocchi the Rock!" -> "bocchitherock"
+func normalizeTitle(title string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, title)
+}
@@ -0,0 +1,106 @@
+package playback
+
+import (
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchImport(t *testing.T) {
+	var (
+		series = meta.NewMovieOrSeriesMetadata(
+			meta.NewMetadata(meta.TypeSeries, "Bocchi the Rock!", "", "", time.Date(2022, 10, 9, 0, 0, 0, 0, time.UTC), 0, nil),
+			nil, nil, nil, nil,
+		)
+		items = []media.Media{
+			media.NewMedia("movie", "movies/Spirited Away (2001).mkv", meta.NewMetadata(meta.TypeMovie, "Spirited Away", "", "", time.Date(2001, 7, 20, 0, 0, 0, 0, time.UTC), 0, nil), nil),
+			media.NewMedia("episode", "series/bocchi/S01E02.mkv", meta.NewEpisodeMetadata(meta.NewMetadata(meta.TypeEpisode, "Episode 2", "", "", time.Time{}, 0, nil), series, 1, 2), nil),
+			media.NewMedia("file", "other/home_video.mp4", nil, nil),
+		}
+	)
+
+	tests := []struct {
+		name, format, export string
+		matched              map[string]time.Duration // media ID -> position
+		unmatched            int
+	}{
+		{
+			name:   "plex",
+			format: string(ImportFormatPlex),
+			export: `{"MediaContainer": {"Metadata": [
+				{"type": "movie", "title": "Spirited Away", "year": 2001, "viewCount": 1},
+				{"type": "episode", "title": "Episode 2", "grandparentTitle": "Bocchi the Rock", "year": 2022, "parentIndex": 1, "index": 2, "viewOffset": 60000},
+				{"type": "movie", "title": "Unplayed", "year": 2001}
+			]}}`,
+			matched: map[string]time.Duration{"movie": 0, "episode": time.Minute},
+		},
+		{
+			name:   "jellyfin",
+			format: string(ImportFormatJellyfin),
+			export: `{"Items": [
+				{"Type": "Movie", "Name": "Home video", "Path": "C:\\Videos\\HOME_VIDEO.mp4", "UserData": {"PlaybackPositionTicks": 300000000}},
+				{"Type": "Movie", "Name": "Spirited Away", "ProductionYear": 1999, "UserData": {"Played": true}}
+			]}`,
+			matched:   map[string]time.Duration{"file": 30 * time.Second},
+			unmatched: 1, // year mismatch
+		},
+		{
+			name:   "trakt",
+			format: string(ImportFormatTrakt),
+			export: `[
+				{"watched_at": "2023-01-01T00:00:00.000Z", "type": "episode", "show": {"title": "Bocchi the Rock!", "year": 2022}, "episode": {"season": 1, "number": 2}},
+				{"watched_at": "2023-01-02T00:00:00.000Z", "type": "episode", "show": {"title": "Bocchi the Rock!", "year": 2022}, "episode": {"season": 1, "number": 3}}
+			]`,
+			matched:   map[string]time.Duration{"episode": 0},
+			unmatched: 1,
+		},
+		{
+			name:   "katana",
+			format: string(ImportFormatKatana),
+			export: `{"progress": [
+				{"repo_id": "test", "media_id": "episode", "title": "Episode 2", "type": "episode", "position": 12.5, "watched": false, "updated_at": "2023-01-01T00:00:00Z", "hidden": false},
+				{"repo_id": "test", "media_id": "hidden", "position": 0, "watched": false, "hidden": true}
+			]}`,
+			matched: map[string]time.Duration{"episode": 12500 * time.Millisecond},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ParseImport(strings.NewReader(tt.export), ImportFormat(tt.format))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			matched, unmatched := MatchImport(entries, "test", items)
+			if len(matched) != len(tt.matched) || len(unmatched) != tt.unmatched {
+				t.Fatalf("expected %d matched and %d unmatched entries, got %v and %v", len(tt.matched), tt.unmatched, matched, unmatched)
+			}
+			for id, position := range tt.matched {
+				if entry, ok := matched[id]; !ok || entry.Position != position {
+					t.Errorf("expected media %s to match at %s, got %+v", id, position, entry)
+				}
+			}
+		})
+	}
+}
+
+func TestStore_Import(t *testing.T) {
+	s, err := NewStore(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	s.Set("repo", "user", "played", time.Minute, false)
+	if s.Import("repo", "user", "played", time.Hour, true, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("expected an older import to be skipped")
+	}
+	if !s.Import("repo", "user", "unplayed", time.Hour, true, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("expected an import of unplayed media")
+	}
+	if state := s.Get("repo", "user", "unplayed"); !state.Watched || state.Updated.Year() != 2020 {
+		t.Errorf("expected the imported state, got %+v", state)
+	}
+}
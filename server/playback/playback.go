@@ -0,0 +1,440 @@
+package playback
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/katana-project/katana/internal/errors"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// flushInterval is the period between saving changed playback states.
+const flushInterval = time.Minute
+
+// State is the playback state of media for a user.
+type State struct {
+	// Position is the resume position.
+	Position time.Duration
+	// Watched is whether the user has finished watching the media.
+	Watched bool
+	// Updated is the time of the last change, zero if the media hasn't been played.
+	Updated time.Time
+	// Hidden is whether the user has hidden the media from their listings.
+	Hidden bool
+}
+
+// key is a key of the playback state of media for a user.
+type key struct {
+	user, media string
+}
+
+// seriesState is the state of a series for a user, set by the user.
+type seriesState struct {
+	hidden, followed bool
+}
+
+// record is a JSON-serializable playback state entry, or a series entry if Series is set.
+type record struct {
+	User     string    `json:"user"`
+	Media    string    `json:"media,omitempty"`
+	Series   string    `json:"series,omitempty"`
+	Position int64     `json:"position_ms"`
+	Watched  bool      `json:"watched"`
+	Updated  time.Time `json:"updated"`
+	Hidden   bool      `json:"hidden,omitempty"`
+	Followed bool      `json:"followed,omitempty"`
+}
+
+// repoStates is the playback state of a repository's media.
+type repoStates struct {
+	path   string // empty if not persisted
+	states map[key]State
+	series map[key]seriesState // hidden or followed series, the media of keys are series keys
+	dirty  bool
+}
+
+// newRepoStates creates empty playback states of a repository.
+func newRepoStates() *repoStates {
+	return &repoStates{states: make(map[key]State), series: make(map[key]seriesState)}
+}
+
+// Store is a store of per-user playback states of media, persisted to a JSON file per repository.
+type Store struct {
+	logger *zap.Logger
+
+	mu    sync.Mutex
+	repos map[string]*repoStates // repository ID -> states
+
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+// NewStore creates a playback state store from a mapping of repository IDs to their state file paths,
+// saved periodically and on Close. States of repositories with an empty path or not in the mapping are only kept in memory.
+func NewStore(paths map[string]string, logger *zap.Logger) (*Store, error) {
+	s := &Store{
+		logger: logger,
+		repos:  make(map[string]*repoStates, len(paths)),
+	}
+	for repoId, path := range paths {
+		rs := newRepoStates()
+		if path != "" {
+			absPath, err := filepath.Abs(path)
+			if err != nil {
+				return nil, err
+			}
+
+			rs.path = absPath
+			if err := s.load(rs); err != nil {
+				return nil, errors.Wrapf(err, "failed to load playback states of repository %s", repoId)
+			}
+		}
+
+		s.repos[repoId] = rs
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stop, s.done = cancel, make(chan struct{})
+	go s.flusher(ctx)
+
+	return s, nil
+}
+
+// Get returns the playback state of media for a user, a zero value if the media hasn't been played.
+func (s *Store) Get(repoId, user, mediaId string) State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rs, ok := s.repos[repoId]; ok {
+		return rs.states[key{user: user, media: mediaId}]
+	}
+
+	return State{}
+}
+
+// States returns the playback states of a repository's media for a user, keyed by media ID,
+// media that haven't been played or hidden are missing.
+func (s *Store) States(repoId, user string) map[string]State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make(map[string]State)
+	if rs, ok := s.repos[repoId]; ok {
+		for k, state := range rs.states {
+			if k.user == user {
+				states[k.media] = state
+			}
+		}
+	}
+
+	return states
+}
+
+// Set replaces the playback position and watched flag of media for a user and returns the state.
+func (s *Store) Set(repoId, user, mediaId string, position time.Duration, watched bool) State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rs := s.repo(repoId)
+	k := key{user: user, media: mediaId}
+
+	state := State{Position: position, Watched: watched, Updated: time.Now(), Hidden: rs.states[k].Hidden}
+	rs.states[k] = state
+	rs.dirty = true
+
+	return state
+}
+
+// Import sets the playback position and watched flag of media for a user from another server, keeping the time of the change,
+// unless the media has been played since then. Imported states without a time are only set if the media hasn't been played.
+// Returns whether the state was set.
+func (s *Store) Import(repoId, user, mediaId string, position time.Duration, watched bool, updated time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rs := s.repo(repoId)
+	k := key{user: user, media: mediaId}
+
+	prev := rs.states[k]
+	if !prev.Updated.IsZero() && !updated.After(prev.Updated) {
+		return false
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	rs.states[k] = State{Position: position, Watched: watched, Updated: updated, Hidden: prev.Hidden}
+	rs.dirty = true
+
+	return true
+}
+
+// SetHidden hides media from a user's listings or shows it again.
+func (s *Store) SetHidden(repoId, user, mediaId string, hidden bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rs := s.repo(repoId)
+	k := key{user: user, media: mediaId}
+
+	state := rs.states[k]
+	state.Hidden = hidden
+	if state == (State{}) {
+		delete(rs.states, k)
+	} else {
+		rs.states[k] = state
+	}
+	rs.dirty = true
+}
+
+// SetSeriesHidden hides a whole series from a user's listings or shows it again.
+// The series is identified by a key made by the caller, such as from its title.
+func (s *Store) SetSeriesHidden(repoId, user, series string, hidden bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rs := s.repo(repoId)
+	k := key{user: user, media: series}
+
+	state := rs.series[k]
+	state.hidden = hidden
+	rs.setSeries(k, state)
+}
+
+// SetSeriesFollowed follows a series for a user or stops following it, followers are notified of its new episodes.
+// The series is identified by a key made by the caller, like in SetSeriesHidden.
+func (s *Store) SetSeriesFollowed(repoId, user, series string, followed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rs := s.repo(repoId)
+	k := key{user: user, media: series}
+
+	state := rs.series[k]
+	state.followed = followed
+	rs.setSeries(k, state)
+}
+
+// HiddenSeries returns the keys of series hidden by a user in a repository, see SetSeriesHidden.
+func (s *Store) HiddenSeries(repoId, user string) map[string]struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series := make(map[string]struct{})
+	if rs, ok := s.repos[repoId]; ok {
+		for k, state := range rs.series {
+			if k.user == user && state.hidden {
+				series[k.media] = struct{}{}
+			}
+		}
+	}
+
+	return series
+}
+
+// FollowedSeries returns the keys of series followed by a user in a repository, see SetSeriesFollowed.
+func (s *Store) FollowedSeries(repoId, user string) map[string]struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series := make(map[string]struct{})
+	if rs, ok := s.repos[repoId]; ok {
+		for k, state := range rs.series {
+			if k.user == user && state.followed {
+				series[k.media] = struct{}{}
+			}
+		}
+	}
+
+	return series
+}
+
+// Followers returns the users following a series in a repository, sorted.
+func (s *Store) Followers(repoId, series string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var users []string
+	if rs, ok := s.repos[repoId]; ok {
+		for k, state := range rs.series {
+			if k.media == series && state.followed {
+				users = append(users, k.user)
+			}
+		}
+	}
+
+	slices.Sort(users)
+	return users
+}
+
+// setSeries replaces the state of a series for a user, the entry is removed if the state is empty.
+func (rs *repoStates) setSeries(k key, state seriesState) {
+	if state == (seriesState{}) {
+		delete(rs.series, k)
+	} else {
+		rs.series[k] = state
+	}
+	rs.dirty = true
+}
+
+// RenameMedia moves the playback states of a repository's media to new IDs, such as after migrating media IDs.
+// ids is a mapping of old media IDs to new ones, states of media not in it are kept as-is.
+func (s *Store) RenameMedia(repoId string, ids map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rs := s.repo(repoId)
+	states := make(map[key]State, len(rs.states))
+	for k, state := range rs.states {
+		if id, ok := ids[k.media]; ok {
+			k.media = id
+			rs.dirty = true
+		}
+
+		states[k] = state
+	}
+	rs.states = states
+}
+
+// repo returns the playback states of a repository, made if there are none, mu must be held.
+func (s *Store) repo(repoId string) *repoStates {
+	rs, ok := s.repos[repoId]
+	if !ok {
+		rs = newRepoStates()
+		s.repos[repoId] = rs
+	}
+
+	return rs
+}
+
+func (s *Store) load(rs *repoStates) error {
+	b, err := os.ReadFile(rs.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return errors.Wrap(err, "failed to read playback states")
+	}
+
+	var records []record
+	if err := json.Unmarshal(b, &records); err != nil {
+		return errors.Wrap(err, "failed to unmarshal playback states")
+	}
+
+	for _, r := range records {
+		if r.Series != "" {
+			rs.series[key{user: r.User, media: r.Series}] = seriesState{hidden: r.Hidden, followed: r.Followed}
+			continue
+		}
+
+		rs.states[key{user: r.User, media: r.Media}] = State{
+			Position: time.Duration(r.Position) * time.Millisecond,
+			Watched:  r.Watched,
+			Updated:  r.Updated,
+			Hidden:   r.Hidden,
+		}
+	}
+
+	return nil
+}
+
+// flush saves the playback states of repositories that changed since the last save, they're saved again next time if it fails.
+func (s *Store) flush() (err error) {
+	type snapshot struct {
+		repoId, path string
+		records      []record
+	}
+
+	s.mu.Lock()
+	var snapshots []snapshot
+	for repoId, rs := range s.repos {
+		if !rs.dirty || rs.path == "" {
+			continue
+		}
+
+		records := make([]record, 0, len(rs.states)+len(rs.series))
+		for k, state := range rs.states {
+			records = append(records, record{
+				User:     k.user,
+				Media:    k.media,
+				Position: state.Position.Milliseconds(),
+				Watched:  state.Watched,
+				Updated:  state.Updated,
+				Hidden:   state.Hidden,
+			})
+		}
+		for k, state := range rs.series {
+			records = append(records, record{User: k.user, Series: k.media, Hidden: state.hidden, Followed: state.followed})
+		}
+
+		snapshots = append(snapshots, snapshot{repoId: repoId, path: rs.path, records: records})
+		rs.dirty = false
+	}
+	s.mu.Unlock()
+
+	for _, snap := range snapshots {
+		if err0 := save(snap.path, snap.records); err0 != nil {
+			err = multierr.Append(err, errors.Wrapf(err0, "failed to save playback states of repository %s", snap.repoId))
+
+			s.mu.Lock()
+			s.repos[snap.repoId].dirty = true
+			s.mu.Unlock()
+		}
+	}
+
+	return err
+}
+
+// save writes playback state records to a file, replacing it atomically.
+func save(path string, records []record) error {
+	b, err := json.Marshal(records)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal playback states")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return errors.Wrap(err, "failed to write playback states")
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Wrap(err, "failed to replace playback states")
+	}
+
+	return nil
+}
+
+func (s *Store) flusher(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.flush(); err != nil && s.logger != nil {
+				s.logger.Error("failed to save playback states", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Close stops the periodic saving and saves the playback states a final time.
+func (s *Store) Close() error {
+	s.stop()
+	<-s.done
+
+	return s.flush()
+}
@@ -0,0 +1,66 @@
+package playback
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	paths := map[string]string{"repo": filepath.Join(t.TempDir(), "index.playback.json")}
+
+	s, err := NewStore(paths, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Set("repo", "user", "media", 90*time.Second, false)
+	s.Set("repo", "other", "media", 0, true)
+	s.SetHidden("repo", "user", "hidden", true)
+	s.SetSeriesHidden("repo", "user", "series (2000)", true)
+	s.SetSeriesFollowed("repo", "user", "followed (2010)", true)
+	s.SetSeriesFollowed("repo", "other", "followed (2010)", true)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = NewStore(paths, nil) // reload
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if state := s.Get("repo", "user", "media"); state.Position != 90*time.Second || state.Watched || state.Updated.IsZero() {
+		t.Errorf("expected 90s unwatched position, got %+v", state)
+	}
+	if state := s.Get("repo", "other", "media"); !state.Watched {
+		t.Errorf("expected watched media, got %+v", state)
+	}
+	if state := s.Get("repo", "user", "unplayed"); state != (State{}) {
+		t.Errorf("expected zero state of unplayed media, got %+v", state)
+	}
+	if states := s.States("repo", "user"); len(states) != 2 || states["media"].Position != 90*time.Second || !states["hidden"].Hidden {
+		t.Errorf("expected only the states of the user's played and hidden media, got %+v", states)
+	}
+	if series := s.HiddenSeries("repo", "user"); len(series) != 1 {
+		t.Errorf("expected a hidden series, got %v", series)
+	}
+	if series := s.HiddenSeries("repo", "other"); len(series) != 0 {
+		t.Errorf("expected no hidden series of another user, got %v", series)
+	}
+	if series := s.FollowedSeries("repo", "user"); len(series) != 1 {
+		t.Errorf("expected a followed series, got %v", series)
+	}
+	if users := s.Followers("repo", "followed (2010)"); len(users) != 2 || users[0] != "other" || users[1] != "user" {
+		t.Errorf("expected both users to follow the series, got %v", users)
+	}
+
+	s.SetSeriesFollowed("repo", "other", "followed (2010)", false)
+	if users := s.Followers("repo", "followed (2010)"); len(users) != 1 {
+		t.Errorf("expected one follower left, got %v", users)
+	}
+
+	s.Set("repo", "user", "hidden", time.Minute, false)
+	if state := s.Get("repo", "user", "hidden"); !state.Hidden {
+		t.Error("expected hidden media to stay hidden after playing it")
+	}
+}
@@ -1,82 +1,269 @@
 package server
 
 import (
+	"context"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"github.com/katana-project/katana"
 	"github.com/katana-project/katana/config"
 	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/internal/update"
 	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/archive"
+	"github.com/katana-project/katana/repo/checksum"
+	"github.com/katana-project/katana/repo/event"
+	"github.com/katana-project/katana/repo/identify"
 	"github.com/katana-project/katana/repo/index"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
 	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/katana/repo/mount"
 	"github.com/katana-project/katana/repo/mux"
+	"github.com/katana-project/katana/repo/quota"
+	"github.com/katana-project/katana/repo/refresh"
+	"github.com/katana-project/katana/repo/replica"
+	"github.com/katana-project/katana/repo/trash"
 	"github.com/katana-project/katana/repo/watch"
+	"github.com/katana-project/katana/server/api/schema"
+	"github.com/katana-project/katana/server/auth"
+	"github.com/katana-project/katana/server/collection"
+	"github.com/katana-project/katana/server/notify"
+	"github.com/katana-project/katana/server/playback"
+	"github.com/katana-project/katana/server/stats"
 	"github.com/katana-project/katana/server/v1"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"io"
 	"net/http"
+	"path/filepath"
+	"strings"
 )
 
 // HandlerCloser is a http.Handler that is notified of the HTTP server shutting down.
 type HandlerCloser interface {
 	http.Handler
 	io.Closer
+
+	// Drain ends long-lived requests (event streams), it should be called when the HTTP server starts shutting down.
+	Drain()
+	// Shutdown is Close, waiting for running jobs until the context is done.
+	Shutdown(ctx context.Context) error
 }
 
 // handlerCloser is an implementation of HandlerCloser.
 type handlerCloser struct {
 	http.Handler
 	io.Closer
+
+	drain    func()
+	shutdown func(ctx context.Context) error
+}
+
+func (hc *handlerCloser) Drain() {
+	hc.drain()
+}
+
+func (hc *handlerCloser) Shutdown(ctx context.Context) error {
+	return hc.shutdown(ctx)
+}
+
+// RouterOptions are the HTTP middleware options of a router.
+type RouterOptions struct {
+	// CORS are the cross-origin resource sharing options of the API.
+	CORS cors.Options
+	// AccessLog enables logging of requests.
+	AccessLog bool
+	// AccessLogErrorsOnly limits logging to requests with an error response (status 400 and above).
+	AccessLogErrorsOnly bool
+	// AccessLogSkipPaths are URL path prefixes of requests that aren't logged.
+	AccessLogSkipPaths []string
+}
+
+// NewRouterOptions creates router options from the HTTP configuration section, it must be completed with default values.
+func NewRouterOptions(cfg *config.HTTP) *RouterOptions {
+	return &RouterOptions{
+		CORS: cors.Options{
+			AllowedOrigins:   cfg.CORS.AllowedOrigins,
+			AllowedMethods:   cfg.CORS.AllowedMethods,
+			AllowedHeaders:   cfg.CORS.AllowedHeaders,
+			ExposedHeaders:   []string{"Link", "ETag", "Last-Modified", "X-Total-Count"},
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           int(cfg.CORS.MaxAge.Seconds()),
+		},
+		AccessLog:           !cfg.AccessLog.Disabled,
+		AccessLogErrorsOnly: cfg.AccessLog.ErrorsOnly,
+		AccessLogSkipPaths:  cfg.AccessLog.SkipPaths,
+	}
 }
 
-// NewRouter creates a new router from configuration.
-func NewRouter(repos []repo.Repository, logger *zap.Logger) (HandlerCloser, error) {
-	v1Srv, err := v1.NewServer(repos, logger)
+// NewRouter creates a new router of a v1 API server made with the options (see v1.Options).
+// API requests are authenticated by the authenticator, can be nil.
+// The middleware is set up by the options, the configuration defaults are used if nil.
+func NewRouter(srvOpts *v1.Options, authn *auth.Authenticator, opts *RouterOptions, logger *zap.Logger) (HandlerCloser, error) {
+	h, _, err := newRouter(srvOpts, authn, opts, logger)
+	return h, err
+}
+
+// newRouter creates a new router, returns the v1 API server along with it.
+func newRouter(srvOpts *v1.Options, authn *auth.Authenticator, opts *RouterOptions, logger *zap.Logger) (HandlerCloser, *v1.Server, error) {
+	if opts == nil {
+		opts = NewRouterOptions(new(config.HTTP).Defaults())
+	}
+
+	v1Srv, err := v1.NewServer(srvOpts, logger)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create v1 api handler")
+	}
+
+	v1Spec, err := newAsset("v1.yaml", "application/yaml", schema.V1)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create v1 api handler")
+		return nil, nil, errors.Wrap(err, "failed to compress v1 api specification")
 	}
 
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RequestLogger(&middleware.DefaultLogFormatter{
-		Logger:  zap.NewStdLog(logger),
-		NoColor: true,
-	}))
+	r.Use(traceRequests)
+	if opts.AccessLog {
+		r.Use(middleware.RequestLogger(&accessLogFormatter{
+			LogFormatter: &middleware.DefaultLogFormatter{
+				Logger:  zap.NewStdLog(logger),
+				NoColor: true,
+			},
+			errorsOnly: opts.AccessLogErrorsOnly,
+			skipPaths:  opts.AccessLogSkipPaths,
+		}))
+	}
 	r.Use(middleware.Recoverer)
 	r.Route("/api", func(r chi.Router) {
-		r.Use(cors.Handler(cors.Options{
-			AllowedOrigins:   []string{"https://*", "http://*"},
-			AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE"},
-			AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
-			ExposedHeaders:   []string{"Link"},
-			AllowCredentials: false,
-			MaxAge:           300,
-		}))
+		r.Use(cors.Handler(opts.CORS))
+
+		r.Method(http.MethodGet, "/openapi/v1.yaml", v1Spec)
+		if authn != nil {
+			r.Post("/auth/login", authn.HandleLogin)
+			r.Post("/auth/logout", authn.HandleLogout)
+		}
 
-		r.Mount("/v1", v1.NewRouter("/api/v1", v1Srv))
+		r.Group(func(r chi.Router) {
+			r.Use(authn.Middleware)
+			r.Use(requestLogger(logger))
+			r.Mount("/v1", v1.NewRouter("/api/v1", v1Srv))
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(authn.FeedMiddleware)
+			r.Use(requestLogger(logger))
+			r.Get("/feeds/calendar.ics", v1Srv.HandleCalendarFeed)
+		})
 	})
 
 	return &handlerCloser{
-		Handler: r,
-		Closer:  v1Srv,
-	}, nil
+		Handler:  r,
+		Closer:   v1Srv,
+		drain:    v1Srv.Drain,
+		shutdown: v1Srv.Shutdown,
+	}, v1Srv, nil
 }
 
+// requestLogger is a middleware providing handlers with a logger carrying the request ID
+// and the authenticated user, retrievable with repo.Logger.
+func requestLogger(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if logger == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			fields := []zap.Field{zap.String("request_id", middleware.GetReqID(ctx))}
+			if user := auth.User(ctx); user != "" {
+				fields = append(fields, zap.String("user", user))
+			}
+
+			next.ServeHTTP(w, r.WithContext(repo.WithLogger(ctx, logger.With(fields...))))
+		})
+	}
+}
+
+// traceRequests is a middleware recording a server span of each request, continuing traces of clients (traceparent header).
+// Spans are named by the route of the request once it's known.
+func traceRequests(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			if pattern := chi.RouteContext(r.Context()).RoutePattern(); pattern != "" {
+				span := oteltrace.SpanFromContext(r.Context())
+				span.SetName(r.Method + " " + pattern)
+				span.SetAttributes(attribute.String("http.route", pattern))
+			}
+		}),
+		"HTTP",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return "HTTP " + r.Method
+		}),
+	)
+}
+
+// convCaps are the media conversion capabilities, backed by FFmpeg.
+const convCaps = repo.CapabilityRemux | repo.CapabilityTranscode
+
 // NewConfiguredRouter creates a new router from configuration.
 func NewConfiguredRouter(cfg *config.Config, logger *zap.Logger) (HandlerCloser, error) {
-	repos := make(map[string]repo.Repository, len(cfg.Repos))
+	var (
+		repos         = make(map[string]repo.Repository, len(cfg.Repos))
+		aliases       = make(map[string]string)
+		playbackPaths = make(map[string]string, len(cfg.Repos))
+		unavailable   = make(map[string]map[repo.Capability]string, len(cfg.Repos))
+		bins          = make(map[string]*trash.Bin)
+		replicators   = make(map[string]*replica.Replicator)
+		bus           = event.NewBus()
+		features      *repo.Features
+	)
+
+	backendOpts := &mux.BackendOptions{FFmpegPath: cfg.Mux.FFmpegPath, FFprobePath: cfg.Mux.FFprobePath}
+	backend, backendErr := mux.NewBackend(cfg.Mux.Backend, backendOpts, logger)
+	if backendErr != nil { // repositories are still usable, only without conversions
+		logger.Error("failed to create media conversion backend", zap.String("backend", cfg.Mux.Backend), zap.Error(backendErr))
+	} else {
+		features = backend.Features()
+		logger.Info(
+			"detected FFmpeg features",
+			zap.String("backend", backend.Name()),
+			zap.Int("muxers", len(features.Muxers)),
+			zap.Int("demuxers", len(features.Demuxers)),
+			zap.Int("encoders", len(features.Encoders)),
+			zap.Int("decoders", len(features.Decoders)),
+			zap.Strings("hw_accels", features.HWAccels),
+		)
+	}
+
 	for repoId, repoConfig := range cfg.Repos {
 		if _, ok := repos[repoId]; ok {
 			return nil, &ErrDuplicateRepo{
 				ID:   repoId,
-				Path: repoConfig.Path,
+				Path: repoConfig.Path.String(),
 			}
 		}
 
+		for _, alias := range repoConfig.Aliases {
+			if _, ok := aliases[alias]; ok {
+				return nil, &ErrDuplicateRepo{
+					ID:   alias,
+					Path: repoConfig.Path.String(),
+				}
+			}
+
+			aliases[alias] = repoId
+		}
+
 		metaSources := make([]meta.Source, 0, len(repoConfig.Sources))
 		for sourceName, options := range repoConfig.Sources {
-			ms, err := NewConfiguredMetaSource(sourceName, options)
+			ms, err := NewConfiguredMetaSource(sourceName, options, backend)
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to configure metadata source %s", sourceName)
 			}
@@ -84,16 +271,85 @@ func NewConfiguredRouter(cfg *config.Config, logger *zap.Logger) (HandlerCloser,
 			metaSources = append(metaSources, ms)
 		}
 
+		idStrategy := repo.IDStrategy(repoConfig.IDStrategy)
+		if idStrategy == nil {
+			return nil, fmt.Errorf("unknown media ID strategy %s", repoConfig.IDStrategy)
+		}
+
+		filter, err := repo.NewFilter(repoConfig.Ignore, repoConfig.Include)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse file patterns")
+		}
+
 		metaSource := meta.NewCompositeSource(metaSources...)
-		r, err := repo.NewRepository(repoId, repoConfig.Name, repoConfig.Path, metaSource, logger)
+		r, err := repo.NewMultiRootRepository(repoId, repoConfig.Name, repoConfig.Path, metaSource, idStrategy, filter, logger)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create repository")
 		}
 
-		if repoConfig.Capable(config.CapabilityRemux) || repoConfig.Capable(config.CapabilityTranscode) {
-			r, err = mux.NewRepository(r, repo.Capabilities(repoConfig.Capabilities), repoConfig.CachePath, logger)
+		reasons := make(map[repo.Capability]string)
+		if cap := repo.Capabilities(repoConfig.Capabilities) & convCaps; cap != 0 {
+			var reason string
+			if backendErr != nil {
+				reason = fmt.Sprintf("conversion backend failed to initialize: %s", backendErr.Error())
+			}
+			if repoConfig.CachePath == "" { // zero value
+				reason = "no cache path configured"
+			}
+
+			if reason != "" { // the mux repository can't be made without them, it negotiates the rest itself
+				for _, c := range []repo.Capability{repo.CapabilityRemux, repo.CapabilityTranscode} {
+					if cap.Has(c) {
+						logger.Warn("disabled repository capability", zap.String("repo", repoId), zap.Stringer("capability", c), zap.String("reason", reason))
+						reasons[c] = reason
+					}
+				}
+			} else {
+				if missing := mux.MissingMuxers(backend); len(missing) > 0 {
+					logger.Warn(
+						"FFmpeg muxers missing for some target formats",
+						zap.String("repo", repoId),
+						zap.Strings("formats", formatNames(missing)),
+					)
+				}
+
+				cache := &mux.CachePolicy{
+					Layout:    mux.Layout(repoConfig.Cache.Layout),
+					Remux:     repoConfig.Cache.Remux,
+					Transcode: repoConfig.Cache.Transcode,
+					Interval:  repoConfig.Cache.JanitorInterval,
+					MaxBytes:  repoConfig.CacheMaxBytes,
+					MaxAge:    repoConfig.CacheMaxAge,
+				}
+
+				mr, err := mux.NewRepository(r, cap, repoConfig.CachePath, cache, cfg.Mux.ProbeWorkers, backend, logger)
+				if err != nil { // the repository is still usable, only without conversions
+					logger.Error("failed to create mux repository", zap.String("repo", repoId), zap.Error(err))
+					for _, c := range []repo.Capability{repo.CapabilityRemux, repo.CapabilityTranscode} {
+						if cap.Has(c) {
+							reasons[c] = fmt.Sprintf("cache failed to initialize: %s", err.Error())
+						}
+					}
+				} else {
+					r = mr
+				}
+			}
+		}
+
+		if ac := repoConfig.Archive; ac.Enabled() { // below the index, which keeps the media of archived files
+			var mover archive.Mover
+			if len(ac.Command) > 0 {
+				mover, err = archive.NewCommandMover(ac.Command, ac.RestoreCommand)
+			} else {
+				mover, err = archive.NewDirMover(ac.Path)
+			}
 			if err != nil {
-				return nil, errors.Wrap(err, "failed to create mux repository")
+				return nil, errors.Wrap(err, "failed to create archive mover")
+			}
+
+			r, err = archive.NewRepository(r, mover, repoConfig.ArchivePath(), logger)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to create archive repository")
 			}
 		}
 
@@ -102,23 +358,246 @@ func NewConfiguredRouter(cfg *config.Config, logger *zap.Logger) (HandlerCloser,
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to create indexed repository")
 			}
+		} else {
+			reasons[repo.CapabilityIndex] = "no index path configured"
 		}
 
-		if repoConfig.Capable(config.CapabilityWatch) {
-			r, err = watch.NewRepository(r, logger)
+		if repoConfig.Quota > 0 {
+			r, err = quota.NewRepository(r, repoConfig.Quota, logger)
 			if err != nil {
-				return nil, errors.Wrap(err, "failed to create watched repository")
+				return nil, errors.Wrap(err, "failed to create quota repository")
 			}
 		}
 
-		go func() {
-			if err := r.Scan(); err != nil {
-				logger.Error("failed to scan repository", zap.String("repo", repoId), zap.Error(err))
+		if repoConfig.Capable(config.CapabilityWatch) {
+			wr, err := watch.NewRepository(r, logger)
+			if err != nil { // the repository is still usable, changes are picked up by scans
+				logger.Error("failed to create watched repository", zap.String("repo", repoId), zap.Error(err))
+				reasons[repo.CapabilityWatch] = fmt.Sprintf("watcher failed to initialize: %s", err.Error())
+			} else {
+				r = wr
 			}
-		}()
+		}
+
+		r = mount.NewRepository(r, logger) // outermost, so that degraded repositories aren't scanned
+
+		if repoConfig.Trash.Enabled {
+			if bins[repoId], err = trash.NewBin(r, repoConfig.Trash.Retention, logger); err != nil {
+				return nil, errors.Wrap(err, "failed to create trash")
+			}
+		}
+
+		if rc := repoConfig.Replica; rc.URL != "" {
+			opts := &replica.Options{
+				URL:      rc.URL,
+				Token:    rc.Token,
+				Repo:     rc.Repo,
+				Files:    rc.Files,
+				Conflict: replica.Conflict(rc.Conflict),
+				Prune:    rc.Prune,
+				Interval: rc.Interval,
+			}
+			if replicators[repoId], err = replica.NewReplicator(repoConfig.ReplicaPath(), opts, nil, logger); err != nil {
+				return nil, errors.Wrap(err, "failed to create replicator")
+			}
+		}
 
+		event.Forward(r, bus) // for as long as the repository lives
 		repos[repoId] = r
+		playbackPaths[repoId] = repoConfig.PlaybackPath()
+		unavailable[repoId] = reasons
 	}
 
-	return NewRouter(maps.Values(repos), logger)
+	store, err := stats.NewStore(cfg.Stats.Path, cfg.Stats.Retention, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create statistics store")
+	}
+
+	pb, err := playback.NewStore(playbackPaths, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create playback store")
+	}
+
+	authn, err := auth.NewAuthenticator(cfg.Auth.Tokens, cfg.Auth.FeedTokens, cfg.Auth.Users, cfg.Auth.SessionTTL, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create authenticator")
+	}
+
+	colls, err := collection.NewStore(cfg.Collections.Path, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create collection store")
+	}
+
+	var catalog *checksum.Catalog
+	if cfg.Checksums.Path != "" { // zero value
+		if catalog, err = checksum.NewCatalog(cfg.Checksums.Path, logger); err != nil {
+			return nil, errors.Wrap(err, "failed to create checksum catalog")
+		}
+	}
+
+	var refresher *refresh.Refresher
+	if cfg.Refresh.Path != "" { // zero value
+		if refresher, err = refresh.NewRefresher(cfg.Refresh.Path, cfg.Refresh.MaxAge, cfg.Refresh.Jitter, cfg.Refresh.RateLimit, logger); err != nil {
+			return nil, errors.Wrap(err, "failed to create metadata refresher")
+		}
+	}
+
+	var identifier *identify.Identifier
+	if cfg.Identify.Enabled {
+		opts := &identify.Options{
+			FpcalcPath:  cfg.Identify.FpcalcPath,
+			AcoustIDKey: cfg.Identify.AcoustIDKey,
+			Path:        cfg.Identify.Path,
+			Client:      &http.Client{Transport: otelhttp.NewTransport(nil)},
+		}
+		if backendErr == nil { // video frames aren't hashed without a backend
+			opts.Frames = backend
+		}
+
+		if identifier, err = identify.NewIdentifier(opts, logger); err != nil {
+			return nil, errors.Wrap(err, "failed to create identifier")
+		}
+	}
+
+	if cfg.HTTP.CORS.AllowCredentials && slices.ContainsFunc(cfg.HTTP.CORS.AllowedOrigins, func(o string) bool { return strings.Contains(o, "*") }) {
+		logger.Warn("cross-origin requests with credentials are allowed from wildcard origins", zap.Strings("origins", cfg.HTTP.CORS.AllowedOrigins))
+	}
+
+	endpoints := make([]*notify.Endpoint, len(cfg.Webhooks.Push))
+	for i, p := range cfg.Webhooks.Push {
+		kinds := make([]notify.Kind, len(p.Events))
+		for j, e := range p.Events {
+			kinds[j] = notify.Kind(e)
+		}
+
+		if endpoints[i], err = notify.NewEndpoint(notify.Service(p.Service), p.URL, p.Token, p.Priority, kinds); err != nil {
+			return nil, errors.Wrap(err, "failed to configure push notifications")
+		}
+	}
+	if len(endpoints) > 0 { // runs until the bus is closed, before the initial scans so that their new media are pushed
+		notify.NewNotifier(endpoints, maps.Values(repos), bus, cfg.Webhooks.MinFreeSpace, cfg.Webhooks.DiskInterval, logger)
+	}
+
+	srvOpts := &v1.Options{
+		Repos:       maps.Values(repos),
+		Aliases:     aliases,
+		Unavailable: unavailable,
+		Features:    features,
+		Queue:       jobs.NewQueue(cfg.Jobs.Workers, cfg.Jobs.Retention, bus, logger),
+		Stats:       store,
+		Playback:    pb,
+		Collections: colls,
+		Catalog:     catalog,
+		Trash:       bins,
+		Events:      bus,
+	}
+	h, v1Srv, err := newRouter(srvOpts, authn, NewRouterOptions(cfg.HTTP), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if wm := cfg.Mux.Watermark; wm.Text != "" || wm.Image != "" {
+		image := wm.Image
+		if image != "" {
+			if image, err = filepath.Abs(image); err != nil {
+				return nil, errors.Wrap(err, "failed to make watermark image path absolute")
+			}
+		}
+
+		v1Srv.SetWatermark(&media.Watermark{
+			Text:     wm.Text,
+			Image:    image,
+			Position: media.WatermarkPosition(wm.Position),
+			Opacity:  wm.Opacity,
+		})
+	}
+	for repoId, r := range repos { // failures are logged by the job queue
+		if pc := cfg.Repos[repoId].Precache; pc.Format != "" {
+			profile, err := precacheProfile(pc, r.Capabilities())
+			if err == nil {
+				err = v1Srv.Precache(r, profile)
+			}
+			if err != nil {
+				logger.Warn("failed to configure precaching", zap.String("repo", repoId), zap.Error(err))
+			}
+		}
+		if _, err := v1Srv.Scan(r, repo.ScanIncremental); err != nil {
+			logger.Error("failed to queue repository scan", zap.String("repo", repoId), zap.Error(err))
+		}
+	}
+	if err := v1Srv.SetDASH(dashProfiles(cfg.Mux.DASH)); err != nil {
+		logger.Info("DASH streaming disabled", zap.Error(err))
+	}
+	v1Srv.SetTransferCaps(cfg.Stats.MonthlyCap, cfg.Stats.Caps)
+	if backendErr == nil {
+		v1Srv.SetFormatSupport(mux.Support(backend))
+	}
+	if catalog != nil {
+		v1Srv.Maintain(cfg.Checksums.Interval)
+	}
+	if refresher != nil {
+		v1Srv.ScheduleRefresh(refresher, cfg.Refresh.Interval)
+	}
+	if identifier != nil {
+		v1Srv.SetIdentifier(identifier)
+	}
+	if cfg.Update.Interval > 0 && katana.Version != "dev" {
+		v1Srv.ScheduleUpdateCheck(update.NewClient(cfg.Update.Feed, katana.Version, &http.Client{Transport: otelhttp.NewTransport(nil)}), cfg.Update.Interval)
+	}
+	if len(replicators) > 0 {
+		v1Srv.ScheduleReplication(replicators)
+	}
+
+	return h, nil
+}
+
+// formatNames returns the names of media formats.
+func formatNames(formats []*media.Format) []string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = f.Name
+	}
+
+	return names
+}
+
+// precacheProfile makes the conversion profile of a repository's precache configuration, the repository must be capable of it.
+func precacheProfile(pc *config.Precache, cap repo.Capability) (*media.TranscodeProfile, error) {
+	format := media.FindFormat(pc.Format)
+	if format == nil {
+		return nil, fmt.Errorf("unknown format %s", pc.Format)
+	}
+
+	needed := repo.CapabilityRemux
+	if pc.VideoCodec != "" || pc.AudioCodec != "" {
+		needed = repo.CapabilityTranscode
+	}
+	if !cap.Has(needed) {
+		return nil, fmt.Errorf("missing %s capability", needed)
+	}
+
+	return &media.TranscodeProfile{Format: format, VideoCodec: pc.VideoCodec, AudioCodec: pc.AudioCodec}, nil
+}
+
+// dashProfiles makes the transcode profiles of the configured DASH representations, video renditions first.
+func dashProfiles(dc *config.DASH) []*media.TranscodeProfile {
+	profiles := make([]*media.TranscodeProfile, 0, len(dc.Renditions)+1)
+	for _, r := range dc.Renditions {
+		profiles = append(profiles, &media.TranscodeProfile{
+			Format:       media.FormatMP4,
+			VideoCodec:   dc.VideoCodec,
+			VideoBitRate: r.VideoBitRate,
+			Height:       r.Height,
+			Streams:      media.StreamsVideo,
+			Fragmented:   true,
+		})
+	}
+
+	return append(profiles, &media.TranscodeProfile{
+		Format:       media.FormatMP4,
+		AudioCodec:   dc.AudioCodec,
+		AudioBitRate: dc.AudioBitRate,
+		Streams:      media.StreamsAudio,
+		Fragmented:   true,
+	})
 }
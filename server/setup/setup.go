@@ -0,0 +1,381 @@
+package setup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"github.com/go-chi/chi/v5"
+	"github.com/katana-project/katana/config"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/server/api/v1"
+	"github.com/katana-project/katana/server/auth"
+	tmdbClient "github.com/katana-project/tmdb"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tmdbTestTimeout is the timeout of testing a TMDB API key.
+const tmdbTestTimeout = 10 * time.Second
+
+// fileRepo is a repository section of the written configuration file.
+type fileRepo struct {
+	Name         string                                       `toml:"name"`
+	Path         []string                                     `toml:"path"`
+	Capabilities []string                                     `toml:"capabilities"`
+	Sources      map[string]map[string]map[string]interface{} `toml:"sources"`
+}
+
+// file is the written configuration file, only the sections set up by the wizard,
+// the rest is completed with defaults (config.Config.Defaults).
+type file struct {
+	HTTP struct {
+		Host string `toml:"host"`
+	} `toml:"http"`
+	Auth struct {
+		Users map[string]string `toml:"users"`
+	} `toml:"auth"`
+	Repos map[string]*fileRepo `toml:"repos"`
+}
+
+// Wizard is a setup-only HTTP API of a first run, without a configuration file.
+// It creates users, adds repositories and tests a TMDB API key, then writes the configuration file.
+// It's unauthenticated, there are no users yet.
+type Wizard struct {
+	path, host string
+	logger     *zap.Logger
+	handler    http.Handler
+
+	mu       sync.Mutex
+	users    map[string]string    // username -> password hash
+	repos    map[string]*fileRepo // repository ID -> section
+	tmdbKey  string               // tested TMDB API key, empty if not set up
+	done     chan struct{}
+	finished bool
+}
+
+// NewWizard creates a setup wizard writing the configuration file to path,
+// the server of the configuration listens on host, such as the one of the wizard.
+func NewWizard(path, host string, logger *zap.Logger) *Wizard {
+	w := &Wizard{
+		path:   path,
+		host:   host,
+		logger: logger,
+		users:  make(map[string]string),
+		repos:  make(map[string]*fileRepo),
+		done:   make(chan struct{}),
+	}
+
+	r := chi.NewRouter()
+	r.Route("/api/setup", func(r chi.Router) {
+		r.Get("/", w.handleStatus)
+		r.Post("/users", w.handleUser)
+		r.Post("/repos", w.handleRepo)
+		r.Post("/tmdb", w.handleTMDB)
+		r.Post("/finish", w.handleFinish)
+	})
+	w.handler = r
+
+	return w
+}
+
+// Done returns a channel closed once the configuration file is written.
+func (w *Wizard) Done() <-chan struct{} {
+	return w.done
+}
+
+func (w *Wizard) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w.handler.ServeHTTP(rw, r)
+}
+
+// statusRepo is a repository of the setup status.
+type statusRepo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// status is the state of the setup.
+type status struct {
+	Users []string     `json:"users"`
+	Repos []statusRepo `json:"repos"`
+	TMDB  bool         `json:"tmdb"`
+}
+
+// handleStatus handles getting the state of the setup.
+func (w *Wizard) handleStatus(rw http.ResponseWriter, _ *http.Request) {
+	w.mu.Lock()
+	res := status{Users: maps.Keys(w.users), Repos: make([]statusRepo, 0, len(w.repos)), TMDB: w.tmdbKey != ""}
+	for id, fr := range w.repos {
+		res.Repos = append(res.Repos, statusRepo{ID: id, Name: fr.Name, Path: fr.Path[0]})
+	}
+	w.mu.Unlock()
+
+	slices.Sort(res.Users)
+	slices.SortFunc(res.Repos, func(a, b statusRepo) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	writeJSON(rw, http.StatusOK, res)
+}
+
+// userRequest is a request creating a user.
+type userRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// handleUser handles creating a user, a user with the same name is replaced.
+func (w *Wizard) handleUser(rw http.ResponseWriter, r *http.Request) {
+	var req userRequest
+	if !decode(rw, r, &req) {
+		return
+	}
+	if req.Username == "" || req.Password == "" {
+		writeError(rw, http.StatusBadRequest, v1.Error{Type: v1.BadRequest, Description: "username and password are required"})
+		return
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		writeError(rw, http.StatusInternalServerError, v1.Error{Type: v1.InternalError, Description: "failed to hash password"})
+		return
+	}
+
+	w.mu.Lock()
+	w.users[req.Username] = hash
+	w.mu.Unlock()
+
+	if w.logger != nil {
+		w.logger.Info("set up user", zap.String("user", req.Username))
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// repoRequest is a request adding a repository.
+type repoRequest struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Capabilities are the capability IDs of the repository, defaults to "watch".
+	Capabilities []config.Capability `json:"capabilities"`
+}
+
+// handleRepo handles adding a repository, a repository with the same ID is replaced.
+func (w *Wizard) handleRepo(rw http.ResponseWriter, r *http.Request) {
+	var req repoRequest
+	if !decode(rw, r, &req) {
+		return
+	}
+	if !repo.ValidID(req.ID) {
+		writeError(rw, http.StatusBadRequest, v1.Error{Type: v1.BadRequest, Description: "invalid repository ID"})
+		return
+	}
+	if req.Path == "" {
+		writeError(rw, http.StatusBadRequest, v1.Error{Type: v1.BadRequest, Description: "path is required"})
+		return
+	}
+
+	path, err := filepath.Abs(req.Path)
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, v1.Error{Type: v1.BadRequest, Description: fmt.Sprintf("invalid path: %s", err.Error())})
+		return
+	}
+	if fi, err := os.Stat(path); err != nil || !fi.IsDir() {
+		writeError(rw, http.StatusBadRequest, v1.Error{Type: v1.BadRequest, Description: "path is not a directory"})
+		return
+	}
+
+	caps := []string{string(config.CapabilityWatch)}
+	if req.Capabilities != nil {
+		caps = make([]string, len(req.Capabilities))
+		for i, c := range req.Capabilities {
+			if c != config.CapabilityWatch && c != config.CapabilityRemux && c != config.CapabilityTranscode {
+				writeError(rw, http.StatusBadRequest, v1.Error{Type: v1.BadRequest, Description: fmt.Sprintf("unknown capability %s", c)})
+				return
+			}
+
+			caps[i] = string(c)
+		}
+	}
+
+	name := req.Name
+	if name == "" {
+		name = req.ID
+	}
+
+	w.mu.Lock()
+	w.repos[req.ID] = &fileRepo{Name: name, Path: []string{path}, Capabilities: caps}
+	w.mu.Unlock()
+
+	if w.logger != nil {
+		w.logger.Info("set up repository", zap.String("repo", req.ID), zap.String("path", path))
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// tmdbRequest is a request testing a TMDB API key.
+type tmdbRequest struct {
+	Key string `json:"key"`
+}
+
+// handleTMDB handles testing a TMDB API key, the repositories resolve metadata from TMDB with it once it works.
+func (w *Wizard) handleTMDB(rw http.ResponseWriter, r *http.Request) {
+	var req tmdbRequest
+	if !decode(rw, r, &req) {
+		return
+	}
+	if req.Key == "" {
+		writeError(rw, http.StatusBadRequest, v1.Error{Type: v1.BadRequest, Description: "key is required"})
+		return
+	}
+
+	if err := testTMDBKey(r.Context(), req.Key); err != nil {
+		writeError(rw, http.StatusBadRequest, v1.Error{Type: v1.BadRequest, Description: err.Error()})
+		return
+	}
+
+	w.mu.Lock()
+	w.tmdbKey = req.Key
+	w.mu.Unlock()
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// testTMDBKey checks whether a TMDB API key is accepted, by fetching the API configuration.
+func testTMDBKey(ctx context.Context, key string) error {
+	client, err := tmdbClient.NewClientWithResponses(tmdbClient.DefaultServerBaseURL, tmdbClient.WithToken(key))
+	if err != nil {
+		return errors.Wrap(err, "failed to create tmdb api client")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tmdbTestTimeout)
+	defer cancel()
+
+	res, err := client.ConfigurationDetailsWithResponse(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to reach tmdb api")
+	}
+
+	switch code := res.StatusCode(); code {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		return errors.New("tmdb api key was rejected")
+	default:
+		return fmt.Errorf("tmdb api responded with status %d", code)
+	}
+}
+
+// handleFinish handles writing the configuration file, there must be at least one user and repository.
+func (w *Wizard) handleFinish(rw http.ResponseWriter, _ *http.Request) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.finished {
+		writeError(rw, http.StatusBadRequest, v1.Error{Type: v1.BadRequest, Description: "setup is finished already"})
+		return
+	}
+	if len(w.users) == 0 {
+		writeError(rw, http.StatusBadRequest, v1.Error{Type: v1.BadRequest, Description: "no user set up"})
+		return
+	}
+	if len(w.repos) == 0 {
+		writeError(rw, http.StatusBadRequest, v1.Error{Type: v1.BadRequest, Description: "no repository set up"})
+		return
+	}
+
+	if err := w.write(); err != nil {
+		if w.logger != nil {
+			w.logger.Error("failed to write configuration", zap.String("path", w.path), zap.Error(err))
+		}
+
+		writeError(rw, http.StatusInternalServerError, v1.Error{Type: v1.InternalError, Description: "failed to write configuration"})
+		return
+	}
+
+	if w.logger != nil {
+		w.logger.Info("wrote configuration", zap.String("path", w.path))
+	}
+
+	w.finished = true
+	close(w.done)
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// write writes the configuration file, replacing it atomically, mu must be held.
+// The file is only readable by its owner, it holds password hashes and API keys.
+func (w *Wizard) write() error {
+	// files are analyzed for a query, resolved by TMDB if it's set up
+	sources := map[string]map[string]interface{}{"literal": {}}
+	if w.tmdbKey != "" {
+		sources = map[string]map[string]interface{}{"tmdb": {"key": w.tmdbKey}}
+	}
+
+	var f file
+	f.HTTP.Host = w.host
+	f.Auth.Users = w.users
+	f.Repos = w.repos
+	for _, fr := range f.Repos {
+		fr.Sources = map[string]map[string]map[string]interface{}{string(config.MetadataSourceAnalysis): sources}
+	}
+
+	path := filepath.Clean(w.path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	tmp := path + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create file")
+	}
+	if err := toml.NewEncoder(out).Encode(&f); err != nil {
+		_ = out.Close()
+		_ = os.Remove(tmp)
+		return errors.Wrap(err, "failed to encode configuration")
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return errors.Wrap(err, "failed to close file")
+	}
+	if _, err := config.Parse(tmp); err != nil { // sanity check, the server is started with it next
+		_ = os.Remove(tmp)
+		return errors.Wrap(err, "failed to parse written configuration")
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return errors.Wrap(err, "failed to replace file")
+	}
+
+	return nil
+}
+
+// decode decodes a JSON request body, writing an error response if it fails.
+func decode(rw http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(rw, http.StatusBadRequest, v1.Error{Type: v1.BadRequest, Description: fmt.Sprintf("failed to decode request: %s", err.Error())})
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(rw http.ResponseWriter, code int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+
+	_ = json.NewEncoder(rw).Encode(v)
+}
+
+func writeError(rw http.ResponseWriter, code int, e v1.Error) {
+	writeJSON(rw, code, e)
+}
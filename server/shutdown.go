@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ShutdownFunc is a shutdown step, it should give up when the context is done.
+type ShutdownFunc func(ctx context.Context) error
+
+// hook is a named shutdown step.
+type hook struct {
+	name string
+	fn   ShutdownFunc
+}
+
+// Shutdown is an ordered sequence of shutdown steps, such as stopping the HTTP server before closing repositories.
+type Shutdown struct {
+	logger *zap.Logger
+	hooks  []hook
+}
+
+// NewShutdown creates an empty shutdown sequence.
+func NewShutdown(logger *zap.Logger) *Shutdown {
+	return &Shutdown{logger: logger}
+}
+
+// Add appends a step to the sequence.
+func (s *Shutdown) Add(name string, fn ShutdownFunc) {
+	s.hooks = append(s.hooks, hook{name: name, fn: fn})
+}
+
+// AddCloser appends a step closing a resource, it can't be interrupted by the context.
+func (s *Shutdown) AddCloser(name string, fn func() error) {
+	s.Add(name, func(_ context.Context) error {
+		return fn()
+	})
+}
+
+// Run runs the steps in the order they were added, a failing step doesn't prevent the following ones from running.
+func (s *Shutdown) Run(ctx context.Context) (err error) {
+	for _, h := range s.hooks {
+		startTime := time.Now()
+		if err0 := h.fn(ctx); err0 != nil {
+			err = multierr.Append(err, errors.Wrapf(err0, "failed to shut down %s", h.name))
+			continue
+		}
+
+		if s.logger != nil {
+			s.logger.Info(
+				"finished shutdown step",
+				zap.String("step", h.name),
+				zap.Int64("elapsed_ms", time.Since(startTime).Milliseconds()),
+			)
+		}
+	}
+
+	return err
+}
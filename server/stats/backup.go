@@ -0,0 +1,16 @@
+package stats
+
+import (
+	"github.com/katana-project/katana/backup"
+	"github.com/katana-project/katana/config"
+)
+
+func init() {
+	backup.Register("stats.json", func(cfg *config.Config) string {
+		if cfg.Stats == nil {
+			return ""
+		}
+
+		return cfg.Stats.Path
+	})
+}
@@ -0,0 +1,328 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/katana-project/katana/internal/errors"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DayLayout is the time layout of days ("2006-01-02").
+const DayLayout = "2006-01-02"
+
+// flushInterval is the period between saving changed statistics.
+const flushInterval = time.Minute
+
+// Day is the number of bytes served on a day.
+type Day struct {
+	// Date is the day in the local time zone.
+	Date time.Time
+	// Bytes is the number of bytes served.
+	Bytes int64
+}
+
+// key is a key of the bytes served of media on a day.
+type key struct {
+	repo, media, day string
+}
+
+// clientKey is a key of the bytes served to a client on a day.
+type clientKey struct {
+	client, day string
+}
+
+// record is a JSON-serializable statistics entry, of a client if Client is not empty or of media otherwise.
+type record struct {
+	Repo   string `json:"repo,omitempty"`
+	Media  string `json:"media,omitempty"`
+	Client string `json:"client,omitempty"`
+	Day    string `json:"day"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// Store is a store of bytes served per media and per client, rolled up per day.
+type Store struct {
+	path      string
+	retention time.Duration
+	logger    *zap.Logger
+
+	mu      sync.Mutex
+	days    map[key]int64
+	clients map[clientKey]int64
+	dirty   bool
+
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+// NewStore creates a statistics store persisted to a JSON file, periodically and on Close.
+// The store is kept only in memory if path is empty, days older than the retention period are forgotten, zero keeps them forever.
+func NewStore(path string, retention time.Duration, logger *zap.Logger) (*Store, error) {
+	s := &Store{
+		retention: retention,
+		logger:    logger,
+		days:      make(map[key]int64),
+		clients:   make(map[clientKey]int64),
+	}
+	if path != "" {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+
+		s.path = absPath
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stop, s.done = cancel, make(chan struct{})
+	go s.flusher(ctx)
+
+	return s, nil
+}
+
+// Add records bytes served of media at the current time.
+func (s *Store) Add(repoId, mediaId string, n int64) {
+	if n <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.days[key{repo: repoId, media: mediaId, day: time.Now().Format(DayLayout)}] += n
+	s.dirty = true
+}
+
+// AddClient records bytes served to a client (auth.Client) at the current time, requests of no client are not recorded.
+func (s *Store) AddClient(client string, n int64) {
+	if n <= 0 || client == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clients[clientKey{client: client, day: time.Now().Format(DayLayout)}] += n
+	s.dirty = true
+}
+
+// Media returns the bytes served of media per day, sorted by the date.
+func (s *Store) Media(repoId, mediaId string) []Day {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var days []Day
+	for k, n := range s.days {
+		if k.repo == repoId && k.media == mediaId {
+			days = appendDay(days, k.day, n)
+		}
+	}
+
+	sortDays(days)
+	return days
+}
+
+// Client returns the bytes served to a client per day since a date, sorted by the date.
+func (s *Store) Client(client string, since time.Time) []Day {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		days   []Day
+		cutoff = since.Format(DayLayout)
+	)
+	for k, n := range s.clients {
+		if k.client == client && k.day >= cutoff { // lexicographic order of the layout is chronological
+			days = appendDay(days, k.day, n)
+		}
+	}
+
+	sortDays(days)
+	return days
+}
+
+// Clients returns the clients with bytes served recorded, sorted.
+func (s *Store) Clients() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var clients []string
+	for k := range s.clients {
+		if !slices.Contains(clients, k.client) {
+			clients = append(clients, k.client)
+		}
+	}
+
+	slices.Sort(clients)
+	return clients
+}
+
+// appendDay appends a day of the DayLayout format to days.
+func appendDay(days []Day, day string, n int64) []Day {
+	date, err := time.ParseInLocation(DayLayout, day, time.Local)
+	if err != nil {
+		return days // validated on load
+	}
+
+	return append(days, Day{Date: date, Bytes: n})
+}
+
+// sortDays sorts days by the date.
+func sortDays(days []Day) {
+	slices.SortFunc(days, func(a, b Day) int {
+		return a.Date.Compare(b.Date)
+	})
+}
+
+// RenameMedia moves the statistics of a repository's media to new IDs, such as after migrating media IDs.
+// ids is a mapping of old media IDs to new ones, statistics of media not in it are kept as-is.
+func (s *Store) RenameMedia(repoId string, ids map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	days := make(map[key]int64, len(s.days))
+	for k, n := range s.days {
+		if id, ok := ids[k.media]; ok && k.repo == repoId {
+			k.media = id
+			s.dirty = true
+		}
+
+		days[k] += n
+	}
+	s.days = days
+}
+
+// prune forgets days past the retention period, mu must be held.
+func (s *Store) prune() {
+	if s.retention <= 0 {
+		return
+	}
+
+	cutoff := time.Now().Add(-s.retention).Format(DayLayout)
+	for k := range s.days {
+		if k.day < cutoff { // lexicographic order of the layout is chronological
+			delete(s.days, k)
+			s.dirty = true
+		}
+	}
+	for k := range s.clients {
+		if k.day < cutoff {
+			delete(s.clients, k)
+			s.dirty = true
+		}
+	}
+}
+
+func (s *Store) load() error {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return errors.Wrap(err, "failed to read statistics")
+	}
+
+	var records []record
+	if err := json.Unmarshal(b, &records); err != nil {
+		return errors.Wrap(err, "failed to unmarshal statistics")
+	}
+
+	for _, r := range records {
+		if _, err := time.Parse(DayLayout, r.Day); err != nil {
+			if s.logger != nil {
+				s.logger.Warn("malformed statistics day, skipping", zap.String("path", s.path), zap.String("day", r.Day))
+			}
+			continue
+		}
+
+		if r.Client != "" {
+			s.clients[clientKey{client: r.Client, day: r.Day}] += r.Bytes
+		} else {
+			s.days[key{repo: r.Repo, media: r.Media, day: r.Day}] += r.Bytes
+		}
+	}
+
+	return nil
+}
+
+// flush saves the statistics if they changed since the last save, they're saved again next time if it fails.
+func (s *Store) flush() (err error) {
+	s.mu.Lock()
+	s.prune()
+	if !s.dirty || s.path == "" {
+		s.mu.Unlock()
+		return nil
+	}
+
+	records := make([]record, 0, len(s.days)+len(s.clients))
+	for k, n := range s.days {
+		records = append(records, record{Repo: k.repo, Media: k.media, Day: k.day, Bytes: n})
+	}
+	for k, n := range s.clients {
+		records = append(records, record{Client: k.client, Day: k.day, Bytes: n})
+	}
+	s.dirty = false
+	s.mu.Unlock()
+
+	defer func() {
+		if err != nil {
+			s.mu.Lock()
+			s.dirty = true
+			s.mu.Unlock()
+		}
+	}()
+
+	b, err := json.Marshal(records)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal statistics")
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return errors.Wrap(err, "failed to write statistics")
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return errors.Wrap(err, "failed to replace statistics")
+	}
+
+	return nil
+}
+
+func (s *Store) flusher(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.flush(); err != nil && s.logger != nil {
+				s.logger.Error("failed to save statistics", zap.String("path", s.path), zap.Error(err))
+			}
+		}
+	}
+}
+
+// Close stops the periodic saving and saves the statistics a final time.
+func (s *Store) Close() error {
+	s.stop()
+	<-s.done
+
+	return s.flush()
+}
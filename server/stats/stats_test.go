@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+
+	s, err := NewStore(path, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Add("repo", "media", 100)
+	s.Add("repo", "media", 50)
+	s.Add("repo", "other", 10)
+	s.AddClient("user", 70)
+	s.AddClient("", 30) // unauthenticated
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = NewStore(path, 0, nil) // reload
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	days := s.Media("repo", "media")
+	if len(days) != 1 || days[0].Bytes != 150 {
+		t.Fatalf("expected 150 bytes on a single day, got %v", days)
+	}
+	if today := time.Now().Format(DayLayout); days[0].Date.Format(DayLayout) != today {
+		t.Errorf("expected day %s, got %s", today, days[0].Date.Format(DayLayout))
+	}
+
+	clients := s.Clients()
+	if len(clients) != 1 || clients[0] != "user" {
+		t.Fatalf("expected a single client, got %v", clients)
+	}
+	if days := s.Client("user", time.Now().AddDate(0, 0, -1)); len(days) != 1 || days[0].Bytes != 70 {
+		t.Errorf("expected 70 bytes on a single day, got %v", days)
+	}
+	if days := s.Client("user", time.Now().AddDate(0, 0, 1)); len(days) != 0 {
+		t.Errorf("expected no days since tomorrow, got %v", days)
+	}
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// NewRedirectHandler creates a handler redirecting requests to HTTPS, to the same host and the port of the HTTPS listener address.
+func NewRedirectHandler(httpsAddr string) http.Handler {
+	_, port, err := net.SplitHostPort(httpsAddr)
+	if err != nil || port == "443" { // default port, omitted
+		port = ""
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil { // no port
+			host = strings.TrimSuffix(strings.TrimPrefix(r.Host, "["), "]")
+		}
+		if port != "" {
+			host = net.JoinHostPort(host, port)
+		} else if strings.Contains(host, ":") { // IPv6 literal
+			host = "[" + host + "]"
+		}
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
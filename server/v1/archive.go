@@ -0,0 +1,126 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/server/api/v1"
+	"go.uber.org/zap"
+)
+
+// archiveStatuses are the archive statuses mapped to their REST representation.
+var archiveStatuses = map[repo.ArchiveStatus]v1.ArchiveStatus{
+	repo.ArchiveStatusNone:      v1.ArchiveStatusNone,
+	repo.ArchiveStatusArchiving: v1.ArchiveStatusArchiving,
+	repo.ArchiveStatusArchived:  v1.ArchiveStatusArchived,
+	repo.ArchiveStatusRestoring: v1.ArchiveStatusRestoring,
+}
+
+// moveArchived queues a job archiving or restoring the file of media in a mutable repository,
+// the unfinished job is returned if the media is being archived or restored already.
+func (s *Server) moveArchived(mr repo.MutableRepository, mediaId string, restore bool) (*jobs.Job, error) {
+	var (
+		type_ = jobs.TypeArchive
+		move  = mr.Archive
+	)
+	if restore {
+		type_, move = jobs.TypeRestore, mr.Restore
+	}
+
+	return s.keyed.Enqueue(jobKey("archive", mr.ID(), mediaId), type_, mr.ID(), mediaId, func(ctx context.Context) (media.Media, error) {
+		return nil, move(ctx, mediaId)
+	})
+}
+
+// restoreArchived queues a job restoring the file of archived media, if the repository is mutable.
+// Returns whether the media is archived, its file is missing from the repository until the job finishes then.
+func (s *Server) restoreArchived(ctx context.Context, rp repo.Repository, mediaId string) bool {
+	if rp.ArchiveStatus(mediaId) == repo.ArchiveStatusNone {
+		return false
+	}
+
+	if mr := rp.Mutable(); mr != nil {
+		if _, err := s.moveArchived(mr, mediaId, true); err != nil {
+			if logger := s.log(ctx, rp.ID(), mediaId); logger != nil {
+				logger.Warn("failed to queue restore of archived media", zap.Error(err))
+			}
+		}
+	}
+
+	return true
+}
+
+func (s *Server) GetRepoMediaArchive(_ context.Context, request v1.GetRepoMediaArchiveRequestObject) (v1.GetRepoMediaArchiveResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaArchive400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if rp.Get(request.MediaId) == nil {
+		return v1.GetRepoMediaArchive400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	res := v1.ArchiveState{Status: archiveStatuses[rp.ArchiveStatus(request.MediaId)]}
+
+	if j := s.keyed.Get(jobKey("archive", rp.ID(), request.MediaId)); j != nil {
+		j0 := s.wrapJob(j)
+		res.Job = &j0
+	}
+
+	return v1.GetRepoMediaArchive200JSONResponse(res), nil
+}
+
+func (s *Server) ArchiveRepoMedia(_ context.Context, request v1.ArchiveRepoMediaRequestObject) (v1.ArchiveRepoMediaResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.ArchiveRepoMedia400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if !rp.Capabilities().Has(repo.CapabilityArchive) {
+		return v1.ArchiveRepoMedia400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'archive' capability"}), nil
+	}
+	if rp.Get(request.MediaId) == nil {
+		return v1.ArchiveRepoMedia400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+	if rp.ArchiveStatus(request.MediaId) == repo.ArchiveStatusArchived {
+		return v1.ArchiveRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "media is archived already"}), nil
+	}
+
+	j, err := s.moveArchived(rp.Mutable(), request.MediaId, false)
+	if err != nil {
+		if errors.Is(err, jobs.ErrQueueFull) {
+			return v1.ArchiveRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "job queue is full"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to queue job")
+	}
+
+	return v1.ArchiveRepoMedia202JSONResponse(s.wrapJob(j)), nil
+}
+
+func (s *Server) RestoreRepoMedia(_ context.Context, request v1.RestoreRepoMediaRequestObject) (v1.RestoreRepoMediaResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.RestoreRepoMedia400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if !rp.Capabilities().Has(repo.CapabilityArchive) {
+		return v1.RestoreRepoMedia400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'archive' capability"}), nil
+	}
+	if rp.Get(request.MediaId) == nil {
+		return v1.RestoreRepoMedia400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+	if rp.ArchiveStatus(request.MediaId) == repo.ArchiveStatusNone {
+		return v1.RestoreRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "media is not archived"}), nil
+	}
+
+	j, err := s.moveArchived(rp.Mutable(), request.MediaId, true)
+	if err != nil {
+		if errors.Is(err, jobs.ErrQueueFull) {
+			return v1.RestoreRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "job queue is full"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to queue job")
+	}
+
+	return v1.RestoreRepoMedia202JSONResponse(s.wrapJob(j)), nil
+}
@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/server/api/v1"
+	"golang.org/x/text/language"
+)
+
+func (s *Server) GetRepoMediaAudioTracks(_ context.Context, request v1.GetRepoMediaAudioTracksRequestObject) (v1.GetRepoMediaAudioTracksResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaAudioTracks400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	audioTracks, err := rp.AudioTracks(request.MediaId)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list audio tracks")
+	}
+	if audioTracks == nil {
+		return v1.GetRepoMediaAudioTracks400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	tracks := make([]v1.AudioTrack, len(audioTracks))
+	for i, track := range audioTracks {
+		tracks[i] = s.wrapAudioTrack(track)
+	}
+
+	return v1.GetRepoMediaAudioTracks200JSONResponse(tracks), nil
+}
+
+func (s *Server) wrapAudioTrack(track *media.AudioTrack) v1.AudioTrack {
+	var lang *string
+	if track.Language != language.Und {
+		lang = makeOptString(track.Language.String())
+	}
+
+	return v1.AudioTrack{
+		Stream:   track.Stream,
+		Codec:    track.Codec,
+		Language: lang,
+		Title:    makeOptString(track.Title),
+	}
+}
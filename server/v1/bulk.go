@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/archive"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/katana/server/api/v1"
+	"github.com/katana-project/katana/server/auth"
+	"go.uber.org/multierr"
+	"golang.org/x/exp/slices"
+	"strings"
+)
+
+// bulkOp is an operation applied to each media of a bulk job.
+type bulkOp func(ctx context.Context, m media.Media) error
+
+func (s *Server) BulkRepoMedia(ctx context.Context, request v1.BulkRepoMediaRequestObject) (v1.BulkRepoMediaResponseObject, error) {
+	rp := s.Repo(request.Id)
+	if rp == nil {
+		return v1.BulkRepoMedia400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	body := request.Body
+	if len(body.MediaIds) == 0 {
+		return v1.BulkRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "no media IDs"}), nil
+	}
+
+	mr := rp.Mutable()
+	if mr == nil && body.Action != v1.MarkWatched && body.Action != v1.MarkUnwatched {
+		return v1.BulkRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "repository is not mutable"}), nil
+	}
+
+	var (
+		op   bulkOp
+		user = auth.User(ctx)
+	)
+	switch body.Action {
+	case v1.AddGenre, v1.RemoveGenre:
+		genre := strings.TrimSpace(derefString(body.Genre))
+		if genre == "" {
+			return v1.BulkRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "missing genre"}), nil
+		}
+
+		op = s.bulkGenre(mr, genre, body.Action == v1.AddGenre)
+	case v1.MarkWatched, v1.MarkUnwatched:
+		watched := body.Action == v1.MarkWatched
+		op = func(_ context.Context, m media.Media) error {
+			s.playback.Set(rp.ID(), user, m.ID(), 0, watched)
+			return nil
+		}
+	case v1.RefreshMetadata:
+		op = func(_ context.Context, m media.Media) error {
+			_, err := s.refreshMedia(mr, m)
+			return err
+		}
+	case v1.Trash:
+		bin, ok := s.trash[rp.ID()]
+		if !ok {
+			return v1.BulkRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "repository has no trash"}), nil
+		}
+
+		op = func(_ context.Context, m media.Media) error {
+			if status := mr.ArchiveStatus(m.ID()); status != repo.ArchiveStatusNone {
+				return &archive.ErrBusy{ID: m.ID(), Status: status} // the file is in cold storage
+			}
+
+			return bin.Put(m)
+		}
+	default:
+		return v1.BulkRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "unknown action " + string(body.Action)}), nil
+	}
+
+	var (
+		ids    = slices.Clone(body.MediaIds)
+		logger = s.log(ctx, rp.ID(), "") // jobs outlive the request, carry its logger over
+	)
+	j, err := s.jobs.Enqueue(jobs.TypeBulk, rp.ID(), "", func(ctx context.Context) (_ media.Media, err error) {
+		ctx = repo.WithLogger(ctx, logger)
+		if mr != nil { // persisted once at the end
+			mr.BeginBatch()
+			defer func() {
+				if err0 := mr.Commit(); err0 != nil {
+					err = multierr.Append(err, errors.Wrap(err0, "failed to persist media"))
+				}
+			}()
+		}
+
+		for i, id := range ids {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			if m := rp.Get(id); m != nil {
+				jobs.ReportOutcome(ctx, id, op(ctx, m))
+			} else {
+				jobs.ReportOutcome(ctx, id, &repo.ErrMediaNotFound{ID: id, Repo: rp.ID()})
+			}
+			repo.ReportProgress(ctx, repo.Progress{Processed: int64(i + 1), Total: int64(len(ids))})
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		if errors.Is(err, jobs.ErrQueueFull) {
+			return v1.BulkRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "job queue is full"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to queue job")
+	}
+
+	return v1.BulkRepoMedia202JSONResponse(s.wrapJob(j)), nil
+}
+
+// bulkGenre returns an operation adding a genre to media or removing it, genres of series are changed for all their episodes.
+func (s *Server) bulkGenre(mr repo.MutableRepository, genre string, add bool) bulkOp {
+	return func(_ context.Context, m media.Media) error {
+		msm, ok := m.Meta().(meta.MovieOrSeriesMetadata)
+		if em, isEpisode := m.Meta().(meta.EpisodeMetadata); isEpisode {
+			msm, ok = em.Series(), em.Series() != nil
+		}
+		if !ok {
+			return errors.New("media has no genres")
+		}
+
+		genres := slices.DeleteFunc(slices.Clone(msm.Genres()), func(g string) bool { return strings.EqualFold(g, genre) })
+		if add {
+			genres = append(genres, genre)
+		}
+
+		targets := []media.Media{m}
+		if key := seriesKey(m); key != "" { // the series' genres are shared by its episodes
+			targets = targets[:0]
+			for _, item := range mr.Items() {
+				if seriesKey(item) == key {
+					targets = append(targets, item)
+				}
+			}
+		}
+
+		for _, target := range targets {
+			if err := mr.Update(media.NewMedia(target.ID(), target.Path(), meta.WithGenres(target.Meta(), genres), target.Format())); err != nil {
+				var notFound *repo.ErrMediaNotFound
+				if !errors.As(err, &notFound) || target.ID() == m.ID() { // others removed in the meantime
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,61 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/server/api/v1"
+)
+
+// wrapCacheUsage converts cache usage to its REST representation.
+func wrapCacheUsage(u *repo.CacheUsage) v1.CacheUsage {
+	res := v1.CacheUsage{Files: u.Files, Bytes: u.Bytes}
+	if u.MaxBytes > 0 {
+		res.MaxBytes = &u.MaxBytes
+	}
+	if u.MaxAge > 0 {
+		maxAge := int64(u.MaxAge.Seconds())
+		res.MaxAge = &maxAge
+	}
+
+	return res
+}
+
+// hasCache checks whether a repository has a cache of remuxed or transcoded files.
+func hasCache(rp repo.Repository) bool {
+	return rp.Capabilities().Has(repo.CapabilityRemux) || rp.Capabilities().Has(repo.CapabilityTranscode)
+}
+
+func (s *Server) GetRepoCache(_ context.Context, request v1.GetRepoCacheRequestObject) (v1.GetRepoCacheResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoCache400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if !hasCache(rp) {
+		return v1.GetRepoCache400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'remux' and 'transcode' capabilities"}), nil
+	}
+
+	usage, err := rp.CacheUsage()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cache usage")
+	}
+
+	return v1.GetRepoCache200JSONResponse(wrapCacheUsage(usage)), nil
+}
+
+func (s *Server) PurgeRepoCache(_ context.Context, request v1.PurgeRepoCacheRequestObject) (v1.PurgeRepoCacheResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.PurgeRepoCache400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if !hasCache(rp) {
+		return v1.PurgeRepoCache400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'remux' and 'transcode' capabilities"}), nil
+	}
+
+	usage, err := rp.PurgeCache()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to purge cache")
+	}
+
+	return v1.PurgeRepoCache200JSONResponse(wrapCacheUsage(usage)), nil
+}
@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/katana/server/api/v1"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"strings"
+	"time"
+)
+
+const (
+	// calendarDays is the default number of days listed after the first day of a calendar range.
+	calendarDays = 30
+	// calendarMaxDays is the maximum number of days listed in a calendar range, it bounds the requests made to metadata sources.
+	calendarMaxDays = 366
+)
+
+// calendarSeries is a series present in a repository, with the episodes in it.
+type calendarSeries struct {
+	meta     meta.Metadata
+	episodes map[[2]int]media.Media // [season, episode] -> media
+}
+
+func (s *Server) GetCalendar(ctx context.Context, request v1.GetCalendarRequestObject) (v1.GetCalendarResponseObject, error) {
+	from := time.Now().UTC().Truncate(24 * time.Hour)
+	if request.Params.From != nil {
+		var err error
+		if from, err = time.Parse(time.DateOnly, *request.Params.From); err != nil {
+			return v1.GetCalendar400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "invalid from date"}), nil
+		}
+	}
+
+	to := from.AddDate(0, 0, calendarDays)
+	if request.Params.To != nil {
+		var err error
+		if to, err = time.Parse(time.DateOnly, *request.Params.To); err != nil {
+			return v1.GetCalendar400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "invalid to date"}), nil
+		}
+	}
+	if to.Before(from) || to.After(from.AddDate(0, 0, calendarMaxDays)) {
+		return v1.GetCalendar400JSONResponse(v1.Error{
+			Type:        v1.BadRequest,
+			Description: fmt.Sprintf("date range must not be reversed or longer than %d days", calendarMaxDays),
+		}), nil
+	}
+
+	repos := maps.Values(s.repos)
+	if request.Params.RepoId != nil {
+		rp := s.Repo(*request.Params.RepoId)
+		if rp == nil {
+			return v1.GetCalendar400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+		}
+
+		repos = []repo.Repository{rp}
+	}
+
+	return v1.GetCalendar200JSONResponse(s.calendarEpisodes(ctx, repos, from, to)), nil
+}
+
+// calendarEpisodes returns the episodes of the series in repositories airing in a date range (calendar),
+// sorted by the air date, series title and episode number.
+func (s *Server) calendarEpisodes(ctx context.Context, repos []repo.Repository, from, to time.Time) []v1.CalendarEpisode {
+	slices.SortFunc(repos, func(a, b repo.Repository) int {
+		return strings.Compare(a.ID(), b.ID())
+	})
+
+	episodes := make([]v1.CalendarEpisode, 0)
+	for _, rp := range repos {
+		episodes = append(episodes, s.calendar(ctx, rp, from, to)...)
+	}
+	slices.SortStableFunc(episodes, func(a, b v1.CalendarEpisode) int {
+		if c := strings.Compare(a.AirDate, b.AirDate); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Series, b.Series); c != 0 {
+			return c
+		}
+		if a.Season != b.Season {
+			return a.Season - b.Season
+		}
+
+		return a.Episode - b.Episode
+	})
+
+	return episodes
+}
+
+// calendar returns the episodes of the series in a repository airing in a date range.
+// Air dates are asked from the repository's metadata source, the episodes in the repository are listed by their release date
+// if it doesn't know them.
+func (s *Server) calendar(ctx context.Context, rp repo.Repository, from, to time.Time) []v1.CalendarEpisode {
+	series := make(map[string]*calendarSeries) // series key -> series
+	for _, m := range rp.Items() {
+		key := seriesKey(m)
+		if key == "" {
+			continue
+		}
+
+		em := m.Meta().(meta.EpisodeMetadata)
+		cs, ok := series[key]
+		if !ok {
+			cs = &calendarSeries{meta: em.Series(), episodes: make(map[[2]int]media.Media)}
+			series[key] = cs
+		}
+		cs.episodes[[2]int{em.Season(), em.Episode()}] = m
+	}
+
+	var episodes []v1.CalendarEpisode
+	for _, cs := range series {
+		airing, err := meta.Schedule(rp.Source(), cs.meta, from, to)
+		if err != nil {
+			if logger := s.log(ctx, rp.ID(), ""); logger != nil {
+				logger.Warn("failed to get series schedule", zap.String("series", cs.meta.Title()), zap.Error(err))
+			}
+		}
+		if airing == nil { // unknown, list the episodes in the repository
+			for _, m := range cs.episodes {
+				em := m.Meta().(meta.EpisodeMetadata)
+				if rd := em.ReleaseDate(); !rd.Before(from) && !rd.After(to) {
+					airing = append(airing, &meta.AiringEpisode{Title: em.Title(), Season: em.Season(), Episode: em.Episode(), AirDate: rd})
+				}
+			}
+		}
+
+		for _, ae := range airing {
+			var mediaId string
+			if m, ok := cs.episodes[[2]int{ae.Season, ae.Episode}]; ok {
+				mediaId = m.ID()
+			}
+
+			episodes = append(episodes, v1.CalendarEpisode{
+				RepoId:     rp.ID(),
+				Series:     cs.meta.Title(),
+				Title:      makeOptString(ae.Title),
+				Season:     ae.Season,
+				Episode:    ae.Episode,
+				AirDate:    ae.AirDate.Format(time.DateOnly),
+				Downloaded: mediaId != "",
+				MediaId:    makeOptString(mediaId),
+			})
+		}
+	}
+
+	return episodes
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/server/api/v1"
+)
+
+func (s *Server) GetRepoMediaChapters(_ context.Context, request v1.GetRepoMediaChaptersRequestObject) (v1.GetRepoMediaChaptersResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaChapters400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	mediaChapters, err := rp.Chapters(request.MediaId)
+	if err != nil {
+		var euf *repo.ErrUnsupportedFormat
+		if errors.As(err, &euf) {
+			return v1.GetRepoMediaChapters501JSONResponse(v1.Error{Type: v1.Unsupported, Description: "chapters can't be read by the conversion backend"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to list chapters")
+	}
+	if mediaChapters == nil {
+		return v1.GetRepoMediaChapters400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	chapters := make([]v1.Chapter, len(mediaChapters))
+	for i, chapter := range mediaChapters {
+		chapters[i] = s.wrapChapter(chapter)
+	}
+
+	return v1.GetRepoMediaChapters200JSONResponse(chapters), nil
+}
+
+func (s *Server) wrapChapter(chapter *media.Chapter) v1.Chapter {
+	return v1.Chapter{
+		Title: makeOptString(chapter.Title),
+		Start: chapter.Start.Seconds(),
+		End:   chapter.End.Seconds(),
+	}
+}
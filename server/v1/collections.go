@@ -0,0 +1,239 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/server/api/v1"
+	"github.com/katana-project/katana/server/auth"
+	"github.com/katana-project/katana/server/collection"
+	"strings"
+)
+
+func (s *Server) GetCollections(ctx context.Context, _ v1.GetCollectionsRequestObject) (v1.GetCollectionsResponseObject, error) {
+	collections := s.colls.All()
+
+	res := make([]v1.Collection, len(collections))
+	for i, c := range collections {
+		var err error
+		if res[i], err = s.wrapCollection(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+
+	return v1.GetCollections200JSONResponse(res), nil
+}
+
+func (s *Server) CreateCollection(ctx context.Context, request v1.CreateCollectionRequestObject) (v1.CreateCollectionResponseObject, error) {
+	items, rules, err := s.makeCollectionItems(request.Body)
+	if err != nil {
+		return v1.CreateCollection400JSONResponse(v1.Error{Type: v1.BadRequest, Description: err.Error()}), nil
+	}
+
+	c, err := s.colls.Create(strings.TrimSpace(request.Body.Name), derefString(request.Body.Description), items, rules)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create collection")
+	}
+
+	res, err := s.wrapCollection(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return v1.CreateCollection201JSONResponse(res), nil
+}
+
+func (s *Server) GetCollectionById(ctx context.Context, request v1.GetCollectionByIdRequestObject) (v1.GetCollectionByIdResponseObject, error) {
+	c := s.colls.Get(request.CollectionId)
+	if c == nil {
+		return v1.GetCollectionById400JSONResponse(v1.Error{Type: v1.NotFound, Description: "collection not found"}), nil
+	}
+
+	res, err := s.wrapCollection(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return v1.GetCollectionById200JSONResponse(res), nil
+}
+
+func (s *Server) UpdateCollection(ctx context.Context, request v1.UpdateCollectionRequestObject) (v1.UpdateCollectionResponseObject, error) {
+	items, rules, err := s.makeCollectionItems(request.Body)
+	if err != nil {
+		return v1.UpdateCollection400JSONResponse(v1.Error{Type: v1.BadRequest, Description: err.Error()}), nil
+	}
+
+	c, err := s.colls.Update(request.CollectionId, strings.TrimSpace(request.Body.Name), derefString(request.Body.Description), items, rules)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update collection")
+	}
+	if c == nil {
+		return v1.UpdateCollection400JSONResponse(v1.Error{Type: v1.NotFound, Description: "collection not found"}), nil
+	}
+
+	res, err := s.wrapCollection(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return v1.UpdateCollection200JSONResponse(res), nil
+}
+
+func (s *Server) DeleteCollection(_ context.Context, request v1.DeleteCollectionRequestObject) (v1.DeleteCollectionResponseObject, error) {
+	ok, err := s.colls.Delete(request.CollectionId)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to delete collection")
+	}
+	if !ok {
+		return v1.DeleteCollection400JSONResponse(v1.Error{Type: v1.NotFound, Description: "collection not found"}), nil
+	}
+
+	return v1.DeleteCollection204Response{}, nil
+}
+
+// makeCollectionItems validates a collection request and makes references of its items and its rules, nil if it isn't smart.
+// Repository aliases are resolved, so that the references survive alias changes.
+func (s *Server) makeCollectionItems(body *v1.CollectionRequest) ([]collection.Ref, *collection.Rules, error) {
+	if strings.TrimSpace(body.Name) == "" {
+		return nil, nil, errors.New("blank collection name")
+	}
+
+	var reqItems []v1.MediaRef
+	if body.Items != nil {
+		reqItems = *body.Items
+	}
+
+	var rules *collection.Rules
+	if body.Rules != nil {
+		if len(reqItems) > 0 {
+			return nil, nil, errors.New("smart collections can't have items")
+		}
+
+		rules = makeCollectionRules(body.Rules)
+		if err := rules.Validate(); err != nil {
+			return nil, nil, errors.Wrap(err, "invalid rules")
+		}
+	}
+
+	items := make([]collection.Ref, len(reqItems))
+	for i, item := range reqItems {
+		rp := s.Repo(item.RepoId)
+		if rp == nil {
+			return nil, nil, fmt.Errorf("repository %s not found", item.RepoId)
+		}
+		if rp.Get(item.MediaId) == nil {
+			return nil, nil, fmt.Errorf("media %s not found in repository %s", item.MediaId, item.RepoId)
+		}
+
+		items[i] = collection.Ref{Repo: rp.ID(), Media: item.MediaId}
+	}
+
+	return items, rules, nil
+}
+
+// makeCollectionRules translates CollectionRules to collection.Rules.
+func makeCollectionRules(r *v1.CollectionRules) *collection.Rules {
+	rules := &collection.Rules{
+		Any:        derefBool(r.Any),
+		Conditions: make([]collection.Condition, len(r.Conditions)),
+		Descending: derefBool(r.Descending),
+		Limit:      derefInt(r.Limit),
+	}
+	for i, c := range r.Conditions {
+		rules.Conditions[i] = collection.Condition{Field: collection.Field(c.Field), Op: collection.Op(c.Op), Value: c.Value}
+	}
+	if r.Sort != nil {
+		rules.Sort = collection.SortKey(*r.Sort)
+	}
+
+	return rules
+}
+
+// wrapCollectionRules translates collection.Rules to CollectionRules, returns nil for nil rules.
+func wrapCollectionRules(r *collection.Rules) *v1.CollectionRules {
+	if r == nil {
+		return nil
+	}
+
+	rules := &v1.CollectionRules{
+		Any:        &r.Any,
+		Conditions: make([]v1.RuleCondition, len(r.Conditions)),
+		Descending: &r.Descending,
+		Limit:      &r.Limit,
+	}
+	for i, c := range r.Conditions {
+		rules.Conditions[i] = v1.RuleCondition{Field: v1.RuleField(c.Field), Op: v1.RuleOp(c.Op), Value: c.Value}
+	}
+	if r.Sort != "" {
+		sort := v1.CollectionSortKey(r.Sort)
+		rules.Sort = &sort
+	}
+
+	return rules
+}
+
+// selectCollection selects the media of a smart collection across all repositories,
+// the watched state of media is the requesting user's.
+func (s *Server) selectCollection(ctx context.Context, rules *collection.Rules) []*collection.Subject {
+	var (
+		user     = auth.User(ctx)
+		subjects []*collection.Subject
+	)
+	for _, r := range s.repos {
+		states := s.playback.States(r.ID(), user)
+		for _, m := range r.Items() {
+			subjects = append(subjects, &collection.Subject{
+				Repo:    r.ID(),
+				Media:   m,
+				Tags:    r.Tags(m.ID()),
+				Watched: states[m.ID()].Watched,
+			})
+		}
+	}
+
+	return rules.Select(subjects)
+}
+
+func (s *Server) wrapCollection(ctx context.Context, c *collection.Collection) (v1.Collection, error) {
+	lang := requestLang(ctx)
+
+	var items []v1.CollectionItem
+	if c.Rules != nil {
+		selected := s.selectCollection(ctx, c.Rules)
+
+		items = make([]v1.CollectionItem, len(selected))
+		for i, subject := range selected {
+			wm, err := s.wrapMedia(subject.Repo, subject.Media, WrapModeBasicImages, lang)
+			if err != nil {
+				return v1.Collection{}, errors.Wrap(err, "failed to wrap media")
+			}
+
+			items[i] = v1.CollectionItem{RepoId: subject.Repo, MediaId: subject.Media.ID(), Media: &wm}
+		}
+	} else {
+		items = make([]v1.CollectionItem, len(c.Items))
+		for i, ref := range c.Items {
+			items[i] = v1.CollectionItem{RepoId: ref.Repo, MediaId: ref.Media}
+			if rp := s.Repo(ref.Repo); rp != nil {
+				if m := rp.Get(ref.Media); m != nil {
+					wm, err := s.wrapMedia(ref.Repo, m, WrapModeBasicImages, lang)
+					if err != nil {
+						return v1.Collection{}, errors.Wrap(err, "failed to wrap media")
+					}
+
+					items[i].Media = &wm
+				}
+			}
+		}
+	}
+
+	return v1.Collection{
+		Id:          c.ID,
+		Name:        c.Name,
+		Description: makeOptString(c.Description),
+		Items:       items,
+		Rules:       wrapCollectionRules(c.Rules),
+		Created:     c.Created,
+		Updated:     c.Updated,
+	}, nil
+}
@@ -1,5 +1,12 @@
 package v1
 
+import (
+	"encoding/json"
+	"github.com/katana-project/katana/server/api/v1"
+	"net/http"
+	"time"
+)
+
 // makeOptString converts a string to its pointer if it's not a zero value.
 func makeOptString(v string) *string {
 	if v == "" {
@@ -8,6 +15,22 @@ func makeOptString(v string) *string {
 	return &v
 }
 
+// makeOptTime converts a time to its pointer if it's not a zero value.
+func makeOptTime(v time.Time) *time.Time {
+	if v.IsZero() {
+		return nil
+	}
+	return &v
+}
+
+// makeOptFloat converts a float to its pointer if it's not a zero value.
+func makeOptFloat(v float32) *float32 {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
 // makeOptArray converts an array to its pointer if it's not empty.
 func makeOptArray[T any](a []T) *[]T {
 	if len(a) == 0 {
@@ -15,3 +38,51 @@ func makeOptArray[T any](a []T) *[]T {
 	}
 	return &a
 }
+
+// makeArray converts a nil array to an empty one, for it to be serialized as an empty JSON array.
+func makeArray[T any](a []T) []T {
+	if a == nil {
+		return []T{}
+	}
+	return a
+}
+
+// derefString converts a string pointer to its value, nil is converted to a zero value.
+func derefString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+// derefInt converts an int pointer to its value, nil is converted to a zero value.
+func derefInt(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// derefSeconds converts a pointer to a number of seconds to a duration, nil is converted to a zero value.
+func derefSeconds(v *float64) time.Duration {
+	if v == nil {
+		return 0
+	}
+	return time.Duration(*v * float64(time.Second))
+}
+
+// derefBool converts a bool pointer to its value, nil is converted to a zero value.
+func derefBool(v *bool) bool {
+	if v == nil {
+		return false
+	}
+	return *v
+}
+
+// writeError writes an error response outside a generated response type.
+func writeError(w http.ResponseWriter, code int, e v1.Error) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	return json.NewEncoder(w).Encode(e)
+}
@@ -0,0 +1,205 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/dash"
+	"github.com/katana-project/katana/server/api/v1"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+// SetDASH sets the transcode profiles of the MPEG-DASH representations of media, video renditions (media.StreamsVideo)
+// and audio (media.StreamsAudio) ones, all of them fragmented; nil disables DASH streaming.
+// Profiles needing settings the conversion backend doesn't honor are refused, DASH streaming stays disabled then.
+// It must be called before serving requests.
+func (s *Server) SetDASH(profiles []*media.TranscodeProfile) error {
+	if s.features != nil {
+		for _, p := range profiles {
+			if opt, ok := s.features.UnsupportedOption(p); ok {
+				return fmt.Errorf("'%s' unsupported by the conversion backend", opt)
+			}
+		}
+	}
+
+	s.dash = profiles
+	return nil
+}
+
+// dashProfiles returns the representation profiles of media for a request, video ones are watermarked for the requesting user.
+// Audio media only get audio representations.
+func (s *Server) dashProfiles(ctx context.Context, m media.Media) []*media.TranscodeProfile {
+	var (
+		wm  = s.watermark(ctx)
+		res []*media.TranscodeProfile
+	)
+	for _, p := range s.dash {
+		if p.Streams == media.StreamsVideo {
+			if m.Format().Audio() {
+				continue
+			}
+			if wm != nil {
+				wp := *p
+				wp.Watermark = wm
+				p = &wp
+			}
+		}
+
+		res = append(res, p)
+	}
+
+	return res
+}
+
+// queueDASH queues a job transcoding the DASH representations of media, the unfinished job is returned if they're being made already.
+// Progress is reported over all representations, in source bytes.
+func (s *Server) queueDASH(ctx context.Context, rp repo.Repository, mediaId string, profiles []*media.TranscodeProfile) (*jobs.Job, error) {
+	keys := make([]string, len(profiles))
+	for i, p := range profiles {
+		keys[i] = p.Key()
+	}
+
+	logger := s.log(ctx, rp.ID(), mediaId) // jobs outlive the request, carry its logger over
+	return s.keyed.Enqueue(jobKey("dash", rp.ID(), mediaId, strings.Join(keys, ",")), jobs.TypeDASH, rp.ID(), mediaId, func(ctx context.Context) (media.Media, error) {
+		for i, p := range profiles {
+			i := i
+			pctx := repo.WithProgress(repo.WithLogger(ctx, logger), func(pr repo.Progress) {
+				repo.ReportProgress(ctx, repo.Progress{Processed: int64(i)*pr.Total + pr.Processed, Total: int64(len(profiles)) * pr.Total})
+			})
+
+			if _, err := rp.Transcode(pctx, mediaId, p); err != nil {
+				return nil, errors.Wrapf(err, "failed to transcode representation %s", keys[i])
+			}
+		}
+
+		return nil, nil
+	})
+}
+
+func (s *Server) GetRepoMediaDashManifest(ctx context.Context, request v1.GetRepoMediaDashManifestRequestObject) (v1.GetRepoMediaDashManifestResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaDashManifest400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if !rp.Capabilities().Has(repo.CapabilityTranscode) || len(s.dash) == 0 {
+		return v1.GetRepoMediaDashManifest400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'transcode' capability"}), nil
+	}
+
+	m := rp.Get(request.MediaId)
+	if m == nil {
+		return v1.GetRepoMediaDashManifest400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	var (
+		profiles = s.dashProfiles(ctx, m)
+		reps     = make([]*dash.Representation, 0, len(profiles))
+		ready    = true
+	)
+	for _, p := range profiles {
+		tm, err := rp.Transcode(repo.WithCachedOnly(ctx), request.MediaId, p)
+		if err != nil {
+			var notReady *repo.ErrNotReady
+			if !errors.As(err, &notReady) {
+				return nil, errors.Wrap(err, "failed to get representation")
+			}
+
+			ready = false
+			break
+		}
+		if tm == nil { // removed in the meantime
+			return v1.GetRepoMediaDashManifest400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+		}
+
+		track, err := dash.ReadTrack(tm.Path())
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) { // evicted in the meantime
+				ready = false
+				break
+			}
+
+			return nil, errors.Wrap(err, "failed to read representation")
+		}
+
+		key := p.Key()
+		reps = append(reps, &dash.Representation{ID: key, URL: key, Track: track})
+	}
+
+	if !ready {
+		j, err := s.queueDASH(ctx, rp, request.MediaId, profiles)
+		if err != nil {
+			if errors.Is(err, jobs.ErrQueueFull) {
+				return v1.GetRepoMediaDashManifest400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "job queue is full"}), nil
+			}
+
+			return nil, errors.Wrap(err, "failed to queue job")
+		}
+
+		return v1.GetRepoMediaDashManifest202JSONResponse(s.wrapJob(j)), nil
+	}
+
+	var buf bytes.Buffer
+	if err := dash.WriteManifest(&buf, reps); err != nil {
+		return nil, errors.Wrap(err, "failed to write manifest")
+	}
+
+	return v1.GetRepoMediaDashManifest200ApplicationdashXmlResponse{Body: &buf, ContentLength: int64(buf.Len())}, nil
+}
+
+func (s *Server) GetRepoMediaDashRepresentation(ctx context.Context, request v1.GetRepoMediaDashRepresentationRequestObject) (v1.GetRepoMediaDashRepresentationResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaDashRepresentation400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if !rp.Capabilities().Has(repo.CapabilityTranscode) {
+		return v1.GetRepoMediaDashRepresentation400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'transcode' capability"}), nil
+	}
+
+	m := rp.Get(request.MediaId)
+	if m == nil {
+		return v1.GetRepoMediaDashRepresentation400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	var profile *media.TranscodeProfile
+	for _, p := range s.dashProfiles(ctx, m) {
+		if p.Key() == request.RepresentationId {
+			profile = p
+			break
+		}
+	}
+	if profile == nil {
+		return v1.GetRepoMediaDashRepresentation400JSONResponse(v1.Error{Type: v1.NotFound, Description: "representation not found"}), nil
+	}
+
+	tm, err := rp.Transcode(repo.WithCachedOnly(ctx), request.MediaId, profile)
+	if err != nil {
+		var notReady *repo.ErrNotReady
+		if errors.As(err, &notReady) {
+			return v1.GetRepoMediaDashRepresentation503JSONResponse{
+				Body:    v1.Error{Type: v1.NotReady, Description: "representation has not been made yet"},
+				Headers: v1.GetRepoMediaDashRepresentation503ResponseHeaders{RetryAfter: retryAfter},
+			}, nil
+		}
+
+		return nil, errors.Wrap(err, "failed to get representation")
+	}
+	if tm == nil { // removed in the meantime
+		return v1.GetRepoMediaDashRepresentation400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	mime := "video/mp4"
+	if profile.Streams == media.StreamsAudio {
+		mime = "audio/mp4"
+	}
+
+	return s.streamResp(rp, request.MediaId, tm.Path(), mime), nil
+}
+
+func (sr *streamResp) VisitGetRepoMediaDashRepresentationResponse(w http.ResponseWriter, r *http.Request) error {
+	return sr.writeResponse("inline", w, r)
+}
@@ -0,0 +1,24 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+)
+
+// ErrInvalidPath is an error about a media path unusable for adding media to a repository.
+type ErrInvalidPath struct {
+	// Path is the offending path.
+	Path string
+	// Reason is the reason of the path being invalid.
+	Reason string
+}
+
+// Error returns the string representation of the error.
+func (eip *ErrInvalidPath) Error() string {
+	return fmt.Sprintf("invalid path %s, %s", eip.Path, eip.Reason)
+}
+
+// Code returns the error code (errors.CodeBadRequest).
+func (eip *ErrInvalidPath) Code() errors.Code {
+	return errors.CodeBadRequest
+}
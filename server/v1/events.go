@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/event"
+	"github.com/katana-project/katana/server/api/v1"
+	"github.com/katana-project/katana/server/auth"
+	"net/http"
+	"time"
+)
+
+// eventKeepAlive is the period between comment lines sent to keep idle event streams open through proxies.
+const eventKeepAlive = 30 * time.Second
+
+// eventStreamResp is a response streaming events as Server-Sent Events, until the client disconnects or the subscription ends.
+type eventStreamResp struct {
+	sub    *event.Subscription
+	repoId string // empty for all repositories
+	user   string // the authenticated user, receives only the events addressed to them
+}
+
+func (esr *eventStreamResp) VisitGetEventsResponse(w http.ResponseWriter, r *http.Request) error {
+	defer esr.sub.Close()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // disable nginx response buffering
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return errors.Wrap(err, "failed to flush response")
+	}
+
+	ticker := time.NewTicker(eventKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return nil // client gone
+			}
+		case e, ok := <-esr.sub.C:
+			if !ok { // lagging behind or shutting down, the client reconnects
+				return nil
+			}
+			if esr.repoId != "" && e.RepoID != esr.repoId {
+				continue
+			}
+			if e.User != "" && e.User != esr.user {
+				continue
+			}
+
+			data, err := json.Marshal(wrapEvent(e))
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal event")
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+				return nil // client gone
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return nil
+		}
+	}
+}
+
+func (s *Server) GetEvents(ctx context.Context, request v1.GetEventsRequestObject) (v1.GetEventsResponseObject, error) {
+	var repoId string
+	if request.Params.RepoId != nil {
+		rp := s.Repo(*request.Params.RepoId)
+		if rp == nil {
+			return v1.GetEvents400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+		}
+
+		repoId = rp.ID()
+	}
+
+	return &eventStreamResp{sub: s.events.Subscribe(), repoId: repoId, user: auth.User(ctx)}, nil
+}
+
+// wrapEvent wraps an event in a REST representation.
+func wrapEvent(e event.Event) v1.Event {
+	e0 := v1.Event{
+		Type:    v1.EventType(e.Type),
+		RepoId:  e.RepoID,
+		MediaId: makeOptString(e.MediaID),
+		Time:    e.Time,
+	}
+	if e.JobID != "" {
+		e0.Job = &v1.EventJob{
+			Id:    e.JobID,
+			Type:  v1.JobType(e.JobType),
+			State: v1.JobState(e.JobState),
+		}
+	}
+
+	return e0
+}
@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/katana-project/katana/server/api/v1"
+	"github.com/katana-project/katana/server/auth"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exportHeader is the header row of CSV user data exports.
+var exportHeader = []string{"repo_id", "media_id", "title", "type", "year", "position", "watched", "updated_at", "hidden"}
+
+func (s *Server) ExportUserData(ctx context.Context, request v1.ExportUserDataRequestObject) (v1.ExportUserDataResponseObject, error) {
+	format := v1.Json
+	if request.Params.Format != nil {
+		format = *request.Params.Format
+	}
+	if format != v1.Json && format != v1.Csv {
+		return v1.ExportUserData400JSONResponse(v1.Error{Type: v1.BadRequest, Description: fmt.Sprintf("unknown format '%s'", format)}), nil
+	}
+
+	export := s.exportUserData(auth.User(ctx))
+	headers := v1.ExportUserData200ResponseHeaders{
+		ContentDisposition: fmt.Sprintf(`attachment; filename="katana-export-%s.%s"`, export.ExportedAt.Format(time.DateOnly), format),
+	}
+	if format == v1.Json {
+		return v1.ExportUserData200JSONResponse{Body: export, Headers: headers}, nil
+	}
+
+	b, err := exportCSV(export.Progress)
+	if err != nil {
+		return nil, err
+	}
+
+	return v1.ExportUserData200TextcsvResponse{Body: bytes.NewReader(b), Headers: headers, ContentLength: int64(len(b))}, nil
+}
+
+// exportUserData collects the playback states and followed series of a user across all repositories.
+func (s *Server) exportUserData(user string) v1.UserExport {
+	export := v1.UserExport{
+		User:           user,
+		ExportedAt:     time.Now().UTC(),
+		Progress:       make([]v1.ExportedProgress, 0),
+		FollowedSeries: make([]v1.ExportedSeries, 0),
+	}
+
+	repoIds := maps.Keys(s.repos)
+	slices.Sort(repoIds)
+	for _, repoId := range repoIds {
+		rp := s.repos[repoId]
+
+		states := s.playback.States(repoId, user)
+		mediaIds := maps.Keys(states)
+		slices.Sort(mediaIds)
+		for _, mediaId := range mediaIds {
+			state := states[mediaId]
+			p := v1.ExportedProgress{
+				RepoId:    repoId,
+				MediaId:   mediaId,
+				Position:  state.Position.Seconds(),
+				Watched:   state.Watched,
+				UpdatedAt: makeOptTime(state.Updated),
+				Hidden:    state.Hidden,
+			}
+			if m := rp.Get(mediaId); m != nil {
+				if mm := m.Meta(); mm != nil {
+					type_ := wrapMetaType(mm.Type())
+
+					p.Title, p.Type = makeOptString(mm.Title()), &type_
+					if rd := mm.ReleaseDate(); !rd.IsZero() {
+						year := rd.Year()
+						p.Year = &year
+					}
+				} else {
+					type_ := v1.MetadataTypeUnknown
+					name := filepath.Base(m.Path())
+
+					p.Title, p.Type = makeOptString(strings.TrimSuffix(name, filepath.Ext(name))), &type_
+				}
+			}
+
+			export.Progress = append(export.Progress, p)
+		}
+
+		series := maps.Keys(s.playback.FollowedSeries(repoId, user))
+		slices.Sort(series)
+		for _, key := range series {
+			export.FollowedSeries = append(export.FollowedSeries, v1.ExportedSeries{RepoId: repoId, Series: key})
+		}
+	}
+
+	return export
+}
+
+// exportCSV formats exported playback progress as CSV, a row per media after a header row.
+func exportCSV(progress []v1.ExportedProgress) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		w   = csv.NewWriter(&buf)
+	)
+	if err := w.Write(exportHeader); err != nil {
+		return nil, err
+	}
+	for _, p := range progress {
+		var type_, year, updated string
+		if p.Type != nil {
+			type_ = string(*p.Type)
+		}
+		if p.Year != nil {
+			year = strconv.Itoa(*p.Year)
+		}
+		if p.UpdatedAt != nil {
+			updated = p.UpdatedAt.Format(time.RFC3339)
+		}
+
+		err := w.Write([]string{
+			p.RepoId,
+			p.MediaId,
+			derefString(p.Title),
+			type_,
+			year,
+			strconv.FormatFloat(p.Position, 'f', 3, 64),
+			strconv.FormatBool(p.Watched),
+			updated,
+			strconv.FormatBool(p.Hidden),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/server/api/v1"
+	"go.uber.org/multierr"
+	"io/fs"
+	"net/http"
+	"os"
+)
+
+func (s *Server) GetRepoMediaExtras(_ context.Context, request v1.GetRepoMediaExtrasRequestObject) (v1.GetRepoMediaExtrasResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaExtras400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	extras, err := rp.Extras(request.MediaId)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list extras")
+	}
+	if extras == nil {
+		return v1.GetRepoMediaExtras400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	res := make([]v1.MediaExtra, len(extras))
+	for i, extra := range extras {
+		res[i] = v1.MediaExtra{Id: extra.ID, Kind: v1.MediaExtraKind(extra.Kind), Mime: extra.MIME}
+	}
+
+	return v1.GetRepoMediaExtras200JSONResponse(res), nil
+}
+
+func (s *Server) GetRepoMediaExtra(_ context.Context, request v1.GetRepoMediaExtraRequestObject) (v1.GetRepoMediaExtraResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaExtra400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	extras, err := rp.Extras(request.MediaId)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list extras")
+	}
+	if extras == nil {
+		return v1.GetRepoMediaExtra400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	for _, extra := range extras {
+		if extra.ID == request.ExtraId {
+			return &extraResp{extra: extra}, nil
+		}
+	}
+
+	return v1.GetRepoMediaExtra400JSONResponse(v1.Error{Type: v1.NotFound, Description: "extra not found"}), nil
+}
+
+// extraResp is a response streaming an extra file, conditional and range requests are handled by http.ServeContent.
+type extraResp struct {
+	extra *media.Extra
+}
+
+func (er *extraResp) VisitGetRepoMediaExtraResponse(w http.ResponseWriter, r *http.Request) (err error) {
+	f, err := os.Open(er.extra.Path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) { // rediscovered once the removal is noticed
+			return writeError(w, http.StatusGone, v1.Error{Type: v1.Gone, Description: "extra file not found"})
+		}
+
+		return errors.Wrap(err, "failed to open extra")
+	}
+	defer func() {
+		if err0 := f.Close(); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to close file"))
+		}
+	}()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return errors.Wrap(err, "failed to stat extra")
+	}
+
+	w.Header().Set("Content-Type", er.extra.MIME)
+	http.ServeContent(w, r, fi.Name(), fi.ModTime(), f)
+	return nil
+}
@@ -0,0 +1,119 @@
+package v1
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/server/api/v1"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// feedPastDays is the number of days before today listed in the calendar feed.
+	feedPastDays = 30
+	// feedDays is the number of days after today listed in the calendar feed.
+	feedDays = 180
+	// icsLineLength is the maximum length of an iCalendar content line in octets, longer lines are folded (RFC 5545, section 3.1).
+	icsLineLength = 75
+)
+
+// icsEscaper escapes iCalendar text values (RFC 5545, section 3.3.11).
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// HandleCalendarFeed handles getting the calendar (GetCalendar) as an iCalendar feed, for subscribing from calendar apps.
+// The feed lists the episodes airing from a month ago to half a year from now, of the repository in the "repoId" query parameter
+// or all repositories. It's not a part of the API specification, calendar apps authenticate with a feed token in the URL.
+func (s *Server) HandleCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	repos := maps.Values(s.repos)
+	if repoId := r.URL.Query().Get("repoId"); repoId != "" {
+		rp := s.Repo(repoId)
+		if rp == nil {
+			_ = writeError(w, http.StatusNotFound, v1.Error{Type: v1.NotFound, Description: "repository not found"})
+			return
+		}
+
+		repos = []repo.Repository{rp}
+	}
+
+	var (
+		today    = time.Now().UTC().Truncate(24 * time.Hour)
+		episodes = s.calendarEpisodes(r.Context(), repos, today.AddDate(0, 0, -feedPastDays), today.AddDate(0, 0, feedDays))
+	)
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="katana.ics"`)
+	if err := writeICS(w, episodes, time.Now()); err != nil {
+		if logger := s.log(r.Context(), "", ""); logger != nil {
+			logger.Warn("failed to write calendar feed", zap.Error(err))
+		}
+	}
+}
+
+// writeICS writes calendar episodes as an iCalendar object of all-day events, stamped with the time of now.
+// The events are identified by the repository, series and episode, so that calendar apps update them in place.
+func writeICS(w io.Writer, episodes []v1.CalendarEpisode, now time.Time) error {
+	bw := bufio.NewWriter(w)
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	writeICSLine(bw, "BEGIN:VCALENDAR")
+	writeICSLine(bw, "VERSION:2.0")
+	writeICSLine(bw, "PRODID:-//katana-project//Katana//EN")
+	writeICSLine(bw, "CALSCALE:GREGORIAN")
+	writeICSLine(bw, "METHOD:PUBLISH")
+	writeICSLine(bw, "X-WR-CALNAME:Katana")
+	for _, e := range episodes {
+		airDate, err := time.Parse(time.DateOnly, e.AirDate)
+		if err != nil {
+			continue // formatted by calendar
+		}
+
+		var (
+			code    = fmt.Sprintf("S%02dE%02d", e.Season, e.Episode)
+			summary = e.Series + " " + code
+			status  = "Not downloaded yet."
+		)
+		if e.Title != nil && *e.Title != "" {
+			summary += " - " + *e.Title
+		}
+		if e.Downloaded {
+			status = "Downloaded."
+		}
+
+		writeICSLine(bw, "BEGIN:VEVENT")
+		writeICSLine(bw, "UID:"+icsEscaper.Replace(fmt.Sprintf("%s/%s/%s@katana", e.RepoId, e.Series, code)))
+		writeICSLine(bw, "DTSTAMP:"+stamp)
+		writeICSLine(bw, "DTSTART;VALUE=DATE:"+airDate.Format("20060102"))
+		writeICSLine(bw, "DTEND;VALUE=DATE:"+airDate.AddDate(0, 0, 1).Format("20060102"))
+		writeICSLine(bw, "SUMMARY:"+icsEscaper.Replace(summary))
+		writeICSLine(bw, "DESCRIPTION:"+icsEscaper.Replace(status))
+		writeICSLine(bw, "TRANSP:TRANSPARENT")
+		writeICSLine(bw, "END:VEVENT")
+	}
+	writeICSLine(bw, "END:VCALENDAR")
+
+	return bw.Flush()
+}
+
+// writeICSLine writes an iCalendar content line, folded into lines of at most icsLineLength octets without splitting characters.
+func writeICSLine(w *bufio.Writer, line string) {
+	limit := icsLineLength
+	for len(line) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(line[i]) {
+			i--
+		}
+
+		w.WriteString(line[:i])
+		w.WriteString("\r\n ") // continuation lines start with a space, counted in their length
+		line, limit = line[i:], icsLineLength-1
+	}
+
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
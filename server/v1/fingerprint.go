@@ -0,0 +1,27 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/server/api/v1"
+)
+
+func (s *Server) GetRepoFingerprint(_ context.Context, request v1.GetRepoFingerprintRequestObject) (v1.GetRepoFingerprintResponseObject, error) {
+	r := s.Repo(request.Id)
+	if r == nil {
+		return v1.GetRepoFingerprint400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	fp, err := repo.MakeFingerprint(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make fingerprint")
+	}
+
+	res := v1.RepoFingerprint{Hash: fp.Hash, Count: fp.Count}
+	if derefBool(request.Params.Items) {
+		res.Items = &fp.Items
+	}
+
+	return v1.GetRepoFingerprint200JSONResponse(res), nil
+}
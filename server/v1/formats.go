@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/mux"
+	"github.com/katana-project/katana/server/api/v1"
+	"strings"
+)
+
+// SetFormatSupport sets the availability of conversions of media formats by the media conversion backend (mux.Support),
+// all conversions are reported as unavailable if it's not set. It must be called before serving requests.
+func (s *Server) SetFormatSupport(formats []mux.FormatSupport) {
+	s.formats = formats
+}
+
+func (s *Server) GetFormats(_ context.Context, _ v1.GetFormatsRequestObject) (v1.GetFormatsResponseObject, error) {
+	formats := s.formats
+	if formats == nil { // no backend, nothing can be converted
+		for _, f := range media.Formats() {
+			formats = append(formats, mux.FormatSupport{Format: f})
+		}
+	}
+
+	res := make([]v1.FormatSupport, len(formats))
+	for i, fs := range formats {
+		res[i] = v1.FormatSupport{
+			Name:      strings.ToLower(fs.Format.Name),
+			Mime:      fs.Format.MIME,
+			Extension: fs.Format.Extension,
+			Audio:     fs.Format.Audio(),
+			Demux:     fs.Demux,
+			Remux:     fs.Remux,
+			Transcode: fs.Transcode,
+		}
+	}
+
+	return v1.GetFormats200JSONResponse(res), nil
+}
@@ -0,0 +1,125 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/server/api/v1"
+	"golang.org/x/exp/slices"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func (s *Server) GetRepoDirectory(_ context.Context, request v1.GetRepoDirectoryRequestObject) (v1.GetRepoDirectoryResponseObject, error) {
+	r := s.Repo(request.Id)
+	if r == nil {
+		return v1.GetRepoDirectory400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if health := r.Health(); health.Degraded {
+		return nil, &repo.ErrUnavailable{Path: r.Path(), Reason: health.Reason}
+	}
+
+	var path string
+	if request.Params.Path != nil {
+		path = *request.Params.Path
+	}
+
+	dirPath, err := resolveDir(r.Path(), path)
+	if err != nil { // invalid paths are client errors (ErrInvalidPath), see DefaultResponseErrorHandler
+		return nil, errors.Wrap(err, "failed to resolve directory path")
+	}
+
+	fi, err := os.Stat(dirPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return v1.GetRepoDirectory400JSONResponse(v1.Error{Type: v1.NotFound, Description: "directory not found"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to stat directory")
+	}
+	if !fi.IsDir() {
+		return v1.GetRepoDirectory400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "path is not a directory"}), nil
+	}
+
+	dirEntries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read directory")
+	}
+
+	entries := make([]v1.DirectoryEntry, 0, len(dirEntries))
+	for _, de := range dirEntries {
+		if strings.HasPrefix(de.Name(), ".") { // excluded from scans
+			continue
+		}
+
+		entryPath := filepath.Join(dirPath, de.Name())
+		fi, err := os.Stat(entryPath) // follow symlinks
+		if err != nil {
+			continue // broken symlinks and files removed meanwhile
+		}
+
+		relPath, _ := filepath.Rel(r.Path(), entryPath)
+		entry := v1.DirectoryEntry{
+			Name:       de.Name(),
+			Path:       filepath.ToSlash(relPath),
+			Type:       v1.File,
+			ModifiedAt: fi.ModTime(),
+		}
+		if fi.IsDir() {
+			entry.Type = v1.Directory
+		} else {
+			size := fi.Size()
+			entry.Size = &size
+
+			if m := r.Find(entryPath); m != nil {
+				entry.Indexed = true
+				entry.MediaId = makeOptString(m.ID())
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b v1.DirectoryEntry) int {
+		if a.Type != b.Type {
+			if a.Type == v1.Directory {
+				return -1
+			}
+			return 1
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	relDir, _ := filepath.Rel(r.Path(), dirPath)
+	if relDir == "." {
+		relDir = ""
+	}
+
+	return v1.GetRepoDirectory200JSONResponse(v1.DirectoryListing{
+		Path:    filepath.ToSlash(relDir),
+		Entries: entries,
+	}), nil
+}
+
+// resolveDir resolves a directory path relative to the repository root, returns its absolute path.
+// Unlike resolvePath, the root itself is allowed.
+func resolveDir(root, path string) (string, error) {
+	absPath := filepath.Join(root, filepath.FromSlash(path))
+
+	relPath, err := filepath.Rel(root, absPath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", &ErrInvalidPath{Path: path, Reason: "outside of repository root"}
+	}
+	if relPath == "." {
+		return absPath, nil
+	}
+	for _, elem := range strings.Split(relPath, string(filepath.Separator)) {
+		if strings.HasPrefix(elem, ".") {
+			return "", &ErrInvalidPath{Path: path, Reason: "dot-prefixed paths are excluded"}
+		}
+	}
+
+	return absPath, nil
+}
@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/identify"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/server/api/v1"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+)
+
+// SetIdentifier sets the identifier suggesting matches of media that couldn't be identified by their file names, nil disables it.
+// It's closed along with the server, it must be called before serving requests.
+func (s *Server) SetIdentifier(i *identify.Identifier) {
+	s.identifier = i
+}
+
+func wrapSuggestion(s *identify.Suggestion) v1.MatchSuggestion {
+	return v1.MatchSuggestion{
+		Source:      v1.SuggestionSource(s.Source),
+		Score:       s.Score,
+		Title:       s.Title,
+		Artists:     makeOptArray(s.Artists),
+		Album:       makeOptString(s.Album),
+		RecordingId: makeOptString(s.RecordingID),
+		RepoId:      makeOptString(s.RepoID),
+		MediaId:     makeOptString(s.MediaID),
+	}
+}
+
+func (s *Server) GetRepoMediaMetaSuggestions(ctx context.Context, request v1.GetRepoMediaMetaSuggestionsRequestObject) (v1.GetRepoMediaMetaSuggestionsResponseObject, error) {
+	if s.identifier == nil {
+		return v1.GetRepoMediaMetaSuggestions400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "identification is not enabled"}), nil
+	}
+
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaMetaSuggestions400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	m := rp.Get(request.MediaId)
+	if m == nil {
+		return v1.GetRepoMediaMetaSuggestions400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	suggestions, err := s.identifier.Suggest(ctx, rp, m, maps.Values(s.repos))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if logger := s.log(ctx, rp.ID(), m.ID()); logger != nil {
+			logger.Warn("failed to suggest matches", zap.Error(err))
+		}
+		return v1.GetRepoMediaMetaSuggestions400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "failed to read media"}), nil
+	}
+
+	res := make(v1.GetRepoMediaMetaSuggestions200JSONResponse, len(suggestions))
+	for i, suggestion := range suggestions {
+		res[i] = wrapSuggestion(suggestion)
+	}
+
+	return res, nil
+}
+
+func (s *Server) IdentifyRepo(ctx context.Context, request v1.IdentifyRepoRequestObject) (v1.IdentifyRepoResponseObject, error) {
+	if s.identifier == nil {
+		return v1.IdentifyRepo400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "identification is not enabled"}), nil
+	}
+
+	r := s.Repo(request.Id)
+	if r == nil {
+		return v1.IdentifyRepo400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	logger := s.log(ctx, r.ID(), "") // jobs outlive the request, carry its logger over
+	j, err := s.jobs.Enqueue(jobs.TypeIdentify, r.ID(), "", func(ctx context.Context) (media.Media, error) {
+		return nil, s.identifier.Hash(repo.WithLogger(ctx, logger), r)
+	})
+	if err != nil {
+		if errors.Is(err, jobs.ErrQueueFull) {
+			return v1.IdentifyRepo400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "job queue is full"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to queue job")
+	}
+
+	return v1.IdentifyRepo202JSONResponse(s.wrapJob(j)), nil
+}
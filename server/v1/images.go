@@ -0,0 +1,459 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/erni27/imcache"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/internal/sync"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/katana/server/api/v1"
+	"go.uber.org/zap"
+	"image"
+	"image/color"
+	_ "image/gif" // register decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	// imageCacheEntries is the maximum number of images (of any size) held in memory.
+	imageCacheEntries = 256
+	// maxImageSize is the maximum number of bytes read from an image file or a remote image.
+	maxImageSize = 32 << 20
+	// imageMaxAge is the number of seconds clients are allowed to cache images for.
+	imageMaxAge = 24 * 60 * 60
+)
+
+// imageCacheExp is the cache expiration period for images loaded into memory.
+var imageCacheExp = imcache.WithSlidingExpiration(30 * time.Minute)
+
+// ErrImageFetch is an error of a remote image request not being successful.
+type ErrImageFetch struct {
+	// URL is the remote image URL.
+	URL string
+	// StatusCode is the HTTP response status code.
+	StatusCode int
+}
+
+// Error returns the string representation of the error.
+func (eif *ErrImageFetch) Error() string {
+	return fmt.Sprintf("failed to fetch image %s, status code %d", eif.URL, eif.StatusCode)
+}
+
+// cachedImage is an encoded image held in memory.
+type cachedImage struct {
+	data []byte
+	mime string
+	etag string
+}
+
+// imageCache is an in-memory cache of original and resized images, keyed by image path and size.
+type imageCache struct {
+	mu      sync.KMutex // prevents loading the same image concurrently
+	entries *imcache.Cache[string, *cachedImage]
+	client  *http.Client
+}
+
+// newImageCache creates a new image cache.
+func newImageCache() *imageCache {
+	return &imageCache{
+		entries: imcache.New[string, *cachedImage](
+			imcache.WithMaxEntriesLimitOption[string, *cachedImage](imageCacheEntries, imcache.EvictionPolicyLRU),
+		),
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// get returns an image in the requested width (0 is the original size), loaded and resized if not cached.
+func (ic *imageCache) get(ctx context.Context, i meta.Image, width int) (*cachedImage, error) {
+	key := fmt.Sprintf("%s@%d", i.Path(), width)
+	if ci, ok := ic.entries.Get(key); ok {
+		return ci, nil
+	}
+
+	res, err := ic.mu.Do(key, func() (interface{}, error) {
+		if ci, ok := ic.entries.Get(key); ok { // loaded while waiting for the lock
+			return ci, nil
+		}
+
+		b, err := ic.load(ctx, i)
+		if err != nil {
+			return nil, err
+		}
+		if width > 0 {
+			if b, err = resizeImage(b, width); err != nil {
+				return nil, errors.Wrap(err, "failed to resize image")
+			}
+		}
+
+		sum := sha256.Sum256(b)
+		ci := &cachedImage{
+			data: b,
+			mime: http.DetectContentType(b),
+			etag: fmt.Sprintf(`"%x"`, sum[:16]),
+		}
+
+		ic.entries.Set(key, ci, imageCacheExp)
+		return ci, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(*cachedImage), nil
+}
+
+// load reads the original image data from the filesystem or a remote server.
+func (ic *imageCache) load(ctx context.Context, i meta.Image) (_ []byte, err error) {
+	var r io.ReadCloser
+	if i.Remote() {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, i.Path(), nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create request")
+		}
+
+		res, err := ic.client.Do(req)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch image")
+		}
+		if res.StatusCode != http.StatusOK {
+			_ = res.Body.Close()
+			return nil, &ErrImageFetch{URL: i.Path(), StatusCode: res.StatusCode}
+		}
+
+		r = res.Body
+	} else {
+		if r, err = os.Open(i.Path()); err != nil {
+			return nil, errors.Wrap(err, "failed to open image")
+		}
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(io.LimitReader(r, maxImageSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read image")
+	}
+	if len(b) > maxImageSize {
+		return nil, fmt.Errorf("image exceeds %d bytes", maxImageSize)
+	}
+
+	return b, nil
+}
+
+// resizeImage scales encoded image data down to a width, keeping the aspect ratio.
+// JPEG images are re-encoded as JPEG, other formats as PNG; images narrower than the width are returned as-is.
+func resizeImage(b []byte, width int) ([]byte, error) {
+	src, format, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode image")
+	}
+
+	bounds := src.Bounds()
+	if bounds.Dx() <= width {
+		return b, nil
+	}
+
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA64(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := bounds.Min.Y+y*bounds.Dy()/height, bounds.Min.Y+(y+1)*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			x0, x1 := bounds.Min.X+x*bounds.Dx()/width, bounds.Min.X+(x+1)*bounds.Dx()/width
+
+			var r, g, b, a, n uint64 // box filter, average the source pixels covered by the destination pixel
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					sr, sg, sb, sa := src.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(sr), g+uint64(sg), b+uint64(sb), a+uint64(sa), n+1
+				}
+			}
+			if n > 0 {
+				dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode image")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// imageWidths are the widths in pixels of image sizes, 0 is the original size.
+var imageWidths = map[v1.ImageSize]int{
+	v1.Original: 0,
+	v1.W92:      92,
+	v1.W185:     185,
+	v1.W300:     300,
+	v1.W500:     500,
+	v1.W780:     780,
+	v1.W1280:    1280,
+}
+
+// imageID makes a stable ID of an image, derived from its path.
+func imageID(i meta.Image) string {
+	sum := sha256.Sum256([]byte(i.Path()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// findImage looks up an image by its ID in metadata, including the series metadata of episodes and cast member images.
+func findImage(m meta.Metadata, id string) meta.Image {
+	for _, i := range m.Images() {
+		if imageID(i) == id {
+			return i
+		}
+	}
+
+	switch metaVariant := m.(type) {
+	case meta.EpisodeMetadata:
+		if series := metaVariant.Series(); series != nil {
+			return findImage(series, id)
+		}
+	case meta.MovieOrSeriesMetadata:
+		for _, cm := range metaVariant.Cast() {
+			if i := cm.Image(); i != nil && imageID(i) == id {
+				return i
+			}
+		}
+	}
+
+	return nil
+}
+
+// galleryPageSize is the number of images in a page of an image gallery.
+const galleryPageSize = 20
+
+func (s *Server) GetRepoMediaImages(_ context.Context, request v1.GetRepoMediaImagesRequestObject) (v1.GetRepoMediaImagesResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaImages400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	m := rp.Get(request.MediaId)
+	if m == nil {
+		return v1.GetRepoMediaImages400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	page := 1
+	if request.Params.Page != nil {
+		if page = *request.Params.Page; page < 1 {
+			return v1.GetRepoMediaImages400JSONResponse(v1.Error{Type: v1.BadRequest, Description: fmt.Sprintf("invalid page %d", page)}), nil
+		}
+	}
+
+	var gallery []meta.Image
+	if mm := m.Meta(); mm != nil {
+		var err error
+		if gallery, err = meta.Gallery(rp.Source(), galleryMeta(mm)); err != nil {
+			return nil, errors.Wrap(err, "failed to fetch image gallery")
+		}
+	}
+
+	var (
+		imageBase = imageBase(rp.ID(), m.ID())
+		images    = make([]v1.Image, 0, len(gallery))
+	)
+	for _, i := range gallery {
+		wi := s.wrapImage(i, imageBase)
+		if request.Params.Type == nil || wi.Type == *request.Params.Type {
+			images = append(images, wi)
+		}
+	}
+
+	total := len(images)
+	if start := (page - 1) * galleryPageSize; start < total {
+		images = images[start:]
+	} else {
+		images = images[:0]
+	}
+	if len(images) > galleryPageSize {
+		images = images[:galleryPageSize]
+	}
+
+	return v1.GetRepoMediaImages200JSONResponse{
+		Body:    images,
+		Headers: v1.GetRepoMediaImages200ResponseHeaders{XTotalCount: total},
+	}, nil
+}
+
+func (s *Server) SetRepoMediaPreferredImage(ctx context.Context, request v1.SetRepoMediaPreferredImageRequestObject) (v1.SetRepoMediaPreferredImageResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.SetRepoMediaPreferredImage400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	mr := rp.Mutable()
+	if mr == nil {
+		return v1.SetRepoMediaPreferredImage400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "repository is not mutable"}), nil
+	}
+
+	m := mr.Get(request.MediaId)
+	if m == nil {
+		return v1.SetRepoMediaPreferredImage400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	var i meta.Image
+	if mm := m.Meta(); mm != nil {
+		var err error
+		if i, err = lookupImage(mr.Source(), mm, request.ImageId); err != nil {
+			return nil, err
+		}
+	}
+	if i == nil {
+		return v1.SetRepoMediaPreferredImage400JSONResponse(v1.Error{Type: v1.NotFound, Description: "image not found"}), nil
+	}
+	if i.Type() != meta.ImageTypePoster && i.Type() != meta.ImageTypeBackdrop {
+		return v1.SetRepoMediaPreferredImage400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "image is not a poster or backdrop"}), nil
+	}
+
+	targets := []media.Media{m}
+	if key := seriesKey(m); key != "" { // the series' images are shared by its episodes
+		targets = targets[:0]
+		for _, item := range mr.Items() {
+			if seriesKey(item) == key {
+				targets = append(targets, item)
+			}
+		}
+	}
+
+	mr.BeginBatch()
+	for _, target := range targets {
+		updated := media.NewMedia(target.ID(), target.Path(), meta.WithImage(target.Meta(), i), target.Format())
+		if err := mr.Update(updated); err != nil {
+			var notFound *repo.ErrMediaNotFound
+			if !errors.As(err, &notFound) { // removed in the meantime
+				_ = mr.Commit()
+				return nil, errors.Wrap(err, "failed to update media")
+			}
+		}
+		if target.ID() == m.ID() {
+			m = updated
+		}
+	}
+	if err := mr.Commit(); err != nil {
+		return nil, errors.Wrap(err, "failed to persist media")
+	}
+
+	m0, err := s.wrapMedia(mr.ID(), m, 0, requestLang(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to wrap media")
+	}
+
+	return v1.SetRepoMediaPreferredImage200JSONResponse(m0), nil
+}
+
+// lookupImage looks up an image by its ID in metadata (findImage) and its image gallery, returns nil if it's not found.
+func lookupImage(source meta.Source, m meta.Metadata, id string) (meta.Image, error) {
+	if i := findImage(m, id); i != nil {
+		return i, nil
+	}
+
+	images, err := meta.Gallery(source, galleryMeta(m)) // cached by the source, if listed before
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch image gallery")
+	}
+	for _, i := range images {
+		if imageID(i) == id {
+			return i, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// galleryMeta returns the metadata whose image gallery is listed for media metadata, the series of episodes.
+func galleryMeta(m meta.Metadata) meta.Metadata {
+	if em, ok := m.(meta.EpisodeMetadata); ok && em.Series() != nil {
+		return em.Series()
+	}
+
+	return m
+}
+
+// imageResp is a response of a cached image, conditional and range requests are handled by http.ServeContent.
+type imageResp struct {
+	image *cachedImage
+}
+
+func (ir *imageResp) VisitGetRepoMediaImageResponse(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", ir.image.mime)
+	w.Header().Set("ETag", ir.image.etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", imageMaxAge))
+
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(ir.image.data))
+	return nil
+}
+
+func (s *Server) GetRepoMediaImage(ctx context.Context, request v1.GetRepoMediaImageRequestObject) (v1.GetRepoMediaImageResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaImage400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	m := rp.Get(request.MediaId)
+	if m == nil {
+		return v1.GetRepoMediaImage400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	var i meta.Image
+	if mm := m.Meta(); mm != nil {
+		var err error
+		if i, err = lookupImage(rp.Source(), mm, request.ImageId); err != nil {
+			return nil, err
+		}
+	}
+	if i == nil {
+		return v1.GetRepoMediaImage400JSONResponse(v1.Error{Type: v1.NotFound, Description: "image not found"}), nil
+	}
+
+	var width int
+	if request.Params.Size != nil {
+		var ok bool
+		if width, ok = imageWidths[*request.Params.Size]; !ok {
+			return v1.GetRepoMediaImage400JSONResponse(v1.Error{Type: v1.BadRequest, Description: fmt.Sprintf("unknown image size '%s'", *request.Params.Size)}), nil
+		}
+	}
+
+	ci, err := s.images.get(ctx, i, width)
+	if err != nil {
+		if logger := s.log(ctx, rp.ID(), request.MediaId); logger != nil {
+			logger.Error(
+				"failed to load image",
+				zap.String("path", i.Path()),
+				zap.Bool("remote", i.Remote()),
+				zap.Int("width", width),
+				zap.Error(err),
+			)
+		}
+
+		return v1.GetRepoMediaImage400JSONResponse(v1.Error{Type: v1.UnknownFormat, Description: "image could not be read"}), nil
+	}
+
+	return &imageResp{image: ci}, nil
+}
+
+// imageBase makes the base of media image URLs, relative to the API server URL.
+func imageBase(repoId, mediaId string) string {
+	return fmt.Sprintf("/repos/%s/media/%s/images/", repoId, mediaId)
+}
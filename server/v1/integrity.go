@@ -0,0 +1,131 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/checksum"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/server/api/v1"
+	"go.uber.org/zap"
+	"time"
+)
+
+// maintenanceCheckInterval is the period between checks whether checksums are due to be computed and the job queue is idle.
+const maintenanceCheckInterval = time.Minute
+
+// Verify queues a job verifying the checksums of a repository's media, the job fails if corrupted files are found.
+// The catalog must not be nil.
+func (s *Server) Verify(r repo.Repository) (*jobs.Job, error) {
+	return s.jobs.Enqueue(jobs.TypeVerify, r.ID(), "", func(ctx context.Context) (media.Media, error) {
+		_, err := s.catalog.Verify(ctx, r)
+		return nil, err
+	})
+}
+
+// Maintain starts periodically queueing jobs computing checksums of new and modified media (checksum.Catalog.Update),
+// each repository at most once in the interval, only while no other jobs are queued or running.
+// The catalog must not be nil, maintenance is stopped when the server is closed.
+func (s *Server) Maintain(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.maintStop, s.maintDone = cancel, make(chan struct{})
+
+	go func() {
+		defer close(s.maintDone)
+
+		ticker := time.NewTicker(maintenanceCheckInterval)
+		defer ticker.Stop()
+
+		lastRuns := make(map[string]time.Time, len(s.repos)) // repository ID -> last queued update
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			for repoId, r := range s.repos {
+				if time.Since(lastRuns[repoId]) < interval || !s.jobs.Idle() || r.Health().Degraded { // one at a time, so jobs queued meanwhile come first
+					continue
+				}
+
+				_, err := s.jobs.Enqueue(jobs.TypeChecksum, repoId, "", func(ctx context.Context) (media.Media, error) {
+					return nil, s.catalog.Update(ctx, r)
+				})
+				if err != nil {
+					if s.logger != nil {
+						s.logger.Warn("failed to queue checksum job", zap.String("repo", repoId), zap.Error(err))
+					}
+					continue
+				}
+
+				lastRuns[repoId] = time.Now()
+			}
+		}
+	}()
+}
+
+// stopMaintenance stops the maintenance started by Maintain, if any.
+func (s *Server) stopMaintenance() {
+	if s.maintStop != nil {
+		s.maintStop()
+		<-s.maintDone
+	}
+}
+
+func (s *Server) VerifyRepo(_ context.Context, request v1.VerifyRepoRequestObject) (v1.VerifyRepoResponseObject, error) {
+	r := s.Repo(request.Id)
+	if r == nil {
+		return v1.VerifyRepo400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if s.catalog == nil {
+		return v1.VerifyRepo400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "checksums are not enabled"}), nil
+	}
+
+	j, err := s.Verify(r)
+	if err != nil {
+		if errors.Is(err, jobs.ErrQueueFull) {
+			return v1.VerifyRepo400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "job queue is full"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to queue job")
+	}
+
+	return v1.VerifyRepo202JSONResponse(s.wrapJob(j)), nil
+}
+
+func (s *Server) GetRepoIntegrity(_ context.Context, request v1.GetRepoIntegrityRequestObject) (v1.GetRepoIntegrityResponseObject, error) {
+	r := s.Repo(request.Id)
+	if r == nil {
+		return v1.GetRepoIntegrity400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if s.catalog == nil {
+		return v1.GetRepoIntegrity400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "checksums are not enabled"}), nil
+	}
+
+	report := s.catalog.LastReport(r.ID())
+	if report == nil {
+		return v1.GetRepoIntegrity400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not verified yet"}), nil
+	}
+
+	return v1.GetRepoIntegrity200JSONResponse(wrapReport(report)), nil
+}
+
+func wrapReport(report *checksum.Report) v1.IntegrityReport {
+	corrupted := make([]v1.CorruptedFile, len(report.Corrupted))
+	for i, m := range report.Corrupted {
+		corrupted[i] = v1.CorruptedFile{
+			MediaId:  m.MediaID,
+			Path:     m.Path,
+			Expected: m.Expected,
+			Actual:   m.Actual,
+		}
+	}
+
+	return v1.IntegrityReport{
+		VerifiedAt: report.Time,
+		Checked:    report.Checked,
+		Corrupted:  corrupted,
+	}
+}
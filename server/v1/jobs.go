@@ -0,0 +1,161 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/server/api/v1"
+	"golang.org/x/exp/slices"
+	"strings"
+)
+
+func (s *Server) ConvertRepoMedia(ctx context.Context, request v1.ConvertRepoMediaRequestObject) (v1.ConvertRepoMediaResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.ConvertRepoMedia400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if rp.Get(request.MediaId) == nil {
+		return v1.ConvertRepoMedia400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	format := media.FindFormat(request.Body.Format)
+	if format == nil {
+		return v1.ConvertRepoMedia400JSONResponse(v1.Error{Type: v1.UnknownFormat, Description: fmt.Sprintf("unknown format '%s'", request.Body.Format)}), nil
+	}
+
+	if v := request.Body.Start; v != nil && !(*v >= 0) {
+		return v1.ConvertRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "start position must not be negative"}), nil
+	}
+	start := derefSeconds(request.Body.Start)
+
+	var (
+		type_  jobs.Type
+		fn     jobs.Func
+		logger = s.log(ctx, rp.ID(), request.MediaId) // jobs outlive the request, carry its logger over
+	)
+	if request.Body.VideoCodec == nil && request.Body.AudioCodec == nil {
+		if start > 0 {
+			return v1.ConvertRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "a start position needs a video or audio codec"}), nil
+		}
+		if !rp.Capabilities().Has(repo.CapabilityRemux) {
+			return v1.ConvertRepoMedia400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'remux' capability"}), nil
+		}
+
+		type_ = jobs.TypeRemux
+		fn = func(ctx context.Context) (media.Media, error) {
+			return rp.Remux(repo.WithLogger(ctx, logger), request.MediaId, format)
+		}
+	} else {
+		if !rp.Capabilities().Has(repo.CapabilityTranscode) {
+			return v1.ConvertRepoMedia400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'transcode' capability"}), nil
+		}
+
+		profile := &media.TranscodeProfile{
+			Format:     format,
+			VideoCodec: derefString(request.Body.VideoCodec),
+			AudioCodec: derefString(request.Body.AudioCodec),
+			Start:      start,
+		}
+		if profile.VideoCodec != "" { // copied video can't be drawn over
+			profile.Watermark = s.watermark(ctx)
+		}
+		if key, ok := sessionOf(ctx, derefString(request.Params.XKatanaSession)); ok {
+			if qc, ok := s.qualities.get(key); ok {
+				qc.apply(profile)
+			}
+		}
+		if s.features != nil { // after capping, the cap may need settings the backend doesn't honor
+			if opt, ok := s.features.UnsupportedOption(profile); ok {
+				return v1.ConvertRepoMedia400JSONResponse(v1.Error{Type: v1.UnknownFormat, Description: fmt.Sprintf("'%s' unsupported by the conversion backend", opt)}), nil
+			}
+		}
+
+		type_ = jobs.TypeTranscode
+		fn = func(ctx context.Context) (media.Media, error) {
+			return rp.Transcode(repo.WithLogger(ctx, logger), request.MediaId, profile)
+		}
+	}
+
+	j, err := s.jobs.Enqueue(type_, rp.ID(), request.MediaId, fn)
+	if err != nil {
+		if errors.Is(err, jobs.ErrQueueFull) {
+			return v1.ConvertRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "job queue is full"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to queue job")
+	}
+
+	return v1.ConvertRepoMedia202JSONResponse(s.wrapJob(j)), nil
+}
+
+func (s *Server) GetJobById(_ context.Context, request v1.GetJobByIdRequestObject) (v1.GetJobByIdResponseObject, error) {
+	if j := s.jobs.Get(request.JobId); j != nil {
+		return v1.GetJobById200JSONResponse(s.wrapJob(j)), nil
+	}
+
+	return v1.GetJobById400JSONResponse(v1.Error{Type: v1.NotFound, Description: "job not found"}), nil
+}
+
+func (s *Server) CancelJob(_ context.Context, request v1.CancelJobRequestObject) (v1.CancelJobResponseObject, error) {
+	j := s.jobs.Get(request.JobId)
+	if j == nil {
+		return v1.CancelJob400JSONResponse(v1.Error{Type: v1.NotFound, Description: "job not found"}), nil
+	}
+	if !s.jobs.Cancel(j.ID()) {
+		return v1.CancelJob400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "job already finished"}), nil
+	}
+
+	return v1.CancelJob204Response{}, nil
+}
+
+func (s *Server) wrapJob(j *jobs.Job) v1.Job {
+	var (
+		progress = j.Progress()
+		eta      *int
+		jobErr   *string
+	)
+	if d, ok := j.ETA(); ok {
+		secs := int(d.Seconds())
+		eta = &secs
+	}
+	if err := j.Err(); err != nil {
+		jobErr = makeOptString(err.Error())
+	}
+
+	var summary *v1.JobSummary
+	if outcome := j.Outcome(); outcome != nil {
+		summary = &v1.JobSummary{Succeeded: outcome.Succeeded, Failed: make([]v1.JobFailure, 0, len(outcome.Failed))}
+		for mediaId, err := range outcome.Failed {
+			summary.Failed = append(summary.Failed, v1.JobFailure{MediaId: mediaId, Error: err.Error()})
+		}
+		slices.SortFunc(summary.Failed, func(a, b v1.JobFailure) int {
+			return strings.Compare(a.MediaId, b.MediaId)
+		})
+	}
+
+	state := v1.JobState(j.State())
+	if state == v1.Completed { // the source size is not always known, don't make clients wait for 100 percent
+		progress.Processed, progress.Total = 1, 1
+	}
+
+	return v1.Job{
+		Id:      j.ID(),
+		Type:    v1.JobType(j.Type()),
+		State:   state,
+		RepoId:  j.RepoID(),
+		MediaId: makeOptString(j.MediaID()),
+		Progress: v1.JobProgress{
+			Percent:      float32(progress.Percent()),
+			BytesWritten: progress.Written,
+			Eta:          eta,
+		},
+		Error:      jobErr,
+		CreatedAt:  j.Created(),
+		StartedAt:  makeOptTime(j.Started()),
+		FinishedAt: makeOptTime(j.Finished()),
+		Summary:    summary,
+	}
+}
@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/repo/media/meta"
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type langKey struct{}
+
+// withLang returns a context carrying the language of a request's response.
+func withLang(ctx context.Context, lang language.Tag) context.Context {
+	return context.WithValue(ctx, langKey{}, lang)
+}
+
+// requestLang returns the language of a request's response, language.Und if the client didn't request one.
+func requestLang(ctx context.Context) language.Tag {
+	if lang, ok := ctx.Value(langKey{}).(language.Tag); ok {
+		return lang
+	}
+
+	return language.Und
+}
+
+// negotiateLang returns the language requested by the lang query parameter or else the Accept-Language header,
+// language.Und if there's neither or they're malformed.
+func negotiateLang(r *http.Request) language.Tag {
+	if q := r.URL.Query().Get("lang"); q != "" {
+		if tag, err := language.Parse(q); err == nil {
+			return tag
+		}
+
+		return language.Und
+	}
+
+	if al := r.Header.Get("Accept-Language"); al != "" {
+		tags, _, err := language.ParseAcceptLanguage(al) // sorted by quality
+		if err == nil && len(tags) > 0 {
+			return tags[0]
+		}
+	}
+
+	return language.Und
+}
+
+// localize is a middleware providing handlers with the requested language of the response, retrievable with requestLang.
+func localize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Language")
+
+		lang := negotiateLang(r)
+		if lang != language.Und {
+			w.Header().Set("Content-Language", lang.String())
+		}
+
+		next.ServeHTTP(w, r.WithContext(withLang(r.Context(), lang)))
+	})
+}
+
+// dateFormat is the long date format of a language.
+type dateFormat struct {
+	layout string     // fmt format of the day, month name and year, in that order of arguments
+	months [12]string // month names, in the grammatical case used in dates
+}
+
+// dateFormats are the long date formats of languages by their base, English is used for others.
+var dateFormats = map[string]dateFormat{
+	"en": {layout: "%[2]s %[1]d, %[3]d", months: [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}},
+	"cs": {layout: "%d. %s %d", months: [12]string{"ledna", "února", "března", "dubna", "května", "června", "července", "srpna", "září", "října", "listopadu", "prosince"}},
+	"de": {layout: "%d. %s %d", months: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"}},
+	"es": {layout: "%d de %s de %d", months: [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"}},
+	"fr": {layout: "%d %s %d", months: [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"}},
+	"it": {layout: "%d %s %d", months: [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"}},
+	"pl": {layout: "%d %s %d", months: [12]string{"stycznia", "lutego", "marca", "kwietnia", "maja", "czerwca", "lipca", "sierpnia", "września", "października", "listopada", "grudnia"}},
+	"pt": {layout: "%d de %s de %d", months: [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"}},
+	"sk": {layout: "%d. %s %d", months: [12]string{"januára", "februára", "marca", "apríla", "mája", "júna", "júla", "augusta", "septembra", "októbra", "novembra", "decembra"}},
+}
+
+// formatDate formats a date as a long date in a language, returns nil for zero times.
+// Example: 2006-01-02 -> "January 2, 2006" (en), "2. ledna 2006" (cs)
+func formatDate(t time.Time, lang language.Tag) *string {
+	if t.IsZero() {
+		return nil
+	}
+
+	base, _ := lang.Base()
+	df, ok := dateFormats[base.String()]
+	if !ok {
+		df = dateFormats["en"]
+	}
+
+	s := fmt.Sprintf(df.layout, t.Day(), df.months[t.Month()-1], t.Year())
+	return &s
+}
+
+// locale is the language of wrapped metadata and the metadata source translating it, the zero value doesn't translate.
+type locale struct {
+	lang   language.Tag
+	source meta.Source // can be nil
+}
+
+// localizeGenres translates genre names of movies or series with a repository's metadata source,
+// names are kept as-is if they can't be translated.
+func (s *Server) localizeGenres(genres []string, type_ meta.Type, loc locale) []string {
+	if loc.source == nil {
+		return genres
+	}
+
+	localized, err := meta.LocalizeGenres(loc.source, genres, type_, loc.lang)
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Warn("failed to localize genres", zap.String("lang", loc.lang.String()), zap.Error(err))
+		}
+
+		return genres
+	}
+
+	return localized
+}
+
+// etagLang suffixes an entity tag with a language, so that representations in different languages are told apart.
+func etagLang(etag string, lang language.Tag) string {
+	if lang == language.Und {
+		return etag
+	}
+
+	return strings.TrimSuffix(etag, `"`) + "-" + strings.ToLower(lang.String()) + `"`
+}
@@ -0,0 +1,448 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/katana/server/api/v1"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func (s *Server) AddRepoMedia(ctx context.Context, request v1.AddRepoMediaRequestObject) (v1.AddRepoMediaResponseObject, error) {
+	rp := s.Repo(request.Id)
+	if rp == nil {
+		return v1.AddRepoMedia400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	mr := rp.Mutable()
+	if mr == nil {
+		return v1.AddRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "repository is not mutable"}), nil
+	}
+
+	var (
+		path     string
+		uploaded bool
+		err      error
+	)
+	switch {
+	case request.JSONBody != nil:
+		path, err = resolvePath(mr.Path(), request.JSONBody.Path)
+	case request.MultipartBody != nil:
+		path, err = saveUpload(mr.Path(), request.MultipartBody)
+		uploaded = err == nil
+	default:
+		return v1.AddRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "missing request body"}), nil
+	}
+	if err != nil { // invalid paths are client errors (ErrInvalidPath), see DefaultResponseErrorHandler
+		return nil, errors.Wrap(err, "failed to resolve media path")
+	}
+
+	if err := mr.AddPath(path); err != nil {
+		var duplicatePath *repo.ErrDuplicatePath
+		if !uploaded || !errors.As(err, &duplicatePath) { // uploads may have been added by a filesystem watcher already
+			if uploaded {
+				if err0 := os.Remove(path); err0 != nil {
+					err = multierr.Append(err, errors.Wrap(err0, "failed to remove uploaded file"))
+				}
+			}
+
+			return nil, errors.Wrap(err, "failed to add media")
+		}
+	}
+
+	m := mr.Find(path)
+	if m == nil { // removed in the meantime
+		return v1.AddRepoMedia400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	m0, err := s.wrapMedia(mr.ID(), m, 0, requestLang(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to wrap media")
+	}
+
+	return v1.AddRepoMedia201JSONResponse(m0), nil
+}
+
+func (s *Server) DeleteRepoMedia(_ context.Context, request v1.DeleteRepoMediaRequestObject) (v1.DeleteRepoMediaResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.DeleteRepoMedia400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	mr := rp.Mutable()
+	if mr == nil {
+		return v1.DeleteRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "repository is not mutable"}), nil
+	}
+
+	m := mr.Get(request.MediaId)
+	if m == nil {
+		return v1.DeleteRepoMedia400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+	if mr.ArchiveStatus(m.ID()) != repo.ArchiveStatusNone { // the file would be left in cold storage
+		return v1.DeleteRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "media is archived, restore it first"}), nil
+	}
+
+	if bin, ok := s.trash[rp.ID()]; ok && request.Params.DeleteFile != nil && *request.Params.DeleteFile {
+		if err := bin.Put(m); err != nil {
+			return nil, errors.Wrap(err, "failed to move media to trash")
+		}
+
+		return v1.DeleteRepoMedia204Response{}, nil
+	}
+
+	// remove before deleting the file, cache files are found by the file contents
+	if err := mr.Remove(m); err != nil {
+		return nil, errors.Wrap(err, "failed to remove media")
+	}
+	if request.Params.DeleteFile != nil && *request.Params.DeleteFile {
+		if err := os.Remove(m.Path()); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, errors.Wrap(err, "failed to delete media file")
+		}
+	}
+
+	return v1.DeleteRepoMedia204Response{}, nil
+}
+
+func (s *Server) UpdateRepoMediaMeta(ctx context.Context, request v1.UpdateRepoMediaMetaRequestObject) (v1.UpdateRepoMediaMetaResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.UpdateRepoMediaMeta400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	mr := rp.Mutable()
+	if mr == nil {
+		return v1.UpdateRepoMediaMeta400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "repository is not mutable"}), nil
+	}
+
+	m := mr.Get(request.MediaId)
+	if m == nil {
+		return v1.UpdateRepoMediaMeta400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	query, err := makeMetaQuery(request.Body)
+	if err != nil {
+		return v1.UpdateRepoMediaMeta400JSONResponse(v1.Error{Type: v1.BadRequest, Description: err.Error()}), nil
+	}
+
+	mm, err := mr.Source().FromQuery(query)
+	if err != nil { // invalid queries are client errors (meta.ErrInvalidQuery)
+		return nil, errors.Wrap(err, "failed to resolve metadata")
+	}
+	if mm == nil {
+		return v1.UpdateRepoMediaMeta400JSONResponse(v1.Error{Type: v1.NotFound, Description: "no metadata found"}), nil
+	}
+
+	m = media.NewMedia(m.ID(), m.Path(), mm, m.Format())
+	if err := mr.Update(m); err != nil {
+		var notFound *repo.ErrMediaNotFound
+		if errors.As(err, &notFound) { // removed in the meantime
+			return v1.UpdateRepoMediaMeta400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to update media")
+	}
+	if s.refresher != nil { // refreshed with the query from now on, rather than matched from the file again
+		s.refresher.Matched(mr.ID(), m.ID(), query)
+	}
+
+	m0, err := s.wrapMedia(mr.ID(), m, 0, requestLang(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to wrap media")
+	}
+
+	return v1.UpdateRepoMediaMeta200JSONResponse(m0), nil
+}
+
+func (s *Server) UpdateRepoMediaTags(ctx context.Context, request v1.UpdateRepoMediaTagsRequestObject) (v1.UpdateRepoMediaTagsResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.UpdateRepoMediaTags400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	mr := rp.Mutable()
+	if mr == nil {
+		return v1.UpdateRepoMediaTags400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "repository is not mutable"}), nil
+	}
+
+	if err := mr.SetTags(request.MediaId, request.Body.Tags); err != nil {
+		var (
+			notFound   *repo.ErrMediaNotFound
+			invalidTag *repo.ErrInvalidTag
+		)
+		if errors.As(err, &notFound) {
+			return v1.UpdateRepoMediaTags400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+		}
+		if errors.As(err, &invalidTag) {
+			return v1.UpdateRepoMediaTags400JSONResponse(v1.Error{Type: v1.BadRequest, Description: err.Error()}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to update media tags")
+	}
+
+	m := mr.Get(request.MediaId)
+	if m == nil { // removed in the meantime
+		return v1.UpdateRepoMediaTags400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	m0, err := s.wrapMedia(mr.ID(), m, 0, requestLang(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to wrap media")
+	}
+
+	return v1.UpdateRepoMediaTags200JSONResponse(m0), nil
+}
+
+func (s *Server) LockRepoMedia(ctx context.Context, request v1.LockRepoMediaRequestObject) (v1.LockRepoMediaResponseObject, error) {
+	m, e, err := s.setLocked(ctx, request.RepoId, request.MediaId, true)
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return v1.LockRepoMedia400JSONResponse(*e), nil
+	}
+
+	return v1.LockRepoMedia200JSONResponse(m), nil
+}
+
+func (s *Server) UnlockRepoMedia(ctx context.Context, request v1.UnlockRepoMediaRequestObject) (v1.UnlockRepoMediaResponseObject, error) {
+	m, e, err := s.setLocked(ctx, request.RepoId, request.MediaId, false)
+	if err != nil {
+		return nil, err
+	}
+	if e != nil {
+		return v1.UnlockRepoMedia400JSONResponse(*e), nil
+	}
+
+	return v1.UnlockRepoMedia200JSONResponse(m), nil
+}
+
+// setLocked locks media against automatic changes or unlocks it, returns the wrapped media or a client error if it isn't possible.
+func (s *Server) setLocked(ctx context.Context, repoId, mediaId string, locked bool) (v1.Media, *v1.Error, error) {
+	rp := s.Repo(repoId)
+	if rp == nil {
+		return v1.Media{}, &v1.Error{Type: v1.NotFound, Description: "repository not found"}, nil
+	}
+
+	mr := rp.Mutable()
+	if mr == nil {
+		return v1.Media{}, &v1.Error{Type: v1.BadRequest, Description: "repository is not mutable"}, nil
+	}
+
+	if err := mr.SetLocked(mediaId, locked); err != nil {
+		var notFound *repo.ErrMediaNotFound
+		if errors.As(err, &notFound) {
+			return v1.Media{}, &v1.Error{Type: v1.NotFound, Description: "media not found"}, nil
+		}
+
+		return v1.Media{}, nil, errors.Wrap(err, "failed to update media lock")
+	}
+
+	m := mr.Get(mediaId)
+	if m == nil { // removed in the meantime
+		return v1.Media{}, &v1.Error{Type: v1.NotFound, Description: "media not found"}, nil
+	}
+
+	m0, err := s.wrapMedia(mr.ID(), m, 0, requestLang(ctx))
+	if err != nil {
+		return v1.Media{}, nil, errors.Wrap(err, "failed to wrap media")
+	}
+
+	return m0, nil, nil
+}
+
+// gone checks whether the file of media is missing, such media is removed from the repository if it's mutable.
+// Media missing from the repository is gone too.
+func (s *Server) gone(ctx context.Context, rp repo.Repository, mediaId string) bool {
+	m := rp.Get(mediaId)
+	if m == nil {
+		return true
+	}
+	if _, err := os.Stat(m.Path()); !errors.Is(err, fs.ErrNotExist) {
+		return false
+	}
+
+	logger := s.log(ctx, rp.ID(), mediaId)
+	if logger != nil {
+		logger.Warn("media file missing, removing media", zap.String("path", m.Path()))
+	}
+	if mr := rp.Mutable(); mr != nil {
+		if err := mr.Remove(m); err != nil && logger != nil {
+			logger.Error("failed to remove missing media", zap.Error(err))
+		}
+	}
+
+	return true
+}
+
+// makeMetaQuery translates a MetadataQuery to a meta.Query, the episode is only queried if both its numbers are set.
+func makeMetaQuery(mq *v1.MetadataQuery) (*meta.Query, error) {
+	if mq == nil || (derefString(mq.Query) == "" && derefString(mq.Id) == "") {
+		return nil, errors.New("missing query or ID")
+	}
+
+	q := &meta.Query{
+		Query:   derefString(mq.Query),
+		ID:      derefString(mq.Id),
+		Type:    meta.TypeUnknown,
+		Season:  -1,
+		Episode: -1,
+	}
+	if mq.Type != nil {
+		type_, err := parseMetaType(*mq.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		q.Type = type_
+	}
+	if mq.Season != nil && mq.Episode != nil {
+		if *mq.Season < 0 || *mq.Episode < 0 {
+			return nil, errors.New("negative season or episode number")
+		}
+
+		q.Season, q.Episode = *mq.Season, *mq.Episode
+	}
+
+	return q, nil
+}
+
+// parseMetaType translates a MetadataType to a meta.Type.
+func parseMetaType(t v1.MetadataType) (meta.Type, error) {
+	switch t {
+	case v1.MetadataTypeUnknown:
+		return meta.TypeUnknown, nil
+	case v1.MetadataTypeMovie:
+		return meta.TypeMovie, nil
+	case v1.MetadataTypeSeries:
+		return meta.TypeSeries, nil
+	case v1.MetadataTypeEpisode:
+		return meta.TypeEpisode, nil
+	}
+
+	return 0, fmt.Errorf("unknown metadata type '%s'", t)
+}
+
+// wrapMetaType translates a meta.Type to a MetadataType.
+func wrapMetaType(t meta.Type) v1.MetadataType {
+	switch t {
+	case meta.TypeMovie:
+		return v1.MetadataTypeMovie
+	case meta.TypeSeries:
+		return v1.MetadataTypeSeries
+	case meta.TypeEpisode:
+		return v1.MetadataTypeEpisode
+	}
+
+	return v1.MetadataTypeUnknown
+}
+
+// resolvePath resolves a media path relative to the repository root or absolute within it.
+// Paths outside of the root, dot-prefixed paths (excluded from repositories) and subtitle files are refused.
+func resolvePath(root, path string) (string, error) {
+	absPath := filepath.Clean(path)
+	if !filepath.IsAbs(absPath) {
+		absPath = filepath.Join(root, absPath)
+	}
+
+	relPath, err := filepath.Rel(root, absPath)
+	if err != nil || relPath == "." || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", &ErrInvalidPath{Path: path, Reason: "outside of repository root"}
+	}
+	for _, elem := range strings.Split(relPath, string(filepath.Separator)) {
+		if strings.HasPrefix(elem, ".") {
+			return "", &ErrInvalidPath{Path: path, Reason: "dot-prefixed paths are excluded"}
+		}
+	}
+	if media.SubtitleCodec(filepath.Ext(absPath)) != "" {
+		return "", &ErrInvalidPath{Path: path, Reason: "subtitle files are not media"}
+	}
+
+	return absPath, nil
+}
+
+// saveUpload saves an uploaded media file (MediaUpload) to the repository root, returns its absolute path.
+// Existing files are not replaced.
+func saveUpload(root string, mr *multipart.Reader) (string, error) {
+	var destPath string
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			if err == io.EOF {
+				return "", &ErrInvalidPath{Path: destPath, Reason: "missing file part"}
+			}
+
+			return "", errors.Wrap(err, "failed to read part")
+		}
+
+		switch part.FormName() {
+		case "path":
+			b, err := io.ReadAll(io.LimitReader(part, 4096))
+			if err != nil {
+				return "", errors.Wrap(err, "failed to read path part")
+			}
+
+			destPath = string(b)
+		case "file":
+			if destPath == "" {
+				destPath = filepath.Base(part.FileName())
+			}
+
+			path, err := resolvePath(root, destPath)
+			if err != nil {
+				return "", err
+			}
+
+			if err := writeUpload(path, part); err != nil {
+				if errors.Is(err, fs.ErrExist) {
+					return "", &ErrInvalidPath{Path: destPath, Reason: "file already exists"}
+				}
+
+				return "", err
+			}
+
+			return path, nil
+		}
+	}
+}
+
+// writeUpload writes an uploaded file, it's first written to a dot-prefixed file that's ignored by repositories.
+// An error wrapping fs.ErrExist is returned if the file exists.
+func writeUpload(path string, r io.Reader) (err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "failed to make directories")
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.upload")
+	if err != nil {
+		return errors.Wrap(err, "failed to create file")
+	}
+	defer func() {
+		if err0 := os.Remove(f.Name()); err0 != nil && !errors.Is(err0, fs.ErrNotExist) {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to remove temporary file"))
+		}
+	}()
+
+	_, err = io.Copy(f, r)
+	if err0 := f.Close(); err0 != nil {
+		err = multierr.Append(err, errors.Wrap(err0, "failed to close file"))
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to write file")
+	}
+
+	if err := os.Link(f.Name(), path); err != nil { // unlike renaming, fails if the destination exists
+		return errors.Wrap(err, "failed to link file")
+	}
+
+	return nil
+}
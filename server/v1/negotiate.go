@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"strconv"
+	"strings"
+)
+
+// mediaRange is a media range of an Accept header ("video/*;q=0.5").
+type mediaRange struct {
+	type_, subtype string
+	q              float64
+}
+
+// specificity returns how specific the range is, -1 if it doesn't match the media type.
+func (mr *mediaRange) specificity(type_, subtype string) int {
+	switch {
+	case mr.type_ == "*" && mr.subtype == "*":
+		return 0
+	case !strings.EqualFold(mr.type_, type_):
+		return -1
+	case mr.subtype == "*":
+		return 1
+	case strings.EqualFold(mr.subtype, subtype):
+		return 2
+	}
+
+	return -1
+}
+
+// parseAccept parses the media ranges of an Accept header, malformed ranges are skipped.
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+
+		type_, subtype, ok := strings.Cut(strings.TrimSpace(params[0]), "/")
+		if !ok || type_ == "" || subtype == "" {
+			continue
+		}
+
+		mr := mediaRange{type_: type_, subtype: subtype, q: 1}
+		for _, param := range params[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil && q >= 0 && q <= 1 {
+					mr.q = q
+				}
+			}
+		}
+
+		ranges = append(ranges, mr)
+	}
+
+	return ranges
+}
+
+// negotiate picks the most preferred offered media type by an Accept header, returns an empty string if none are acceptable.
+// The quality of an offer is taken from the most specific matching media range, ties are resolved by the offer order.
+// An empty Accept header accepts anything.
+func negotiate(accept string, offers []string) string {
+	if strings.TrimSpace(accept) == "" {
+		accept = "*/*"
+	}
+
+	var (
+		ranges = parseAccept(accept)
+
+		best  string
+		bestQ float64
+	)
+	for _, offer := range offers {
+		type_, subtype, _ := strings.Cut(offer, "/")
+
+		q, specificity := 0.0, -1
+		for _, mr := range ranges {
+			if s := mr.specificity(type_, subtype); s > specificity {
+				q, specificity = mr.q, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,173 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/event"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/katana/server/api/v1"
+	"github.com/katana-project/katana/server/auth"
+	"github.com/katana-project/katana/server/playback"
+	"math"
+	"strings"
+	"time"
+)
+
+func (s *Server) GetRepoMediaProgress(ctx context.Context, request v1.GetRepoMediaProgressRequestObject) (v1.GetRepoMediaProgressResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaProgress400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if rp.Get(request.MediaId) == nil {
+		return v1.GetRepoMediaProgress400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	state := s.playback.Get(rp.ID(), auth.User(ctx), request.MediaId)
+	return v1.GetRepoMediaProgress200JSONResponse(wrapProgress(state)), nil
+}
+
+func (s *Server) UpdateRepoMediaProgress(ctx context.Context, request v1.UpdateRepoMediaProgressRequestObject) (v1.UpdateRepoMediaProgressResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.UpdateRepoMediaProgress400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if rp.Get(request.MediaId) == nil {
+		return v1.UpdateRepoMediaProgress400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	body := request.Body
+	if body == nil {
+		return v1.UpdateRepoMediaProgress400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "missing request body"}), nil
+	}
+	if body.Position < 0 || math.IsNaN(body.Position) || math.IsInf(body.Position, 0) {
+		return v1.UpdateRepoMediaProgress400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "invalid position"}), nil
+	}
+
+	position := time.Duration(body.Position * float64(time.Second))
+	state := s.playback.Set(rp.ID(), auth.User(ctx), request.MediaId, position, body.Watched)
+
+	return v1.UpdateRepoMediaProgress200JSONResponse(wrapProgress(state)), nil
+}
+
+func (s *Server) HideRepoMedia(ctx context.Context, request v1.HideRepoMediaRequestObject) (v1.HideRepoMediaResponseObject, error) {
+	if e := s.setHidden(ctx, request.RepoId, request.MediaId, derefBool(request.Params.Series), true); e != nil {
+		return v1.HideRepoMedia400JSONResponse(*e), nil
+	}
+
+	return v1.HideRepoMedia204Response{}, nil
+}
+
+func (s *Server) UnhideRepoMedia(ctx context.Context, request v1.UnhideRepoMediaRequestObject) (v1.UnhideRepoMediaResponseObject, error) {
+	if e := s.setHidden(ctx, request.RepoId, request.MediaId, derefBool(request.Params.Series), false); e != nil {
+		return v1.UnhideRepoMedia400JSONResponse(*e), nil
+	}
+
+	return v1.UnhideRepoMedia204Response{}, nil
+}
+
+// setHidden hides media or its series from the listings of the authenticated user or shows it again,
+// returns a client error if it isn't possible.
+func (s *Server) setHidden(ctx context.Context, repoId, mediaId string, series, hidden bool) *v1.Error {
+	rp := s.Repo(repoId)
+	if rp == nil {
+		return &v1.Error{Type: v1.NotFound, Description: "repository not found"}
+	}
+	m := rp.Get(mediaId)
+	if m == nil {
+		return &v1.Error{Type: v1.NotFound, Description: "media not found"}
+	}
+
+	user := auth.User(ctx)
+	if !series {
+		s.playback.SetHidden(rp.ID(), user, m.ID(), hidden)
+		return nil
+	}
+
+	key := seriesKey(m)
+	if key == "" {
+		return &v1.Error{Type: v1.BadRequest, Description: "media is not an episode of a series"}
+	}
+
+	s.playback.SetSeriesHidden(rp.ID(), user, key, hidden)
+	return nil
+}
+
+func (s *Server) FollowRepoMediaSeries(ctx context.Context, request v1.FollowRepoMediaSeriesRequestObject) (v1.FollowRepoMediaSeriesResponseObject, error) {
+	if e := s.setFollowed(ctx, request.RepoId, request.MediaId, true); e != nil {
+		return v1.FollowRepoMediaSeries400JSONResponse(*e), nil
+	}
+
+	return v1.FollowRepoMediaSeries204Response{}, nil
+}
+
+func (s *Server) UnfollowRepoMediaSeries(ctx context.Context, request v1.UnfollowRepoMediaSeriesRequestObject) (v1.UnfollowRepoMediaSeriesResponseObject, error) {
+	if e := s.setFollowed(ctx, request.RepoId, request.MediaId, false); e != nil {
+		return v1.UnfollowRepoMediaSeries400JSONResponse(*e), nil
+	}
+
+	return v1.UnfollowRepoMediaSeries204Response{}, nil
+}
+
+// setFollowed follows the series of an episode for the authenticated user or stops following it,
+// returns a client error if it isn't possible.
+func (s *Server) setFollowed(ctx context.Context, repoId, mediaId string, followed bool) *v1.Error {
+	rp := s.Repo(repoId)
+	if rp == nil {
+		return &v1.Error{Type: v1.NotFound, Description: "repository not found"}
+	}
+	m := rp.Get(mediaId)
+	if m == nil {
+		return &v1.Error{Type: v1.NotFound, Description: "media not found"}
+	}
+
+	key := seriesKey(m)
+	if key == "" {
+		return &v1.Error{Type: v1.BadRequest, Description: "media is not an episode of a series"}
+	}
+
+	s.playback.SetSeriesFollowed(rp.ID(), auth.User(ctx), key, followed)
+	return nil
+}
+
+// notifyFollowers publishes a new episode event for each follower of the series of media added to a repository.
+func (s *Server) notifyFollowers(e repo.Event) {
+	if e.Type != repo.EventMediaAdded || e.Media == nil {
+		return
+	}
+
+	key := seriesKey(e.Media)
+	if key == "" {
+		return
+	}
+
+	for _, user := range s.playback.Followers(e.RepoID, key) {
+		s.events.Publish(event.Event{Type: event.TypeNewEpisode, RepoID: e.RepoID, MediaID: e.Media.ID(), User: user})
+	}
+}
+
+// seriesKey returns the key of the series of an episode among hidden and followed series, empty if the media isn't an episode of a series.
+// Series have no IDs, they're told apart by their title and release year.
+func seriesKey(m media.Media) string {
+	em, ok := m.Meta().(meta.EpisodeMetadata)
+	if !ok || em.Series() == nil {
+		return ""
+	}
+
+	series := em.Series()
+	return fmt.Sprintf("%s (%d)", strings.ToLower(series.Title()), series.ReleaseDate().Year())
+}
+
+// wrapProgress wraps a playback state in a REST representation.
+func wrapProgress(state playback.State) v1.PlaybackProgress {
+	p := v1.PlaybackProgress{
+		Position: state.Position.Seconds(),
+		Watched:  state.Watched,
+	}
+	if !state.Updated.IsZero() {
+		p.UpdatedAt = &state.Updated
+	}
+
+	return p
+}
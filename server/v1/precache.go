@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"go.uber.org/zap"
+)
+
+// Precache queues conversions of media added to or updated in a repository, so that their cache is warm before they're streamed.
+// Media is remuxed to the profile's format, or transcoded to the profile if it has a codec.
+// It must be called before the repository's first scan, media already in the repository is converted on request.
+// Profiles needing settings the conversion backend doesn't honor are refused.
+func (s *Server) Precache(r repo.Repository, profile *media.TranscodeProfile) error {
+	transcode := profile.VideoCodec != "" || profile.AudioCodec != ""
+	if profile.VideoCodec != "" {
+		profile.Watermark = s.watermark(context.Background()) // no user, same as a conversion by an anonymous request
+	}
+	if transcode && s.features != nil {
+		if opt, ok := s.features.UnsupportedOption(profile); ok {
+			return fmt.Errorf("'%s' unsupported by the conversion backend", opt)
+		}
+	}
+
+	r.Events().Subscribe(func(e repo.Event) { // for as long as the repository lives
+		if e.Type != repo.EventMediaAdded && e.Type != repo.EventMediaUpdated {
+			return
+		}
+		if !transcode && e.Media.Format().MIME == profile.Format.MIME { // nothing to remux
+			return
+		}
+
+		var (
+			id    = e.Media.ID()
+			type_ = jobs.TypeRemux
+			fn    = func(ctx context.Context) (media.Media, error) { return r.Remux(ctx, id, profile.Format) }
+		)
+		if transcode {
+			type_ = jobs.TypeTranscode
+			fn = func(ctx context.Context) (media.Media, error) {
+				p := *profile
+				return r.Transcode(ctx, id, &p)
+			}
+		}
+
+		if _, err := s.jobs.Enqueue(type_, r.ID(), id, fn); err != nil && s.logger != nil { // left for the first request
+			s.logger.Warn("failed to queue precache job", zap.String("repo", r.ID()), zap.String("id", id), zap.Error(err))
+		}
+	})
+
+	return nil
+}
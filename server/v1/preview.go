@@ -0,0 +1,94 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/server/api/v1"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// queuePreview queues a job making the preview clip of media, the unfinished job is returned if it's being made already.
+func (s *Server) queuePreview(ctx context.Context, rp repo.Repository, mediaId string) (*jobs.Job, error) {
+	logger := s.log(ctx, rp.ID(), mediaId) // jobs outlive the request, carry its logger over
+	return s.keyed.Enqueue(jobKey("preview", rp.ID(), mediaId), jobs.TypePreview, rp.ID(), mediaId, func(ctx context.Context) (media.Media, error) {
+		return rp.Preview(repo.WithLogger(ctx, logger), mediaId)
+	})
+}
+
+type previewResp struct {
+	path string
+}
+
+func (pr *previewResp) VisitGetRepoMediaPreviewResponse(w http.ResponseWriter, r *http.Request) error {
+	f, err := os.Open(pr.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) { // evicted in the meantime
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			return writeError(w, http.StatusServiceUnavailable, v1.Error{Type: v1.NotReady, Description: "preview was removed while being requested"})
+		}
+
+		return errors.Wrap(err, "failed to open preview")
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return errors.Wrap(err, "failed to stat preview")
+	}
+
+	w.Header().Set("Content-Type", media.FormatMP4.MIME)
+	http.ServeContent(w, r, fi.Name(), fi.ModTime(), f)
+	return nil
+}
+
+func (s *Server) GetRepoMediaPreview(ctx context.Context, request v1.GetRepoMediaPreviewRequestObject) (v1.GetRepoMediaPreviewResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaPreview400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if !rp.Capabilities().Has(repo.CapabilityTranscode) {
+		return v1.GetRepoMediaPreview400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'transcode' capability"}), nil
+	}
+	if s.features == nil || !s.features.Previews {
+		return v1.GetRepoMediaPreview501JSONResponse(v1.Error{Type: v1.Unsupported, Description: "preview clips can't be made by the conversion backend"}), nil
+	}
+
+	m := rp.Get(request.MediaId)
+	if m == nil {
+		return v1.GetRepoMediaPreview400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+	if m.Format().Audio() {
+		return v1.GetRepoMediaPreview400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "media has no video"}), nil
+	}
+
+	pm, err := rp.Preview(repo.WithCachedOnly(ctx), request.MediaId)
+	if err == nil {
+		if pm == nil { // removed in the meantime
+			return v1.GetRepoMediaPreview400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+		}
+
+		return &previewResp{path: pm.Path()}, nil
+	}
+
+	var notReady *repo.ErrNotReady
+	if !errors.As(err, &notReady) {
+		return nil, errors.Wrap(err, "failed to get preview")
+	}
+
+	j, err := s.queuePreview(ctx, rp, request.MediaId)
+	if err != nil {
+		if errors.Is(err, jobs.ErrQueueFull) {
+			return v1.GetRepoMediaPreview400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "job queue is full"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to queue job")
+	}
+
+	return v1.GetRepoMediaPreview202JSONResponse(s.wrapJob(j)), nil
+}
@@ -0,0 +1,20 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/server/api/v1"
+	"testing"
+)
+
+func TestServer_GetRepoMediaPreviewUnsupported(t *testing.T) {
+	s, _ := newStreamServer(t, &repo.Features{})
+
+	res, err := s.GetRepoMediaPreview(context.Background(), v1.GetRepoMediaPreviewRequestObject{RepoId: "test", MediaId: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := res.(v1.GetRepoMediaPreview501JSONResponse); !ok || e.Type != v1.Unsupported {
+		t.Errorf("expected an unsupported response, got %+v", res)
+	}
+}
@@ -0,0 +1,33 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/server/api/v1"
+)
+
+func (s *Server) ProbeRepo(_ context.Context, request v1.ProbeRepoRequestObject) (v1.ProbeRepoResponseObject, error) {
+	r := s.Repo(request.Id)
+	if r == nil {
+		return v1.ProbeRepo400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if !r.Capabilities().Has(repo.CapabilityRemux) {
+		return v1.ProbeRepo400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'remux' capability"}), nil
+	}
+
+	j, err := s.jobs.Enqueue(jobs.TypeProbe, r.ID(), "", func(ctx context.Context) (media.Media, error) {
+		return nil, r.Probe(ctx)
+	})
+	if err != nil {
+		if errors.Is(err, jobs.ErrQueueFull) {
+			return v1.ProbeRepo400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "job queue is full"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to queue job")
+	}
+
+	return v1.ProbeRepo202JSONResponse(s.wrapJob(j)), nil
+}
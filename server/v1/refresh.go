@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/refresh"
+	"github.com/katana-project/katana/server/api/v1"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ScheduleRefresh starts periodically queueing jobs refreshing the metadata of media older than the refresher's maximum age
+// (refresh.Refresher.RefreshDue), checking each repository in the interval, only while no other jobs are queued or running.
+// The refresher must not be nil, it's closed along with the server and refreshing is stopped.
+func (s *Server) ScheduleRefresh(rf *refresh.Refresher, interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.refresher, s.refreshStop, s.refreshDone = rf, cancel, make(chan struct{})
+
+	go func() {
+		defer close(s.refreshDone)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			for repoId, r := range s.repos {
+				mr := r.Mutable()
+				if mr == nil || !s.jobs.Idle() || r.Health().Degraded || rf.Due(r) == 0 { // one at a time, so jobs queued meanwhile come first
+					continue
+				}
+
+				_, err := s.jobs.Enqueue(jobs.TypeRefresh, repoId, "", func(ctx context.Context) (media.Media, error) {
+					return nil, rf.RefreshDue(ctx, mr)
+				})
+				if err != nil && s.logger != nil {
+					s.logger.Warn("failed to queue metadata refresh job", zap.String("repo", repoId), zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// stopRefresh stops the refreshing started by ScheduleRefresh, if any.
+func (s *Server) stopRefresh() {
+	if s.refreshStop != nil {
+		s.refreshStop()
+		<-s.refreshDone
+	}
+}
+
+// refreshMedia resolves the metadata of media again, recording the refresh if metadata is refreshed periodically.
+func (s *Server) refreshMedia(mr repo.MutableRepository, m media.Media) (media.Media, error) {
+	if s.refresher != nil {
+		return s.refresher.Refresh(mr, m)
+	}
+
+	return refresh.Media(mr, m, nil)
+}
+
+func (s *Server) RefreshRepoMediaMeta(ctx context.Context, request v1.RefreshRepoMediaMetaRequestObject) (v1.RefreshRepoMediaMetaResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.RefreshRepoMediaMeta400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	mr := rp.Mutable()
+	if mr == nil {
+		return v1.RefreshRepoMediaMeta400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "repository is not mutable"}), nil
+	}
+
+	m := mr.Get(request.MediaId)
+	if m == nil {
+		return v1.RefreshRepoMediaMeta400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	m, err := s.refreshMedia(mr, m)
+	if err != nil {
+		var (
+			noMeta   *refresh.ErrNoMetadata
+			notFound *repo.ErrMediaNotFound
+			locked   *repo.ErrLocked
+		)
+		if errors.As(err, &noMeta) {
+			return v1.RefreshRepoMediaMeta400JSONResponse(v1.Error{Type: v1.NotFound, Description: "no metadata found"}), nil
+		}
+		if errors.As(err, &locked) {
+			return v1.RefreshRepoMediaMeta400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "media is locked"}), nil
+		}
+		if errors.As(err, &notFound) { // removed in the meantime
+			return v1.RefreshRepoMediaMeta400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to refresh metadata")
+	}
+
+	m0, err := s.wrapMedia(mr.ID(), m, 0, requestLang(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to wrap media")
+	}
+
+	return v1.RefreshRepoMediaMeta200JSONResponse(m0), nil
+}
@@ -0,0 +1,123 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/replica"
+	"github.com/katana-project/katana/server/api/v1"
+	"go.uber.org/zap"
+	"time"
+)
+
+// replicaCheckInterval is the period between checks of replicators being due to replicate.
+const replicaCheckInterval = time.Minute
+
+// ScheduleReplication sets up mirroring repositories of other servers, keyed by the ID of the repository they're mirrored into,
+// and starts queueing replication jobs of those with an interval once it passes.
+// The replicators are stopped along with the server.
+func (s *Server) ScheduleReplication(replicators map[string]*replica.Replicator) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.replicators, s.replicaStop, s.replicaDone = replicators, cancel, make(chan struct{})
+
+	go func() {
+		defer close(s.replicaDone)
+
+		ticker := time.NewTicker(replicaCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			for repoId, rp := range s.replicators {
+				if r := s.repos[repoId]; r == nil || r.Mutable() == nil || r.Health().Degraded || !rp.Due() {
+					continue
+				}
+
+				if _, err := s.replicate(repoId, rp); err != nil && s.logger != nil {
+					s.logger.Warn("failed to queue replication job", zap.String("repo", repoId), zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// stopReplication stops the replication started by ScheduleReplication, if any.
+func (s *Server) stopReplication() {
+	if s.replicaStop != nil {
+		s.replicaStop()
+		<-s.replicaDone
+	}
+}
+
+// replicate queues a job mirroring the origin of a replicator into a repository, it must be mutable.
+func (s *Server) replicate(repoId string, rp *replica.Replicator) (*jobs.Job, error) {
+	mr := s.repos[repoId].Mutable()
+	return s.jobs.Enqueue(jobs.TypeReplicate, repoId, "", func(ctx context.Context) (media.Media, error) {
+		return nil, rp.Replicate(ctx, mr)
+	})
+}
+
+func (s *Server) GetRepoReplica(_ context.Context, request v1.GetRepoReplicaRequestObject) (v1.GetRepoReplicaResponseObject, error) {
+	r := s.Repo(request.Id)
+	if r == nil {
+		return v1.GetRepoReplica400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	m, err := replica.MakeManifest(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make replication manifest")
+	}
+
+	res := v1.ReplicaManifest{Repo: m.Repo, Hash: m.Hash, Items: make([]v1.ReplicaItem, len(m.Items))}
+	for i, item := range m.Items {
+		var media0 map[string]interface{}
+		if err := json.Unmarshal(item.Media, &media0); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal media")
+		}
+
+		res.Items[i] = v1.ReplicaItem{
+			Id:      item.ID,
+			Path:    item.Path,
+			Size:    item.Size,
+			ModTime: makeOptTime(item.ModTime),
+			Digest:  item.Digest,
+			Media:   media0,
+			Tags:    makeOptArray(item.Tags),
+		}
+	}
+
+	return v1.GetRepoReplica200JSONResponse(res), nil
+}
+
+func (s *Server) ReplicateRepo(_ context.Context, request v1.ReplicateRepoRequestObject) (v1.ReplicateRepoResponseObject, error) {
+	r := s.Repo(request.Id)
+	if r == nil {
+		return v1.ReplicateRepo400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if r.Mutable() == nil {
+		return v1.ReplicateRepo400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "repository is not mutable"}), nil
+	}
+
+	rp, ok := s.replicators[r.ID()]
+	if !ok {
+		return v1.ReplicateRepo400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "replication is not configured"}), nil
+	}
+
+	j, err := s.replicate(r.ID(), rp)
+	if err != nil {
+		if errors.Is(err, jobs.ErrQueueFull) {
+			return v1.ReplicateRepo400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "job queue is full"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to queue job")
+	}
+
+	return v1.ReplicateRepo202JSONResponse(s.wrapJob(j)), nil
+}
@@ -2,25 +2,26 @@ package v1
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
-	"github.com/erni27/imcache"
 	"github.com/katana-project/katana/internal/errors"
 	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/jobs"
 	"github.com/katana-project/katana/repo/media"
 	"github.com/katana-project/katana/repo/media/meta"
 	"github.com/katana-project/katana/server/api/v1"
+	"github.com/katana-project/katana/server/auth"
 	"go.uber.org/multierr"
-	"go.uber.org/zap"
 	"golang.org/x/text/language"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
-	"time"
+	"strconv"
+	"strings"
 )
 
-// imageCacheExp is the cache expiration period for non-remote images' data loaded into memory.
-var imageCacheExp = imcache.WithExpiration(5 * time.Minute)
+// retryAfter is the number of seconds after which clients should retry requesting media that is still being made.
+const retryAfter = 10
 
 // WrapMode is a collection of option flags (integers ORed together).
 type WrapMode uint
@@ -36,34 +37,86 @@ func (wm WrapMode) Has(flag WrapMode) bool {
 }
 
 func (s *Server) GetRepos(_ context.Context, _ v1.GetReposRequestObject) (v1.GetReposResponseObject, error) {
-	repos := make([]v1.Repository, 0, len(s.repos))
+	var (
+		repos = make([]v1.Repository, 0, len(s.repos))
+		revs  = make([]repo.Revision, 0, len(s.repos))
+	)
 	for _, r := range s.repos {
 		repos = append(repos, s.wrapRepo(r))
+		revs = append(revs, r.Revision())
 	}
 
-	return v1.GetRepos200JSONResponse(repos), nil
+	return s.validate(v1.GetRepos200JSONResponse(repos).VisitGetReposResponse, revs...), nil
 }
 
 func (s *Server) GetRepoById(_ context.Context, request v1.GetRepoByIdRequestObject) (v1.GetRepoByIdResponseObject, error) {
-	if r, ok := s.repos[request.Id]; ok {
+	if r := s.Repo(request.Id); r != nil {
 		return v1.GetRepoById200JSONResponse(s.wrapRepo(r)), nil
 	}
 
 	return v1.GetRepoById400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
 }
 
-func (s *Server) GetRepoMedia(_ context.Context, request v1.GetRepoMediaRequestObject) (v1.GetRepoMediaResponseObject, error) {
-	r, ok := s.repos[request.Id]
-	if !ok {
+func (s *Server) GetRepoCapabilities(_ context.Context, request v1.GetRepoCapabilitiesRequestObject) (v1.GetRepoCapabilitiesResponseObject, error) {
+	if r := s.Repo(request.Id); r != nil {
+		return v1.GetRepoCapabilities200JSONResponse(s.wrapCapStatuses(r)), nil
+	}
+
+	return v1.GetRepoCapabilities400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+}
+
+func (s *Server) GetRepoMedia(ctx context.Context, request v1.GetRepoMediaRequestObject) (v1.GetRepoMediaResponseObject, error) {
+	r := s.Repo(request.Id)
+	if r == nil {
 		return v1.GetRepoMedia400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
 	}
 
+	query, err := makeRepoQuery(request.Params)
+	if err != nil {
+		return v1.GetRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: err.Error()}), nil
+	}
+	if query.AudioLanguage != language.Und && !r.Capabilities().Has(repo.CapabilityRemux) {
+		return v1.GetRepoMedia400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'remux' capability"}), nil
+	}
+
 	var (
-		items     = r.Items()
-		repoMedia = make([]v1.Media, len(items))
+		user    = auth.User(ctx)
+		filters []func(m media.Media) bool
 	)
+	if request.Params.Watched != nil {
+		filter, err := s.watchedFilter(r.ID(), user, *request.Params.Watched)
+		if err != nil {
+			return v1.GetRepoMedia400JSONResponse(v1.Error{Type: v1.BadRequest, Description: err.Error()}), nil
+		}
+
+		filters = append(filters, filter)
+	}
+	if !derefBool(request.Params.IncludeHidden) {
+		if filter := s.hiddenFilter(r.ID(), user); filter != nil {
+			filters = append(filters, filter)
+		}
+	}
+	if len(filters) > 0 {
+		query.Filter = func(m media.Media) bool {
+			for _, filter := range filters {
+				if !filter(m) {
+					return false
+				}
+			}
+
+			return true
+		}
+	}
+
+	rev := r.Revision() // before taking the snapshot, a concurrent change makes the validators outdated, not the items
+	items, total, err := r.Query(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query media")
+	}
+
+	repoMedia := make([]v1.Media, len(items))
 	for i, item := range items {
-		m, err := s.wrapMedia(item, WrapModeBasicImages)
+		m, err := s.wrapMedia(r.ID(), item, WrapModeBasicImages, requestLang(ctx))
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to wrap media")
 		}
@@ -71,31 +124,160 @@ func (s *Server) GetRepoMedia(_ context.Context, request v1.GetRepoMediaRequestO
 		repoMedia[i] = m
 	}
 
-	return v1.GetRepoMedia200JSONResponse(repoMedia), nil
+	res := v1.GetRepoMedia200JSONResponse{
+		Body:    repoMedia,
+		Headers: v1.GetRepoMedia200ResponseHeaders{XTotalCount: total},
+	}
+	if query.Filter != nil || query.AudioLanguage != language.Und { // playback states and probe results aren't revisioned
+		return res, nil
+	}
+	return s.validate(res.VisitGetRepoMediaResponse, rev), nil
+}
+
+// hiddenFilter makes a repo.Query filter of media not hidden by a user, returns nil if the user hasn't hidden any.
+func (s *Server) hiddenFilter(repoId, user string) func(m media.Media) bool {
+	var (
+		states = s.playback.States(repoId, user)
+		series = s.playback.HiddenSeries(repoId, user)
+		hidden = make(map[string]struct{})
+	)
+	for mediaId, state := range states {
+		if state.Hidden {
+			hidden[mediaId] = struct{}{}
+		}
+	}
+	if len(hidden) == 0 && len(series) == 0 {
+		return nil
+	}
+
+	return func(m media.Media) bool {
+		if _, ok := hidden[m.ID()]; ok {
+			return false
+		}
+		if key := seriesKey(m); key != "" {
+			if _, ok := series[key]; ok {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// watchedFilter makes a repo.Query filter of media by their playback state for a user.
+func (s *Server) watchedFilter(repoId, user string, watched v1.WatchedFilter) (func(m media.Media) bool, error) {
+	states := s.playback.States(repoId, user)
+	switch watched {
+	case v1.True:
+		return func(m media.Media) bool {
+			return states[m.ID()].Watched
+		}, nil
+	case v1.False:
+		return func(m media.Media) bool {
+			return !states[m.ID()].Watched
+		}, nil
+	case v1.InProgress:
+		return func(m media.Media) bool {
+			state := states[m.ID()]
+			return !state.Watched && state.Position > 0
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown watched filter '%s'", watched)
+}
+
+// makeRepoQuery translates GetRepoMedia parameters to a repo.Query.
+func makeRepoQuery(params v1.GetRepoMediaParams) (*repo.Query, error) {
+	q := &repo.Query{
+		Genre:  derefString(params.Genre),
+		Tag:    derefString(params.Tag),
+		Sort:   repo.SortTitle,
+		Offset: derefInt(params.Offset),
+		Limit:  derefInt(params.Limit),
+	}
+	if params.Type != nil {
+		type_, err := parseMetaType(*params.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		q.Type = &type_
+	}
+	if params.Year != nil {
+		q.Year = *params.Year
+	}
+	if params.Match != nil {
+		switch *params.Match {
+		case v1.MatchFilterUnmatched:
+			q.Match = repo.MatchUnmatched
+		case v1.MatchFilterLowConfidence:
+			q.Match = repo.MatchLowConfidence
+		default:
+			return nil, fmt.Errorf("unknown match filter '%s'", *params.Match)
+		}
+	}
+	if params.AudioLanguage != nil {
+		lang, err := language.Parse(*params.AudioLanguage)
+		if err != nil || lang == language.Und {
+			return nil, fmt.Errorf("invalid audio language '%s'", *params.AudioLanguage)
+		}
+
+		q.AudioLanguage = lang
+	}
+	if params.Sort != nil {
+		switch *params.Sort {
+		case v1.MediaSortKeyTitle:
+			q.Sort = repo.SortTitle
+		case v1.MediaSortKeyReleaseDate:
+			q.Sort = repo.SortReleaseDate
+		case v1.MediaSortKeyAddedAt:
+			q.Sort = repo.SortAdded
+		default:
+			return nil, fmt.Errorf("unknown sort key '%s'", *params.Sort)
+		}
+	}
+	if params.Order != nil {
+		switch *params.Order {
+		case v1.Asc:
+		case v1.Desc:
+			q.Descending = true
+		default:
+			return nil, fmt.Errorf("unknown sort order '%s'", *params.Order)
+		}
+	}
+	if q.Offset < 0 {
+		return nil, fmt.Errorf("negative offset %d", q.Offset)
+	}
+	if params.Limit != nil && q.Limit < 1 {
+		return nil, fmt.Errorf("non-positive limit %d", q.Limit)
+	}
+
+	return q, nil
 }
 
-func (s *Server) GetRepoMediaById(_ context.Context, request v1.GetRepoMediaByIdRequestObject) (v1.GetRepoMediaByIdResponseObject, error) {
-	r, ok := s.repos[request.RepoId]
-	if !ok {
+func (s *Server) GetRepoMediaById(ctx context.Context, request v1.GetRepoMediaByIdRequestObject) (v1.GetRepoMediaByIdResponseObject, error) {
+	r := s.Repo(request.RepoId)
+	if r == nil {
 		return v1.GetRepoMediaById400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
 	}
 
+	rev := r.Revision()
 	m := r.Get(request.MediaId)
 	if m == nil {
 		return v1.GetRepoMediaById400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
 	}
 
-	m0, err := s.wrapMedia(m, 0)
+	m0, err := s.wrapMedia(r.ID(), m, 0, requestLang(ctx))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to wrap media")
 	}
 
-	return v1.GetRepoMediaById200JSONResponse(m0), nil
+	return s.validate(v1.GetRepoMediaById200JSONResponse(m0).VisitGetRepoMediaByIdResponse, rev), nil
 }
 
 func (s *Server) GetRepoMediaDownload(_ context.Context, request v1.GetRepoMediaDownloadRequestObject) (v1.GetRepoMediaDownloadResponseObject, error) {
-	rp, ok := s.repos[request.RepoId]
-	if !ok {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
 		return v1.GetRepoMediaDownload400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
 	}
 
@@ -105,22 +287,103 @@ func (s *Server) GetRepoMediaDownload(_ context.Context, request v1.GetRepoMedia
 	}
 
 	format := m.Format()
-	return &streamResp{path: m.Path(), mime: format.MIME}, nil
+	return s.streamResp(rp, request.MediaId, m.Path(), format.MIME), nil
 }
 
-func (s *Server) GetRepoMediaStreams(_ context.Context, _ v1.GetRepoMediaStreamsRequestObject) (v1.GetRepoMediaStreamsResponseObject, error) {
-	//TODO implement me
-	panic("implement me")
+func (s *Server) GetRepoMediaStreams(ctx context.Context, request v1.GetRepoMediaStreamsRequestObject) (v1.GetRepoMediaStreamsResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaStreams400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	m := rp.Get(request.MediaId)
+	if m == nil {
+		return v1.GetRepoMediaStreams400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	var (
+		rawFormat = m.Format()
+
+		variants = []v1.MediaFormat{{Name: "raw", Mime: rawFormat.MIME, Extension: rawFormat.Extension}}
+		offers   = []string{rawFormat.MIME} // in order of preference on ties
+	)
+	if rp.Capabilities().Has(repo.CapabilityRemux) {
+		for _, format := range media.Formats() {
+			if format.MIME == rawFormat.MIME {
+				continue
+			}
+
+			variants = append(variants, v1.MediaFormat{Name: strings.ToLower(format.Name), Mime: format.MIME, Extension: format.Extension})
+			offers = append(offers, format.MIME)
+		}
+	}
+	offers = append(offers, "application/json")
+
+	var (
+		accept = derefString(request.Params.Accept)
+		resp   v1.GetRepoMediaStreamsResponseObject
+	)
+	switch mime := negotiate(accept, offers); mime {
+	case "":
+		resp = v1.GetRepoMediaStreams406JSONResponse(v1.Error{Type: v1.UnknownFormat, Description: "no acceptable format"})
+	case "application/json":
+		resp = v1.GetRepoMediaStreams200JSONResponse(variants)
+	case rawFormat.MIME:
+		resp = s.streamResp(rp, request.MediaId, m.Path(), mime)
+	default:
+		rm, err := rp.Remux(repo.WithNoWait(s.detach(ctx, rp.ID(), request.MediaId)), request.MediaId, media.FindFormatMIME(mime))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && s.restoreArchived(ctx, rp, request.MediaId) {
+				return v1.GetRepoMediaStreams503JSONResponse{
+					Body:    v1.Error{Type: v1.NotReady, Description: "media is archived, restoring it"},
+					Headers: v1.GetRepoMediaStreams503ResponseHeaders{RetryAfter: retryAfter},
+				}, nil
+			}
+			if errors.Is(err, fs.ErrNotExist) && s.gone(ctx, rp, request.MediaId) {
+				return v1.GetRepoMediaStreams410JSONResponse(v1.Error{Type: v1.Gone, Description: "media file not found"}), nil
+			}
+
+			var notReady *repo.ErrNotReady
+			if !errors.As(err, &notReady) {
+				return nil, errors.Wrap(err, "failed to remux media")
+			}
+
+			if negotiate(accept, offers[:1]) == "" { // raw format not acceptable, can't fall back
+				return v1.GetRepoMediaStreams503JSONResponse{
+					Body:    v1.Error{Type: v1.NotReady, Description: "media is still being remuxed"},
+					Headers: v1.GetRepoMediaStreams503ResponseHeaders{RetryAfter: retryAfter},
+				}, nil
+			}
+
+			rm = m
+		}
+		if rm == nil { // removed in the meantime
+			return v1.GetRepoMediaStreams400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+		}
+
+		resp = s.streamResp(rp, request.MediaId, rm.Path(), rm.Format().MIME)
+	}
+
+	return &negotiatedResp{resp}, nil
 }
 
-func (s *Server) GetRepoMediaStream(_ context.Context, request v1.GetRepoMediaStreamRequestObject) (v1.GetRepoMediaStreamResponseObject, error) {
-	rp, ok := s.repos[request.RepoId]
-	if !ok {
+func (s *Server) GetRepoMediaStream(ctx context.Context, request v1.GetRepoMediaStreamRequestObject) (v1.GetRepoMediaStreamResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
 		return v1.GetRepoMediaStream400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
 	}
 
+	if v := request.Params.Start; v != nil && !(*v >= 0) {
+		return v1.GetRepoMediaStream400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "start position must not be negative"}), nil
+	}
+	start := derefSeconds(request.Params.Start)
+
 	var m media.Media
 	if request.Format == "raw" {
+		if start > 0 {
+			return v1.GetRepoMediaStream400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "raw streams can't start past the beginning"}), nil
+		}
+
 		m = rp.Get(request.MediaId)
 	} else {
 		if !rp.Capabilities().Has(repo.CapabilityRemux) {
@@ -132,9 +395,71 @@ func (s *Server) GetRepoMediaStream(_ context.Context, request v1.GetRepoMediaSt
 			return v1.GetRepoMediaStream400JSONResponse(v1.Error{Type: v1.UnknownFormat, Description: fmt.Sprintf("unknown format '%s'", request.Format)}), nil
 		}
 
+		mode := v1.StreamModeWait
+		if request.Params.Mode != nil {
+			mode = *request.Params.Mode
+		}
+
+		if mode == v1.StreamModeProgressive && (s.features == nil || !s.features.Fragmenting) {
+			return v1.GetRepoMediaStream400JSONResponse(v1.Error{Type: v1.UnknownFormat, Description: "progressive streaming unsupported by the conversion backend"}), nil
+		}
+		if start > 0 { // transcoded, streams copied from the start position
+			if !rp.Capabilities().Has(repo.CapabilityTranscode) {
+				return v1.GetRepoMediaStream400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'transcode' capability"}), nil
+			}
+			if mode == v1.StreamModeProgressive {
+				return v1.GetRepoMediaStream400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "progressive streams can't start past the beginning"}), nil
+			}
+			if s.features != nil {
+				if opt, ok := s.features.UnsupportedOption(&media.TranscodeProfile{Format: format, Start: start}); ok {
+					return v1.GetRepoMediaStream400JSONResponse(v1.Error{Type: v1.UnknownFormat, Description: fmt.Sprintf("'%s' unsupported by the conversion backend", opt)}), nil
+				}
+			}
+		}
+
 		var err error
-		m, err = rp.Remux(request.MediaId, format)
+		switch mode {
+		case v1.StreamModeProgressive:
+			var pr *partialResp
+			if m, pr, err = s.remuxPartial(ctx, rp, request.MediaId, format); pr != nil {
+				return pr, nil
+			}
+		case v1.StreamModeJob:
+			m, err = streamFormat(repo.WithCachedOnly(ctx), rp, request.MediaId, format, start)
+		default:
+			m, err = streamFormat(repo.WithNoWait(s.detach(ctx, rp.ID(), request.MediaId)), rp, request.MediaId, format, start) // others may wait for the remux made by this request, it's finished even if the request ends
+		}
 		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && s.restoreArchived(ctx, rp, request.MediaId) {
+				return v1.GetRepoMediaStream503JSONResponse{
+					Body:    v1.Error{Type: v1.NotReady, Description: "media is archived, restoring it"},
+					Headers: v1.GetRepoMediaStream503ResponseHeaders{RetryAfter: retryAfter},
+				}, nil
+			}
+			if errors.Is(err, fs.ErrNotExist) && s.gone(ctx, rp, request.MediaId) {
+				return v1.GetRepoMediaStream410JSONResponse(v1.Error{Type: v1.Gone, Description: "media file not found"}), nil
+			}
+
+			var notReady *repo.ErrNotReady
+			if errors.As(err, &notReady) && mode == v1.StreamModeJob {
+				j, err := s.queueRemux(ctx, rp, request.MediaId, format, start)
+				if err != nil {
+					if errors.Is(err, jobs.ErrQueueFull) {
+						return v1.GetRepoMediaStream400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "job queue is full"}), nil
+					}
+
+					return nil, errors.Wrap(err, "failed to queue job")
+				}
+
+				return v1.GetRepoMediaStream202JSONResponse(s.wrapJob(j)), nil
+			}
+			if errors.As(err, &notReady) {
+				return v1.GetRepoMediaStream503JSONResponse{
+					Body:    v1.Error{Type: v1.NotReady, Description: "media is still being remuxed"},
+					Headers: v1.GetRepoMediaStream503ResponseHeaders{RetryAfter: retryAfter},
+				}, nil
+			}
+
 			return nil, errors.Wrap(err, "failed to remux media")
 		}
 	}
@@ -144,16 +469,35 @@ func (s *Server) GetRepoMediaStream(_ context.Context, request v1.GetRepoMediaSt
 	}
 
 	format := m.Format()
-	return &streamResp{path: m.Path(), mime: format.MIME}, nil
+	return s.streamResp(rp, request.MediaId, m.Path(), format.MIME), nil
 }
 
 type streamResp struct {
 	path, mime string
+
+	s       *Server
+	repo    repo.Repository
+	mediaId string
+}
+
+// streamResp makes a response streaming a file of media, bytes written are recorded in the statistics store,
+// per media and per client, it's refused if the client's monthly transfer cap is exceeded.
+func (s *Server) streamResp(r repo.Repository, mediaId, path, mime string) *streamResp {
+	return &streamResp{path: path, mime: mime, s: s, repo: r, mediaId: mediaId}
 }
 
 func (sr *streamResp) writeResponse(disp string, w http.ResponseWriter, r *http.Request) (err error) {
+	client := auth.Client(r.Context())
+	if sr.s.capExceeded(client) {
+		return writeError(w, http.StatusTooManyRequests, v1.Error{Type: v1.QuotaExceeded, Description: "monthly transfer cap exceeded"})
+	}
+
 	f, err := os.Open(sr.path)
 	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return sr.writeMissing(w, r)
+		}
+
 		return errors.Wrap(err, "failed to open media")
 	}
 	defer func() {
@@ -170,65 +514,153 @@ func (sr *streamResp) writeResponse(disp string, w http.ResponseWriter, r *http.
 	w.Header().Set("Content-Type", sr.mime)
 	w.Header().Set("Content-Disposition", disp)
 
-	http.ServeContent(w, r, fi.Name(), fi.ModTime(), f)
+	cw := &countingWriter{ResponseWriter: w}
+	defer func() {
+		sr.s.stats.Add(sr.repo.ID(), sr.mediaId, cw.n)
+		sr.s.stats.AddClient(client, cw.n)
+	}()
+
+	http.ServeContent(cw, r, fi.Name(), fi.ModTime(), f)
 	return err
 }
 
+// writeMissing writes a response about the streamed file missing, it's gone for good if the media file is missing,
+// unless it's archived and being restored, a cache file is made again on the next request.
+func (sr *streamResp) writeMissing(w http.ResponseWriter, r *http.Request) error {
+	if sr.s.restoreArchived(r.Context(), sr.repo, sr.mediaId) {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		return writeError(w, http.StatusServiceUnavailable, v1.Error{Type: v1.NotReady, Description: "media is archived, restoring it"})
+	}
+	if sr.s.gone(r.Context(), sr.repo, sr.mediaId) {
+		return writeError(w, http.StatusGone, v1.Error{Type: v1.Gone, Description: "media file not found"})
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	return writeError(w, http.StatusServiceUnavailable, v1.Error{Type: v1.NotReady, Description: "media was removed while being requested"})
+}
+
 func (sr *streamResp) VisitGetRepoMediaStreamResponse(w http.ResponseWriter, r *http.Request) error {
 	return sr.writeResponse("inline", w, r)
 }
 
+func (sr *streamResp) VisitGetRepoMediaStreamsResponse(w http.ResponseWriter, r *http.Request) error {
+	return sr.writeResponse("inline", w, r)
+}
+
 func (sr *streamResp) VisitGetRepoMediaDownloadResponse(w http.ResponseWriter, r *http.Request) error {
 	return sr.writeResponse(fmt.Sprintf("attachment; filename=\"%s\"", filepath.Base(sr.path)), w, r)
 }
 
+// negotiatedResp is a response picked based on the Accept header, it marks the response as such for caches.
+type negotiatedResp struct {
+	v1.GetRepoMediaStreamsResponseObject
+}
+
+func (nr *negotiatedResp) VisitGetRepoMediaStreamsResponse(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Add("Vary", "Accept")
+	return nr.GetRepoMediaStreamsResponseObject.VisitGetRepoMediaStreamsResponse(w, r)
+}
+
 func (s *Server) wrapRepo(r repo.Repository) v1.Repository {
+	health := r.Health()
 	return v1.Repository{
-		Id:           r.ID(),
-		Name:         r.Name(),
-		Capabilities: s.wrapCaps(r.Capabilities()),
+		Id:             r.ID(),
+		Aliases:        makeOptArray(s.Aliases(r.ID())),
+		Name:           r.Name(),
+		Capabilities:   s.wrapCaps(r.Capabilities()),
+		Degraded:       health.Degraded,
+		DegradedReason: makeOptString(health.Reason),
 	}
 }
 
+// capabilities are the repository capabilities mapped to their REST representation, in the order of listing.
+var capabilities = []struct {
+	cap  repo.Capability
+	name v1.RepositoryCapability
+}{
+	{repo.CapabilityWatch, v1.RepositoryCapabilityWatch},
+	{repo.CapabilityIndex, v1.RepositoryCapabilityIndex},
+	{repo.CapabilityRemux, v1.RepositoryCapabilityRemux},
+	{repo.CapabilityTranscode, v1.RepositoryCapabilityTranscode},
+	{repo.CapabilityArchive, v1.RepositoryCapabilityArchive},
+}
+
 func (s *Server) wrapCaps(c repo.Capability) []v1.RepositoryCapability {
 	var caps []v1.RepositoryCapability
-	if c.Has(repo.CapabilityWatch) {
-		caps = append(caps, v1.Watch)
-	}
-	if c.Has(repo.CapabilityIndex) {
-		caps = append(caps, v1.Index)
-	}
-	if c.Has(repo.CapabilityRemux) {
-		caps = append(caps, v1.Remux)
-	}
-	if c.Has(repo.CapabilityTranscode) {
-		caps = append(caps, v1.Transcode)
+	for _, capability := range capabilities {
+		if c.Has(capability.cap) {
+			caps = append(caps, capability.name)
+		}
 	}
 
 	return caps
 }
 
-func (s *Server) wrapMedia(m media.Media, mode WrapMode) (v1.Media, error) {
+// wrapCapStatuses wraps the availability of all capabilities of a repository in a REST representation.
+func (s *Server) wrapCapStatuses(r repo.Repository) []v1.CapabilityStatus {
+	var (
+		c          = r.Capabilities()
+		reasons    = s.reasons[r.ID()]
+		negotiated = r.Unavailable()
+		statuses   = make([]v1.CapabilityStatus, 0, len(capabilities))
+	)
+	for _, capability := range capabilities {
+		status := v1.CapabilityStatus{Capability: capability.name, Available: c.Has(capability.cap)}
+		if !status.Available {
+			reason, ok := negotiated[capability.cap] // left out by a wrapper, rather than the wrapper not being made
+			if !ok {
+				reason, ok = reasons[capability.cap]
+			}
+			if !ok {
+				reason = "not enabled in the repository configuration"
+			}
+
+			status.Reason = &reason
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+func (s *Server) wrapMedia(repoId string, m media.Media, mode WrapMode, lang language.Tag) (v1.Media, error) {
 	var (
 		err error
 
 		repoMeta  = m.Meta()
 		mediaMeta *v1.Media_Meta
+		tags      = []string{}
+		locked    bool
+		r         = s.Repo(repoId)
 	)
+	if r != nil {
+		if t := r.Tags(m.ID()); t != nil {
+			tags = t
+		}
+		locked = r.Locked(m.ID())
+	}
 	if repoMeta != nil {
-		mediaMeta, err = s.wrapMediaMeta(repoMeta, mode)
+		loc := locale{lang: lang}
+		if r != nil && lang != language.Und {
+			loc.source = r.Source()
+		}
+
+		mediaMeta, err = s.wrapMediaMeta(repoMeta, imageBase(repoId, m.ID()), mode, loc)
 		if err != nil {
 			return v1.Media{}, err
 		}
 	}
 
 	return v1.Media{
-		Id:   m.ID(),
-		Meta: mediaMeta,
+		Id:     m.ID(),
+		Locked: locked,
+		Meta:   mediaMeta,
+		Tags:   tags,
 	}, nil
 }
 
-func (s *Server) wrapMediaMeta(m meta.Metadata, mode WrapMode) (*v1.Media_Meta, error) {
+func (s *Server) wrapMediaMeta(m meta.Metadata, imageBase string, mode WrapMode, loc locale) (*v1.Media_Meta, error) {
 	var (
 		mm  = &v1.Media_Meta{}
 		err error
@@ -236,39 +668,41 @@ func (s *Server) wrapMediaMeta(m meta.Metadata, mode WrapMode) (*v1.Media_Meta,
 
 	switch metaVariant := m.(type) {
 	case meta.EpisodeMetadata:
-		err = mm.FromEpisodeMetadata(s.wrapEpisodeMeta(metaVariant, mode))
+		err = mm.FromEpisodeMetadata(s.wrapEpisodeMeta(metaVariant, imageBase, mode, loc))
 	case meta.MovieOrSeriesMetadata:
 		switch metaVariant.Type() {
 		case meta.TypeMovie:
-			err = mm.FromMovieMetadata(s.wrapMovieMeta(metaVariant, mode))
+			err = mm.FromMovieMetadata(s.wrapMovieMeta(metaVariant, imageBase, mode, loc))
 		case meta.TypeSeries:
-			err = mm.FromSeriesMetadata(s.wrapSeriesMeta(metaVariant, mode))
+			err = mm.FromSeriesMetadata(s.wrapSeriesMeta(metaVariant, imageBase, mode, loc))
 		default: // the metadata instance is breaking its contract, just force it to be generic
-			err = mm.FromMetadata(s.wrapMeta(metaVariant, mode))
+			err = mm.FromMetadata(s.wrapMeta(metaVariant, imageBase, mode, loc))
 		}
 	default:
-		err = mm.FromMetadata(s.wrapMeta(metaVariant, mode))
+		err = mm.FromMetadata(s.wrapMeta(metaVariant, imageBase, mode, loc))
 	}
 
 	return mm, err
 }
 
-func (s *Server) wrapMovieMeta(m meta.MovieOrSeriesMetadata, mode WrapMode) v1.MovieMetadata {
+func (s *Server) wrapMovieMeta(m meta.MovieOrSeriesMetadata, imageBase string, mode WrapMode, loc locale) v1.MovieMetadata {
 	return v1.MovieMetadata{
-		Title:         m.Title(),
-		OriginalTitle: makeOptString(m.OriginalTitle()),
-		Overview:      makeOptString(m.Overview()),
-		ReleaseDate:   m.ReleaseDate(),
-		VoteRating:    m.VoteRating(),
-		Images:        s.wrapImages(m.Images(), mode),
-		Genres:        m.Genres(),
-		Cast:          s.wrapCastMembers(m.Cast(), mode),
-		Languages:     s.wrapLanguages(m.Languages()),
-		Countries:     s.wrapCountries(m.Countries()),
+		Title:              m.Title(),
+		OriginalTitle:      makeOptString(m.OriginalTitle()),
+		Overview:           makeOptString(m.Overview()),
+		ReleaseDate:        m.ReleaseDate(),
+		ReleaseDateDisplay: formatDate(m.ReleaseDate(), loc.lang),
+		VoteRating:         m.VoteRating(),
+		Confidence:         makeOptFloat(meta.Confidence(m)),
+		Images:             s.wrapImages(m.Images(), imageBase, mode),
+		Genres:             s.localizeGenres(m.Genres(), meta.TypeMovie, loc),
+		Cast:               s.wrapCastMembers(m.Cast(), imageBase, mode),
+		Languages:          s.wrapLanguages(m.Languages()),
+		Countries:          s.wrapCountries(m.Countries()),
 	}
 }
 
-func (s *Server) wrapImages(ims []meta.Image, mode WrapMode) []v1.Image {
+func (s *Server) wrapImages(ims []meta.Image, imageBase string, mode WrapMode) []v1.Image {
 	var images []v1.Image
 	for _, i := range ims {
 		type_ := i.Type()
@@ -276,48 +710,13 @@ func (s *Server) wrapImages(ims []meta.Image, mode WrapMode) []v1.Image {
 			continue // basic only sends backdrops and posters
 		}
 
-		im, err := s.wrapImage(i)
-		if err != nil {
-			s.logger.Error(
-				"failed to read image, skipping",
-				zap.String("path", i.Path()),
-				zap.Bool("remote", i.Remote()),
-				zap.String("description", i.Description()),
-				zap.Error(err),
-			)
-			continue
-		}
-
-		images = append(images, im)
+		images = append(images, s.wrapImage(i, imageBase))
 	}
 
 	return images
 }
 
-func (s *Server) wrapImage(i meta.Image) (v1.Image, error) {
-	var (
-		path   = i.Path()
-		remote = i.Remote()
-	)
-	if !remote {
-		if path0, ok := s.imageCache.Get(path); ok {
-			path = path0
-		} else {
-			b, err := os.ReadFile(path)
-			if err != nil {
-				return v1.Image{}, err
-			}
-
-			data := fmt.Sprintf(
-				"data:%s;base64,%s",
-				http.DetectContentType(b),
-				base64.StdEncoding.EncodeToString(b),
-			)
-			s.imageCache.Set(path, data, imageCacheExp)
-			path = data
-		}
-	}
-
+func (s *Server) wrapImage(i meta.Image, imageBase string) v1.Image {
 	type_ := v1.ImageTypeUnknown
 	switch i.Type() {
 	case meta.ImageTypeStill:
@@ -330,44 +729,32 @@ func (s *Server) wrapImage(i meta.Image) (v1.Image, error) {
 		type_ = v1.ImageTypeAvatar
 	}
 
+	id := imageID(i)
 	return v1.Image{
+		Id:          id,
 		Type:        type_,
-		Path:        path,
-		Remote:      remote,
+		Url:         imageBase + id,
 		Description: makeOptString(i.Description()),
-	}, nil
+	}
 }
 
-func (s *Server) wrapCastMembers(cms []meta.CastMember, mode WrapMode) []v1.CastMember {
+func (s *Server) wrapCastMembers(cms []meta.CastMember, imageBase string, mode WrapMode) []v1.CastMember {
 	if cms == nil {
 		return nil
 	}
 
 	castMembers := make([]v1.CastMember, len(cms))
 	for i, cm := range cms {
-		var (
-			image  = cm.Image()
-			image0 *v1.Image
-		)
-		if image != nil && !mode.Has(WrapModeBasicImages) {
-			im, err := s.wrapImage(image)
-			if err != nil {
-				s.logger.Error(
-					"failed to read cast member image, skipping",
-					zap.String("path", image.Path()),
-					zap.Bool("remote", image.Remote()),
-					zap.String("description", image.Description()),
-					zap.Error(err),
-				)
-			} else {
-				image0 = &im
-			}
+		var image *v1.Image
+		if cmImage := cm.Image(); cmImage != nil && !mode.Has(WrapModeBasicImages) {
+			im := s.wrapImage(cmImage, imageBase)
+			image = &im
 		}
 
 		castMembers[i] = v1.CastMember{
 			Name:  cm.Name(),
 			Role:  cm.Role(),
-			Image: image0,
+			Image: image,
 		}
 	}
 
@@ -400,42 +787,48 @@ func (s *Server) wrapCountries(rgs []language.Region) []string {
 	return regions
 }
 
-func (s *Server) wrapSeriesMeta(m meta.MovieOrSeriesMetadata, mode WrapMode) v1.SeriesMetadata {
+func (s *Server) wrapSeriesMeta(m meta.MovieOrSeriesMetadata, imageBase string, mode WrapMode, loc locale) v1.SeriesMetadata {
 	return v1.SeriesMetadata{
-		Title:         m.Title(),
-		OriginalTitle: makeOptString(m.OriginalTitle()),
-		Overview:      makeOptString(m.Overview()),
-		ReleaseDate:   m.ReleaseDate(),
-		VoteRating:    m.VoteRating(),
-		Images:        s.wrapImages(m.Images(), mode),
-		Genres:        m.Genres(),
-		Cast:          s.wrapCastMembers(m.Cast(), mode),
-		Languages:     s.wrapLanguages(m.Languages()),
-		Countries:     s.wrapCountries(m.Countries()),
+		Title:              m.Title(),
+		OriginalTitle:      makeOptString(m.OriginalTitle()),
+		Overview:           makeOptString(m.Overview()),
+		ReleaseDate:        m.ReleaseDate(),
+		ReleaseDateDisplay: formatDate(m.ReleaseDate(), loc.lang),
+		VoteRating:         m.VoteRating(),
+		Confidence:         makeOptFloat(meta.Confidence(m)),
+		Images:             s.wrapImages(m.Images(), imageBase, mode),
+		Genres:             s.localizeGenres(m.Genres(), meta.TypeSeries, loc),
+		Cast:               s.wrapCastMembers(m.Cast(), imageBase, mode),
+		Languages:          s.wrapLanguages(m.Languages()),
+		Countries:          s.wrapCountries(m.Countries()),
 	}
 }
 
-func (s *Server) wrapEpisodeMeta(m meta.EpisodeMetadata, mode WrapMode) v1.EpisodeMetadata {
+func (s *Server) wrapEpisodeMeta(m meta.EpisodeMetadata, imageBase string, mode WrapMode, loc locale) v1.EpisodeMetadata {
 	return v1.EpisodeMetadata{
-		Title:         m.Title(),
-		OriginalTitle: makeOptString(m.OriginalTitle()),
-		Overview:      makeOptString(m.Overview()),
-		ReleaseDate:   m.ReleaseDate(),
-		VoteRating:    m.VoteRating(),
-		Images:        s.wrapImages(m.Images(), mode),
-		Series:        s.wrapSeriesMeta(m.Series(), mode),
-		Season:        m.Season(),
-		Episode:       m.Episode(),
+		Title:              m.Title(),
+		OriginalTitle:      makeOptString(m.OriginalTitle()),
+		Overview:           makeOptString(m.Overview()),
+		ReleaseDate:        m.ReleaseDate(),
+		ReleaseDateDisplay: formatDate(m.ReleaseDate(), loc.lang),
+		VoteRating:         m.VoteRating(),
+		Confidence:         makeOptFloat(meta.Confidence(m)),
+		Images:             s.wrapImages(m.Images(), imageBase, mode),
+		Series:             s.wrapSeriesMeta(m.Series(), imageBase, mode, loc),
+		Season:             m.Season(),
+		Episode:            m.Episode(),
 	}
 }
 
-func (s *Server) wrapMeta(m meta.Metadata, mode WrapMode) v1.Metadata {
+func (s *Server) wrapMeta(m meta.Metadata, imageBase string, mode WrapMode, loc locale) v1.Metadata {
 	return v1.Metadata{
-		Title:         m.Title(),
-		OriginalTitle: makeOptString(m.OriginalTitle()),
-		Overview:      makeOptString(m.Overview()),
-		ReleaseDate:   m.ReleaseDate(),
-		VoteRating:    m.VoteRating(),
-		Images:        s.wrapImages(m.Images(), mode),
+		Title:              m.Title(),
+		OriginalTitle:      makeOptString(m.OriginalTitle()),
+		Overview:           makeOptString(m.Overview()),
+		ReleaseDate:        m.ReleaseDate(),
+		ReleaseDateDisplay: formatDate(m.ReleaseDate(), loc.lang),
+		VoteRating:         m.VoteRating(),
+		Confidence:         makeOptFloat(meta.Confidence(m)),
+		Images:             s.wrapImages(m.Images(), imageBase, mode),
 	}
 }
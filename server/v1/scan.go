@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/internal/trace"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/server/api/v1"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Scan queues a job scanning a repository for media, the unfinished job is returned if it's being scanned already.
+// ErrUnsupportedOperation is returned if the repository is not mutable.
+func (s *Server) Scan(r repo.Repository, mode repo.ScanMode) (*jobs.Job, error) {
+	mr := r.Mutable()
+	if mr == nil {
+		return nil, &repo.ErrUnsupportedOperation{
+			Operation: "scan",
+			Repo:      r.ID(),
+		}
+	}
+
+	return s.keyed.Enqueue(jobKey("scan", r.ID()), jobs.TypeScan, r.ID(), "", func(ctx context.Context) (media.Media, error) {
+		_, span := trace.Start(ctx, "repo.scan", attribute.String("repo.id", r.ID()), attribute.Bool("repo.scan.full", mode == repo.ScanFull))
+		defer span.End()
+
+		err := mr.Scan(mode)
+		if span.IsRecording() { // don't copy the items for nothing
+			trace.Fail(span, err)
+			span.SetAttributes(attribute.Int("repo.media_count", len(mr.Items())))
+		}
+
+		return nil, err
+	})
+}
+
+// LastScan returns the last scan job of a repository, nil if it hasn't been scanned in the job retention period.
+func (s *Server) LastScan(r repo.Repository) *jobs.Job {
+	return s.keyed.Get(jobKey("scan", r.ID()))
+}
+
+func (s *Server) ScanRepo(_ context.Context, request v1.ScanRepoRequestObject) (v1.ScanRepoResponseObject, error) {
+	r := s.Repo(request.Id)
+	if r == nil {
+		return v1.ScanRepo400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	mode := repo.ScanIncremental
+	if derefBool(request.Params.Full) {
+		mode = repo.ScanFull
+	}
+
+	j, err := s.Scan(r, mode)
+	if err != nil {
+		var unsupportedOp *repo.ErrUnsupportedOperation
+		switch {
+		case errors.As(err, &unsupportedOp):
+			return v1.ScanRepo400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "repository is not mutable"}), nil
+		case errors.Is(err, jobs.ErrQueueFull):
+			return v1.ScanRepo400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "job queue is full"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to queue job")
+	}
+
+	return v1.ScanRepo202JSONResponse(s.wrapJob(j)), nil
+}
+
+func (s *Server) GetRepoScan(_ context.Context, request v1.GetRepoScanRequestObject) (v1.GetRepoScanResponseObject, error) {
+	r := s.Repo(request.Id)
+	if r == nil {
+		return v1.GetRepoScan400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	j := s.LastScan(r)
+	if j == nil {
+		return v1.GetRepoScan400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not scanned yet"}), nil
+	}
+
+	return v1.GetRepoScan200JSONResponse(s.wrapJob(j)), nil
+}
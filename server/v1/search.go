@@ -0,0 +1,58 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/katana/server/api/v1"
+)
+
+func (s *Server) SearchMeta(_ context.Context, request v1.SearchMetaRequestObject) (v1.SearchMetaResponseObject, error) {
+	rp := s.Repo(request.Params.RepoId)
+	if rp == nil {
+		return v1.SearchMeta400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if request.Params.Q == "" {
+		return v1.SearchMeta400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "missing query"}), nil
+	}
+
+	query := &meta.Query{Query: request.Params.Q, Type: meta.TypeUnknown, Season: -1, Episode: -1}
+	if request.Params.Type != nil {
+		type_, err := parseMetaType(*request.Params.Type)
+		if err != nil {
+			return v1.SearchMeta400JSONResponse(v1.Error{Type: v1.BadRequest, Description: err.Error()}), nil
+		}
+
+		query.Type = type_
+	}
+
+	candidates, err := rp.Source().Search(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search metadata")
+	}
+
+	res := make([]v1.MetadataCandidate, len(candidates))
+	for i, c := range candidates {
+		res[i] = wrapCandidate(c)
+	}
+
+	return v1.SearchMeta200JSONResponse(res), nil
+}
+
+// wrapCandidate translates a meta.MetadataCandidate to a MetadataCandidate, posters are only linked if they're remote.
+func wrapCandidate(c meta.MetadataCandidate) v1.MetadataCandidate {
+	mc := v1.MetadataCandidate{
+		Id:            c.ID,
+		Type:          wrapMetaType(c.Type),
+		Title:         c.Title,
+		OriginalTitle: makeOptString(c.OriginalTitle),
+		Overview:      makeOptString(c.Overview),
+		ReleaseDate:   makeOptTime(c.ReleaseDate),
+		Score:         c.Score,
+	}
+	if c.Poster != nil && c.Poster.Remote() {
+		mc.PosterUrl = makeOptString(c.Poster.Path())
+	}
+
+	return mc
+}
@@ -0,0 +1,149 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/katana/server/api/v1"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/text/language"
+	"strings"
+)
+
+// seriesGroup is the episodes of a series in a repository.
+type seriesGroup struct {
+	id       string
+	series   meta.MovieOrSeriesMetadata
+	episodes []media.Media // sorted by season and episode number
+}
+
+// seriesID returns the ID of the series of an episode, empty if the media isn't an episode of a series.
+// Series are told apart by their title and release year, like in seriesKey.
+// Example: "Breaking Bad" (2008) -> "breaking-bad-2008"
+func seriesID(m media.Media) string {
+	em, ok := m.Meta().(meta.EpisodeMetadata)
+	if !ok || em.Series() == nil {
+		return ""
+	}
+
+	series := em.Series()
+	return media.SanitizeID(fmt.Sprintf("%s %d", series.Title(), series.ReleaseDate().Year()))
+}
+
+// groupSeries groups episodes into their series, sorted by title.
+func groupSeries(items []media.Media) []*seriesGroup {
+	groups := make(map[string]*seriesGroup)
+	for _, item := range items {
+		id := seriesID(item)
+		if id == "" {
+			continue
+		}
+
+		g, ok := groups[id]
+		if !ok {
+			g = &seriesGroup{id: id, series: item.Meta().(meta.EpisodeMetadata).Series()}
+			groups[id] = g
+		}
+		g.episodes = append(g.episodes, item)
+	}
+
+	res := maps.Values(groups)
+	for _, g := range res {
+		slices.SortFunc(g.episodes, compareEpisodes)
+	}
+	slices.SortFunc(res, func(a, b *seriesGroup) int {
+		if c := strings.Compare(strings.ToLower(a.series.Title()), strings.ToLower(b.series.Title())); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.id, b.id)
+	})
+
+	return res
+}
+
+// compareEpisodes compares episodes by their season and episode number.
+func compareEpisodes(a, b media.Media) int {
+	am, bm := a.Meta().(meta.EpisodeMetadata), b.Meta().(meta.EpisodeMetadata)
+	if am.Season() != bm.Season() {
+		return am.Season() - bm.Season()
+	}
+	if am.Episode() != bm.Episode() {
+		return am.Episode() - bm.Episode()
+	}
+
+	return strings.Compare(a.ID(), b.ID())
+}
+
+func (s *Server) GetRepoSeries(ctx context.Context, request v1.GetRepoSeriesRequestObject) (v1.GetRepoSeriesResponseObject, error) {
+	r := s.Repo(request.Id)
+	if r == nil {
+		return v1.GetRepoSeries400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	var (
+		rev    = r.Revision() // before taking the snapshot, like in GetRepoMedia
+		groups = groupSeries(r.Items())
+		loc    = locale{lang: requestLang(ctx)}
+		res    = make([]v1.Series, len(groups))
+	)
+	if loc.lang != language.Und {
+		loc.source = r.Source()
+	}
+	for i, g := range groups {
+		var seasons []v1.SeasonSummary
+		for _, ep := range g.episodes {
+			season := ep.Meta().(meta.EpisodeMetadata).Season()
+			if n := len(seasons); n > 0 && seasons[n-1].Season == season {
+				seasons[n-1].EpisodeCount++
+			} else {
+				seasons = append(seasons, v1.SeasonSummary{Season: season, EpisodeCount: 1})
+			}
+		}
+
+		res[i] = v1.Series{
+			Id:           g.id,
+			Meta:         s.wrapSeriesMeta(g.series, imageBase(r.ID(), g.episodes[0].ID()), WrapModeBasicImages, loc), // the episodes' images include the series'
+			Seasons:      seasons,
+			EpisodeCount: len(g.episodes),
+		}
+	}
+
+	return s.validate(v1.GetRepoSeries200JSONResponse(res).VisitGetRepoSeriesResponse, rev), nil
+}
+
+func (s *Server) GetRepoSeriesSeason(ctx context.Context, request v1.GetRepoSeriesSeasonRequestObject) (v1.GetRepoSeriesSeasonResponseObject, error) {
+	r := s.Repo(request.Id)
+	if r == nil {
+		return v1.GetRepoSeriesSeason400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	var (
+		rev      = r.Revision()
+		episodes []media.Media
+	)
+	for _, item := range r.Items() {
+		if seriesID(item) == request.SeriesId && item.Meta().(meta.EpisodeMetadata).Season() == request.Season {
+			episodes = append(episodes, item)
+		}
+	}
+	if len(episodes) == 0 {
+		return v1.GetRepoSeriesSeason400JSONResponse(v1.Error{Type: v1.NotFound, Description: "series or season not found"}), nil
+	}
+	slices.SortFunc(episodes, compareEpisodes)
+
+	res := v1.Season{SeriesId: request.SeriesId, Season: request.Season, Episodes: make([]v1.Media, len(episodes))}
+	for i, ep := range episodes {
+		m, err := s.wrapMedia(r.ID(), ep, WrapModeBasicImages, requestLang(ctx))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to wrap media")
+		}
+
+		res.Episodes[i] = m
+	}
+
+	return s.validate(v1.GetRepoSeriesSeason200JSONResponse(res).VisitGetRepoSeriesSeasonResponse, rev), nil
+}
@@ -1,15 +1,32 @@
 package v1
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/erni27/imcache"
+	"github.com/katana-project/katana/internal/errors"
 	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/checksum"
+	"github.com/katana-project/katana/repo/event"
+	"github.com/katana-project/katana/repo/identify"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/mux"
+	"github.com/katana-project/katana/repo/refresh"
+	"github.com/katana-project/katana/repo/replica"
+	"github.com/katana-project/katana/repo/trash"
 	"github.com/katana-project/katana/server/api/v1"
+	"github.com/katana-project/katana/server/collection"
+	"github.com/katana-project/katana/server/playback"
+	"github.com/katana-project/katana/server/stats"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 )
 
 // ErrorHandler handles translating errors to HTTP responses.
@@ -27,28 +44,119 @@ var (
 	}
 
 	DefaultResponseErrorHandler ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		code, cause := errors.Classify(err)
+		status := errorStatus(code)
+		if logger := repo.Logger(r.Context(), nil); logger != nil && status == http.StatusInternalServerError {
+			logger.Error("failed to handle request", zap.String("method", r.Method), zap.String("path", r.URL.Path), zap.Error(err))
+		}
+
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(status)
 
-		e := v1.Error{Type: v1.InternalError, Description: err.Error()}
+		e := v1.Error{Type: v1.ErrorType(code), Description: cause.Error()} // the classified error for client errors, the whole chain otherwise
 		if err := json.NewEncoder(w).Encode(e); err != nil {
 			_, _ = fmt.Fprintf(w, "{\"type\":\"%s\",\"description\":\"%s\"}", v1.InternalError, "failed to serialize error")
 		}
 	}
 )
 
+// errorStatus returns the HTTP response status code of an error code.
+// Client errors are 400 Bad Request, like the responses documented in the API specification.
+func errorStatus(code errors.Code) int {
+	switch code {
+	case errors.CodeInternal:
+		return http.StatusInternalServerError
+	case errors.CodeNotReady, errors.CodeUnavailable:
+		return http.StatusServiceUnavailable
+	case errors.CodeGone:
+		return http.StatusGone
+	case errors.CodeUnauthorized:
+		return http.StatusUnauthorized
+	}
+
+	return http.StatusBadRequest
+}
+
 // Server is a REST server for the Katana v1 API.
 type Server struct {
-	repos  map[string]repo.Repository
-	logger *zap.Logger
+	repos      map[string]repo.Repository
+	aliases    map[string]string                     // alias -> repository ID
+	reasons    map[string]map[repo.Capability]string // repository ID -> unavailable capability -> reason
+	features   *repo.Features
+	formats    []mux.FormatSupport       // nil if there's no media conversion backend
+	wm         *media.Watermark          // transcoded video watermark with a text template, nil if there's none
+	identifier *identify.Identifier      // nil if identification isn't enabled
+	dash       []*media.TranscodeProfile // DASH representation profiles, nil if DASH streaming is disabled
+	jobs       *jobs.Queue
+	stats      *stats.Store
+	playback   *playback.Store
+	colls      *collection.Store
+	catalog    *checksum.Catalog
+	trash      map[string]*trash.Bin // repository ID -> trash bin
+	events     *event.Bus
+	logger     *zap.Logger
+	epoch      int64 // server creation time, distinguishes caching validators of different server runs
+
+	capDefault int64            // monthly transfer cap of clients, zero if unlimited
+	caps       map[string]int64 // client ID -> monthly transfer cap, overriding capDefault
 
-	imageCache imcache.Cache[string, string] // non-remote image data, base64-encoded data:image URLs
+	images    *imageCache
+	qualities *sessionQualities // temporary quality caps of client sessions
+
+	keyed *jobs.Registry // jobs not queued twice for the same thing, such as scans of a repository (see jobKey)
+
+	maintStop context.CancelFunc // stops the maintenance loop, nil if it's not running
+	maintDone chan struct{}
+
+	refresher   *refresh.Refresher // nil if metadata isn't refreshed periodically
+	refreshStop context.CancelFunc // stops the refresh loop, nil if it's not running
+	refreshDone chan struct{}
+
+	replicators map[string]*replica.Replicator // repository ID -> replicator, nil if no repositories are replicated
+	replicaStop context.CancelFunc             // stops the replication loop, nil if it's not running
+	replicaDone chan struct{}
+
+	latestMu   sync.Mutex
+	latest     string             // version of the latest release, empty if it wasn't checked
+	updateStop context.CancelFunc // stops the update check loop, nil if it's not running
+	updateDone chan struct{}
+
+	ctx    context.Context    // lifetime of the server, for work shared by requests (see detach)
+	cancel context.CancelFunc // cancels ctx on closing
 }
 
-// NewServer creates a new server with pre-defined repositories.
-func NewServer(repos []repo.Repository, logger *zap.Logger) (*Server, error) {
-	reposById := make(map[string]repo.Repository, len(repos))
-	for _, r := range repos {
+// Options are the dependencies of a Server.
+type Options struct {
+	// Repos are the pre-defined repositories of the server.
+	Repos []repo.Repository
+	// Aliases are additional IDs repositories can be addressed by, a mapping of alias IDs to repository IDs, can be nil.
+	Aliases map[string]string
+	// Unavailable is a mapping of repository IDs to reasons of their capabilities being unavailable, can be nil;
+	// it covers the capabilities whose repository wrappers couldn't be made, the rest are reported by the repositories (repo.Repository.Unavailable).
+	Unavailable map[string]map[repo.Capability]string
+	// Features are the detected media processing components, reported by the system endpoint, can be nil.
+	Features *repo.Features
+	// Queue processes media conversions, it's closed along with the server.
+	Queue *jobs.Queue
+	// Stats records bytes streamed, it's closed along with the server.
+	Stats *stats.Store
+	// Playback keeps playback progress of users, it's closed along with the server.
+	Playback *playback.Store
+	// Collections keeps collections of media across repositories.
+	Collections *collection.Store
+	// Catalog keeps media checksums, can be nil if they're not computed, it's closed along with the server.
+	Catalog *checksum.Catalog
+	// Trash are the trash bins files of deleted media are moved to, keyed by repository ID, can be nil; they're closed along with the server.
+	Trash map[string]*trash.Bin
+	// Events streams change notifications to clients, followers of series are notified of their new episodes through it as well;
+	// it's closed along with the server.
+	Events *event.Bus
+}
+
+// NewServer creates a new server with pre-defined repositories (see Options).
+func NewServer(opts *Options, logger *zap.Logger) (*Server, error) {
+	reposById := make(map[string]repo.Repository, len(opts.Repos))
+	for _, r := range opts.Repos {
 		repoId := r.ID()
 		if _, ok := reposById[repoId]; ok {
 			return nil, fmt.Errorf("duplicate repository ID %s", repoId)
@@ -57,13 +165,45 @@ func NewServer(repos []repo.Repository, logger *zap.Logger) (*Server, error) {
 		reposById[repoId] = r
 	}
 
-	return &Server{
-		repos:  reposById,
-		logger: logger,
-	}, nil
+	for alias, repoId := range opts.Aliases {
+		if !repo.ValidID(alias) {
+			return nil, fmt.Errorf("invalid repository alias %s", alias)
+		}
+		if _, ok := reposById[alias]; ok {
+			return nil, fmt.Errorf("repository alias %s shadows a repository ID", alias)
+		}
+		if _, ok := reposById[repoId]; !ok {
+			return nil, fmt.Errorf("repository alias %s of unknown repository ID %s", alias, repoId)
+		}
+	}
+
+	s := &Server{
+		repos:     reposById,
+		aliases:   opts.Aliases,
+		reasons:   opts.Unavailable,
+		features:  opts.Features,
+		jobs:      opts.Queue,
+		stats:     opts.Stats,
+		playback:  opts.Playback,
+		colls:     opts.Collections,
+		catalog:   opts.Catalog,
+		trash:     opts.Trash,
+		events:    opts.Events,
+		logger:    logger,
+		epoch:     time.Now().UnixNano(),
+		images:    newImageCache(),
+		qualities: newSessionQualities(),
+		keyed:     jobs.NewRegistry(opts.Queue),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	for _, r := range opts.Repos {
+		r.Events().Subscribe(s.notifyFollowers) // for as long as the repository lives
+	}
+
+	return s, nil
 }
 
-// NewRouter creates a new v1 API router.
+// NewRouter creates a new v1 API router, responses are localized to the language requested by clients (see requestLang).
 func NewRouter(baseUrl string, handler v1.StrictServerInterface) http.Handler {
 	h := v1.NewStrictHandlerWithOptions(handler, nil, v1.StrictHTTPServerOptions{
 		RequestErrorHandlerFunc:  DefaultRequestErrorHandler,
@@ -72,18 +212,109 @@ func NewRouter(baseUrl string, handler v1.StrictServerInterface) http.Handler {
 
 	return v1.HandlerWithOptions(h, v1.ChiServerOptions{
 		BaseURL:          baseUrl,
+		Middlewares:      []v1.MiddlewareFunc{localize},
 		ErrorHandlerFunc: DefaultRequestErrorHandler,
 	})
 }
 
+// Repo returns a repository by its ID or alias, returns nil if not found.
+func (s *Server) Repo(id string) repo.Repository {
+	if repoId, ok := s.aliases[id]; ok {
+		id = repoId
+	}
+
+	return s.repos[id]
+}
+
+// Aliases returns the aliases of a repository ID, sorted.
+func (s *Server) Aliases(id string) []string {
+	var aliases []string
+	for alias, repoId := range s.aliases {
+		if repoId == id {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	slices.Sort(aliases)
+	return aliases
+}
+
+// log returns the logger of a request (repo.Logger) with fields of the repository and media it concerns, can be empty.
+// The server's logger is used for requests without one, returns nil if neither is available.
+func (s *Server) log(ctx context.Context, repoId, mediaId string) *zap.Logger {
+	logger := repo.Logger(ctx, s.logger)
+	if logger == nil {
+		return nil
+	}
+
+	var fields []zap.Field
+	if repoId != "" {
+		fields = append(fields, zap.String("repo", repoId))
+	}
+	if mediaId != "" {
+		fields = append(fields, zap.String("media", mediaId))
+	}
+
+	return logger.With(fields...)
+}
+
+// jobKey returns the key of a job in the keyed job registry, the kind of the job followed by the IDs of what it's for.
+func jobKey(kind string, ids ...string) string {
+	return kind + ":" + strings.Join(ids, "/")
+}
+
+// detach returns a context of the server's lifetime carrying the logger of a request (see log),
+// for work shared with other requests, which mustn't be canceled along with the request starting it.
+func (s *Server) detach(ctx context.Context, repoId, mediaId string) context.Context {
+	return repo.WithLogger(s.ctx, s.log(ctx, repoId, mediaId))
+}
+
 // Repos returns all repositories available to the server.
 func (s *Server) Repos() []repo.Repository {
 	return maps.Values(s.repos)
 }
 
-// Close cleans up residual data after the server.
-func (s *Server) Close() (err error) {
-	for _, r := range s.repos {
+// Drain ends event streams, so that the HTTP server doesn't wait for them when shutting down.
+func (s *Server) Drain() {
+	s.events.Close()
+}
+
+// Close cleans up residual data after the server, running jobs are canceled.
+func (s *Server) Close() error {
+	s.stopMaintenance()
+	s.stopRefresh()
+	s.stopReplication()
+	s.stopUpdateCheck()
+	return s.close(s.jobs.Close())
+}
+
+// Shutdown cleans up residual data after the server, running jobs are waited for until the context is done (see jobs.Queue.Shutdown).
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.stopMaintenance()
+	s.stopRefresh()
+	s.stopReplication()
+	s.stopUpdateCheck()
+	return s.close(s.jobs.Shutdown(ctx))
+}
+
+// close closes the server's stores and repositories, after its jobs have been stopped with an error.
+func (s *Server) close(jobsErr error) (err error) {
+	s.cancel()
+	s.Drain()
+	err = multierr.Combine(jobsErr, s.stats.Close(), s.playback.Close()) // jobs are stopped before the repositories go away
+	if s.catalog != nil {
+		err = multierr.Append(err, s.catalog.Close())
+	}
+	if s.refresher != nil {
+		err = multierr.Append(err, s.refresher.Close())
+	}
+	if s.identifier != nil {
+		err = multierr.Append(err, s.identifier.Close())
+	}
+	for _, b := range s.trash {
+		err = multierr.Append(err, b.Close())
+	}
+	for _, r := range s.repos { // each closes the repositories it wraps after itself (index before mux, ...)
 		err = multierr.Append(err, r.Close())
 	}
 
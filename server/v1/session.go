@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/server/api/v1"
+	"github.com/katana-project/katana/server/auth"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sessionQualityTTL is the time without requests after which the quality cap of a client session is forgotten.
+const sessionQualityTTL = 24 * time.Hour
+
+// sessionKey is a key of a client session of a user.
+type sessionKey struct {
+	user, session string
+}
+
+// qualityCap is a temporary quality cap of a client session, zero limits are not capped.
+type qualityCap struct {
+	maxHeight                        int
+	maxVideoBitRate, maxAudioBitRate int64
+}
+
+// apply limits a transcode profile to the cap, only streams being re-encoded are limited.
+func (qc qualityCap) apply(profile *media.TranscodeProfile) {
+	if profile.VideoCodec != "" {
+		if qc.maxHeight > 0 && (profile.Height == 0 || profile.Height > qc.maxHeight) {
+			profile.Width, profile.Height = 0, qc.maxHeight // keep the aspect ratio
+		}
+		if qc.maxVideoBitRate > 0 && (profile.VideoBitRate == 0 || profile.VideoBitRate > qc.maxVideoBitRate) {
+			profile.VideoBitRate = qc.maxVideoBitRate
+		}
+	}
+	if profile.AudioCodec != "" && qc.maxAudioBitRate > 0 && (profile.AudioBitRate == 0 || profile.AudioBitRate > qc.maxAudioBitRate) {
+		profile.AudioBitRate = qc.maxAudioBitRate
+	}
+}
+
+// sessionCap is a quality cap along with the time of the session's last request.
+type sessionCap struct {
+	qualityCap
+	lastUsed time.Time
+}
+
+// sessionQualities are the quality caps of client sessions, kept in memory.
+type sessionQualities struct {
+	mu   sync.Mutex
+	caps map[sessionKey]*sessionCap
+}
+
+func newSessionQualities() *sessionQualities {
+	return &sessionQualities{caps: make(map[sessionKey]*sessionCap)}
+}
+
+// get returns the quality cap of a session and marks it as used, returns false if it has none.
+func (sq *sessionQualities) get(key sessionKey) (qualityCap, bool) {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+
+	sq.prune()
+	sc, ok := sq.caps[key]
+	if !ok {
+		return qualityCap{}, false
+	}
+
+	sc.lastUsed = time.Now()
+	return sc.qualityCap, true
+}
+
+// set replaces the quality cap of a session, the cap is removed if it has no limits.
+func (sq *sessionQualities) set(key sessionKey, qc qualityCap) {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+
+	sq.prune()
+	if qc == (qualityCap{}) {
+		delete(sq.caps, key)
+	} else {
+		sq.caps[key] = &sessionCap{qualityCap: qc, lastUsed: time.Now()}
+	}
+}
+
+// prune forgets the caps of sessions idle for longer than sessionQualityTTL, mu must be held.
+func (sq *sessionQualities) prune() {
+	now := time.Now()
+	for key, sc := range sq.caps {
+		if now.Sub(sc.lastUsed) > sessionQualityTTL {
+			delete(sq.caps, key)
+		}
+	}
+}
+
+// sessionOf makes the key of a client session of the requesting user, returns false if the session ID is blank.
+func sessionOf(ctx context.Context, session string) (sessionKey, bool) {
+	session = strings.TrimSpace(session)
+	return sessionKey{user: auth.User(ctx), session: session}, session != ""
+}
+
+func (s *Server) GetSessionQuality(ctx context.Context, request v1.GetSessionQualityRequestObject) (v1.GetSessionQualityResponseObject, error) {
+	key, ok := sessionOf(ctx, request.Params.XKatanaSession)
+	if !ok {
+		return v1.GetSessionQuality400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "missing session ID"}), nil
+	}
+
+	qc, _ := s.qualities.get(key)
+	return v1.GetSessionQuality200JSONResponse(wrapQualityCap(qc)), nil
+}
+
+func (s *Server) UpdateSessionQuality(ctx context.Context, request v1.UpdateSessionQualityRequestObject) (v1.UpdateSessionQualityResponseObject, error) {
+	key, ok := sessionOf(ctx, request.Params.XKatanaSession)
+	if !ok {
+		return v1.UpdateSessionQuality400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "missing session ID"}), nil
+	}
+
+	body := request.Body
+	if body == nil {
+		return v1.UpdateSessionQuality400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "missing request body"}), nil
+	}
+
+	qc := qualityCap{maxHeight: derefInt(body.MaxHeight)}
+	if body.MaxVideoBitRate != nil {
+		qc.maxVideoBitRate = *body.MaxVideoBitRate
+	}
+	if body.MaxAudioBitRate != nil {
+		qc.maxAudioBitRate = *body.MaxAudioBitRate
+	}
+	if qc.maxHeight < 0 || qc.maxVideoBitRate < 0 || qc.maxAudioBitRate < 0 ||
+		body.MaxHeight != nil && qc.maxHeight == 0 || body.MaxVideoBitRate != nil && qc.maxVideoBitRate == 0 || body.MaxAudioBitRate != nil && qc.maxAudioBitRate == 0 {
+		return v1.UpdateSessionQuality400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "limits must be positive"}), nil
+	}
+
+	s.qualities.set(key, qc)
+	return v1.UpdateSessionQuality200JSONResponse(wrapQualityCap(qc)), nil
+}
+
+func (s *Server) DeleteSessionQuality(ctx context.Context, request v1.DeleteSessionQualityRequestObject) (v1.DeleteSessionQualityResponseObject, error) {
+	key, ok := sessionOf(ctx, request.Params.XKatanaSession)
+	if !ok {
+		return v1.DeleteSessionQuality400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "missing session ID"}), nil
+	}
+
+	s.qualities.set(key, qualityCap{})
+	return v1.DeleteSessionQuality204Response{}, nil
+}
+
+// wrapQualityCap wraps a quality cap in a REST representation, zero limits are left out.
+func wrapQualityCap(qc qualityCap) v1.SessionQuality {
+	var sq v1.SessionQuality
+	if qc.maxHeight > 0 {
+		sq.MaxHeight = &qc.maxHeight
+	}
+	if qc.maxVideoBitRate > 0 {
+		sq.MaxVideoBitRate = &qc.maxVideoBitRate
+	}
+	if qc.maxAudioBitRate > 0 {
+		sq.MaxAudioBitRate = &qc.maxAudioBitRate
+	}
+
+	return sq
+}
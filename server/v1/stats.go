@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/server/api/v1"
+	"github.com/katana-project/katana/server/stats"
+	"io"
+	"net/http"
+)
+
+// countingWriter is a http.ResponseWriter counting the bytes of the written body.
+type countingWriter struct {
+	http.ResponseWriter
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.ResponseWriter.Write(p)
+	cw.n += int64(n)
+
+	return n, err
+}
+
+// ReadFrom keeps the optimized copy of the underlying writer (sendfile) available to http.ServeContent.
+func (cw *countingWriter) ReadFrom(r io.Reader) (int64, error) {
+	var (
+		n   int64
+		err error
+	)
+	if rf, ok := cw.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(r)
+	} else {
+		n, err = io.Copy(cw.ResponseWriter, r)
+	}
+	cw.n += n
+
+	return n, err
+}
+
+func (cw *countingWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func (s *Server) GetRepoMediaStats(_ context.Context, request v1.GetRepoMediaStatsRequestObject) (v1.GetRepoMediaStatsResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaStats400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if rp.Get(request.MediaId) == nil {
+		return v1.GetRepoMediaStats400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	var (
+		days = s.stats.Media(rp.ID(), request.MediaId)
+		res  = v1.MediaStats{Days: make([]v1.DayStats, len(days))}
+	)
+	for i, day := range days {
+		res.Days[i] = v1.DayStats{Date: day.Date.Format(stats.DayLayout), Bytes: day.Bytes}
+		res.TotalBytes += day.Bytes
+	}
+
+	return v1.GetRepoMediaStats200JSONResponse(res), nil
+}
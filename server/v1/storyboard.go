@@ -0,0 +1,146 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/storyboard"
+	"github.com/katana-project/katana/server/api/v1"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// queueStoryboard queues a job making the storyboard of media, the unfinished job is returned if it's being made already.
+func (s *Server) queueStoryboard(ctx context.Context, rp repo.Repository, mediaId string) (*jobs.Job, error) {
+	logger := s.log(ctx, rp.ID(), mediaId) // jobs outlive the request, carry its logger over
+	return s.keyed.Enqueue(jobKey("storyboard", rp.ID(), mediaId), jobs.TypeStoryboard, rp.ID(), mediaId, func(ctx context.Context) (media.Media, error) {
+		_, err := rp.Storyboard(repo.WithLogger(ctx, logger), mediaId)
+		return nil, err
+	})
+}
+
+func (s *Server) GetRepoMediaStoryboard(ctx context.Context, request v1.GetRepoMediaStoryboardRequestObject) (v1.GetRepoMediaStoryboardResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaStoryboard400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if !rp.Capabilities().Has(repo.CapabilityTranscode) {
+		return v1.GetRepoMediaStoryboard400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'transcode' capability"}), nil
+	}
+	if s.features == nil || !s.features.Storyboards {
+		return v1.GetRepoMediaStoryboard501JSONResponse(v1.Error{Type: v1.Unsupported, Description: "thumbnails can't be taken by the conversion backend"}), nil
+	}
+
+	m := rp.Get(request.MediaId)
+	if m == nil {
+		return v1.GetRepoMediaStoryboard400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+	if m.Format().Audio() {
+		return v1.GetRepoMediaStoryboard400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "media has no video"}), nil
+	}
+
+	sb, err := rp.Storyboard(repo.WithCachedOnly(ctx), request.MediaId)
+	if err == nil {
+		if sb == nil { // removed in the meantime
+			return v1.GetRepoMediaStoryboard400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+		}
+
+		// relative to the track URL, resolving to the sheet operation
+		var buf bytes.Buffer
+		if err := storyboard.WriteTrack(&buf, sb, func(sheet int) string { return fmt.Sprintf("storyboard/%d", sheet) }); err != nil {
+			return nil, errors.Wrap(err, "failed to write thumbnail track")
+		}
+
+		return v1.GetRepoMediaStoryboard200TextvttResponse{Body: &buf, ContentLength: int64(buf.Len())}, nil
+	}
+
+	var notReady *repo.ErrNotReady
+	if !errors.As(err, &notReady) {
+		return nil, errors.Wrap(err, "failed to get storyboard")
+	}
+
+	j, err := s.queueStoryboard(ctx, rp, request.MediaId)
+	if err != nil {
+		if errors.Is(err, jobs.ErrQueueFull) {
+			return v1.GetRepoMediaStoryboard400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "job queue is full"}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to queue job")
+	}
+
+	return v1.GetRepoMediaStoryboard202JSONResponse(s.wrapJob(j)), nil
+}
+
+type sheetResp struct {
+	path string
+}
+
+func (sr *sheetResp) VisitGetRepoMediaStoryboardSheetResponse(w http.ResponseWriter, r *http.Request) error {
+	f, err := os.Open(sr.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) { // evicted in the meantime
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			return writeError(w, http.StatusServiceUnavailable, v1.Error{Type: v1.NotReady, Description: "sprite sheet was removed while being requested"})
+		}
+
+		return errors.Wrap(err, "failed to open sprite sheet")
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return errors.Wrap(err, "failed to stat sprite sheet")
+	}
+
+	w.Header().Set("Content-Type", storyboard.MIME)
+	http.ServeContent(w, r, fi.Name(), fi.ModTime(), f)
+	return nil
+}
+
+func (s *Server) GetRepoMediaStoryboardSheet(ctx context.Context, request v1.GetRepoMediaStoryboardSheetRequestObject) (v1.GetRepoMediaStoryboardSheetResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaStoryboardSheet400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if !rp.Capabilities().Has(repo.CapabilityTranscode) {
+		return v1.GetRepoMediaStoryboardSheet400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'transcode' capability"}), nil
+	}
+	if s.features == nil || !s.features.Storyboards {
+		return v1.GetRepoMediaStoryboardSheet501JSONResponse(v1.Error{Type: v1.Unsupported, Description: "thumbnails can't be taken by the conversion backend"}), nil
+	}
+
+	m := rp.Get(request.MediaId)
+	if m == nil {
+		return v1.GetRepoMediaStoryboardSheet400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+	if m.Format().Audio() {
+		return v1.GetRepoMediaStoryboardSheet400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "media has no video"}), nil
+	}
+
+	sb, err := rp.Storyboard(repo.WithCachedOnly(ctx), request.MediaId)
+	if err != nil {
+		var notReady *repo.ErrNotReady
+		if errors.As(err, &notReady) {
+			return v1.GetRepoMediaStoryboardSheet503JSONResponse{
+				Body:    v1.Error{Type: v1.NotReady, Description: "storyboard has not been made yet"},
+				Headers: v1.GetRepoMediaStoryboardSheet503ResponseHeaders{RetryAfter: retryAfter},
+			}, nil
+		}
+
+		return nil, errors.Wrap(err, "failed to get storyboard")
+	}
+	if sb == nil { // removed in the meantime
+		return v1.GetRepoMediaStoryboardSheet400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+	if request.Sheet < 0 || request.Sheet >= len(sb.Paths) {
+		return v1.GetRepoMediaStoryboardSheet400JSONResponse(v1.Error{Type: v1.NotFound, Description: "sheet not found"}), nil
+	}
+
+	return &sheetResp{path: sb.Paths[request.Sheet]}, nil
+}
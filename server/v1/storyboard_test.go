@@ -0,0 +1,28 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/server/api/v1"
+	"testing"
+)
+
+func TestServer_GetRepoMediaStoryboardUnsupported(t *testing.T) {
+	s, _ := newStreamServer(t, &repo.Features{})
+
+	res, err := s.GetRepoMediaStoryboard(context.Background(), v1.GetRepoMediaStoryboardRequestObject{RepoId: "test", MediaId: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := res.(v1.GetRepoMediaStoryboard501JSONResponse); !ok || e.Type != v1.Unsupported {
+		t.Errorf("expected an unsupported response, got %+v", res)
+	}
+
+	sheetRes, err := s.GetRepoMediaStoryboardSheet(context.Background(), v1.GetRepoMediaStoryboardSheetRequestObject{RepoId: "test", MediaId: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := sheetRes.(v1.GetRepoMediaStoryboardSheet501JSONResponse); !ok || e.Type != v1.Unsupported {
+		t.Errorf("expected an unsupported response, got %+v", sheetRes)
+	}
+}
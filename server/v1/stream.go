@@ -0,0 +1,191 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/jobs"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/server/api/v1"
+	"github.com/katana-project/katana/server/auth"
+	"go.uber.org/multierr"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"time"
+)
+
+// partialPoll is the period of checking a file being written for new data.
+const partialPoll = 250 * time.Millisecond
+
+// streamFormat remuxes media to a format for streaming it, or transcodes it from a start position with its streams copied if it's positive.
+func streamFormat(ctx context.Context, rp repo.Repository, mediaId string, format *media.Format, start time.Duration) (media.Media, error) {
+	if start > 0 {
+		return rp.Transcode(ctx, mediaId, &media.TranscodeProfile{Format: format, Start: start})
+	}
+
+	return rp.Remux(ctx, mediaId, format)
+}
+
+// queueRemux queues a job remuxing media to a format, or transcoding it from a start position (see streamFormat),
+// the unfinished job is returned if it's being remuxed already.
+func (s *Server) queueRemux(ctx context.Context, rp repo.Repository, mediaId string, format *media.Format, start time.Duration) (*jobs.Job, error) {
+	type_, key := jobs.TypeRemux, jobKey("stream", rp.ID(), mediaId, format.Extension)
+	if start > 0 {
+		type_, key = jobs.TypeTranscode, fmt.Sprintf("%s-%d", key, start.Milliseconds())
+	}
+
+	logger := s.log(ctx, rp.ID(), mediaId) // jobs outlive the request, carry its logger over
+	return s.keyed.Enqueue(key, type_, rp.ID(), mediaId, func(ctx context.Context) (media.Media, error) {
+		return streamFormat(repo.WithLogger(ctx, logger), rp, mediaId, format, start)
+	})
+}
+
+// remuxResult is the result of a remux streamed while it's being made.
+type remuxResult struct {
+	m   media.Media
+	err error
+}
+
+// remuxPartial remuxes media to a format for streaming it while it's being written. A response streaming the partial file
+// is returned once it has data, the remux result is returned instead if it ends before, such as when it's made already
+// or another request is making it already, whose partial file may not be readable.
+// Others may wait for the remux, it's finished even if the request ends, the context's error is returned then.
+func (s *Server) remuxPartial(ctx context.Context, rp repo.Repository, mediaId string, format *media.Format) (media.Media, *partialResp, error) {
+	var (
+		partial = make(chan string, 1)
+		done    = make(chan remuxResult, 1)
+	)
+	go func() {
+		m, err := rp.Remux(repo.WithPartial(s.detach(ctx, rp.ID(), mediaId), func(path string) { partial <- path }), mediaId, format)
+		done <- remuxResult{m: m, err: err}
+	}()
+
+	var path string
+	select {
+	case res := <-done:
+		return res.m, nil, res.err
+	case path = <-partial:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	ticker := time.NewTicker(partialPoll)
+	defer ticker.Stop()
+	for {
+		if fi, err := os.Stat(path); err == nil && fi.Size() > 0 {
+			return nil, &partialResp{path: path, mime: format.MIME, done: done, s: s, repo: rp, mediaId: mediaId}, nil
+		}
+
+		select {
+		case res := <-done:
+			return res.m, nil, res.err
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+}
+
+// partialResp is a response streaming a file being written until it's complete, without range requests.
+type partialResp struct {
+	path, mime string
+	done       <-chan remuxResult
+
+	s       *Server
+	repo    repo.Repository
+	mediaId string
+}
+
+func (pr *partialResp) VisitGetRepoMediaStreamResponse(w http.ResponseWriter, r *http.Request) (err error) {
+	client := auth.Client(r.Context())
+	if pr.s.capExceeded(client) {
+		return writeError(w, http.StatusTooManyRequests, v1.Error{Type: v1.QuotaExceeded, Description: "monthly transfer cap exceeded"})
+	}
+
+	f, err := os.Open(pr.path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return errors.Wrap(err, "failed to open partial file")
+		}
+
+		// completed or failed in the meantime
+		res := <-pr.done
+		if res.err != nil {
+			return errors.Wrap(res.err, "failed to remux media")
+		}
+		if res.m == nil {
+			return writeError(w, http.StatusBadRequest, v1.Error{Type: v1.NotFound, Description: "media not found"})
+		}
+
+		return pr.s.streamResp(pr.repo, pr.mediaId, res.m.Path(), pr.mime).VisitGetRepoMediaStreamResponse(w, r)
+	}
+	defer func() {
+		if err0 := f.Close(); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to close file"))
+		}
+	}()
+
+	w.Header().Set("Content-Type", pr.mime)
+	w.Header().Set("Content-Disposition", "inline")
+	w.WriteHeader(http.StatusOK)
+
+	cw := &countingWriter{ResponseWriter: w}
+	defer func() {
+		pr.s.stats.Add(pr.repo.ID(), pr.mediaId, cw.n)
+		pr.s.stats.AddClient(client, cw.n)
+	}()
+
+	var (
+		rc     = http.NewResponseController(w)
+		buf    = make([]byte, 32*1024)
+		ticker = time.NewTicker(partialPoll)
+		res    *remuxResult
+	)
+	defer ticker.Stop()
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			if _, err := cw.Write(buf[:n]); err != nil {
+				return nil // client gone
+			}
+			_ = rc.Flush()
+		}
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			return errors.Wrap(err, "failed to read partial file")
+		}
+		if res != nil { // read to the end of the complete file
+			break
+		}
+
+		select {
+		case res0 := <-pr.done:
+			if res0.err != nil {
+				return errors.Wrap(res0.err, "failed to remux media")
+			}
+			res = &res0
+		case <-ticker.C:
+		case <-r.Context().Done():
+			return nil
+		}
+	}
+
+	// the partial file may have been replaced by a retry of another operation, the one read would be incomplete
+	fi, err := f.Stat()
+	if err != nil {
+		return errors.Wrap(err, "failed to stat partial file")
+	}
+	if res.m == nil {
+		return errors.New("media was removed while being streamed")
+	}
+	if fi0, err := os.Stat(res.m.Path()); err != nil || !os.SameFile(fi, fi0) {
+		return errors.New("remuxed file was replaced while being streamed")
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/repo/media/meta"
+	"github.com/katana-project/katana/server/api/v1"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// transcodeRepo is a transcoding-capable repository, recording the profiles it transcodes with.
+type transcodeRepo struct {
+	repo.MutableRepository
+
+	profiles []*media.TranscodeProfile
+}
+
+func (tr *transcodeRepo) Capabilities() repo.Capability {
+	return repo.CapabilityRemux | repo.CapabilityTranscode
+}
+
+func (tr *transcodeRepo) Transcode(_ context.Context, id string, profile *media.TranscodeProfile) (media.Media, error) {
+	tr.profiles = append(tr.profiles, profile)
+	return tr.Get(id), nil
+}
+
+// newStreamServer creates a server of one transcoding-capable repository of one media file, converting with the features.
+func newStreamServer(t *testing.T, features *repo.Features) (*Server, *transcodeRepo) {
+	root := t.TempDir()
+	path := filepath.Join(root, "Test.mp4")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := repo.NewRepository("test", "Test", root, meta.NewDummySource(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(media.NewMedia("test", path, nil, media.FormatMP4)); err != nil {
+		t.Fatal(err)
+	}
+
+	tr := &transcodeRepo{MutableRepository: r}
+	s, err := NewServer(&Options{Repos: []repo.Repository{tr}, Features: features}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.cancel)
+
+	return s, tr
+}
+
+func streamRequest(format string, start float64) v1.GetRepoMediaStreamRequestObject {
+	return v1.GetRepoMediaStreamRequestObject{
+		RepoId:  "test",
+		MediaId: "test",
+		Format:  format,
+		Params:  v1.GetRepoMediaStreamParams{Start: &start},
+	}
+}
+
+func TestServer_GetRepoMediaStreamStart(t *testing.T) {
+	s, tr := newStreamServer(t, &repo.Features{TranscodeOptions: media.TranscodeOptions()})
+
+	res, err := s.GetRepoMediaStream(context.Background(), streamRequest("mp4", 90))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := res.(*streamResp); !ok {
+		t.Fatalf("expected a stream response, got %T", res)
+	}
+	if len(tr.profiles) != 1 || tr.profiles[0].Start != 90*time.Second || tr.profiles[0].VideoCodec != "" || tr.profiles[0].AudioCodec != "" {
+		t.Errorf("expected one transcode from 90s with streams copied, got %+v", tr.profiles)
+	}
+
+	for _, req := range []v1.GetRepoMediaStreamRequestObject{streamRequest("raw", 90), streamRequest("mp4", -1)} {
+		res, err := s.GetRepoMediaStream(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e, ok := res.(v1.GetRepoMediaStream400JSONResponse); !ok || e.Type != v1.BadRequest {
+			t.Errorf("expected a bad request response for format %s from %v, got %+v", req.Format, *req.Params.Start, res)
+		}
+	}
+	if len(tr.profiles) != 1 {
+		t.Errorf("expected rejected requests not to transcode, got %d profiles", len(tr.profiles))
+	}
+}
+
+func TestServer_GetRepoMediaStreamStartUnsupported(t *testing.T) {
+	s, tr := newStreamServer(t, &repo.Features{})
+
+	res, err := s.GetRepoMediaStream(context.Background(), streamRequest("mp4", 90))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := res.(v1.GetRepoMediaStream400JSONResponse); !ok || e.Type != v1.UnknownFormat {
+		t.Errorf("expected an unsupported option response, got %+v", res)
+	}
+	if len(tr.profiles) != 0 {
+		t.Errorf("expected no transcode, got %+v", tr.profiles)
+	}
+}
+
+func TestServer_ConvertRepoMediaStart(t *testing.T) {
+	s, _ := newStreamServer(t, &repo.Features{})
+
+	start, codec := 90.0, "aac"
+	tests := []struct {
+		name string
+		body v1.ConvertRequest
+		typ  v1.ErrorType
+	}{
+		{"remux", v1.ConvertRequest{Format: "mp4", Start: &start}, v1.BadRequest},
+		{"unsupported", v1.ConvertRequest{Format: "mp4", AudioCodec: &codec, Start: &start}, v1.UnknownFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := s.ConvertRepoMedia(context.Background(), v1.ConvertRepoMediaRequestObject{RepoId: "test", MediaId: "test", Body: &tt.body})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e, ok := res.(v1.ConvertRepoMedia400JSONResponse); !ok || e.Type != tt.typ {
+				t.Errorf("expected a %s response, got %+v", tt.typ, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/server/api/v1"
+	"go.uber.org/multierr"
+	"golang.org/x/text/language"
+	"os"
+)
+
+func (s *Server) GetRepoMediaSubtitles(_ context.Context, request v1.GetRepoMediaSubtitlesRequestObject) (v1.GetRepoMediaSubtitlesResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaSubtitles400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	subtitles, err := rp.Subtitles(request.MediaId)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list subtitles")
+	}
+	if subtitles == nil {
+		return v1.GetRepoMediaSubtitles400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	tracks := make([]v1.SubtitleTrack, len(subtitles))
+	for i, subtitle := range subtitles {
+		tracks[i] = s.wrapSubtitle(subtitle)
+	}
+
+	return v1.GetRepoMediaSubtitles200JSONResponse(tracks), nil
+}
+
+func (s *Server) GetRepoMediaSubtitle(ctx context.Context, request v1.GetRepoMediaSubtitleRequestObject) (v1.GetRepoMediaSubtitleResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoMediaSubtitle400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+	if rp.Get(request.MediaId) == nil {
+		return v1.GetRepoMediaSubtitle400JSONResponse(v1.Error{Type: v1.NotFound, Description: "media not found"}), nil
+	}
+
+	subtitle, err := rp.ExtractSubtitle(ctx, request.MediaId, request.TrackId)
+	if err != nil {
+		var (
+			euo *repo.ErrUnsupportedOperation
+			euf *repo.ErrUnsupportedFormat
+		)
+		if errors.As(err, &euo) {
+			return v1.GetRepoMediaSubtitle400JSONResponse(v1.Error{Type: v1.MissingCapability, Description: "missing 'remux' capability"}), nil
+		}
+		if errors.As(err, &euf) {
+			return v1.GetRepoMediaSubtitle400JSONResponse(v1.Error{Type: v1.UnknownFormat, Description: fmt.Sprintf("subtitle codec '%s' can't be converted to WebVTT", euf.Format)}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to extract subtitles")
+	}
+	if subtitle == nil {
+		return v1.GetRepoMediaSubtitle400JSONResponse(v1.Error{Type: v1.NotFound, Description: "subtitle track not found"}), nil
+	}
+	if !subtitle.Text() {
+		return v1.GetRepoMediaSubtitle400JSONResponse(v1.Error{Type: v1.UnknownFormat, Description: fmt.Sprintf("subtitle codec '%s' can't be converted to WebVTT", subtitle.Codec)}), nil
+	}
+
+	vtt, err := convertSubtitle(subtitle)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert subtitles")
+	}
+
+	return v1.GetRepoMediaSubtitle200TextvttResponse{
+		Body:          bytes.NewReader(vtt),
+		ContentLength: int64(len(vtt)),
+	}, nil
+}
+
+// convertSubtitle converts an extracted subtitle track to WebVTT.
+func convertSubtitle(subtitle *media.Subtitle) (_ []byte, err error) {
+	f, err := os.Open(subtitle.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open subtitles")
+	}
+	defer func() {
+		if err0 := f.Close(); err0 != nil {
+			err = multierr.Append(err, errors.Wrap(err0, "failed to close file"))
+		}
+	}()
+
+	var buf bytes.Buffer
+	if err := media.WriteWebVTT(&buf, f, subtitle.Codec); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (s *Server) wrapSubtitle(subtitle *media.Subtitle) v1.SubtitleTrack {
+	var lang *string
+	if subtitle.Language != language.Und {
+		lang = makeOptString(subtitle.Language.String())
+	}
+
+	return v1.SubtitleTrack{
+		Id:          subtitle.ID,
+		Codec:       subtitle.Codec,
+		Language:    lang,
+		External:    subtitle.External(),
+		Convertible: subtitle.Text(),
+	}
+}
@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana"
+	"github.com/katana-project/katana/internal/update"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/server/api/v1"
+)
+
+func (s *Server) GetSystem(_ context.Context, _ v1.GetSystemRequestObject) (v1.GetSystemResponseObject, error) {
+	latest := s.latestVersion()
+	sys := v1.System{
+		Version:         katana.Version,
+		UpdateAvailable: update.Newer(latest, katana.Version),
+		LatestVersion:   makeOptString(latest),
+	}
+	if s.features != nil {
+		sys.Ffmpeg = wrapFeatures(s.features)
+	}
+
+	return v1.GetSystem200JSONResponse(sys), nil
+}
+
+// wrapFeatures wraps media processing components in a REST representation.
+func wrapFeatures(f *repo.Features) *v1.FFmpegFeatures {
+	opts := make([]v1.TranscodeOption, len(f.TranscodeOptions))
+	for i, opt := range f.TranscodeOptions {
+		opts[i] = v1.TranscodeOption(opt)
+	}
+
+	return &v1.FFmpegFeatures{
+		Muxers:           makeArray(f.Muxers),
+		Demuxers:         makeArray(f.Demuxers),
+		Encoders:         makeArray(f.Encoders),
+		Decoders:         makeArray(f.Decoders),
+		HwAccels:         makeArray(f.HWAccels),
+		TranscodeOptions: opts,
+		Fragmenting:      f.Fragmenting,
+		Storyboards:      f.Storyboards,
+		Previews:         f.Previews,
+	}
+}
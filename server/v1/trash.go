@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/internal/errors"
+	"github.com/katana-project/katana/repo"
+	"github.com/katana-project/katana/server/api/v1"
+)
+
+func (s *Server) GetRepoTrash(ctx context.Context, request v1.GetRepoTrashRequestObject) (v1.GetRepoTrashResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.GetRepoTrash400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	bin, ok := s.trash[rp.ID()]
+	if !ok {
+		return v1.GetRepoTrash400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "repository has no trash"}), nil
+	}
+
+	items := bin.Items()
+	res := make([]v1.TrashedMedia, len(items))
+	for i, item := range items {
+		m, err := s.wrapMedia(rp.ID(), item.Media, WrapModeBasicImages, requestLang(ctx))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to wrap media")
+		}
+
+		res[i] = v1.TrashedMedia{Media: m, Path: item.Media.Path(), Trashed: item.Trashed}
+		if retention := bin.Retention(); retention > 0 {
+			expires := item.Trashed.Add(retention)
+			res[i].Expires = &expires
+		}
+	}
+
+	return v1.GetRepoTrash200JSONResponse(res), nil
+}
+
+func (s *Server) RestoreRepoTrash(ctx context.Context, request v1.RestoreRepoTrashRequestObject) (v1.RestoreRepoTrashResponseObject, error) {
+	rp := s.Repo(request.RepoId)
+	if rp == nil {
+		return v1.RestoreRepoTrash400JSONResponse(v1.Error{Type: v1.NotFound, Description: "repository not found"}), nil
+	}
+
+	bin, ok := s.trash[rp.ID()]
+	if !ok {
+		return v1.RestoreRepoTrash400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "repository has no trash"}), nil
+	}
+
+	m, err := bin.Restore(request.MediaId)
+	if err != nil {
+		var (
+			duplicateId   *repo.ErrDuplicateID
+			duplicatePath *repo.ErrDuplicatePath
+		)
+		if errors.As(err, &duplicateId) || errors.As(err, &duplicatePath) {
+			return v1.RestoreRepoTrash400JSONResponse(v1.Error{Type: v1.BadRequest, Description: err.Error()}), nil
+		}
+
+		return nil, errors.Wrap(err, "failed to restore media")
+	}
+	if m == nil {
+		return v1.RestoreRepoTrash400JSONResponse(v1.Error{Type: v1.NotFound, Description: "trashed media not found"}), nil
+	}
+
+	res, err := s.wrapMedia(rp.ID(), m, WrapModeBasicImages, requestLang(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to wrap media")
+	}
+
+	return v1.RestoreRepoTrash200JSONResponse(res), nil
+}
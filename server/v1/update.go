@@ -0,0 +1,76 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana"
+	"github.com/katana-project/katana/internal/update"
+	"go.uber.org/zap"
+	"time"
+)
+
+// updateCheckTimeout is the timeout of checking for the latest release.
+const updateCheckTimeout = time.Minute
+
+// ScheduleUpdateCheck starts checking for the latest release in the interval, right away first,
+// a newer one is reported by the system endpoint. It's stopped when the server is closed.
+func (s *Server) ScheduleUpdateCheck(client *update.Client, interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.updateStop, s.updateDone = cancel, make(chan struct{})
+
+	go func() {
+		defer close(s.updateDone)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.checkUpdate(ctx, client)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// checkUpdate fetches the version of the latest release, failures are only logged.
+func (s *Server) checkUpdate(ctx context.Context, client *update.Client) {
+	ctx, cancel := context.WithTimeout(ctx, updateCheckTimeout)
+	defer cancel()
+
+	r, err := client.Latest(ctx)
+	if err != nil {
+		if ctx.Err() == nil && s.logger != nil {
+			s.logger.Warn("failed to check for updates", zap.Error(err))
+		}
+
+		return
+	}
+
+	s.latestMu.Lock()
+	prev := s.latest
+	s.latest = r.Version
+	s.latestMu.Unlock()
+
+	if r.Version != prev && update.Newer(r.Version, katana.Version) && s.logger != nil {
+		s.logger.Info("update available, install it with the update sub-command", zap.String("version", katana.Version), zap.String("latest", r.Version))
+	}
+}
+
+// stopUpdateCheck stops the checking started by ScheduleUpdateCheck, if any.
+func (s *Server) stopUpdateCheck() {
+	if s.updateStop != nil {
+		s.updateStop()
+		<-s.updateDone
+	}
+}
+
+// latestVersion returns the version of the latest release, empty if it wasn't checked.
+func (s *Server) latestVersion() string {
+	s.latestMu.Lock()
+	defer s.latestMu.Unlock()
+
+	return s.latest
+}
@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/server/api/v1"
+	"github.com/katana-project/katana/server/auth"
+	"github.com/katana-project/katana/server/stats"
+	"golang.org/x/exp/slices"
+	"time"
+)
+
+// monthLayout is the time layout of months ("2006-01").
+const monthLayout = "2006-01"
+
+// SetTransferCaps sets the number of bytes served to a client (auth.Client) per calendar month, past which streaming is refused.
+// The default cap applies to clients without their own in caps, keyed by client ID, zero is unlimited.
+// It must be called before serving requests.
+func (s *Server) SetTransferCaps(def int64, caps map[string]int64) {
+	s.capDefault, s.caps = def, caps
+}
+
+// transferCap returns the monthly transfer cap of a client, zero if it's unlimited.
+func (s *Server) transferCap(client string) int64 {
+	if limit, ok := s.caps[client]; ok {
+		return limit
+	}
+
+	return s.capDefault
+}
+
+// monthStart returns the start of the month of a time, in its time zone.
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// clientUsage returns the bytes served to a client per day in the month starting at a time.
+func (s *Server) clientUsage(client string, month time.Time) ([]stats.Day, int64) {
+	var (
+		next  = month.AddDate(0, 1, 0)
+		days  = slices.DeleteFunc(s.stats.Client(client, month), func(d stats.Day) bool { return !d.Date.Before(next) })
+		total int64
+	)
+	for _, day := range days {
+		total += day.Bytes
+	}
+
+	return days, total
+}
+
+// capExceeded checks whether a client was served its monthly transfer cap already, requests of no client are never capped.
+func (s *Server) capExceeded(client string) bool {
+	limit := s.transferCap(client)
+	if client == "" || limit <= 0 {
+		return false
+	}
+
+	_, total := s.clientUsage(client, monthStart(time.Now()))
+	return total >= limit
+}
+
+func (s *Server) GetUsage(ctx context.Context, request v1.GetUsageRequestObject) (v1.GetUsageResponseObject, error) {
+	month := monthStart(time.Now())
+	if request.Params.Month != nil {
+		t, err := time.ParseInLocation(monthLayout, *request.Params.Month, time.Local)
+		if err != nil {
+			return v1.GetUsage400JSONResponse(v1.Error{Type: v1.BadRequest, Description: "malformed month"}), nil
+		}
+
+		month = t
+	}
+
+	var clients []string
+	if user := auth.User(ctx); user != "" {
+		clients = []string{user}
+	} else { // API token or authentication not enforced
+		clients = s.stats.Clients()
+		for client := range s.caps {
+			if !slices.Contains(clients, client) {
+				clients = append(clients, client)
+			}
+		}
+		slices.Sort(clients)
+	}
+
+	res := make(v1.GetUsage200JSONResponse, len(clients))
+	for i, client := range clients {
+		days, total := s.clientUsage(client, month)
+
+		usage := v1.ClientUsage{Client: client, Bytes: total, Days: make([]v1.DayStats, len(days))}
+		if limit := s.transferCap(client); limit > 0 {
+			usage.Cap = &limit
+		}
+		for j, day := range days {
+			usage.Days[j] = v1.DayStats{Date: day.Date.Format(stats.DayLayout), Bytes: day.Bytes}
+		}
+
+		res[i] = usage
+	}
+
+	return res, nil
+}
@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/katana-project/katana/repo"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// validatedResp is a response with caching validators (ETag, Last-Modified),
+// it's replaced with 304 Not Modified if the client's copy is current.
+type validatedResp struct {
+	etag     string
+	modified time.Time
+	visit    func(w http.ResponseWriter, r *http.Request) error
+}
+
+// validate wraps a response of data derived from repository revisions with caching validators.
+func (s *Server) validate(visit func(w http.ResponseWriter, r *http.Request) error, revs ...repo.Revision) *validatedResp {
+	var (
+		number   uint64 // revision numbers only grow, so their sum changes with every change
+		modified time.Time
+	)
+	for _, rev := range revs {
+		number += rev.Number
+		if rev.Modified.After(modified) {
+			modified = rev.Modified
+		}
+	}
+
+	return &validatedResp{
+		etag:     fmt.Sprintf(`W/"%x-%x-%x"`, s.epoch, len(revs), number), // revisions aren't persisted, epoch tells server runs apart
+		modified: modified,
+		visit:    visit,
+	}
+}
+
+// notModified checks the request's conditional headers against the validators,
+// If-Modified-Since is only considered without If-None-Match.
+func (vr *validatedResp) notModified(r *http.Request) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, etag := range strings.Split(inm, ",") {
+			etag = strings.TrimSpace(etag)
+			if etag == "*" || strings.TrimPrefix(etag, "W/") == strings.TrimPrefix(vr.etag, "W/") { // weak comparison
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		return err == nil && !vr.modified.Truncate(time.Second).After(t)
+	}
+
+	return false
+}
+
+func (vr *validatedResp) write(w http.ResponseWriter, r *http.Request) error {
+	vr.etag = etagLang(vr.etag, requestLang(r.Context()))
+	w.Header().Set("ETag", vr.etag)
+	w.Header().Set("Last-Modified", vr.modified.UTC().Format(http.TimeFormat))
+	if vr.notModified(r) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	return vr.visit(w, r)
+}
+
+func (vr *validatedResp) VisitGetReposResponse(w http.ResponseWriter, r *http.Request) error {
+	return vr.write(w, r)
+}
+
+func (vr *validatedResp) VisitGetRepoMediaResponse(w http.ResponseWriter, r *http.Request) error {
+	return vr.write(w, r)
+}
+
+func (vr *validatedResp) VisitGetRepoMediaByIdResponse(w http.ResponseWriter, r *http.Request) error {
+	return vr.write(w, r)
+}
+
+func (vr *validatedResp) VisitGetRepoSeriesResponse(w http.ResponseWriter, r *http.Request) error {
+	return vr.write(w, r)
+}
+
+func (vr *validatedResp) VisitGetRepoSeriesSeasonResponse(w http.ResponseWriter, r *http.Request) error {
+	return vr.write(w, r)
+}
@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"context"
+	"github.com/katana-project/katana/repo/media"
+	"github.com/katana-project/katana/server/auth"
+	"os"
+	"strings"
+)
+
+// SetWatermark sets the watermark overlaid on transcoded video, nil or one without a text and an image disables watermarking.
+// The text may contain "{user}" and "{server}", replaced with the requesting user's name and the host name.
+// It must be called before serving requests.
+func (s *Server) SetWatermark(wm *media.Watermark) {
+	if wm != nil && wm.Text == "" && wm.Image == "" {
+		wm = nil
+	}
+
+	s.wm = wm
+}
+
+// watermark returns the watermark of a request's transcoded video, nil if there's none.
+func (s *Server) watermark(ctx context.Context) *media.Watermark {
+	if s.wm == nil {
+		return nil
+	}
+
+	host, _ := os.Hostname()
+	wm := *s.wm
+	wm.Text = strings.TrimSpace(strings.NewReplacer("{user}", auth.User(ctx), "{server}", host).Replace(wm.Text))
+	if wm.Text == "" && wm.Image == "" { // only an empty user name
+		return nil
+	}
+
+	return &wm
+}
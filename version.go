@@ -0,0 +1,5 @@
+package katana
+
+// Version is the version of the build, set at link time (-ldflags "-X github.com/katana-project/katana.Version=v1.0.0"),
+// "dev" for development builds, which are never updated.
+var Version = "dev"